@@ -17,6 +17,7 @@ package cli
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -106,6 +107,8 @@ func CreateRebaseArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(AbortParam, "", "Abort an interactive rebase and return the working set to the pre-rebase state")
 	ap.SupportsFlag(ContinueFlag, "", "Continue an interactive rebase after adjusting the rebase plan")
 	ap.SupportsFlag(InteractiveFlag, "i", "Start an interactive rebase")
+	ap.SupportsString(OntoParam, "", "newbase", "Replay the commits onto {{.LessThan}}newbase{{.GreaterThan}} instead of the upstream branch")
+	ap.SupportsFlag(RebaseMergesFlag, "", "Keep merge commits in the rebase plan and replay them by merging their original second parents, instead of dropping them")
 	return ap
 }
 
@@ -141,6 +144,9 @@ func CreateCloneArgParser() *argparser.ArgParser {
 	ap.SupportsString(dbfactory.OSSCredsProfile, "", "profile", "OSS profile to use.")
 	ap.SupportsString(UserFlag, "u", "user", "User name to use when authenticating with the remote. Gets password from the environment variable {{.EmphasisLeft}}DOLT_REMOTE_PASSWORD{{.EmphasisRight}}.")
 	ap.SupportsFlag(SingleBranchFlag, "", "Clone only the history leading to the tip of a single branch, either specified by --branch or the remote's HEAD (default).")
+	ap.SupportsString(TablesFlag, "", "tables", "Comma separated list of tables whose data is fetched. Other tables are marked as not fetched.")
+	ap.SupportsString(ExcludeTablesFlag, "", "tables", "Comma separated list of tables whose data isn't fetched. They are marked as not fetched.")
+	ap.SupportsString(dbfactory.EncryptionKeyFileParam, "", "file", "Key file of a local file remote which is encrypted at rest. The clone is encrypted with the same key file.")
 	return ap
 }
 
@@ -188,6 +194,8 @@ func CreateFetchArgParser() *argparser.ArgParser {
 	ap.SupportsString(UserFlag, "", "user", "User name to use when authenticating with the remote. Gets password from the environment variable {{.EmphasisLeft}}DOLT_REMOTE_PASSWORD{{.EmphasisRight}}.")
 	ap.SupportsFlag(PruneFlag, "p", "After fetching, remove any remote-tracking references that don't exist on the remote.")
 	ap.SupportsFlag(SilentFlag, "", "Suppress progress information.")
+	ap.SupportsString(TablesFlag, "", "tables", "Comma separated list of tables to fetch the data of, including the data skipped by earlier partial fetches.")
+	ap.SupportsString(ExcludeTablesFlag, "", "tables", "Comma separated list of tables to stop fetching the data of.")
 	return ap
 }
 
@@ -212,6 +220,8 @@ func CreatePullArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(NoEditFlag, "", "Use an auto-generated commit message when creating a merge commit. The default for interactive CLI sessions is to open an editor.")
 	ap.SupportsString(UserFlag, "", "user", "User name to use when authenticating with the remote. Gets password from the environment variable {{.EmphasisLeft}}DOLT_REMOTE_PASSWORD{{.EmphasisRight}}.")
 	ap.SupportsFlag(SilentFlag, "", "Suppress progress information.")
+	ap.SupportsString(TablesFlag, "", "tables", "Comma separated list of tables to fetch the data of, including the data skipped by earlier partial fetches.")
+	ap.SupportsString(ExcludeTablesFlag, "", "tables", "Comma separated list of tables to stop fetching the data of.")
 	return ap
 }
 
@@ -247,6 +257,30 @@ func CreateTagArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(VerboseFlag, "v", "list tags along with their metadata.")
 	ap.SupportsFlag(DeleteFlag, "d", "Delete a tag.")
 	ap.SupportsString(AuthorParam, "", "author", "Specify an explicit author using the standard A U Thor {{.LessThan}}author@example.com{{.GreaterThan}} format.")
+	ap.SupportsFlag(SignTagFlag, "s", "Sign the tag with the key given by the 'signingkey' system variable.")
+	ap.SupportsString(SigningKeyParam, "u", "key-id", "Sign the tag with the key {{.LessThan}}key-id{{.GreaterThan}}.")
+	return ap
+}
+
+func CreateNotesArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithVariableArgs("notes")
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"subcommand", "One of add, show, list or remove."})
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"commit", "A commit ref whose note should be operated on. Defaults to HEAD."})
+	ap.SupportsString(MessageArg, "m", "msg", "Use the given {{.LessThan}}msg{{.GreaterThan}} as the note.")
+	ap.SupportsFlag(ForceFlag, "f", "Replace the existing note of the commit.")
+	ap.SupportsString(AuthorParam, "", "author", "Specify an explicit author using the standard A U Thor {{.LessThan}}author@example.com{{.GreaterThan}} format.")
+	return ap
+}
+
+func CreateVerifyCommitArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithVariableArgs("verify-commit")
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"commit", "A commit whose signature should be verified."})
+	return ap
+}
+
+func CreateVerifyTagArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithVariableArgs("verify-tag")
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"tag", "A tag whose signature should be verified."})
 	return ap
 }
 
@@ -261,6 +295,7 @@ func CreateBackupArgParser() *argparser.ArgParser {
 	ap.SupportsValidatedString(dbfactory.AWSCredsTypeParam, "", "creds-type", "", argparser.ValidatorFromStrList(dbfactory.AWSCredsTypeParam, dbfactory.AWSCredTypes))
 	ap.SupportsString(dbfactory.AWSCredsFileParam, "", "file", "AWS credentials file")
 	ap.SupportsString(dbfactory.AWSCredsProfile, "", "profile", "AWS profile to use")
+	ap.SupportsString(dbfactory.EncryptionKeyFileParam, "", "file", "Key file used to encrypt a local file backup at rest. It is created if it does not exist.")
 	return ap
 }
 
@@ -281,6 +316,7 @@ func CreateLogArgParser(isTableFunction bool) *argparser.ArgParser {
 	ap.SupportsString(DecorateFlag, "", "decorate_fmt", "Shows refs next to commits. Valid options are short, full, no, and auto")
 	ap.SupportsStringList(NotFlag, "", "revision", "Excludes commits from revision.")
 	ap.SupportsFlag(ShowSignatureFlag, "", "Shows the signature of each commit.")
+	ap.SupportsFlag(NotesFlag, "", "Shows the note attached to each commit.")
 	if isTableFunction {
 		ap.SupportsStringList(TablesFlag, "t", "table", "Restricts the log to commits that modified the specified tables.")
 	} else {
@@ -305,6 +341,26 @@ func CreateCountCommitsArgParser() *argparser.ArgParser {
 	return ap
 }
 
+// CreateCIRunArgParser creates the argparser shared by dolt ci run and DOLT_CI_RUN.
+func CreateBisectArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithVariableArgs("bisect")
+	return ap
+}
+
+func CreateWorktreeArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithVariableArgs("worktree")
+	ap.SupportsString(CheckoutCreateBranch, "", "new-branch", "Create a new branch and check it out in the new worktree.")
+	ap.SupportsFlag(ForceFlag, "f", "Remove a worktree even if it has uncommitted changes.")
+	return ap
+}
+
+func CreateCIRunArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs("run", 2)
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"workflow", "The name of the workflow to run."})
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"revision", "The branch, tag or commit to run the workflow's saved queries against. Defaults to the current branch."})
+	return ap
+}
+
 func CreateReflogArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithMaxArgs("reflog", 1)
 	ap.SupportsFlag(AllFlag, "", "Show all refs, including hidden refs, such as DoltHub workspace refs")
@@ -345,6 +401,11 @@ func ProcessBackupArgs(apr *argparser.ArgParseResults, scheme, backupUrl string)
 	default:
 		err = VerifyNoAwsParams(apr)
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = AddEncryptionParams(scheme, apr, params)
 	return params, err
 }
 
@@ -388,6 +449,22 @@ func AddOSSParams(remoteUrl string, apr *argparser.ArgParseResults, params map[s
 	return nil
 }
 
+// AddEncryptionParams adds the encryption at rest params in |apr| to |params|. They are only valid for file remotes
+// and backups, whose databases are stored locally.
+func AddEncryptionParams(scheme string, apr *argparser.ArgParseResults, params map[string]string) error {
+	if val, ok := apr.GetValue(dbfactory.EncryptionKeyFileParam); ok {
+		if scheme != dbfactory.FileScheme {
+			return fmt.Errorf("%s param is only valid for file remotes and backups in the format file:///path/to/database", dbfactory.EncryptionKeyFileParam)
+		}
+		absPath, err := filepath.Abs(val)
+		if err != nil {
+			return err
+		}
+		params[dbfactory.EncryptionKeyFileParam] = absPath
+	}
+	return nil
+}
+
 func VerifyNoAwsParams(apr *argparser.ArgParseResults) error {
 	if awsParams := apr.GetValues(awsParams...); len(awsParams) > 0 {
 		awsParamKeys := make([]string, 0, len(awsParams))
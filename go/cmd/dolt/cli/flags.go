@@ -36,6 +36,7 @@ const (
 	DepthFlag            = "depth"
 	DryRunFlag           = "dry-run"
 	EmptyParam           = "empty"
+	ExcludeTablesFlag    = "exclude-tables"
 	ForceFlag            = "force"
 	FullFlag             = "full"
 	GraphFlag            = "graph"
@@ -54,8 +55,10 @@ const (
 	NoTLSFlag            = "no-tls"
 	NoJsonMergeFlag      = "dont-merge-json"
 	NotFlag              = "not"
+	NotesFlag            = "notes"
 	NumberFlag           = "number"
 	OneLineFlag          = "oneline"
+	OntoParam            = "onto"
 	OursFlag             = "ours"
 	OutputOnlyFlag       = "output-only"
 	ParentsFlag          = "parents"
@@ -64,12 +67,15 @@ const (
 	PortFlag             = "port"
 	PruneFlag            = "prune"
 	QuietFlag            = "quiet"
+	RebaseMergesFlag     = "rebase-merges"
 	RemoteParam          = "remote"
 	SetUpstreamFlag      = "set-upstream"
 	ShallowFlag          = "shallow"
 	ShowIgnoredFlag      = "ignored"
 	ShowSignatureFlag    = "show-signature"
 	SignFlag             = "gpg-sign"
+	SignTagFlag          = "sign"
+	SigningKeyParam      = "local-user"
 	SilentFlag           = "silent"
 	SingleBranchFlag     = "single-branch"
 	SkipEmptyFlag        = "skip-empty"
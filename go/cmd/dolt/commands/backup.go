@@ -283,6 +283,10 @@ func backup(ctx context.Context, dEnv *env.DoltEnv, b env.Remote) errhand.Verbos
 	if err != nil {
 		return errhand.BuildDError("error: ").AddCause(err).Build()
 	}
+	// archive the reflog first, so that the backup includes it
+	if err = dEnv.DoltDB.ArchiveReflog(ctx); err != nil {
+		return errhand.BuildDError("error: unable to archive the reflog.").AddCause(err).Build()
+	}
 	err = actions.SyncRoots(ctx, dEnv.DoltDB, destDb, tmpDir, buildProgStarter(defaultLanguage), stopProgFuncs)
 
 	switch err {
@@ -367,7 +371,7 @@ func restoreBackup(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgPar
 		}
 	} else {
 		// Create a new Dolt env for the clone; use env.NoRemote to avoid origin upstream
-		clonedEnv, err := actions.EnvForClone(ctx, srcDb.ValueReadWriter().Format(), env.NoRemote, restoredDB, dEnv.FS, dEnv.Version, env.GetCurrentUserHomeDir)
+		clonedEnv, err := actions.EnvForClone(ctx, srcDb, env.NoRemote, restoredDB, dEnv.FS, dEnv.Version, env.GetCurrentUserHomeDir)
 		if err != nil {
 			return errhand.VerboseErrorFromError(err)
 		}
@@ -0,0 +1,105 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+var bisectDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Use binary search to find the commit that introduced a change",
+	LongDesc: `Finds the first commit in which something changed, for example the first commit where a query's results became wrong, by binary searching the commits between a commit known to be good and a commit known to be bad.
+
+{{.EmphasisLeft}}dolt bisect start{{.EmphasisRight}} begins a bisect. The bad commit and any number of good commits can be given when starting, or marked afterwards with {{.EmphasisLeft}}dolt bisect bad{{.EmphasisRight}} and {{.EmphasisLeft}}dolt bisect good{{.EmphasisRight}}. Once a good and a bad commit are known, each step prints the next commit to test. Test it, for example with {{.EmphasisLeft}}dolt sql -q "select ... as of '<commit>'"{{.EmphasisRight}}, and mark it with {{.EmphasisLeft}}dolt bisect good{{.EmphasisRight}} or {{.EmphasisLeft}}dolt bisect bad{{.EmphasisRight}}. Commits that can't be tested can be marked with {{.EmphasisLeft}}dolt bisect skip{{.EmphasisRight}}. When no commit is given to good, bad or skip, the commit being tested is marked.
+
+{{.EmphasisLeft}}dolt bisect run{{.EmphasisRight}} automates the search with a SELECT query. The query is run against each commit tested, and the commit is good if the first column of the first row returned is truthy, and bad otherwise.
+
+{{.EmphasisLeft}}dolt bisect reset{{.EmphasisRight}} ends the bisect. The current branch is never changed by a bisect.`,
+	Synopsis: []string{
+		`start [{{.LessThan}}bad{{.GreaterThan}} [{{.LessThan}}good{{.GreaterThan}}...]]`,
+		`(bad | good | skip) [{{.LessThan}}commit{{.GreaterThan}}...]`,
+		`run {{.LessThan}}query{{.GreaterThan}}`,
+		`reset`,
+	},
+}
+
+type BisectCmd struct{}
+
+var _ cli.Command = BisectCmd{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd BisectCmd) Name() string {
+	return "bisect"
+}
+
+// Description returns a description of the command
+func (cmd BisectCmd) Description() string {
+	return bisectDocs.ShortDesc
+}
+
+func (cmd BisectCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(bisectDocs, ap)
+}
+
+func (cmd BisectCmd) ArgParser() *argparser.ArgParser {
+	return cli.CreateBisectArgParser()
+}
+
+// Exec executes the command
+func (cmd BisectCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, bisectDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+	if apr.NArg() == 0 {
+		usage()
+		return 1
+	}
+
+	queryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if closeFunc != nil {
+		defer closeFunc()
+	}
+
+	query, err := interpolateStoredProcedureCall("DOLT_BISECT", apr.Args)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+
+	rows, err := GetRowsForSql(queryist, sqlCtx, query)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+
+	status, err := getInt64ColAsInt64(rows[0][0])
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if status == 1 {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(errors.New("error: "+rows[0][1].(string))), usage)
+	}
+
+	cli.Println(rows[0][1].(string))
+	return 0
+}
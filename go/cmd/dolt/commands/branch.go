@@ -129,9 +129,7 @@ func (cmd BranchCmd) Exec(ctx context.Context, commandStr string, args []string,
 		return moveBranch(sqlCtx, queryEngine, apr, args, usage)
 	case apr.Contains(cli.CopyFlag):
 		return copyBranch(sqlCtx, queryEngine, apr, args, usage)
-	case apr.Contains(cli.DeleteFlag):
-		return deleteBranches(sqlCtx, queryEngine, apr, args, usage)
-	case apr.Contains(cli.DeleteForceFlag):
+	case apr.Contains(cli.DeleteFlag), apr.Contains(cli.DeleteForceFlag):
 		return deleteBranches(sqlCtx, queryEngine, apr, args, usage)
 	case apr.Contains(cli.ListFlag):
 		return printBranches(sqlCtx, queryEngine, apr, usage)
@@ -144,6 +144,16 @@ func (cmd CheckoutCmd) Exec(ctx context.Context, commandStr string, args []strin
 		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
 	}
 
+	// A branch checked out in another worktree can't be checked out here, which dolt_checkout checks. The worktrees stay
+	// locked until the new branch has been saved to the repo state, so that another worktree can't check it out too.
+	if dEnv != nil && dEnv.Valid() && branchName != "" {
+		unlock, err := dEnv.LockWorktrees()
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
+		defer unlock()
+	}
+
 	rows, err := GetRowsForSql(queryEngine, sqlCtx, sqlQuery)
 
 	if err != nil {
@@ -224,6 +234,8 @@ func handleErrors(branchName string, err error) errhand.VerboseError {
 		return errhand.VerboseErrorFromError(err)
 	} else if doltdb.IsRootValUnreachable(err) {
 		return errhand.VerboseErrorFromError(err)
+	} else if env.ErrBranchCheckedOutInWorktree.Is(err) {
+		return errhand.BuildDError("fatal: %s", err.Error()).Build()
 	} else if actions.IsCheckoutWouldOverwrite(err) {
 		return errhand.VerboseErrorFromError(err)
 	} else if err.Error() == actions.ErrWorkingSetsOnBothBranches.Error() {
@@ -25,4 +25,5 @@ var Commands = cli.NewHiddenSubCommandHandler("ci", "Commands for working with D
 	ExportCmd{},
 	ListCmd{},
 	RemoveCmd{},
+	RunCmd{},
 })
@@ -0,0 +1,170 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ci
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fatih/color"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/dolt_ci"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+var runDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Run a Dolt continuous integration workflow by name",
+	LongDesc: `Run each step of each job of a Dolt continuous integration workflow and report whether it passed.
+
+Saved query steps look up their query by name in {{.EmphasisLeft}}dolt_query_catalog{{.EmphasisRight}}, run it, and compare the number of rows and columns returned against the step's expected results. If a {{.LessThan}}revision{{.GreaterThan}} is given, the saved queries are looked up and run against that branch, tag or commit, otherwise they are run against the current branch. The workflow definition itself is always read from the current branch.
+
+The command exits with a non-zero status if any step fails.`,
+	Synopsis: []string{
+		"{{.LessThan}}workflow name{{.GreaterThan}} [{{.LessThan}}revision{{.GreaterThan}}]",
+	},
+}
+
+type RunCmd struct{}
+
+// Name implements cli.Command.
+func (cmd RunCmd) Name() string {
+	return "run"
+}
+
+// Description implements cli.Command.
+func (cmd RunCmd) Description() string {
+	return runDocs.ShortDesc
+}
+
+// RequiresRepo implements cli.Command.
+func (cmd RunCmd) RequiresRepo() bool {
+	return true
+}
+
+// Docs implements cli.Command.
+func (cmd RunCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(runDocs, ap)
+}
+
+// Hidden should return true if this command should be hidden from the help text
+func (cmd RunCmd) Hidden() bool {
+	return true
+}
+
+// ArgParser implements cli.Command.
+func (cmd RunCmd) ArgParser() *argparser.ArgParser {
+	return cli.CreateCIRunArgParser()
+}
+
+// Exec implements cli.Command.
+func (cmd RunCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, runDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+	if !cli.CheckEnvIsValid(dEnv) {
+		return 1
+	}
+
+	var verr errhand.VerboseError
+	verr = validateRunArgs(apr)
+	if verr != nil {
+		return commands.HandleVErrAndExitCode(verr, usage)
+	}
+
+	workflowName := apr.Arg(0)
+	revision := ""
+	if apr.NArg() > 1 {
+		revision = apr.Arg(1)
+	}
+
+	queryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if closeFunc != nil {
+		defer closeFunc()
+	}
+
+	user, email, err := env.GetNameAndEmail(dEnv.Config)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+
+	hasTables, err := dolt_ci.HasDoltCITables(sqlCtx)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+
+	if !hasTables {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(fmt.Errorf("dolt ci has not been initialized, please initialize with: dolt ci init")), usage)
+	}
+
+	wm := dolt_ci.NewWorkflowManager(user, email, queryist.Query)
+
+	db, err := newDatabase(sqlCtx, sqlCtx.GetCurrentDatabase(), dEnv, false)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+
+	res, err := wm.RunWorkflow(sqlCtx, db, workflowName, revision)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+
+	printWorkflowRunResult(res)
+
+	if !res.Passed() {
+		failed := res.FailedSteps()
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("Dolt CI Workflow '%s' failed: %d of %d steps failed", res.WorkflowName, len(failed), len(res.Steps)).Build(), usage)
+	}
+	return 0
+}
+
+func printWorkflowRunResult(res *dolt_ci.WorkflowRunResult) {
+	if res.Revision != "" {
+		cli.Println(color.CyanString(fmt.Sprintf("Running Dolt CI Workflow '%s' against %s", res.WorkflowName, res.Revision)))
+	} else {
+		cli.Println(color.CyanString(fmt.Sprintf("Running Dolt CI Workflow '%s'", res.WorkflowName)))
+	}
+
+	currentJob := ""
+	for i, step := range res.Steps {
+		if i == 0 || step.JobName != currentJob {
+			currentJob = step.JobName
+			cli.Println(fmt.Sprintf("Job: %s", step.JobName))
+		}
+		if step.Passed {
+			cli.Println(fmt.Sprintf("  %s %s", color.GreenString("PASS"), step.StepName))
+		} else {
+			cli.Println(fmt.Sprintf("  %s %s: %s", color.RedString("FAIL"), step.StepName, step.Message))
+		}
+	}
+
+	if res.Passed() {
+		cli.Println(color.GreenString(fmt.Sprintf("Dolt CI Workflow '%s' passed", res.WorkflowName)))
+	}
+}
+
+func validateRunArgs(apr *argparser.ArgParseResults) errhand.VerboseError {
+	if apr.NArg() < 1 || apr.NArg() > 2 {
+		return errhand.BuildDError("expected 1 or 2 arguments").SetPrintUsage().Build()
+	}
+	return nil
+}
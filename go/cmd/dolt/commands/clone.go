@@ -43,9 +43,11 @@ var cloneDocs = cli.CommandDocumentationContent{
 After the clone, a plain {{.EmphasisLeft}}dolt fetch{{.EmphasisRight}} without arguments will update all the remote-tracking branches, and a {{.EmphasisLeft}}dolt pull{{.EmphasisRight}} without arguments will in addition merge the remote branch into the current branch.
 
 This default configuration is achieved by creating references to the remote branch heads under {{.LessThan}}refs/remotes/origin{{.GreaterThan}}  and by creating a remote named 'origin'.
+
+A partial clone, made with {{.EmphasisLeft}}--tables{{.EmphasisRight}} or {{.EmphasisLeft}}--exclude-tables{{.EmphasisRight}}, clones the full history of the repository but only the data of the selected tables. The other tables are listed as not fetched, and reading them is an error until they are fetched with {{.EmphasisLeft}}dolt fetch --tables{{.EmphasisRight}}. Later fetches and pulls from the remote keep the same table selection.
 `,
 	Synopsis: []string{
-		"[-remote {{.LessThan}}remote{{.GreaterThan}}] [-branch {{.LessThan}}branch{{.GreaterThan}}]  [--aws-region {{.LessThan}}region{{.GreaterThan}}] [--aws-creds-type {{.LessThan}}creds-type{{.GreaterThan}}] [--aws-creds-file {{.LessThan}}file{{.GreaterThan}}] [--aws-creds-profile {{.LessThan}}profile{{.GreaterThan}}] {{.LessThan}}remote-url{{.GreaterThan}} {{.LessThan}}new-dir{{.GreaterThan}}",
+		"[-remote {{.LessThan}}remote{{.GreaterThan}}] [-branch {{.LessThan}}branch{{.GreaterThan}}]  [--aws-region {{.LessThan}}region{{.GreaterThan}}] [--aws-creds-type {{.LessThan}}creds-type{{.GreaterThan}}] [--aws-creds-file {{.LessThan}}file{{.GreaterThan}}] [--aws-creds-profile {{.LessThan}}profile{{.GreaterThan}}] [--tables {{.LessThan}}tables{{.GreaterThan}} | --exclude-tables {{.LessThan}}tables{{.GreaterThan}}] {{.LessThan}}remote-url{{.GreaterThan}} {{.LessThan}}new-dir{{.GreaterThan}}",
 	},
 }
 
@@ -104,6 +106,13 @@ func clone(ctx context.Context, apr *argparser.ArgParseResults, dEnv *env.DoltEn
 		return verr
 	}
 
+	tables, _ := apr.GetValueList(cli.TablesFlag)
+	excludeTables, _ := apr.GetValueList(cli.ExcludeTablesFlag)
+	tableFilter, err := env.NewTableFilter(tables, excludeTables)
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+
 	dEnv.UserPassConfig, verr = getRemoteUserAndPassConfig(apr)
 	if verr != nil {
 		return verr
@@ -134,9 +143,10 @@ func clone(ctx context.Context, apr *argparser.ArgParseResults, dEnv *env.DoltEn
 	if verr != nil {
 		return verr
 	}
+	r.TableFilter = tableFilter
 
 	// Create a new Dolt env for the clone
-	clonedEnv, err := actions.EnvForClone(ctx, srcDB.ValueReadWriter().Format(), r, dir, dEnv.FS, dEnv.Version, env.GetCurrentUserHomeDir)
+	clonedEnv, err := actions.EnvForClone(ctx, srcDB, r, dir, dEnv.FS, dEnv.Version, env.GetCurrentUserHomeDir)
 	if err != nil {
 		return errhand.VerboseErrorFromError(err)
 	}
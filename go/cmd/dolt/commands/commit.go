@@ -272,6 +272,9 @@ func constructParametrizedDoltCommitQuery(msg string, apr *argparser.ArgParseRes
 		writeToBuffer("--gpg-sign")
 
 		gpgKey := apr.GetValueOrDefault(cli.SignFlag, "")
+		if gpgKey == "" {
+			gpgKey = cliCtx.Config().GetStringOrDefault(config.GPGSigningKeyKey, "")
+		}
 		if gpgKey != "" {
 			param = true
 			writeToBuffer("?")
@@ -63,6 +63,8 @@ Valid configuration variables:
 
 	- user.name - sets email used in the author and committer field of commit objects.
 
+	- user.signingkey - sets the id of the key used to sign commits when {{.EmphasisLeft}}dolt commit -S{{.EmphasisRight}} is given no key id.
+
 	- remotes.default_host - sets default host for authenticating with doltremoteapi.
 
 	- remotes.default_port - sets default port for authenticating with doltremoteapi.
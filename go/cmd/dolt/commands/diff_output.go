@@ -23,6 +23,7 @@ import (
 
 	textdiff "github.com/andreyvit/diff"
 	"github.com/dolthub/go-mysql-server/sql"
+	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
 	"github.com/dustin/go-humanize"
 	"github.com/fatih/color"
 
@@ -32,6 +33,9 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtablefunctions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlfmt"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/arrow"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/avro"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/json"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/sqlexport"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/tabular"
@@ -68,6 +72,14 @@ func newDiffWriter(diffOutput diffOutput) (diffWriter, error) {
 		return sqlDiffWriter{}, nil
 	case JsonDiffOutput:
 		return newJsonDiffWriter(iohelp.NopWrCloser(cli.CliOut))
+	case AvroDiffOutput:
+		return newBinaryDiffWriter("avro", iohelp.NopWrCloser(cli.CliOut), func(sch sql.Schema, wr io.WriteCloser) (table.SqlRowWriter, error) {
+			return avro.NewAvroRowWriter(sch, wr)
+		}), nil
+	case ArrowDiffOutput:
+		return newBinaryDiffWriter("arrow", iohelp.NopWrCloser(cli.CliOut), func(sch sql.Schema, wr io.WriteCloser) (table.SqlRowWriter, error) {
+			return arrow.NewArrowRowWriter(sch, wr)
+		}), nil
 	default:
 		panic(fmt.Sprintf("unexpected diff output: %v", diffOutput))
 	}
@@ -650,3 +662,135 @@ func (j *jsonDiffWriter) Close(ctx context.Context) error {
 	// Writer has already been closed here during row iteration, no need to close it here
 	return nil
 }
+
+// diffTypeColName is the name of the column holding the type of change of the rows written by a binaryDiffWriter
+const diffTypeColName = "diff_type"
+
+// binaryDiffWriter writes the data diff of a single table as a file of a binary format, such as avro or arrow. The
+// rows of the file have the columns of the union of the table's schemas, followed by a diff_type column with one of the
+// values added, removed, modified_old and modified_new. Schema diffs are not written.
+type binaryDiffWriter struct {
+	format    string
+	wr        io.WriteCloser
+	newWriter func(sch sql.Schema, wr io.WriteCloser) (table.SqlRowWriter, error)
+	tableName string
+	// written is whether the file has been written. A table's diff may have several row writers, only one of which
+	// writes the file.
+	written bool
+}
+
+var _ diffWriter = (*binaryDiffWriter)(nil)
+
+func newBinaryDiffWriter(format string, wr io.WriteCloser, newWriter func(sch sql.Schema, wr io.WriteCloser) (table.SqlRowWriter, error)) *binaryDiffWriter {
+	return &binaryDiffWriter{format: format, wr: wr, newWriter: newWriter}
+}
+
+func (b *binaryDiffWriter) BeginTable(fromTableName, toTableName string, isAdd, isDrop bool) error {
+	tableName := toTableName
+	if len(tableName) == 0 {
+		tableName = fromTableName
+	}
+	if b.tableName != "" && b.tableName != tableName {
+		return fmt.Errorf("%s diff output supports a single table, found changes to %s and %s. Specify the table to diff", b.format, b.tableName, tableName)
+	}
+	b.tableName = tableName
+	return nil
+}
+
+func (b *binaryDiffWriter) WriteTableSchemaDiff(fromTableInfo, toTableInfo *diff.TableInfo, tds diff.TableDeltaSummary) error {
+	return nil
+}
+
+func (b *binaryDiffWriter) WriteEventDiff(ctx context.Context, eventName, oldDefn, newDefn string) error {
+	return fmt.Errorf("event diffs are not supported for %s output", b.format)
+}
+
+func (b *binaryDiffWriter) WriteTriggerDiff(ctx context.Context, triggerName, oldDefn, newDefn string) error {
+	return fmt.Errorf("trigger diffs are not supported for %s output", b.format)
+}
+
+func (b *binaryDiffWriter) WriteViewDiff(ctx context.Context, viewName, oldDefn, newDefn string) error {
+	return fmt.Errorf("view diffs are not supported for %s output", b.format)
+}
+
+func (b *binaryDiffWriter) WriteTableDiffStats(diffStats []diffStatistics, oldColLen, newColLen int, areTablesKeyless bool) error {
+	return fmt.Errorf("diff stats are not supported for %s output", b.format)
+}
+
+func (b *binaryDiffWriter) RowWriter(fromTableInfo, toTableInfo *diff.TableInfo, tds diff.TableDeltaSummary, unionSch sql.Schema) (diff.SqlRowDiffWriter, error) {
+	// the columns of rows which were added or removed are null for the other side of the diff
+	sch := make(sql.Schema, 0, len(unionSch)+1)
+	for _, col := range unionSch {
+		nullableCol := *col
+		nullableCol.Nullable = true
+		sch = append(sch, &nullableCol)
+	}
+	sch = append(sch, &sql.Column{Name: diffTypeColName, Type: gmstypes.LongText, Nullable: false})
+	return &binaryRowDiffWriter{parent: b, sch: sch}, nil
+}
+
+func (b *binaryDiffWriter) Close(ctx context.Context) error {
+	return nil
+}
+
+// binaryRowDiffWriter writes the rows of a table's diff for a binaryDiffWriter. Its file is created with the first row
+// written, or when it's closed if there were no rows and no other row writer wrote the file.
+type binaryRowDiffWriter struct {
+	parent *binaryDiffWriter
+	sch    sql.Schema
+	rowWr  table.SqlRowWriter
+}
+
+func (w *binaryRowDiffWriter) init() error {
+	if w.rowWr != nil {
+		return nil
+	}
+	rowWr, err := w.parent.newWriter(w.sch, w.parent.wr)
+	if err != nil {
+		return err
+	}
+	w.rowWr = rowWr
+	w.parent.written = true
+	return nil
+}
+
+func (w *binaryRowDiffWriter) WriteRow(ctx context.Context, row sql.Row, diffType diff.ChangeType, colDiffTypes []diff.ChangeType) error {
+	if err := w.init(); err != nil {
+		return err
+	}
+
+	var diffTypeStr string
+	switch diffType {
+	case diff.Added:
+		diffTypeStr = "added"
+	case diff.Removed:
+		diffTypeStr = "removed"
+	case diff.ModifiedOld:
+		diffTypeStr = "modified_old"
+	case diff.ModifiedNew:
+		diffTypeStr = "modified_new"
+	default:
+		return fmt.Errorf("unexpected diff type: %v", diffType)
+	}
+
+	r := make(sql.Row, 0, len(row)+1)
+	r = append(r, row...)
+	r = append(r, diffTypeStr)
+	return w.rowWr.WriteSqlRow(ctx, r)
+}
+
+func (w *binaryRowDiffWriter) WriteCombinedRow(ctx context.Context, oldRow, newRow sql.Row, mode diff.Mode) error {
+	return fmt.Errorf("%s format is unable to output diffs for combined rows", w.parent.format)
+}
+
+func (w *binaryRowDiffWriter) Close(ctx context.Context) error {
+	if w.rowWr == nil {
+		if w.parent.written {
+			return nil
+		}
+		if err := w.init(); err != nil {
+			return err
+		}
+	}
+	return w.rowWr.Close(ctx)
+}
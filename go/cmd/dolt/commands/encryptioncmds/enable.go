@@ -0,0 +1,113 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryptioncmds
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/store/nbs"
+)
+
+const (
+	keyFileParam    = "key-file"
+	keyCommandParam = "key-command"
+)
+
+var enableDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Enable encryption at rest for the database.",
+	LongDesc: `Enables encryption at rest for the table files, archives and chunk journal of the database. Each storage file is encrypted with its own data key, which is wrapped by a master key managed by a key provider.
+
+With {{.EmphasisLeft}}--key-file{{.EmphasisRight}}, master keys are stored in a local key file, which is created with a new key if it does not exist. With {{.EmphasisLeft}}--key-command{{.EmphasisRight}}, data keys are wrapped and unwrapped by an external command, such as a plugin for a key management service. The command is run with a JSON request on stdin and must write a JSON response to stdout.
+
+Storage files written after encryption is enabled are encrypted. Run {{.EmphasisLeft}}dolt gc --full{{.EmphasisRight}} to encrypt existing storage files.`,
+	Synopsis: []string{
+		"--key-file {{.LessThan}}path{{.GreaterThan}}",
+		"--key-command {{.LessThan}}command{{.GreaterThan}}",
+	},
+}
+
+type EnableCmd struct{}
+
+// Name is returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd EnableCmd) Name() string {
+	return "enable"
+}
+
+// Description returns a description of the command
+func (cmd EnableCmd) Description() string {
+	return enableDocs.ShortDesc
+}
+
+func (cmd EnableCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(enableDocs, ap)
+}
+
+func (cmd EnableCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 0)
+	ap.SupportsString(keyFileParam, "", "path", "Key file holding the master keys.")
+	ap.SupportsString(keyCommandParam, "", "command", "Command that wraps and unwraps data keys.")
+	return ap
+}
+
+// Exec executes the command
+func (cmd EnableCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, enableDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	var cfg nbs.EncryptionConfig
+	keyFile, hasKeyFile := apr.GetValue(keyFileParam)
+	keyCommand, hasKeyCommand := apr.GetValue(keyCommandParam)
+	switch {
+	case hasKeyFile && hasKeyCommand:
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: --%s and --%s cannot be used together", keyFileParam, keyCommandParam).Build(), usage)
+	case hasKeyFile:
+		keyFile, err := filepath.Abs(keyFile)
+		if err != nil {
+			return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
+		if exists, _ := dEnv.FS.Exists(keyFile); !exists {
+			keyID, err := nbs.AddEncryptionKey(keyFile)
+			if err != nil {
+				return commands.HandleVErrAndExitCode(errhand.BuildDError("error: failed to create key file").AddCause(err).Build(), usage)
+			}
+			cli.Printf("Created key file %s with key %s\n", keyFile, keyID)
+		}
+		cfg = nbs.EncryptionConfig{KeyProvider: nbs.FileKeyProviderName, KeyFile: keyFile}
+	case hasKeyCommand:
+		cfg = nbs.EncryptionConfig{KeyProvider: nbs.ExecKeyProviderName, Command: strings.Fields(keyCommand)}
+	default:
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: one of --%s or --%s is required", keyFileParam, keyCommandParam).SetPrintUsage().Build(), usage)
+	}
+
+	dir, err := dataDir(dEnv)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if err = nbs.EnableEncryption(dir, cfg); err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: failed to enable encryption at rest").AddCause(err).Build(), usage)
+	}
+
+	cli.Println("Encryption at rest enabled. Run 'dolt gc --full' to encrypt existing storage files.")
+	return 0
+}
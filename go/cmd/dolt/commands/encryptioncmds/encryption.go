@@ -0,0 +1,32 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryptioncmds
+
+import (
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+)
+
+var Commands = cli.NewSubCommandHandler("encryption", "Commands for managing encryption at rest of the database's storage files.", []cli.Command{
+	EnableCmd{},
+	NewKeyCmd{},
+	StatusCmd{},
+})
+
+// dataDir returns the absolute path of the directory holding the storage files of the database in |dEnv|.
+func dataDir(dEnv *env.DoltEnv) (string, error) {
+	return dEnv.FS.Abs(dbfactory.DoltDataDir)
+}
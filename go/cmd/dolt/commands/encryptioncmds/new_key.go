@@ -0,0 +1,85 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryptioncmds
+
+import (
+	"context"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/store/nbs"
+)
+
+var newKeyDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Rotate the master key used to encrypt the database at rest.",
+	LongDesc: `Adds a new master key to the key file of the database and makes it the current key. Storage files written afterwards have their data keys wrapped with the new key. Existing storage files are not changed, and are still read with the keys they were written with.
+
+Rotation of existing storage files happens through {{.EmphasisLeft}}dolt gc --full{{.EmphasisRight}}, which rewrites every table file, archive and the chunk journal of the database, encrypting them with the new key. Run {{.EmphasisLeft}}dolt encryption status{{.EmphasisRight}} afterwards to confirm that no file still uses an old key before removing old keys from the key file; files encrypted with a removed key can't be read. {{.EmphasisLeft}}dolt gc{{.EmphasisRight}} without {{.EmphasisLeft}}--full{{.EmphasisRight}} only rewrites the files written since the last full collection, so it does not complete a rotation.
+
+Keys for databases using {{.EmphasisLeft}}--key-command{{.EmphasisRight}} are rotated by the key management service.`,
+	Synopsis: []string{},
+}
+
+type NewKeyCmd struct{}
+
+// Name is returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd NewKeyCmd) Name() string {
+	return "new-key"
+}
+
+// Description returns a description of the command
+func (cmd NewKeyCmd) Description() string {
+	return newKeyDocs.ShortDesc
+}
+
+func (cmd NewKeyCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(newKeyDocs, ap)
+}
+
+func (cmd NewKeyCmd) ArgParser() *argparser.ArgParser {
+	return argparser.NewArgParserWithMaxArgs(cmd.Name(), 0)
+}
+
+// Exec executes the command
+func (cmd NewKeyCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, newKeyDocs, ap))
+	cli.ParseArgsOrDie(ap, args, help)
+
+	dir, err := dataDir(dEnv)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	cfg, err := nbs.LoadEncryptionConfig(dir)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	} else if cfg == nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: encryption at rest is not enabled for this database").Build(), usage)
+	} else if cfg.KeyProvider != nbs.FileKeyProviderName {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: keys of the '%s' key provider are not managed by dolt", cfg.KeyProvider).Build(), usage)
+	}
+
+	keyID, err := nbs.AddEncryptionKey(cfg.KeyFile)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: failed to add key to %s", cfg.KeyFile).AddCause(err).Build(), usage)
+	}
+
+	cli.Printf("Added key %s to %s. Run 'dolt gc --full' to re-encrypt existing storage files.\n", keyID, cfg.KeyFile)
+	return 0
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryptioncmds
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/store/nbs"
+)
+
+var statusDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Show the encryption at rest status of the database.",
+	LongDesc:  `Prints the key provider used to encrypt the database at rest, and lists each storage file of the database with the ID of the master key it is encrypted with, or {{.EmphasisLeft}}plaintext{{.EmphasisRight}} if it is not encrypted.`,
+	Synopsis:  []string{},
+}
+
+type StatusCmd struct{}
+
+// Name is returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd StatusCmd) Name() string {
+	return "status"
+}
+
+// Description returns a description of the command
+func (cmd StatusCmd) Description() string {
+	return statusDocs.ShortDesc
+}
+
+func (cmd StatusCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(statusDocs, ap)
+}
+
+func (cmd StatusCmd) ArgParser() *argparser.ArgParser {
+	return argparser.NewArgParserWithMaxArgs(cmd.Name(), 0)
+}
+
+// Exec executes the command
+func (cmd StatusCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, statusDocs, ap))
+	cli.ParseArgsOrDie(ap, args, help)
+
+	dir, err := dataDir(dEnv)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	cfg, err := nbs.LoadEncryptionConfig(dir)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if cfg == nil {
+		cli.Println("Encryption at rest is not enabled")
+	} else {
+		cli.Printf("Encryption at rest is enabled with the '%s' key provider\n", cfg.KeyProvider)
+	}
+
+	files, err := nbs.ListStorageFileEncryption(dir)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	for _, f := range files {
+		name, err := filepath.Rel(dir, f.Path)
+		if err != nil {
+			name = f.Path
+		}
+		keyID := f.KeyID
+		if keyID == "" {
+			keyID = "plaintext"
+		}
+		cli.Printf("\t%s\t%s\n", name, keyID)
+	}
+	return 0
+}
@@ -28,6 +28,8 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/arrow"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/avro"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/json"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/parquet"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/csv"
@@ -44,6 +46,8 @@ const (
 	FormatNull // used for profiling
 	FormatVertical
 	FormatParquet
+	FormatAvro
+	FormatArrow
 )
 
 type PrintSummaryBehavior byte
@@ -105,6 +109,18 @@ func prettyPrintResultsWithSummary(ctx *sql.Context, resultFormat PrintResultFor
 		if err != nil {
 			return err
 		}
+	case FormatAvro:
+		var err error
+		wr, err = avro.NewAvroRowWriter(sqlSch, iohelp.NopWrCloser(cli.CliOut))
+		if err != nil {
+			return err
+		}
+	case FormatArrow:
+		var err error
+		wr, err = arrow.NewArrowRowWriter(sqlSch, iohelp.NopWrCloser(cli.CliOut))
+		if err != nil {
+			return err
+		}
 	}
 
 	numRows, err := writeResultSet(ctx, rowIter, wr)
@@ -47,6 +47,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/statspro"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/writer"
 	"github.com/dolthub/dolt/go/libraries/utils/config"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 )
 
 // SqlEngine packages up the context necessary to run sql queries against dsqle.
@@ -55,6 +56,9 @@ type SqlEngine struct {
 	contextFactory contextFactory
 	dsessFactory   sessionFactory
 	engine         *gms.Engine
+
+	branchControl   *branch_control.Controller
+	branchControlFS filesys.Filesys
 }
 
 type sessionFactory func(mysqlSess *sql.BaseSession, pro sql.DatabaseProvider) (*dsess.DoltSession, error)
@@ -144,6 +148,7 @@ func NewSqlEngine(
 		IsReadOnly:     config.IsReadOnly,
 		IsServerLocked: config.IsServerLocked,
 	}).WithBackgroundThreads(bThreads)
+	pro.SetStatementRunner(engine)
 
 	if err := configureBinlogPrimaryController(engine); err != nil {
 		return nil, err
@@ -193,6 +198,8 @@ func NewSqlEngine(
 	sqlEngine.contextFactory = sqlContextFactory()
 	sqlEngine.dsessFactory = sessFactory
 	sqlEngine.engine = engine
+	sqlEngine.branchControl = bcController
+	sqlEngine.branchControlFS = mrEnv.FileSystem()
 
 	// configuring stats depends on sessionBuilder
 	// sessionBuilder needs ref to statsProv
@@ -230,7 +237,11 @@ func NewSqlEngine(
 			return nil, err
 		}
 
-		err = configureBinlogReplicaController(config, engine, binLogSession)
+		newBinlogSession := func() (*dsess.DoltSession, error) {
+			return sessFactory(sql.NewBaseSession(), pro)
+		}
+
+		err = configureBinlogReplicaController(config, engine, binLogSession, newBinlogSession)
 		if err != nil {
 			return nil, err
 		}
@@ -314,22 +325,38 @@ func (se *SqlEngine) GetUnderlyingEngine() *gms.Engine {
 }
 
 func (se *SqlEngine) Close() error {
+	if se.branchControl != nil {
+		// commit authors are not saved as they are recorded, so any recorded since the last save are saved now
+		if err := se.branchControl.SaveRecordedAuthors(context.Background(), se.branchControlFS); err != nil {
+			logrus.Warnf("error saving the recorded commit authors of the branch control data: %v", err)
+		}
+	}
 	if se.engine != nil {
 		return se.engine.Close()
 	}
 	return nil
 }
 
-// configureBinlogReplicaController configures the binlog replication controller with the |engine|.
-func configureBinlogReplicaController(config *SqlEngineConfig, engine *gms.Engine, session *dsess.DoltSession) error {
+// configureBinlogReplicaController configures the binlog replication controller with the |engine|. |session| is used
+// for the default replication channel's applier, and |newSession| creates the sessions for the appliers of named
+// replication channels. The |engine|'s parser is wrapped to support the FOR CHANNEL clause of replication statements.
+func configureBinlogReplicaController(config *SqlEngineConfig, engine *gms.Engine, session *dsess.DoltSession, newSession func() (*dsess.DoltSession, error)) error {
 	ctxFactory := sqlContextFactory()
 	executionCtx, err := ctxFactory(context.Background(), session)
 	if err != nil {
 		return err
 	}
 	dblr.DoltBinlogReplicaController.SetExecutionContext(executionCtx)
+	dblr.DoltBinlogReplicaController.SetExecutionContextFactory(func() (*sql.Context, error) {
+		session, err := newSession()
+		if err != nil {
+			return nil, err
+		}
+		return ctxFactory(context.Background(), session)
+	})
 	dblr.DoltBinlogReplicaController.SetEngine(engine)
 	engine.Analyzer.Catalog.BinlogReplicaController = config.BinlogReplicaController
+	engine.Parser = dblr.NewReplicaChannelParser(engine.Parser)
 
 	return nil
 }
@@ -37,10 +37,13 @@ var fetchDocs = cli.CommandDocumentationContent{
 By default dolt will attempt to fetch from a remote named {{.EmphasisLeft}}origin{{.EmphasisRight}}.  The {{.LessThan}}remote{{.GreaterThan}} parameter allows you to specify the name of a different remote you wish to pull from by the remote's name.
 
 When no refspec(s) are specified on the command line, the fetch_specs for the default remote are used.
+
+The data of a repository cloned with {{.EmphasisLeft}}--tables{{.EmphasisRight}} or {{.EmphasisLeft}}--exclude-tables{{.EmphasisRight}} is only fetched for the selected tables. {{.EmphasisLeft}}--tables{{.EmphasisRight}} adds tables to the selection and fetches their data for every commit already fetched, and {{.EmphasisLeft}}--exclude-tables{{.EmphasisRight}} stops fetching the data of tables from the remote.
 `,
 
 	Synopsis: []string{
 		"[{{.LessThan}}remote{{.GreaterThan}}] [{{.LessThan}}refspec{{.GreaterThan}} ...]",
+		"[{{.LessThan}}remote{{.GreaterThan}}] --tables {{.LessThan}}tables{{.GreaterThan}}",
 	},
 }
 
@@ -150,6 +153,14 @@ func constructInterpolatedDoltFetchQuery(apr *argparser.ArgParseResults) (string
 		args = append(args, "?")
 		params = append(params, user)
 	}
+	if tables, hasTables := apr.GetValue(cli.TablesFlag); hasTables {
+		args = append(args, "?")
+		params = append(params, "--"+cli.TablesFlag+"="+tables)
+	}
+	if excludeTables, hasExcludeTables := apr.GetValue(cli.ExcludeTablesFlag); hasExcludeTables {
+		args = append(args, "?")
+		params = append(params, "--"+cli.ExcludeTablesFlag+"="+excludeTables)
+	}
 	for _, arg := range apr.Args {
 		args = append(args, "?")
 		params = append(params, arg)
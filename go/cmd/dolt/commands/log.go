@@ -282,6 +282,7 @@ func getExistingTables(revisions []string, queryist cli.Queryist, sqlCtx *sql.Co
 func logCommits(apr *argparser.ArgParseResults, commitHashes []sql.Row, queryist cli.Queryist, sqlCtx *sql.Context) error {
 	opts := commitInfoOptions{
 		showSignature: apr.Contains(cli.ShowSignatureFlag),
+		showNotes:     apr.Contains(cli.NotesFlag),
 	}
 
 	var commitsInfo []CommitInfo
@@ -343,6 +344,9 @@ func logCompact(pager *outputpager.Pager, apr *argparser.ArgParseResults, commit
 func logDefault(pager *outputpager.Pager, apr *argparser.ArgParseResults, commits []CommitInfo, sqlCtx *sql.Context, queryist cli.Queryist) error {
 	for _, comm := range commits {
 		PrintCommitInfo(pager, apr.GetIntOrDefault(cli.MinParentsFlag, 0), apr.Contains(cli.ParentsFlag), apr.Contains(cli.ShowSignatureFlag), apr.GetValueOrDefault(cli.DecorateFlag, "auto"), &comm)
+		if comm.note != "" && len(comm.parentHashes) >= apr.GetIntOrDefault(cli.MinParentsFlag, 0) {
+			pager.Writer.Write([]byte("Notes:\n\t" + strings.Replace(comm.note, "\n", "\n\t", -1) + "\n\n"))
+		}
 		if apr.Contains(cli.StatFlag) {
 			if comm.parentHashes != nil && len(comm.parentHashes) == 1 { // don't print stats for merge commits
 				diffStats := make(map[string]*merge.MergeStats)
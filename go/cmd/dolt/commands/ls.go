@@ -17,6 +17,7 @@ package commands
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/gocraft/dbr/v2"
@@ -28,6 +29,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/libraries/utils/set"
 )
 
 var lsDocs = cli.CommandDocumentationContent{
@@ -37,6 +39,8 @@ var lsDocs = cli.CommandDocumentationContent{
 If the {{.EmphasisLeft}}--system{{.EmphasisRight}} flag is supplied this will show the dolt system tables which are queryable with SQL.
 
 If the {{.EmphasisLeft}}--all{{.EmphasisRight}} flag is supplied both user and system tables will be printed.
+
+Tables whose data wasn't fetched by a partial clone are marked as not fetched.
 `,
 
 	Synopsis: []string{
@@ -111,6 +115,11 @@ func (cmd LsCmd) Exec(ctx context.Context, commandStr string, args []string, dEn
 		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
 	}
 
+	unfetchedTables, err := getUnfetchedTables(apr, queryist, sqlCtx)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+
 	var userTables []string
 	var systemTables []string
 	for _, row := range rows {
@@ -123,7 +132,7 @@ func (cmd LsCmd) Exec(ctx context.Context, commandStr string, args []string, dEn
 	}
 
 	if apr.Contains(cli.AllFlag) {
-		err = printUserTables(userTables, apr, queryist, sqlCtx)
+		err = printUserTables(userTables, unfetchedTables, apr, queryist, sqlCtx)
 		if err != nil {
 			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
 		}
@@ -137,7 +146,7 @@ func (cmd LsCmd) Exec(ctx context.Context, commandStr string, args []string, dEn
 			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
 		}
 	} else {
-		err = printUserTables(userTables, apr, queryist, sqlCtx)
+		err = printUserTables(userTables, unfetchedTables, apr, queryist, sqlCtx)
 		if err != nil {
 			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
 		}
@@ -162,7 +171,34 @@ func constructInterpolatedDoltLsQuery(apr *argparser.ArgParseResults) (string, e
 	}
 }
 
-func printUserTables(tableNames []string, apr *argparser.ArgParseResults, queryist cli.Queryist, sqlCtx *sql.Context) error {
+// getUnfetchedTables returns the tables whose data wasn't fetched by a partial clone. They aren't listed by show tables.
+func getUnfetchedTables(apr *argparser.ArgParseResults, queryist cli.Queryist, sqlCtx *sql.Context) ([]string, error) {
+	query := "select table_name from dolt_unfetched_tables"
+	if apr.NArg() == 1 {
+		var err error
+		query, err = dbr.InterpolateForDialect(query+" as of ?", []interface{}{apr.Arg(0)}, dialect.MySQL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := GetRowsForSql(queryist, sqlCtx, query)
+	if err != nil {
+		if isTableNotFoundError(err) {
+			// servers of older versions don't support partial clones
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	tableNames := make([]string, len(rows))
+	for i, row := range rows {
+		tableNames[i] = row[0].(string)
+	}
+	return tableNames, nil
+}
+
+func printUserTables(tableNames, unfetchedTables []string, apr *argparser.ArgParseResults, queryist cli.Queryist, sqlCtx *sql.Context) error {
 	var label string
 	if apr.NArg() == 0 {
 		label = "working set"
@@ -175,14 +211,20 @@ func printUserTables(tableNames []string, apr *argparser.ArgParseResults, queryi
 		label = row[0][0].(string)
 	}
 
-	if len(tableNames) == 0 {
+	if len(tableNames) == 0 && len(unfetchedTables) == 0 {
 		cli.Printf("No tables in %s\n", label)
 		return nil
 	}
 
+	unfetched := set.NewStrSet(unfetchedTables)
+	tableNames = append(tableNames, unfetchedTables...)
+	sort.Strings(tableNames)
+
 	cli.Printf("Tables in %s:\n", label)
 	for _, tbl := range tableNames {
-		if apr.Contains(cli.VerboseFlag) {
+		if unfetched.Contains(tbl) {
+			cli.Println("\t", tbl, "(not fetched)")
+		} else if apr.Contains(cli.VerboseFlag) {
 			err := printTableVerbose(tbl, queryist, sqlCtx)
 			if err != nil {
 				return err
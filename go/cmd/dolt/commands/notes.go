@@ -0,0 +1,174 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/fatih/color"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+var notesDocs = cli.CommandDocumentationContent{
+	ShortDesc: `Add, show, list or remove commit notes.`,
+	LongDesc: `Notes annotate commits without changing them. Each commit has at most one note, which can be replaced or removed at any time, and is stored in {{.EmphasisLeft}}refs/notes/{{.LessThan}}commit hash{{.GreaterThan}}{{.EmphasisRight}}.
+
+{{.EmphasisLeft}}add{{.EmphasisRight}} attaches the note given with {{.EmphasisLeft}}-m{{.EmphasisRight}} to {{.LessThan}}commit{{.GreaterThan}}, which defaults to {{.EmphasisLeft}}HEAD{{.EmphasisRight}}. It is an error to add a note to a commit that already has one unless {{.EmphasisLeft}}-f{{.EmphasisRight}} is given.
+
+{{.EmphasisLeft}}show{{.EmphasisRight}} prints the note of {{.LessThan}}commit{{.GreaterThan}}, and {{.EmphasisLeft}}list{{.EmphasisRight}} lists every note, which is also the behavior when no subcommand is given. {{.EmphasisLeft}}remove{{.EmphasisRight}} removes the notes of the commits given.
+
+Notes are pushed and fetched with the refspec {{.EmphasisLeft}}refs/notes/*{{.EmphasisRight}}, e.g. {{.EmphasisLeft}}dolt push origin refs/notes/*{{.EmphasisRight}}.`,
+	Synopsis: []string{
+		`[list]`,
+		`add [-f] -m {{.LessThan}}msg{{.GreaterThan}} [{{.LessThan}}commit{{.GreaterThan}}]`,
+		`show [{{.LessThan}}commit{{.GreaterThan}}]`,
+		`remove [{{.LessThan}}commit{{.GreaterThan}}...]`,
+	},
+}
+
+type NotesCmd struct{}
+
+var _ cli.Command = NotesCmd{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd NotesCmd) Name() string {
+	return "notes"
+}
+
+// Description returns a description of the command
+func (cmd NotesCmd) Description() string {
+	return notesDocs.ShortDesc
+}
+
+func (cmd NotesCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(notesDocs, ap)
+}
+
+func (cmd NotesCmd) ArgParser() *argparser.ArgParser {
+	return cli.CreateNotesArgParser()
+}
+
+// Exec executes the command
+func (cmd NotesCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, notesDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	queryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
+	if err != nil {
+		return handleStatusVErr(err)
+	}
+	if closeFunc != nil {
+		defer closeFunc()
+	}
+
+	subcommand := "list"
+	if apr.NArg() > 0 {
+		subcommand = apr.Arg(0)
+	}
+
+	switch subcommand {
+	case "add", "remove":
+		err = callNotesProcedure(queryist, sqlCtx, subcommand, args)
+	case "show":
+		err = showNote(queryist, sqlCtx, apr)
+	case "list":
+		err = listNotes(queryist, sqlCtx, apr)
+	default:
+		err = fmt.Errorf("error: unknown subcommand '%s'", subcommand)
+	}
+	return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+}
+
+func callNotesProcedure(queryist cli.Queryist, sqlCtx *sql.Context, subcommand string, args []string) error {
+	query, err := interpolateStoredProcedureCall("DOLT_NOTES", args)
+	if err != nil {
+		return err
+	}
+
+	_, err = GetRowsForSql(queryist, sqlCtx, query)
+	if err != nil {
+		return fmt.Errorf("error: failed to %s note: %w", subcommand, err)
+	}
+	return nil
+}
+
+func showNote(queryist cli.Queryist, sqlCtx *sql.Context, apr *argparser.ArgParseResults) error {
+	if apr.NArg() > 2 {
+		return errors.New("show note takes at most one commit")
+	}
+	commit := "HEAD"
+	if apr.NArg() == 2 {
+		commit = apr.Arg(1)
+	}
+
+	rows, err := InterpolateAndRunQuery(queryist, sqlCtx, "SELECT note FROM dolt_notes WHERE commit_hash = hashof(?)", commit)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf("error: no note found for commit %s", commit)
+	}
+
+	cli.Println(rows[0][0].(string))
+	return nil
+}
+
+func listNotes(queryist cli.Queryist, sqlCtx *sql.Context, apr *argparser.ArgParseResults) error {
+	if apr.Contains(cli.MessageArg) || apr.Contains(cli.ForceFlag) {
+		return errors.New("must use the add subcommand to add a note")
+	}
+
+	var rows []sql.Row
+	var err error
+	if apr.NArg() > 1 {
+		if apr.NArg() > 2 {
+			return errors.New("list notes takes at most one commit")
+		}
+		rows, err = InterpolateAndRunQuery(queryist, sqlCtx, "SELECT * FROM dolt_notes WHERE commit_hash = hashof(?)", apr.Arg(1))
+	} else {
+		rows, err = GetRowsForSql(queryist, sqlCtx, "SELECT * FROM dolt_notes")
+	}
+	if err != nil {
+		return fmt.Errorf("error: failed to list notes: %w", err)
+	}
+
+	for _, row := range rows {
+		timestamp, err := getTimestampColAsUint64(row[3])
+		if err != nil {
+			return fmt.Errorf("failed to parse note timestamp: %w", err)
+		}
+
+		cli.Println(color.YellowString("note %s", row[0].(string)))
+		cli.Printf("Author: %s <%s>\n", row[1].(string), row[2].(string))
+		timeStr := time.UnixMilli(int64(timestamp)).In(datas.CommitLoc).Format(time.RubyDate)
+		cli.Println("Date:  ", timeStr)
+		cli.Println("\n\t" + strings.Replace(row[4].(string), "\n", "\n\t", -1))
+		cli.Println("")
+	}
+
+	return nil
+}
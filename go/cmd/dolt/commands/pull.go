@@ -41,6 +41,8 @@ var pullDocs = cli.CommandDocumentationContent{
 	LongDesc: `Incorporates changes from a remote repository into the current branch. In its default mode, {{.EmphasisLeft}}dolt pull{{.EmphasisRight}} is shorthand for {{.EmphasisLeft}}dolt fetch{{.EmphasisRight}} followed by {{.EmphasisLeft}}dolt merge <remote>/<branch>{{.EmphasisRight}}.
 
 More precisely, dolt pull runs {{.EmphasisLeft}}dolt fetch{{.EmphasisRight}} with the given parameters and calls {{.EmphasisLeft}}dolt merge{{.EmphasisRight}} to merge the retrieved branch {{.EmphasisLeft}}HEAD{{.EmphasisRight}} into the current branch.
+
+The {{.EmphasisLeft}}--tables{{.EmphasisRight}} and {{.EmphasisLeft}}--exclude-tables{{.EmphasisRight}} options change the tables fetched from the remote by a partial clone, as they do for {{.EmphasisLeft}}dolt fetch{{.EmphasisRight}}.
 `,
 	Synopsis: []string{
 		`[{{.LessThan}}remote{{.GreaterThan}}, [{{.LessThan}}remoteBranch{{.GreaterThan}}]]`,
@@ -259,6 +261,14 @@ func constructInterpolatedDoltPullQuery(apr *argparser.ArgParseResults) (string,
 		args = append(args, "?")
 		params = append(params, user)
 	}
+	if tables, hasTables := apr.GetValue(cli.TablesFlag); hasTables {
+		args = append(args, "?")
+		params = append(params, "--"+cli.TablesFlag+"="+tables)
+	}
+	if excludeTables, hasExcludeTables := apr.GetValue(cli.ExcludeTablesFlag); hasExcludeTables {
+		args = append(args, "?")
+		params = append(params, "--"+cli.ExcludeTablesFlag+"="+excludeTables)
+	}
 
 	query := "call dolt_pull(" + strings.Join(args, ", ") + ")"
 
@@ -132,7 +132,7 @@ func (cmd ReadTablesCmd) Exec(ctx context.Context, commandStr string, args []str
 		BuildVerrAndExit("Failed to get remote branches", err)
 	}
 
-	dEnv, verr = initializeShallowCloneRepo(ctx, dEnv, srcDB.Format(), dir, env.GetDefaultBranch(dEnv, branches))
+	dEnv, verr = initializeShallowCloneRepo(ctx, dEnv, srcDB, dir, env.GetDefaultBranch(dEnv, branches))
 	if verr != nil {
 		return HandleVErrAndExitCode(verr, usage)
 	}
@@ -234,9 +234,9 @@ func getRemoteDBAtCommit(ctx context.Context, remoteUrl string, remoteUrlParams
 	return srcDB, srcRoot, nil
 }
 
-func initializeShallowCloneRepo(ctx context.Context, dEnv *env.DoltEnv, nbf *types.NomsBinFormat, dir, branchName string) (*env.DoltEnv, errhand.VerboseError) {
+func initializeShallowCloneRepo(ctx context.Context, dEnv *env.DoltEnv, srcDB *doltdb.DoltDB, dir, branchName string) (*env.DoltEnv, errhand.VerboseError) {
 	var err error
-	dEnv, err = actions.EnvForClone(ctx, nbf, env.NoRemote, dir, dEnv.FS, dEnv.Version, env.GetCurrentUserHomeDir)
+	dEnv, err = actions.EnvForClone(ctx, srcDB, env.NoRemote, dir, dEnv.FS, dEnv.Version, env.GetCurrentUserHomeDir)
 
 	if err != nil {
 		return nil, errhand.VerboseErrorFromError(err)
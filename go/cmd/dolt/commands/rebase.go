@@ -47,9 +47,21 @@ the same as Git and Dolt's "two dot log" syntax, or |upstreamBranch|..|currentBr
 Rebasing is useful to clean and organize your commit history, especially before merging a feature branch back to a shared 
 branch. For example, you can drop commits that contain debugging or test changes, or squash or fixup small commits into a 
 single commit, or reorder commits so that related changes are adjacent in the new commit history.
+
+The rebase plan can also pause the rebase. An {{.EmphasisLeft}}edit{{.EmphasisRight}} step applies a commit and then stops so 
+that it can be amended, a {{.EmphasisLeft}}break{{.EmphasisRight}} step stops without applying a commit, and an 
+{{.EmphasisLeft}}exec{{.EmphasisRight}} step runs SQL statements and stops if they fail, for example to check constraints 
+with {{.EmphasisLeft}}CALL dolt_verify_constraints('--all'){{.EmphasisRight}}. Run {{.EmphasisLeft}}dolt rebase --continue{{.EmphasisRight}} to resume the rebase.
+
+By default, merge commits are dropped from the rebase plan and the commits they merged in are replayed one at a time. With 
+{{.EmphasisLeft}}--rebase-merges{{.EmphasisRight}}, the rebase plan follows the first parents of the current branch and each merge commit 
+is replayed with a {{.EmphasisLeft}}merge{{.EmphasisRight}} step, which merges the merge commit's original second parent.
+
+With {{.EmphasisLeft}}--onto {{.LessThan}}newbase{{.GreaterThan}}{{.EmphasisRight}}, the commits in |upstream|..|currentBranch| are replayed on top of 
+{{.LessThan}}newbase{{.GreaterThan}} instead of |upstream|, which transplants a range of commits onto a different base.
 `,
 	Synopsis: []string{
-		`(-i | --interactive) [--empty=drop|keep] {{.LessThan}}upstream{{.GreaterThan}}`,
+		`(-i | --interactive) [--empty=drop|keep] [--rebase-merges] [--onto {{.LessThan}}newbase{{.GreaterThan}}] {{.LessThan}}upstream{{.GreaterThan}}`,
 		`(--continue | --abort)`,
 	},
 }
@@ -137,7 +149,21 @@ func (cmd RebaseCmd) Exec(ctx context.Context, commandStr string, args []string,
 		return 0
 	}
 
-	rebasePlan, err := getRebasePlan(cliCtx, sqlCtx, queryist, apr.Arg(0), branchName)
+	// If the rebase stopped at an edit or break step, leave the CLI on the rebase working branch so that
+	// changes can be made before the rebase is continued
+	if strings.Contains(message, dprocedures.RebaseStoppedMessage) {
+		cli.Println(message)
+		if err = syncCliBranchToSqlSessionBranch(sqlCtx, dEnv); err != nil {
+			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
+		return 0
+	}
+
+	onto, ok := apr.GetValue(cli.OntoParam)
+	if !ok {
+		onto = apr.Arg(0)
+	}
+	rebasePlan, err := getRebasePlan(cliCtx, sqlCtx, queryist, apr.Arg(0), onto, branchName)
 	if err != nil {
 		// attempt to abort the rebase
 		_, _, _, _ = queryist.Query(sqlCtx, "CALL DOLT_REBASE('--abort');")
@@ -171,8 +197,9 @@ func (cmd RebaseCmd) Exec(ctx context.Context, commandStr string, args []string,
 
 	rows, err = GetRowsForSql(queryist, sqlCtx, "CALL DOLT_REBASE('--continue');")
 	if err != nil {
-		// If the error is a data conflict, don't abort the rebase, but let the caller resolve the conflicts
-		if dprocedures.ErrRebaseDataConflict.Is(err) || strings.Contains(err.Error(), dprocedures.ErrRebaseDataConflict.Message[:40]) {
+		// If the error is a data conflict or a failed exec step, don't abort the rebase, but let the caller
+		// fix the problem and continue the rebase
+		if isResumableRebaseError(err) {
 			if checkoutErr := syncCliBranchToSqlSessionBranch(sqlCtx, dEnv); checkoutErr != nil {
 				return HandleVErrAndExitCode(errhand.VerboseErrorFromError(checkoutErr), usage)
 			}
@@ -201,12 +228,27 @@ func (cmd RebaseCmd) Exec(ctx context.Context, commandStr string, args []string,
 		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(errors.New("error: "+rows[0][1].(string))), usage)
 	}
 
-	cli.Println(rows[0][1].(string))
+	message = rows[0][1].(string)
+	cli.Println(message)
+	if strings.Contains(message, dprocedures.RebaseStoppedMessage) {
+		if err = syncCliBranchToSqlSessionBranch(sqlCtx, dEnv); err != nil {
+			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
+	}
 	return 0
 }
 
+// isResumableRebaseError returns true if |err| stops the rebase in a state that the caller can fix before
+// continuing the rebase, such as a data conflict or a failed exec step. The rebase runs in the local Dolt session
+// of the CLI (see syncCliBranchToSqlSessionBranch), so the errors of the procedure keep their kinds.
+func isResumableRebaseError(err error) bool {
+	return dprocedures.ErrRebaseDataConflict.Is(err) ||
+		dprocedures.ErrRebaseExecFailed.Is(err) ||
+		dprocedures.ErrRebaseExecLeftChanges.Is(err)
+}
+
 // getRebasePlan opens an editor for users to edit the rebase plan and returns the parsed rebase plan from the editor.
-func getRebasePlan(cliCtx cli.CliContext, sqlCtx *sql.Context, queryist cli.Queryist, rebaseBranch, currentBranch string) (*rebase.RebasePlan, error) {
+func getRebasePlan(cliCtx cli.CliContext, sqlCtx *sql.Context, queryist cli.Queryist, rebaseBranch, ontoBranch, currentBranch string) (*rebase.RebasePlan, error) {
 	if cli.ExecuteWithStdioRestored == nil {
 		return nil, nil
 	}
@@ -215,7 +257,7 @@ func getRebasePlan(cliCtx cli.CliContext, sqlCtx *sql.Context, queryist cli.Quer
 		return nil, nil
 	}
 
-	initialRebaseMsg, err := buildInitialRebaseMsg(sqlCtx, queryist, rebaseBranch, currentBranch)
+	initialRebaseMsg, err := buildInitialRebaseMsg(sqlCtx, queryist, rebaseBranch, ontoBranch, currentBranch)
 	if err != nil {
 		return nil, err
 	}
@@ -241,7 +283,7 @@ func getRebasePlan(cliCtx cli.CliContext, sqlCtx *sql.Context, queryist cli.Quer
 
 // buildInitialRebaseMsg builds the initial message to display to the user when they open the rebase plan editor,
 // including the formatted rebase plan.
-func buildInitialRebaseMsg(sqlCtx *sql.Context, queryist cli.Queryist, rebaseBranch, currentBranch string) (string, error) {
+func buildInitialRebaseMsg(sqlCtx *sql.Context, queryist cli.Queryist, rebaseBranch, ontoBranch, currentBranch string) (string, error) {
 	var buffer bytes.Buffer
 
 	rows, err := GetRowsForSql(queryist, sqlCtx, "SELECT action, commit_hash, commit_message FROM dolt_rebase ORDER BY rebase_order")
@@ -257,7 +299,14 @@ func buildInitialRebaseMsg(sqlCtx *sql.Context, queryist cli.Queryist, rebaseBra
 		}
 		commitHash := row[1].(string)
 		commitMessage := row[2].(string)
-		buffer.WriteString(fmt.Sprintf("%s %s %s\n", action, commitHash, commitMessage))
+		switch action {
+		case rebase.RebaseActionExec:
+			buffer.WriteString(fmt.Sprintf("%s %s\n", action, commitMessage))
+		case rebase.RebaseActionBreak:
+			buffer.WriteString(fmt.Sprintf("%s\n", action))
+		default:
+			buffer.WriteString(fmt.Sprintf("%s %s %s\n", action, commitHash, commitMessage))
+		}
 	}
 	buffer.WriteString("\n")
 
@@ -270,8 +319,12 @@ func buildInitialRebaseMsg(sqlCtx *sql.Context, queryist cli.Queryist, rebaseBra
 	if err != nil {
 		return "", err
 	}
+	ontoBranchHash, err := getHashOf(queryist, sqlCtx, ontoBranch)
+	if err != nil {
+		return "", err
+	}
 	numSteps := len(rows)
-	buffer.WriteString(fmt.Sprintf("# Rebase %s..%s onto %s (%d commands)\n#\n", rebaseBranchHash, currentBranchHash, rebaseBranchHash, numSteps))
+	buffer.WriteString(fmt.Sprintf("# Rebase %s..%s onto %s (%d commands)\n#\n", rebaseBranchHash, currentBranchHash, ontoBranchHash, numSteps))
 
 	buffer.WriteString("# Commands:\n")
 	buffer.WriteString("# p, pick <commit> = use commit\n")
@@ -279,6 +332,10 @@ func buildInitialRebaseMsg(sqlCtx *sql.Context, queryist cli.Queryist, rebaseBra
 	buffer.WriteString("# r, reword <commit> = use commit, but edit the commit message\n")
 	buffer.WriteString("# s, squash <commit> = use commit, but meld into previous commit\n")
 	buffer.WriteString("# f, fixup <commit> = like \"squash\", but discard this commit's message\n")
+	buffer.WriteString("# e, edit <commit> = use commit, but stop for amending\n")
+	buffer.WriteString("# x, exec <sql> = run SQL statements, and stop the rebase if they fail\n")
+	buffer.WriteString("# b, break = stop here (continue rebase later with 'dolt rebase --continue')\n")
+	buffer.WriteString("# m, merge <commit> = recreate a merge commit by merging its original second parent\n")
 	buffer.WriteString("# These lines can be re-ordered; they are executed from top to bottom.\n")
 	buffer.WriteString("#\n")
 	buffer.WriteString("# If you remove a line here THAT COMMIT WILL BE LOST.\n")
@@ -307,6 +364,22 @@ func parseRebaseMessage(rebaseMsg string) (*rebase.RebasePlan, error) {
 	splitMsg := strings.Split(rebaseMsg, "\n")
 	for i, line := range splitMsg {
 		if !strings.HasPrefix(line, "#") && strings.TrimSpace(line) != "" {
+			// exec and break steps don't reference a commit
+			action, statement, _ := strings.Cut(strings.TrimSpace(line), " ")
+			switch action {
+			case rebase.RebaseActionExec:
+				plan.Steps = append(plan.Steps, rebase.RebasePlanStep{
+					Action:    action,
+					CommitMsg: strings.TrimSpace(statement),
+				})
+				continue
+			case rebase.RebaseActionBreak:
+				plan.Steps = append(plan.Steps, rebase.RebasePlanStep{
+					Action: action,
+				})
+				continue
+			}
+
 			rebaseStepParts := strings.SplitN(line, " ", 3)
 			if len(rebaseStepParts) != 3 {
 				return nil, fmt.Errorf("invalid line %d: %s", i, line)
@@ -331,7 +404,8 @@ func insertRebasePlanIntoDoltRebaseTable(plan *rebase.RebasePlan, sqlCtx *sql.Co
 	}
 
 	for i, step := range plan.Steps {
-		_, err := GetRowsForSql(queryist, sqlCtx, fmt.Sprintf("INSERT INTO dolt_rebase VALUES (%d, '%s', '%s', '%s')", i+1, step.Action, step.CommitHash, step.CommitMsg))
+		_, err := GetRowsForSql(queryist, sqlCtx, fmt.Sprintf("INSERT INTO dolt_rebase VALUES (%d, '%s', '%s', '%s')",
+			i+1, step.Action, step.CommitHash, strings.ReplaceAll(step.CommitMsg, "'", "''")))
 		if err != nil {
 			return err
 		}
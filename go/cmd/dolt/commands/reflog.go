@@ -38,6 +38,8 @@ or tag changed over time to reference different commits, particularly for inform
 The data from Dolt's reflog comes from [Dolt's journaling chunk store](https://www.dolthub.com/blog/2023-03-08-dolt-chunk-journal/). 
 This data is local to a Dolt database and never included when pushing, pulling, or cloning a Dolt database. This means when you clone a Dolt database, it will not have any reflog data until you perform operations that change what commit branches or tags reference.
 
+By default, the reflog only holds a limited number of the most recent changes, and garbage collection clears it. When the persistent reflog is enabled, by setting the {{.EmphasisLeft}}DOLT_PERSISTENT_REFLOG{{.EmphasisRight}} environment variable or the {{.EmphasisLeft}}reflog{{.EmphasisRight}} section of the sql-server config, every change is kept, and the reflog is saved in the database before garbage collection, so that it survives garbage collection and is included in backups. The sql-server config can also limit the age and number of entries kept in the persistent reflog.
+
 Dolt's reflog is similar to [Git's reflog](https://git-scm.com/docs/git-reflog), but there are a few differences:
 - The Dolt reflog currently only supports named references, such as branches and tags, and not any of Git's special refs (e.g. {{.EmphasisLeft}}HEAD{{.EmphasisRight}}, {{.EmphasisLeft}}FETCH-HEAD{{.EmphasisRight}}, {{.EmphasisLeft}}MERGE-HEAD{{.EmphasisRight}}).
 - The Dolt reflog can be queried for the log of references, even after a reference has been deleted. In Git, once a branch or tag is deleted, the reflog for that ref is also deleted and to find the last commit a branch or tag pointed to you have to use Git's special {{.EmphasisLeft}}HEAD{{.EmphasisRight}} reflog to find the commit, which can sometimes be challenging. Dolt makes this much easier by allowing you to see the history for a deleted ref so you can easily see the last commit a branch or tag pointed to before it was deleted.`,
@@ -101,6 +101,8 @@ func (cmd RemoteCmd) ArgParser() *argparser.ArgParser {
 
 	ap.SupportsString(dbfactory.OSSCredsFileParam, "", "file", "OSS credentials file")
 	ap.SupportsString(dbfactory.OSSCredsProfile, "", "profile", "OSS profile to use")
+
+	ap.SupportsString(dbfactory.EncryptionKeyFileParam, "", "file", "Key file used to encrypt a local file remote at rest. It is created if it does not exist.")
 	return ap
 }
 
@@ -218,6 +220,9 @@ func parseRemoteArgs(apr *argparser.ArgParseResults, scheme, remoteUrl string) (
 	default:
 		err = cli.VerifyNoAwsParams(apr)
 	}
+	if err == nil {
+		err = cli.AddEncryptionParams(scheme, apr, params)
+	}
 	if err != nil {
 		return nil, errhand.VerboseErrorFromError(err)
 	}
@@ -33,7 +33,7 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-const keyId = "573DA8C6366D04E35CDB1A44E09A0B208F666373"
+const keyId = "D734F7535E0B95F2A990F665E7940B58CC9ED591"
 
 func importKey(t *testing.T, ctx context.Context) {
 	err := gpg.ImportKey(ctx, "testdata/signed_commits/private.pgp")
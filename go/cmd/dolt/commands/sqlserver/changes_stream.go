@@ -0,0 +1,202 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/mysql_db"
+	"github.com/gocraft/dbr/v2"
+	"github.com/gocraft/dbr/v2/dialect"
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+)
+
+const changesStreamPath = "/changes"
+
+// changesStreamHandler serves the dolt_changes feed of a branch over HTTP, as a stream of newline-delimited JSON
+// objects, one per row change. Requests are authenticated with HTTP basic auth against the users of the server, and
+// the feed is read with the privileges of the user.
+//
+// The stream is requested with GET /changes?database=<db>&branch=<branch>[&from=<commit or cursor>][&follow=false].
+// Unless follow is false, the stream stays open after the current head of the branch, and new commits are sent as they
+// are made. A client which disconnects can resume the stream by passing the event_cursor of the last change it
+// received as from.
+type changesStreamHandler struct {
+	ctxFactory   func(context.Context) (*sql.Context, error)
+	query        func(*sql.Context, string) (sql.Schema, sql.RowIter, *sql.QueryFlags, error)
+	rawDb        *mysql_db.MySQLDb
+	pollInterval time.Duration
+	lgr          *logrus.Entry
+}
+
+var _ http.Handler = (*changesStreamHandler)(nil)
+
+func (h *changesStreamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, password, ok := r.BasicAuth()
+	if !ok {
+		w.Header().Set("WWW-Authenticate", `Basic realm="dolt"`)
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+	if err := commands.ValidatePasswordWithAuthResponse(h.rawDb, user, password); err != nil {
+		w.Header().Set("WWW-Authenticate", `Basic realm="dolt"`)
+		http.Error(w, "authentication failed", http.StatusUnauthorized)
+		return
+	}
+
+	params := r.URL.Query()
+	database, branch, from := params.Get("database"), params.Get("branch"), params.Get("from")
+	if database == "" || branch == "" {
+		http.Error(w, "database and branch parameters are required", http.StatusBadRequest)
+		return
+	}
+	follow := true
+	if f := params.Get("follow"); f != "" {
+		var err error
+		follow, err = strconv.ParseBool(f)
+		if err != nil {
+			http.Error(w, "invalid follow parameter: "+f, http.StatusBadRequest)
+			return
+		}
+	}
+
+	address, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		address = r.RemoteAddr
+	}
+
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+	started := false
+	start := func() {
+		if !started {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+	}
+	for {
+		n, cursor, err := h.writeChanges(r.Context(), enc, sql.Client{User: user, Address: address}, database, branch, from, start)
+		if err != nil {
+			if r.Context().Err() == nil {
+				h.lgr.Warnf("error streaming changes of branch %s of database %s: %v", branch, database, err)
+			}
+			if !started {
+				if errors.Is(err, doltdb.ErrBranchNotFound) {
+					http.Error(w, err.Error(), http.StatusNotFound)
+				} else {
+					http.Error(w, "error reading changes, see the server log for details", http.StatusInternalServerError)
+				}
+			}
+			return
+		}
+		start()
+		if n > 0 {
+			from = cursor
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		if !follow {
+			return
+		}
+
+		select {
+		case <-time.After(h.pollInterval):
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// writeChanges writes the changes of |branch| following |from| to |enc|, and returns the number of changes written and
+// the cursor of the last one. |start| is called before the first change is written.
+func (h *changesStreamHandler) writeChanges(ctx context.Context, enc *json.Encoder, client sql.Client, database, branch, from string, start func()) (int, string, error) {
+	sqlCtx, err := h.ctxFactory(ctx)
+	if err != nil {
+		return 0, "", err
+	}
+	sqlCtx.Session.SetClient(client)
+	sqlCtx.SetCurrentDatabase(database)
+
+	query := "select * from dolt_changes(?)"
+	args := []interface{}{branch}
+	if from != "" {
+		query = "select * from dolt_changes(?, ?)"
+		args = append(args, from)
+	}
+	query, err = dbr.InterpolateForDialect(query, args, dialect.MySQL)
+	if err != nil {
+		return 0, "", err
+	}
+
+	sch, iter, _, err := h.query(sqlCtx, query)
+	if err != nil {
+		return 0, "", err
+	}
+	defer iter.Close(sqlCtx)
+
+	// row iters are lazy, so the first row is read before the response is started to report errors
+	n, cursor := 0, ""
+	for {
+		row, err := iter.Next(sqlCtx)
+		if err == io.EOF {
+			return n, cursor, nil
+		}
+		if err != nil {
+			return n, cursor, err
+		}
+		if n == 0 {
+			start()
+		}
+
+		event := make(map[string]interface{}, len(sch))
+		for i, col := range sch {
+			switch v := row[i].(type) {
+			case sql.JSONWrapper:
+				event[col.Name], err = v.ToInterface()
+				if err != nil {
+					return n, cursor, err
+				}
+			case time.Time:
+				event[col.Name] = v.UTC().Format(time.RFC3339)
+			default:
+				event[col.Name] = v
+			}
+		}
+		if err = enc.Encode(event); err != nil {
+			return n, cursor, err
+		}
+		n++
+		cursor = fmt.Sprint(row[0])
+	}
+}
@@ -285,6 +285,26 @@ func (cfg *commandLineServerConfig) ClusterConfig() servercfg.ClusterConfig {
 	return nil
 }
 
+func (cfg *commandLineServerConfig) CIConfig() servercfg.CIConfig {
+	return nil
+}
+
+func (cfg *commandLineServerConfig) SignedCommitsConfig() servercfg.SignedCommitsConfig {
+	return nil
+}
+
+func (cfg *commandLineServerConfig) WebhooksConfig() []servercfg.WebhookConfig {
+	return nil
+}
+
+func (cfg *commandLineServerConfig) CDCConfig() servercfg.CDCConfig {
+	return nil
+}
+
+func (cfg *commandLineServerConfig) ReflogConfig() servercfg.ReflogConfig {
+	return nil
+}
+
 // PrivilegeFilePath returns the path to the file which contains all needed privilege information in the form of a
 // JSON string.
 func (cfg *commandLineServerConfig) PrivilegeFilePath() string {
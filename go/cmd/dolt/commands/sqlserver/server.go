@@ -44,15 +44,20 @@ import (
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
 	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
 	remotesapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/remotesapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/dolt_ci"
 	"github.com/dolthub/dolt/go/libraries/doltcore/remotesrv"
 	"github.com/dolthub/dolt/go/libraries/doltcore/servercfg"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/binlogreplication"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/citrigger"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cluster"
 	_ "github.com/dolthub/dolt/go/libraries/doltcore/sqle/dfunctions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/signedcommits"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/webhooks"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqlserver"
 	"github.com/dolthub/dolt/go/libraries/events"
 	"github.com/dolthub/dolt/go/libraries/utils/config"
@@ -64,6 +69,10 @@ const (
 	ApiSqleContextKey   = "__sqle_context__"
 )
 
+// reflogArchiveCheckInterval is how often the sql-server checks whether the persistent reflog of its databases is due
+// to be archived.
+const reflogArchiveCheckInterval = 30 * time.Second
+
 // sqlServerHeartbeatIntervalEnvVar is the duration between heartbeats sent to the remote server, used for testing
 const sqlServerHeartbeatIntervalEnvVar = "DOLT_SQL_SERVER_HEARTBEAT_INTERVAL"
 
@@ -352,6 +361,23 @@ func ConfigureServices(
 				binlogreplication.BinlogBranch = logBinBranch
 			}
 
+			_, logBinBranchesValue, ok := sql.SystemVariables.GetGlobal("log_bin_branches")
+			if !ok {
+				return fmt.Errorf("unable to load @@log_bin_branches system variable")
+			}
+			logBinBranches, ok := logBinBranchesValue.(string)
+			if !ok {
+				return fmt.Errorf("unexpected type for @@log_bin_branches system variable: %T", logBinBranchesValue)
+			}
+			binlogBranches, err := binlogreplication.ParseBinlogBranches(logBinBranches)
+			if err != nil {
+				// As with @@log_bin_branch, let the server start up so that the value can be corrected
+				logrus.Warnf("%s. Not enabling binlog replication; fix @@log_bin_branches value "+
+					"and restart Dolt (current value: %s)", err.Error(), logBinBranches)
+				return nil
+			}
+			binlogreplication.BinlogBranches = binlogBranches
+
 			if logBin == 1 {
 				logrus.Infof("Enabling binary logging for branch %s", logBinBranch)
 				binlogProducer, err := binlogreplication.NewBinlogProducer(dEnv.FS)
@@ -380,6 +406,130 @@ func ConfigureServices(
 	}
 	controller.Register(InitBinlogging)
 
+	// Run Dolt CI workflows when branches are updated, if configured
+	var ciTrigger *citrigger.Trigger
+	InitCIWorkflowTrigger := &svcs.AnonService{
+		InitF: func(ctx context.Context) error {
+			ciConfig := serverConfig.CIConfig()
+			if ciConfig == nil || !ciConfig.Enabled() {
+				return nil
+			}
+			dolt_ci.SetWorkflowRunHistorySize(ciConfig.MaxRunHistory())
+
+			required := make(map[string][]string)
+			for _, r := range ciConfig.RequiredWorkflows() {
+				required[r.Branch()] = append(required[r.Branch()], r.Workflows()...)
+			}
+
+			provider := sqlEngine.GetUnderlyingEngine().Analyzer.Catalog.DbProvider
+			doltProvider, ok := provider.(*sqle.DoltDatabaseProvider)
+			if !ok {
+				return fmt.Errorf("unexpected type of database provider: %T", provider)
+			}
+
+			ciTrigger = citrigger.NewTrigger(sqlEngine.NewDefaultContext, required, logrus.NewEntry(lgr))
+			return ciTrigger.Start(ctx, sqlEngine.GetUnderlyingEngine().BackgroundThreads, doltProvider)
+		},
+	}
+	controller.Register(InitCIWorkflowTrigger)
+
+	// Require signed commits on protected branches, if configured
+	var signedCommitsPolicy *signedcommits.Policy
+	InitSignedCommits := &svcs.AnonService{
+		InitF: func(context.Context) error {
+			signedCommitsConfig := serverConfig.SignedCommitsConfig()
+			if signedCommitsConfig == nil || len(signedCommitsConfig.Branches()) == 0 {
+				return nil
+			}
+
+			provider := sqlEngine.GetUnderlyingEngine().Analyzer.Catalog.DbProvider
+			doltProvider, ok := provider.(*sqle.DoltDatabaseProvider)
+			if !ok {
+				return fmt.Errorf("unexpected type of database provider: %T", provider)
+			}
+
+			signedCommitsPolicy = signedcommits.NewPolicy(signedCommitsConfig.Branches(), signedCommitsConfig.AllowedSigners())
+			doltProvider.AddCommitValidator(signedCommitsPolicy)
+			return nil
+		},
+	}
+	controller.Register(InitSignedCommits)
+
+	// Keep the reflog across garbage collection, if configured, and periodically archive the reflog of databases
+	// whose reflog is persistent
+	InitReflogPolicy := &svcs.AnonService{
+		InitF: func(context.Context) error {
+			provider := sqlEngine.GetUnderlyingEngine().Analyzer.Catalog.DbProvider
+			doltProvider, ok := provider.(*sqle.DoltDatabaseProvider)
+			if !ok {
+				return fmt.Errorf("unexpected type of database provider: %T", provider)
+			}
+
+			if reflogConfig := serverConfig.ReflogConfig(); reflogConfig != nil {
+				doltProvider.SetReflogPolicy(doltdb.ReflogPolicy{
+					Persistent: reflogConfig.Persistent(),
+					MaxAge:     time.Duration(reflogConfig.MaxAgeDays()) * 24 * time.Hour,
+					MaxEntries: reflogConfig.MaxEntries(),
+				})
+			}
+
+			return sqlEngine.GetUnderlyingEngine().BackgroundThreads.Add("reflog_archiver", func(ctx context.Context) {
+				ticker := time.NewTicker(reflogArchiveCheckInterval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						if err := doltProvider.ArchiveReflogs(ctx); err != nil {
+							lgr.Warnf("failed to archive reflog: %v", err)
+						}
+					}
+				}
+			})
+		},
+	}
+	controller.Register(InitReflogPolicy)
+
+	// Notify webhooks of branch updates, if configured
+	InitWebhooks := &svcs.AnonService{
+		InitF: func(ctx context.Context) error {
+			webhooksConfig := serverConfig.WebhooksConfig()
+			if len(webhooksConfig) == 0 {
+				return nil
+			}
+
+			provider := sqlEngine.GetUnderlyingEngine().Analyzer.Catalog.DbProvider
+			doltProvider, ok := provider.(*sqle.DoltDatabaseProvider)
+			if !ok {
+				return fmt.Errorf("unexpected type of database provider: %T", provider)
+			}
+
+			hooks := make([]webhooks.Webhook, len(webhooksConfig))
+			for i, cfg := range webhooksConfig {
+				hooks[i] = webhooks.Webhook{
+					Config: webhooks.Config{
+						URL:           cfg.URL(),
+						Secret:        cfg.Secret(),
+						MaxRetries:    cfg.MaxRetries(),
+						RetryInterval: time.Duration(cfg.RetryIntervalMillis()) * time.Millisecond,
+						Timeout:       time.Duration(cfg.TimeoutMillis()) * time.Millisecond,
+						QueueSize:     cfg.QueueSize(),
+					},
+					Databases: cfg.Databases(),
+				}
+				if len(cfg.Branches()) > 0 {
+					branches := branch_control.ParseBranchExpressions(cfg.Branches())
+					hooks[i].IncludeBranch = func(branch string) bool {
+						return branch_control.MatchesBranch(branches, branch)
+					}
+				}
+			}
+			return webhooks.Start(ctx, sqlEngine.GetUnderlyingEngine().BackgroundThreads, doltProvider, hooks, logrus.NewEntry(lgr))
+		},
+	}
+	controller.Register(InitWebhooks)
+
 	// Add superuser if specified user exists; add root superuser if no user specified and no existing privileges
 	InitSuperUser := &svcs.AnonService{
 		InitF: func(context.Context) error {
@@ -487,6 +637,68 @@ func ConfigureServices(
 	}
 	controller.Register(RunMetricsServer)
 
+	type CDCService struct {
+		state svcs.ServiceState
+		lis   net.Listener
+		srv   *http.Server
+	}
+
+	var cdcSrv CDCService
+
+	// Serve the change data capture stream, if configured
+	RunCDCServer := &svcs.AnonService{
+		InitF: func(context.Context) (err error) {
+			cdcConfig := serverConfig.CDCConfig()
+			if cdcConfig == nil {
+				return nil
+			}
+			cdcSrv.state.Swap(svcs.ServiceState_Init)
+
+			addr := net.JoinHostPort(cdcConfig.Host(), strconv.Itoa(cdcConfig.Port()))
+			if serverConf.TLSConfig == nil && serverConfig.RequireSecureTransport() {
+				return errors.New("require_secure_transport is enabled, but no tls certificate is configured for the cdc server")
+			}
+			cdcSrv.lis, err = net.Listen("tcp", addr)
+			if err != nil {
+				return err
+			}
+			// clients authenticate with the passwords of sql users, so the stream is served with the TLS config of
+			// the sql listener
+			if serverConf.TLSConfig != nil {
+				cdcSrv.lis = tls.NewListener(cdcSrv.lis, serverConf.TLSConfig)
+			}
+
+			mux := http.NewServeMux()
+			mux.Handle(changesStreamPath, &changesStreamHandler{
+				ctxFactory:   sqlEngine.NewDefaultContext,
+				query:        sqlEngine.Query,
+				rawDb:        sqlEngine.GetUnderlyingEngine().Analyzer.Catalog.MySQLDb,
+				pollInterval: time.Duration(cdcConfig.PollIntervalMillis()) * time.Millisecond,
+				lgr:          logrus.NewEntry(lgr),
+			})
+			cdcSrv.srv = &http.Server{
+				Addr:    addr,
+				Handler: mux,
+			}
+			return nil
+		},
+		RunF: func(context.Context) {
+			if cdcSrv.state.CompareAndSwap(svcs.ServiceState_Init, svcs.ServiceState_Run) {
+				_ = cdcSrv.srv.Serve(cdcSrv.lis)
+			}
+		},
+		StopF: func() error {
+			state := cdcSrv.state.Swap(svcs.ServiceState_Stopped)
+			if state == svcs.ServiceState_Run {
+				cdcSrv.srv.Close()
+			} else if state == svcs.ServiceState_Init {
+				cdcSrv.lis.Close()
+			}
+			return nil
+		},
+	}
+	controller.Register(RunCDCServer)
+
 	type RemoteSrvService struct {
 		state svcs.ServiceState
 		lis   remotesrv.Listeners
@@ -516,7 +728,14 @@ func ConfigureServices(
 				ConcurrencyControl: remotesapi.PushConcurrencyControl_PUSH_CONCURRENCY_CONTROL_ASSERT_WORKING_SET,
 			}
 			var err error
-			args.FS, args.DBCache, err = sqle.RemoteSrvFSAndDBCache(sqlEngine.NewDefaultContext, sqle.DoNotCreateUnknownDatabases)
+			pushHooks := []sqle.RemoteSrvPushHook{sqle.NewProtectedBranchesPushHook(pushUserContextFactory(sqlEngine.NewDefaultContext), logrus.NewEntry(lgr))}
+			if signedCommitsPolicy != nil {
+				pushHooks = append(pushHooks, sqle.NewSignedCommitsPushHook(sqlEngine.NewDefaultContext, signedCommitsPolicy))
+			}
+			if ciTrigger != nil {
+				pushHooks = append(pushHooks, ciTrigger)
+			}
+			args.FS, args.DBCache, err = sqle.RemoteSrvFSAndDBCache(sqlEngine.NewDefaultContext, sqle.DoNotCreateUnknownDatabases, pushHooks...)
 			if err != nil {
 				lgr.Errorf("error creating SQL engine context for remotesapi server: %v", err)
 				return err
@@ -852,6 +1071,21 @@ func (r *remotesapiAuth) ApiAuthenticate(ctx context.Context) (context.Context,
 	return updatedCtx, nil
 }
 
+// pushUserContextFactory returns a factory of SQL contexts from |ctxFactory| whose client is the user that
+// authenticated the remotesapi request of the given context, when there is one.
+func pushUserContextFactory(ctxFactory func(context.Context) (*sql.Context, error)) func(context.Context) (*sql.Context, error) {
+	return func(ctx context.Context) (*sql.Context, error) {
+		sqlCtx, err := ctxFactory(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if apiCtx, ok := ctx.Value(ApiSqleContextKey).(*sql.Context); ok {
+			sqlCtx.Session.SetClient(apiCtx.Session.Client())
+		}
+		return sqlCtx, nil
+	}
+}
+
 func (r *remotesapiAuth) ApiAuthorize(ctx context.Context, superUserRequired bool) (bool, error) {
 	sqlCtx, ok := ctx.Value(ApiSqleContextKey).(*sql.Context)
 	if !ok {
@@ -47,10 +47,12 @@ var tagDocs = cli.CommandDocumentationContent{
 
 The command's second form creates a new tag named {{.LessThan}}tagname{{.GreaterThan}} which points to the current {{.EmphasisLeft}}HEAD{{.EmphasisRight}}, or {{.LessThan}}ref{{.GreaterThan}} if given. Optionally, a tag message can be passed using the {{.EmphasisLeft}}-m{{.EmphasisRight}} option. 
 
+With {{.EmphasisLeft}}-s{{.EmphasisRight}} or {{.EmphasisLeft}}-u{{.EmphasisRight}}, the tag is signed with the key named by the {{.EmphasisLeft}}signingkey{{.EmphasisRight}} system variable or {{.LessThan}}key-id{{.GreaterThan}}. Signed tags can be checked with {{.EmphasisLeft}}dolt verify-tag{{.EmphasisRight}}.
+
 With a {{.EmphasisLeft}}-d{{.EmphasisRight}}, {{.LessThan}}tagname{{.GreaterThan}} will be deleted.`,
 	Synopsis: []string{
 		`[-v]`,
-		`[-m {{.LessThan}}message{{.GreaterThan}}] {{.LessThan}}tagname{{.GreaterThan}} [{{.LessThan}}ref{{.GreaterThan}}]`,
+		`[-m {{.LessThan}}message{{.GreaterThan}}] [-s | -u {{.LessThan}}key-id{{.GreaterThan}}] {{.LessThan}}tagname{{.GreaterThan}} [{{.LessThan}}ref{{.GreaterThan}}]`,
 		`-d {{.LessThan}}tagname{{.GreaterThan}}`,
 	},
 }
@@ -124,30 +126,26 @@ func createTag(queryist cli.Queryist, sqlCtx *sql.Context, apr *argparser.ArgPar
 	if len(apr.Args) > 1 {
 		startPoint = apr.Arg(1)
 	}
-	message, _ := apr.GetValue(cli.MessageArg)
-	author, _ := apr.GetValue(cli.AuthorParam)
+	args := []string{tagName, startPoint}
+	if message, ok := apr.GetValue(cli.MessageArg); ok && len(message) > 0 {
+		args = append(args, "-m", message)
+	}
+	if author, ok := apr.GetValue(cli.AuthorParam); ok && len(author) > 0 {
+		args = append(args, "--"+cli.AuthorParam, author)
+	}
+	if apr.Contains(cli.SignTagFlag) {
+		args = append(args, "--"+cli.SignTagFlag)
+	}
+	if keyPath, ok := apr.GetValue(cli.SigningKeyParam); ok {
+		args = append(args, "--"+cli.SigningKeyParam, keyPath)
+	}
 
-	var query string
-	var params []interface{}
-	if len(message) == 0 {
-		if len(author) == 0 {
-			query = "call dolt_tag(?, ?)"
-			params = []interface{}{tagName, startPoint}
-		} else {
-			query = "call dolt_tag(?, ?, '--author', ?)"
-			params = []interface{}{tagName, startPoint, author}
-		}
-	} else {
-		if len(author) == 0 {
-			query = "call dolt_tag('-m', ?, ?, ?)"
-			params = []interface{}{message, tagName, startPoint}
-		} else {
-			query = "call dolt_tag('-m', ?, ?, ?, '--author', ?)"
-			params = []interface{}{message, tagName, startPoint, author}
-		}
+	query, err := interpolateStoredProcedureCall("DOLT_TAG", args)
+	if err != nil {
+		return err
 	}
 
-	_, err := InterpolateAndRunQuery(queryist, sqlCtx, query, params...)
+	_, err = GetRowsForSql(queryist, sqlCtx, query)
 	if err != nil {
 		return fmt.Errorf("error: failed to create tag %s: %w", tagName, err)
 	}
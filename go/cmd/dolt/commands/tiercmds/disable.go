@@ -0,0 +1,88 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tiercmds
+
+import (
+	"context"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/store/nbs"
+)
+
+var disableDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Move the database's history back to local disk.",
+	LongDesc: `Disables tiered storage for the database. The table files and archives of the database's old generation are downloaded from the blobstore to local disk, and the local cache is removed. The files are not deleted from the blobstore.
+
+This command fails if the database is in use by another dolt process, such as a running sql-server.`,
+	Synopsis: []string{},
+}
+
+type DisableCmd struct{}
+
+// Name is returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd DisableCmd) Name() string {
+	return "disable"
+}
+
+// Description returns a description of the command
+func (cmd DisableCmd) Description() string {
+	return disableDocs.ShortDesc
+}
+
+func (cmd DisableCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(disableDocs, ap)
+}
+
+func (cmd DisableCmd) ArgParser() *argparser.ArgParser {
+	return argparser.NewArgParserWithMaxArgs(cmd.Name(), 0)
+}
+
+// Exec executes the command
+func (cmd DisableCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, disableDocs, ap))
+	cli.ParseArgsOrDie(ap, args, help)
+
+	dir, err := oldGenDir(dEnv)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	cfg, err := nbs.LoadTieredStorageConfig(dir)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	} else if cfg == nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: tiered storage is not enabled").Build(), usage)
+	}
+
+	bs, err := dbfactory.NewBlobstore(ctx, cfg.BlobstoreURL, blobstoreParams(*cfg))
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: failed to open blobstore %s", cfg.BlobstoreURL).AddCause(err).Build(), usage)
+	}
+	if err = closeDatabase(dEnv); err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: failed to disable tiered storage").AddCause(err).Build(), usage)
+	}
+	if err = lockedErr(nbs.RecallTableFiles(ctx, dir, bs)); err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: failed to disable tiered storage").AddCause(err).Build(), usage)
+	}
+
+	cli.Println("Tiered storage disabled. The database's history is stored on local disk")
+	return 0
+}
@@ -0,0 +1,188 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tiercmds
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/libraries/utils/earl"
+	"github.com/dolthub/dolt/go/store/nbs"
+)
+
+const (
+	cacheDirParam  = "cache-dir"
+	cacheSizeParam = "cache-size"
+)
+
+var awsParams = []string{dbfactory.AWSRegionParam, dbfactory.AWSCredsTypeParam, dbfactory.AWSCredsFileParam, dbfactory.AWSCredsProfile}
+var ossParams = []string{dbfactory.OSSCredsFileParam, dbfactory.OSSCredsProfile}
+
+var enableDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Move the database's history to a blobstore.",
+	LongDesc: `Enables tiered storage for the database. The table files and archives of the database's old generation, which holds the history that garbage collection has moved out of the working set, are uploaded to the blobstore at {{.LessThan}}url{{.GreaterThan}} and removed from local disk. Afterwards they are read from the blobstore through a local cache, which holds at most {{.EmphasisLeft}}--cache-size{{.EmphasisRight}} bytes. Recent data is always stored locally.
+
+Supported blobstore URLs are {{.EmphasisLeft}}gs://bucket/path{{.EmphasisRight}}, {{.EmphasisLeft}}s3://bucket/path{{.EmphasisRight}}, {{.EmphasisLeft}}oci://bucket/path{{.EmphasisRight}}, {{.EmphasisLeft}}oss://bucket/path{{.EmphasisRight}} and {{.EmphasisLeft}}localbs://path{{.EmphasisRight}}.
+
+This command fails if the database is in use by another dolt process, such as a running sql-server. Encryption at rest is not supported for databases with tiered storage.`,
+	Synopsis: []string{
+		"[--cache-dir {{.LessThan}}dir{{.GreaterThan}}] [--cache-size {{.LessThan}}size{{.GreaterThan}}] {{.LessThan}}url{{.GreaterThan}}",
+	},
+}
+
+type EnableCmd struct{}
+
+// Name is returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd EnableCmd) Name() string {
+	return "enable"
+}
+
+// Description returns a description of the command
+func (cmd EnableCmd) Description() string {
+	return enableDocs.ShortDesc
+}
+
+func (cmd EnableCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(enableDocs, ap)
+}
+
+func (cmd EnableCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 1)
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"url", "URL of the blobstore to store the database's history in."})
+	ap.SupportsString(cacheDirParam, "", "dir", "Directory of the local cache. Defaults to a directory within the database.")
+	ap.SupportsString(cacheSizeParam, "", "size", fmt.Sprintf("Maximum size of the local cache, e.g. 50GB. Defaults to %s.", humanize.IBytes(nbs.DefaultTieredCacheSize)))
+
+	ap.SupportsString(dbfactory.AWSRegionParam, "", "region", "Region of the s3 bucket.")
+	ap.SupportsValidatedString(dbfactory.AWSCredsTypeParam, "", "creds-type", "AWS credential type. Valid options are role, env, and file.", argparser.ValidatorFromStrList(dbfactory.AWSCredsTypeParam, dbfactory.AWSCredTypes))
+	ap.SupportsString(dbfactory.AWSCredsFileParam, "", "file", "AWS credentials file")
+	ap.SupportsString(dbfactory.AWSCredsProfile, "", "profile", "AWS profile to use")
+
+	ap.SupportsString(dbfactory.OSSCredsFileParam, "", "file", "OSS credentials file")
+	ap.SupportsString(dbfactory.OSSCredsProfile, "", "profile", "OSS profile to use")
+	return ap
+}
+
+// Exec executes the command
+func (cmd EnableCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, enableDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	if apr.NArg() != 1 {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: a blobstore url is required").SetPrintUsage().Build(), usage)
+	}
+
+	cfg, verr := configFromArgs(apr)
+	if verr != nil {
+		return commands.HandleVErrAndExitCode(verr, usage)
+	}
+
+	dir, err := oldGenDir(dEnv)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	existing, err := nbs.LoadTieredStorageConfig(dir)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	} else if existing != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: tiered storage is already enabled with blobstore %s", existing.BlobstoreURL).Build(), usage)
+	}
+
+	bs, err := dbfactory.NewBlobstore(ctx, cfg.BlobstoreURL, blobstoreParams(cfg))
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: failed to open blobstore %s", cfg.BlobstoreURL).AddCause(err).Build(), usage)
+	}
+	if err = closeDatabase(dEnv); err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: failed to enable tiered storage").AddCause(err).Build(), usage)
+	}
+	if err = lockedErr(nbs.OffloadTableFiles(ctx, dir, bs, cfg)); err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: failed to enable tiered storage").AddCause(err).Build(), usage)
+	}
+
+	cli.Printf("Tiered storage enabled. The database's history is stored in %s\n", cfg.BlobstoreURL)
+	return 0
+}
+
+// configFromArgs returns the tiered storage config for the arguments in |apr|.
+func configFromArgs(apr *argparser.ArgParseResults) (nbs.TieredStorageConfig, errhand.VerboseError) {
+	urlStr := apr.Arg(0)
+	urlObj, err := earl.Parse(urlStr)
+	if err != nil {
+		return nbs.TieredStorageConfig{}, errhand.BuildDError("error: invalid blobstore url '%s'", urlStr).AddCause(err).Build()
+	}
+	scheme := strings.ToLower(urlObj.Scheme)
+
+	if scheme == dbfactory.LocalBSScheme {
+		// the config is read from within the database, so local blobstore paths must not be relative
+		absPath, err := filepath.Abs(filepath.Join(urlObj.Host, urlObj.Path))
+		if err != nil {
+			return nbs.TieredStorageConfig{}, errhand.VerboseErrorFromError(err)
+		}
+		urlStr = dbfactory.LocalBSScheme + "://" + filepath.ToSlash(absPath)
+	}
+
+	cfg := nbs.TieredStorageConfig{BlobstoreURL: urlStr, Params: make(map[string]string)}
+	if verr := addParams(apr, cfg.Params, awsParams, scheme, dbfactory.S3Scheme); verr != nil {
+		return nbs.TieredStorageConfig{}, verr
+	}
+	if verr := addParams(apr, cfg.Params, ossParams, scheme, dbfactory.OSSScheme); verr != nil {
+		return nbs.TieredStorageConfig{}, verr
+	}
+
+	if cacheDir, ok := apr.GetValue(cacheDirParam); ok {
+		if cfg.CacheDir, err = filepath.Abs(cacheDir); err != nil {
+			return nbs.TieredStorageConfig{}, errhand.VerboseErrorFromError(err)
+		}
+	}
+	if cacheSize, ok := apr.GetValue(cacheSizeParam); ok {
+		if cfg.CacheSize, err = humanize.ParseBytes(cacheSize); err != nil || cfg.CacheSize == 0 {
+			return nbs.TieredStorageConfig{}, errhand.BuildDError("error: invalid cache size '%s'", cacheSize).Build()
+		}
+	}
+	return cfg, nil
+}
+
+// addParams adds the values in |apr| of the blobstore parameters |names| to |params|. The parameters are only valid
+// for blobstores with the scheme |validScheme|.
+func addParams(apr *argparser.ArgParseResults, params map[string]string, names []string, scheme, validScheme string) errhand.VerboseError {
+	for _, p := range names {
+		val, ok := apr.GetValue(p)
+		if !ok {
+			continue
+		} else if scheme != validScheme {
+			return errhand.BuildDError("error: %s param is only valid for %s blobstores", p, validScheme).Build()
+		}
+		if p == dbfactory.AWSCredsFileParam || p == dbfactory.OSSCredsFileParam {
+			absPath, err := filepath.Abs(val)
+			if err != nil {
+				return errhand.VerboseErrorFromError(err)
+			}
+			val = absPath
+		}
+		params[p] = val
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tiercmds
+
+import (
+	"context"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/store/nbs"
+)
+
+var statusDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Show the tiered storage status of the database.",
+	LongDesc:  `Prints the blobstore holding the database's history if tiered storage is enabled, along with the location, size and capacity of the local cache.`,
+	Synopsis:  []string{},
+}
+
+type StatusCmd struct{}
+
+// Name is returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd StatusCmd) Name() string {
+	return "status"
+}
+
+// Description returns a description of the command
+func (cmd StatusCmd) Description() string {
+	return statusDocs.ShortDesc
+}
+
+func (cmd StatusCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(statusDocs, ap)
+}
+
+func (cmd StatusCmd) ArgParser() *argparser.ArgParser {
+	return argparser.NewArgParserWithMaxArgs(cmd.Name(), 0)
+}
+
+// Exec executes the command
+func (cmd StatusCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, statusDocs, ap))
+	cli.ParseArgsOrDie(ap, args, help)
+
+	dir, err := oldGenDir(dEnv)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	cfg, err := nbs.LoadTieredStorageConfig(dir)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if cfg == nil {
+		cli.Println("Tiered storage is not enabled")
+		return 0
+	}
+
+	cacheDir := cfg.CacheDirFor(dir)
+	used, err := cacheUsage(cacheDir)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	cli.Printf("Tiered storage is enabled with blobstore %s\n", cfg.BlobstoreURL)
+	cli.Printf("\tcache:\t%s\n", cacheDir)
+	cli.Printf("\tcache size:\t%s of %s\n", humanize.IBytes(used), humanize.IBytes(cfg.MaxCacheSize()))
+	return 0
+}
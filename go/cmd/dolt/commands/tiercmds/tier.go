@@ -0,0 +1,93 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tiercmds
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/store/nbs"
+)
+
+var Commands = cli.NewSubCommandHandler("tier", "Commands for managing tiered storage of the database's history.", []cli.Command{
+	EnableCmd{},
+	DisableCmd{},
+	StatusCmd{},
+})
+
+// oldGenDir returns the absolute path of the directory holding the old generation of the database in |dEnv|, which
+// is the part of the database that is offloaded by tiered storage.
+func oldGenDir(dEnv *env.DoltEnv) (string, error) {
+	return dEnv.FS.Abs(filepath.Join(dbfactory.DoltDataDir, "oldgen"))
+}
+
+// closeDatabase closes the database of |dEnv|, releasing its lock so that the old generation can be offloaded or
+// recalled. It returns env.ErrDatabaseIsLocked if the database is in use by another dolt process, such as a running
+// sql-server.
+func closeDatabase(dEnv *env.DoltEnv) error {
+	if dEnv.DoltDB == nil {
+		return nil
+	} else if dEnv.IsAccessModeReadOnly() {
+		return env.ErrDatabaseIsLocked
+	}
+
+	if err := dEnv.DoltDB.Close(); err != nil {
+		return err
+	}
+	absPath, err := dEnv.FS.Abs(dbfactory.DoltDataDir)
+	if err != nil {
+		return err
+	}
+	return dbfactory.DeleteFromSingletonCache(filepath.ToSlash(absPath))
+}
+
+// lockedErr maps nbs.ErrDatabaseLocked to env.ErrDatabaseIsLocked, so that tier commands report a locked database the
+// same way other commands do.
+func lockedErr(err error) error {
+	if errors.Is(err, nbs.ErrDatabaseLocked) {
+		return env.ErrDatabaseIsLocked
+	}
+	return err
+}
+
+// blobstoreParams returns the creation parameters for the blobstore of |cfg|.
+func blobstoreParams(cfg nbs.TieredStorageConfig) map[string]interface{} {
+	params := make(map[string]interface{}, len(cfg.Params))
+	for k, v := range cfg.Params {
+		params[k] = v
+	}
+	return params
+}
+
+// cacheUsage returns the total size of the files in the block cache |dir|.
+func cacheUsage(dir string) (uint64, error) {
+	var size uint64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += uint64(info.Size())
+		return nil
+	})
+	return size, err
+}
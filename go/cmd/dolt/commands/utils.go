@@ -57,6 +57,7 @@ type CommitInfo struct {
 	localBranchNames  []string
 	remoteBranchNames []string
 	tagNames          []string
+	note              string
 }
 
 var fwtStageName = "fwt"
@@ -656,6 +657,7 @@ func printRefs(pager *outputpager.Pager, comm *CommitInfo, decoration string) {
 
 type commitInfoOptions struct {
 	showSignature bool
+	showNotes     bool
 }
 
 // getCommitInfo returns the commit info for the given ref.
@@ -669,20 +671,23 @@ func getCommitInfoWithOptions(queryist cli.Queryist, sqlCtx *sql.Context, ref st
 		return nil, fmt.Errorf("error getting hash of HEAD: %v", err)
 	}
 
-	var q string
+	logArgs := "?, '--parents', '--decorate=full'"
 	if opts.showSignature {
-		q, err = dbr.InterpolateForDialect("select * from dolt_log(?, '--parents', '--decorate=full', '--show-signature')", []interface{}{ref}, dialect.MySQL)
-		if err != nil {
-			return nil, fmt.Errorf("error interpolating query: %v", err)
-		}
-	} else {
-		q, err = dbr.InterpolateForDialect("select * from dolt_log(?, '--parents', '--decorate=full')", []interface{}{ref}, dialect.MySQL)
-		if err != nil {
-			return nil, fmt.Errorf("error interpolating query: %v", err)
-		}
+		logArgs += ", '--show-signature'"
+	}
+	if opts.showNotes {
+		logArgs += ", '--notes'"
+	}
+	q, err := dbr.InterpolateForDialect("select * from dolt_log("+logArgs+")", []interface{}{ref}, dialect.MySQL)
+	if err != nil {
+		return nil, fmt.Errorf("error interpolating query: %v", err)
 	}
 
-	rows, err := GetRowsForSql(queryist, sqlCtx, q)
+	sch, rowIter, _, err := queryist.Query(sqlCtx, q)
+	if err != nil {
+		return nil, fmt.Errorf("error getting logs for ref '%s': %v", ref, err)
+	}
+	rows, err := sql.RowIterToRows(sqlCtx, rowIter)
 	if err != nil {
 		return nil, fmt.Errorf("error getting logs for ref '%s': %v", ref, err)
 	}
@@ -705,9 +710,15 @@ func getCommitInfoWithOptions(queryist cli.Queryist, sqlCtx *sql.Context, ref st
 
 	isHead := commitHash == hashOfHead
 
+	// The optional columns of dolt_log depend on the flags given, so they are found by name
 	var signature string
-	if len(row) > 7 {
-		signature = row[7].(string)
+	if i := sch.IndexOfColName("signature"); opts.showSignature && i >= 0 && row[i] != nil {
+		signature = row[i].(string)
+	}
+
+	var note string
+	if i := sch.IndexOfColName("notes"); opts.showNotes && i >= 0 && row[i] != nil {
+		note = row[i].(string)
 	}
 
 	localBranchesForHash, err := getBranchesForHash(queryist, sqlCtx, commitHash, true)
@@ -738,6 +749,7 @@ func getCommitInfoWithOptions(queryist cli.Queryist, sqlCtx *sql.Context, ref st
 		localBranchNames:  localBranchesForHash,
 		remoteBranchNames: remoteBranchesForHash,
 		tagNames:          tagsForHash,
+		note:              note,
 	}
 
 	if parent != "" {
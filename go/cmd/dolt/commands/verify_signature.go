@@ -0,0 +1,155 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/libraries/utils/gpg"
+)
+
+const verifySignatureTrustDocs = `A signature is good if it was made by a key listed for the signer's email in the allowed signers file named by the {{.EmphasisLeft}}allowedsigners{{.EmphasisRight}} system variable, which can be set with {{.EmphasisLeft}}dolt config --global --add sqlserver.global.allowedsigners {{.LessThan}}path{{.GreaterThan}}{{.EmphasisRight}}. The file uses the format of git's {{.EmphasisLeft}}gpg.ssh.allowedSignersFile{{.EmphasisRight}}, with one key per line:
+
+	{{.LessThan}}principals{{.GreaterThan}} [namespaces="dolt"] {{.LessThan}}key type{{.GreaterThan}} {{.LessThan}}base64 key{{.GreaterThan}} [comment]
+
+OpenPGP public keys are listed with the key type {{.EmphasisLeft}}openpgp{{.EmphasisRight}}, followed by the base64 encoded output of {{.EmphasisLeft}}gpg --export {{.LessThan}}key id{{.GreaterThan}}{{.EmphasisRight}}.`
+
+var verifyCommitDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Check the signature of commits",
+	LongDesc: `Verifies the OpenPGP or SSH signature of each {{.LessThan}}commit{{.GreaterThan}} and prints the result. Exits with a non-zero status unless every signature is good.
+
+` + verifySignatureTrustDocs,
+	Synopsis: []string{
+		`{{.LessThan}}commit{{.GreaterThan}}...`,
+	},
+}
+
+var verifyTagDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Check the signature of tags",
+	LongDesc: `Verifies the OpenPGP or SSH signature of each {{.LessThan}}tag{{.GreaterThan}} and prints the result. Exits with a non-zero status unless every signature is good.
+
+` + verifySignatureTrustDocs,
+	Synopsis: []string{
+		`{{.LessThan}}tag{{.GreaterThan}}...`,
+	},
+}
+
+type VerifyCommitCmd struct{}
+
+var _ cli.Command = VerifyCommitCmd{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd VerifyCommitCmd) Name() string {
+	return "verify-commit"
+}
+
+// Description returns a description of the command
+func (cmd VerifyCommitCmd) Description() string {
+	return verifyCommitDocs.ShortDesc
+}
+
+func (cmd VerifyCommitCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(verifyCommitDocs, ap)
+}
+
+func (cmd VerifyCommitCmd) ArgParser() *argparser.ArgParser {
+	return cli.CreateVerifyCommitArgParser()
+}
+
+// Exec executes the command
+func (cmd VerifyCommitCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, verifyCommitDocs, ap))
+	return execVerifySignature(ctx, "DOLT_VERIFY_COMMIT", ap, args, cliCtx, help, usage)
+}
+
+type VerifyTagCmd struct{}
+
+var _ cli.Command = VerifyTagCmd{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd VerifyTagCmd) Name() string {
+	return "verify-tag"
+}
+
+// Description returns a description of the command
+func (cmd VerifyTagCmd) Description() string {
+	return verifyTagDocs.ShortDesc
+}
+
+func (cmd VerifyTagCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(verifyTagDocs, ap)
+}
+
+func (cmd VerifyTagCmd) ArgParser() *argparser.ArgParser {
+	return cli.CreateVerifyTagArgParser()
+}
+
+// Exec executes the command
+func (cmd VerifyTagCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, verifyTagDocs, ap))
+	return execVerifySignature(ctx, "DOLT_VERIFY_TAG", ap, args, cliCtx, help, usage)
+}
+
+// execVerifySignature calls the verify procedure |procName| and prints the verification of each commit or tag. It
+// returns a non-zero exit code if any signature isn't good.
+func execVerifySignature(ctx context.Context, procName string, ap *argparser.ArgParser, args []string, cliCtx cli.CliContext, help, usage cli.UsagePrinter) int {
+	apr := cli.ParseArgsOrDie(ap, args, help)
+	if apr.NArg() == 0 {
+		usage()
+		return 1
+	}
+
+	queryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if closeFunc != nil {
+		defer closeFunc()
+	}
+
+	query, err := interpolateStoredProcedureCall(procName, apr.Args)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+
+	rows, err := GetRowsForSql(queryist, sqlCtx, query)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+
+	exitCode := 0
+	for _, row := range rows {
+		name, status, message := fmt.Sprint(row[0]), fmt.Sprint(row[1]), fmt.Sprint(row[2])
+		if len(rows) > 1 {
+			cli.Println(name)
+		}
+		if status == string(gpg.SignatureStatusGood) {
+			cli.Println(message)
+		} else {
+			cli.PrintErrln(message)
+			exitCode = 1
+		}
+	}
+	return exitCode
+}
@@ -0,0 +1,217 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+var worktreeDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Manage multiple working directories of a repository",
+	LongDesc: `A worktree is a directory with its own checked out branch, which shares the database of the repository it was created from. Commits, branches and remotes are shared by all the worktrees of a repository, so several branches can be worked on side by side without cloning the repository. A branch can only be checked out in one worktree at a time.
+
+{{.EmphasisLeft}}add{{.EmphasisRight}}
+Creates a worktree at {{.LessThan}}path{{.GreaterThan}} with {{.LessThan}}branch{{.GreaterThan}} checked out. With {{.EmphasisLeft}}-b{{.EmphasisRight}}, a new branch is created at {{.LessThan}}start-point{{.GreaterThan}}, or at HEAD if it is omitted, and checked out. When neither is given, the branch named after the last element of {{.LessThan}}path{{.GreaterThan}} is checked out, and created at HEAD if it doesn't exist.
+
+{{.EmphasisLeft}}list{{.EmphasisRight}}
+Lists the worktrees of the repository, with the branch checked out in each of them.
+
+{{.EmphasisLeft}}remove{{.EmphasisRight}}
+Deletes the worktree at {{.LessThan}}path{{.GreaterThan}}, which must be run from another worktree. A worktree with uncommitted changes is only removed with {{.EmphasisLeft}}--force{{.EmphasisRight}}. Even then, the changes are not lost: they remain in the working set of the branch, and are seen the next time it is checked out.`,
+	Synopsis: []string{
+		`add {{.LessThan}}path{{.GreaterThan}} [{{.LessThan}}branch{{.GreaterThan}}]`,
+		`add -b {{.LessThan}}new-branch{{.GreaterThan}} {{.LessThan}}path{{.GreaterThan}} [{{.LessThan}}start-point{{.GreaterThan}}]`,
+		`list`,
+		`remove [-f] {{.LessThan}}path{{.GreaterThan}}`,
+	},
+}
+
+const (
+	addWorktreeId    = "add"
+	listWorktreeId   = "list"
+	removeWorktreeId = "remove"
+)
+
+type WorktreeCmd struct{}
+
+var _ cli.Command = WorktreeCmd{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd WorktreeCmd) Name() string {
+	return "worktree"
+}
+
+// Description returns a description of the command
+func (cmd WorktreeCmd) Description() string {
+	return worktreeDocs.ShortDesc
+}
+
+func (cmd WorktreeCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(worktreeDocs, ap)
+}
+
+func (cmd WorktreeCmd) ArgParser() *argparser.ArgParser {
+	return cli.CreateWorktreeArgParser()
+}
+
+// Exec executes the command
+func (cmd WorktreeCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, worktreeDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+	if apr.NArg() == 0 {
+		usage()
+		return 1
+	}
+
+	queryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if closeFunc != nil {
+		defer closeFunc()
+	}
+
+	var verr errhand.VerboseError
+	switch {
+	case apr.Arg(0) == addWorktreeId && apr.NArg() >= 2 && apr.NArg() <= 3:
+		verr = addWorktree(sqlCtx, queryist, dEnv, apr)
+	case apr.Arg(0) == listWorktreeId && apr.NArg() == 1:
+		verr = listWorktrees(sqlCtx, queryist, dEnv)
+	case apr.Arg(0) == removeWorktreeId && apr.NArg() == 2:
+		verr = removeWorktree(ctx, dEnv, apr.Arg(1), apr.Contains(cli.ForceFlag))
+	default:
+		verr = errhand.BuildDError("").SetPrintUsage().Build()
+	}
+
+	return HandleVErrAndExitCode(verr, usage)
+}
+
+func addWorktree(sqlCtx *sql.Context, queryist cli.Queryist, dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.VerboseError {
+	path := apr.Arg(1)
+
+	branches, err := getBranches(sqlCtx, queryist, false)
+	if err != nil {
+		return errhand.BuildDError("error: failed to read branches").AddCause(err).Build()
+	}
+	exists := func(name string) bool {
+		for _, b := range branches {
+			if b.name == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	var branch string
+	var branchArgs []string
+	if newBranch, ok := apr.GetValue(cli.CheckoutCreateBranch); ok {
+		branch = newBranch
+		branchArgs = append([]string{newBranch}, apr.Args[2:]...)
+	} else if apr.NArg() == 3 {
+		branch = apr.Arg(2)
+		if !exists(branch) {
+			return errhand.BuildDError("fatal: Branch '%s' not found.", branch).Build()
+		}
+	} else {
+		branch = filepath.Base(path)
+		if !exists(branch) {
+			branchArgs = []string{branch}
+		}
+	}
+
+	if err = dEnv.CheckBranchNotCheckedOut(ref.NewBranchRef(branch)); err != nil {
+		return errhand.BuildDError("fatal: %s", err.Error()).Build()
+	}
+
+	if branchArgs != nil {
+		query, err := interpolateStoredProcedureCall("DOLT_BRANCH", branchArgs)
+		if err != nil {
+			return errhand.VerboseErrorFromError(err)
+		}
+		if _, err = GetRowsForSql(queryist, sqlCtx, query); err != nil {
+			return errhand.BuildDError("error: failed to create branch '%s'", branch).AddCause(err).Build()
+		}
+	}
+
+	absPath, err := dEnv.AddWorktree(path, ref.NewBranchRef(branch))
+	if err == env.ErrWorktreeExists {
+		return errhand.BuildDError("fatal: '%s' already exists", path).Build()
+	} else if err != nil {
+		return errhand.BuildDError("fatal: failed to create worktree at '%s'", path).AddCause(err).Build()
+	}
+
+	cli.Printf("Created worktree at '%s' with branch '%s' checked out\n", absPath, branch)
+	return nil
+}
+
+func listWorktrees(sqlCtx *sql.Context, queryist cli.Queryist, dEnv *env.DoltEnv) errhand.VerboseError {
+	worktrees, err := dEnv.Worktrees()
+	if err != nil {
+		return errhand.BuildDError("error: failed to read worktrees").AddCause(err).Build()
+	}
+
+	branches, err := getBranches(sqlCtx, queryist, false)
+	if err != nil {
+		return errhand.BuildDError("error: failed to read branches").AddCause(err).Build()
+	}
+	hashes := make(map[string]string, len(branches))
+	for _, b := range branches {
+		hashes[b.name] = b.hash
+	}
+
+	width := 0
+	for _, wt := range worktrees {
+		width = max(width, len(wt.Path))
+	}
+	for _, wt := range worktrees {
+		if wt.Head == nil {
+			cli.Printf("%-*s (missing)\n", width, wt.Path)
+			continue
+		}
+		branch := wt.Head.GetPath()
+		cli.Printf("%-*s %s [%s]\n", width, wt.Path, hashes[branch], branch)
+	}
+
+	return nil
+}
+
+func removeWorktree(ctx context.Context, dEnv *env.DoltEnv, path string, force bool) errhand.VerboseError {
+	err := dEnv.RemoveWorktree(ctx, path, force)
+	switch err {
+	case nil:
+		return nil
+	case env.ErrWorktreeNotFound:
+		return errhand.BuildDError("fatal: '%s' is not a worktree of this repository", path).Build()
+	case env.ErrRemoveMainWorktree:
+		return errhand.BuildDError("fatal: '%s' is the main worktree of the repository", path).Build()
+	case env.ErrRemoveCurrentWorktree:
+		return errhand.BuildDError("fatal: '%s' is the current worktree", path).Build()
+	case env.ErrWorktreeDirty:
+		return errhand.BuildDError("fatal: '%s' has uncommitted changes, use --force to remove it anyway", path).Build()
+	default:
+		return errhand.BuildDError("fatal: failed to remove worktree at '%s'", path).AddCause(err).Build()
+	}
+}
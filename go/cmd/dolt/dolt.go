@@ -48,11 +48,13 @@ import (
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/credcmds"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/cvcmds"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/docscmds"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/encryptioncmds"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/indexcmds"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/schcmds"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/sqlserver"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/stashcmds"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/tblcmds"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/tiercmds"
 	"github.com/dolthub/dolt/go/cmd/dolt/doltversion"
 	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
 	"github.com/dolthub/dolt/go/libraries/doltcore/dconfig"
@@ -103,6 +105,7 @@ var doltSubCommands = []cli.Command{
 	schcmds.Commands,
 	tblcmds.Commands,
 	commands.TagCmd{},
+	commands.NotesCmd{},
 	commands.BlameCmd{},
 	cvcmds.Commands,
 	commands.SendMetricsCmd{},
@@ -126,7 +129,13 @@ var doltSubCommands = []cli.Command{
 	commands.QueryDiff{},
 	commands.ReflogCmd{},
 	commands.RebaseCmd{},
+	commands.BisectCmd{},
+	commands.WorktreeCmd{},
+	commands.VerifyCommitCmd{},
+	commands.VerifyTagCmd{},
 	commands.ArchiveCmd{},
+	encryptioncmds.Commands,
+	tiercmds.Commands,
 	ci.Commands,
 }
 
@@ -155,6 +164,8 @@ var commandsWithoutCliCtx = []cli.Command{
 	commands.ProfileCmd{},
 	commands.ArchiveCmd{},
 	commands.FsckCmd{},
+	encryptioncmds.Commands,
+	tiercmds.Commands,
 }
 
 var commandsWithoutGlobalArgSupport = []cli.Command{
@@ -84,7 +84,39 @@ func (rcv *BranchControl) TryNamespaceTbl(obj *BranchControlNamespace) (*BranchC
 	return nil, nil
 }
 
-const BranchControlNumFields = 2
+func (rcv *BranchControl) TryProtectionTbl(obj *BranchControlProtection) (*BranchControlProtection, error) {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
+	if o != 0 {
+		x := rcv._tab.Indirect(o + rcv._tab.Pos)
+		if obj == nil {
+			obj = new(BranchControlProtection)
+		}
+		obj.Init(rcv._tab.Bytes, x)
+		if BranchControlProtectionNumFields < obj.Table().NumFields() {
+			return nil, flatbuffers.ErrTableHasUnknownFields
+		}
+		return obj, nil
+	}
+	return nil, nil
+}
+
+func (rcv *BranchControl) TryApprovalsTbl(obj *BranchControlApprovals) (*BranchControlApprovals, error) {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(10))
+	if o != 0 {
+		x := rcv._tab.Indirect(o + rcv._tab.Pos)
+		if obj == nil {
+			obj = new(BranchControlApprovals)
+		}
+		obj.Init(rcv._tab.Bytes, x)
+		if BranchControlApprovalsNumFields < obj.Table().NumFields() {
+			return nil, flatbuffers.ErrTableHasUnknownFields
+		}
+		return obj, nil
+	}
+	return nil, nil
+}
+
+const BranchControlNumFields = 4
 
 func BranchControlStart(builder *flatbuffers.Builder) {
 	builder.StartObject(BranchControlNumFields)
@@ -95,6 +127,12 @@ func BranchControlAddAccessTbl(builder *flatbuffers.Builder, accessTbl flatbuffe
 func BranchControlAddNamespaceTbl(builder *flatbuffers.Builder, namespaceTbl flatbuffers.UOffsetT) {
 	builder.PrependUOffsetTSlot(1, flatbuffers.UOffsetT(namespaceTbl), 0)
 }
+func BranchControlAddProtectionTbl(builder *flatbuffers.Builder, protectionTbl flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(2, flatbuffers.UOffsetT(protectionTbl), 0)
+}
+func BranchControlAddApprovalsTbl(builder *flatbuffers.Builder, approvalsTbl flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(3, flatbuffers.UOffsetT(approvalsTbl), 0)
+}
 func BranchControlEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
@@ -694,6 +732,423 @@ func BranchControlNamespaceValueEnd(builder *flatbuffers.Builder) flatbuffers.UO
 	return builder.EndObject()
 }
 
+type BranchControlProtection struct {
+	_tab flatbuffers.Table
+}
+
+func InitBranchControlProtectionRoot(o *BranchControlProtection, buf []byte, offset flatbuffers.UOffsetT) error {
+	n := flatbuffers.GetUOffsetT(buf[offset:])
+	return o.Init(buf, n+offset)
+}
+
+func TryGetRootAsBranchControlProtection(buf []byte, offset flatbuffers.UOffsetT) (*BranchControlProtection, error) {
+	x := &BranchControlProtection{}
+	return x, InitBranchControlProtectionRoot(x, buf, offset)
+}
+
+func TryGetSizePrefixedRootAsBranchControlProtection(buf []byte, offset flatbuffers.UOffsetT) (*BranchControlProtection, error) {
+	x := &BranchControlProtection{}
+	return x, InitBranchControlProtectionRoot(x, buf, offset+flatbuffers.SizeUint32)
+}
+
+func (rcv *BranchControlProtection) Init(buf []byte, i flatbuffers.UOffsetT) error {
+	rcv._tab.Bytes = buf
+	rcv._tab.Pos = i
+	if BranchControlProtectionNumFields < rcv.Table().NumFields() {
+		return flatbuffers.ErrTableHasUnknownFields
+	}
+	return nil
+}
+
+func (rcv *BranchControlProtection) Table() flatbuffers.Table {
+	return rcv._tab
+}
+
+func (rcv *BranchControlProtection) TryValues(obj *BranchControlProtectionValue, j int) (bool, error) {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		x := rcv._tab.Vector(o)
+		x += flatbuffers.UOffsetT(j) * 4
+		x = rcv._tab.Indirect(x)
+		obj.Init(rcv._tab.Bytes, x)
+		if BranchControlProtectionValueNumFields < obj.Table().NumFields() {
+			return false, flatbuffers.ErrTableHasUnknownFields
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func (rcv *BranchControlProtection) ValuesLength() int {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		return rcv._tab.VectorLen(o)
+	}
+	return 0
+}
+
+const BranchControlProtectionNumFields = 1
+
+func BranchControlProtectionStart(builder *flatbuffers.Builder) {
+	builder.StartObject(BranchControlProtectionNumFields)
+}
+func BranchControlProtectionAddValues(builder *flatbuffers.Builder, values flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(0, flatbuffers.UOffsetT(values), 0)
+}
+func BranchControlProtectionStartValuesVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
+	return builder.StartVector(4, numElems, 4)
+}
+func BranchControlProtectionEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
+	return builder.EndObject()
+}
+
+type BranchControlProtectionValue struct {
+	_tab flatbuffers.Table
+}
+
+func InitBranchControlProtectionValueRoot(o *BranchControlProtectionValue, buf []byte, offset flatbuffers.UOffsetT) error {
+	n := flatbuffers.GetUOffsetT(buf[offset:])
+	return o.Init(buf, n+offset)
+}
+
+func TryGetRootAsBranchControlProtectionValue(buf []byte, offset flatbuffers.UOffsetT) (*BranchControlProtectionValue, error) {
+	x := &BranchControlProtectionValue{}
+	return x, InitBranchControlProtectionValueRoot(x, buf, offset)
+}
+
+func TryGetSizePrefixedRootAsBranchControlProtectionValue(buf []byte, offset flatbuffers.UOffsetT) (*BranchControlProtectionValue, error) {
+	x := &BranchControlProtectionValue{}
+	return x, InitBranchControlProtectionValueRoot(x, buf, offset+flatbuffers.SizeUint32)
+}
+
+func (rcv *BranchControlProtectionValue) Init(buf []byte, i flatbuffers.UOffsetT) error {
+	rcv._tab.Bytes = buf
+	rcv._tab.Pos = i
+	if BranchControlProtectionValueNumFields < rcv.Table().NumFields() {
+		return flatbuffers.ErrTableHasUnknownFields
+	}
+	return nil
+}
+
+func (rcv *BranchControlProtectionValue) Table() flatbuffers.Table {
+	return rcv._tab
+}
+
+func (rcv *BranchControlProtectionValue) Database() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *BranchControlProtectionValue) Branch() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *BranchControlProtectionValue) RequiredApprovals() uint32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
+	if o != 0 {
+		return rcv._tab.GetUint32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *BranchControlProtectionValue) MutateRequiredApprovals(n uint32) bool {
+	return rcv._tab.MutateUint32Slot(8, n)
+}
+
+func (rcv *BranchControlProtectionValue) RequiredWorkflow() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(10))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+const BranchControlProtectionValueNumFields = 4
+
+func BranchControlProtectionValueStart(builder *flatbuffers.Builder) {
+	builder.StartObject(BranchControlProtectionValueNumFields)
+}
+func BranchControlProtectionValueAddDatabase(builder *flatbuffers.Builder, database flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(0, flatbuffers.UOffsetT(database), 0)
+}
+func BranchControlProtectionValueAddBranch(builder *flatbuffers.Builder, branch flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(1, flatbuffers.UOffsetT(branch), 0)
+}
+func BranchControlProtectionValueAddRequiredApprovals(builder *flatbuffers.Builder, requiredApprovals uint32) {
+	builder.PrependUint32Slot(2, requiredApprovals, 0)
+}
+func BranchControlProtectionValueAddRequiredWorkflow(builder *flatbuffers.Builder, requiredWorkflow flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(3, flatbuffers.UOffsetT(requiredWorkflow), 0)
+}
+func BranchControlProtectionValueEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
+	return builder.EndObject()
+}
+
+type BranchControlApprovals struct {
+	_tab flatbuffers.Table
+}
+
+func InitBranchControlApprovalsRoot(o *BranchControlApprovals, buf []byte, offset flatbuffers.UOffsetT) error {
+	n := flatbuffers.GetUOffsetT(buf[offset:])
+	return o.Init(buf, n+offset)
+}
+
+func TryGetRootAsBranchControlApprovals(buf []byte, offset flatbuffers.UOffsetT) (*BranchControlApprovals, error) {
+	x := &BranchControlApprovals{}
+	return x, InitBranchControlApprovalsRoot(x, buf, offset)
+}
+
+func TryGetSizePrefixedRootAsBranchControlApprovals(buf []byte, offset flatbuffers.UOffsetT) (*BranchControlApprovals, error) {
+	x := &BranchControlApprovals{}
+	return x, InitBranchControlApprovalsRoot(x, buf, offset+flatbuffers.SizeUint32)
+}
+
+func (rcv *BranchControlApprovals) Init(buf []byte, i flatbuffers.UOffsetT) error {
+	rcv._tab.Bytes = buf
+	rcv._tab.Pos = i
+	if BranchControlApprovalsNumFields < rcv.Table().NumFields() {
+		return flatbuffers.ErrTableHasUnknownFields
+	}
+	return nil
+}
+
+func (rcv *BranchControlApprovals) Table() flatbuffers.Table {
+	return rcv._tab
+}
+
+func (rcv *BranchControlApprovals) TryValues(obj *BranchControlApprovalValue, j int) (bool, error) {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		x := rcv._tab.Vector(o)
+		x += flatbuffers.UOffsetT(j) * 4
+		x = rcv._tab.Indirect(x)
+		obj.Init(rcv._tab.Bytes, x)
+		if BranchControlApprovalValueNumFields < obj.Table().NumFields() {
+			return false, flatbuffers.ErrTableHasUnknownFields
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func (rcv *BranchControlApprovals) ValuesLength() int {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		return rcv._tab.VectorLen(o)
+	}
+	return 0
+}
+
+func (rcv *BranchControlApprovals) TryAuthors(obj *BranchControlCommitAuthor, j int) (bool, error) {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		x := rcv._tab.Vector(o)
+		x += flatbuffers.UOffsetT(j) * 4
+		x = rcv._tab.Indirect(x)
+		obj.Init(rcv._tab.Bytes, x)
+		if BranchControlCommitAuthorNumFields < obj.Table().NumFields() {
+			return false, flatbuffers.ErrTableHasUnknownFields
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func (rcv *BranchControlApprovals) AuthorsLength() int {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		return rcv._tab.VectorLen(o)
+	}
+	return 0
+}
+
+const BranchControlApprovalsNumFields = 2
+
+func BranchControlApprovalsStart(builder *flatbuffers.Builder) {
+	builder.StartObject(BranchControlApprovalsNumFields)
+}
+func BranchControlApprovalsAddValues(builder *flatbuffers.Builder, values flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(0, flatbuffers.UOffsetT(values), 0)
+}
+func BranchControlApprovalsStartValuesVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
+	return builder.StartVector(4, numElems, 4)
+}
+func BranchControlApprovalsAddAuthors(builder *flatbuffers.Builder, authors flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(1, flatbuffers.UOffsetT(authors), 0)
+}
+func BranchControlApprovalsStartAuthorsVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
+	return builder.StartVector(4, numElems, 4)
+}
+func BranchControlApprovalsEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
+	return builder.EndObject()
+}
+
+type BranchControlApprovalValue struct {
+	_tab flatbuffers.Table
+}
+
+func InitBranchControlApprovalValueRoot(o *BranchControlApprovalValue, buf []byte, offset flatbuffers.UOffsetT) error {
+	n := flatbuffers.GetUOffsetT(buf[offset:])
+	return o.Init(buf, n+offset)
+}
+
+func TryGetRootAsBranchControlApprovalValue(buf []byte, offset flatbuffers.UOffsetT) (*BranchControlApprovalValue, error) {
+	x := &BranchControlApprovalValue{}
+	return x, InitBranchControlApprovalValueRoot(x, buf, offset)
+}
+
+func TryGetSizePrefixedRootAsBranchControlApprovalValue(buf []byte, offset flatbuffers.UOffsetT) (*BranchControlApprovalValue, error) {
+	x := &BranchControlApprovalValue{}
+	return x, InitBranchControlApprovalValueRoot(x, buf, offset+flatbuffers.SizeUint32)
+}
+
+func (rcv *BranchControlApprovalValue) Init(buf []byte, i flatbuffers.UOffsetT) error {
+	rcv._tab.Bytes = buf
+	rcv._tab.Pos = i
+	if BranchControlApprovalValueNumFields < rcv.Table().NumFields() {
+		return flatbuffers.ErrTableHasUnknownFields
+	}
+	return nil
+}
+
+func (rcv *BranchControlApprovalValue) Table() flatbuffers.Table {
+	return rcv._tab
+}
+
+func (rcv *BranchControlApprovalValue) Database() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *BranchControlApprovalValue) Branch() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *BranchControlApprovalValue) CommitHash() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *BranchControlApprovalValue) Approver() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(10))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+const BranchControlApprovalValueNumFields = 4
+
+func BranchControlApprovalValueStart(builder *flatbuffers.Builder) {
+	builder.StartObject(BranchControlApprovalValueNumFields)
+}
+func BranchControlApprovalValueAddDatabase(builder *flatbuffers.Builder, database flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(0, flatbuffers.UOffsetT(database), 0)
+}
+func BranchControlApprovalValueAddBranch(builder *flatbuffers.Builder, branch flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(1, flatbuffers.UOffsetT(branch), 0)
+}
+func BranchControlApprovalValueAddCommitHash(builder *flatbuffers.Builder, commitHash flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(2, flatbuffers.UOffsetT(commitHash), 0)
+}
+func BranchControlApprovalValueAddApprover(builder *flatbuffers.Builder, approver flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(3, flatbuffers.UOffsetT(approver), 0)
+}
+func BranchControlApprovalValueEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
+	return builder.EndObject()
+}
+
+type BranchControlCommitAuthor struct {
+	_tab flatbuffers.Table
+}
+
+func InitBranchControlCommitAuthorRoot(o *BranchControlCommitAuthor, buf []byte, offset flatbuffers.UOffsetT) error {
+	n := flatbuffers.GetUOffsetT(buf[offset:])
+	return o.Init(buf, n+offset)
+}
+
+func TryGetRootAsBranchControlCommitAuthor(buf []byte, offset flatbuffers.UOffsetT) (*BranchControlCommitAuthor, error) {
+	x := &BranchControlCommitAuthor{}
+	return x, InitBranchControlCommitAuthorRoot(x, buf, offset)
+}
+
+func TryGetSizePrefixedRootAsBranchControlCommitAuthor(buf []byte, offset flatbuffers.UOffsetT) (*BranchControlCommitAuthor, error) {
+	x := &BranchControlCommitAuthor{}
+	return x, InitBranchControlCommitAuthorRoot(x, buf, offset+flatbuffers.SizeUint32)
+}
+
+func (rcv *BranchControlCommitAuthor) Init(buf []byte, i flatbuffers.UOffsetT) error {
+	rcv._tab.Bytes = buf
+	rcv._tab.Pos = i
+	if BranchControlCommitAuthorNumFields < rcv.Table().NumFields() {
+		return flatbuffers.ErrTableHasUnknownFields
+	}
+	return nil
+}
+
+func (rcv *BranchControlCommitAuthor) Table() flatbuffers.Table {
+	return rcv._tab
+}
+
+func (rcv *BranchControlCommitAuthor) CommitHash() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *BranchControlCommitAuthor) User() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *BranchControlCommitAuthor) Host() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+const BranchControlCommitAuthorNumFields = 3
+
+func BranchControlCommitAuthorStart(builder *flatbuffers.Builder) {
+	builder.StartObject(BranchControlCommitAuthorNumFields)
+}
+func BranchControlCommitAuthorAddCommitHash(builder *flatbuffers.Builder, commitHash flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(0, flatbuffers.UOffsetT(commitHash), 0)
+}
+func BranchControlCommitAuthorAddUser(builder *flatbuffers.Builder, user flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(1, flatbuffers.UOffsetT(user), 0)
+}
+func BranchControlCommitAuthorAddHost(builder *flatbuffers.Builder, host flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(2, flatbuffers.UOffsetT(host), 0)
+}
+func BranchControlCommitAuthorEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
+	return builder.EndObject()
+}
+
 type BranchControlBinlog struct {
 	_tab flatbuffers.Table
 }
@@ -134,7 +134,15 @@ func (rcv *Tag) MutateUserTimestampMillis(n int64) bool {
 	return rcv._tab.MutateInt64Slot(14, n)
 }
 
-const TagNumFields = 6
+func (rcv *Tag) Signature() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(16))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+const TagNumFields = 7
 
 func TagStart(builder *flatbuffers.Builder) {
 	builder.StartObject(TagNumFields)
@@ -160,6 +168,9 @@ func TagAddTimestampMillis(builder *flatbuffers.Builder, timestampMillis uint64)
 func TagAddUserTimestampMillis(builder *flatbuffers.Builder, userTimestampMillis int64) {
 	builder.PrependInt64Slot(5, userTimestampMillis, 0)
 }
+func TagAddSignature(builder *flatbuffers.Builder, signature flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(6, flatbuffers.UOffsetT(signature), 0)
+}
 func TagEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
@@ -0,0 +1,185 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bisect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+var ErrNotStarted = errors.New("no bisect in progress, start one with: dolt bisect start")
+var ErrInProgress = errors.New("a bisect is already in progress, end it with: dolt bisect reset")
+
+// Result is the outcome of narrowing down a bisect after a commit is marked good, bad or skipped.
+type Result struct {
+	// FirstBad is the first bad commit, set once the bisect is complete.
+	FirstBad *doltdb.Commit
+	// Next is the commit that should be tested next.
+	Next *doltdb.Commit
+	// Remaining is roughly the number of commits left to test after Next.
+	Remaining int
+	// Steps is roughly the number of steps left after Next.
+	Steps int
+	// OnlySkipped is set when the only commits left to test have been skipped. Any of them could be the first bad
+	// commit.
+	OnlySkipped []hash.Hash
+}
+
+// Waiting returns whether the bisect still needs a good and a bad commit to be marked before it can narrow anything
+// down.
+func (r *Result) Waiting() bool {
+	return r.FirstBad == nil && r.Next == nil && len(r.OnlySkipped) == 0
+}
+
+// Next finds the next commit to test for the bisect |state|, or the first bad commit if it is known. The commits
+// considered are the ones reachable from the bad commit but not from any good commit, and the commit chosen to test
+// next is the one that splits them most evenly. |state| is updated with the commit to test next.
+func Next(ctx context.Context, ddb *doltdb.DoltDB, state *env.BisectState) (*Result, error) {
+	state.Current = ""
+	if state.Bad == "" || len(state.Good) == 0 {
+		return &Result{}, nil
+	}
+
+	bad, err := readCommit(ctx, ddb, state.Bad)
+	if err != nil {
+		return nil, err
+	}
+	badHash := hash.Parse(state.Bad)
+
+	goodHashes := make([]hash.Hash, len(state.Good))
+	for i, g := range state.Good {
+		good, err := readCommit(ctx, ddb, g)
+		if err != nil {
+			return nil, err
+		}
+		// the history between a good commit and the bad one can only be searched if the good commit is an ancestor
+		// of the bad one, which is the case when it is their merge base
+		mergeBase, err := doltdb.GetCommitAncestor(ctx, good, bad)
+		if err != nil {
+			return nil, err
+		}
+		if mergeBase.Addr.String() != g {
+			return nil, fmt.Errorf("good commit %s is not an ancestor of bad commit %s", g, state.Bad)
+		}
+		goodHashes[i] = hash.Parse(g)
+	}
+
+	commits, err := commitwalk.GetDotDotRevisions(ctx, ddb, []hash.Hash{badHash}, ddb, goodHashes, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	skipped := make(hash.HashSet, len(state.Skip))
+	for _, s := range state.Skip {
+		skipped.Insert(hash.Parse(s))
+	}
+
+	candidates := make(map[hash.Hash]*doltdb.Commit, len(commits))
+	var order []hash.Hash
+	for _, optCmt := range commits {
+		cm, ok := optCmt.ToCommit()
+		if !ok {
+			return nil, doltdb.ErrGhostCommitEncountered
+		}
+		h, err := cm.HashOf()
+		if err != nil {
+			return nil, err
+		}
+		candidates[h] = cm
+		order = append(order, h)
+	}
+
+	var testable []hash.Hash
+	var skippedCandidates []hash.Hash
+	for _, h := range order {
+		if h == badHash {
+			continue
+		}
+		if skipped.Has(h) {
+			skippedCandidates = append(skippedCandidates, h)
+		} else {
+			testable = append(testable, h)
+		}
+	}
+
+	if len(testable) == 0 {
+		if len(skippedCandidates) == 0 {
+			return &Result{FirstBad: bad}, nil
+		}
+		return &Result{OnlySkipped: append([]hash.Hash{badHash}, skippedCandidates...)}, nil
+	}
+
+	// pick the commit whose ancestors are closest to half of the candidates, so that marking it good or bad removes
+	// as many candidates as possible
+	var best hash.Hash
+	bestScore := -1
+	for _, h := range testable {
+		reach, err := countReachableCandidates(ctx, h, candidates)
+		if err != nil {
+			return nil, err
+		}
+		score := min(reach, len(candidates)-reach)
+		if score > bestScore {
+			best, bestScore = h, score
+		}
+	}
+
+	state.Current = best.String()
+	remaining := len(testable) / 2
+	steps := 0
+	for n := remaining; n > 0; n >>= 1 {
+		steps++
+	}
+	return &Result{Next: candidates[best], Remaining: remaining, Steps: steps}, nil
+}
+
+// countReachableCandidates returns the number of |candidates| that are reachable from |start|, including |start|.
+func countReachableCandidates(ctx context.Context, start hash.Hash, candidates map[hash.Hash]*doltdb.Commit) (int, error) {
+	seen := hash.NewHashSet(start)
+	pending := []hash.Hash{start}
+	for len(pending) > 0 {
+		h := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		parents, err := candidates[h].ParentHashes(ctx)
+		if err != nil {
+			return 0, err
+		}
+		for _, p := range parents {
+			if _, ok := candidates[p]; ok && !seen.Has(p) {
+				seen.Insert(p)
+				pending = append(pending, p)
+			}
+		}
+	}
+	return len(seen), nil
+}
+
+func readCommit(ctx context.Context, ddb *doltdb.DoltDB, h string) (*doltdb.Commit, error) {
+	optCmt, err := ddb.ReadCommit(ctx, hash.Parse(h))
+	if err != nil {
+		return nil, err
+	}
+	cm, ok := optCmt.ToCommit()
+	if !ok {
+		return nil, doltdb.ErrGhostCommitEncountered
+	}
+	return cm, nil
+}
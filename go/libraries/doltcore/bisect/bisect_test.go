@@ -0,0 +1,145 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bisect
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const (
+	testHomeDir = "/doesnotexist/home"
+	workingDir  = "/doesnotexist/work"
+)
+
+// createLinearHistory creates a database whose main branch has |n| commits after its initial commit, and returns
+// its commits from the oldest to the most recent.
+func createLinearHistory(t *testing.T, n int) (*doltdb.DoltDB, []string) {
+	ctx := context.Background()
+	fs := filesys.NewInMemFS([]string{testHomeDir, workingDir}, nil, workingDir)
+	dEnv := env.Load(ctx, func() (string, error) { return testHomeDir, nil }, fs, doltdb.InMemDoltDB, "test")
+	err := dEnv.InitRepo(ctx, types.Format_Default, "Bill Billerson", "bill@billerson.com", env.DefaultInitBranch)
+	require.NoError(t, err)
+
+	cs, err := doltdb.NewCommitSpec(env.DefaultInitBranch)
+	require.NoError(t, err)
+	opt, err := dEnv.DoltDB.Resolve(ctx, cs, nil)
+	require.NoError(t, err)
+	commit, ok := opt.ToCommit()
+	require.True(t, ok)
+	rv, err := commit.GetRootValue(ctx)
+	require.NoError(t, err)
+	_, rvh, err := dEnv.DoltDB.WriteRootValue(ctx, rv)
+	require.NoError(t, err)
+
+	h, err := commit.HashOf()
+	require.NoError(t, err)
+	commits := []string{h.String()}
+	ts := time.Now()
+	for i := 0; i < n; i++ {
+		ts = ts.Add(time.Second)
+		meta, err := datas.NewCommitMetaWithUserTS("Bill Billerson", "bill@billerson.com", "A New Commit.", ts)
+		require.NoError(t, err)
+		parent, err := doltdb.NewCommitSpec(commits[len(commits)-1])
+		require.NoError(t, err)
+		commit, err = dEnv.DoltDB.CommitWithParentSpecs(ctx, rvh, ref.NewBranchRef(env.DefaultInitBranch), []*doltdb.CommitSpec{parent}, meta)
+		require.NoError(t, err)
+		h, err = commit.HashOf()
+		require.NoError(t, err)
+		commits = append(commits, h.String())
+	}
+	return dEnv.DoltDB, commits
+}
+
+func resultHash(t *testing.T, cm *doltdb.Commit) string {
+	require.NotNil(t, cm)
+	h, err := cm.HashOf()
+	require.NoError(t, err)
+	return h.String()
+}
+
+func TestNext(t *testing.T) {
+	ctx := context.Background()
+	ddb, commits := createLinearHistory(t, 8)
+
+	t.Run("waits for good and bad commits", func(t *testing.T) {
+		state := &env.BisectState{Bad: commits[8]}
+		res, err := Next(ctx, ddb, state)
+		require.NoError(t, err)
+		assert.True(t, res.Waiting())
+		assert.Empty(t, state.Current)
+	})
+
+	t.Run("tests the midpoint", func(t *testing.T) {
+		state := &env.BisectState{Bad: commits[8], Good: []string{commits[0]}}
+		res, err := Next(ctx, ddb, state)
+		require.NoError(t, err)
+		assert.Equal(t, commits[4], resultHash(t, res.Next))
+		assert.Equal(t, commits[4], state.Current)
+		assert.Equal(t, 3, res.Remaining)
+		assert.Equal(t, 2, res.Steps)
+
+		// marking the midpoint good moves the next commit to the midpoint of the remaining range
+		state.Good = append(state.Good, commits[4])
+		res, err = Next(ctx, ddb, state)
+		require.NoError(t, err)
+		assert.Equal(t, commits[6], resultHash(t, res.Next))
+	})
+
+	t.Run("skips skipped commits", func(t *testing.T) {
+		state := &env.BisectState{Bad: commits[8], Good: []string{commits[0]}, Skip: []string{commits[4]}}
+		res, err := Next(ctx, ddb, state)
+		require.NoError(t, err)
+		next := resultHash(t, res.Next)
+		assert.NotEqual(t, commits[4], next)
+		assert.Contains(t, []string{commits[3], commits[5]}, next)
+	})
+
+	t.Run("single commit range", func(t *testing.T) {
+		state := &env.BisectState{Bad: commits[8], Good: []string{commits[7]}}
+		res, err := Next(ctx, ddb, state)
+		require.NoError(t, err)
+		assert.Nil(t, res.Next)
+		assert.Equal(t, commits[8], resultHash(t, res.FirstBad))
+		assert.Empty(t, state.Current)
+	})
+
+	t.Run("only skipped commits left", func(t *testing.T) {
+		state := &env.BisectState{Bad: commits[8], Good: []string{commits[6]}, Skip: []string{commits[7]}}
+		res, err := Next(ctx, ddb, state)
+		require.NoError(t, err)
+		assert.Nil(t, res.Next)
+		assert.Nil(t, res.FirstBad)
+		assert.ElementsMatch(t, []hash.Hash{hash.Parse(commits[8]), hash.Parse(commits[7])}, res.OnlySkipped)
+	})
+
+	t.Run("good commit must be an ancestor of the bad commit", func(t *testing.T) {
+		state := &env.BisectState{Bad: commits[4], Good: []string{commits[6]}}
+		_, err := Next(ctx, ddb, state)
+		require.Error(t, err)
+	})
+}
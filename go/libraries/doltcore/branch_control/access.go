@@ -32,6 +32,7 @@ const (
 	Permissions_Admin Permissions = 1 << iota // Permissions_Admin grants unrestricted control over a branch, including modification of table entries
 	Permissions_Write                         // Permissions_Write allows for all modifying operations on a branch, but does not allow modification of table entries
 	Permissions_Read                          // Permissions_Read allows for reading from a branch, which is equivalent to having no permissions
+	Permissions_Deny                          // Permissions_Deny hides a branch, preventing it from being read or referenced
 
 	Permissions_None Permissions = 0 // Permissions_None represents a lack of permissions, which defaults to allowing reading
 )
@@ -262,12 +263,15 @@ OuterLoop:
 
 // Consolidate reduces the permission set down to the most representative permission. For example, having both admin and
 // write permissions are equivalent to only having the admin permission. Additionally, having no permissions is
-// equivalent to only having the read permission.
+// equivalent to only having the read permission. The deny permission is only representative when it is the sole
+// permission, as any other permission on an equally long match grants access to the branch.
 func (perm Permissions) Consolidate() Permissions {
 	if perm&Permissions_Admin == Permissions_Admin {
 		return Permissions_Admin
 	} else if perm&Permissions_Write == Permissions_Write {
 		return Permissions_Write
+	} else if perm == Permissions_Deny {
+		return Permissions_Deny
 	} else {
 		return Permissions_Read
 	}
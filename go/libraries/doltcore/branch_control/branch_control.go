@@ -34,6 +34,8 @@ var (
 	ErrIncorrectPermissions  = errors.NewKind("`%s`@`%s` does not have the correct permissions on branch `%s`")
 	ErrCannotCreateBranch    = errors.NewKind("`%s`@`%s` cannot create a branch named `%s`")
 	ErrCannotDeleteBranch    = errors.NewKind("`%s`@`%s` cannot delete the branch `%s`")
+	ErrCannotReadBranch      = errors.NewKind("`%s`@`%s` cannot read the branch `%s`")
+	ErrCannotReadCommit      = errors.NewKind("`%s`@`%s` cannot read the commit `%s`")
 	ErrExpressionsTooLong    = errors.NewKind("expressions are too long [%q, %q, %q, %q]")
 	ErrInsertingAccessRow    = errors.NewKind("`%s`@`%s` cannot add the row [%q, %q, %q, %q, %q]")
 	ErrInsertingNamespaceRow = errors.NewKind("`%s`@`%s` cannot add the row [%q, %q, %q, %q]")
@@ -41,8 +43,19 @@ var (
 	ErrUpdatingToRow         = errors.NewKind("`%s`@`%s` cannot update the row [%q, %q, %q, %q] to the new branch expression [%q, %q]")
 	ErrDeletingRow           = errors.NewKind("`%s`@`%s` cannot delete the row [%q, %q, %q, %q]")
 	ErrMissingController     = errors.NewKind("a context has a non-nil session but is missing its branch controller")
+
+	ErrProtectedBranch         = errors.NewKind("branch `%s` is protected and may only be updated by merging an approved branch")
+	ErrProtectedBranchMerge    = errors.NewKind("cannot merge `%s` into protected branch `%s`: %s")
+	ErrInsertingProtectionRow  = errors.NewKind("`%s`@`%s` cannot add the row [%q, %q]")
+	ErrDeletingProtectionRow   = errors.NewKind("`%s`@`%s` cannot delete the row [%q, %q]")
+	ErrApprovingForAnotherUser = errors.NewKind("`%s`@`%s` cannot record an approval for `%s`")
+	ErrApprovingOwnCommit      = errors.NewKind("`%s` cannot approve commit %s of branch `%s`, as they are its author")
+	ErrApprovingStaleCommit    = errors.NewKind("cannot approve commit %s of branch `%s`, as the head of the branch is %s")
 )
 
+// protectedMergeKey is the context key of the protected branch that a context has been allowed to merge into.
+type protectedMergeKey struct{}
+
 // Context represents the interface that must be inherited from the context.
 type Context interface {
 	GetBranch() (string, error)
@@ -56,8 +69,10 @@ type Context interface {
 
 // Controller is the central hub for branch control functions. This is passed within a context.
 type Controller struct {
-	Access    *Access
-	Namespace *Namespace
+	Access     *Access
+	Namespace  *Namespace
+	Protection *Protection
+	Approvals  *Approvals
 
 	Serialized atomic.Pointer[[]byte]
 
@@ -87,6 +102,8 @@ func LoadData(ctx context.Context, branchControlFilePath string, doltConfigDirPa
 	controller := &Controller{
 		Access:                accessTbl,
 		Namespace:             newNamespace(accessTbl),
+		Protection:            newProtection(accessTbl),
+		Approvals:             newApprovals(accessTbl),
 		branchControlFilePath: branchControlFilePath,
 		doltConfigDirPath:     doltConfigDirPath,
 	}
@@ -140,6 +157,14 @@ func (controller *Controller) LoadData(ctx context.Context, data []byte, isFirst
 	if err != nil {
 		return err
 	}
+	protection, err := bc.TryProtectionTbl(nil)
+	if err != nil {
+		return err
+	}
+	approvals, err := bc.TryApprovalsTbl(nil)
+	if err != nil {
+		return err
+	}
 
 	rollback := controller.Serialized.Load()
 
@@ -157,6 +182,16 @@ func (controller *Controller) LoadData(ctx context.Context, data []byte, isFirst
 		controller.LoadData(ctx, *rollback, isFirstLoad)
 		return err
 	}
+	if err = controller.Protection.Deserialize(protection); err != nil {
+		// TODO: More principaled rollback. Hopefully this does not fail.
+		controller.LoadData(ctx, *rollback, isFirstLoad)
+		return err
+	}
+	if err = controller.Approvals.Deserialize(approvals); err != nil {
+		// TODO: More principaled rollback. Hopefully this does not fail.
+		controller.LoadData(ctx, *rollback, isFirstLoad)
+		return err
+	}
 
 	controller.Serialized.Store(&data)
 	if controller.SavedCallback != nil {
@@ -202,9 +237,13 @@ func (controller *Controller) SaveData(ctx context.Context, fs filesys.Filesys)
 	// The Serialize functions acquire read locks, so we don't acquire them here
 	accessOffset := controller.Access.Serialize(b)
 	namespaceOffset := controller.Namespace.Serialize(b)
+	protectionOffset := controller.Protection.Serialize(b)
+	approvalsOffset := controller.Approvals.Serialize(b)
 	serial.BranchControlStart(b)
 	serial.BranchControlAddAccessTbl(b, accessOffset)
 	serial.BranchControlAddNamespaceTbl(b, namespaceOffset)
+	serial.BranchControlAddProtectionTbl(b, protectionOffset)
+	serial.BranchControlAddApprovalsTbl(b, approvalsOffset)
 	root := serial.BranchControlEnd(b)
 	// serial.FinishMessage() limits files to 2^24 bytes, so this works around it while maintaining read compatibility
 	b.Prep(1, flatbuffers.SizeInt32+4+serial.MessagePrefixSz)
@@ -309,10 +348,193 @@ func CanDeleteBranch(ctx context.Context, branchName string) error {
 	// Get the permissions for the branch, user, and host combination
 	_, perms := controller.Access.Match(database, branchName, user, host)
 	// If the user has the write or admin flags, then we allow access
-	if (perms&Permissions_Write == Permissions_Write) || (perms&Permissions_Admin == Permissions_Admin) {
+	if (perms&Permissions_Write != Permissions_Write) && (perms&Permissions_Admin != Permissions_Admin) {
+		return ErrCannotDeleteBranch.New(user, host, branchName)
+	}
+	// Protected branches may not be deleted, nor may they be moved or renamed, which also delete the branch
+	if _, ok := controller.Protection.Match(database, branchName); ok {
+		return ErrProtectedBranch.New(branchName)
+	}
+	return nil
+}
+
+// CheckReadAccess returns whether the given context may read from the given branch. Branches are readable unless the
+// longest matching entries only hold the deny permission, and users with the database privileges required to modify
+// the branch control tables may always read every branch. An empty database uses the context's current database. As
+// with CheckAccess, contexts without a session (such as local CLI commands) are always allowed, as are sessions without
+// a controller.
+func CheckReadAccess(ctx context.Context, database string, branch string) error {
+	branchAwareSession := GetBranchAwareSession(ctx)
+	// A nil session means we're not in the SQL context, so we allow the read
+	if branchAwareSession == nil {
+		return nil
+	}
+	controller := branchAwareSession.GetController()
+	// Unlike writes, reads have never required a controller, so some internal sessions do not carry one
+	if controller == nil {
+		return nil
+	}
+	if len(database) == 0 {
+		database = branchAwareSession.GetCurrentDatabase()
+	}
+	database = getDatabaseNameOnly(database)
+	if HasDatabasePrivileges(branchAwareSession, database) {
+		return nil
+	}
+	controller.Access.RWMutex.RLock()
+	defer controller.Access.RWMutex.RUnlock()
+
+	user := branchAwareSession.GetUser()
+	host := branchAwareSession.GetHost()
+	_, perms := controller.Access.Match(database, branch, user, host)
+	if perms.Consolidate() == Permissions_Deny {
+		return ErrCannotReadBranch.New(user, host, branch)
+	}
+	return nil
+}
+
+// MayHideBranches returns whether any branch may be hidden from the given context by branch control, which is only
+// possible when an entry of the access table holds the deny permission. Callers use it to skip looking for hidden
+// branches.
+func MayHideBranches(ctx context.Context) bool {
+	branchAwareSession := GetBranchAwareSession(ctx)
+	if branchAwareSession == nil {
+		return false
+	}
+	controller := branchAwareSession.GetController()
+	if controller == nil {
+		return false
+	}
+	controller.Access.RWMutex.RLock()
+	defer controller.Access.RWMutex.RUnlock()
+
+	iter := controller.Access.Iter()
+	for row, ok := iter.Next(); ok; row, ok = iter.Next() {
+		if row.Permissions&Permissions_Deny == Permissions_Deny {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckProtection returns whether the given context may directly write to its selected branch, which it may not do if
+// the branch is protected. As with CheckAccess, contexts without a session (such as local CLI commands) are always
+// allowed.
+func CheckProtection(ctx context.Context) error {
+	branchAwareSession := GetBranchAwareSession(ctx)
+	if branchAwareSession == nil {
+		return nil
+	}
+	branch, err := branchAwareSession.GetBranch()
+	if err != nil {
+		return err
+	}
+	return CheckBranchProtection(ctx, branchAwareSession.GetCurrentDatabase(), branch)
+}
+
+// CheckBranchProtection returns whether the given context may directly write to the given branch. Writes to a
+// protected branch are only allowed from a context returned by AllowProtectedMerge for that branch.
+func CheckBranchProtection(ctx context.Context, database string, branch string) error {
+	branchAwareSession := GetBranchAwareSession(ctx)
+	// A nil session means we're not in the SQL context, so we allow the write
+	if branchAwareSession == nil {
+		return nil
+	}
+	controller := branchAwareSession.GetController()
+	if controller == nil {
+		return ErrMissingController.New()
+	}
+	database = getDatabaseNameOnly(database)
+	controller.Access.RWMutex.RLock()
+	_, ok := controller.Protection.Match(database, branch)
+	controller.Access.RWMutex.RUnlock()
+	if !ok {
+		return nil
+	}
+	if allowed, _ := ctx.Value(protectedMergeKey{}).(string); allowed == protectedMergeValue(database, branch) {
 		return nil
 	}
-	return ErrCannotDeleteBranch.New(user, host, branchName)
+	return ErrProtectedBranch.New(branch)
+}
+
+// GetProtection returns the rule protecting the given branch, and whether the branch is protected. Contexts without a
+// session or controller see no protected branches.
+func GetProtection(ctx context.Context, database string, branch string) (ProtectionValue, bool) {
+	branchAwareSession := GetBranchAwareSession(ctx)
+	if branchAwareSession == nil {
+		return ProtectionValue{}, false
+	}
+	controller := branchAwareSession.GetController()
+	if controller == nil {
+		return ProtectionValue{}, false
+	}
+	controller.Access.RWMutex.RLock()
+	defer controller.Access.RWMutex.RUnlock()
+	return controller.Protection.Match(getDatabaseNameOnly(database), branch)
+}
+
+// RecordsCommitAuthors returns whether RecordCommitAuthor records authors for the given context, which it only does
+// while protection rules exist.
+func RecordsCommitAuthors(ctx context.Context) bool {
+	branchAwareSession := GetBranchAwareSession(ctx)
+	if branchAwareSession == nil {
+		return false
+	}
+	controller := branchAwareSession.GetController()
+	if controller == nil {
+		return false
+	}
+	controller.Access.RWMutex.RLock()
+	defer controller.Access.RWMutex.RUnlock()
+	return len(controller.Protection.Values) > 0
+}
+
+// RecordCommitAuthor records the context's user as the author of the given commit, so that the user may not approve
+// the commit. Authors are only recorded while protection rules exist. Contexts without a session (such as local CLI
+// commands) record nothing. Recorded authors are kept in memory, and are written along with the next save of the
+// controller's data, or by SaveRecordedAuthors.
+func RecordCommitAuthor(ctx context.Context, commitHash string) error {
+	branchAwareSession := GetBranchAwareSession(ctx)
+	if branchAwareSession == nil {
+		return nil
+	}
+	controller := branchAwareSession.GetController()
+	if controller == nil {
+		return nil
+	}
+	controller.Access.RWMutex.Lock()
+	defer controller.Access.RWMutex.Unlock()
+	if len(controller.Protection.Values) == 0 {
+		return nil
+	}
+	controller.Approvals.RecordAuthor(CommitAuthorValue{
+		CommitHash: commitHash,
+		User:       branchAwareSession.GetUser(),
+		Host:       branchAwareSession.GetHost(),
+	})
+	return nil
+}
+
+// SaveRecordedAuthors saves the controller's data if commit authors have been recorded or forgotten since it was last
+// saved.
+func (controller *Controller) SaveRecordedAuthors(ctx context.Context, fs filesys.Filesys) error {
+	controller.Access.RWMutex.RLock()
+	changed := controller.Approvals.authorsChanged
+	controller.Access.RWMutex.RUnlock()
+	if !changed {
+		return nil
+	}
+	return controller.SaveData(ctx, fs)
+}
+
+// AllowProtectedMerge returns a context which may write to the given protected branch. This must only be used once
+// the merge that is about to update the branch has been checked against the branch's protection rule.
+func AllowProtectedMerge(ctx context.Context, database string, branch string) context.Context {
+	return context.WithValue(ctx, protectedMergeKey{}, protectedMergeValue(getDatabaseNameOnly(database), branch))
+}
+
+func protectedMergeValue(database string, branch string) string {
+	return strings.ToLower(database) + "/" + strings.ToLower(branch)
 }
 
 // AddAdminForContext adds an entry in the access table for the user represented by the given context. If the
@@ -16,6 +16,7 @@ package branch_control
 
 import (
 	"math"
+	"strings"
 	"sync"
 	"unicode/utf8"
 
@@ -212,6 +213,26 @@ func Match(matchExprCollection []MatchExpression, str string, collation sql.Coll
 	return validMatches
 }
 
+// ParseBranchExpressions parses each of the given branch expressions into a MatchExpression, which may be used with
+// MatchesBranch. Branch expressions use the same syntax as the branch column of the branch control tables.
+func ParseBranchExpressions(exprs []string) []MatchExpression {
+	matchExprs := make([]MatchExpression, len(exprs))
+	for i, expr := range exprs {
+		matchExprs[i] = MatchExpression{
+			CollectionIndex: uint32(i),
+			SortOrders:      ParseExpression(strings.ToLower(FoldExpression(expr)), sql.Collation_utf8mb4_0900_ai_ci),
+		}
+	}
+	return matchExprs
+}
+
+// MatchesBranch returns whether the given branch matches any of the expressions returned by ParseBranchExpressions.
+func MatchesBranch(matchExprCollection []MatchExpression, branch string) bool {
+	matches := Match(matchExprCollection, strings.ToLower(branch), sql.Collation_utf8mb4_0900_ai_ci)
+	defer indexPool.Put(matches)
+	return len(matches) > 0
+}
+
 // Matches returns true when the given sort order matches the expectation of the calling match expression. Returns a
 // reduced match expression as `next`, which should take the place of the calling match function. In the event of a
 // branch, returns the branching match expression as `extra`.
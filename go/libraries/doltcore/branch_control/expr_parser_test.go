@@ -166,3 +166,26 @@ func BenchmarkSimpleCase(b *testing.B) {
 	}
 	b.ReportAllocs()
 }
+
+func TestMatchesBranch(t *testing.T) {
+	exprs := ParseBranchExpressions([]string{"main", "release/%", `hotfix\_%`})
+	tests := []struct {
+		branch  string
+		matches bool
+	}{
+		{"main", true},
+		{"MAIN", true},
+		{"main2", false},
+		{"release/1.0", true},
+		{"release", false},
+		{"hotfix_1", true},
+		{"hotfix-1", false},
+		{"feature", false},
+	}
+	for _, test := range tests {
+		t.Run(test.branch, func(t *testing.T) {
+			require.Equal(t, test.matches, MatchesBranch(exprs, test.branch))
+		})
+	}
+	require.False(t, MatchesBranch(nil, "main"))
+}
@@ -0,0 +1,450 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	flatbuffers "github.com/dolthub/flatbuffers/v23/go"
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/gen/fb/serial"
+)
+
+// Protection contains all of the rules that comprise the "dolt_branch_protection" table, which declares the branches
+// that may not be written to directly, and may only be updated by merging a branch that has been approved. Modification
+// of this table is handled by the Access table.
+type Protection struct {
+	access *Access
+
+	Databases []MatchExpression
+	Branches  []MatchExpression
+	Values    []ProtectionValue
+	RWMutex   *sync.RWMutex
+}
+
+// ProtectionValue contains the user-facing values of a particular row.
+type ProtectionValue struct {
+	Database          string
+	Branch            string
+	RequiredApprovals uint32
+	// RequiredWorkflow is the name of a Dolt CI workflow that must pass against a branch before it is merged into the
+	// protected branch. Empty if no workflow is required.
+	RequiredWorkflow string
+}
+
+// Approvals contains all of the rows of the "dolt_approvals" table. Each row records that a user has approved the
+// commit at the head of a branch. An approval is only counted while the branch's head remains at the approved commit.
+// Approvals also records the authenticated user that created each commit while protection rules exist, as approvers
+// may not approve commits that they authored, and the author recorded within a commit may be set to anyone. Authors
+// are keyed by commit hash, and are forgotten once their commits have been merged into a protected branch or are no
+// longer reachable.
+type Approvals struct {
+	Values  []ApprovalValue
+	Authors map[string]CommitAuthorValue
+	RWMutex *sync.RWMutex
+
+	// authorsChanged is set when authors have been recorded or forgotten since the table was last serialized
+	authorsChanged bool
+}
+
+// ApprovalValue contains the user-facing values of a particular row.
+type ApprovalValue struct {
+	Database   string
+	Branch     string
+	CommitHash string
+	Approver   string
+}
+
+// CommitAuthorValue records the authenticated user that created a commit. As commits are addressed by their content,
+// the same commit has the same author in every database.
+type CommitAuthorValue struct {
+	CommitHash string
+	User       string
+	Host       string
+}
+
+// newProtection returns a new Protection.
+func newProtection(accessTbl *Access) *Protection {
+	return &Protection{
+		access:    accessTbl,
+		Databases: nil,
+		Branches:  nil,
+		Values:    nil,
+		RWMutex:   accessTbl.RWMutex,
+	}
+}
+
+// newApprovals returns a new Approvals.
+func newApprovals(accessTbl *Access) *Approvals {
+	return &Approvals{
+		Values:  nil,
+		Authors: make(map[string]CommitAuthorValue),
+		RWMutex: accessTbl.RWMutex,
+	}
+}
+
+// Match returns the rule protecting the given database and branch, and whether any rule matched. When multiple rules
+// match, the rule with the longest branch expression is used, and ties are resolved in favor of the rule requiring the
+// most approvals. Requires external synchronization handling, therefore manually manage the RWMutex.
+func (tbl *Protection) Match(database string, branch string) (ProtectionValue, bool) {
+	filteredIndexes := Match(tbl.Databases, database, sql.Collation_utf8mb4_0900_ai_ci)
+	if len(filteredIndexes) == 0 {
+		indexPool.Put(filteredIndexes)
+		return ProtectionValue{}, false
+	}
+	filteredBranches := tbl.filterBranches(filteredIndexes)
+	indexPool.Put(filteredIndexes)
+	matchedSet := Match(filteredBranches, branch, sql.Collation_utf8mb4_0900_ai_ci)
+	matchExprPool.Put(filteredBranches)
+	defer indexPool.Put(matchedSet)
+
+	found := false
+	var match ProtectionValue
+	for _, matched := range matchedSet {
+		value := tbl.Values[matched]
+		if !found || len(value.Branch) > len(match.Branch) ||
+			(len(value.Branch) == len(match.Branch) && value.RequiredApprovals > match.RequiredApprovals) {
+			match = value
+			found = true
+		}
+	}
+	return match, found
+}
+
+// GetIndex returns the index of the given database and branch expressions. If the expressions cannot be found, returns
+// -1. Assumes that the given expressions have already been folded.
+func (tbl *Protection) GetIndex(databaseExpr string, branchExpr string) int {
+	for i, value := range tbl.Values {
+		if value.Database == databaseExpr && value.Branch == branchExpr {
+			return i
+		}
+	}
+	return -1
+}
+
+// Access returns the Access table.
+func (tbl *Protection) Access() *Access {
+	return tbl.access
+}
+
+// Insert adds the given rule to the table, replacing any existing rule with the same database and branch expressions.
+// Assumes that the expressions have already been folded.
+func (tbl *Protection) Insert(value ProtectionValue) {
+	if tblIndex := tbl.GetIndex(value.Database, value.Branch); tblIndex != -1 {
+		tbl.Values[tblIndex] = value
+		return
+	}
+	nextIdx := uint32(len(tbl.Values))
+	tbl.Databases = append(tbl.Databases, MatchExpression{
+		CollectionIndex: nextIdx,
+		SortOrders:      ParseExpression(value.Database, sql.Collation_utf8mb4_0900_ai_ci),
+	})
+	tbl.Branches = append(tbl.Branches, MatchExpression{
+		CollectionIndex: nextIdx,
+		SortOrders:      ParseExpression(value.Branch, sql.Collation_utf8mb4_0900_ai_ci),
+	})
+	tbl.Values = append(tbl.Values, value)
+}
+
+// Delete removes the rule with the given database and branch expressions from the table. Assumes that the expressions
+// have already been folded.
+func (tbl *Protection) Delete(databaseExpr string, branchExpr string) {
+	tblIndex := tbl.GetIndex(databaseExpr, branchExpr)
+	if tblIndex == -1 {
+		return
+	}
+	endIndex := len(tbl.Values) - 1
+	// Remove the matching row from all slices by first swapping with the last element
+	tbl.Databases[tblIndex], tbl.Databases[endIndex] = tbl.Databases[endIndex], tbl.Databases[tblIndex]
+	tbl.Branches[tblIndex], tbl.Branches[endIndex] = tbl.Branches[endIndex], tbl.Branches[tblIndex]
+	tbl.Values[tblIndex], tbl.Values[endIndex] = tbl.Values[endIndex], tbl.Values[tblIndex]
+	tbl.Databases = tbl.Databases[:endIndex]
+	tbl.Branches = tbl.Branches[:endIndex]
+	tbl.Values = tbl.Values[:endIndex]
+	if tblIndex != endIndex {
+		tbl.Databases[tblIndex].CollectionIndex = uint32(tblIndex)
+		tbl.Branches[tblIndex].CollectionIndex = uint32(tblIndex)
+	}
+}
+
+// Serialize returns the offset for the Protection table written to the given builder.
+func (tbl *Protection) Serialize(b *flatbuffers.Builder) flatbuffers.UOffsetT {
+	valueOffsets := make([]flatbuffers.UOffsetT, len(tbl.Values))
+	for i, val := range tbl.Values {
+		valueOffsets[i] = val.Serialize(b)
+	}
+	serial.BranchControlProtectionStartValuesVector(b, len(valueOffsets))
+	for i := len(valueOffsets) - 1; i >= 0; i-- {
+		b.PrependUOffsetT(valueOffsets[i])
+	}
+	values := b.EndVector(len(valueOffsets))
+	serial.BranchControlProtectionStart(b)
+	serial.BranchControlProtectionAddValues(b, values)
+	return serial.BranchControlProtectionEnd(b)
+}
+
+// Deserialize populates the table with the data from the flatbuffers representation. A nil table, written before
+// branch protection existed, results in an empty table.
+func (tbl *Protection) Deserialize(fb *serial.BranchControlProtection) error {
+	tbl.Databases = nil
+	tbl.Branches = nil
+	tbl.Values = nil
+	if fb == nil {
+		return nil
+	}
+	for i := 0; i < fb.ValuesLength(); i++ {
+		serialValue := &serial.BranchControlProtectionValue{}
+		if _, err := fb.TryValues(serialValue, i); err != nil {
+			return err
+		}
+		tbl.Insert(ProtectionValue{
+			Database:          string(serialValue.Database()),
+			Branch:            string(serialValue.Branch()),
+			RequiredApprovals: serialValue.RequiredApprovals(),
+			RequiredWorkflow:  string(serialValue.RequiredWorkflow()),
+		})
+	}
+	return nil
+}
+
+// filterBranches returns all branches that match the given collection indexes.
+func (tbl *Protection) filterBranches(filters []uint32) []MatchExpression {
+	matchExprs := matchExprPool.Get().([]MatchExpression)[:0]
+	for _, filter := range filters {
+		matchExprs = append(matchExprs, tbl.Branches[filter])
+	}
+	return matchExprs
+}
+
+// Serialize returns the offset for the ProtectionValue written to the given builder.
+func (val *ProtectionValue) Serialize(b *flatbuffers.Builder) flatbuffers.UOffsetT {
+	database := b.CreateSharedString(val.Database)
+	branch := b.CreateSharedString(val.Branch)
+	requiredWorkflow := b.CreateSharedString(val.RequiredWorkflow)
+
+	serial.BranchControlProtectionValueStart(b)
+	serial.BranchControlProtectionValueAddDatabase(b, database)
+	serial.BranchControlProtectionValueAddBranch(b, branch)
+	serial.BranchControlProtectionValueAddRequiredApprovals(b, val.RequiredApprovals)
+	serial.BranchControlProtectionValueAddRequiredWorkflow(b, requiredWorkflow)
+	return serial.BranchControlProtectionValueEnd(b)
+}
+
+// Approvers returns the distinct users that have approved the given commit of the given branch, sorted by name. Database
+// and branch names are matched case-insensitively. Requires external synchronization handling, therefore manually
+// manage the RWMutex.
+func (tbl *Approvals) Approvers(database string, branch string, commitHash string) []string {
+	database = strings.ToLower(database)
+	branch = strings.ToLower(branch)
+	var approvers []string
+	for _, value := range tbl.Values {
+		if value.Database == database && value.Branch == branch && value.CommitHash == commitHash {
+			approvers = append(approvers, value.Approver)
+		}
+	}
+	sort.Strings(approvers)
+	return approvers
+}
+
+// CommitApprovers returns the distinct users that have approved the given commit on any branch of the given database,
+// sorted by name. Requires external synchronization handling, therefore manually manage the RWMutex.
+func (tbl *Approvals) CommitApprovers(database string, commitHash string) []string {
+	database = strings.ToLower(database)
+	seen := make(map[string]struct{})
+	var approvers []string
+	for _, value := range tbl.Values {
+		if value.Database != database || value.CommitHash != commitHash {
+			continue
+		}
+		if _, ok := seen[value.Approver]; !ok {
+			seen[value.Approver] = struct{}{}
+			approvers = append(approvers, value.Approver)
+		}
+	}
+	sort.Strings(approvers)
+	return approvers
+}
+
+// GetIndex returns the index of the approval of the given branch by the given approver. If the approval cannot be
+// found, returns -1. Assumes that the database and branch have already been lowercased.
+func (tbl *Approvals) GetIndex(database string, branch string, approver string) int {
+	for i, value := range tbl.Values {
+		if value.Database == database && value.Branch == branch && value.Approver == approver {
+			return i
+		}
+	}
+	return -1
+}
+
+// Insert records the given approval. As an approver may only approve a single commit of each branch, this replaces
+// any earlier approval of the branch by the same approver. Assumes that the database and branch have already been
+// lowercased.
+func (tbl *Approvals) Insert(value ApprovalValue) {
+	if tblIndex := tbl.GetIndex(value.Database, value.Branch, value.Approver); tblIndex != -1 {
+		tbl.Values[tblIndex] = value
+		return
+	}
+	tbl.Values = append(tbl.Values, value)
+}
+
+// Delete removes the approval of the given branch by the given approver. Assumes that the database and branch have
+// already been lowercased.
+func (tbl *Approvals) Delete(database string, branch string, approver string) {
+	tblIndex := tbl.GetIndex(database, branch, approver)
+	if tblIndex == -1 {
+		return
+	}
+	tbl.Values = append(tbl.Values[:tblIndex], tbl.Values[tblIndex+1:]...)
+}
+
+// RecordAuthor records the authenticated user that created the given commit, unless an author was already recorded.
+// Requires external synchronization handling, therefore manually manage the RWMutex.
+func (tbl *Approvals) RecordAuthor(value CommitAuthorValue) {
+	if _, ok := tbl.Authors[value.CommitHash]; ok {
+		return
+	}
+	if tbl.Authors == nil {
+		tbl.Authors = make(map[string]CommitAuthorValue)
+	}
+	tbl.Authors[value.CommitHash] = value
+	tbl.authorsChanged = true
+}
+
+// Author returns the authenticated user that created the given commit, and whether the author was recorded. Commits
+// that were created before any protection rule existed, or that were not created by a SQL session or pushed through
+// the remotesapi server, have no recorded author. Requires external synchronization handling, therefore manually
+// manage the RWMutex.
+func (tbl *Approvals) Author(commitHash string) (CommitAuthorValue, bool) {
+	value, ok := tbl.Authors[commitHash]
+	return value, ok
+}
+
+// AuthorHashes returns the hashes of the commits with a recorded author. Requires external synchronization handling,
+// therefore manually manage the RWMutex.
+func (tbl *Approvals) AuthorHashes() []string {
+	hashes := make([]string, 0, len(tbl.Authors))
+	for commitHash := range tbl.Authors {
+		hashes = append(hashes, commitHash)
+	}
+	return hashes
+}
+
+// ForgetAuthors removes the recorded authors of the given commits. Requires external synchronization handling,
+// therefore manually manage the RWMutex.
+func (tbl *Approvals) ForgetAuthors(commitHashes []string) {
+	for _, commitHash := range commitHashes {
+		if _, ok := tbl.Authors[commitHash]; ok {
+			delete(tbl.Authors, commitHash)
+			tbl.authorsChanged = true
+		}
+	}
+}
+
+// Serialize returns the offset for the Approvals table written to the given builder.
+func (tbl *Approvals) Serialize(b *flatbuffers.Builder) flatbuffers.UOffsetT {
+	valueOffsets := make([]flatbuffers.UOffsetT, len(tbl.Values))
+	for i, val := range tbl.Values {
+		valueOffsets[i] = val.Serialize(b)
+	}
+	serial.BranchControlApprovalsStartValuesVector(b, len(valueOffsets))
+	for i := len(valueOffsets) - 1; i >= 0; i-- {
+		b.PrependUOffsetT(valueOffsets[i])
+	}
+	values := b.EndVector(len(valueOffsets))
+	authorHashes := tbl.AuthorHashes()
+	sort.Strings(authorHashes)
+	authorOffsets := make([]flatbuffers.UOffsetT, len(authorHashes))
+	for i, commitHash := range authorHashes {
+		val := tbl.Authors[commitHash]
+		authorOffsets[i] = val.Serialize(b)
+	}
+	serial.BranchControlApprovalsStartAuthorsVector(b, len(authorOffsets))
+	for i := len(authorOffsets) - 1; i >= 0; i-- {
+		b.PrependUOffsetT(authorOffsets[i])
+	}
+	authors := b.EndVector(len(authorOffsets))
+	serial.BranchControlApprovalsStart(b)
+	serial.BranchControlApprovalsAddValues(b, values)
+	serial.BranchControlApprovalsAddAuthors(b, authors)
+	tbl.authorsChanged = false
+	return serial.BranchControlApprovalsEnd(b)
+}
+
+// Deserialize populates the table with the data from the flatbuffers representation. A nil table, written before
+// approvals existed, results in an empty table.
+func (tbl *Approvals) Deserialize(fb *serial.BranchControlApprovals) error {
+	tbl.Values = nil
+	tbl.Authors = make(map[string]CommitAuthorValue)
+	tbl.authorsChanged = false
+	if fb == nil {
+		return nil
+	}
+	tbl.Values = make([]ApprovalValue, fb.ValuesLength())
+	for i := 0; i < fb.ValuesLength(); i++ {
+		serialValue := &serial.BranchControlApprovalValue{}
+		if _, err := fb.TryValues(serialValue, i); err != nil {
+			return err
+		}
+		tbl.Values[i] = ApprovalValue{
+			Database:   string(serialValue.Database()),
+			Branch:     string(serialValue.Branch()),
+			CommitHash: string(serialValue.CommitHash()),
+			Approver:   string(serialValue.Approver()),
+		}
+	}
+	for i := 0; i < fb.AuthorsLength(); i++ {
+		serialAuthor := &serial.BranchControlCommitAuthor{}
+		if _, err := fb.TryAuthors(serialAuthor, i); err != nil {
+			return err
+		}
+		tbl.Authors[string(serialAuthor.CommitHash())] = CommitAuthorValue{
+			CommitHash: string(serialAuthor.CommitHash()),
+			User:       string(serialAuthor.User()),
+			Host:       string(serialAuthor.Host()),
+		}
+	}
+	return nil
+}
+
+// Serialize returns the offset for the ApprovalValue written to the given builder.
+func (val *ApprovalValue) Serialize(b *flatbuffers.Builder) flatbuffers.UOffsetT {
+	database := b.CreateSharedString(val.Database)
+	branch := b.CreateSharedString(val.Branch)
+	commitHash := b.CreateSharedString(val.CommitHash)
+	approver := b.CreateSharedString(val.Approver)
+
+	serial.BranchControlApprovalValueStart(b)
+	serial.BranchControlApprovalValueAddDatabase(b, database)
+	serial.BranchControlApprovalValueAddBranch(b, branch)
+	serial.BranchControlApprovalValueAddCommitHash(b, commitHash)
+	serial.BranchControlApprovalValueAddApprover(b, approver)
+	return serial.BranchControlApprovalValueEnd(b)
+}
+
+// Serialize returns the offset for the CommitAuthorValue written to the given builder.
+func (val *CommitAuthorValue) Serialize(b *flatbuffers.Builder) flatbuffers.UOffsetT {
+	commitHash := b.CreateSharedString(val.CommitHash)
+	user := b.CreateSharedString(val.User)
+	host := b.CreateSharedString(val.Host)
+
+	serial.BranchControlCommitAuthorStart(b)
+	serial.BranchControlCommitAuthorAddCommitHash(b, commitHash)
+	serial.BranchControlCommitAuthorAddUser(b, user)
+	serial.BranchControlCommitAuthorAddHost(b, host)
+	return serial.BranchControlCommitAuthorEnd(b)
+}
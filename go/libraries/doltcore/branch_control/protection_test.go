@@ -0,0 +1,51 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branch_control
+
+import (
+	"sync"
+	"testing"
+
+	fb "github.com/dolthub/flatbuffers/v23/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/gen/fb/serial"
+)
+
+func TestApprovalsSerialization(t *testing.T) {
+	approvals := &Approvals{RWMutex: &sync.RWMutex{}}
+	approvals.Insert(ApprovalValue{Database: "mydb", Branch: "feature", CommitHash: "abc", Approver: "alice"})
+	approvals.RecordAuthor(CommitAuthorValue{CommitHash: "abc", User: "bob", Host: "localhost"})
+	approvals.RecordAuthor(CommitAuthorValue{CommitHash: "def", User: "carol", Host: "localhost"})
+	approvals.ForgetAuthors([]string{"def"})
+	approvals.RecordAuthor(CommitAuthorValue{CommitHash: "abc", User: "carol", Host: "localhost"})
+
+	b := fb.NewBuilder(0)
+	b.Finish(approvals.Serialize(b))
+	fbApprovals, err := serial.TryGetRootAsBranchControlApprovals(b.FinishedBytes(), 0)
+	require.NoError(t, err)
+	deserialized := &Approvals{RWMutex: &sync.RWMutex{}}
+	require.NoError(t, deserialized.Deserialize(fbApprovals))
+	assert.Equal(t, approvals.Values, deserialized.Values)
+	// The first recorded author of a commit is kept
+	assert.Equal(t, map[string]CommitAuthorValue{"abc": {CommitHash: "abc", User: "bob", Host: "localhost"}}, deserialized.Authors)
+	author, ok := deserialized.Author("abc")
+	assert.True(t, ok)
+	assert.Equal(t, "bob", author.User)
+	// Forgotten authors are not recorded
+	_, ok = deserialized.Author("def")
+	assert.False(t, ok)
+}
@@ -0,0 +1,74 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbfactory
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/dolthub/dolt/go/libraries/utils/earl"
+	"github.com/dolthub/dolt/go/store/blobstore"
+)
+
+// S3Scheme is the scheme of blobstore URLs for AWS S3 buckets, s3://[bucket]/[prefix]. It is only supported by
+// NewBlobstore, since S3 alone can't hold a database's manifest.
+const S3Scheme = "s3"
+
+// BlobstoreSchemes are the URL schemes supported by NewBlobstore.
+var BlobstoreSchemes = []string{GSScheme, S3Scheme, OCIScheme, OSSScheme, LocalBSScheme}
+
+// NewBlobstore returns the blobstore.Blobstore at |urlStr|, which must use one of the BlobstoreSchemes. |params| are
+// the same creation parameters used for databases with the corresponding scheme, e.g. AWSCredsTypeParam for s3 URLs.
+func NewBlobstore(ctx context.Context, urlStr string, params map[string]interface{}) (blobstore.Blobstore, error) {
+	urlObj, err := earl.Parse(urlStr)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(urlObj.Scheme) {
+	case GSScheme:
+		return newGCSBlobstore(ctx, urlObj)
+	case S3Scheme:
+		return newS3Blobstore(urlObj, params)
+	case OCIScheme:
+		return newOCIBlobstore(ctx, urlObj)
+	case OSSScheme:
+		return newOSSBlobstore(urlObj, params)
+	case LocalBSScheme:
+		return newLocalBlobstore(urlObj)
+	default:
+		return nil, fmt.Errorf("unsupported blobstore scheme '%s', supported schemes are: %s", urlObj.Scheme, strings.Join(BlobstoreSchemes, ", "))
+	}
+}
+
+func newS3Blobstore(urlObj *url.URL, params map[string]interface{}) (*blobstore.S3Blobstore, error) {
+	opts, err := awsConfigFromParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	sess := session.Must(session.NewSessionWithOptions(opts))
+	_, err = sess.Config.Credentials.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	return blobstore.NewS3Blobstore(s3.New(sess), urlObj.Host, urlObj.Path), nil
+}
@@ -51,8 +51,27 @@ const (
 	StatsDir = "stats"
 
 	ChunkJournalParam = "journal"
+
+	// EncryptionKeyFileParam is a creation parameter that enables encryption at rest for a new local database, using
+	// the key file at the given path. It has no effect on databases that already have encryption configured.
+	EncryptionKeyFileParam = "encryption-key-file"
+
+	// EncryptionConfigParam is a creation parameter that enables encryption at rest for a new local database with the
+	// *nbs.EncryptionConfig it holds. It has no effect on databases that already have encryption configured.
+	EncryptionConfigParam = "encryption-config"
+
+	// LocalDatabaseParam marks the database as the local database of a repository, as opposed to a file remote,
+	// backup or clone source. Only local databases are encrypted at rest with the encryption config in their own
+	// directory. Other databases are encrypted with the config in their params, since the config in their directory
+	// comes from whoever wrote the database, and the key provider it names may run a command.
+	LocalDatabaseParam = "local-database"
 )
 
+// ErrEncryptionKeyNotConfigured is returned when opening a file remote or backup which is encrypted at rest without
+// configuring its key locally.
+var ErrEncryptionKeyNotConfigured = errors.New("database is encrypted at rest, but its key is not configured locally; " +
+	"configure the remote or backup with --" + EncryptionKeyFileParam)
+
 // DoltDataDir is the directory where noms files will be stored
 var DoltDataDir = filepath.Join(DoltDir, DataDir)
 var DoltStatsDir = filepath.Join(DoltDir, StatsDir)
@@ -136,17 +155,44 @@ func (fact FileFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat,
 		return nil, nil, nil, err
 	}
 
-	var useJournal bool
+	var useJournal, local bool
 	if params != nil {
 		_, useJournal = params[ChunkJournalParam]
+		_, local = params[LocalDatabaseParam]
+	}
+
+	if keyFile, ok := params[EncryptionKeyFileParam]; ok {
+		err = enableEncryption(path, keyFile.(string))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if cfg, ok := params[EncryptionConfigParam]; ok {
+		err = enableEncryptionWithConfig(path, *cfg.(*nbs.EncryptionConfig))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	var enc *nbs.EncryptionConfig
+	if !local {
+		enc, err = encryptionConfigFromParams(path, params)
+		if err != nil {
+			return nil, nil, nil, err
+		}
 	}
 
 	var newGenSt *nbs.NomsBlockStore
 	q := nbs.NewUnlimitedMemQuotaProvider()
-	if useJournal && chunkJournalFeatureFlag {
+	switch {
+	case useJournal && chunkJournalFeatureFlag && local:
 		newGenSt, err = nbs.NewLocalJournalingStore(ctx, nbf.VersionString(), path, q)
-	} else {
+	case useJournal && chunkJournalFeatureFlag:
+		newGenSt, err = nbs.NewLocalJournalingStoreWithEncryption(ctx, nbf.VersionString(), path, q, enc)
+	case local:
 		newGenSt, err = nbs.NewLocalStore(ctx, nbf.VersionString(), path, defaultMemTableSize, q)
+	default:
+		newGenSt, err = nbs.NewLocalStoreWithEncryption(ctx, nbf.VersionString(), path, defaultMemTableSize, q, enc)
 	}
 
 	if err != nil {
@@ -166,7 +212,7 @@ func (fact FileFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat,
 		}
 	}
 
-	oldGenSt, err := nbs.NewLocalStore(ctx, newGenSt.Version(), oldgenPath, defaultMemTableSize, q)
+	oldGenSt, err := newOldGenStore(ctx, newGenSt.Version(), oldgenPath, q, local, enc)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -192,6 +238,82 @@ func (fact FileFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat,
 	return ddb, vrw, ns, nil
 }
 
+// newOldGenStore returns the store for the old generation in |oldgenPath|. If tiered storage is configured for it, its
+// table files are stored in a blobstore and read through a local cache, otherwise they are stored in |oldgenPath|. The
+// store is encrypted with the encryption config in |oldgenPath| if it belongs to a |local| database, and with |enc|
+// otherwise.
+func newOldGenStore(ctx context.Context, nbfVerStr, oldgenPath string, q nbs.MemoryQuotaProvider, local bool, enc *nbs.EncryptionConfig) (*nbs.NomsBlockStore, error) {
+	cfg, err := nbs.LoadTieredStorageConfig(oldgenPath)
+	if err != nil {
+		return nil, err
+	} else if cfg == nil && local {
+		return nbs.NewLocalStore(ctx, nbfVerStr, oldgenPath, defaultMemTableSize, q)
+	} else if cfg == nil {
+		return nbs.NewLocalStoreWithEncryption(ctx, nbfVerStr, oldgenPath, defaultMemTableSize, q, enc)
+	}
+
+	bsParams := make(map[string]interface{}, len(cfg.Params))
+	for k, v := range cfg.Params {
+		bsParams[k] = v
+	}
+	bs, err := NewBlobstore(ctx, cfg.BlobstoreURL, bsParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open tiered storage %s: %w", cfg.BlobstoreURL, err)
+	}
+	return nbs.NewTieredStore(ctx, nbfVerStr, oldgenPath, bs, cfg.CacheDirFor(oldgenPath), cfg.MaxCacheSize(), defaultMemTableSize, q)
+}
+
+// enableEncryption configures encryption at rest with the key file |keyFile| for the database at |path|, unless it
+// already has encryption configured. The key file is created with a new key if it does not exist.
+func enableEncryption(path, keyFile string) error {
+	cfg, err := nbs.LoadEncryptionConfig(path)
+	if err != nil || cfg != nil {
+		return err
+	}
+	keyFile, err = filepath.Abs(keyFile)
+	if err != nil {
+		return err
+	}
+	if _, err = os.Stat(keyFile); errors.Is(err, os.ErrNotExist) {
+		if _, err = nbs.AddEncryptionKey(keyFile); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+	return nbs.EnableEncryption(path, nbs.EncryptionConfig{KeyProvider: nbs.FileKeyProviderName, KeyFile: keyFile})
+}
+
+// encryptionConfigFromParams returns the encryption config of the database at |path|, which is not the local database
+// of a repository, from its |params|. The encryption config in |path| is only used to check whether the database is
+// encrypted at rest, and ErrEncryptionKeyNotConfigured is returned if it is, but |params| don't configure its key.
+func encryptionConfigFromParams(path string, params map[string]interface{}) (*nbs.EncryptionConfig, error) {
+	if cfg, ok := params[EncryptionConfigParam].(*nbs.EncryptionConfig); ok {
+		return cfg, nil
+	}
+	if keyFile, ok := params[EncryptionKeyFileParam].(string); ok {
+		return &nbs.EncryptionConfig{KeyProvider: nbs.FileKeyProviderName, KeyFile: keyFile}, nil
+	}
+
+	cfg, err := nbs.LoadEncryptionConfig(path)
+	if err != nil {
+		return nil, err
+	} else if cfg != nil {
+		return nil, fmt.Errorf("%w: %s", ErrEncryptionKeyNotConfigured, path)
+	}
+	return nil, nil
+}
+
+// enableEncryptionWithConfig configures encryption at rest with |cfg| for the database at |path|, unless it already
+// has encryption configured.
+func enableEncryptionWithConfig(path string, cfg nbs.EncryptionConfig) error {
+	existing, err := nbs.LoadEncryptionConfig(path)
+	if err != nil || existing != nil {
+		return err
+	}
+	return nbs.EnableEncryption(path, cfg)
+}
+
 func validateDir(path string) error {
 	info, err := os.Stat(path)
 
@@ -40,14 +40,7 @@ func (fact OCIFactory) PrepareDB(ctx context.Context, nbf *types.NomsBinFormat,
 // CreateDB creates an OCI backed database
 func (fact OCIFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat, urlObj *url.URL, params map[string]interface{}) (datas.Database, types.ValueReadWriter, tree.NodeStore, error) {
 	var db datas.Database
-	provider := common.DefaultConfigProvider()
-
-	client, err := objectstorage.NewObjectStorageClientWithConfigurationProvider(provider)
-	if err != nil {
-		return nil, nil, nil, err
-	}
-
-	bs, err := blobstore.NewOCIBlobstore(ctx, provider, client, urlObj.Host, urlObj.Path)
+	bs, err := newOCIBlobstore(ctx, urlObj)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -65,3 +58,14 @@ func (fact OCIFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat, u
 
 	return db, vrw, ns, nil
 }
+
+func newOCIBlobstore(ctx context.Context, urlObj *url.URL) (*blobstore.OCIBlobstore, error) {
+	provider := common.DefaultConfigProvider()
+
+	client, err := objectstorage.NewObjectStorageClientWithConfigurationProvider(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	return blobstore.NewOCIBlobstore(ctx, provider, client, urlObj.Host, urlObj.Path)
+}
@@ -32,6 +32,7 @@ const (
 	EnvDisableChunkJournal           = "DOLT_DISABLE_CHUNK_JOURNAL"
 	EnvDisableReflog                 = "DOLT_DISABLE_REFLOG"
 	EnvReflogRecordLimit             = "DOLT_REFLOG_RECORD_LIMIT"
+	EnvPersistentReflog              = "DOLT_PERSISTENT_REFLOG"
 	EnvOssEndpoint                   = "OSS_ENDPOINT"
 	EnvOssAccessKeyID                = "OSS_ACCESS_KEY_ID"
 	EnvOssAccessKeySecret            = "OSS_ACCESS_KEY_SECRET"
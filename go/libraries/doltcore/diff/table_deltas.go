@@ -118,7 +118,7 @@ func GetTableDeltas(ctx context.Context, fromRoot, toRoot doltdb.RootValue) (del
 	toNS := toRoot.NodeStore()
 
 	fromDeltas := make([]TableDelta, 0)
-	err = fromRoot.IterTables(ctx, func(name doltdb.TableName, tbl *doltdb.Table, sch schema.Schema) (stop bool, err error) {
+	fromUnfetched, err := doltdb.IterFetchedTables(ctx, fromRoot, func(name doltdb.TableName, tbl *doltdb.Table, sch schema.Schema) (stop bool, err error) {
 		c, err := fromRoot.GetForeignKeyCollection(ctx)
 		if err != nil {
 			return true, err
@@ -148,7 +148,7 @@ func GetTableDeltas(ctx context.Context, fromRoot, toRoot doltdb.RootValue) (del
 
 	toDeltas := make([]TableDelta, 0)
 
-	err = toRoot.IterTables(ctx, func(name doltdb.TableName, tbl *doltdb.Table, sch schema.Schema) (stop bool, err error) {
+	toUnfetched, err := doltdb.IterFetchedTables(ctx, toRoot, func(name doltdb.TableName, tbl *doltdb.Table, sch schema.Schema) (stop bool, err error) {
 		c, err := toRoot.GetForeignKeyCollection(ctx)
 		if err != nil {
 			return true, err
@@ -177,6 +177,17 @@ func GetTableDeltas(ctx context.Context, fromRoot, toRoot doltdb.RootValue) (del
 		return nil, err
 	}
 
+	// tables excluded from a partial clone or fetch can't be diffed, so they must be unchanged
+	for name, addr := range fromUnfetched {
+		if toAddr, ok := toUnfetched[name]; !ok || toAddr != addr {
+			return nil, doltdb.TableNotFetchedError{Name: name}
+		}
+		delete(toUnfetched, name)
+	}
+	for name := range toUnfetched {
+		return nil, doltdb.TableNotFetchedError{Name: name}
+	}
+
 	deltas = matchTableDeltas(fromDeltas, toDeltas)
 	deltas, err = filterUnmodifiedTableDeltas(deltas)
 	if err != nil {
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/store/datas"
 	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/prolly"
@@ -329,3 +330,22 @@ func (ddb *DoltDB) NewPendingCommit(
 		CommitOptions: commitOpts,
 	}, nil
 }
+
+// PendingCommitParents returns the parents |commit| will have if it is written to |headRef| now, which are the current
+// head of |headRef| followed by the commit's merge parents.
+func (ddb *DoltDB) PendingCommitParents(ctx context.Context, headRef ref.DoltRef, commit *PendingCommit) ([]hash.Hash, error) {
+	ds, err := ddb.db.GetDataset(ctx, headRef.String())
+	if err != nil {
+		return nil, err
+	}
+	headAddr, ok := ds.MaybeHeadAddr()
+	if !ok {
+		return commit.CommitOptions.Parents, nil
+	}
+	for _, h := range commit.CommitOptions.Parents {
+		if h == headAddr {
+			return commit.CommitOptions.Parents, nil
+		}
+	}
+	return append([]hash.Hash{headAddr}, commit.CommitOptions.Parents...), nil
+}
@@ -70,12 +70,19 @@ func pushDataset(ctx context.Context, destDB, srcDB datas.Database, ds datas.Dat
 		return err
 	}
 
-	ds, err = destDB.GetDataset(ctx, rf.String())
+	destDS, err := destDB.GetDataset(ctx, rf.String())
 	if err != nil {
 		return err
 	}
 
-	_, err = destDB.SetHead(ctx, ds, addr, "")
+	if ds.IsMap() {
+		// Maps, like the notes of the database, are mirrored rather than merged
+		prev, _ := destDS.MaybeHeadAddr()
+		_, err = destDB.SetMapHead(ctx, destDS, addr, prev)
+		return err
+	}
+
+	_, err = destDB.SetHead(ctx, destDS, addr, "")
 	return err
 }
 
@@ -46,7 +46,8 @@ type commitItr struct {
 	curr        *Commit
 }
 
-// CommitItrForAllBranches returns a CommitItr which will iterate over all commits in all branches in a DoltDB
+// CommitItrForAllBranches returns a CommitItr which will iterate over all commits in all branches in a DoltDB. Branches
+// that are hidden from the context's user by branch control are skipped.
 func CommitItrForAllBranches(ctx context.Context, ddb *DoltDB) (CommitItr, error) {
 	branchRefs, err := ddb.GetBranches(ctx)
 
@@ -56,6 +57,12 @@ func CommitItrForAllBranches(ctx context.Context, ddb *DoltDB) (CommitItr, error
 
 	rootCommits := make([]*Commit, 0, len(branchRefs))
 	for _, ref := range branchRefs {
+		if hidden, err := ddb.isHiddenBranch(ctx, ref.GetPath()); err != nil {
+			return nil, err
+		} else if hidden {
+			continue
+		}
+
 		cm, err := ddb.ResolveCommitRef(ctx, ref)
 
 		if err != nil {
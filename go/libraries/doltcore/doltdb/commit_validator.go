@@ -0,0 +1,98 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// CommitValidator checks updates to branch heads before they are written. Every update a DoltDB makes to a branch
+// head, whether by committing, fast-forwarding, resetting or creating the branch, is checked by the validators added
+// to it with AddCommitValidator, and an error from any of them aborts the update.
+type CommitValidator interface {
+	// ValidateCommit is called before a new commit with |meta|, |parents| and the root value |rootHash| becomes the
+	// head of |branch|.
+	ValidateCommit(ctx context.Context, ddb *DoltDB, branch ref.DoltRef, meta *datas.CommitMeta, parents []hash.Hash, rootHash hash.Hash) error
+	// ValidateBranchUpdate is called before the head of |branch| is set to the existing commit |newHead|.
+	ValidateBranchUpdate(ctx context.Context, ddb *DoltDB, branch ref.DoltRef, newHead hash.Hash) error
+}
+
+// AddCommitValidator adds |v| to the validators run for every branch head update made through this DoltDB. It is not
+// safe to call concurrently with updates and should be called before the database is used.
+func (ddb *DoltDB) AddCommitValidator(v CommitValidator) {
+	ddb.validators = append(ddb.validators, v)
+}
+
+func (ddb *DoltDB) validateCommit(ctx context.Context, branch ref.DoltRef, meta *datas.CommitMeta, parents []hash.Hash, rootHash hash.Hash) error {
+	if branch.GetType() != ref.BranchRefType {
+		return nil
+	}
+	for _, v := range ddb.validators {
+		if err := v.ValidateCommit(ctx, ddb, branch, meta, parents, rootHash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ddb *DoltDB) validateBranchUpdate(ctx context.Context, branch ref.DoltRef, newHead hash.Hash) error {
+	if branch.GetType() != ref.BranchRefType {
+		return nil
+	}
+	for _, v := range ddb.validators {
+		if err := v.ValidateBranchUpdate(ctx, ddb, branch, newHead); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateCommitValue validates committing |val| to the dataset |ds| of |branch| with |opts|, which gets the current
+// head of |ds| as its first parent if no parents are given.
+func (ddb *DoltDB) validateCommitValue(ctx context.Context, branch ref.DoltRef, ds datas.Dataset, val types.Value, opts datas.CommitOptions) error {
+	if len(ddb.validators) == 0 || branch.GetType() != ref.BranchRefType {
+		return nil
+	}
+	parents := opts.Parents
+	if headAddr, ok := ds.MaybeHeadAddr(); ok && len(parents) == 0 {
+		parents = []hash.Hash{headAddr}
+	}
+	rootHash, err := val.Hash(ddb.Format())
+	if err != nil {
+		return err
+	}
+	return ddb.validateCommit(ctx, branch, opts.Meta, parents, rootHash)
+}
+
+// validatePendingCommit validates committing |commit| to |branch|.
+func (ddb *DoltDB) validatePendingCommit(ctx context.Context, branch ref.DoltRef, commit *PendingCommit) error {
+	if len(ddb.validators) == 0 || branch.GetType() != ref.BranchRefType {
+		return nil
+	}
+	parents, err := ddb.PendingCommitParents(ctx, branch, commit)
+	if err != nil {
+		return err
+	}
+	rootHash, err := commit.Roots.Staged.HashOf()
+	if err != nil {
+		return err
+	}
+	return ddb.validateCommit(ctx, branch, commit.CommitOptions.Meta, parents, rootHash)
+}
@@ -30,6 +30,8 @@ import (
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/sirupsen/logrus"
 
+	"github.com/dolthub/dolt/go/gen/fb/serial"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
 	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/utils/earl"
@@ -86,6 +88,15 @@ type DoltDB struct {
 	// parent directory as the database name. For non-filesystem based databases, the database name will not
 	// currently be populated.
 	databaseName string
+
+	// validators check every update of a branch head made through this DoltDB
+	validators []CommitValidator
+
+	// hiddenCommits caches the commits found to be hidden from users by branch control
+	hiddenCommits hiddenCommitCache
+
+	// reflogArchiver holds the policy of the persistent reflog of this database
+	reflogArchiver reflogArchiver
 }
 
 // DoltDBFromCS creates a DoltDB from a noms chunks.ChunkStore
@@ -136,6 +147,7 @@ func LoadDoltDBWithParams(ctx context.Context, nbf *types.NomsBinFormat, urlStr
 			params = make(map[string]any)
 		}
 		params[dbfactory.ChunkJournalParam] = struct{}{}
+		params[dbfactory.LocalDatabaseParam] = struct{}{}
 	}
 
 	// Pull the database name out of the URL string. For filesystem-based databases (e.g. in-memory or disk-based
@@ -169,6 +181,11 @@ func (ddb *DoltDB) Has(ctx context.Context, h hash.Hash) (bool, error) {
 	return datas.ChunkStoreFromDatabase(ddb.db).Has(ctx, h)
 }
 
+// EncryptionConfig returns the encryption at rest config of this database, or nil if it is not encrypted at rest.
+func (ddb *DoltDB) EncryptionConfig() (*nbs.EncryptionConfig, error) {
+	return nbs.EncryptionConfigOf(datas.ChunkStoreFromDatabase(ddb.db))
+}
+
 func (ddb *DoltDB) CSMetricsSummary() string {
 	return datas.GetCSStatSummaryForDB(ddb.db)
 }
@@ -385,6 +402,12 @@ func (ddb *DoltDB) getHashFromCommitSpec(ctx context.Context, cs *CommitSpec, cw
 		if !ok {
 			return nil, errors.New("invalid hash: " + cs.baseSpec)
 		}
+		// Commits hidden by branch control resolve as though they do not exist
+		if hidden, err := ddb.IsHiddenCommit(ctx, parsedHash); err != nil {
+			return nil, err
+		} else if hidden {
+			return nil, datas.ErrCommitNotFound
+		}
 		return &parsedHash, nil
 	case refCommitSpec:
 		// For a ref in a CommitSpec, we have the following behavior.
@@ -416,6 +439,19 @@ func (ddb *DoltDB) getHashFromCommitSpec(ctx context.Context, cs *CommitSpec, cw
 				valueHash, err = ddb.GetHashForRefStrByNomsRoot(ctx, candidate, nomsRoot)
 			}
 			if err == nil {
+				// Branches hidden by branch control resolve as though they do not exist, as do the tags and other
+				// refs which point to commits only reachable from hidden branches
+				var hidden bool
+				if branchName, ok := strings.CutPrefix(candidate, "refs/heads/"); ok {
+					hidden, err = ddb.isHiddenBranch(ctx, branchName)
+				} else {
+					hidden, err = ddb.IsHiddenCommit(ctx, *valueHash)
+				}
+				if err != nil {
+					return nil, err
+				} else if hidden {
+					continue
+				}
 				return valueHash, nil
 			}
 			if err != ErrBranchNotFound {
@@ -713,6 +749,10 @@ func (ddb *DoltDB) FastForwardWithWorkspaceCheck(ctx context.Context, branch ref
 		ws = wsRef.String()
 	}
 
+	if err = ddb.validateBranchUpdate(ctx, branch, addr); err != nil {
+		return err
+	}
+
 	_, err = ddb.db.FastForward(ctx, ds, addr, ws)
 
 	return err
@@ -735,6 +775,10 @@ func (ddb *DoltDB) FastForwardToHash(ctx context.Context, branch ref.DoltRef, ha
 		return err
 	}
 
+	if err = ddb.validateBranchUpdate(ctx, branch, hash); err != nil {
+		return err
+	}
+
 	_, err = ddb.db.FastForward(ctx, ds, hash, "")
 
 	return err
@@ -783,6 +827,10 @@ func (ddb *DoltDB) SetHeadAndWorkingSetToCommit(ctx context.Context, rf ref.Dolt
 		return err
 	}
 
+	if err = ddb.validateBranchUpdate(ctx, rf, addr); err != nil {
+		return err
+	}
+
 	_, err = ddb.db.SetHead(ctx, ds, addr, wsRef.String())
 	return err
 }
@@ -794,6 +842,10 @@ func (ddb *DoltDB) SetHead(ctx context.Context, ref ref.DoltRef, addr hash.Hash)
 		return err
 	}
 
+	if err = ddb.validateBranchUpdate(ctx, ref, addr); err != nil {
+		return err
+	}
+
 	_, err = ddb.db.SetHead(ctx, ds, addr, "")
 	return err
 }
@@ -864,6 +916,10 @@ func (ddb *DoltDB) CommitValue(ctx context.Context, dref ref.DoltRef, val types.
 		return nil, err
 	}
 
+	if err = ddb.validateCommitValue(ctx, dref, ds, val, commitOpts); err != nil {
+		return nil, err
+	}
+
 	ds, err = ddb.db.Commit(ctx, ds, val, commitOpts)
 	if err != nil {
 		return nil, err
@@ -886,6 +942,9 @@ func (ddb *DoltDB) CommitValue(ctx context.Context, dref ref.DoltRef, val types.
 		return nil, ErrGhostCommitEncountered
 	}
 
+	if err = ddb.recordCommitAuthor(ctx, dc); err != nil {
+		return nil, err
+	}
 	return NewCommit(ctx, ddb.vrw, ddb.ns, dc)
 }
 
@@ -927,9 +986,18 @@ func (ddb *DoltDB) CommitDangling(ctx context.Context, val types.Value, opts dat
 		return nil, err
 	}
 
+	if err = ddb.recordCommitAuthor(ctx, dcommit); err != nil {
+		return nil, err
+	}
 	return NewCommit(ctx, ddb.vrw, ddb.ns, dcommit)
 }
 
+// recordCommitAuthor records the authenticated user of |ctx| as the author of |dc|, which protected branches use to
+// keep users from approving their own commits.
+func (ddb *DoltDB) recordCommitAuthor(ctx context.Context, dc *datas.Commit) error {
+	return branch_control.RecordCommitAuthor(ctx, dc.Addr().String())
+}
+
 // ValueReadWriter returns the underlying noms database as a types.ValueReadWriter.
 func (ddb *DoltDB) ValueReadWriter() types.ValueReadWriter {
 	return ddb.vrw
@@ -1216,6 +1284,98 @@ func visitDatasets(ctx context.Context, refTypeFilter map[ref.RefType]struct{},
 	})
 }
 
+// isHiddenBranch returns whether the branch with the given name is hidden from the context's user by branch control.
+// Hidden branches are treated as though they do not exist whenever they are referenced by name.
+func (ddb *DoltDB) isHiddenBranch(ctx context.Context, branchName string) (bool, error) {
+	err := branch_control.CheckReadAccess(ctx, ddb.databaseName, branchName)
+	if branch_control.ErrCannotReadBranch.Is(err) {
+		return true, nil
+	}
+	return false, err
+}
+
+// IsHiddenCommit returns whether the commit with the given address is hidden from the context's user by branch control.
+// Commits are readable when they can be reached from a branch which the user may read, so a commit resolved by hash,
+// tag or remote ref is hidden when it is only reachable from hidden branches. The history of a readable branch stays
+// readable, including the commits merged into it from hidden branches.
+func (ddb *DoltDB) IsHiddenCommit(ctx context.Context, addr hash.Hash) (bool, error) {
+	if !branch_control.MayHideBranches(ctx) {
+		return false, nil
+	}
+
+	branches, err := ddb.GetBranchesWithHashes(ctx)
+	if err != nil {
+		return false, err
+	}
+	var visibleHeads []hash.Hash
+	anyHidden := false
+	for _, branch := range branches {
+		hidden, err := ddb.isHiddenBranch(ctx, branch.Ref.GetPath())
+		if err != nil {
+			return false, err
+		}
+		if hidden {
+			anyHidden = true
+			continue
+		}
+		if branch.Hash == addr {
+			return false, nil
+		}
+		visibleHeads = append(visibleHeads, branch.Hash)
+	}
+	if !anyHidden {
+		return false, nil
+	}
+
+	results := ddb.hiddenCommits.forHeads(visibleHeads)
+	if hidden, ok := results.Get(addr); ok {
+		return hidden, nil
+	}
+	reachable, err := ddb.IsCommitReachable(ctx, addr, visibleHeads)
+	if err != nil {
+		return false, err
+	}
+	results.Add(addr, !reachable)
+	return !reachable, nil
+}
+
+// IsCommitReachable returns whether the commit with the given address is one of |heads| or can be reached from any of
+// them. Returns datas.ErrCommitNotFound if the commit does not exist. Commits are always reachable in the old format,
+// which lacks commit closures.
+func (ddb *DoltDB) IsCommitReachable(ctx context.Context, addr hash.Hash, heads []hash.Hash) (bool, error) {
+	cm, err := datas.LoadCommitAddr(ctx, ddb.vrw, addr)
+	if err != nil {
+		return false, err
+	}
+	if cm.IsGhost() || !ddb.Format().UsesFlatbuffers() {
+		return true, nil
+	}
+	for _, headAddr := range heads {
+		if headAddr == addr {
+			return true, nil
+		}
+		head, err := datas.LoadCommitAddr(ctx, ddb.vrw, headAddr)
+		if err != nil {
+			return false, err
+		}
+		if head.IsGhost() || head.Height() <= cm.Height() {
+			continue
+		}
+		closure, err := getCommitClosure(ctx, head, ddb.vrw, ddb.ns)
+		if err != nil {
+			return false, err
+		}
+		reachable, err := closure.ContainsKey(ctx, addr, cm.Height())
+		if err != nil {
+			return false, err
+		}
+		if reachable {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // GetRefByNameInsensitive searches this Dolt database's branch, tag, and head refs for a case-insensitive
 // match of the specified ref name. If a matching DoltRef is found, it is returned; otherwise an error is returned.
 func (ddb *DoltDB) GetRefByNameInsensitive(ctx context.Context, refName string) (ref.DoltRef, error) {
@@ -1225,7 +1385,11 @@ func (ddb *DoltDB) GetRefByNameInsensitive(ctx context.Context, refName string)
 	}
 	for _, branchRef := range branchRefs {
 		if strings.EqualFold(branchRef.GetPath(), refName) {
-			return branchRef, nil
+			if hidden, err := ddb.isHiddenBranch(ctx, branchRef.GetPath()); err != nil {
+				return nil, err
+			} else if !hidden {
+				return branchRef, nil
+			}
 		}
 	}
 
@@ -1235,6 +1399,13 @@ func (ddb *DoltDB) GetRefByNameInsensitive(ctx context.Context, refName string)
 	}
 	for _, headRef := range headRefs {
 		if strings.EqualFold(headRef.GetPath(), refName) {
+			if headRef.GetType() == ref.BranchRefType {
+				if hidden, err := ddb.isHiddenBranch(ctx, headRef.GetPath()); err != nil {
+					return nil, err
+				} else if hidden {
+					continue
+				}
+			}
 			return headRef, nil
 		}
 	}
@@ -1287,6 +1458,10 @@ func (ddb *DoltDB) NewBranchAtCommit(ctx context.Context, branchRef ref.DoltRef,
 		return err
 	}
 
+	if err = ddb.validateBranchUpdate(ctx, branchRef, addr); err != nil {
+		return err
+	}
+
 	_, err = ddb.db.SetHead(ctx, ds, addr, "")
 	if err != nil {
 		return err
@@ -1524,6 +1699,10 @@ func (ddb *DoltDB) CommitWithWorkingSet(
 		return nil, err
 	}
 
+	if err = ddb.validatePendingCommit(ctx, headRef, commit); err != nil {
+		return nil, err
+	}
+
 	wsSpec, err := ddb.writeWorkingSet(ctx, workingSetRef, workingSet, meta, wsDs)
 	if err != nil {
 		return nil, err
@@ -1552,6 +1731,9 @@ func (ddb *DoltDB) CommitWithWorkingSet(
 		return nil, ErrGhostCommitEncountered
 	}
 
+	if err = ddb.recordCommitAuthor(ctx, dc); err != nil {
+		return nil, err
+	}
 	return NewCommit(ctx, ddb.vrw, ddb.ns, dc)
 }
 
@@ -1684,7 +1866,14 @@ func (ddb *DoltDB) GC(ctx context.Context, mode types.GCMode, safepointF func()
 		return fmt.Errorf("this database does not support garbage collection")
 	}
 
-	err := ddb.pruneUnreferencedDatasets(ctx)
+	// GC discards the roots recorded by the chunk journal, which the reflog is read from, so the persistent reflog
+	// archives them first
+	err := ddb.ArchiveReflog(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = ddb.pruneUnreferencedDatasets(ctx)
 	if err != nil {
 		return err
 	}
@@ -1722,7 +1911,20 @@ func (ddb *DoltDB) GC(ctx context.Context, mode types.GCMode, safepointF func()
 		return err
 	}
 
-	return collector.GC(ctx, mode, oldGen, newGen, safepointF)
+	// Keep the roots written since the reflog was archived, and archive them once GC completes. Roots written while GC
+	// runs are kept by it.
+	unarchived, err := ddb.unarchivedReflogRoots()
+	if err != nil {
+		return err
+	}
+	newGen.InsertAll(unarchived)
+
+	err = collector.GC(ctx, mode, oldGen, newGen, safepointF)
+	if err != nil {
+		return err
+	}
+
+	return ddb.ArchiveReflog(ctx)
 }
 
 func (ddb *DoltDB) ShallowGC(ctx context.Context) error {
@@ -1784,10 +1986,20 @@ func pullHash(
 	tempDir string,
 	statsCh chan pull.Stats,
 	skipHashes hash.HashSet,
+) error {
+	return pullHashWithWalk(ctx, destDB, srcDB, targetHashes, tempDir, statsCh, types.WalkAddrsForNBF(srcDB.Format(), skipHashes))
+}
+
+func pullHashWithWalk(
+	ctx context.Context,
+	destDB, srcDB datas.Database,
+	targetHashes []hash.Hash,
+	tempDir string,
+	statsCh chan pull.Stats,
+	waf pull.WalkAddrs,
 ) error {
 	srcCS := datas.ChunkStoreFromDatabase(srcDB)
 	destCS := datas.ChunkStoreFromDatabase(destDB)
-	waf := types.WalkAddrsForNBF(srcDB.Format(), skipHashes)
 
 	if datas.CanUsePuller(srcDB) && datas.CanUsePuller(destDB) {
 		puller, err := pull.NewPuller(ctx, tempDir, defaultChunksPerTF, srcCS, destCS, waf, targetHashes, statsCh)
@@ -1803,6 +2015,90 @@ func pullHash(
 	}
 }
 
+// PullChunksWithTableFilter is PullChunks for a partial fetch: the data of the tables rejected by |includeTable| in the
+// root values it pulls is skipped, unless the same table data is also used by an included table. It returns the
+// addresses of the skipped tables which are missing from this database, which the caller persists as ghosts with
+// PersistGhostCommits.
+func (ddb *DoltDB) PullChunksWithTableFilter(
+	ctx context.Context,
+	tempDir string,
+	srcDB *DoltDB,
+	targetHashes []hash.Hash,
+	statsCh chan pull.Stats,
+	skipHashes hash.HashSet,
+	includeTable func(TableName) bool,
+) (hash.HashSet, error) {
+	if !srcDB.Format().UsesFlatbuffers() {
+		return nil, fmt.Errorf("partial fetches are not supported by the storage format %s", srcDB.Format().VersionString())
+	}
+
+	w := &tableFilterWalk{
+		ctx:          ctx,
+		vrw:          srcDB.vrw,
+		ns:           srcDB.ns,
+		includeTable: includeTable,
+		base:         types.WalkAddrsForNBF(srcDB.Format(), skipHashes),
+		excluded:     hash.NewHashSet(),
+		included:     hash.NewHashSet(),
+	}
+	err := pullHashWithWalk(ctx, ddb.db, srcDB.db, targetHashes, tempDir, statsCh, w.walkAddrs)
+	if err != nil {
+		return nil, err
+	}
+
+	skipped := hash.NewHashSet()
+	for h := range w.excluded {
+		if !w.included.Has(h) {
+			skipped.Insert(h)
+		}
+	}
+	if skipped.Size() == 0 {
+		return skipped, nil
+	}
+	return datas.ChunkStoreFromDatabase(ddb.db).HasMany(ctx, skipped)
+}
+
+// tableFilterWalk walks the chunks pulled by a partial fetch. Every root value it sees records the addresses of its
+// included and excluded tables, and the walk doesn't descend into an excluded address unless it was also seen under
+// an included table. Table addresses are referenced by root values and by the chunks of their table maps, which are
+// walked after them. The puller calls walkAddrs from a single goroutine.
+type tableFilterWalk struct {
+	ctx          context.Context
+	vrw          types.ValueReadWriter
+	ns           tree.NodeStore
+	includeTable func(TableName) bool
+	base         pull.WalkAddrs
+	excluded     hash.HashSet
+	included     hash.HashSet
+}
+
+func (w *tableFilterWalk) walkAddrs(c chunks.Chunk, cb func(hash.Hash, bool) error) error {
+	if fileID := serial.GetFileID(c.Data()); fileID == serial.RootValueFileID || fileID == serial.DoltgresRootValueFileID {
+		root, err := NewRootValue(w.ctx, w.vrw, w.ns, types.SerialMessage(c.Data()))
+		if err != nil {
+			return err
+		}
+		tableHashes, err := MapTableHashes(w.ctx, root)
+		if err != nil {
+			return err
+		}
+		for name, addr := range tableHashes {
+			if w.includeTable(name) {
+				w.included.Insert(addr)
+			} else {
+				w.excluded.Insert(addr)
+			}
+		}
+	}
+
+	return w.base(c, func(h hash.Hash, isLeaf bool) error {
+		if w.excluded.Has(h) && !w.included.Has(h) {
+			return nil
+		}
+		return cb(h, isLeaf)
+	})
+}
+
 func (ddb *DoltDB) Clone(ctx context.Context, destDB *DoltDB, eventCh chan<- pull.TableFileEvent) error {
 	return pull.Clone(ctx, datas.ChunkStoreFromDatabase(ddb.db), datas.ChunkStoreFromDatabase(destDB.db), eventCh)
 }
@@ -2089,11 +2385,24 @@ func (ddb *DoltDB) GetStashRootAndHeadCommitAtIdx(ctx context.Context, idx int)
 
 // PersistGhostCommits persists the set of ghost commits to the database. This is how the application layer passes
 // information about ghost commits to the storage layer. This can be called multiple times over the course of performing
-// a shallow clone, but should not be called after the clone is complete.
+// a shallow clone, but should not be called after the clone is complete. Partial fetches use it as well, to persist the
+// addresses of the tables excluded by their table filter. Each call adds to the ghosts already persisted.
 func (ddb *DoltDB) PersistGhostCommits(ctx context.Context, ghostCommits hash.HashSet) error {
 	return ddb.db.Database.PersistGhostCommitIDs(ctx, ghostCommits)
 }
 
+// DeleteGhostHashes removes addresses from the ghosts of the database, which must be done before pulling the chunks
+// they stand for.
+func (ddb *DoltDB) DeleteGhostHashes(ctx context.Context, ghosts hash.HashSet) error {
+	return ddb.db.Database.DeleteGhostHashes(ctx, ghosts)
+}
+
+// HasGhosts returns whether the database is a shallow or partial clone, which is missing the chunks of some of the
+// commits or tables it references.
+func (ddb *DoltDB) HasGhosts(ctx context.Context) (bool, error) {
+	return ddb.db.Database.HasGhosts(ctx)
+}
+
 type FSCKReport struct {
 	ChunkCount uint32
 	Problems   []error
@@ -50,6 +50,9 @@ const (
 
 var (
 	ErrUnknownAutoIncrementValue = fmt.Errorf("auto increment set for non-numeric column type")
+
+	// ErrTableNotFetched is returned when loading a table which was excluded from a partial clone or fetch.
+	ErrTableNotFetched = fmt.Errorf("table not fetched")
 )
 
 var (
@@ -184,6 +187,9 @@ func TableFromAddr(ctx context.Context, vrw types.ValueReadWriter, ns tree.NodeS
 	if err != nil {
 		return nil, err
 	}
+	if _, ok := val.(types.GhostValue); ok {
+		return nil, ErrTableNotFetched
+	}
 
 	if !vrw.Format().UsesFlatbuffers() {
 		st, ok := val.(types.Struct)
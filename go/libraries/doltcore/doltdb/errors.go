@@ -18,6 +18,8 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
 )
 
 var ErrInvBranchName = errors.New("not a valid user branch name")
@@ -34,6 +36,7 @@ var ErrHashNotFound = errors.New("could not find a value for this hash")
 var ErrBranchNotFound = errors.New("branch not found")
 var ErrTagNotFound = errors.New("tag not found")
 var ErrTupleNotFound = errors.New("tuple not found")
+var ErrNoteNotFound = errors.New("note not found")
 var ErrWorkingSetNotFound = errors.New("working set not found")
 var ErrWorkspaceNotFound = errors.New("workspace not found")
 var ErrTableNotFound = errors.New("table not found")
@@ -63,6 +66,20 @@ func (e ErrClientOutOfDate) Error() string {
 	visit https://github.com/dolthub/dolt/releases/latest/`, e.ClientVer, e.RepoVer)
 }
 
+// TableNotFetchedError is returned when reading a table which was excluded by the table filter of a partial clone or
+// fetch. Its data can be fetched with `dolt fetch --tables`.
+type TableNotFetchedError struct {
+	Name TableName
+}
+
+func (e TableNotFetchedError) Error() string {
+	return fmt.Sprintf("table '%s' has not been fetched from the remote; run 'dolt fetch --tables %s' to fetch it", e.Name, e.Name.Name)
+}
+
+func (e TableNotFetchedError) Unwrap() error {
+	return durable.ErrTableNotFetched
+}
+
 func IsInvalidFormatErr(err error) bool {
 	switch err {
 	case ErrInvBranchName, ErrInvTableName, ErrInvHash, ErrInvalidAncestorSpec, ErrInvalidBranchOrHash:
@@ -0,0 +1,65 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"sort"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+const (
+	// hiddenCommitHeadSets is the number of visible branch head sets whose results are cached. Users denied different
+	// branches see different sets, as does every user once a branch head moves.
+	hiddenCommitHeadSets = 64
+	// hiddenCommitsPerHeadSet is the number of commits whose results are cached for each visible branch head set.
+	hiddenCommitsPerHeadSet = 4096
+)
+
+// hiddenCommitCache caches whether commits are hidden by branch control, keyed by the set of branch heads visible to
+// the user. Whether a commit is reachable from a fixed set of heads never changes, so entries need no invalidation.
+type hiddenCommitCache struct {
+	mu   sync.Mutex
+	sets *lru.Cache[hash.Hash, *lru.Cache[hash.Hash, bool]]
+}
+
+// forHeads returns the cached results for the commits resolved against |visibleHeads|.
+func (c *hiddenCommitCache) forHeads(visibleHeads []hash.Hash) *lru.Cache[hash.Hash, bool] {
+	sorted := make([]hash.Hash, len(visibleHeads))
+	copy(sorted, visibleHeads)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Less(sorted[j])
+	})
+	buf := make([]byte, 0, len(sorted)*hash.ByteLen)
+	for _, h := range sorted {
+		buf = append(buf, h[:]...)
+	}
+	key := hash.Of(buf)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.sets == nil {
+		c.sets, _ = lru.New[hash.Hash, *lru.Cache[hash.Hash, bool]](hiddenCommitHeadSets)
+	}
+	results, ok := c.sets.Get(key)
+	if !ok {
+		results, _ = lru.New[hash.Hash, bool](hiddenCommitsPerHeadSet)
+		c.sets.Add(key, results)
+	}
+	return results
+}
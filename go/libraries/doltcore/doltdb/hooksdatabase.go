@@ -196,6 +196,14 @@ func (db hooksDatabase) Tag(ctx context.Context, ds datas.Dataset, commitAddr ha
 	return ds, err
 }
 
+func (db hooksDatabase) SetMapHead(ctx context.Context, ds datas.Dataset, mapAddr hash.Hash, prevHash hash.Hash) (datas.Dataset, error) {
+	ds, err := db.Database.SetMapHead(ctx, ds, mapAddr, prevHash)
+	if err == nil {
+		db.ExecuteCommitHooks(ctx, ds, false)
+	}
+	return ds, err
+}
+
 func (db hooksDatabase) SetTuple(ctx context.Context, ds datas.Dataset, val []byte) (datas.Dataset, error) {
 	ds, err := db.Database.SetTuple(ctx, ds, val)
 	if err == nil {
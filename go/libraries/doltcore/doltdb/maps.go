@@ -0,0 +1,95 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// ErrMapsNotSupported is returned when maps are read or written in a database in the old storage format.
+var ErrMapsNotSupported = errors.New("maps are not supported for old storage format")
+
+// GetMap returns the map named |name|, whose keys and values are described by |kd| and |vd|. Maps hold data about a
+// database that is not versioned with it, like the history of workflow runs. An empty map is returned if no map
+// named |name| was written.
+func (ddb *DoltDB) GetMap(ctx context.Context, name string, kd, vd val.TupleDesc) (prolly.Map, error) {
+	m, _, err := ddb.getMapAtRef(ctx, ref.NewMapRef(name), kd, vd)
+	return m, err
+}
+
+// UpdateMap applies |edit| to the map named |name| and stores the result. If the map is concurrently updated, the
+// edit is applied again to the updated map, so |edit| may be called more than once.
+func (ddb *DoltDB) UpdateMap(ctx context.Context, name string, kd, vd val.TupleDesc, edit func(*prolly.MutableMap) error) error {
+	return ddb.updateMapAtRef(ctx, ref.NewMapRef(name), kd, vd, edit)
+}
+
+func (ddb *DoltDB) updateMapAtRef(ctx context.Context, r ref.DoltRef, kd, vd val.TupleDesc, edit func(*prolly.MutableMap) error) error {
+	for {
+		m, ds, err := ddb.getMapAtRef(ctx, r, kd, vd)
+		if err != nil {
+			return err
+		}
+
+		mut := m.Mutate()
+		if err = edit(mut); err != nil {
+			return err
+		}
+		m, err = mut.Map(ctx)
+		if err != nil {
+			return err
+		}
+
+		prev, ok := ds.MaybeHeadAddr()
+		if ok && prev == m.HashOf() {
+			// nothing changed, so there's no need to write a new root
+			return nil
+		}
+		_, err = ddb.db.SetMapHead(ctx, ds, m.HashOf(), prev)
+		if errors.Is(err, datas.ErrOptimisticLockFailed) {
+			continue
+		}
+		return err
+	}
+}
+
+func (ddb *DoltDB) getMapAtRef(ctx context.Context, r ref.DoltRef, kd, vd val.TupleDesc) (prolly.Map, datas.Dataset, error) {
+	if !types.IsFormat_DOLT(ddb.Format()) {
+		return prolly.Map{}, datas.Dataset{}, ErrMapsNotSupported
+	}
+
+	ds, err := ddb.db.GetDataset(ctx, r.String())
+	if err != nil {
+		return prolly.Map{}, datas.Dataset{}, err
+	}
+
+	addr, ok := ds.MaybeHeadAddr()
+	if !ok {
+		m, err := prolly.NewMapFromTuples(ctx, ddb.NodeStore(), kd, vd)
+		return m, ds, err
+	}
+
+	node, err := ddb.NodeStore().Read(ctx, addr)
+	if err != nil {
+		return prolly.Map{}, datas.Dataset{}, err
+	}
+	return prolly.NewMap(node, ddb.NodeStore(), kd, vd), ds, nil
+}
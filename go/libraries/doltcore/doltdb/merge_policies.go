@@ -0,0 +1,259 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const (
+	// MergePolicyAllColumns is the column name of a merge policy that applies to every column of a table.
+	MergePolicyAllColumns = "*"
+
+	MergePoliciesTableNameCol  = "table_name"
+	MergePoliciesColumnNameCol = "column_name"
+	MergePoliciesStrategyCol   = "strategy"
+	MergePoliciesArgumentCol   = "argument"
+
+	MergeResolutionsTableNameCol   = "table_name"
+	MergeResolutionsRowKeyCol      = "row_key"
+	MergeResolutionsColumnNameCol  = "column_name"
+	MergeResolutionsStrategyCol    = "strategy"
+	MergeResolutionsOurValueCol    = "our_value"
+	MergeResolutionsTheirValueCol  = "their_value"
+	MergeResolutionsMergedValueCol = "merged_value"
+)
+
+// MergeStrategy is the strategy a merge policy uses to resolve a cell that was modified differently on both sides
+// of a merge.
+type MergeStrategy string
+
+const (
+	// MergeStrategyOurs keeps the value of the branch being merged into.
+	MergeStrategyOurs MergeStrategy = "ours"
+	// MergeStrategyTheirs takes the value of the branch being merged.
+	MergeStrategyTheirs MergeStrategy = "theirs"
+	// MergeStrategyLastWriterWins takes the value from the side whose timestamp column, named by the
+	// policy's argument, is greater. Ties are resolved to our side.
+	MergeStrategyLastWriterWins MergeStrategy = "last_writer_wins"
+	// MergeStrategyMax takes the greater of the two values.
+	MergeStrategyMax MergeStrategy = "max"
+	// MergeStrategyMin takes the lesser of the two values.
+	MergeStrategyMin MergeStrategy = "min"
+	// MergeStrategySum applies the changes made on both sides to the base value, i.e. ours + theirs - base.
+	MergeStrategySum MergeStrategy = "sum"
+	// MergeStrategyJsonUnion takes the union of the elements of two JSON arrays.
+	MergeStrategyJsonUnion MergeStrategy = "json_union"
+	// MergeStrategyExpression evaluates the SQL expression given as the policy's argument. The expression can
+	// reference the columns `ours`, `theirs` and `base`.
+	MergeStrategyExpression MergeStrategy = "expression"
+)
+
+var mergeStrategies = []MergeStrategy{
+	MergeStrategyOurs,
+	MergeStrategyTheirs,
+	MergeStrategyLastWriterWins,
+	MergeStrategyMax,
+	MergeStrategyMin,
+	MergeStrategySum,
+	MergeStrategyJsonUnion,
+	MergeStrategyExpression,
+}
+
+// MergePolicy declares how merge resolves conflicting changes to a column, or to every column, of a table.
+type MergePolicy struct {
+	TableName  string
+	ColumnName string
+	Strategy   MergeStrategy
+	Argument   string
+}
+
+// NewMergePolicy returns a validated MergePolicy.
+func NewMergePolicy(tableName, columnName, strategy, argument string) (MergePolicy, error) {
+	p := MergePolicy{
+		TableName:  tableName,
+		ColumnName: columnName,
+		Strategy:   MergeStrategy(strings.ToLower(strings.TrimSpace(strategy))),
+		Argument:   argument,
+	}
+	return p, p.Validate()
+}
+
+// Validate returns an error if the policy names an unknown strategy or is missing the argument its strategy needs.
+func (p MergePolicy) Validate() error {
+	if p.TableName == "" {
+		return fmt.Errorf("invalid merge policy: %s must not be empty", MergePoliciesTableNameCol)
+	}
+	if p.ColumnName == "" {
+		return fmt.Errorf("invalid merge policy for table '%s': %s must be a column name or '%s'", p.TableName, MergePoliciesColumnNameCol, MergePolicyAllColumns)
+	}
+
+	known := false
+	for _, s := range mergeStrategies {
+		if p.Strategy == s {
+			known = true
+			break
+		}
+	}
+	if !known {
+		names := make([]string, len(mergeStrategies))
+		for i, s := range mergeStrategies {
+			names[i] = string(s)
+		}
+		return fmt.Errorf("invalid merge policy for table '%s': unknown strategy '%s', expected one of: %s", p.TableName, p.Strategy, strings.Join(names, ", "))
+	}
+
+	switch p.Strategy {
+	case MergeStrategyLastWriterWins:
+		if p.Argument == "" {
+			return fmt.Errorf("invalid merge policy for table '%s': strategy '%s' requires the name of a timestamp column as its argument", p.TableName, p.Strategy)
+		}
+	case MergeStrategyExpression:
+		if p.Argument == "" {
+			return fmt.Errorf("invalid merge policy for table '%s': strategy '%s' requires a SQL expression as its argument", p.TableName, p.Strategy)
+		}
+	}
+	return nil
+}
+
+// MergePolicies are the merge policies declared in the dolt_merge_policies table.
+type MergePolicies []MergePolicy
+
+// ForTable returns the policies declared for the table named |tableName|, keyed by lower case column name.
+// A policy for MergePolicyAllColumns applies to every column without a policy of its own.
+func (mp MergePolicies) ForTable(tableName string) map[string]MergePolicy {
+	var policies map[string]MergePolicy
+	for _, p := range mp {
+		if !strings.EqualFold(p.TableName, tableName) {
+			continue
+		}
+		if policies == nil {
+			policies = make(map[string]MergePolicy)
+		}
+		policies[strings.ToLower(p.ColumnName)] = p
+	}
+	return policies
+}
+
+var MergePoliciesSchema schema.Schema
+var MergeResolutionsSchema schema.Schema
+
+func init() {
+	argumentCol, err := schema.NewColumnWithTypeInfo(MergePoliciesArgumentCol, schema.DoltMergePoliciesArgumentTag, typeinfo.LongTextType, false, "", false, "")
+	if err != nil {
+		panic(err)
+	}
+	MergePoliciesSchema = schema.MustSchemaFromCols(schema.NewColCollection(
+		schema.NewColumn(MergePoliciesTableNameCol, schema.DoltMergePoliciesTableNameTag, types.StringKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn(MergePoliciesColumnNameCol, schema.DoltMergePoliciesColumnNameTag, types.StringKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn(MergePoliciesStrategyCol, schema.DoltMergePoliciesStrategyTag, types.StringKind, false, schema.NotNullConstraint{}),
+		argumentCol,
+	))
+
+	valueCols := make([]schema.Column, 0, 3)
+	for _, c := range []struct {
+		name string
+		tag  uint64
+	}{
+		{MergeResolutionsOurValueCol, schema.DoltMergeResolutionsOurValueTag},
+		{MergeResolutionsTheirValueCol, schema.DoltMergeResolutionsTheirValueTag},
+		{MergeResolutionsMergedValueCol, schema.DoltMergeResolutionsMergedValueTag},
+	} {
+		col, err := schema.NewColumnWithTypeInfo(c.name, c.tag, typeinfo.LongTextType, false, "", false, "")
+		if err != nil {
+			panic(err)
+		}
+		valueCols = append(valueCols, col)
+	}
+	MergeResolutionsSchema = schema.MustSchemaFromCols(schema.NewColCollection(
+		schema.NewColumn(MergeResolutionsTableNameCol, schema.DoltMergeResolutionsTableNameTag, types.StringKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn(MergeResolutionsRowKeyCol, schema.DoltMergeResolutionsRowKeyTag, types.StringKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn(MergeResolutionsColumnNameCol, schema.DoltMergeResolutionsColumnNameTag, types.StringKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn(MergeResolutionsStrategyCol, schema.DoltMergeResolutionsStrategyTag, types.StringKind, false, schema.NotNullConstraint{}),
+		valueCols[0],
+		valueCols[1],
+		valueCols[2],
+	))
+}
+
+// GetMergePolicies returns the merge policies stored in the dolt_merge_policies table of |root|.
+func GetMergePolicies(ctx context.Context, root RootValue) (MergePolicies, error) {
+	tbl, found, err := root.GetTable(ctx, TableName{Name: MergePoliciesTableName})
+	if err != nil {
+		return nil, err
+	}
+	if !found || !types.IsFormat_DOLT(tbl.Format()) {
+		return nil, nil
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !schema.SchemasAreEqual(sch, MergePoliciesSchema) {
+		return nil, fmt.Errorf("%s has an unexpected schema, this should never happen", MergePoliciesTableName)
+	}
+
+	idx, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m := durable.ProllyMapFromIndex(idx)
+	keyDesc, valDesc := m.Descriptors()
+
+	iter, err := m.IterAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var policies MergePolicies
+	for {
+		k, v, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		tableName, _ := keyDesc.GetString(0, k)
+		columnName, _ := keyDesc.GetString(1, k)
+		strategy, _ := valDesc.GetString(0, v)
+		argument := ""
+		arg, err := tree.GetField(ctx, valDesc, 1, v, m.NodeStore())
+		if err != nil {
+			return nil, err
+		}
+		if arg != nil {
+			argument = arg.(string)
+		}
+
+		p, err := NewMergePolicy(tableName, columnName, strategy, argument)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, p)
+	}
+
+	return policies, nil
+}
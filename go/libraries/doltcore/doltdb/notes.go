@@ -0,0 +1,220 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// notesRef is the ref under which the notes of every commit are stored.
+var notesRef = ref.NewNotesRef(ref.DefaultNotesName)
+
+var (
+	// notesKeyDesc is keyed by the hash of the annotated commit.
+	notesKeyDesc = val.NewTupleDescriptor(
+		val.Type{Enc: val.StringEnc},
+	)
+	// notesValDesc holds the author, timestamps and message of a note.
+	notesValDesc = val.NewTupleDescriptor(
+		val.Type{Enc: val.StringEnc},
+		val.Type{Enc: val.StringEnc},
+		val.Type{Enc: val.Uint64Enc},
+		val.Type{Enc: val.Int64Enc},
+		val.Type{Enc: val.StringEnc},
+	)
+)
+
+// Note is an annotation of a commit. Notes are stored in a map from commit hash to note under refs/notes/commits,
+// outside of the commit graph, so they can be changed or removed without rewriting the commits they annotate.
+type Note struct {
+	CommitHash hash.Hash
+	Meta       *datas.TagMeta
+}
+
+// SetNote attaches a note with |meta| to the commit given, replacing any existing note for the commit.
+func (ddb *DoltDB) SetNote(ctx context.Context, c *Commit, meta *datas.TagMeta) error {
+	commitAddr, err := c.HashOf()
+	if err != nil {
+		return err
+	}
+
+	key, err := noteKey(ddb, commitAddr)
+	if err != nil {
+		return err
+	}
+	vb := val.NewTupleBuilder(notesValDesc)
+	if err = vb.PutString(0, meta.Name); err != nil {
+		return err
+	}
+	if err = vb.PutString(1, meta.Email); err != nil {
+		return err
+	}
+	vb.PutUint64(2, meta.Timestamp)
+	vb.PutInt64(3, meta.UserTimestamp)
+	if err = vb.PutString(4, meta.Description); err != nil {
+		return err
+	}
+	value := vb.Build(ddb.NodeStore().Pool())
+
+	return ddb.updateMapAtRef(ctx, notesRef, notesKeyDesc, notesValDesc, func(m *prolly.MutableMap) error {
+		return m.Put(ctx, key, value)
+	})
+}
+
+// ResolveNote returns the note attached to the commit with the hash given, or ErrNoteNotFound if there is none.
+func (ddb *DoltDB) ResolveNote(ctx context.Context, commitHash hash.Hash) (*Note, error) {
+	m, _, err := ddb.getMapAtRef(ctx, notesRef, notesKeyDesc, notesValDesc)
+	if errors.Is(err, ErrMapsNotSupported) {
+		return nil, ErrNoteNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	key, err := noteKey(ddb, commitHash)
+	if err != nil {
+		return nil, err
+	}
+
+	var note *Note
+	err = m.Get(ctx, key, func(_, v val.Tuple) error {
+		if v != nil {
+			note = &Note{CommitHash: commitHash, Meta: noteMeta(v)}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if note == nil {
+		return nil, ErrNoteNotFound
+	}
+	return note, nil
+}
+
+// GetNotes returns all the notes in the database, ordered by the hash of the commit they annotate. Databases in the
+// old storage format cannot store notes, and have none.
+func (ddb *DoltDB) GetNotes(ctx context.Context) ([]*Note, error) {
+	m, _, err := ddb.getMapAtRef(ctx, notesRef, notesKeyDesc, notesValDesc)
+	if errors.Is(err, ErrMapsNotSupported) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	iter, err := m.IterAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var notes []*Note
+	for {
+		k, v, err := iter.Next(ctx)
+		if err == io.EOF {
+			return notes, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		s, _ := notesKeyDesc.GetString(0, k)
+		h, ok := hash.MaybeParse(s)
+		if !ok {
+			continue
+		}
+		notes = append(notes, &Note{CommitHash: h, Meta: noteMeta(v)})
+	}
+}
+
+// DeleteNote removes the note attached to the commit with the hash given, or returns ErrNoteNotFound if there is none.
+func (ddb *DoltDB) DeleteNote(ctx context.Context, commitHash hash.Hash) error {
+	key, err := noteKey(ddb, commitHash)
+	if err != nil {
+		return err
+	}
+
+	return ddb.updateMapAtRef(ctx, notesRef, notesKeyDesc, notesValDesc, func(m *prolly.MutableMap) error {
+		ok, err := m.Has(ctx, key)
+		if err != nil {
+			return err
+		} else if !ok {
+			return ErrNoteNotFound
+		}
+		return m.Delete(ctx, key)
+	})
+}
+
+// NotesAddr returns the address of the map holding the notes of the database, or false if no note was ever written.
+// The map, and the chunks it references, can be copied to another database with the puller and merged into its notes
+// with MergeNotes.
+func (ddb *DoltDB) NotesAddr(ctx context.Context) (hash.Hash, bool, error) {
+	ds, err := ddb.db.GetDataset(ctx, notesRef.String())
+	if err != nil {
+		return hash.Hash{}, false, err
+	}
+	addr, ok := ds.MaybeHeadAddr()
+	return addr, ok, nil
+}
+
+// MergeNotes adds the notes of the map at |addr|, which must already be present in this database's chunk store, to
+// the notes of the database. Notes of the same commit are replaced by the incoming ones.
+func (ddb *DoltDB) MergeNotes(ctx context.Context, addr hash.Hash) error {
+	node, err := ddb.NodeStore().Read(ctx, addr)
+	if err != nil {
+		return err
+	}
+	incoming := prolly.NewMap(node, ddb.NodeStore(), notesKeyDesc, notesValDesc)
+
+	return ddb.updateMapAtRef(ctx, notesRef, notesKeyDesc, notesValDesc, func(m *prolly.MutableMap) error {
+		iter, err := incoming.IterAll(ctx)
+		if err != nil {
+			return err
+		}
+		for {
+			k, v, err := iter.Next(ctx)
+			if err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			if err = m.Put(ctx, k, v); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+func noteKey(ddb *DoltDB, commitHash hash.Hash) (val.Tuple, error) {
+	kb := val.NewTupleBuilder(notesKeyDesc)
+	if err := kb.PutString(0, commitHash.String()); err != nil {
+		return nil, err
+	}
+	return kb.Build(ddb.NodeStore().Pool()), nil
+}
+
+func noteMeta(v val.Tuple) *datas.TagMeta {
+	name, _ := notesValDesc.GetString(0, v)
+	email, _ := notesValDesc.GetString(1, v)
+	ts, _ := notesValDesc.GetUint64(2, v)
+	userTS, _ := notesValDesc.GetInt64(3, v)
+	desc, _ := notesValDesc.GetString(4, v)
+	return &datas.TagMeta{Name: name, Email: email, Timestamp: ts, UserTimestamp: userTS, Description: desc}
+}
@@ -0,0 +1,77 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestNotes(t *testing.T) {
+	ctx := context.Background()
+	ddb, err := LoadDoltDB(ctx, types.Format_Default, InMemDoltDB, filesys.LocalFS)
+	require.NoError(t, err)
+	require.NoError(t, ddb.WriteEmptyRepo(ctx, defaultBranch, "Bill Billerson", "bigbillieb@fake.horse"))
+
+	cs, err := NewCommitSpec(defaultBranch)
+	require.NoError(t, err)
+	optCmt, err := ddb.Resolve(ctx, cs, nil)
+	require.NoError(t, err)
+	cm, ok := optCmt.ToCommit()
+	require.True(t, ok)
+	h, err := cm.HashOf()
+	require.NoError(t, err)
+
+	_, ok, err = ddb.NotesAddr(ctx)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	_, err = ddb.ResolveNote(ctx, h)
+	assert.ErrorIs(t, err, ErrNoteNotFound)
+
+	require.NoError(t, ddb.SetNote(ctx, cm, datas.NewTagMeta("Bill Billerson", "bigbillieb@fake.horse", "ticket 123")))
+	note, err := ddb.ResolveNote(ctx, h)
+	require.NoError(t, err)
+	assert.Equal(t, h, note.CommitHash)
+	assert.Equal(t, "ticket 123", note.Meta.Description)
+	assert.Equal(t, "Bill Billerson", note.Meta.Name)
+	addr, ok, err := ddb.NotesAddr(ctx)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	require.NoError(t, ddb.SetNote(ctx, cm, datas.NewTagMeta("Bill Billerson", "bigbillieb@fake.horse", "ticket 456")))
+	notes, err := ddb.GetNotes(ctx)
+	require.NoError(t, err)
+	require.Len(t, notes, 1)
+	assert.Equal(t, "ticket 456", notes[0].Meta.Description)
+
+	require.NoError(t, ddb.DeleteNote(ctx, h))
+	assert.ErrorIs(t, ddb.DeleteNote(ctx, h), ErrNoteNotFound)
+	notes, err = ddb.GetNotes(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, notes)
+
+	// merging an earlier version of the notes restores the notes it holds
+	require.NoError(t, ddb.MergeNotes(ctx, addr))
+	note, err = ddb.ResolveNote(ctx, h)
+	require.NoError(t, err)
+	assert.Equal(t, "ticket 123", note.Meta.Description)
+}
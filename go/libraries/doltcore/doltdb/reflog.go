@@ -0,0 +1,567 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dconfig"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/pool"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// reflogMapName is the name of the map holding the persistent reflog of a database.
+const reflogMapName = "reflog"
+
+// maxStoredReflogMessageLength is the length at which the commit messages of archived entries are truncated.
+const maxStoredReflogMessageLength = int(val.MaxTupleDataSize) / 2
+
+var (
+	// reflogKeyDesc orders the entries of the persistent reflog by the time of the change, in unix nanoseconds, and the
+	// ref changed. The chunk journal records the time of changes with a precision of a second, so changes of the same
+	// ref at the same time are ordered by a sequence number.
+	reflogKeyDesc = val.NewTupleDescriptor(
+		val.Type{Enc: val.Int64Enc},
+		val.Type{Enc: val.StringEnc},
+		val.Type{Enc: val.Uint32Enc},
+	)
+	// reflogValDesc holds the commit the ref pointed to after the change, its message, and the address the ref pointed
+	// to, which differs from the commit for tags.
+	reflogValDesc = val.NewTupleDescriptor(
+		val.Type{Enc: val.StringEnc},
+		val.Type{Enc: val.StringEnc},
+		val.Type{Enc: val.StringEnc},
+	)
+)
+
+// reflogArchiveInterval is how often the persistent reflog archives the roots recorded by the chunk journal, see
+// MaybeArchiveReflog.
+const reflogArchiveInterval = 5 * time.Minute
+
+// reflogArchiveThreshold is the number of roots recorded by the chunk journal after which the persistent reflog
+// archives them without waiting for reflogArchiveInterval, which bounds the memory the chunk journal's reflog uses.
+const reflogArchiveThreshold = 1_000
+
+// ReflogPolicy configures the persistent reflog. The reflog is read from the roots recorded by the chunk journal,
+// which only keeps the most recent roots in memory, and which garbage collection discards. When the reflog is
+// persistent, the chunk journal keeps every root until its entries are archived in a map stored in the database, so
+// that they survive restarts and garbage collection, are included in backups, and are replicated along with the
+// database.
+type ReflogPolicy struct {
+	// Persistent enables the persistent reflog.
+	Persistent bool
+	// MaxAge is the age after which entries are dropped from the persistent reflog. Zero means no limit.
+	MaxAge time.Duration
+	// MaxEntries is the number of most recent entries kept in the persistent reflog. Zero means no limit.
+	MaxEntries int
+}
+
+// defaultReflogPolicy is the policy of databases whose policy is not set with SetReflogPolicy.
+var defaultReflogPolicy = ReflogPolicy{Persistent: os.Getenv(dconfig.EnvPersistentReflog) != ""}
+
+// reflogArchiver holds the reflog policy of a DoltDB and when its reflog was last archived.
+type reflogArchiver struct {
+	policy atomic.Pointer[ReflogPolicy]
+	// last is the time of the last archive, in unix nanoseconds
+	last atomic.Int64
+}
+
+// SetReflogPolicy sets the policy of the reflog of this database.
+func (ddb *DoltDB) SetReflogPolicy(policy ReflogPolicy) {
+	ddb.reflogArchiver.policy.Store(&policy)
+	if journal := ddb.ChunkJournal(); journal != nil {
+		journal.SetPersistentReflog(policy.Persistent)
+	}
+}
+
+// ReflogPolicy returns the policy of the reflog of this database.
+func (ddb *DoltDB) ReflogPolicy() ReflogPolicy {
+	if policy := ddb.reflogArchiver.policy.Load(); policy != nil {
+		return *policy
+	}
+	return defaultReflogPolicy
+}
+
+// ReflogEntry is a change of the commit a ref points to, as recorded in the reflog.
+type ReflogEntry struct {
+	// Ref is the full path of the ref, e.g. refs/heads/main.
+	Ref string
+	// Timestamp is when the ref changed, or nil if the change was recorded by a version of Dolt which did not record
+	// the time of changes.
+	Timestamp *time.Time
+	// CommitHash is the hash of the commit the ref pointed to after the change.
+	CommitHash hash.Hash
+	// CommitMessage is the description of the commit the ref pointed to after the change.
+	CommitMessage string
+}
+
+// reflogState is what the persistent reflog records of the refs of a database, which is used to read the entries of
+// the roots recorded by the chunk journal since then.
+type reflogState struct {
+	// heads are the addresses each ref pointed to as of its last archived entry, including refs deleted since.
+	heads map[string]hash.Hash
+	// last is the time of the most recent archived entry.
+	last time.Time
+}
+
+// Reflog returns the entries of the reflog of this database, from the oldest to the most recent. Entries of the
+// persistent reflog come first, followed by the entries read from the roots recorded by the chunk journal which have
+// not been archived yet.
+func (ddb *DoltDB) Reflog(ctx context.Context) ([]ReflogEntry, error) {
+	state := &reflogState{heads: make(map[string]hash.Hash)}
+	var entries []ReflogEntry
+	if types.IsFormat_DOLT(ddb.Format()) {
+		m, err := ddb.GetMap(ctx, reflogMapName, reflogKeyDesc, reflogValDesc)
+		if err != nil {
+			return nil, err
+		}
+		if state, err = readReflogState(ctx, m); err != nil {
+			return nil, err
+		}
+		if entries, err = archivedReflogEntries(ctx, m, ddb.ReflogPolicy(), time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	live, _, err := ddb.journalReflog(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(entries, live...), nil
+}
+
+// ArchivedReflog returns the entries of the persistent reflog of this database, from the oldest to the most recent.
+func (ddb *DoltDB) ArchivedReflog(ctx context.Context) ([]ReflogEntry, error) {
+	if !types.IsFormat_DOLT(ddb.Format()) {
+		return nil, nil
+	}
+	m, err := ddb.GetMap(ctx, reflogMapName, reflogKeyDesc, reflogValDesc)
+	if err != nil {
+		return nil, err
+	}
+	return archivedReflogEntries(ctx, m, ddb.ReflogPolicy(), time.Now())
+}
+
+// ArchiveReflog adds the entries read from the roots recorded by the chunk journal to the persistent reflog, drops
+// the entries the retention policy no longer keeps, and then drops the archived roots from the chunk journal's
+// reflog. It does nothing unless the persistent reflog is enabled.
+func (ddb *DoltDB) ArchiveReflog(ctx context.Context) error {
+	policy := ddb.ReflogPolicy()
+	journal := ddb.ChunkJournal()
+	if !policy.Persistent || !types.IsFormat_DOLT(ddb.Format()) || journal == nil {
+		return nil
+	}
+	ddb.reflogArchiver.last.Store(time.Now().UnixNano())
+
+	var lastRoot hash.Hash
+	err := ddb.UpdateMap(ctx, reflogMapName, reflogKeyDesc, reflogValDesc, func(m *prolly.MutableMap) error {
+		state, err := readReflogState(ctx, m)
+		if err != nil {
+			return err
+		}
+
+		var live []ReflogEntry
+		live, lastRoot, err = ddb.journalReflog(ctx, state)
+		if err != nil {
+			return err
+		}
+		for _, entry := range live {
+			if err = putReflogEntry(ctx, m, ddb.NodeStore().Pool(), entry, state.heads[entry.Ref]); err != nil {
+				return err
+			}
+		}
+		return prune(ctx, m, policy, time.Now())
+	})
+	if err != nil {
+		return err
+	}
+
+	if !lastRoot.IsEmpty() {
+		journal.TrimReflog(lastRoot)
+	}
+	return nil
+}
+
+// MaybeArchiveReflog archives the reflog of this database with ArchiveReflog if it was last archived more than
+// reflogArchiveInterval ago, or if the chunk journal holds more than reflogArchiveThreshold roots which have not been
+// archived yet. It is called periodically by servers, so that the roots recorded by the chunk journal don't
+// accumulate in memory and the persistent reflog replicated to backups and read replicas stays current.
+func (ddb *DoltDB) MaybeArchiveReflog(ctx context.Context) error {
+	journal := ddb.ChunkJournal()
+	if !ddb.ReflogPolicy().Persistent || journal == nil {
+		return nil
+	}
+	pending := journal.ReflogLen()
+	if pending == 0 {
+		return nil
+	}
+	last := time.Unix(0, ddb.reflogArchiver.last.Load())
+	if pending < reflogArchiveThreshold && time.Since(last) < reflogArchiveInterval {
+		return nil
+	}
+	return ddb.ArchiveReflog(ctx)
+}
+
+// ReplicateReflog mirrors the persistent reflog of |srcDB| into this database, so that a read replica shares the
+// reflog of the database it replicates. Push replication and cluster replication copy it along with the other
+// datasets of the database.
+func (ddb *DoltDB) ReplicateReflog(ctx context.Context, tempDir string, srcDB *DoltDB) error {
+	if !types.IsFormat_DOLT(ddb.Format()) {
+		return nil
+	}
+
+	mapRef := ref.NewMapRef(reflogMapName).String()
+	srcDS, err := srcDB.db.GetDataset(ctx, mapRef)
+	if err != nil {
+		return err
+	}
+	addr, ok := srcDS.MaybeHeadAddr()
+	if !ok {
+		return nil
+	}
+
+	ds, err := ddb.db.GetDataset(ctx, mapRef)
+	if err != nil {
+		return err
+	}
+	prev, _ := ds.MaybeHeadAddr()
+	if prev == addr {
+		return nil
+	}
+
+	err = ddb.PullChunks(ctx, tempDir, srcDB, []hash.Hash{addr}, nil, nil)
+	if err != nil {
+		return err
+	}
+	_, err = ddb.db.SetMapHead(ctx, ds, addr, prev)
+	if errors.Is(err, datas.ErrOptimisticLockFailed) {
+		// the reflog was updated concurrently, it is replicated again on the next pull
+		return nil
+	}
+	return err
+}
+
+// unarchivedReflogRoots returns the roots recorded by the chunk journal whose entries have not been archived by the
+// persistent reflog yet. Garbage collection keeps them, so that they can still be archived after it completes.
+func (ddb *DoltDB) unarchivedReflogRoots() (hash.HashSet, error) {
+	roots := make(hash.HashSet)
+	journal := ddb.ChunkJournal()
+	if !ddb.ReflogPolicy().Persistent || !types.IsFormat_DOLT(ddb.Format()) || journal == nil {
+		return roots, nil
+	}
+
+	err := journal.IterateRoots(func(root string, _ *time.Time) error {
+		roots.Insert(hash.Parse(root))
+		return nil
+	})
+	return roots, err
+}
+
+// putReflogEntry adds |entry| to the persistent reflog |m|. |addr| is the address its ref pointed to.
+func putReflogEntry(ctx context.Context, m *prolly.MutableMap, pool pool.BuffPool, entry ReflogEntry, addr hash.Hash) error {
+	var ts int64
+	if entry.Timestamp != nil {
+		ts = entry.Timestamp.UnixNano()
+	}
+	msg := entry.CommitMessage
+	if len(msg) > maxStoredReflogMessageLength {
+		msg = msg[:maxStoredReflogMessageLength]
+	}
+
+	vb := val.NewTupleBuilder(reflogValDesc)
+	if err := vb.PutString(0, entry.CommitHash.String()); err != nil {
+		return err
+	}
+	if err := vb.PutString(1, msg); err != nil {
+		return err
+	}
+	if err := vb.PutString(2, addr.String()); err != nil {
+		return err
+	}
+	value := vb.Build(pool)
+
+	// Find the first free sequence number for changes of the ref at the same time
+	for seq := uint32(0); ; seq++ {
+		kb := val.NewTupleBuilder(reflogKeyDesc)
+		kb.PutInt64(0, ts)
+		if err := kb.PutString(1, entry.Ref); err != nil {
+			return err
+		}
+		kb.PutUint32(2, seq)
+		key := kb.Build(pool)
+
+		ok, err := m.Has(ctx, key)
+		if err != nil {
+			return err
+		} else if !ok {
+			return m.Put(ctx, key, value)
+		}
+	}
+}
+
+// reflogMapIter is implemented by both prolly.Map and prolly.MutableMap.
+type reflogMapIter interface {
+	IterAll(ctx context.Context) (prolly.MapIter, error)
+}
+
+// readReflogState returns the state of the refs recorded by the persistent reflog |m|.
+func readReflogState(ctx context.Context, m reflogMapIter) (*reflogState, error) {
+	state := &reflogState{heads: make(map[string]hash.Hash)}
+	iter, err := m.IterAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		k, v, err := iter.Next(ctx)
+		if err == io.EOF {
+			return state, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		ts, _ := reflogKeyDesc.GetInt64(0, k)
+		r, _ := reflogKeyDesc.GetString(1, k)
+		addr, _ := reflogValDesc.GetString(2, v)
+		state.heads[r] = hash.Parse(addr)
+		if ts != 0 {
+			state.last = time.Unix(0, ts)
+		}
+	}
+}
+
+// archivedReflogEntries returns the entries of the persistent reflog |m| which |policy| keeps as of |now|, from the
+// oldest to the most recent.
+func archivedReflogEntries(ctx context.Context, m prolly.Map, policy ReflogPolicy, now time.Time) ([]ReflogEntry, error) {
+	iter, err := m.IterAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []ReflogEntry
+	for {
+		k, v, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		ts, _ := reflogKeyDesc.GetInt64(0, k)
+		r, _ := reflogKeyDesc.GetString(1, k)
+		commitHash, _ := reflogValDesc.GetString(0, v)
+		msg, _ := reflogValDesc.GetString(1, v)
+		entry := ReflogEntry{
+			Ref:           r,
+			CommitHash:    hash.Parse(commitHash),
+			CommitMessage: msg,
+		}
+		if ts != 0 {
+			t := time.Unix(0, ts)
+			if policy.MaxAge > 0 && t.Before(now.Add(-policy.MaxAge)) {
+				continue
+			}
+			entry.Timestamp = &t
+		}
+		entries = append(entries, entry)
+	}
+
+	if policy.MaxEntries > 0 && len(entries) > policy.MaxEntries {
+		entries = entries[len(entries)-policy.MaxEntries:]
+	}
+	return entries, nil
+}
+
+// ReflogRootAtTime returns the root hash of the database as of |t|, which is the most recent root recorded in the
+// reflog at or before |t|. Roots are recorded by the chunk journal, so this returns false if the database has no
+// chunk journal or if the reflog does not go back as far as |t|.
+func (ddb *DoltDB) ReflogRootAtTime(t time.Time) (hash.Hash, bool, error) {
+	journal := ddb.ChunkJournal()
+	if journal == nil {
+		return hash.Hash{}, false, nil
+	}
+
+	var root hash.Hash
+	found := false
+	err := journal.IterateRoots(func(r string, timestamp *time.Time) error {
+		// Roots written by older versions of Dolt have no timestamp and can't be placed in time
+		if timestamp == nil || timestamp.After(t) {
+			return nil
+		}
+		root = hash.Parse(r)
+		found = true
+		return nil
+	})
+	if err != nil {
+		return hash.Hash{}, false, err
+	}
+
+	return root, found, nil
+}
+
+// journalReflog returns the entries read from the roots recorded by the chunk journal which have not been archived
+// in the persistent reflog with |state|, and the last root read. An entry is returned for each root which changed a
+// ref, including refs which have since been deleted. Working sets and refs which don't point to a commit are skipped.
+// |state.heads| is updated with the address of each changed ref.
+func (ddb *DoltDB) journalReflog(ctx context.Context, state *reflogState) ([]ReflogEntry, hash.Hash, error) {
+	journal := ddb.ChunkJournal()
+	if journal == nil {
+		return nil, hash.Hash{}, nil
+	}
+
+	type journalRoot struct {
+		root      hash.Hash
+		timestamp *time.Time
+	}
+	var roots []journalRoot
+	err := journal.IterateRoots(func(root string, timestamp *time.Time) error {
+		jr := journalRoot{root: hash.Parse(root)}
+		if timestamp != nil {
+			ts := *timestamp
+			jr.timestamp = &ts
+		}
+		roots = append(roots, jr)
+		return nil
+	})
+	if err != nil {
+		return nil, hash.Hash{}, err
+	}
+	if len(roots) == 0 {
+		return nil, hash.Hash{}, nil
+	}
+	lastRoot := roots[len(roots)-1].root
+
+	// Roots are dropped from the chunk journal's reflog once they are archived, but a journal reopened after a
+	// restart records them again. Skip the roots older than the last archived entry, the roots recorded in the same
+	// second are compared against the archived heads.
+	if !state.last.IsZero() {
+		since := state.last.Truncate(time.Second)
+		i := 0
+		for i < len(roots) && (roots[i].timestamp == nil || roots[i].timestamp.Before(since)) {
+			i++
+		}
+		roots = roots[i:]
+	}
+
+	var entries []ReflogEntry
+	for _, jr := range roots {
+		datasets, err := ddb.DatasetsByRootHash(ctx, jr.root)
+		if err != nil {
+			return nil, hash.Hash{}, fmt.Errorf("unable to look up references for root hash %s: %s",
+				jr.root.String(), err.Error())
+		}
+
+		err = datasets.IterAll(ctx, func(id string, addr hash.Hash) error {
+			// Skip working set references (WorkingSetRefs can't always be resolved to commits)
+			if ref.IsWorkingSet(id) {
+				return nil
+			}
+
+			doltRef, err := ref.Parse(id)
+			if err != nil {
+				return err
+			}
+
+			// Skip internal refs, and refs which don't point to a commit, like stashes and maps
+			if _, ok := ref.HeadRefTypes[doltRef.GetType()]; !ok || doltRef.GetType() == ref.InternalRefType {
+				return nil
+			}
+
+			// Skip refs whose address didn't change from the previous entry
+			if state.heads[id] == addr {
+				return nil
+			}
+
+			commit, err := ddb.ResolveCommitRefAtRoot(ctx, doltRef, jr.root)
+			if err != nil {
+				return err
+			}
+			commitHash, err := commit.HashOf()
+			if err != nil {
+				return err
+			}
+			commitMeta, err := commit.GetCommitMeta(ctx)
+			if err != nil {
+				return err
+			}
+
+			entries = append(entries, ReflogEntry{
+				Ref:           id,
+				Timestamp:     jr.timestamp,
+				CommitHash:    commitHash,
+				CommitMessage: commitMeta.Description,
+			})
+			state.heads[id] = addr
+			return nil
+		})
+		if err != nil {
+			return nil, hash.Hash{}, err
+		}
+	}
+
+	return entries, lastRoot, nil
+}
+
+// prune drops the entries of the persistent reflog |m| which |policy| no longer keeps as of |now|. Entries without a
+// timestamp are only dropped to keep |policy.MaxEntries| entries.
+func prune(ctx context.Context, m *prolly.MutableMap, policy ReflogPolicy, now time.Time) error {
+	if policy.MaxAge <= 0 && policy.MaxEntries <= 0 {
+		return nil
+	}
+
+	iter, err := m.IterAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	var kept, dropped []val.Tuple
+	oldest := now.Add(-policy.MaxAge).UnixNano()
+	for {
+		k, _, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		ts, _ := reflogKeyDesc.GetInt64(0, k)
+		if policy.MaxAge > 0 && ts != 0 && ts < oldest {
+			dropped = append(dropped, k)
+		} else {
+			kept = append(kept, k)
+		}
+	}
+	if policy.MaxEntries > 0 && len(kept) > policy.MaxEntries {
+		dropped = append(dropped, kept[:len(kept)-policy.MaxEntries]...)
+	}
+
+	for _, k := range dropped {
+		if err = m.Delete(ctx, k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/libraries/utils/test"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestReflogPrune(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	at := func(age time.Duration) *time.Time {
+		ts := now.Add(-age)
+		return &ts
+	}
+
+	tests := []struct {
+		name     string
+		policy   ReflogPolicy
+		expected []string
+	}{
+		{
+			name:     "no limit",
+			policy:   ReflogPolicy{},
+			expected: []string{"untimed", "oldest", "older", "old", "new"},
+		},
+		{
+			name:     "max age",
+			policy:   ReflogPolicy{MaxAge: 90 * time.Minute},
+			expected: []string{"untimed", "old", "new"},
+		},
+		{
+			name:     "max entries",
+			policy:   ReflogPolicy{MaxEntries: 2},
+			expected: []string{"old", "new"},
+		},
+		{
+			name:     "max age and max entries",
+			policy:   ReflogPolicy{MaxAge: 150 * time.Minute, MaxEntries: 3},
+			expected: []string{"older", "old", "new"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := tree.NewTestNodeStore()
+			m, err := prolly.NewMapFromTuples(ctx, ns, reflogKeyDesc, reflogValDesc)
+			require.NoError(t, err)
+			mut := m.Mutate()
+
+			entries := []ReflogEntry{
+				{Ref: "refs/heads/main", CommitMessage: "untimed"},
+				{Ref: "refs/heads/main", Timestamp: at(3 * time.Hour), CommitMessage: "oldest"},
+				{Ref: "refs/heads/main", Timestamp: at(2 * time.Hour), CommitMessage: "older"},
+				{Ref: "refs/heads/main", Timestamp: at(time.Hour), CommitMessage: "old"},
+				{Ref: "refs/heads/main", Timestamp: at(0), CommitMessage: "new"},
+			}
+			for _, entry := range entries {
+				require.NoError(t, putReflogEntry(ctx, mut, ns.Pool(), entry, hash.Of([]byte(entry.CommitMessage))))
+			}
+
+			require.NoError(t, prune(ctx, mut, tt.policy, now))
+			m, err = mut.Map(ctx)
+			require.NoError(t, err)
+			kept, err := archivedReflogEntries(ctx, m, ReflogPolicy{}, now)
+			require.NoError(t, err)
+
+			var messages []string
+			for _, entry := range kept {
+				messages = append(messages, entry.CommitMessage)
+			}
+			assert.Equal(t, tt.expected, messages)
+		})
+	}
+}
+
+func TestPutReflogEntry(t *testing.T) {
+	ctx := context.Background()
+	ns := tree.NewTestNodeStore()
+	m, err := prolly.NewMapFromTuples(ctx, ns, reflogKeyDesc, reflogValDesc)
+	require.NoError(t, err)
+	mut := m.Mutate()
+
+	// changes of the same ref recorded in the same second are all kept, in the order they were added
+	ts := time.Unix(1700000000, 0)
+	for _, msg := range []string{"first", "second", "third"} {
+		entry := ReflogEntry{Ref: "refs/heads/main", Timestamp: &ts, CommitMessage: msg}
+		require.NoError(t, putReflogEntry(ctx, mut, ns.Pool(), entry, hash.Of([]byte(msg))))
+	}
+
+	m, err = mut.Map(ctx)
+	require.NoError(t, err)
+	entries, err := archivedReflogEntries(ctx, m, ReflogPolicy{}, ts)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	for i, msg := range []string{"first", "second", "third"} {
+		assert.Equal(t, msg, entries[i].CommitMessage)
+		assert.True(t, ts.Equal(*entries[i].Timestamp))
+	}
+
+	state, err := readReflogState(ctx, m)
+	require.NoError(t, err)
+	assert.Equal(t, hash.Of([]byte("third")), state.heads["refs/heads/main"])
+	assert.True(t, ts.Equal(state.last))
+}
+
+func TestJournalReflog(t *testing.T) {
+	ctx := context.Background()
+	testDir, err := test.ChangeToTestDir("TestJournalReflog")
+	require.NoError(t, err)
+	require.NoError(t, filesys.LocalFS.MkDirs(filepath.Join(testDir, dbfactory.DoltDataDir)))
+
+	ddb, err := LoadDoltDB(ctx, types.Format_Default, LocalDirDoltDB, filesys.LocalFS)
+	require.NoError(t, err)
+	defer ddb.Close()
+	require.NotNil(t, ddb.ChunkJournal())
+	require.NoError(t, ddb.WriteEmptyRepo(ctx, defaultBranch, "Bill Billerson", "bigbillieb@fake.horse"))
+
+	cs, err := NewCommitSpec(defaultBranch)
+	require.NoError(t, err)
+	optCmt, err := ddb.Resolve(ctx, cs, nil)
+	require.NoError(t, err)
+	cm, ok := optCmt.ToCommit()
+	require.True(t, ok)
+	require.NoError(t, ddb.NewBranchAtCommit(ctx, ref.NewBranchRef("other"), cm, nil))
+
+	// every change of a ref is read when nothing was archived
+	state := &reflogState{heads: make(map[string]hash.Hash)}
+	entries, lastRoot, err := ddb.journalReflog(ctx, state)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"refs/heads/main", "refs/heads/other"}, refsOf(entries))
+	assert.False(t, lastRoot.IsEmpty())
+	assert.Len(t, state.heads, 2)
+
+	// refs which point to the archived addresses are skipped
+	entries, _, err = ddb.journalReflog(ctx, state)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// roots recorded before the last archived entry are skipped
+	state = &reflogState{heads: make(map[string]hash.Hash), last: time.Now().Add(time.Hour)}
+	entries, _, err = ddb.journalReflog(ctx, state)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestArchiveReflog(t *testing.T) {
+	ctx := context.Background()
+	testDir, err := test.ChangeToTestDir("TestArchiveReflog")
+	require.NoError(t, err)
+	require.NoError(t, filesys.LocalFS.MkDirs(filepath.Join(testDir, dbfactory.DoltDataDir)))
+
+	ddb, err := LoadDoltDB(ctx, types.Format_Default, LocalDirDoltDB, filesys.LocalFS)
+	require.NoError(t, err)
+	defer ddb.Close()
+	ddb.SetReflogPolicy(ReflogPolicy{Persistent: true})
+	require.NoError(t, ddb.WriteEmptyRepo(ctx, defaultBranch, "Bill Billerson", "bigbillieb@fake.horse"))
+
+	require.NoError(t, ddb.ArchiveReflog(ctx))
+	archived, err := ddb.ArchivedReflog(ctx)
+	require.NoError(t, err)
+	require.Len(t, archived, 1)
+	assert.Equal(t, "refs/heads/main", archived[0].Ref)
+
+	// archived changes are not read again from the chunk journal
+	entries, err := ddb.Reflog(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, refsOf(archived), refsOf(entries))
+
+	// changes made after archiving are read from the chunk journal until they are archived
+	cs, err := NewCommitSpec(defaultBranch)
+	require.NoError(t, err)
+	optCmt, err := ddb.Resolve(ctx, cs, nil)
+	require.NoError(t, err)
+	cm, ok := optCmt.ToCommit()
+	require.True(t, ok)
+	require.NoError(t, ddb.NewBranchAtCommit(ctx, ref.NewBranchRef("other"), cm, nil))
+
+	entries, err = ddb.Reflog(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"refs/heads/main", "refs/heads/other"}, refsOf(entries))
+
+	require.NoError(t, ddb.ArchiveReflog(ctx))
+	archived, err = ddb.ArchivedReflog(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"refs/heads/main", "refs/heads/other"}, refsOf(archived))
+	entries, err = ddb.Reflog(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, refsOf(archived), refsOf(entries))
+
+	// changes are only archived periodically once the reflog has been archived recently
+	require.NoError(t, ddb.NewBranchAtCommit(ctx, ref.NewBranchRef("third"), cm, nil))
+	require.NoError(t, ddb.MaybeArchiveReflog(ctx))
+	assert.NotZero(t, ddb.ChunkJournal().ReflogLen())
+	ddb.reflogArchiver.last.Store(time.Now().Add(-reflogArchiveInterval).UnixNano())
+	require.NoError(t, ddb.MaybeArchiveReflog(ctx))
+	archived, err = ddb.ArchivedReflog(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"refs/heads/main", "refs/heads/other", "refs/heads/third"}, refsOf(archived))
+}
+
+func refsOf(entries []ReflogEntry) []string {
+	var refs []string
+	for _, entry := range entries {
+		refs = append(refs, entry.Ref)
+	}
+	return refs
+}
@@ -548,7 +548,11 @@ func (root *rootValue) GetTable(ctx context.Context, tName TableName) (*Table, b
 		return nil, false, err
 	}
 
-	return GetTable(ctx, root, addr)
+	tbl, ok, err := GetTable(ctx, root, addr)
+	if errors.Is(err, durable.ErrTableNotFetched) {
+		return nil, false, TableNotFetchedError{Name: tName}
+	}
+	return tbl, ok, err
 }
 
 func GetTable(ctx context.Context, root RootValue, addr hash.Hash) (*Table, bool, error) {
@@ -578,9 +582,10 @@ func GetTableInsensitive(ctx context.Context, root RootValue, tName TableName) (
 	return tbl, resolvedName, ok, nil
 }
 
-// GetTableByColTag looks for the table containing the given column tag.
+// GetTableByColTag looks for the table containing the given column tag. The schemas of the tables excluded from a
+// partial clone or fetch are unknown, so they are not searched.
 func GetTableByColTag(ctx context.Context, root RootValue, tag uint64) (tbl *Table, name TableName, found bool, err error) {
-	err = root.IterTables(ctx, func(tn TableName, t *Table, s schema.Schema) (bool, error) {
+	_, err = IterFetchedTables(ctx, root, func(tn TableName, t *Table, s schema.Schema) (bool, error) {
 		_, found = s.GetAllCols().GetByTag(tag)
 		if found {
 			name, tbl = tn, t
@@ -637,7 +642,10 @@ func TablesWithDataConflicts(ctx context.Context, root RootValue) ([]TableName,
 	conflicted := make([]TableName, 0, len(names))
 	for _, name := range names {
 		tbl, _, err := root.GetTable(ctx, name)
-		if err != nil {
+		if errors.Is(err, durable.ErrTableNotFetched) {
+			// unfetched tables can't be modified, so they have no conflicts
+			continue
+		} else if err != nil {
 			return nil, err
 		}
 
@@ -663,7 +671,10 @@ func TablesWithConstraintViolations(ctx context.Context, root RootValue) ([]Tabl
 	violating := make([]TableName, 0, len(names))
 	for _, name := range names {
 		tbl, _, err := root.GetTable(ctx, name)
-		if err != nil {
+		if errors.Is(err, durable.ErrTableNotFetched) {
+			// unfetched tables can't be modified, so they have no constraint violations
+			continue
+		} else if err != nil {
 			return nil, err
 		}
 
@@ -699,7 +710,8 @@ func HasConstraintViolations(ctx context.Context, root RootValue) (bool, error)
 	return len(tbls) > 0, nil
 }
 
-// IterTables calls the callback function cb on each table in this RootValue.
+// IterTables calls the callback function cb on each table in this RootValue. A TableNotFetchedError is returned when
+// a table which was excluded from a partial clone or fetch is reached, see IterFetchedTables.
 func (root *rootValue) IterTables(ctx context.Context, cb func(name TableName, table *Table, sch schema.Schema) (stop bool, err error)) error {
 	schemaNames, err := schemaNames(ctx, root)
 	if err != nil {
@@ -714,7 +726,9 @@ func (root *rootValue) IterTables(ctx context.Context, cb func(name TableName, t
 
 		err = tm.Iter(ctx, func(name string, addr hash.Hash) (bool, error) {
 			nt, err := durable.TableFromAddr(ctx, root.VRW(), root.ns, addr)
-			if err != nil {
+			if errors.Is(err, durable.ErrTableNotFetched) {
+				return true, TableNotFetchedError{Name: TableName{Name: name, Schema: schemaName}}
+			} else if err != nil {
 				return true, err
 			}
 			tbl := &Table{table: nt}
@@ -734,6 +748,45 @@ func (root *rootValue) IterTables(ctx context.Context, cb func(name TableName, t
 	return nil
 }
 
+// IterFetchedTables calls |cb| on each table of |root| like IterTables, skipping the tables which were excluded from a
+// partial clone or fetch instead of failing on them. The names and addresses of the skipped tables are returned, so that
+// callers can account for them.
+func IterFetchedTables(ctx context.Context, root RootValue, cb func(name TableName, table *Table, sch schema.Schema) (stop bool, err error)) (map[TableName]hash.Hash, error) {
+	names, err := UnionTableNames(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+
+	unfetched := make(map[TableName]hash.Hash)
+	for _, name := range names {
+		tbl, ok, err := root.GetTable(ctx, name)
+		if errors.Is(err, durable.ErrTableNotFetched) {
+			addr, _, err := root.GetTableHash(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			unfetched[name] = addr
+			continue
+		} else if err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+
+		sch, err := tbl.GetSchema(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if stop, err := cb(name, tbl, sch); err != nil {
+			return nil, err
+		} else if stop {
+			break
+		}
+	}
+
+	return unfetched, nil
+}
+
 func (root *rootValue) withStorage(st rootValueStorage) *rootValue {
 	return &rootValue{root.vrw, root.ns, st, nil, hash.Hash{}, 0, nil}
 }
@@ -1114,7 +1167,11 @@ func ValidateForeignKeysOnSchemas(ctx context.Context, root RootValue) (RootValu
 	allTablesSet := make(map[TableName]schema.Schema)
 	for _, tableName := range allTablesSlice {
 		tbl, ok, err := root.GetTable(ctx, tableName)
-		if err != nil {
+		if errors.Is(err, durable.ErrTableNotFetched) {
+			// the schema of an unfetched table is unknown, but it can't have been changed since it was committed
+			allTablesSet[tableName] = nil
+			continue
+		} else if err != nil {
 			return nil, err
 		}
 		if !ok {
@@ -1132,15 +1189,19 @@ func ValidateForeignKeysOnSchemas(ctx context.Context, root RootValue) (RootValu
 	for _, foreignKey := range allForeignKeys {
 		tblSch, existsInRoot := allTablesSet[foreignKey.TableName]
 		if existsInRoot {
-			if err := foreignKey.ValidateTableSchema(tblSch); err != nil {
-				return nil, err
+			if tblSch != nil {
+				if err := foreignKey.ValidateTableSchema(tblSch); err != nil {
+					return nil, err
+				}
 			}
 			parentSch, existsInRoot := allTablesSet[foreignKey.ReferencedTableName]
 			if !existsInRoot {
 				return nil, fmt.Errorf("foreign key `%s` requires the referenced table `%s`", foreignKey.Name, foreignKey.ReferencedTableName)
 			}
-			if err := foreignKey.ValidateReferencedTableSchema(parentSch); err != nil {
-				return nil, err
+			if parentSch != nil {
+				if err := foreignKey.ValidateReferencedTableSchema(parentSch); err != nil {
+					return nil, err
+				}
 			}
 		} else {
 			if !fkCollection.RemoveKeyByName(foreignKey.Name) {
@@ -1152,14 +1213,15 @@ func ValidateForeignKeysOnSchemas(ctx context.Context, root RootValue) (RootValu
 	return root.PutForeignKeyCollection(ctx, fkCollection)
 }
 
-// GetAllTagsForRoots gets all tags for |roots|.
+// GetAllTagsForRoots gets all tags for |roots|. The tags of the tables excluded from a partial clone or fetch are
+// unknown, so they are not included.
 func GetAllTagsForRoots(ctx context.Context, roots ...RootValue) (tags schema.TagMapping, err error) {
 	tags = make(schema.TagMapping)
 	for _, root := range roots {
 		if root == nil {
 			continue
 		}
-		err = root.IterTables(ctx, func(tblName TableName, _ *Table, sch schema.Schema) (stop bool, err error) {
+		_, err = IterFetchedTables(ctx, root, func(tblName TableName, _ *Table, sch schema.Schema) (stop bool, err error) {
 			for _, t := range sch.GetAllCols().Tags {
 				// TODO: schema names
 				tags.Add(t, tblName.Name)
@@ -1317,7 +1379,7 @@ func (root *rootValue) DebugString(ctx context.Context, transitive bool) string
 
 	if transitive {
 		buf.WriteString("\nTables:")
-		root.IterTables(ctx, func(name TableName, table *Table, sch schema.Schema) (stop bool, err error) {
+		IterFetchedTables(ctx, root, func(name TableName, table *Table, sch schema.Schema) (stop bool, err error) {
 			buf.WriteString("\nTable ")
 			buf.WriteString(name.Name)
 			buf.WriteString(":\n")
@@ -1331,6 +1393,35 @@ func (root *rootValue) DebugString(ctx context.Context, transitive bool) string
 	return buf.String()
 }
 
+// GetUnfetchedTableNames returns the names of the tables in |schemaName| which were excluded from a partial clone or
+// fetch, and have not been fetched since.
+func GetUnfetchedTableNames(ctx context.Context, root RootValue, schemaName string) ([]string, error) {
+	names, err := root.GetTableNames(ctx, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := make(hash.HashSlice, len(names))
+	for i, name := range names {
+		addrs[i], _, err = root.GetTableHash(ctx, TableName{Name: name, Schema: schemaName})
+		if err != nil {
+			return nil, err
+		}
+	}
+	vals, err := root.VRW().ReadManyValues(ctx, addrs)
+	if err != nil {
+		return nil, err
+	}
+
+	var unfetched []string
+	for i, val := range vals {
+		if _, ok := val.(types.GhostValue); ok {
+			unfetched = append(unfetched, names[i])
+		}
+	}
+	return unfetched, nil
+}
+
 // MapTableHashes returns a map of each table name and hash.
 func MapTableHashes(ctx context.Context, root RootValue) (map[TableName]hash.Hash, error) {
 	names, err := UnionTableNames(ctx, root)
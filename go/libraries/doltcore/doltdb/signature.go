@@ -0,0 +1,195 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/utils/gpg"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+const (
+	sigPayloadDb      = "db: "
+	sigPayloadMessage = "Message: "
+	sigPayloadName    = "Name: "
+	sigPayloadEmail   = "Email: "
+	sigPayloadDate    = "Date: "
+	sigPayloadParents = "Parents: "
+	sigPayloadHead    = "Head: "
+	sigPayloadStaged  = "Staged: "
+	sigPayloadVersion = "Version: "
+	sigPayloadTag     = "Tag: "
+	sigPayloadCommit  = "Commit: "
+)
+
+// commitSignaturePayloadVersion is the version of the payloads written by CommitSignaturePayload. Payloads without a
+// version were written by earlier versions of Dolt, and don't bind the commit's parents or date.
+const commitSignaturePayloadVersion = "2"
+
+// CommitSignaturePayload returns the message that is signed to sign a commit of the |staged| root with the given
+// commit metadata. The commit's |parents| and author |date| are part of the payload, so that a signature can't be
+// reused for a commit with different history.
+func CommitSignaturePayload(dbName, message, name, email string, date time.Time, parents []hash.Hash, staged hash.Hash) string {
+	lines := []string{
+		sigPayloadDb + dbName,
+		sigPayloadMessage + message,
+		sigPayloadName + name,
+		sigPayloadEmail + email,
+		sigPayloadDate + strconv.FormatInt(date.UnixMilli(), 10),
+		sigPayloadParents + joinHashes(parents),
+		sigPayloadStaged + staged.String(),
+		sigPayloadVersion + commitSignaturePayloadVersion,
+	}
+	return strings.Join(lines, "\n")
+}
+
+func joinHashes(hashes []hash.Hash) string {
+	strs := make([]string, len(hashes))
+	for i, h := range hashes {
+		strs[i] = h.String()
+	}
+	return strings.Join(strs, " ")
+}
+
+// TagSignaturePayload returns the message that is signed to sign the tag |tagName| of the commit |commitAddr|
+func TagSignaturePayload(tagName string, commitAddr hash.Hash, meta *datas.TagMeta) string {
+	lines := []string{
+		sigPayloadTag + tagName,
+		sigPayloadCommit + commitAddr.String(),
+		sigPayloadName + meta.Name,
+		sigPayloadEmail + meta.Email,
+		sigPayloadDate + strconv.FormatInt(meta.UserTimestamp, 10),
+		sigPayloadMessage + meta.Description,
+	}
+	return strings.Join(lines, "\n")
+}
+
+// VerifyCommitSignature verifies the signature of |cm|. The signature is only good if it was made by a key trusted by
+// |signers| for the commit's email, and the signed payload describes |cm|.
+func VerifyCommitSignature(ctx context.Context, cm *Commit, signers *gpg.AllowedSigners) (*gpg.Verification, error) {
+	meta, err := cm.GetCommitMeta(ctx)
+	if err != nil {
+		return nil, err
+	}
+	root, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rootHash, err := root.HashOf()
+	if err != nil {
+		return nil, err
+	}
+	parents, err := cm.ParentHashes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return VerifyCommitMetaSignature(meta, parents, rootHash, signers), nil
+}
+
+// VerifyCommitMetaSignature verifies the signature of a commit with |meta| and |parents| of the root |rootHash|, which
+// may not have been written yet.
+func VerifyCommitMetaSignature(meta *datas.CommitMeta, parents []hash.Hash, rootHash hash.Hash, signers *gpg.AllowedSigners) *gpg.Verification {
+	v := gpg.VerifySignature([]byte(meta.Signature), signers, meta.Email)
+	if v.Status != gpg.SignatureStatusGood && v.Status != gpg.SignatureStatusUntrusted {
+		return v
+	}
+
+	if reason := checkCommitSignaturePayload(string(v.Payload), meta, parents, rootHash); reason != "" {
+		v.Status = gpg.SignatureStatusBad
+		v.Reason = reason
+	}
+	return v
+}
+
+// checkCommitSignaturePayload checks that |payload| was created by CommitSignaturePayload for a commit with |meta| and
+// |parents| of the root |rootHash|, returning the reason it doesn't match if it does not. Legacy payloads, which have no
+// version, are checked against the fields they contain.
+func checkCommitSignaturePayload(payload string, meta *datas.CommitMeta, parents []hash.Hash, rootHash hash.Hash) string {
+	lines := strings.Split(payload, "\n")
+	trailing := []string{sigPayloadName, sigPayloadEmail, sigPayloadDate, sigPayloadParents, sigPayloadStaged, sigPayloadVersion}
+	legacy := !strings.HasPrefix(lines[len(lines)-1], sigPayloadVersion)
+	if legacy {
+		trailing = []string{sigPayloadName, sigPayloadEmail, sigPayloadDate, sigPayloadHead, sigPayloadStaged}
+	}
+	if len(lines) < len(trailing)+2 || !strings.HasPrefix(lines[0], sigPayloadDb) || !strings.HasPrefix(lines[1], sigPayloadMessage) {
+		return "signed payload is not a commit"
+	}
+
+	// the message can span multiple lines, so the remaining fields are read from the end of the payload
+	n := len(lines) - len(trailing)
+	fields := make(map[string]string)
+	for i, prefix := range trailing {
+		line := lines[n+i]
+		if !strings.HasPrefix(line, prefix) {
+			return "signed payload is not a commit"
+		}
+		fields[prefix] = strings.TrimPrefix(line, prefix)
+	}
+	message := strings.TrimPrefix(strings.Join(lines[1:n], "\n"), sigPayloadMessage)
+
+	switch {
+	case !legacy && strings.TrimSpace(fields[sigPayloadVersion]) != commitSignaturePayloadVersion:
+		return fmt.Sprintf("unsupported signed payload version %s", strings.TrimSpace(fields[sigPayloadVersion]))
+	case trimLineEnds(strings.TrimSpace(message)) != trimLineEnds(meta.Description):
+		return "signed message does not match the commit message"
+	case strings.TrimSpace(fields[sigPayloadName]) != meta.Name || strings.TrimSpace(fields[sigPayloadEmail]) != meta.Email:
+		return "signed author does not match the commit author"
+	case fields[sigPayloadStaged] != rootHash.String():
+		return "signed data does not match the commit's data"
+	case legacy:
+		// legacy payloads sign the date in the signer's local format, and don't sign the parents
+		return ""
+	case strings.TrimSpace(fields[sigPayloadDate]) != strconv.FormatInt(meta.UserTimestamp, 10):
+		return "signed date does not match the commit date"
+	case strings.TrimSpace(fields[sigPayloadParents]) != joinHashes(parents):
+		return "signed parents do not match the commit's parents"
+	}
+	return ""
+}
+
+// VerifyTagSignature verifies the signature of |t|. The signature is only good if it was made by a key trusted by
+// |signers| for the tagger's email, and the signed payload describes |t|.
+func VerifyTagSignature(ctx context.Context, t *Tag, signers *gpg.AllowedSigners) (*gpg.Verification, error) {
+	v := gpg.VerifySignature([]byte(t.Meta.Signature), signers, t.Meta.Email)
+	if v.Status != gpg.SignatureStatusGood && v.Status != gpg.SignatureStatusUntrusted {
+		return v, nil
+	}
+
+	commitAddr, err := t.Commit.HashOf()
+	if err != nil {
+		return nil, err
+	}
+	if trimLineEnds(string(v.Payload)) != trimLineEnds(TagSignaturePayload(t.Name, commitAddr, t.Meta)) {
+		v.Status = gpg.SignatureStatusBad
+		v.Reason = fmt.Sprintf("signed payload does not match tag %s", t.Name)
+	}
+	return v, nil
+}
+
+// trimLineEnds removes trailing whitespace from each line of |s|. OpenPGP clear signatures don't sign trailing
+// whitespace, so it is ignored when comparing signed payloads.
+func trimLineEnds(s string) string {
+	lines := strings.Split(s, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " \t\r")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestCheckCommitSignaturePayload(t *testing.T) {
+	date := time.UnixMilli(1722533002000)
+	root := hash.Of([]byte("root"))
+	parents := []hash.Hash{hash.Of([]byte("parent"))}
+	meta := &datas.CommitMeta{Name: "Test User", Email: "test@dolthub.com", Description: "message\nbody", UserTimestamp: date.UnixMilli()}
+
+	payload := CommitSignaturePayload("db", meta.Description, meta.Name, meta.Email, date, parents, root)
+	assert.Empty(t, checkCommitSignaturePayload(payload, meta, parents, root))
+	assert.Equal(t, "signed data does not match the commit's data", checkCommitSignaturePayload(payload, meta, parents, hash.Of([]byte("other"))))
+	assert.Equal(t, "signed parents do not match the commit's parents", checkCommitSignaturePayload(payload, meta, nil, root))
+	later := *meta
+	later.UserTimestamp++
+	assert.Equal(t, "signed date does not match the commit date", checkCommitSignaturePayload(payload, &later, parents, root))
+	future := strings.Replace(payload, sigPayloadVersion+commitSignaturePayloadVersion, sigPayloadVersion+"3", 1)
+	assert.Equal(t, "unsupported signed payload version 3", checkCommitSignaturePayload(future, meta, parents, root))
+
+	// payloads signed by earlier versions of Dolt have no version, and sign the date as formatted by the signer
+	legacy := strings.Join([]string{
+		sigPayloadDb + "db",
+		sigPayloadMessage + meta.Description,
+		sigPayloadName + meta.Name,
+		sigPayloadEmail + meta.Email,
+		sigPayloadDate + date.String(),
+		sigPayloadHead + hash.Of([]byte("head")).String(),
+		sigPayloadStaged + root.String(),
+	}, "\n")
+	assert.Empty(t, checkCommitSignaturePayload(legacy, meta, parents, root))
+	assert.Equal(t, "signed data does not match the commit's data", checkCommitSignaturePayload(legacy, meta, parents, hash.Of([]byte("other"))))
+	other := *meta
+	other.Name = "Other User"
+	assert.Equal(t, "signed author does not match the commit author", checkCommitSignaturePayload(legacy, &other, parents, root))
+	assert.Equal(t, "signed payload is not a commit", checkCommitSignaturePayload("Tag: v1", meta, parents, root))
+}
@@ -191,6 +191,8 @@ var getWriteableSystemTables = func() []string {
 		SchemasTableName,
 		ProceduresTableName,
 		IgnoreTableName,
+		MergePoliciesTableName,
+		MergeResolutionsTableName,
 		GetRebaseTableName(),
 
 		// TODO: find way to make these writable by the dolt process
@@ -423,14 +425,32 @@ const (
 	// TagsTableName is the tags table name
 	TagsTableName = "dolt_tags"
 
+	// NotesTableName is the name of the system table listing the notes attached to commits
+	NotesTableName = "dolt_notes"
+
+	// UnfetchedTablesTableName is the name of the system table listing the tables not fetched by a partial clone
+	UnfetchedTablesTableName = "dolt_unfetched_tables"
+
 	// IgnoreTableName is the ignore table name
 	IgnoreTableName = "dolt_ignore"
 
+	// MergePoliciesTableName is the name of the table declaring how merge resolves conflicting cells
+	MergePoliciesTableName = "dolt_merge_policies"
+
+	// MergeResolutionsTableName is the name of the table recording the cells resolved by merge policies
+	MergeResolutionsTableName = "dolt_merge_resolutions"
+
 	// RebaseTableName is the rebase system table name.
 	RebaseTableName = "dolt_rebase"
 
 	// StatisticsTableName is the statistics system table name
 	StatisticsTableName = "dolt_statistics"
+
+	// CIRunsTableName is the dolt CI workflow runs system table name
+	CIRunsTableName = "dolt_ci_runs"
+
+	// CIRunStepsTableName is the dolt CI workflow run steps system table name
+	CIRunStepsTableName = "dolt_ci_run_steps"
 )
 
 const (
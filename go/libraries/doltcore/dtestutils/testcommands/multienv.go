@@ -191,7 +191,7 @@ func (mr *MultiRepoTestSetup) CloneDB(fromRemote, dbName string) {
 	}
 
 	dEnv := env.Load(context.Background(), mr.homeProv, filesys.LocalFS, doltdb.LocalDirDoltDB, "test")
-	dEnv, err = actions.EnvForClone(ctx, srcDB.Format(), r, cloneDir, dEnv.FS, dEnv.Version, mr.homeProv)
+	dEnv, err = actions.EnvForClone(ctx, srcDB, r, cloneDir, dEnv.FS, dEnv.Version, mr.homeProv)
 	if err != nil {
 		mr.Errhand(err)
 	}
@@ -37,6 +37,10 @@ func RenameBranch(ctx context.Context, dbData env.DbData, oldBranch, newBranch s
 	oldRef := ref.NewBranchRef(oldBranch)
 	newRef := ref.NewBranchRef(newBranch)
 
+	if err := CheckBranchNotInWorktree(dbData, oldRef); err != nil {
+		return err
+	}
+
 	// TODO: This function smears the branch updates across multiple commits of the datas.Database.
 
 	err := CopyBranchOnDB(ctx, dbData.Ddb, oldBranch, newBranch, force, rsc)
@@ -68,6 +72,20 @@ func RenameBranch(ctx context.Context, dbData env.DbData, oldBranch, newBranch s
 	return DeleteBranch(ctx, dbData, oldBranch, DeleteOptions{Force: true, AllowDeletingCurrentBranch: true}, remoteDbPro, rsc)
 }
 
+// CheckBranchNotInWorktree returns an error if |branch| is checked out in another worktree of the repository of
+// |dbData|, in which case it can't be checked out, renamed or deleted.
+func CheckBranchNotInWorktree(dbData env.DbData, branch ref.DoltRef) error {
+	br, ok := branch.(ref.BranchRef)
+	if !ok {
+		return nil
+	}
+	wc, ok := dbData.Rsr.(env.WorktreeChecker)
+	if !ok {
+		return nil
+	}
+	return wc.CheckBranchNotCheckedOut(br)
+}
+
 func CopyBranch(ctx context.Context, dEnv *env.DoltEnv, oldBranch, newBranch string, force bool) error {
 	return CopyBranchOnDB(ctx, dEnv.DoltDB, oldBranch, newBranch, force, nil)
 }
@@ -132,6 +150,9 @@ func DeleteBranch(ctx context.Context, dbData env.DbData, brName string, opts De
 		if !opts.AllowDeletingCurrentBranch && ref.Equals(headRef, branchRef) {
 			return ErrCOBranchDelete.New(brName)
 		}
+		if err = CheckBranchNotInWorktree(dbData, branchRef); err != nil {
+			return err
+		}
 	}
 
 	return DeleteBranchOnDB(ctx, dbData, branchRef, opts, remoteDbPro, rsc)
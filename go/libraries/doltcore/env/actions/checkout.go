@@ -23,7 +23,6 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
-	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/resolve"
 	"github.com/dolthub/dolt/go/store/datas"
 	"github.com/dolthub/dolt/go/store/hash"
@@ -397,22 +396,27 @@ func mergeForeignKeyChanges(
 
 	conflicts := doltdb.NewTableNameSet(nil)
 
-	err := newRoot.IterTables(ctx, func(tblName doltdb.TableName, tbl *doltdb.Table, sch schema.Schema) (stop bool, err error) {
+	// the foreign keys of a table are stored in the root, so tables excluded from a partial clone or fetch are merged too
+	newNames, err := doltdb.UnionTableNames(ctx, newRoot)
+	if err != nil {
+		return nil, err
+	}
+	for _, tblName := range newNames {
 		oldFksForTable, _ := oldFks.KeysForTable(tblName)
 		newFksForTable, _ := newFks.KeysForTable(tblName)
 		changedFksForTable, _ := changedFks.KeysForTable(tblName)
 
 		oldHash, err := doltdb.CombinedHash(oldFksForTable)
 		if err != nil {
-			return true, err
+			return nil, err
 		}
 		newHash, err := doltdb.CombinedHash(newFksForTable)
 		if err != nil {
-			return true, err
+			return nil, err
 		}
 		changedHash, err := doltdb.CombinedHash(changedFksForTable)
 		if err != nil {
-			return true, err
+			return nil, err
 		}
 
 		if oldHash == changedHash {
@@ -424,25 +428,24 @@ func mergeForeignKeyChanges(
 		} else {
 			conflicts.Add(tblName)
 		}
+	}
 
-		return false, nil
-	})
+	changedNames, err := doltdb.UnionTableNames(ctx, changedRoot)
 	if err != nil {
 		return nil, err
 	}
-
-	err = changedRoot.IterTables(ctx, func(tblName doltdb.TableName, table *doltdb.Table, sch schema.Schema) (stop bool, err error) {
+	for _, tblName := range changedNames {
 		if _, exists := fksByTable[tblName]; !exists {
 			oldKeys, _ := oldFks.KeysForTable(tblName)
 			oldHash, err := doltdb.CombinedHash(oldKeys)
 			if err != nil {
-				return true, err
+				return nil, err
 			}
 
 			changedKeys, _ := changedFks.KeysForTable(tblName)
 			changedHash, err := doltdb.CombinedHash(changedKeys)
 			if err != nil {
-				return true, err
+				return nil, err
 			}
 
 			if oldHash == emptyHash {
@@ -453,11 +456,6 @@ func mergeForeignKeyChanges(
 				conflicts.Add(tblName)
 			}
 		}
-
-		return false, nil
-	})
-	if err != nil {
-		return nil, err
 	}
 
 	if conflicts.Size() > 0 {
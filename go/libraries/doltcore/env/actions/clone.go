@@ -56,8 +56,8 @@ var ErrUserNotFound = errors.New("could not determine user name. run dolt config
 var ErrEmailNotFound = errors.New("could not determine email. run dolt config --global --add user.email")
 var ErrCloneFailed = errors.New("clone failed")
 
-// EnvForClone creates a new DoltEnv and configures it with repo state from the specified remote. The returned DoltEnv is ready for content to be cloned into it. The directory used for the new DoltEnv is determined by resolving the specified dir against the specified Filesys.
-func EnvForClone(ctx context.Context, nbf *types.NomsBinFormat, r env.Remote, dir string, fs filesys.Filesys, version string, homeProvider env.HomeDirProvider) (*env.DoltEnv, error) {
+// EnvForClone creates a new DoltEnv and configures it with repo state from the specified remote. The returned DoltEnv is ready for content to be cloned into it from srcDB. The directory used for the new DoltEnv is determined by resolving the specified dir against the specified Filesys. If srcDB is encrypted at rest, the new database is encrypted with the same configuration.
+func EnvForClone(ctx context.Context, srcDB *doltdb.DoltDB, r env.Remote, dir string, fs filesys.Filesys, version string, homeProvider env.HomeDirProvider) (*env.DoltEnv, error) {
 	exists, _ := fs.Exists(filepath.Join(dir, dbfactory.DoltDir))
 
 	if exists {
@@ -74,8 +74,16 @@ func EnvForClone(ctx context.Context, nbf *types.NomsBinFormat, r env.Remote, di
 		return nil, fmt.Errorf("%w: %s; %s", ErrFailedToAccessDir, dir, err.Error())
 	}
 
+	var params map[string]interface{}
+	encryption, err := srcDB.EncryptionConfig()
+	if err != nil {
+		return nil, err
+	} else if encryption != nil {
+		params = map[string]interface{}{dbfactory.EncryptionConfigParam: encryption}
+	}
+
 	dEnv := env.Load(ctx, homeProvider, newFs, doltdb.LocalDirDoltDB, version)
-	err = dEnv.InitRepoWithNoData(ctx, nbf)
+	err = dEnv.InitRepoWithNoData(ctx, srcDB.Format(), params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to init repo: %w", err)
 	}
@@ -163,8 +171,9 @@ func sortedKeys(m map[string]iohelp.ReadStats) []string {
 //
 // The `branch` parameter is the branch to clone. If it is empty, the default branch is used.
 func CloneRemote(ctx context.Context, srcDB *doltdb.DoltDB, remoteName, branch string, singleBranch bool, depth int, dEnv *env.DoltEnv) error {
-	// We support two forms of cloning: full and shallow. These two approaches have little in common, with the exception
-	// of the first and last steps. Determining the branch to check out and setting the working set to the checked out commit.
+	// We support two forms of cloning: full and fetch based. Shallow and partial clones are fetch based. These two
+	// approaches have little in common, with the exception of the first and last steps. Determining the branch to check
+	// out and setting the working set to the checked out commit.
 
 	srcRefHashes, branch, err := getSrcRefs(ctx, branch, srcDB, dEnv)
 	if err != nil {
@@ -183,10 +192,12 @@ func CloneRemote(ctx context.Context, srcDB *doltdb.DoltDB, remoteName, branch s
 	var checkedOutCommit *doltdb.Commit
 
 	// Step 1) Pull the remote information we care about to a local disk.
-	if depth <= 0 {
-		checkedOutCommit, err = fullClone(ctx, srcDB, dEnv, srcRefHashes, branch, remoteName, singleBranch)
-	} else {
+	if depth > 0 {
 		checkedOutCommit, err = shallowCloneDataPull(ctx, dEnv.DbData(), srcDB, remoteName, branch, depth)
+	} else if isPartialClone(dEnv, remoteName) {
+		checkedOutCommit, err = partialCloneDataPull(ctx, dEnv.DbData(), srcDB, remoteName, branch, singleBranch)
+	} else {
+		checkedOutCommit, err = fullClone(ctx, srcDB, dEnv, srcRefHashes, branch, remoteName, singleBranch)
 	}
 
 	if err != nil {
@@ -363,6 +374,63 @@ func shallowCloneDataPull(ctx context.Context, destData env.DbData, srcDB *doltd
 	return cmt, nil
 }
 
+// isPartialClone returns whether the remote named |remoteName| has a table filter, in which case the clone only
+// fetches the selected tables.
+func isPartialClone(dEnv *env.DoltEnv, remoteName string) bool {
+	remotes, err := dEnv.GetRemotes()
+	if err != nil {
+		return false
+	}
+	remote, ok := remotes.Get(remoteName)
+	return ok && !remote.TableFilter.IsEmpty()
+}
+
+// partialCloneDataPull is a partial clone specific helper function which fetches the branches of the remote, or only
+// |branch| if |singleBranch| is set, skipping the data of the tables excluded by the table filter of the remote.
+func partialCloneDataPull(ctx context.Context, destData env.DbData, srcDB *doltdb.DoltDB, remoteName, branch string, singleBranch bool) (*doltdb.Commit, error) {
+	remotes, err := destData.Rsr.GetRemotes()
+	if err != nil {
+		return nil, err
+	}
+	remote, ok := remotes.Get(remoteName)
+	if !ok {
+		// By the time we get to this point, the remote should be created, so this should never happen.
+		return nil, fmt.Errorf("remote %s not found", remoteName)
+	}
+
+	var args []string
+	if singleBranch {
+		args = []string{branch}
+	}
+	specs, defaultRefSpecs, err := env.ParseRefSpecs(args, destData.Rsr, remote)
+	if err != nil {
+		return nil, err
+	}
+
+	err = fetchRefSpecsWithDepth(ctx, destData, srcDB, specs, defaultRefSpecs, &remote, ref.ForceUpdate, -1, NoopRunProgFuncs, NoopStopProgFuncs)
+	if err != nil {
+		return nil, err
+	}
+
+	cmt, err := destData.Ddb.ResolveCommitRef(ctx, ref.NewRemoteRef(remoteName, branch))
+	if err != nil {
+		return nil, err
+	}
+
+	hsh, err := cmt.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	// This is the only local branch after the clone is complete.
+	err = destData.Ddb.SetHead(ctx, ref.NewBranchRef(branch), hsh)
+	if err != nil {
+		return nil, err
+	}
+
+	return cmt, nil
+}
+
 // InitEmptyClonedRepo inits an empty, newly cloned repo. This would be unnecessary if we properly initialized the
 // storage for a repository when we created it on dolthub. If we do that, this code can be removed.
 func InitEmptyClonedRepo(ctx context.Context, dEnv *env.DoltEnv) error {
@@ -21,7 +21,6 @@ import (
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
-	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 )
 
@@ -158,7 +157,7 @@ func commitCIInit(ctx *sql.Context, queryFunc queryFunc, tableNames []doltdb.Tab
 }
 
 // DestroyDoltCITables drops all dolt_ci tables and creates a new Dolt commit.
-func DestroyDoltCITables(ctx *sql.Context, db sqle.Database, queryFunc queryFunc, commiterName, commiterEmail string) error {
+func DestroyDoltCITables(ctx *sql.Context, db dsess.SqlDatabase, queryFunc queryFunc, commiterName, commiterEmail string) error {
 	if err := dsess.CheckAccessForDb(ctx, db, branch_control.Permissions_Write); err != nil {
 		return err
 	}
@@ -191,7 +190,7 @@ func DestroyDoltCITables(ctx *sql.Context, db sqle.Database, queryFunc queryFunc
 }
 
 // CreateDoltCITables creates all dolt_ci tables and creates a new Dolt commit.
-func CreateDoltCITables(ctx *sql.Context, db sqle.Database, queryFunc queryFunc, commiterName, commiterEmail string) error {
+func CreateDoltCITables(ctx *sql.Context, db dsess.SqlDatabase, queryFunc queryFunc, commiterName, commiterEmail string) error {
 	if err := dsess.CheckAccessForDb(ctx, db, branch_control.Permissions_Write); err != nil {
 		return err
 	}
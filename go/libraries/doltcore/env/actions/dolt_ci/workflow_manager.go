@@ -28,7 +28,6 @@ import (
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
-	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 )
 
@@ -41,13 +40,18 @@ var ErrMultipleWorkflowsFound = errors.New("multiple workflows found")
 
 type WorkflowManager interface {
 	// RemoveWorkflow deletes a workflow from the database and creates a Dolt commit
-	RemoveWorkflow(ctx *sql.Context, db sqle.Database, workflowName string) error
+	RemoveWorkflow(ctx *sql.Context, db dsess.SqlDatabase, workflowName string) error
 	// ListWorkflows lists all workflows in the database.
-	ListWorkflows(ctx *sql.Context, db sqle.Database) ([]string, error)
+	ListWorkflows(ctx *sql.Context, db dsess.SqlDatabase) ([]string, error)
 	// GetWorkflowConfig returns the WorkflowConfig for a workflow by name.
-	GetWorkflowConfig(ctx *sql.Context, db sqle.Database, workflowName string) (*WorkflowConfig, error)
+	GetWorkflowConfig(ctx *sql.Context, db dsess.SqlDatabase, workflowName string) (*WorkflowConfig, error)
 	// StoreAndCommit creates or updates a workflow and creates a Dolt commit
-	StoreAndCommit(ctx *sql.Context, db sqle.Database, config *WorkflowConfig) error
+	StoreAndCommit(ctx *sql.Context, db dsess.SqlDatabase, config *WorkflowConfig) error
+	// RunWorkflow runs the saved query steps of every job of a workflow against the revision given, or against the
+	// current database if the revision is empty, and returns the outcome of each step.
+	RunWorkflow(ctx *sql.Context, db dsess.SqlDatabase, workflowName string, revision string) (*WorkflowRunResult, error)
+	// ListPushWorkflowsForBranch lists the workflows with a push event that fires for updates to the branch given.
+	ListPushWorkflowsForBranch(ctx *sql.Context, db dsess.SqlDatabase, branch string) ([]string, error)
 }
 
 type doltWorkflowManager struct {
@@ -1927,14 +1931,14 @@ func (d *doltWorkflowManager) storeFromConfig(ctx *sql.Context, config *Workflow
 	return d.updateExistingWorkflow(ctx, config)
 }
 
-func (d *doltWorkflowManager) GetWorkflowConfig(ctx *sql.Context, db sqle.Database, workflowName string) (*WorkflowConfig, error) {
+func (d *doltWorkflowManager) GetWorkflowConfig(ctx *sql.Context, db dsess.SqlDatabase, workflowName string) (*WorkflowConfig, error) {
 	if err := dsess.CheckAccessForDb(ctx, db, branch_control.Permissions_Read); err != nil {
 		return nil, err
 	}
 	return d.getWorkflowConfig(ctx, workflowName)
 }
 
-func (d *doltWorkflowManager) ListWorkflows(ctx *sql.Context, db sqle.Database) ([]string, error) {
+func (d *doltWorkflowManager) ListWorkflows(ctx *sql.Context, db dsess.SqlDatabase) ([]string, error) {
 	if err := dsess.CheckAccessForDb(ctx, db, branch_control.Permissions_Read); err != nil {
 		return nil, err
 	}
@@ -1952,7 +1956,7 @@ func (d *doltWorkflowManager) ListWorkflows(ctx *sql.Context, db sqle.Database)
 	return names, nil
 }
 
-func (d *doltWorkflowManager) RemoveWorkflow(ctx *sql.Context, db sqle.Database, workflowName string) error {
+func (d *doltWorkflowManager) RemoveWorkflow(ctx *sql.Context, db dsess.SqlDatabase, workflowName string) error {
 	if err := dsess.CheckAccessForDb(ctx, db, branch_control.Permissions_Write); err != nil {
 		return err
 	}
@@ -1968,7 +1972,7 @@ func (d *doltWorkflowManager) RemoveWorkflow(ctx *sql.Context, db sqle.Database,
 	return d.commitRemoveWorkflow(ctx, ExpectedDoltCITablesOrdered.ActiveTableNames(), workflowName)
 }
 
-func (d *doltWorkflowManager) StoreAndCommit(ctx *sql.Context, db sqle.Database, config *WorkflowConfig) error {
+func (d *doltWorkflowManager) StoreAndCommit(ctx *sql.Context, db dsess.SqlDatabase, config *WorkflowConfig) error {
 	if err := dsess.CheckAccessForDb(ctx, db, branch_control.Permissions_Write); err != nil {
 		return err
 	}
@@ -1981,6 +1985,18 @@ func (d *doltWorkflowManager) StoreAndCommit(ctx *sql.Context, db sqle.Database,
 	return d.commitWorkflow(ctx, ExpectedDoltCITablesOrdered.ActiveTableNames(), config.Name.Value)
 }
 
+func (d *doltWorkflowManager) RunWorkflow(ctx *sql.Context, db dsess.SqlDatabase, workflowName string, revision string) (*WorkflowRunResult, error) {
+	if err := dsess.CheckReadAccessForDb(ctx, db); err != nil {
+		return nil, err
+	}
+	return d.runWorkflow(ctx, workflowName, revision)
+}
+
+func (d *doltWorkflowManager) ListPushWorkflowsForBranch(ctx *sql.Context, db dsess.SqlDatabase, branch string) ([]string, error) {
+	// like RunWorkflow, this is used to trigger workflows and only reads from the database
+	return d.listPushWorkflowsForBranch(ctx, branch)
+}
+
 func newScalarDoubleQuotedYamlNode(value string) yaml.Node {
 	return yaml.Node{
 		Kind:  yaml.ScalarNode,
@@ -0,0 +1,291 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dolt_ci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+const DefaultWorkflowRunHistorySize = 1000
+
+// WorkflowRunEvent is the kind of branch update that triggered a workflow run.
+type WorkflowRunEvent string
+
+const (
+	// WorkflowRunEventPush is a branch update made by a client pushing to the remotesapi server.
+	WorkflowRunEventPush WorkflowRunEvent = "push"
+	// WorkflowRunEventCommit is a branch update made by a commit in sql-server.
+	WorkflowRunEventCommit WorkflowRunEvent = "commit"
+	// WorkflowRunEventMerge is a branch update made by a merge commit in sql-server.
+	WorkflowRunEventMerge WorkflowRunEvent = "merge"
+)
+
+// WorkflowRunStatus is the outcome of a triggered workflow run.
+type WorkflowRunStatus string
+
+const (
+	WorkflowRunStatusRunning WorkflowRunStatus = "running"
+	WorkflowRunStatusPassed  WorkflowRunStatus = "passed"
+	WorkflowRunStatusFailed  WorkflowRunStatus = "failed"
+	// WorkflowRunStatusError means the workflow could not be run at all, for example because its definition could
+	// not be read.
+	WorkflowRunStatusError WorkflowRunStatus = "error"
+)
+
+// WorkflowRun is a record of a workflow run triggered by a branch update.
+type WorkflowRun struct {
+	Id           string
+	WorkflowName string
+	Event        WorkflowRunEvent
+	Branch       string
+	CommitHash   string
+	Status       WorkflowRunStatus
+	Message      string
+	StartedAt    time.Time
+	FinishedAt   time.Time
+	Steps        []*WorkflowStepRunResult
+}
+
+// NewWorkflowRun returns a running WorkflowRun for the workflow and branch update given.
+func NewWorkflowRun(workflowName string, event WorkflowRunEvent, branch, commitHash string) *WorkflowRun {
+	return &WorkflowRun{
+		Id:           uuid.NewString(),
+		WorkflowName: workflowName,
+		Event:        event,
+		Branch:       branch,
+		CommitHash:   commitHash,
+		Status:       WorkflowRunStatusRunning,
+		StartedAt:    time.Now(),
+	}
+}
+
+// Finish records the outcome of a workflow run. If |err| is non-nil the run is recorded as an error, otherwise it
+// passes or fails based on |res|.
+func (r *WorkflowRun) Finish(res *WorkflowRunResult, err error) {
+	r.FinishedAt = time.Now()
+	if err != nil {
+		r.Status = WorkflowRunStatusError
+		r.Message = err.Error()
+		return
+	}
+
+	r.Steps = res.Steps
+	failed := res.FailedSteps()
+	if len(failed) == 0 {
+		r.Status = WorkflowRunStatusPassed
+		return
+	}
+
+	r.Status = WorkflowRunStatusFailed
+	names := make([]string, len(failed))
+	for i, s := range failed {
+		names[i] = s.StepName
+	}
+	r.Message = "failed steps: " + strings.Join(names, ", ")
+}
+
+// workflowRunsMapName is the name of the map in which the workflow runs of a database are recorded.
+const workflowRunsMapName = "ci_runs"
+
+// maxStoredMessageLength is the length at which the messages of a recorded run and its steps are truncated.
+const maxStoredMessageLength = 1024
+
+// maxStoredRunLength is the maximum length of the serialization of a recorded run.
+const maxStoredRunLength = int(val.MaxTupleDataSize) / 2
+
+// workflowRunHistorySize is the number of runs kept for each database.
+var workflowRunHistorySize atomic.Int64
+
+func init() {
+	workflowRunHistorySize.Store(DefaultWorkflowRunHistorySize)
+}
+
+// SetWorkflowRunHistorySize changes the number of runs kept for each database. Runs in excess of |size| are discarded
+// the next time a run is recorded.
+func SetWorkflowRunHistorySize(size int) {
+	workflowRunHistorySize.Store(int64(size))
+}
+
+var (
+	// workflowRunKeyDesc orders runs by the time they started, then by id.
+	workflowRunKeyDesc = val.NewTupleDescriptor(
+		val.Type{Enc: val.Int64Enc},
+		val.Type{Enc: val.StringEnc},
+	)
+	// workflowRunValDesc holds the JSON serialization of a run.
+	workflowRunValDesc = val.NewTupleDescriptor(
+		val.Type{Enc: val.ByteStringEnc, Nullable: true},
+	)
+)
+
+// WorkflowRunHistory is the record of the workflow runs triggered for a database, read by the dolt_ci_runs and
+// dolt_ci_run_steps system tables. Runs are stored in the database, outside of its commit graph, so they are kept
+// across restarts and are visible to every server using the database. Once a database has more runs than the size
+// set with SetWorkflowRunHistorySize, its oldest runs are discarded.
+type WorkflowRunHistory struct {
+	ddb *doltdb.DoltDB
+}
+
+// NewWorkflowRunHistory returns the history of the workflow runs of |ddb|.
+func NewWorkflowRunHistory(ddb *doltdb.DoltDB) *WorkflowRunHistory {
+	return &WorkflowRunHistory{ddb: ddb}
+}
+
+// Add records a new run.
+func (h *WorkflowRunHistory) Add(ctx context.Context, run *WorkflowRun) error {
+	return h.put(ctx, run, true)
+}
+
+// Update replaces the recorded run with the same id as |run|. Runs that have already been discarded are ignored.
+func (h *WorkflowRunHistory) Update(ctx context.Context, run *WorkflowRun) error {
+	return h.put(ctx, run, false)
+}
+
+// Runs returns the recorded runs, oldest first.
+func (h *WorkflowRunHistory) Runs(ctx context.Context) ([]WorkflowRun, error) {
+	m, err := h.ddb.GetMap(ctx, workflowRunsMapName, workflowRunKeyDesc, workflowRunValDesc)
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := m.IterAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []WorkflowRun
+	for {
+		_, v, err := iter.Next(ctx)
+		if err == io.EOF {
+			return runs, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		b, _ := workflowRunValDesc.GetBytes(0, v)
+		var run WorkflowRun
+		if err = json.Unmarshal(b, &run); err != nil {
+			return nil, fmt.Errorf("unable to read workflow run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+}
+
+func (h *WorkflowRunHistory) put(ctx context.Context, run *WorkflowRun, add bool) error {
+	stored := storedWorkflowRun(run)
+	b, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	if len(b) > maxStoredRunLength {
+		// Drop the messages of the steps of runs with many failing steps, they can be found by rerunning the workflow
+		for _, step := range stored.Steps {
+			step.Message = ""
+		}
+		if b, err = json.Marshal(stored); err != nil {
+			return err
+		}
+	}
+	if len(b) > maxStoredRunLength {
+		return fmt.Errorf("workflow run %s is too large to be recorded", run.Id)
+	}
+
+	pool := h.ddb.NodeStore().Pool()
+	kb := val.NewTupleBuilder(workflowRunKeyDesc)
+	kb.PutInt64(0, run.StartedAt.UnixNano())
+	if err = kb.PutString(1, run.Id); err != nil {
+		return err
+	}
+	key := kb.Build(pool)
+	vb := val.NewTupleBuilder(workflowRunValDesc)
+	vb.PutByteString(0, b)
+	value := vb.Build(pool)
+
+	return h.ddb.UpdateMap(ctx, workflowRunsMapName, workflowRunKeyDesc, workflowRunValDesc, func(m *prolly.MutableMap) error {
+		if !add {
+			ok, err := m.Has(ctx, key)
+			if err != nil || !ok {
+				return err
+			}
+			return m.Put(ctx, key, value)
+		}
+
+		if err := m.Put(ctx, key, value); err != nil {
+			return err
+		}
+		return truncateWorkflowRuns(ctx, m, int(workflowRunHistorySize.Load()))
+	})
+}
+
+// truncateWorkflowRuns discards the oldest runs of |m| so that it holds no more than |size| runs.
+func truncateWorkflowRuns(ctx context.Context, m *prolly.MutableMap, size int) error {
+	if size < 0 {
+		return nil
+	}
+
+	iter, err := m.IterAll(ctx)
+	if err != nil {
+		return err
+	}
+	var keys []val.Tuple
+	for {
+		k, _, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		keys = append(keys, k)
+	}
+
+	for i := 0; i < len(keys)-size; i++ {
+		if err = m.Delete(ctx, keys[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// storedWorkflowRun returns a copy of |run| whose messages are truncated to |maxStoredMessageLength|.
+func storedWorkflowRun(run *WorkflowRun) WorkflowRun {
+	cp := *run
+	cp.Message = truncateMessage(cp.Message)
+	cp.Steps = make([]*WorkflowStepRunResult, len(run.Steps))
+	for i, step := range run.Steps {
+		stepCp := *step
+		stepCp.Message = truncateMessage(stepCp.Message)
+		cp.Steps[i] = &stepCp
+	}
+	return cp
+}
+
+func truncateMessage(msg string) string {
+	if len(msg) > maxStoredMessageLength {
+		return msg[:maxStoredMessageLength] + "..."
+	}
+	return msg
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dolt_ci
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestWorkflowRunHistory(t *testing.T) {
+	ctx := context.Background()
+	ddb, err := doltdb.LoadDoltDB(ctx, types.Format_Default, doltdb.InMemDoltDB, filesys.LocalFS)
+	require.NoError(t, err)
+	defer SetWorkflowRunHistorySize(DefaultWorkflowRunHistorySize)
+	SetWorkflowRunHistorySize(2)
+
+	h := NewWorkflowRunHistory(ddb)
+	runs, err := h.Runs(ctx)
+	require.NoError(t, err)
+	require.Empty(t, runs)
+
+	first := NewWorkflowRun("wf", WorkflowRunEventCommit, "main", "abc")
+	require.NoError(t, h.Add(ctx, first))
+	runs, err = h.Runs(ctx)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	require.Equal(t, WorkflowRunStatusRunning, runs[0].Status)
+
+	first.Finish(&WorkflowRunResult{Steps: []*WorkflowStepRunResult{{StepName: "one", Passed: true}}}, nil)
+	require.NoError(t, h.Update(ctx, first))
+	runs, err = NewWorkflowRunHistory(ddb).Runs(ctx)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	require.Equal(t, WorkflowRunStatusPassed, runs[0].Status)
+	require.Len(t, runs[0].Steps, 1)
+
+	second := NewWorkflowRun("wf", WorkflowRunEventMerge, "main", "def")
+	second.Finish(&WorkflowRunResult{Steps: []*WorkflowStepRunResult{{StepName: "one"}, {StepName: "two"}}}, nil)
+	require.NoError(t, h.Add(ctx, second))
+	third := NewWorkflowRun("wf", WorkflowRunEventPush, "main", "ghi")
+	third.Finish(nil, errors.New("workflow not found"))
+	require.NoError(t, h.Add(ctx, third))
+
+	runs, err = h.Runs(ctx)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	require.Equal(t, second.Id, runs[0].Id)
+	require.Equal(t, WorkflowRunStatusFailed, runs[0].Status)
+	require.Equal(t, "failed steps: one, two", runs[0].Message)
+	require.Equal(t, WorkflowRunStatusError, runs[1].Status)
+	require.Equal(t, "workflow not found", runs[1].Message)
+
+	// updates of discarded runs are ignored
+	require.NoError(t, h.Update(ctx, first))
+	runs, err = h.Runs(ctx)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+
+	SetWorkflowRunHistorySize(1)
+	fourth := NewWorkflowRun("wf", WorkflowRunEventPush, "main", "jkl")
+	require.NoError(t, h.Add(ctx, fourth))
+	runs, err = h.Runs(ctx)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	require.Equal(t, fourth.Id, runs[0].Id)
+}
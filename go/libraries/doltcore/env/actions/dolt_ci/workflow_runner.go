@@ -0,0 +1,404 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dolt_ci
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+var ErrSavedQueryNotFound = errors.New("saved query not found")
+
+// WorkflowStepRunResult is the outcome of running a single step of a workflow job.
+type WorkflowStepRunResult struct {
+	JobName        string
+	StepName       string
+	SavedQueryName string
+	Passed         bool
+	// Message describes why the step failed. It is empty for steps that passed.
+	Message string
+}
+
+// WorkflowRunResult is the outcome of running every job of a workflow against a single revision.
+type WorkflowRunResult struct {
+	WorkflowName string
+	Revision     string
+	Steps        []*WorkflowStepRunResult
+}
+
+// Passed returns true if every step of the workflow run passed.
+func (r *WorkflowRunResult) Passed() bool {
+	for _, s := range r.Steps {
+		if !s.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// FailedSteps returns the steps of the workflow run that did not pass.
+func (r *WorkflowRunResult) FailedSteps() []*WorkflowStepRunResult {
+	failed := make([]*WorkflowStepRunResult, 0)
+	for _, s := range r.Steps {
+		if !s.Passed {
+			failed = append(failed, s)
+		}
+	}
+	return failed
+}
+
+func (d *doltWorkflowManager) selectQueryFromQueryCatalogByNameQuery(savedQueryName string) string {
+	return fmt.Sprintf("select `%s` from %s where `%s` = '%s' limit 1;", doltdb.QueryCatalogQueryCol, doltdb.DoltQueryCatalogTableName, doltdb.QueryCatalogNameCol, savedQueryName)
+}
+
+func (d *doltWorkflowManager) getSavedQuery(ctx *sql.Context, savedQueryName string) (string, error) {
+	query := d.selectQueryFromQueryCatalogByNameQuery(savedQueryName)
+
+	var savedQuery string
+	found := false
+	cb := func(cbCtx *sql.Context, cvs columnValues) error {
+		found = true
+		for _, cv := range cvs {
+			if cv == nil {
+				continue
+			}
+			if cv.ColumnName == doltdb.QueryCatalogQueryCol {
+				savedQuery = cv.Value
+			}
+		}
+		return nil
+	}
+
+	err := d.sqlReadQuery(ctx, query, cb)
+	if err != nil {
+		if sql.ErrTableNotFound.Is(err) {
+			return "", fmt.Errorf("%w: %s", ErrSavedQueryNotFound, savedQueryName)
+		}
+		return "", err
+	}
+	if !found || savedQuery == "" {
+		return "", fmt.Errorf("%w: %s", ErrSavedQueryNotFound, savedQueryName)
+	}
+	return savedQuery, nil
+}
+
+// countQueryResults runs |query| and returns the number of rows and columns in its result set.
+func (d *doltWorkflowManager) countQueryResults(ctx *sql.Context, query string) (rows int64, columns int64, err error) {
+	sch, rowIter, _, err := d.queryFunc(ctx, query)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() {
+		cerr := rowIter.Close(ctx)
+		if err == nil {
+			err = cerr
+		}
+	}()
+
+	for {
+		_, err = rowIter.Next(ctx)
+		if err == io.EOF {
+			err = nil
+			break
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		rows++
+	}
+
+	return rows, int64(len(sch)), nil
+}
+
+// useRevision makes |revision| of the current database the current database of the session, and returns a function
+// that restores the original current database. If |revision| is empty, the current database is left unchanged.
+func (d *doltWorkflowManager) useRevision(ctx *sql.Context, revision string) (func() error, error) {
+	noop := func() error { return nil }
+	if revision == "" {
+		return noop, nil
+	}
+
+	current := ctx.GetCurrentDatabase()
+	if current == "" {
+		return noop, fmt.Errorf("no database selected")
+	}
+
+	baseName, _ := dsess.SplitRevisionDbName(current)
+	err := d.sqlWriteQuery(ctx, fmt.Sprintf("use `%s`;", dsess.RevisionDbName(baseName, revision)))
+	if err != nil {
+		return noop, err
+	}
+
+	return func() error {
+		return d.sqlWriteQuery(ctx, fmt.Sprintf("use `%s`;", current))
+	}, nil
+}
+
+func compareSavedQueryResultCount(comparisonType WorkflowSavedQueryExpectedRowColumnComparisonType, actual, expected int64) (bool, error) {
+	switch comparisonType {
+	case WorkflowSavedQueryExpectedRowColumnComparisonTypeUnspecified:
+		return true, nil
+	case WorkflowSavedQueryExpectedRowColumnComparisonTypeEquals:
+		return actual == expected, nil
+	case WorkflowSavedQueryExpectedRowColumnComparisonTypeNotEquals:
+		return actual != expected, nil
+	case WorkflowSavedQueryExpectedRowColumnComparisonTypeGreaterThan:
+		return actual > expected, nil
+	case WorkflowSavedQueryExpectedRowColumnComparisonTypeGreaterThanOrEqual:
+		return actual >= expected, nil
+	case WorkflowSavedQueryExpectedRowColumnComparisonTypeLessThan:
+		return actual < expected, nil
+	case WorkflowSavedQueryExpectedRowColumnComparisonTypeLessThanOrEqual:
+		return actual <= expected, nil
+	default:
+		return false, ErrUnknownWorkflowSavedQueryExpectedRowColumnComparisonType
+	}
+}
+
+// pendingSavedQueryStep is a saved query step, along with its expected results, read from the workflow tables and
+// waiting to be run.
+type pendingSavedQueryStep struct {
+	job            *WorkflowJob
+	step           *WorkflowStep
+	savedQueryStep *WorkflowSavedQueryStep
+	expected       *WorkflowSavedQueryExpectedRowColumnResult
+}
+
+func (d *doltWorkflowManager) newPendingSavedQueryStep(ctx *sql.Context, job *WorkflowJob, step *WorkflowStep) (*pendingSavedQueryStep, error) {
+	if step.StepType != WorkflowStepTypeSavedQuery {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownWorkflowStepType, step.StepType)
+	}
+
+	savedQueryStep, err := d.getWorkflowSavedQueryStepByStepId(ctx, *step.Id)
+	if err != nil {
+		return nil, err
+	}
+	if savedQueryStep == nil {
+		return nil, fmt.Errorf("no saved query step found for step: %s", step.Name)
+	}
+
+	var expected *WorkflowSavedQueryExpectedRowColumnResult
+	if savedQueryStep.SavedQueryExpectedResultsType == WorkflowSavedQueryExpectedResultsTypeRowColumnCount {
+		expected, err = d.getWorkflowSavedQueryExpectedRowColumnResultBySavedQueryStepId(ctx, *savedQueryStep.Id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &pendingSavedQueryStep{
+		job:            job,
+		step:           step,
+		savedQueryStep: savedQueryStep,
+		expected:       expected,
+	}, nil
+}
+
+// runSavedQueryStep runs a single saved query step and evaluates its results against the step's expected row and
+// column results. Failures to run the saved query are reported as a failed step rather than an error.
+func (d *doltWorkflowManager) runSavedQueryStep(ctx *sql.Context, p *pendingSavedQueryStep) (*WorkflowStepRunResult, error) {
+	result := &WorkflowStepRunResult{
+		JobName:        p.job.Name,
+		StepName:       p.step.Name,
+		SavedQueryName: p.savedQueryStep.SavedQueryName,
+	}
+	expected := p.expected
+
+	savedQuery, err := d.getSavedQuery(ctx, p.savedQueryStep.SavedQueryName)
+	if err != nil {
+		if errors.Is(err, ErrSavedQueryNotFound) {
+			result.Message = err.Error()
+			return result, nil
+		}
+		return nil, err
+	}
+
+	rows, columns, err := d.countQueryResults(ctx, savedQuery)
+	if err != nil {
+		result.Message = fmt.Sprintf("query error: %s", err.Error())
+		return result, nil
+	}
+
+	if expected != nil {
+		ok, err := compareSavedQueryResultCount(expected.ExpectedColumnCountComparisonType, columns, expected.ExpectedColumnCount)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			expectedStr, err := d.toSavedQueryExpectedResultString(expected.ExpectedColumnCountComparisonType, expected.ExpectedColumnCount)
+			if err != nil {
+				return nil, err
+			}
+			result.Message = fmt.Sprintf("expected column count %s, got %d", expectedStr, columns)
+			return result, nil
+		}
+
+		ok, err = compareSavedQueryResultCount(expected.ExpectedRowCountComparisonType, rows, expected.ExpectedRowCount)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			expectedStr, err := d.toSavedQueryExpectedResultString(expected.ExpectedRowCountComparisonType, expected.ExpectedRowCount)
+			if err != nil {
+				return nil, err
+			}
+			result.Message = fmt.Sprintf("expected row count %s, got %d", expectedStr, rows)
+			return result, nil
+		}
+	}
+
+	result.Passed = true
+	return result, nil
+}
+
+// runWorkflow runs every step of every job of the named workflow. The workflow definition is read from the current
+// database, while saved queries are looked up and run against |revision|.
+func (d *doltWorkflowManager) runWorkflow(ctx *sql.Context, workflowName string, revision string) (res *WorkflowRunResult, err error) {
+	workflow, err := d.getWorkflow(ctx, workflowName)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, err := d.listWorkflowJobsByWorkflowName(ctx, *workflow.Name)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].Name < jobs[j].Name
+	})
+
+	stepsByJob := make(map[WorkflowJobId][]*WorkflowStep)
+	for _, job := range jobs {
+		steps, err := d.listWorkflowStepsByJobId(ctx, *job.Id)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(steps, func(i, j int) bool {
+			return steps[i].StepOrder < steps[j].StepOrder
+		})
+		stepsByJob[*job.Id] = steps
+	}
+
+	// saved query steps, and their expected results, are read from the workflow tables before
+	// switching to the revision under test, since that revision may not have the dolt_ci tables
+	pending := make([]*pendingSavedQueryStep, 0)
+	for _, job := range jobs {
+		for _, step := range stepsByJob[*job.Id] {
+			p, err := d.newPendingSavedQueryStep(ctx, job, step)
+			if err != nil {
+				return nil, err
+			}
+			pending = append(pending, p)
+		}
+	}
+
+	restore, err := d.useRevision(ctx, revision)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		rerr := restore()
+		if err == nil {
+			err = rerr
+		}
+	}()
+
+	res = &WorkflowRunResult{
+		WorkflowName: string(*workflow.Name),
+		Revision:     revision,
+		Steps:        make([]*WorkflowStepRunResult, 0, len(pending)),
+	}
+
+	for _, p := range pending {
+		stepResult, err := d.runSavedQueryStep(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		res.Steps = append(res.Steps, stepResult)
+	}
+
+	return res, nil
+}
+
+// branchMatchesTrigger returns whether |branch| is matched by the branch of a push trigger, which may be a glob
+// pattern, like "release/*".
+func branchMatchesTrigger(pattern, branch string) bool {
+	if pattern == branch {
+		return true
+	}
+	ok, err := path.Match(pattern, branch)
+	return err == nil && ok
+}
+
+// pushEventMatchesBranch returns whether a push event fires for updates to |branch|. Push events without branch
+// triggers fire for every branch.
+func (d *doltWorkflowManager) pushEventMatchesBranch(ctx *sql.Context, event *WorkflowEvent, branch string) (bool, error) {
+	triggers, err := d.listWorkflowEventTriggersByEventIdWhereEventTriggerTypeIsBranches(ctx, *event.Id)
+	if err != nil {
+		return false, err
+	}
+	if len(triggers) == 0 {
+		return true, nil
+	}
+
+	for _, trigger := range triggers {
+		branches, err := d.listWorkflowEventTriggerBranchesByEventTriggerId(ctx, *trigger.Id)
+		if err != nil {
+			return false, err
+		}
+		for _, b := range branches {
+			if branchMatchesTrigger(b.Branch, branch) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func (d *doltWorkflowManager) listPushWorkflowsForBranch(ctx *sql.Context, branch string) ([]string, error) {
+	workflows, err := d.listWorkflows(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0)
+	for _, w := range workflows {
+		events, err := d.listWorkflowEventsByWorkflowNameWhereEventTypeIsPush(ctx, *w.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			matched, err := d.pushEventMatchesBranch(ctx, event, branch)
+			if err != nil {
+				return nil, err
+			}
+			if matched {
+				names = append(names, string(*w.Name))
+				break
+			}
+		}
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dolt_ci
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareSavedQueryResultCount(t *testing.T) {
+	tests := []struct {
+		comparisonType WorkflowSavedQueryExpectedRowColumnComparisonType
+		actual         int64
+		expected       int64
+		ok             bool
+	}{
+		{WorkflowSavedQueryExpectedRowColumnComparisonTypeUnspecified, 10, 0, true},
+		{WorkflowSavedQueryExpectedRowColumnComparisonTypeEquals, 2, 2, true},
+		{WorkflowSavedQueryExpectedRowColumnComparisonTypeEquals, 1, 2, false},
+		{WorkflowSavedQueryExpectedRowColumnComparisonTypeNotEquals, 1, 2, true},
+		{WorkflowSavedQueryExpectedRowColumnComparisonTypeNotEquals, 2, 2, false},
+		{WorkflowSavedQueryExpectedRowColumnComparisonTypeGreaterThan, 3, 2, true},
+		{WorkflowSavedQueryExpectedRowColumnComparisonTypeGreaterThan, 2, 2, false},
+		{WorkflowSavedQueryExpectedRowColumnComparisonTypeGreaterThanOrEqual, 2, 2, true},
+		{WorkflowSavedQueryExpectedRowColumnComparisonTypeGreaterThanOrEqual, 1, 2, false},
+		{WorkflowSavedQueryExpectedRowColumnComparisonTypeLessThan, 1, 2, true},
+		{WorkflowSavedQueryExpectedRowColumnComparisonTypeLessThan, 2, 2, false},
+		{WorkflowSavedQueryExpectedRowColumnComparisonTypeLessThanOrEqual, 2, 2, true},
+		{WorkflowSavedQueryExpectedRowColumnComparisonTypeLessThanOrEqual, 3, 2, false},
+	}
+
+	for _, test := range tests {
+		ok, err := compareSavedQueryResultCount(test.comparisonType, test.actual, test.expected)
+		require.NoError(t, err)
+		require.Equal(t, test.ok, ok, "comparison %d of %d and %d", test.comparisonType, test.actual, test.expected)
+	}
+
+	_, err := compareSavedQueryResultCount(WorkflowSavedQueryExpectedRowColumnComparisonType(100), 1, 1)
+	require.ErrorIs(t, err, ErrUnknownWorkflowSavedQueryExpectedRowColumnComparisonType)
+}
+
+func TestWorkflowRunResultPassed(t *testing.T) {
+	res := &WorkflowRunResult{WorkflowName: "wf"}
+	require.True(t, res.Passed())
+
+	res.Steps = append(res.Steps, &WorkflowStepRunResult{StepName: "one", Passed: true})
+	require.True(t, res.Passed())
+	require.Empty(t, res.FailedSteps())
+
+	res.Steps = append(res.Steps, &WorkflowStepRunResult{StepName: "two", Message: "expected row count == 1, got 2"})
+	require.False(t, res.Passed())
+	require.Len(t, res.FailedSteps(), 1)
+	require.Equal(t, "two", res.FailedSteps()[0].StepName)
+}
+
+func TestBranchMatchesTrigger(t *testing.T) {
+	require.True(t, branchMatchesTrigger("main", "main"))
+	require.False(t, branchMatchesTrigger("main", "feature"))
+	require.True(t, branchMatchesTrigger("release/*", "release/1.0"))
+	require.False(t, branchMatchesTrigger("release/*", "release/1.0/hotfix"))
+	require.True(t, branchMatchesTrigger("*", "feature"))
+	require.False(t, branchMatchesTrigger("[", "main"))
+}
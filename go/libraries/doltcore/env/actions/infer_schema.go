@@ -55,8 +55,20 @@ type InferenceArgs interface {
 	FloatThreshold() float64
 }
 
+// TypedTableReader is a table reader for a file format whose schema declares the types of its columns, such as Avro
+// or Arrow. The types of its columns are used as is rather than being inferred from its rows.
+type TypedTableReader interface {
+	table.ReadCloser
+	// HasTypedSchema returns whether the types of the columns of the reader's schema are those of the file.
+	HasTypedSchema() bool
+}
+
 // InferColumnTypesFromTableReader will infer a data types from a table reader.
 func InferColumnTypesFromTableReader(ctx context.Context, rd table.ReadCloser, args InferenceArgs) (*schema.ColCollection, error) {
+	if typed, ok := rd.(TypedTableReader); ok && typed.HasTypedSchema() {
+		return columnsFromTypedSchema(typed.GetSchema(), args), nil
+	}
+
 	// for large imports, we want to sample a subset of the rows.
 	// skip through the file in an exponential manner
 	const exp = 1.02
@@ -92,6 +104,21 @@ OUTER:
 	return i.inferColumnTypes()
 }
 
+// columnsFromTypedSchema returns the columns of |readerSch| renamed with the ColNameMapper of |args|. Unlike inferred
+// columns, they keep their not null constraints, which are declared by the file.
+func columnsFromTypedSchema(readerSch schema.Schema, args InferenceArgs) *schema.ColCollection {
+	mapper := args.ColNameMapper()
+	var cols []schema.Column
+	_ = readerSch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		col.Name = mapper.Map(col.Name)
+		col.Tag = schema.ReservedTagMin + tag
+		cols = append(cols, col)
+		return false, nil
+	})
+
+	return schema.NewColCollection(cols...)
+}
+
 type inferrer struct {
 	readerSch      schema.Schema
 	inferSets      map[uint64]typeInfoSet
@@ -26,6 +26,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/rowconv"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
@@ -491,3 +492,45 @@ func TestInferSchema(t *testing.T) {
 		})
 	}
 }
+
+type typedTestReader struct {
+	sch schema.Schema
+}
+
+func (rd typedTestReader) GetSchema() schema.Schema {
+	return rd.sch
+}
+
+func (rd typedTestReader) ReadRow(ctx context.Context) (row.Row, error) {
+	panic("the rows of typed readers are not read to infer their schema")
+}
+
+func (rd typedTestReader) Close(ctx context.Context) error {
+	return nil
+}
+
+func (rd typedTestReader) HasTypedSchema() bool {
+	return true
+}
+
+func TestInferSchemaFromTypedReader(t *testing.T) {
+	rd := typedTestReader{sch: schema.MustSchemaFromCols(schema.NewColCollection(
+		schema.NewColumn("id", 0, types.IntKind, false, schema.NotNullConstraint{}),
+		schema.NewColumn("name", 1, types.StringKind, false),
+	))}
+	args := testInferenceArgs{ColMapper: rowconv.NameMapper{"name": "full_name"}}
+
+	allCols, err := InferColumnTypesFromTableReader(context.Background(), rd, args)
+	require.NoError(t, err)
+
+	cols := allCols.GetColumns()
+	require.Len(t, cols, 2)
+	assert.Equal(t, "id", cols[0].Name)
+	assert.Equal(t, typeinfo.Int64Type, cols[0].TypeInfo)
+	assert.Equal(t, schema.ReservedTagMin, cols[0].Tag)
+	assert.False(t, cols[0].IsNullable())
+	assert.Equal(t, "full_name", cols[1].Name)
+	assert.Equal(t, typeinfo.StringDefaultType, cols[1].TypeInfo)
+	assert.Equal(t, schema.ReservedTagMin+1, cols[1].Tag)
+	assert.True(t, cols[1].IsNullable())
+}
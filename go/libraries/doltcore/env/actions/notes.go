@@ -0,0 +1,94 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+var ErrNoteAlreadyExists = errors.New("cannot add note: the commit already has a note, use --force to replace it")
+
+type NoteProps struct {
+	AuthorName  string
+	AuthorEmail string
+	Note        string
+	// Force replaces an existing note of the commit.
+	Force bool
+}
+
+// AddNoteOnDB attaches a note to the commit |startPoint| resolves to. It is an error for the commit to already have a
+// note unless |props.Force| is set.
+func AddNoteOnDB(ctx context.Context, ddb *doltdb.DoltDB, startPoint string, props NoteProps, headRef ref.DoltRef) error {
+	cm, h, err := resolveNoteCommit(ctx, ddb, startPoint, headRef)
+	if err != nil {
+		return err
+	}
+
+	if !props.Force {
+		_, err = ddb.ResolveNote(ctx, h)
+		if err == nil {
+			return ErrNoteAlreadyExists
+		} else if !errors.Is(err, doltdb.ErrNoteNotFound) {
+			return err
+		}
+	}
+
+	meta := datas.NewTagMeta(props.AuthorName, props.AuthorEmail, props.Note)
+	return ddb.SetNote(ctx, cm, meta)
+}
+
+// RemoveNotesOnDB removes the notes of the commits the |startPoints| given resolve to.
+func RemoveNotesOnDB(ctx context.Context, ddb *doltdb.DoltDB, headRef ref.DoltRef, startPoints ...string) error {
+	for _, startPoint := range startPoints {
+		_, h, err := resolveNoteCommit(ctx, ddb, startPoint, headRef)
+		if err != nil {
+			return err
+		}
+
+		err = ddb.DeleteNote(ctx, h)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func resolveNoteCommit(ctx context.Context, ddb *doltdb.DoltDB, startPoint string, headRef ref.DoltRef) (*doltdb.Commit, hash.Hash, error) {
+	cs, err := doltdb.NewCommitSpec(startPoint)
+	if err != nil {
+		return nil, hash.Hash{}, err
+	}
+
+	optCmt, err := ddb.Resolve(ctx, cs, headRef)
+	if err != nil {
+		return nil, hash.Hash{}, err
+	}
+	cm, ok := optCmt.ToCommit()
+	if !ok {
+		return nil, hash.Hash{}, doltdb.ErrGhostCommitEncountered
+	}
+
+	h, err := cm.HashOf()
+	if err != nil {
+		return nil, hash.Hash{}, err
+	}
+	return cm, h, nil
+}
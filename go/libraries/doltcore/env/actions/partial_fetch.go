@@ -0,0 +1,312 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas/pull"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// A partial fetch transfers the commits of a remote, but only the data of the tables selected by the table filter of
+// the remote. The addresses of the other tables are persisted as ghosts, the same way shallow clones persist the
+// commits they skip: they are reported as present by the storage layer, so the pull doesn't fetch them and the
+// database has no dangling references, and reading one of them returns a doltdb.TableNotFetchedError. The tables are
+// filtered while walking the pulled chunks, see doltdb.PullChunksWithTableFilter.
+
+// FetchTables fetches the data of the tables included by |filter| which earlier partial fetches skipped, in every
+// commit and working set of |dbData|. This is how tables excluded from a partial clone are fetched on demand.
+func FetchTables(ctx context.Context, dbData env.DbData, srcDB *doltdb.DoltDB, filter *env.TableFilter, progStarter ProgStarter, progStopper ProgStopper) error {
+	unfetched, err := unfetchedTableAddrs(ctx, dbData.Ddb, filter)
+	if err != nil {
+		return err
+	}
+	if unfetched.Size() == 0 {
+		return nil
+	}
+
+	tmpDir, err := dbData.Rsw.TempTableFilesDir()
+	if err != nil {
+		return err
+	}
+
+	toFetch := make([]hash.Hash, 0, unfetched.Size())
+	for h := range unfetched {
+		toFetch = append(toFetch, h)
+	}
+
+	// Ghost hashes are reported as present, so the puller would skip them.
+	err = dbData.Ddb.DeleteGhostHashes(ctx, unfetched)
+	if err != nil {
+		return err
+	}
+
+	err = func() error {
+		newCtx := ctx
+		var statsCh chan pull.Stats
+
+		if progStarter != nil && progStopper != nil {
+			var cancelFunc func()
+			newCtx, cancelFunc = context.WithCancel(ctx)
+			var wg *sync.WaitGroup
+			wg, statsCh = progStarter(newCtx)
+			defer progStopper(cancelFunc, wg, statsCh)
+		}
+
+		err = dbData.Ddb.PullChunks(ctx, tmpDir, srcDB, toFetch, statsCh, nil)
+		if err == pull.ErrDBUpToDate {
+			err = nil
+		}
+		return err
+	}()
+	if err != nil {
+		// Restore the ghosts, so that the tables which weren't fetched are still reported as such.
+		return errors.Join(err, dbData.Ddb.PersistGhostCommits(ctx, unfetched))
+	}
+
+	return nil
+}
+
+// unfetchedTableAddrs returns the addresses of the unfetched tables included by |filter| in the commits and working
+// sets of |ddb|.
+func unfetchedTableAddrs(ctx context.Context, ddb *doltdb.DoltDB, filter *env.TableFilter) (hash.HashSet, error) {
+	unfetched := hash.NewHashSet()
+	seenRoots := hash.NewHashSet()
+	seenTables := hash.NewHashSet()
+	checkRoot := func(root doltdb.RootValue) error {
+		rootHash, err := root.HashOf()
+		if err != nil {
+			return err
+		}
+		if seenRoots.Has(rootHash) {
+			return nil
+		}
+		seenRoots.Insert(rootHash)
+
+		tableHashes, err := doltdb.MapTableHashes(ctx, root)
+		if err != nil {
+			return err
+		}
+		var addrs hash.HashSlice
+		for name, addr := range tableHashes {
+			if filter.Includes(name.Name) && !seenTables.Has(addr) {
+				seenTables.Insert(addr)
+				addrs = append(addrs, addr)
+			}
+		}
+
+		vals, err := ddb.ValueReadWriter().ReadManyValues(ctx, addrs)
+		if err != nil {
+			return err
+		}
+		for i, val := range vals {
+			if _, ok := val.(types.GhostValue); ok {
+				unfetched.Insert(addrs[i])
+			}
+		}
+		return nil
+	}
+
+	var stack []hash.Hash
+	err := ddb.VisitRefsOfType(ctx, ref.HeadRefTypes, func(r ref.DoltRef, addr hash.Hash) error {
+		if r.GetType() == ref.TagRefType {
+			tag, err := ddb.ResolveTag(ctx, r.(ref.TagRef))
+			if err != nil {
+				return err
+			}
+			addr, err = tag.Commit.HashOf()
+			if err != nil {
+				return err
+			}
+		}
+		stack = append(stack, addr)
+
+		if r.GetType() != ref.BranchRefType {
+			return nil
+		}
+		wsRef, err := ref.WorkingSetRefForHead(r)
+		if err != nil {
+			return err
+		}
+		ws, err := ddb.ResolveWorkingSet(ctx, wsRef)
+		if errors.Is(err, doltdb.ErrWorkingSetNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err = checkRoot(ws.WorkingRoot()); err != nil {
+			return err
+		}
+		return checkRoot(ws.StagedRoot())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	visited := hash.NewHashSet()
+	for len(stack) > 0 {
+		h := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if visited.Has(h) {
+			continue
+		}
+		visited.Insert(h)
+
+		optCmt, err := ddb.ReadCommit(ctx, h)
+		if err != nil {
+			return nil, err
+		}
+		cm, ok := optCmt.ToCommit()
+		if !ok {
+			// the history of a shallow clone ends at its ghost commits
+			continue
+		}
+
+		root, err := cm.GetRootValue(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err = checkRoot(root); err != nil {
+			return nil, err
+		}
+
+		parents, err := cm.ParentHashes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		stack = append(stack, parents...)
+	}
+
+	return unfetched, nil
+}
+
+// checkUnfetchedTablesOnDest returns an ErrPartialPushImpossible error if the commits of |srcDB| reachable from |cmHash|
+// which are missing from |destDB| reference unfetched tables which |destDB| doesn't have either. Pushing them would
+// leave |destDB| with dangling references, so the tables must be fetched first.
+func checkUnfetchedTablesOnDest(ctx context.Context, srcDB, destDB *doltdb.DoltDB, cmHash hash.Hash) error {
+	hasGhosts, err := srcDB.HasGhosts(ctx)
+	if err != nil || !hasGhosts {
+		return err
+	}
+
+	missing := make(map[string]struct{})
+	seenRoots := hash.NewHashSet()
+	seenTables := hash.NewHashSet()
+	visited := hash.NewHashSet()
+	stack := []hash.Hash{cmHash}
+	for len(stack) > 0 {
+		h := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if visited.Has(h) {
+			continue
+		}
+		visited.Insert(h)
+
+		has, err := destDB.Has(ctx, h)
+		if err != nil {
+			return err
+		}
+		if has {
+			continue
+		}
+
+		optCmt, err := srcDB.ReadCommit(ctx, h)
+		if err != nil {
+			return err
+		}
+		cm, ok := optCmt.ToCommit()
+		if !ok {
+			// the puller reports the ghost commits of a shallow clone
+			continue
+		}
+
+		root, err := cm.GetRootValue(ctx)
+		if err != nil {
+			return err
+		}
+		rootHash, err := root.HashOf()
+		if err != nil {
+			return err
+		}
+		if !seenRoots.Has(rootHash) {
+			seenRoots.Insert(rootHash)
+			if err = unfetchedTablesMissingFrom(ctx, root, destDB, seenTables, missing); err != nil {
+				return err
+			}
+		}
+
+		parents, err := cm.ParentHashes(ctx)
+		if err != nil {
+			return err
+		}
+		stack = append(stack, parents...)
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("%w: the data of the tables %s wasn't fetched and the remote doesn't have it, fetch it with 'dolt fetch --tables' first", ErrPartialPushImpossible, strings.Join(names, ", "))
+}
+
+// unfetchedTablesMissingFrom adds to |missing| the names of the unfetched tables of |root| whose address |destDB|
+// doesn't have. Addresses of |seenTables| are skipped, and the checked addresses are added to it.
+func unfetchedTablesMissingFrom(ctx context.Context, root doltdb.RootValue, destDB *doltdb.DoltDB, seenTables hash.HashSet, missing map[string]struct{}) error {
+	tableHashes, err := doltdb.MapTableHashes(ctx, root)
+	if err != nil {
+		return err
+	}
+	var names []string
+	var addrs hash.HashSlice
+	for name, addr := range tableHashes {
+		if !seenTables.Has(addr) {
+			seenTables.Insert(addr)
+			names = append(names, name.Name)
+			addrs = append(addrs, addr)
+		}
+	}
+
+	vals, err := root.VRW().ReadManyValues(ctx, addrs)
+	if err != nil {
+		return err
+	}
+	for i, val := range vals {
+		if _, ok := val.(types.GhostValue); !ok {
+			continue
+		}
+		has, err := destDB.Has(ctx, addrs[i])
+		if err != nil {
+			return err
+		}
+		if !has {
+			missing[names[i]] = struct{}{}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestPartialFetch(t *testing.T) {
+	ctx := context.Background()
+	srcDB := newPartialFetchTestDB(t, true)
+	cmHash, fetchedAddr, skippedAddr := commitPartialFetchTables(t, ctx, srcDB)
+
+	destDB := newPartialFetchTestDB(t, false)
+	filter, err := env.NewTableFilter(nil, []string{"skipped"})
+	require.NoError(t, err)
+	skipped, err := destDB.PullChunksWithTableFilter(ctx, t.TempDir(), srcDB, []hash.Hash{cmHash}, nil, nil, filter.IncludesTable)
+	require.NoError(t, err)
+	assert.Equal(t, hash.NewHashSet(skippedAddr), skipped)
+	require.NoError(t, destDB.PersistGhostCommits(ctx, skipped))
+	require.NoError(t, destDB.SetHead(ctx, ref.NewBranchRef("main"), cmHash))
+
+	has, err := destDB.Has(ctx, fetchedAddr)
+	require.NoError(t, err)
+	assert.True(t, has)
+	root := readPartialFetchRoot(t, ctx, destDB, cmHash)
+	_, _, err = root.GetTable(ctx, doltdb.TableName{Name: "skipped"})
+	assert.ErrorIs(t, err, durable.ErrTableNotFetched)
+	_, ok, err := root.GetTable(ctx, doltdb.TableName{Name: "fetched"})
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	err = root.IterTables(ctx, func(doltdb.TableName, *doltdb.Table, schema.Schema) (bool, error) {
+		return false, nil
+	})
+	assert.ErrorIs(t, err, durable.ErrTableNotFetched)
+	var iterated []string
+	unfetched, err := doltdb.IterFetchedTables(ctx, root, func(name doltdb.TableName, _ *doltdb.Table, _ schema.Schema) (bool, error) {
+		iterated = append(iterated, name.Name)
+		return false, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"fetched"}, iterated)
+	assert.Equal(t, map[doltdb.TableName]hash.Hash{{Name: "skipped"}: skippedAddr}, unfetched)
+
+	t.Run("unfetchedTableAddrs", func(t *testing.T) {
+		unfetched, err := unfetchedTableAddrs(ctx, destDB, &env.TableFilter{Tables: []string{"skipped"}})
+		require.NoError(t, err)
+		assert.Equal(t, hash.NewHashSet(skippedAddr), unfetched)
+
+		unfetched, err = unfetchedTableAddrs(ctx, destDB, filter)
+		require.NoError(t, err)
+		assert.Equal(t, 0, unfetched.Size())
+	})
+
+	t.Run("push and sync", func(t *testing.T) {
+		// the source of the partial fetch has the data of the skipped table
+		require.NoError(t, checkUnfetchedTablesOnDest(ctx, destDB, srcDB, cmHash))
+
+		otherDB := newPartialFetchTestDB(t, false)
+		err := checkUnfetchedTablesOnDest(ctx, destDB, otherDB, cmHash)
+		assert.ErrorIs(t, err, ErrPartialPushImpossible)
+		assert.Contains(t, err.Error(), "skipped")
+
+		err = SyncRoots(ctx, destDB, otherDB, t.TempDir(), nil, nil)
+		assert.ErrorIs(t, err, ErrSyncIncompleteRepo)
+	})
+
+	t.Run("FetchTables", func(t *testing.T) {
+		dbData := env.DbData{Ddb: destDB, Rsw: tempDirRepoStateWriter{dir: t.TempDir()}}
+		require.NoError(t, FetchTables(ctx, dbData, srcDB, &env.TableFilter{Tables: []string{"skipped"}}, nil, nil))
+
+		root := readPartialFetchRoot(t, ctx, destDB, cmHash)
+		_, ok, err := root.GetTable(ctx, doltdb.TableName{Name: "skipped"})
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		unfetched, err := unfetchedTableAddrs(ctx, destDB, &env.TableFilter{Tables: []string{"skipped"}})
+		require.NoError(t, err)
+		assert.Equal(t, 0, unfetched.Size())
+		hasGhosts, err := destDB.HasGhosts(ctx)
+		require.NoError(t, err)
+		assert.False(t, hasGhosts)
+	})
+}
+
+// TestPartialFetchSharedTableData checks that the data of an excluded table is fetched when an included table of
+// another commit has the same data.
+func TestPartialFetchSharedTableData(t *testing.T) {
+	ctx := context.Background()
+	srcDB := newPartialFetchTestDB(t, true)
+	sch := dtestutils.CreateSchema(schema.NewColumn("pk", 1, types.IntKind, true, schema.NotNullConstraint{}))
+	root := headPartialFetchRoot(t, ctx, srcDB)
+	root, err := doltdb.CreateEmptyTable(ctx, root, doltdb.TableName{Name: "fetched"}, sch)
+	require.NoError(t, err)
+	commitPartialFetchRoot(t, ctx, srcDB, root)
+	root, err = root.RenameTable(ctx, doltdb.TableName{Name: "fetched"}, doltdb.TableName{Name: "skipped"})
+	require.NoError(t, err)
+	cmHash := commitPartialFetchRoot(t, ctx, srcDB, root)
+
+	destDB := newPartialFetchTestDB(t, false)
+	filter, err := env.NewTableFilter(nil, []string{"skipped"})
+	require.NoError(t, err)
+	skipped, err := destDB.PullChunksWithTableFilter(ctx, t.TempDir(), srcDB, []hash.Hash{cmHash}, nil, nil, filter.IncludesTable)
+	require.NoError(t, err)
+	assert.Equal(t, 0, skipped.Size())
+
+	root = readPartialFetchRoot(t, ctx, destDB, cmHash)
+	_, ok, err := root.GetTable(ctx, doltdb.TableName{Name: "skipped"})
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+type tempDirRepoStateWriter struct {
+	env.RepoStateWriter
+	dir string
+}
+
+func (w tempDirRepoStateWriter) TempTableFilesDir() (string, error) {
+	return w.dir, nil
+}
+
+func newPartialFetchTestDB(t *testing.T, init bool) *doltdb.DoltDB {
+	ctx := context.Background()
+	ddb, err := doltdb.LoadDoltDB(ctx, types.Format_DOLT, "file://"+t.TempDir(), filesys.LocalFS)
+	require.NoError(t, err)
+	t.Cleanup(func() { ddb.Close() })
+	if init {
+		require.NoError(t, ddb.WriteEmptyRepo(ctx, "main", "billy bob", "bigbillieb@fake.horse"))
+	}
+	return ddb
+}
+
+// commitPartialFetchTables commits the tables "fetched" and "skipped" to the main branch of |ddb|, and returns the
+// address of the commit and of the tables.
+func commitPartialFetchTables(t *testing.T, ctx context.Context, ddb *doltdb.DoltDB) (hash.Hash, hash.Hash, hash.Hash) {
+	root := headPartialFetchRoot(t, ctx, ddb)
+	root, err := doltdb.CreateEmptyTable(ctx, root, doltdb.TableName{Name: "fetched"},
+		dtestutils.CreateSchema(schema.NewColumn("pk", 1, types.IntKind, true, schema.NotNullConstraint{})))
+	require.NoError(t, err)
+	root, err = doltdb.CreateEmptyTable(ctx, root, doltdb.TableName{Name: "skipped"},
+		dtestutils.CreateSchema(schema.NewColumn("id", 2, types.StringKind, true, schema.NotNullConstraint{})))
+	require.NoError(t, err)
+
+	fetchedAddr, _, err := root.GetTableHash(ctx, doltdb.TableName{Name: "fetched"})
+	require.NoError(t, err)
+	skippedAddr, _, err := root.GetTableHash(ctx, doltdb.TableName{Name: "skipped"})
+	require.NoError(t, err)
+	require.NotEqual(t, fetchedAddr, skippedAddr)
+	return commitPartialFetchRoot(t, ctx, ddb, root), fetchedAddr, skippedAddr
+}
+
+func headPartialFetchRoot(t *testing.T, ctx context.Context, ddb *doltdb.DoltDB) doltdb.RootValue {
+	cm, err := ddb.ResolveCommitRef(ctx, ref.NewBranchRef("main"))
+	require.NoError(t, err)
+	root, err := cm.GetRootValue(ctx)
+	require.NoError(t, err)
+	return root
+}
+
+func commitPartialFetchRoot(t *testing.T, ctx context.Context, ddb *doltdb.DoltDB, root doltdb.RootValue) hash.Hash {
+	_, valHash, err := ddb.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+	meta, err := datas.NewCommitMeta("billy bob", "bigbillieb@fake.horse", "partial fetch tables")
+	require.NoError(t, err)
+	cm, err := ddb.Commit(ctx, valHash, ref.NewBranchRef("main"), meta)
+	require.NoError(t, err)
+	cmHash, err := cm.HashOf()
+	require.NoError(t, err)
+	return cmHash
+}
+
+func readPartialFetchRoot(t *testing.T, ctx context.Context, ddb *doltdb.DoltDB, cmHash hash.Hash) doltdb.RootValue {
+	optCmt, err := ddb.ReadCommit(ctx, cmHash)
+	require.NoError(t, err)
+	cm, ok := optCmt.ToCommit()
+	require.True(t, ok)
+	root, err := cm.GetRootValue(ctx)
+	require.NoError(t, err)
+	return root
+}
@@ -43,6 +43,8 @@ var ErrFailedToDeleteRemote = errors.New("failed to delete remote")
 var ErrFailedToGetRemoteDb = errors.New("failed to get remote db")
 var ErrUnknownPushErr = errors.New("unknown push error")
 var ErrShallowPushImpossible = errors.New("shallow repository missing chunks to complete push")
+var ErrPartialPushImpossible = errors.New("partial clone missing table data to complete push")
+var ErrSyncIncompleteRepo = errors.New("cannot sync a shallow or partial clone, it is missing the chunks of some of its commits or tables")
 
 type ProgStarter func(ctx context.Context) (*sync.WaitGroup, chan pull.Stats)
 type ProgStopper func(cancel context.CancelFunc, wg *sync.WaitGroup, statsCh chan pull.Stats)
@@ -69,6 +71,11 @@ func Push(ctx context.Context, tempTableDir string, mode ref.UpdateMode, destRef
 		return err
 	}
 
+	err = checkUnfetchedTablesOnDest(ctx, srcDB, destDB, h)
+	if err != nil {
+		return err
+	}
+
 	err = destDB.PullChunks(ctx, tempTableDir, srcDB, []hash.Hash{h}, statsCh, nil)
 
 	if errors.Is(err, nbs.ErrGhostChunkRequested) {
@@ -103,6 +110,11 @@ func Push(ctx context.Context, tempTableDir string, mode ref.UpdateMode, destRef
 // DoPush returns a message about whether the push was successful for each branch or a tag.
 // This includes if there is a new remote branch created, upstream is set or push was rejected for a branch.
 func DoPush(ctx context.Context, pushMeta *env.PushOptions, progStarter ProgStarter, progStopper ProgStopper) (returnMsg string, err error) {
+	// archive the reflog first, so that replicas of this database see it as of the push
+	if err = pushMeta.SrcDb.ArchiveReflog(ctx); err != nil {
+		return "", err
+	}
+
 	var successPush, setUpstreamPush, failedPush []string
 	for _, targets := range pushMeta.Targets {
 		err = push(ctx, pushMeta.Rsr, pushMeta.TmpDir, pushMeta.SrcDb, pushMeta.DestDb, pushMeta.Remote, targets, progStarter, progStopper)
@@ -113,6 +125,8 @@ func DoPush(ctx context.Context, pushMeta *env.PushOptions, progStarter ProgStar
 			// response is not sufficient, as there are many "success" cases that are not errors.
 			if targets.SrcRef == ref.EmptyBranchRef {
 				successPush = append(successPush, fmt.Sprintf(" - [deleted]             %s", targets.DestRef.GetPath()))
+			} else if targets.SrcRef.GetType() == ref.NotesRefType {
+				successPush = append(successPush, fmt.Sprintf(" * [notes]               %s", targets.DestRef.String()))
 			} else {
 				successPush = append(successPush, fmt.Sprintf(" * [new branch]          %s -> %s", targets.SrcRef.GetPath(), targets.DestRef.GetPath()))
 			}
@@ -142,7 +156,7 @@ func DoPush(ctx context.Context, pushMeta *env.PushOptions, progStarter ProgStar
 	return
 }
 
-// push performs push on a branch or a tag.
+// push performs push on a branch, a tag or a note.
 func push(ctx context.Context, rsr env.RepoStateReader, tmpDir string, src, dest *doltdb.DoltDB, remote *env.Remote, opts *env.PushTarget, progStarter ProgStarter, progStopper ProgStopper) error {
 	switch opts.SrcRef.GetType() {
 	case ref.BranchRefType:
@@ -153,6 +167,8 @@ func push(ctx context.Context, rsr env.RepoStateReader, tmpDir string, src, dest
 		}
 	case ref.TagRefType:
 		return pushTagToRemote(ctx, tmpDir, opts.SrcRef, opts.DestRef, src, dest, progStarter, progStopper)
+	case ref.NotesRefType:
+		return pushNotesToRemote(ctx, tmpDir, opts.SrcRef, opts.DestRef, src, dest, progStarter, progStopper)
 	default:
 		return fmt.Errorf("%w: %s of type %s", ErrCannotPushRef, opts.SrcRef.String(), opts.SrcRef.GetType())
 	}
@@ -193,6 +209,15 @@ func PushTag(ctx context.Context, tempTableDir string, destRef ref.TagRef, srcDB
 		return err
 	}
 
+	cmHash, err := tag.Commit.HashOf()
+	if err != nil {
+		return err
+	}
+	err = checkUnfetchedTablesOnDest(ctx, srcDB, destDB, cmHash)
+	if err != nil {
+		return err
+	}
+
 	err = destDB.PullChunks(ctx, tempTableDir, srcDB, []hash.Hash{addr}, statsCh, nil)
 
 	if err != nil {
@@ -202,6 +227,24 @@ func PushTag(ctx context.Context, tempTableDir string, destRef ref.TagRef, srcDB
 	return destDB.SetHead(ctx, destRef, addr)
 }
 
+// PushNotes pushes the notes of a local source database, and all underlying data, to a remote destination database.
+// The pushed notes are merged into the notes of the destination database, replacing its notes of the same commits.
+func PushNotes(ctx context.Context, tempTableDir string, srcDB, destDB *doltdb.DoltDB, statsCh chan pull.Stats) error {
+	addr, ok, err := srcDB.NotesAddr(ctx)
+	if err != nil {
+		return err
+	} else if !ok {
+		return nil
+	}
+
+	err = destDB.PullChunks(ctx, tempTableDir, srcDB, []hash.Hash{addr}, statsCh, nil)
+	if err != nil && err != pull.ErrDBUpToDate {
+		return err
+	}
+
+	return destDB.MergeNotes(ctx, addr)
+}
+
 func deleteRemoteBranch(ctx context.Context, toDelete, remoteRef ref.DoltRef, localDB, remoteDB *doltdb.DoltDB, remote env.Remote, force bool) error {
 	err := DeleteRemoteBranch(ctx, toDelete.(ref.BranchRef), remoteRef.(ref.RemoteRef), localDB, remoteDB, force)
 
@@ -243,6 +286,10 @@ func PushToRemoteBranch(ctx context.Context, rsr env.RepoStateReader, tempTableD
 	err = Push(ctx, tempTableDir, mode, destRef.(ref.BranchRef), remoteRef.(ref.RemoteRef), localDB, remoteDB, cm, statsCh)
 	progStopper(cancelFunc, wg, statsCh)
 
+	if errors.Is(err, ErrPartialPushImpossible) {
+		return err
+	}
+
 	switch err {
 	case nil:
 		cli.Println()
@@ -274,6 +321,24 @@ func pushTagToRemote(ctx context.Context, tempTableDir string, srcRef, destRef r
 	return nil
 }
 
+func pushNotesToRemote(ctx context.Context, tempTableDir string, srcRef, destRef ref.DoltRef, localDB, remoteDB *doltdb.DoltDB, progStarter ProgStarter, progStopper ProgStopper) error {
+	if srcRef.GetPath() != ref.DefaultNotesName || destRef.GetPath() != ref.DefaultNotesName {
+		return fmt.Errorf("%w: '%s'", ref.ErrInvalidRefSpec, srcRef.String())
+	}
+
+	newCtx, cancelFunc := context.WithCancel(ctx)
+	wg, statsCh := progStarter(newCtx)
+	err := PushNotes(ctx, tempTableDir, localDB, remoteDB, statsCh)
+	progStopper(cancelFunc, wg, statsCh)
+
+	if err != nil {
+		return err
+	}
+
+	cli.Println()
+	return nil
+}
+
 // DeleteRemoteBranch validates targetRef is a branch on the remote database, and then deletes it, then deletes the
 // remote tracking branch from the local database.
 func DeleteRemoteBranch(ctx context.Context, targetRef ref.BranchRef, remoteRef ref.RemoteRef, localDB, remoteDB *doltdb.DoltDB, force bool) error {
@@ -527,6 +592,27 @@ func fetchRefSpecsWithDepth(
 	progStarter ProgStarter,
 	progStopper ProgStopper,
 ) error {
+	// Notes are not commits, and are fetched separately from the branches and tags
+	var notesSpecs []ref.NotesToNotesRefSpec
+	var headSpecs []ref.RemoteRefSpec
+	for _, rs := range refSpecs {
+		if ns, ok := rs.(ref.NotesToNotesRefSpec); ok {
+			notesSpecs = append(notesSpecs, ns)
+		} else {
+			headSpecs = append(headSpecs, rs)
+		}
+	}
+	if len(notesSpecs) > 0 {
+		err := fetchNotes(ctx, dbData, srcDB, notesSpecs, progStarter, progStopper)
+		if err != nil {
+			return err
+		}
+		if len(headSpecs) == 0 {
+			return nil
+		}
+		refSpecs = headSpecs
+	}
+
 	var branchRefs []doltdb.RefWithHash
 	err := srcDB.VisitRefsOfType(ctx, ref.HeadRefTypes, func(r ref.DoltRef, addr hash.Hash) error {
 		branchRefs = append(branchRefs, doltdb.RefWithHash{Ref: r, Hash: addr})
@@ -618,11 +704,21 @@ func fetchRefSpecsWithDepth(
 			defer progStopper(cancelFunc, wg, statsCh)
 		}
 
-		err = dbData.Ddb.PullChunks(ctx, tmpDir, srcDB, toFetch, statsCh, skipCmts)
-		if err == pull.ErrDBUpToDate {
-			err = nil
+		if remote.TableFilter.IsEmpty() {
+			err = dbData.Ddb.PullChunks(ctx, tmpDir, srcDB, toFetch, statsCh, skipCmts)
+			if err == pull.ErrDBUpToDate {
+				err = nil
+			}
+			return err
 		}
-		return err
+
+		// A partial fetch skips the data of the tables excluded by the table filter of the remote, which must be
+		// persisted as ghosts before any ref points to the commits referencing them.
+		skipTables, err := dbData.Ddb.PullChunksWithTableFilter(ctx, tmpDir, srcDB, toFetch, statsCh, skipCmts, remote.TableFilter.IncludesTable)
+		if err != nil || skipTables.Size() == 0 {
+			return err
+		}
+		return dbData.Ddb.PersistGhostCommits(ctx, skipTables)
 	}()
 	if err != nil {
 		return err
@@ -691,6 +787,57 @@ func fetchRefSpecsWithDepth(
 	return nil
 }
 
+// fetchNotes fetches the notes of the source database, along with the commits they annotate, if they match
+// |notesSpecs|. Fetched notes are merged into the local notes, replacing the local notes of the same commits.
+func fetchNotes(ctx context.Context, dbData env.DbData, srcDB *doltdb.DoltDB, notesSpecs []ref.NotesToNotesRefSpec, progStarter ProgStarter, progStopper ProgStopper) error {
+	addr, ok, err := srcDB.NotesAddr(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %s", env.ErrFailedToReadDb, err.Error())
+	}
+
+	srcRef := ref.NewNotesRef(ref.DefaultNotesName)
+	matched := false
+	for _, rs := range notesSpecs {
+		if ok && rs.DestRef(srcRef) != nil {
+			matched = true
+		} else if !rs.IsWildcard() {
+			return fmt.Errorf("%w: '%s'", ref.ErrInvalidRefSpec, rs.SrcRef(nil).String())
+		}
+	}
+	if !matched {
+		return nil
+	}
+
+	tmpDir, err := dbData.Rsw.TempTableFilesDir()
+	if err != nil {
+		return err
+	}
+
+	err = func() error {
+		newCtx := ctx
+		var statsCh chan pull.Stats
+
+		if progStarter != nil && progStopper != nil {
+			var cancelFunc func()
+			newCtx, cancelFunc = context.WithCancel(ctx)
+			var wg *sync.WaitGroup
+			wg, statsCh = progStarter(newCtx)
+			defer progStopper(cancelFunc, wg, statsCh)
+		}
+
+		err = dbData.Ddb.PullChunks(ctx, tmpDir, srcDB, []hash.Hash{addr}, statsCh, nil)
+		if err == pull.ErrDBUpToDate {
+			err = nil
+		}
+		return err
+	}()
+	if err != nil {
+		return err
+	}
+
+	return dbData.Ddb.MergeNotes(ctx, addr)
+}
+
 func buildInitialSkipList(ctx context.Context, srcDB *doltdb.DoltDB, toFetch []hash.Hash) (hash.HashSet, error) {
 	if len(toFetch) > 1 {
 		return hash.HashSet{}, fmt.Errorf("runtime error: multiple refspecs not supported in shallow clone")
@@ -814,6 +961,15 @@ func canSyncRootsWithClone(ctx context.Context, srcDb, destDb *doltdb.DoltDB, de
 // TODO     to prevent "restoring a remote", "cloning a backup", "syncing a remote" and "pushing
 // TODO     a backup." SyncRoots has more destructive potential than push right now.
 func SyncRoots(ctx context.Context, srcDb, destDb *doltdb.DoltDB, tempTableDir string, progStarter ProgStarter, progStopper ProgStopper) error {
+	// Copying the table files of a shallow or partial clone would leave the destination with dangling references.
+	hasGhosts, err := srcDb.HasGhosts(ctx)
+	if err != nil {
+		return err
+	}
+	if hasGhosts {
+		return ErrSyncIncompleteRepo
+	}
+
 	srcRoot, err := srcDb.NomsRoot(ctx)
 	if err != nil {
 		return nil
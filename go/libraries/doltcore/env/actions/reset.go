@@ -70,28 +70,21 @@ func resetHardTables(ctx *sql.Context, dbData env.DbData, cSpecStr string, roots
 	// tables in |newHead| we silently drop it from the new working set.
 	// these tag collision is typically cause by table renames (bug #751).
 
-	untracked, err := doltdb.GetAllSchemas(ctx, roots.Working)
+	untracked, err := untrackedSchemas(ctx, roots)
 	if err != nil {
 		return nil, doltdb.Roots{}, err
 	}
 
-	// untracked tables exist in |working| but not in |staged|
-	staged := GetAllTableNames(ctx, roots.Staged)
-	for _, name := range staged {
-		delete(untracked, name)
-	}
-
 	newWkRoot := roots.Head
 
-	ws, err := doltdb.GetAllSchemas(ctx, newWkRoot)
+	tags, err := doltdb.GetAllTagsForRoots(ctx, newWkRoot)
 	if err != nil {
 		return nil, doltdb.Roots{}, err
 	}
-	tags := mapColumnTags(ws)
 
 	for name, sch := range untracked {
 		for _, pk := range sch.GetAllCols().GetColumns() {
-			if _, ok := tags[pk.Tag]; ok {
+			if tags.Contains(pk.Tag) {
 				// |pk.Tag| collides with a schema in |newWkRoot|
 				delete(untracked, name)
 			}
@@ -115,9 +108,7 @@ func resetHardTables(ctx *sql.Context, dbData env.DbData, cSpecStr string, roots
 
 	// need to save the state of files that aren't tracked
 	untrackedTables := make(map[doltdb.TableName]*doltdb.Table)
-	wTblNames := GetAllTableNames(ctx, roots.Working)
-
-	for _, tblName := range wTblNames {
+	for _, tblName := range untrackedTableNames(ctx, roots) {
 		untrackedTables[tblName], _, err = roots.Working.GetTable(ctx, tblName)
 
 		if err != nil {
@@ -125,11 +116,6 @@ func resetHardTables(ctx *sql.Context, dbData env.DbData, cSpecStr string, roots
 		}
 	}
 
-	headTblNames := GetAllTableNames(ctx, roots.Staged)
-	for _, tblName := range headTblNames {
-		delete(untrackedTables, tblName)
-	}
-
 	roots.Working = newWkRoot
 	roots.Staged = roots.Head
 
@@ -137,11 +123,10 @@ func resetHardTables(ctx *sql.Context, dbData env.DbData, cSpecStr string, roots
 }
 
 func GetAllTableNames(ctx context.Context, root doltdb.RootValue) []doltdb.TableName {
-	tableNames := make([]doltdb.TableName, 0)
-	_ = root.IterTables(ctx, func(name doltdb.TableName, table *doltdb.Table, sch schema.Schema) (stop bool, err error) {
-		tableNames = append(tableNames, name)
-		return false, nil
-	})
+	tableNames, err := doltdb.UnionTableNames(ctx, root)
+	if err != nil {
+		return []doltdb.TableName{}
+	}
 	return tableNames
 }
 
@@ -326,12 +311,33 @@ func CleanUntracked(ctx *sql.Context, roots doltdb.Roots, tables []string, dryru
 
 // mapColumnTags takes a map from table name to schema.Schema and generates
 // a map from column tags to table names (see RootValue.GetAllSchemas).
-func mapColumnTags(tables map[doltdb.TableName]schema.Schema) (m map[uint64]string) {
-	m = make(map[uint64]string, len(tables))
-	for tbl, sch := range tables {
-		for _, tag := range sch.GetAllCols().Tags {
-			m[tag] = tbl.Name
+// untrackedTableNames returns the names of the tables which exist in the working root of |roots|, but not in the
+// staged root.
+func untrackedTableNames(ctx context.Context, roots doltdb.Roots) []doltdb.TableName {
+	staged := doltdb.NewTableNameSet(GetAllTableNames(ctx, roots.Staged))
+	var untracked []doltdb.TableName
+	for _, name := range GetAllTableNames(ctx, roots.Working) {
+		if !staged.Contains(name) {
+			untracked = append(untracked, name)
+		}
+	}
+	return untracked
+}
+
+// untrackedSchemas returns the schemas of the untracked tables of |roots|. Untracked tables are never excluded from a
+// partial clone or fetch, since they were created locally.
+func untrackedSchemas(ctx context.Context, roots doltdb.Roots) (map[doltdb.TableName]schema.Schema, error) {
+	schemas := make(map[doltdb.TableName]schema.Schema)
+	for _, name := range untrackedTableNames(ctx, roots) {
+		tbl, ok, err := roots.Working.GetTable(ctx, name)
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+		if schemas[name], err = tbl.GetSchema(ctx); err != nil {
+			return nil, err
 		}
 	}
-	return
+	return schemas, nil
 }
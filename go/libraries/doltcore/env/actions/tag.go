@@ -22,6 +22,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/utils/gpg"
 	"github.com/dolthub/dolt/go/store/datas"
 )
 
@@ -29,6 +30,10 @@ type TagProps struct {
 	TaggerName  string
 	TaggerEmail string
 	Description string
+	// SigningKey is the id of the key the tag is signed with. The tag is not signed if it is empty.
+	SigningKey string
+	// SigningKeys are the keys the tag may be signed with. If nil, the key is looked up in the gpg keyring.
+	SigningKeys *gpg.SigningKeys
 }
 
 func CreateTag(ctx context.Context, dEnv *env.DoltEnv, tagName, startPoint string, props TagProps) error {
@@ -72,6 +77,19 @@ func CreateTagOnDB(ctx context.Context, ddb *doltdb.DoltDB, tagName, startPoint
 
 	meta := datas.NewTagMeta(props.TaggerName, props.TaggerEmail, props.Description)
 
+	if props.SigningKey != "" {
+		h, err := cm.HashOf()
+		if err != nil {
+			return err
+		}
+
+		signature, err := props.SigningKeys.Sign(ctx, props.SigningKey, []byte(doltdb.TagSignaturePayload(tagName, h, meta)))
+		if err != nil {
+			return err
+		}
+		meta.Signature = string(signature)
+	}
+
 	return ddb.NewTagAtCommit(ctx, tagRef, cm, meta)
 }
 
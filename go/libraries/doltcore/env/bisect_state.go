@@ -0,0 +1,121 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"time"
+
+	"github.com/dolthub/fslock"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+const (
+	bisectStateFile     = "bisect.json"
+	bisectStateLockFile = "bisect.lock"
+
+	bisectStateLockTimeout = 10 * time.Second
+)
+
+var ErrBisectStateLocked = errors.New("timed out waiting for the bisect lock of the repository")
+
+// BisectState is the state of a bisect started with `dolt bisect start`. Commits are stored as hash strings. The state
+// is kept in its own file rather than in the repo state, so that sessions updating it can't clobber the rest of the
+// repo state, and the other way around.
+type BisectState struct {
+	// Bad is the commit known to have the regression being searched for
+	Bad string `json:"bad,omitempty"`
+	// Good are commits known to not have the regression
+	Good []string `json:"good,omitempty"`
+	// Skip are commits that could not be tested
+	Skip []string `json:"skip,omitempty"`
+	// Current is the commit that should be tested next
+	Current string `json:"current,omitempty"`
+}
+
+func getBisectStateFile() string {
+	return filepath.Join(dbfactory.DoltDir, bisectStateFile)
+}
+
+// LoadBisectState returns the bisect state of the repository or worktree in |fs|, or nil if no bisect is in progress.
+func LoadBisectState(fs filesys.ReadableFS) (*BisectState, error) {
+	if ok, _ := fs.Exists(getBisectStateFile()); !ok {
+		return nil, nil
+	}
+	data, err := fs.ReadFile(getBisectStateFile())
+	if err != nil {
+		return nil, err
+	}
+
+	var state BisectState
+	if err = json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// UpdateBisectState calls |update| with the current bisect state of the repository or worktree in |fs|, and saves the
+// state it returns. Returning nil ends the bisect. The state is read and written under a lock, so that concurrent
+// sessions and commands don't lose each other's updates.
+func UpdateBisectState(fs filesys.Filesys, update func(state *BisectState) (*BisectState, error)) error {
+	unlock, err := lockBisectState(fs)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := LoadBisectState(fs)
+	if err != nil {
+		return err
+	}
+	state, err = update(state)
+	if err != nil {
+		return err
+	}
+
+	if state == nil {
+		if ok, _ := fs.Exists(getBisectStateFile()); !ok {
+			return nil
+		}
+		return fs.DeleteFile(getBisectStateFile())
+	}
+	return writeJSONFile(fs, getBisectStateFile(), state)
+}
+
+func lockBisectState(fs filesys.Filesys) (func(), error) {
+	path, err := fs.Abs(filepath.Join(dbfactory.DoltDir, bisectStateLockFile))
+	if err != nil {
+		return nil, err
+	}
+	lck := filesys.CreateFilesysLock(fs, path)
+	deadline := time.Now().Add(bisectStateLockTimeout)
+	for {
+		ok, err := lck.TryLock()
+		if err != nil && !errors.Is(err, fslock.ErrLocked) {
+			return nil, err
+		}
+		if ok {
+			return func() { _ = lck.Unlock() }, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, ErrBisectStateLocked
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
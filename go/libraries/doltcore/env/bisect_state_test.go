@@ -0,0 +1,70 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+func TestUpdateBisectState(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, dbfactory.DoltDir), os.ModePerm))
+	fs, err := filesys.LocalFilesysWithWorkingDir(dir)
+	require.NoError(t, err)
+
+	state, err := LoadBisectState(fs)
+	require.NoError(t, err)
+	assert.Nil(t, state)
+
+	require.NoError(t, UpdateBisectState(fs, func(*BisectState) (*BisectState, error) {
+		return &BisectState{Bad: "bad"}, nil
+	}))
+
+	// concurrent updates must not lose each other's marks
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, UpdateBisectState(fs, func(state *BisectState) (*BisectState, error) {
+				state.Good = append(state.Good, fmt.Sprintf("good%d", i))
+				return state, nil
+			}))
+		}(i)
+	}
+	wg.Wait()
+
+	state, err = LoadBisectState(fs)
+	require.NoError(t, err)
+	assert.Equal(t, "bad", state.Bad)
+	assert.Len(t, state.Good, 10)
+
+	require.NoError(t, UpdateBisectState(fs, func(*BisectState) (*BisectState, error) {
+		return nil, nil
+	}))
+	state, err = LoadBisectState(fs)
+	require.NoError(t, err)
+	assert.Nil(t, state)
+}
@@ -93,7 +93,13 @@ func NewTestDoltCliConfigFromHierarchy(ch *config.ConfigHierarchy, fs filesys.Fi
 func LoadDoltCliConfig(hdp HomeDirProvider, fs filesys.ReadWriteFS) (*DoltCliConfig, error) {
 	ch := config.NewConfigHierarchy()
 
+	// worktrees share the local config of their repository
 	lPath := getLocalConfigPath()
+	if dir, ok, err := worktreeRepoDir(fs); err != nil {
+		return nil, err
+	} else if ok {
+		lPath = filepath.Join(dir, lPath)
+	}
 	if exists, _ := fs.Exists(lPath); exists {
 		lCfg, err := config.FromFile(lPath, fs)
 
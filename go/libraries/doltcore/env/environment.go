@@ -169,7 +169,7 @@ func LoadWithoutDB(ctx context.Context, hdp HomeDirProvider, fs filesys.Filesys,
 func Load(ctx context.Context, hdp HomeDirProvider, fs filesys.Filesys, urlStr string, version string) *DoltEnv {
 	dEnv := LoadWithoutDB(ctx, hdp, fs, version)
 
-	ddb, dbLoadErr := doltdb.LoadDoltDB(ctx, types.Format_Default, urlStr, fs)
+	ddb, dbLoadErr := loadDoltDB(ctx, fs, urlStr)
 
 	dEnv.DoltDB = ddb
 	dEnv.DBLoadError = dbLoadErr
@@ -317,7 +317,11 @@ func (dEnv *DoltEnv) HasDoltDir() bool {
 }
 
 func (dEnv *DoltEnv) HasDoltDataDir() bool {
-	exists, isDir := dEnv.FS.Exists(dbfactory.DoltDataDir)
+	dataDir, err := doltDataDir(dEnv.FS)
+	if err != nil {
+		return false
+	}
+	exists, isDir := dEnv.FS.Exists(dataDir)
 	return exists && isDir
 }
 
@@ -415,7 +419,7 @@ func (dEnv *DoltEnv) InitRepoWithCommitMetaGenerator(ctx context.Context, nbf *t
 	return err
 }
 
-func (dEnv *DoltEnv) InitRepoWithNoData(ctx context.Context, nbf *types.NomsBinFormat) error {
+func (dEnv *DoltEnv) InitRepoWithNoData(ctx context.Context, nbf *types.NomsBinFormat, params map[string]interface{}) error {
 	doltDir, err := dEnv.createDirectories(".")
 
 	if err != nil {
@@ -429,7 +433,7 @@ func (dEnv *DoltEnv) InitRepoWithNoData(ctx context.Context, nbf *types.NomsBinF
 		return err
 	}
 
-	dEnv.DoltDB, err = doltdb.LoadDoltDB(ctx, nbf, dEnv.urlStr, dEnv.FS)
+	dEnv.DoltDB, err = doltdb.LoadDoltDBWithParams(ctx, nbf, dEnv.urlStr, dEnv.FS, params)
 
 	return err
 }
@@ -754,6 +758,10 @@ func (r *repoStateWriter) AddRemote(remote Remote) error {
 	return r.DoltEnv.AddRemote(remote)
 }
 
+func (r *repoStateWriter) UpdateRemote(remote Remote) error {
+	return r.DoltEnv.UpdateRemote(remote)
+}
+
 func (r *repoStateWriter) AddBackup(remote Remote) error {
 	return r.DoltEnv.AddBackup(remote)
 }
@@ -948,6 +956,19 @@ func (dEnv *DoltEnv) AddRemote(r Remote) error {
 	return dEnv.RepoState.Save(dEnv.FS)
 }
 
+// UpdateRemote replaces the configuration of an existing remote.
+func (dEnv *DoltEnv) UpdateRemote(r Remote) error {
+	if dEnv.RSLoadErr != nil {
+		return dEnv.RSLoadErr
+	}
+	if _, ok := dEnv.RepoState.Remotes.Get(r.Name); !ok {
+		return ErrRemoteNotFound
+	}
+
+	dEnv.RepoState.AddRemote(r)
+	return dEnv.RepoState.Save(dEnv.FS)
+}
+
 func (dEnv *DoltEnv) GetBackups() (*concurrentmap.Map[string, Remote], error) {
 	if dEnv.RSLoadErr != nil {
 		return nil, dEnv.RSLoadErr
@@ -216,6 +216,10 @@ func (m MemoryRepoState) AddRemote(r Remote) error {
 	return fmt.Errorf("cannot insert a remote in a memory database")
 }
 
+func (m MemoryRepoState) UpdateRemote(r Remote) error {
+	return fmt.Errorf("cannot update a remote in a memory database")
+}
+
 func (m MemoryRepoState) GetBranches() (*concurrentmap.Map[string, BranchConfig], error) {
 	return concurrentmap.New[string, BranchConfig](), nil
 }
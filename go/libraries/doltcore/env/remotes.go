@@ -68,7 +68,7 @@ var ErrFailedToPush = goerrors.NewKind("error: failed to push some refs to '%s'\
 	"hint: 'dolt pull ...') before pushing again.\n")
 
 func IsEmptyRemote(r Remote) bool {
-	return len(r.Name) == 0 && len(r.Url) == 0 && r.FetchSpecs == nil && r.Params == nil
+	return len(r.Name) == 0 && len(r.Url) == 0 && r.FetchSpecs == nil && r.Params == nil && r.TableFilter == nil
 }
 
 type Remote struct {
@@ -76,10 +76,12 @@ type Remote struct {
 	Url        string            `json:"url"`
 	FetchSpecs []string          `json:"fetch_specs"`
 	Params     map[string]string `json:"params"`
+	// TableFilter is the table filter of a partial clone, applied to every fetch from this remote.
+	TableFilter *TableFilter `json:"table_filter,omitempty"`
 }
 
 func NewRemote(name, url string, params map[string]string) Remote {
-	return Remote{name, url, []string{"refs/heads/*:refs/remotes/" + name + "/*"}, params}
+	return Remote{Name: name, Url: url, FetchSpecs: []string{"refs/heads/*:refs/remotes/" + name + "/*"}, Params: params}
 }
 
 func (r *Remote) GetParam(pName string) (string, bool) {
@@ -295,6 +297,11 @@ func getPushTargetsAndRemoteForAllBranches(ctx context.Context, rsrBranches *con
 }
 
 func getPushTargetsAndRemoteForBranchRefs(ctx context.Context, rsrBranches *concurrentmap.Map[string, BranchConfig], localBranches []string, currentBranch ref.DoltRef, remote *Remote, ddb *doltdb.DoltDB, force, setUpstream bool) ([]*PushTarget, *Remote, error) {
+	localBranches, err := expandNotesRefSpecs(ctx, ddb, localBranches)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var pushOptsList []*PushTarget
 	for _, refSpecName := range localBranches {
 		refSpec, err := getRefSpecFromStr(ctx, ddb, refSpecName)
@@ -326,7 +333,7 @@ func getPushTargetFromRefSpec(refSpec ref.RefSpec, currentBranch ref.DoltRef, re
 	switch src.GetType() {
 	case ref.BranchRefType:
 		remoteRef, err = GetTrackingRef(dest, *remote)
-	case ref.TagRefType:
+	case ref.TagRefType, ref.NotesRefType:
 		if setUpstream {
 			err = ErrCannotSetUpstreamForTag
 		}
@@ -349,6 +356,28 @@ func getPushTargetFromRefSpec(refSpec ref.RefSpec, currentBranch ref.DoltRef, re
 	}, nil
 }
 
+// expandNotesRefSpecs replaces each refspec matching every notes ref, e.g. refs/notes/*, with the notes ref of |ddb|,
+// if it has any notes.
+func expandNotesRefSpecs(ctx context.Context, ddb *doltdb.DoltDB, refSpecNames []string) ([]string, error) {
+	var expanded []string
+	for _, refSpecName := range refSpecNames {
+		rs, err := ref.ParseRefSpec(refSpecName)
+		if ns, ok := rs.(ref.NotesToNotesRefSpec); err != nil || !ok || !ns.IsWildcard() {
+			expanded = append(expanded, refSpecName)
+			continue
+		}
+
+		_, ok, err := ddb.NotesAddr(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if notesRef := ref.NewNotesRef(ref.DefaultNotesName); ok && rs.DestRef(notesRef) != nil {
+			expanded = append(expanded, notesRef.String())
+		}
+	}
+	return expanded, nil
+}
+
 // getCurrentBranchRefSpec is called when refSpec is NOT specified. Whether to push depends on the specified remote.
 // If the specified remote is the default or the only remote, then it cannot push without its upstream set.
 // If the specified remote is one of many and non-default remote, then it pushes regardless of upstream is set.
@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/json"
 	"os"
+	"path/filepath"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
@@ -40,6 +41,7 @@ type RepoStateWriter interface {
 	// TODO: kill this
 	SetCWBHeadRef(context.Context, ref.MarshalableRef) error
 	AddRemote(r Remote) error
+	UpdateRemote(r Remote) error
 	AddBackup(r Remote) error
 	RemoveRemote(ctx context.Context, name string) error
 	RemoveBackup(ctx context.Context, name string) error
@@ -144,9 +146,37 @@ func (rs *repoStateLegacy) save(fs filesys.ReadWriteFS) error {
 	return fs.WriteFile(getRepoStateFile(), data, os.ModePerm)
 }
 
-// LoadRepoState parses the repo state file from the file system given
+// LoadRepoState parses the repo state file from the file system given. The repo state of a worktree is the repo state of
+// its repository, with the head of the worktree.
 func LoadRepoState(fs filesys.ReadWriteFS) (*RepoState, error) {
-	path := getRepoStateFile()
+	dir, ok, err := worktreeRepoDir(fs)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return loadRepoStateFile(fs, getRepoStateFile())
+	}
+
+	rs, err := loadRepoStateFile(fs, filepath.Join(dir, getRepoStateFile()))
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := fs.ReadFile(getRepoStateFile())
+	if err != nil {
+		return nil, err
+	}
+
+	var wrs worktreeRepoState
+	if err = json.Unmarshal(data, &wrs); err != nil {
+		return nil, err
+	}
+
+	rs.Head = wrs.Head
+	rs.staged, rs.working, rs.merge = "", "", nil
+	return rs, nil
+}
+
+func loadRepoStateFile(fs filesys.ReadableFS, path string) (*RepoState, error) {
 	data, err := fs.ReadFile(path)
 
 	if err != nil {
@@ -208,14 +238,29 @@ func CreateRepoState(fs filesys.ReadWriteFS, br string) (*RepoState, error) {
 	return rs, nil
 }
 
-// Save writes this repo state file to disk on the filesystem given
+// Save writes this repo state file to disk on the filesystem given. For a worktree, the head is
+// written to the worktree, and the rest to its repository.
 func (rs RepoState) Save(fs filesys.ReadWriteFS) error {
-	data, err := json.MarshalIndent(rs, "", "  ")
+	dir, ok, err := worktreeRepoDir(fs)
 	if err != nil {
 		return err
+	} else if !ok {
+		return writeJSONFile(fs, getRepoStateFile(), rs)
 	}
 
-	return fs.WriteFile(getRepoStateFile(), data, os.ModePerm)
+	err = writeJSONFile(fs, getRepoStateFile(), worktreeRepoState{Head: rs.Head})
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, getRepoStateFile())
+	repoRS, err := loadRepoStateFile(fs, path)
+	if err != nil {
+		return err
+	}
+	repoRS.Remotes, repoRS.Backups, repoRS.Branches = rs.Remotes, rs.Backups, rs.Branches
+
+	return writeJSONFile(fs, path, repoRS)
 }
 
 func (rs *RepoState) CWBHeadRef() ref.DoltRef {
@@ -0,0 +1,138 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+)
+
+var ErrTablesAndExcludeTables = errors.New("--tables and --exclude-tables can't be combined")
+var ErrAllTablesExcluded = errors.New("every table fetched from the remote would be excluded")
+
+// TableFilter restricts the tables whose data is fetched from a remote. A partial clone records its filter on the
+// remote it was cloned from, so that later fetches and pulls from that remote transfer the same tables. Dolt system
+// tables, such as dolt_schemas and dolt_docs, are always fetched.
+type TableFilter struct {
+	// Tables are the only tables fetched when not empty.
+	Tables []string `json:"tables,omitempty"`
+	// ExcludeTables are tables which are not fetched.
+	ExcludeTables []string `json:"exclude_tables,omitempty"`
+}
+
+// NewTableFilter returns the TableFilter for the given --tables and --exclude-tables values, or nil if both are
+// empty.
+func NewTableFilter(tables, excludeTables []string) (*TableFilter, error) {
+	tables, excludeTables = normalizeTableNames(tables), normalizeTableNames(excludeTables)
+	if len(tables) > 0 && len(excludeTables) > 0 {
+		return nil, ErrTablesAndExcludeTables
+	}
+	if len(tables) == 0 && len(excludeTables) == 0 {
+		return nil, nil
+	}
+	return &TableFilter{Tables: tables, ExcludeTables: excludeTables}, nil
+}
+
+// IsEmpty returns whether the filter fetches every table.
+func (f *TableFilter) IsEmpty() bool {
+	return f == nil || (len(f.Tables) == 0 && len(f.ExcludeTables) == 0)
+}
+
+// Includes returns whether the data of the table named |name| is fetched.
+func (f *TableFilter) Includes(name string) bool {
+	if f.IsEmpty() || doltdb.HasDoltPrefix(name) {
+		return true
+	}
+	if len(f.Tables) > 0 {
+		return containsTableName(f.Tables, name)
+	}
+	return !containsTableName(f.ExcludeTables, name)
+}
+
+// IncludesTable is Includes for a doltdb.TableName.
+func (f *TableFilter) IncludesTable(name doltdb.TableName) bool {
+	return f.Includes(name.Name)
+}
+
+// Merge returns the filter resulting from fetching with |other| in a repository cloned with |f|: the tables of
+// |other.Tables| are added to the fetched tables, and the tables of |other.ExcludeTables| are no longer fetched.
+func (f *TableFilter) Merge(other *TableFilter) (*TableFilter, error) {
+	if other.IsEmpty() {
+		return f, nil
+	}
+
+	var merged TableFilter
+	if f != nil {
+		merged.Tables = append(merged.Tables, f.Tables...)
+		merged.ExcludeTables = append(merged.ExcludeTables, f.ExcludeTables...)
+	}
+
+	for _, name := range other.Tables {
+		if len(merged.Tables) == 0 {
+			merged.ExcludeTables = removeTableName(merged.ExcludeTables, name)
+		} else if !containsTableName(merged.Tables, name) {
+			merged.Tables = append(merged.Tables, name)
+		}
+	}
+	for _, name := range other.ExcludeTables {
+		if len(merged.Tables) == 0 {
+			if !containsTableName(merged.ExcludeTables, name) {
+				merged.ExcludeTables = append(merged.ExcludeTables, name)
+			}
+			continue
+		}
+		merged.Tables = removeTableName(merged.Tables, name)
+		if len(merged.Tables) == 0 {
+			return nil, ErrAllTablesExcluded
+		}
+	}
+
+	if merged.IsEmpty() {
+		return nil, nil
+	}
+	return &merged, nil
+}
+
+func normalizeTableNames(names []string) []string {
+	var normalized []string
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name != "" && !containsTableName(normalized, name) {
+			normalized = append(normalized, name)
+		}
+	}
+	return normalized
+}
+
+func containsTableName(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func removeTableName(names []string, name string) []string {
+	var remaining []string
+	for _, n := range names {
+		if !strings.EqualFold(n, name) {
+			remaining = append(remaining, n)
+		}
+	}
+	return remaining
+}
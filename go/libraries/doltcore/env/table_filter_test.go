@@ -0,0 +1,116 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTableFilter(t *testing.T) {
+	f, err := NewTableFilter(nil, []string{""})
+	require.NoError(t, err)
+	assert.Nil(t, f)
+	assert.True(t, f.IsEmpty())
+	assert.True(t, f.Includes("t1"))
+
+	_, err = NewTableFilter([]string{"t1"}, []string{"t2"})
+	assert.Equal(t, ErrTablesAndExcludeTables, err)
+
+	f, err = NewTableFilter([]string{"t1", " T1 ", "t2"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t1", "t2"}, f.Tables)
+	assert.True(t, f.Includes("T1"))
+	assert.False(t, f.Includes("t3"))
+	assert.True(t, f.Includes("dolt_schemas"))
+
+	f, err = NewTableFilter(nil, []string{"t1"})
+	require.NoError(t, err)
+	assert.False(t, f.Includes("t1"))
+	assert.True(t, f.Includes("t2"))
+}
+
+func TestTableFilterMerge(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   *TableFilter
+		other    *TableFilter
+		expected *TableFilter
+		err      error
+	}{
+		{
+			name:     "no filter",
+			filter:   nil,
+			other:    &TableFilter{ExcludeTables: []string{"t1"}},
+			expected: &TableFilter{ExcludeTables: []string{"t1"}},
+		},
+		{
+			name:     "empty other",
+			filter:   &TableFilter{Tables: []string{"t1"}},
+			other:    nil,
+			expected: &TableFilter{Tables: []string{"t1"}},
+		},
+		{
+			name:     "include excluded table",
+			filter:   &TableFilter{ExcludeTables: []string{"t1", "t2"}},
+			other:    &TableFilter{Tables: []string{"T1"}},
+			expected: &TableFilter{ExcludeTables: []string{"t2"}},
+		},
+		{
+			name:     "include every excluded table",
+			filter:   &TableFilter{ExcludeTables: []string{"t1"}},
+			other:    &TableFilter{Tables: []string{"t1"}},
+			expected: nil,
+		},
+		{
+			name:     "add table",
+			filter:   &TableFilter{Tables: []string{"t1"}},
+			other:    &TableFilter{Tables: []string{"t1", "t2"}},
+			expected: &TableFilter{Tables: []string{"t1", "t2"}},
+		},
+		{
+			name:     "exclude more tables",
+			filter:   &TableFilter{ExcludeTables: []string{"t1"}},
+			other:    &TableFilter{ExcludeTables: []string{"t2"}},
+			expected: &TableFilter{ExcludeTables: []string{"t1", "t2"}},
+		},
+		{
+			name:     "exclude fetched table",
+			filter:   &TableFilter{Tables: []string{"t1", "t2"}},
+			other:    &TableFilter{ExcludeTables: []string{"t2"}},
+			expected: &TableFilter{Tables: []string{"t1"}},
+		},
+		{
+			name:   "exclude every fetched table",
+			filter: &TableFilter{Tables: []string{"t1"}},
+			other:  &TableFilter{ExcludeTables: []string{"t1"}},
+			err:    ErrAllTablesExcluded,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged, err := test.filter.Merge(test.other)
+			if test.err != nil {
+				assert.Equal(t, test.err, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, merged)
+		})
+	}
+}
@@ -0,0 +1,475 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dolthub/fslock"
+	goerrors "gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/utils/earl"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// A worktree is a directory with its own checked out branch, which shares the database of the repository it was created
+// from. The .dolt directory of a worktree has no noms directory. Instead, it has a worktree file holding the path of the
+// repository, and a repo state file holding only the checked out branch. The rest of the repo state and the local config
+// are read from and written to the repository. The repository keeps a list of its worktrees, which is used to ensure a
+// branch is never checked out in two of them at once, since they would share its working set.
+
+const (
+	worktreeFile      = "worktree.json"
+	worktreesFile     = "worktrees.json"
+	worktreesLockFile = "worktrees.lock"
+
+	worktreesLockTimeout = 10 * time.Second
+)
+
+var ErrWorktreeExists = errors.New("worktree path already exists")
+var ErrWorktreeNotFound = errors.New("not a worktree of this repository")
+var ErrRemoveMainWorktree = errors.New("the main worktree of a repository cannot be removed")
+var ErrRemoveCurrentWorktree = errors.New("the current worktree cannot be removed")
+var ErrWorktreeDirty = errors.New("the worktree has uncommitted changes")
+var ErrWorktreesLocked = errors.New("timed out waiting for the worktrees lock of the repository")
+var ErrBranchCheckedOutInWorktree = goerrors.NewKind("branch '%s' is already checked out at '%s'")
+
+// Worktree is a directory in which a branch of a repository is checked out.
+type Worktree struct {
+	// Path is the absolute path of the worktree
+	Path string
+	// Head is the branch checked out in the worktree, or nil if the worktree no longer exists on disk
+	Head ref.DoltRef
+	// Main is true for the directory of the repository itself
+	Main bool
+}
+
+// WorktreeChecker is implemented by the repo state of environments which can have worktrees. It is used by the
+// actions which switch, rename or delete branches, so that they can't be run against a branch checked out in another
+// worktree, whether they are run from the command line or from SQL.
+type WorktreeChecker interface {
+	// CheckBranchNotCheckedOut returns an ErrBranchCheckedOutInWorktree error if |branch| is checked out in another
+	// worktree of the repository.
+	CheckBranchNotCheckedOut(branch ref.BranchRef) error
+}
+
+// worktreeLink is the contents of the worktree file of a worktree.
+type worktreeLink struct {
+	Repo string `json:"repo"`
+}
+
+// worktreeList is the contents of the worktrees file of a repository.
+type worktreeList struct {
+	Worktrees []string `json:"worktrees"`
+}
+
+// worktreeRepoState is the repo state file of a worktree.
+type worktreeRepoState struct {
+	Head ref.MarshalableRef `json:"head"`
+}
+
+func getWorktreeFile() string {
+	return filepath.Join(dbfactory.DoltDir, worktreeFile)
+}
+
+// worktreeRepoDir returns the absolute path of the repository which the directory of |fs| is a worktree of, and false
+// if it isn't a worktree.
+func worktreeRepoDir(fs filesys.ReadableFS) (string, bool, error) {
+	path := getWorktreeFile()
+	if exists, isDir := fs.Exists(path); !exists || isDir {
+		return "", false, nil
+	}
+
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		return "", false, err
+	}
+
+	var link worktreeLink
+	if err = json.Unmarshal(data, &link); err != nil {
+		return "", false, err
+	}
+	if link.Repo == "" {
+		return "", false, errors.New("invalid worktree file: " + path)
+	}
+
+	return link.Repo, true, nil
+}
+
+// repoDir returns the absolute path of the repository of the directory of |fs|, which is the directory itself unless
+// it is a worktree.
+func repoDir(fs filesys.ReadableFS) (string, error) {
+	dir, ok, err := worktreeRepoDir(fs)
+	if err != nil || ok {
+		return dir, err
+	}
+	return fs.Abs(".")
+}
+
+// doltDataDir returns the path of the noms directory used by the directory of |fs|.
+func doltDataDir(fs filesys.ReadableFS) (string, error) {
+	dir, ok, err := worktreeRepoDir(fs)
+	if err != nil {
+		return "", err
+	} else if ok {
+		return filepath.Join(dir, dbfactory.DoltDataDir), nil
+	}
+	return dbfactory.DoltDataDir, nil
+}
+
+// loadDoltDB loads the database at |urlStr|. The local database of a worktree is the database of its repository.
+func loadDoltDB(ctx context.Context, fs filesys.Filesys, urlStr string) (*doltdb.DoltDB, error) {
+	if urlStr != doltdb.LocalDirDoltDB {
+		return doltdb.LoadDoltDB(ctx, types.Format_Default, urlStr, fs)
+	}
+
+	dir, ok, err := worktreeRepoDir(fs)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return doltdb.LoadDoltDB(ctx, types.Format_Default, urlStr, fs)
+	}
+
+	dataDir := filepath.Join(dir, dbfactory.DoltDataDir)
+	if exists, isDir := fs.Exists(dataDir); !exists || !isDir {
+		return nil, doltdb.ErrMissingDoltDataDir
+	}
+
+	urlStr = earl.FileUrlFromPath(filepath.ToSlash(dataDir), os.PathSeparator)
+	params := map[string]interface{}{dbfactory.ChunkJournalParam: struct{}{}, dbfactory.LocalDatabaseParam: struct{}{}}
+	return doltdb.LoadDoltDBWithParams(ctx, types.Format_Default, urlStr, fs, params)
+}
+
+// IsWorktree returns whether this environment is a worktree of another repository.
+func (dEnv *DoltEnv) IsWorktree() bool {
+	_, ok, err := worktreeRepoDir(dEnv.FS)
+	return err == nil && ok
+}
+
+// Worktrees returns the worktrees of the repository of this environment, starting with the repository itself.
+func (dEnv *DoltEnv) Worktrees() ([]Worktree, error) {
+	dir, err := repoDir(dEnv.FS)
+	if err != nil {
+		return nil, err
+	}
+	return dEnv.worktrees(dir)
+}
+
+// AddWorktree creates a worktree of the repository of this environment at |path|, with |branch| checked out, and
+// returns its absolute path. |path| must not exist or be an empty directory, and |branch| must not be checked out in
+// another worktree.
+func (dEnv *DoltEnv) AddWorktree(path string, branch ref.BranchRef) (string, error) {
+	absPath, err := dEnv.FS.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := repoDir(dEnv.FS)
+	if err != nil {
+		return "", err
+	}
+
+	unlock, err := dEnv.lockWorktrees(dir)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	if exists, isDir := dEnv.FS.Exists(absPath); exists {
+		empty := isDir
+		if isDir {
+			err = dEnv.FS.Iter(absPath, false, func(string, int64, bool) bool {
+				empty = false
+				return true
+			})
+			if err != nil {
+				return "", err
+			}
+		}
+		if !empty {
+			return "", ErrWorktreeExists
+		}
+	}
+
+	worktrees, err := dEnv.worktrees(dir)
+	if err != nil {
+		return "", err
+	}
+	if err = checkBranchNotCheckedOut(worktrees, branch, ""); err != nil {
+		return "", err
+	}
+
+	err = dEnv.FS.MkDirs(filepath.Join(absPath, dbfactory.DoltDir, tempTablesDir))
+	if err != nil {
+		return "", err
+	}
+
+	err = writeJSONFile(dEnv.FS, filepath.Join(absPath, getWorktreeFile()), worktreeLink{Repo: dir})
+	if err == nil {
+		err = writeJSONFile(dEnv.FS, filepath.Join(absPath, getRepoStateFile()), worktreeRepoState{Head: ref.MarshalableRef{Ref: branch}})
+	}
+	if err == nil {
+		list := worktreeList{Worktrees: make([]string, 0, len(worktrees))}
+		for _, wt := range worktrees[1:] {
+			list.Worktrees = append(list.Worktrees, wt.Path)
+		}
+		list.Worktrees = append(list.Worktrees, absPath)
+		err = writeJSONFile(dEnv.FS, filepath.Join(dir, dbfactory.DoltDir, worktreesFile), list)
+	}
+	if err != nil {
+		_ = dEnv.FS.Delete(absPath, true)
+		return "", err
+	}
+
+	return absPath, nil
+}
+
+// RemoveWorktree deletes the worktree at |path| of the repository of this environment. The working set of the branch
+// checked out in it is kept, since it is stored in the database, but it is no longer checked out anywhere, so unless
+// |force| is true, a worktree with uncommitted changes is not removed and ErrWorktreeDirty is returned.
+func (dEnv *DoltEnv) RemoveWorktree(ctx context.Context, path string, force bool) error {
+	absPath, err := dEnv.FS.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	dir, err := repoDir(dEnv.FS)
+	if err != nil {
+		return err
+	}
+	if filepath.Clean(absPath) == filepath.Clean(dir) {
+		return ErrRemoveMainWorktree
+	}
+	if self, err := dEnv.FS.Abs("."); err != nil {
+		return err
+	} else if filepath.Clean(absPath) == filepath.Clean(self) {
+		return ErrRemoveCurrentWorktree
+	}
+
+	unlock, err := dEnv.lockWorktrees(dir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	list, err := readWorktreeList(dEnv.FS, dir)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	remaining := make([]string, 0, len(list.Worktrees))
+	for _, wt := range list.Worktrees {
+		if filepath.Clean(wt) == filepath.Clean(absPath) {
+			found = true
+		} else {
+			remaining = append(remaining, wt)
+		}
+	}
+	if !found {
+		return ErrWorktreeNotFound
+	}
+
+	if !force {
+		worktrees, err := dEnv.worktrees(dir)
+		if err != nil {
+			return err
+		}
+		for _, wt := range worktrees {
+			branch, ok := wt.Head.(ref.BranchRef)
+			if !ok || filepath.Clean(wt.Path) != filepath.Clean(absPath) {
+				continue
+			}
+			if dirty, err := dEnv.isBranchDirty(ctx, branch); err != nil {
+				return err
+			} else if dirty {
+				return ErrWorktreeDirty
+			}
+		}
+	}
+
+	if exists, _ := dEnv.FS.Exists(absPath); exists {
+		if err = dEnv.FS.Delete(absPath, true); err != nil {
+			return err
+		}
+	}
+
+	list.Worktrees = remaining
+	return writeJSONFile(dEnv.FS, filepath.Join(dir, dbfactory.DoltDir, worktreesFile), list)
+}
+
+// CheckBranchNotCheckedOut returns an ErrBranchCheckedOutInWorktree error if |branch| is checked out in a worktree of
+// the repository of this environment other than this one.
+func (dEnv *DoltEnv) CheckBranchNotCheckedOut(branch ref.BranchRef) error {
+	dir, err := repoDir(dEnv.FS)
+	if err != nil {
+		return err
+	}
+
+	// without worktrees, there is no other directory the branch can be checked out in
+	if list, err := readWorktreeList(dEnv.FS, dir); err != nil || len(list.Worktrees) == 0 {
+		return err
+	}
+
+	worktrees, err := dEnv.worktrees(dir)
+	if err != nil {
+		return err
+	}
+
+	self, err := dEnv.FS.Abs(".")
+	if err != nil {
+		return err
+	}
+
+	return checkBranchNotCheckedOut(worktrees, branch, self)
+}
+
+// LockWorktrees locks the worktrees of the repository of this environment until the function returned is called, so
+// that a branch can be checked out without racing against another worktree checking it out.
+func (dEnv *DoltEnv) LockWorktrees() (func(), error) {
+	dir, err := repoDir(dEnv.FS)
+	if err != nil {
+		return nil, err
+	}
+	return dEnv.lockWorktrees(dir)
+}
+
+func (dEnv *DoltEnv) lockWorktrees(dir string) (func(), error) {
+	lck := filesys.CreateFilesysLock(dEnv.FS, filepath.Join(dir, dbfactory.DoltDir, worktreesLockFile))
+	deadline := time.Now().Add(worktreesLockTimeout)
+	for {
+		ok, err := lck.TryLock()
+		if err != nil && !errors.Is(err, fslock.ErrLocked) {
+			return nil, err
+		}
+		if ok {
+			return func() { _ = lck.Unlock() }, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, ErrWorktreesLocked
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func (dEnv *DoltEnv) worktrees(dir string) ([]Worktree, error) {
+	list, err := readWorktreeList(dEnv.FS, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	rs, err := loadRepoStateFile(dEnv.FS, filepath.Join(dir, getRepoStateFile()))
+	if err != nil {
+		return nil, err
+	}
+
+	worktrees := []Worktree{{Path: dir, Head: rs.Head.Ref, Main: true}}
+	for _, path := range list.Worktrees {
+		wt := Worktree{Path: path}
+		data, err := dEnv.FS.ReadFile(filepath.Join(path, getRepoStateFile()))
+		if err == nil {
+			var wrs worktreeRepoState
+			if err = json.Unmarshal(data, &wrs); err != nil {
+				return nil, err
+			}
+			wt.Head = wrs.Head.Ref
+		}
+		worktrees = append(worktrees, wt)
+	}
+
+	return worktrees, nil
+}
+
+// isBranchDirty returns whether the working set of |branch| has changes which aren't committed to it.
+func (dEnv *DoltEnv) isBranchDirty(ctx context.Context, branch ref.BranchRef) (bool, error) {
+	if dEnv.DoltDB == nil {
+		return false, dEnv.DBLoadError
+	}
+
+	roots, err := dEnv.DoltDB.ResolveBranchRoots(ctx, branch)
+	if errors.Is(err, doltdb.ErrBranchNotFound) || errors.Is(err, doltdb.ErrWorkingSetNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	// roots which differ only by their feature version have no changes
+	version, _, err := roots.Head.GetFeatureVersion(ctx)
+	if err != nil {
+		return false, err
+	}
+	headHash, err := roots.Head.HashOf()
+	if err != nil {
+		return false, err
+	}
+	for _, root := range []doltdb.RootValue{roots.Working, roots.Staged} {
+		root, err = root.SetFeatureVersion(version)
+		if err != nil {
+			return false, err
+		}
+		h, err := root.HashOf()
+		if err != nil {
+			return false, err
+		}
+		if h != headHash {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func checkBranchNotCheckedOut(worktrees []Worktree, branch ref.BranchRef, self string) error {
+	for _, wt := range worktrees {
+		if wt.Head == nil || (self != "" && filepath.Clean(wt.Path) == filepath.Clean(self)) {
+			continue
+		}
+		if ref.Equals(wt.Head, branch) {
+			return ErrBranchCheckedOutInWorktree.New(branch.GetPath(), wt.Path)
+		}
+	}
+	return nil
+}
+
+func readWorktreeList(fs filesys.ReadableFS, dir string) (worktreeList, error) {
+	var list worktreeList
+	path := filepath.Join(dir, dbfactory.DoltDir, worktreesFile)
+	if exists, _ := fs.Exists(path); !exists {
+		return list, nil
+	}
+
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		return list, err
+	}
+	err = json.Unmarshal(data, &list)
+	return list, err
+}
+
+func writeJSONFile(fs filesys.WritableFS, path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fs.WriteFile(path, data, os.ModePerm)
+}
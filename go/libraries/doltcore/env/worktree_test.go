@@ -0,0 +1,157 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestWorktrees(t *testing.T) {
+	dEnv, fs := createTestEnv(true, true)
+	wtDir := filepath.Join(filepath.Dir(workingDir), "feature")
+
+	absPath, err := dEnv.AddWorktree(wtDir, ref.NewBranchRef("feature"))
+	require.NoError(t, err)
+	assert.Equal(t, wtDir, absPath)
+
+	_, err = dEnv.AddWorktree(wtDir, ref.NewBranchRef("other"))
+	assert.Equal(t, ErrWorktreeExists, err)
+	_, err = dEnv.AddWorktree(filepath.Join(filepath.Dir(workingDir), "other"), ref.NewBranchRef("feature"))
+	assert.True(t, ErrBranchCheckedOutInWorktree.Is(err))
+
+	wtFS, err := fs.WithWorkingDir(wtDir)
+	require.NoError(t, err)
+	wtEnv := Load(context.Background(), testHomeDirFunc, wtFS, doltdb.InMemDoltDB, "test")
+	require.NoError(t, wtEnv.RSLoadErr)
+	assert.True(t, wtEnv.IsWorktree())
+	assert.False(t, dEnv.IsWorktree())
+	assert.True(t, wtEnv.HasDoltDataDir())
+	dataDir, err := doltDataDir(wtFS)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(workingDir, dbfactory.DoltDataDir), dataDir)
+
+	t.Run("repo state", func(t *testing.T) {
+		assert.Equal(t, ref.NewBranchRef("feature"), wtEnv.RepoState.CWBHeadRef())
+		assert.Equal(t, ref.NewBranchRef(DefaultInitBranch), dEnv.RepoState.CWBHeadRef())
+
+		// remotes are written to the repository, the head to the worktree
+		wtEnv.RepoState.AddRemote(NewRemote("origin", "file:///remote", nil))
+		wtEnv.RepoState.Head = ref.MarshalableRef{Ref: ref.NewBranchRef("other")}
+		require.NoError(t, wtEnv.RepoState.Save(wtFS))
+
+		rs, err := LoadRepoState(fs)
+		require.NoError(t, err)
+		assert.Equal(t, ref.NewBranchRef(DefaultInitBranch), rs.CWBHeadRef())
+		_, ok := rs.Remotes.Get("origin")
+		assert.True(t, ok)
+
+		rs, err = LoadRepoState(wtFS)
+		require.NoError(t, err)
+		assert.Equal(t, ref.NewBranchRef("other"), rs.CWBHeadRef())
+		_, ok = rs.Remotes.Get("origin")
+		assert.True(t, ok)
+	})
+
+	t.Run("config", func(t *testing.T) {
+		userName, err := wtEnv.Config.GetString("user.name")
+		require.NoError(t, err)
+		assert.Equal(t, "bheni", userName)
+	})
+
+	t.Run("checked out branches", func(t *testing.T) {
+		worktrees, err := wtEnv.Worktrees()
+		require.NoError(t, err)
+		require.Len(t, worktrees, 2)
+		assert.Equal(t, Worktree{Path: workingDir, Head: ref.NewBranchRef(DefaultInitBranch), Main: true}, worktrees[0])
+		assert.Equal(t, Worktree{Path: wtDir, Head: ref.NewBranchRef("other")}, worktrees[1])
+
+		assert.NoError(t, dEnv.CheckBranchNotCheckedOut(ref.NewBranchRef("feature")))
+		assert.NoError(t, dEnv.CheckBranchNotCheckedOut(ref.NewBranchRef(DefaultInitBranch)))
+		assert.True(t, ErrBranchCheckedOutInWorktree.Is(dEnv.CheckBranchNotCheckedOut(ref.NewBranchRef("other"))))
+		assert.NoError(t, wtEnv.CheckBranchNotCheckedOut(ref.NewBranchRef("other")))
+		assert.True(t, ErrBranchCheckedOutInWorktree.Is(wtEnv.CheckBranchNotCheckedOut(ref.NewBranchRef(DefaultInitBranch))))
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		assert.Equal(t, ErrRemoveMainWorktree, wtEnv.RemoveWorktree(context.Background(), workingDir, false))
+		assert.Equal(t, ErrRemoveCurrentWorktree, wtEnv.RemoveWorktree(context.Background(), wtDir, false))
+		require.NoError(t, dEnv.RemoveWorktree(context.Background(), wtDir, false))
+		assert.Equal(t, ErrWorktreeNotFound, dEnv.RemoveWorktree(context.Background(), wtDir, false))
+
+		exists, _ := fs.Exists(wtDir)
+		assert.False(t, exists)
+		worktrees, err := dEnv.Worktrees()
+		require.NoError(t, err)
+		assert.Len(t, worktrees, 1)
+	})
+}
+
+func TestRemoveDirtyWorktree(t *testing.T) {
+	ctx := context.Background()
+	dEnv, fs := createTestEnv(false, false)
+	require.NoError(t, dEnv.InitRepo(ctx, types.Format_Default, "bheni", "bheni@dolthub.com", DefaultInitBranch))
+
+	ddb := dEnv.DoltDB
+	head, err := ddb.ResolveCommitRef(ctx, ref.NewBranchRef(DefaultInitBranch))
+	require.NoError(t, err)
+	feature := ref.NewBranchRef("feature")
+	require.NoError(t, ddb.NewBranchAtCommit(ctx, feature, head, nil))
+
+	wtDir := filepath.Join(filepath.Dir(workingDir), "feature")
+	_, err = dEnv.AddWorktree(wtDir, feature)
+	require.NoError(t, err)
+
+	// a worktree without changes is removed
+	require.NoError(t, dEnv.RemoveWorktree(ctx, wtDir, false))
+	_, err = dEnv.AddWorktree(wtDir, feature)
+	require.NoError(t, err)
+
+	wsRef, err := ref.WorkingSetRefForHead(feature)
+	require.NoError(t, err)
+	ws, err := ddb.ResolveWorkingSet(ctx, wsRef)
+	require.NoError(t, err)
+	wsHash, err := ws.HashOf()
+	require.NoError(t, err)
+	sch := schema.MustSchemaFromCols(schema.NewColCollection(schema.NewColumn("pk", 0, types.IntKind, true)))
+	working, err := doltdb.CreateEmptyTable(ctx, ws.WorkingRoot(), doltdb.TableName{Name: "t"}, sch)
+	require.NoError(t, err)
+	require.NoError(t, ddb.UpdateWorkingSet(ctx, wsRef, ws.WithWorkingRoot(working), wsHash, doltdb.TodoWorkingSetMeta(), nil))
+
+	assert.Equal(t, ErrWorktreeDirty, dEnv.RemoveWorktree(ctx, wtDir, false))
+	exists, _ := fs.Exists(wtDir)
+	assert.True(t, exists)
+
+	require.NoError(t, dEnv.RemoveWorktree(ctx, wtDir, true))
+	exists, _ = fs.Exists(wtDir)
+	assert.False(t, exists)
+
+	// the changes are kept in the working set of the branch
+	roots, err := ddb.ResolveBranchRoots(ctx, feature)
+	require.NoError(t, err)
+	has, err := roots.Working.HasTable(ctx, doltdb.TableName{Name: "t"})
+	require.NoError(t, err)
+	assert.True(t, has)
+}
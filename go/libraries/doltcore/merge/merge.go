@@ -240,6 +240,16 @@ func MergeRoots(
 	if err != nil {
 		return nil, err
 	}
+	merger.policies, err = doltdb.GetMergePolicies(ctx, ourRoot)
+	if err != nil {
+		return nil, err
+	}
+	if len(merger.policies) > 0 && !mergeOpts.ReverifyAllConstraints {
+		merger.resolutions, err = newMergeResolutionsWriter(ctx, ourRoot)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	destSchemaNames, err := getDatabaseSchemaNames(ctx, ourRoot)
 	if err != nil {
@@ -250,6 +260,10 @@ func MergeRoots(
 	visitedTables := make(map[string]struct{})
 	var schConflicts []SchemaConflict
 	for _, tblName := range tblNames {
+		if len(merger.policies) > 0 && tblName.Name == doltdb.MergeResolutionsTableName {
+			// dolt_merge_resolutions is rewritten below with the cells resolved by this merge
+			continue
+		}
 		mergedTable, stats, err := merger.MergeTable(ctx, tblName, opts, mergeOpts)
 
 		if errors.Is(ErrTableDeletedAndModified, err) && doltdb.IsFullTextTable(tblName.Name) {
@@ -341,6 +355,13 @@ func MergeRoots(
 		return nil, err
 	}
 
+	if merger.resolutions != nil {
+		mergedRoot, err = writeMergeResolutions(ctx, mergedRoot, merger.resolutions)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	mergedFKColl, conflicts, err := ForeignKeysMerge(ctx, mergedRoot, ourRoot, theirRoot, ancRoot)
 	if err != nil {
 		return nil, err
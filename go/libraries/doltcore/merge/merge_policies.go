@@ -0,0 +1,458 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/shopspring/decimal"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/expranalysis"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// PolicyResolution records a cell whose conflicting values were resolved by a merge policy declared in the
+// dolt_merge_policies table.
+type PolicyResolution struct {
+	// RowKey is the primary key of the resolved row, formatted as a comma separated list of values.
+	RowKey   string
+	Column   string
+	Strategy doltdb.MergeStrategy
+	// Ours, Theirs and Merged are the formatted values of the cell, or nil for NULL values.
+	Ours   interface{}
+	Theirs interface{}
+	Merged interface{}
+}
+
+// cellResolution is a cell resolved by a merge policy while merging the current row, in the result schema.
+type cellResolution struct {
+	idx                  int
+	strategy             doltdb.MergeStrategy
+	ours, theirs, merged []byte
+}
+
+// policyFor returns the merge policy that applies to |col|, if any.
+func (m *valueMerger) policyFor(col schema.Column) (doltdb.MergePolicy, bool) {
+	if len(m.policies) == 0 {
+		return doltdb.MergePolicy{}, false
+	}
+	if p, ok := m.policies[strings.ToLower(col.Name)]; ok {
+		return p, true
+	}
+	p, ok := m.policies[doltdb.MergePolicyAllColumns]
+	return p, ok
+}
+
+// resolveWithPolicy resolves the conflicting values |leftCol| and |rightCol| of column |i| of the result schema
+// using the merge policy declared for that column. |baseCol| is nil if the row or column was inserted on both sides.
+// All values have already been converted to the result schema. If no policy applies, or the policy can't resolve
+// these values, a conflict is returned.
+func (m *valueMerger) resolveWithPolicy(ctx *sql.Context, i int, left, right val.Tuple, baseCol, leftCol, rightCol []byte) ([]byte, bool, error) {
+	col := m.resultSchema.GetNonPKCols().GetByIndex(i)
+	policy, ok := m.policyFor(col)
+	if !ok {
+		return nil, true, nil
+	}
+
+	resultType := m.resultVD.Types[i]
+	cmp := m.resultVD.Comparator()
+
+	var merged []byte
+	switch policy.Strategy {
+	case doltdb.MergeStrategyOurs:
+		merged = leftCol
+	case doltdb.MergeStrategyTheirs:
+		merged = rightCol
+	case doltdb.MergeStrategyMax:
+		merged = leftCol
+		if cmp.CompareValues(i, leftCol, rightCol, resultType) < 0 {
+			merged = rightCol
+		}
+	case doltdb.MergeStrategyMin:
+		merged = leftCol
+		if cmp.CompareValues(i, leftCol, rightCol, resultType) > 0 {
+			merged = rightCol
+		}
+	case doltdb.MergeStrategyLastWriterWins:
+		rightIsNewer, err := m.rightIsNewer(ctx, policy, left, right)
+		if err != nil {
+			return nil, true, err
+		}
+		merged = leftCol
+		if rightIsNewer {
+			merged = rightCol
+		}
+	case doltdb.MergeStrategySum:
+		var conflict bool
+		var err error
+		merged, conflict, err = m.sumOfDeltas(ctx, i, col, baseCol, leftCol, rightCol)
+		if err != nil || conflict {
+			return nil, true, err
+		}
+	case doltdb.MergeStrategyJsonUnion:
+		var conflict bool
+		var err error
+		merged, conflict, err = m.jsonArrayUnion(ctx, i, col, leftCol, rightCol)
+		if err != nil || conflict {
+			return nil, true, err
+		}
+	case doltdb.MergeStrategyExpression:
+		var err error
+		merged, err = m.evalPolicyExpression(ctx, i, col, policy, baseCol, leftCol, rightCol)
+		if err != nil {
+			return nil, true, err
+		}
+	default:
+		return nil, true, fmt.Errorf("unknown merge strategy '%s'", policy.Strategy)
+	}
+
+	m.resolutions = append(m.resolutions, cellResolution{
+		idx:      i,
+		strategy: policy.Strategy,
+		ours:     leftCol,
+		theirs:   rightCol,
+		merged:   merged,
+	})
+	return merged, false, nil
+}
+
+// rightIsNewer compares the timestamp column named by a last_writer_wins |policy| in the |left| and |right| rows,
+// and returns whether the right side of the merge wrote the row last.
+func (m *valueMerger) rightIsNewer(ctx context.Context, policy doltdb.MergePolicy, left, right val.Tuple) (bool, error) {
+	cols := m.resultSchema.GetNonPKCols()
+	tsCol, ok := cols.GetByNameCaseInsensitive(policy.Argument)
+	if !ok {
+		return false, fmt.Errorf("merge policy for table '%s' names unknown timestamp column '%s'", policy.TableName, policy.Argument)
+	}
+	tsIdx, ok := cols.StoredIndexByTag(tsCol.Tag)
+	if !ok {
+		return false, fmt.Errorf("merge policy for table '%s' can't use generated column '%s' as its timestamp column", policy.TableName, policy.Argument)
+	}
+
+	leftTs, leftTsIdx, leftExists := getColumn(&left, &m.leftMapping, tsIdx)
+	rightTs, rightTsIdx, rightExists := getColumn(&right, &m.rightMapping, tsIdx)
+	if !leftExists || !rightExists {
+		// the timestamp column was added on one side, only that side has a timestamp to compare
+		return rightExists, nil
+	}
+
+	var err error
+	leftTs, err = convert(ctx, m.leftVD, m.resultVD, m.resultSchema, leftTsIdx, tsIdx, left, leftTs, m.ns)
+	if err != nil {
+		return false, err
+	}
+	rightTs, err = convert(ctx, m.rightVD, m.resultVD, m.resultSchema, rightTsIdx, tsIdx, right, rightTs, m.ns)
+	if err != nil {
+		return false, err
+	}
+	return m.resultVD.Comparator().CompareValues(tsIdx, leftTs, rightTs, m.resultVD.Types[tsIdx]) < 0, nil
+}
+
+// sumOfDeltas applies the changes made to a numeric cell on both sides of the merge to its base value, returning
+// ours + theirs - base. NULL values are treated as zero. If the sum doesn't fit the column's type, a conflict is
+// returned.
+func (m *valueMerger) sumOfDeltas(ctx context.Context, i int, col schema.Column, baseCol, leftCol, rightCol []byte) ([]byte, bool, error) {
+	sqlType := col.TypeInfo.ToSqlType()
+	if !types.IsNumber(sqlType) {
+		return nil, true, fmt.Errorf("merge strategy '%s' can't be used for column '%s' of type %s", doltdb.MergeStrategySum, col.Name, sqlType.String())
+	}
+
+	var values [3]decimal.Decimal
+	for j, cell := range [][]byte{leftCol, rightCol, baseCol} {
+		v, err := m.cellValue(ctx, i, cell)
+		if err != nil {
+			return nil, true, err
+		}
+		if v == nil {
+			continue
+		}
+		d, _, err := types.InternalDecimalType.Convert(v)
+		if err != nil {
+			return nil, true, err
+		}
+		values[j] = d.(decimal.Decimal)
+	}
+
+	sum, inRange, err := sqlType.Convert(values[0].Add(values[1]).Sub(values[2]))
+	if err != nil || inRange != sql.InRange {
+		return nil, true, nil
+	}
+	merged, err := m.cellBytes(ctx, i, sum)
+	if err != nil {
+		return nil, true, err
+	}
+	return merged, false, nil
+}
+
+// jsonArrayUnion returns the union of the elements of the JSON arrays in |leftCol| and |rightCol|: the elements of
+// our array followed by the elements of their array that ours doesn't contain. If either value isn't a JSON array,
+// a conflict is returned.
+func (m *valueMerger) jsonArrayUnion(ctx context.Context, i int, col schema.Column, leftCol, rightCol []byte) ([]byte, bool, error) {
+	if _, ok := col.TypeInfo.ToSqlType().(types.JsonType); !ok {
+		return nil, true, fmt.Errorf("merge strategy '%s' can't be used for column '%s' of type %s", doltdb.MergeStrategyJsonUnion, col.Name, col.TypeInfo.ToSqlType().String())
+	}
+
+	var arrays [2][]interface{}
+	for j, cell := range [][]byte{leftCol, rightCol} {
+		v, err := m.cellValue(ctx, i, cell)
+		if err != nil {
+			return nil, true, err
+		}
+		doc, ok := v.(sql.JSONWrapper)
+		if !ok {
+			return nil, true, nil
+		}
+		obj, err := doc.ToInterface()
+		if err != nil {
+			return nil, true, err
+		}
+		arr, ok := obj.([]interface{})
+		if !ok {
+			return nil, true, nil
+		}
+		arrays[j] = arr
+	}
+
+	union := append([]interface{}{}, arrays[0]...)
+	for _, elem := range arrays[1] {
+		found := false
+		for _, existing := range arrays[0] {
+			cmp, err := types.CompareJSON(types.JSONDocument{Val: existing}, types.JSONDocument{Val: elem})
+			if err != nil {
+				return nil, true, err
+			}
+			if cmp == 0 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			union = append(union, elem)
+		}
+	}
+
+	merged, err := m.cellBytes(ctx, i, types.JSONDocument{Val: union})
+	if err != nil {
+		return nil, true, err
+	}
+	return merged, false, nil
+}
+
+// evalPolicyExpression evaluates the SQL expression of |policy| with the values of the conflicting cell and returns
+// the result, converted to the type of the column.
+func (m *valueMerger) evalPolicyExpression(ctx *sql.Context, i int, col schema.Column, policy doltdb.MergePolicy, baseCol, leftCol, rightCol []byte) ([]byte, error) {
+	expr, ok := m.policyExprs[i]
+	if !ok {
+		var err error
+		expr, err = expranalysis.ResolveMergePolicyExpression(ctx, col.TypeInfo, policy.Argument)
+		if err != nil {
+			return nil, err
+		}
+		if m.policyExprs == nil {
+			m.policyExprs = make(map[int]sql.Expression)
+		}
+		m.policyExprs[i] = expr
+	}
+
+	row := make(sql.Row, 4)
+	for j, cell := range [][]byte{leftCol, rightCol, baseCol} {
+		v, err := m.cellValue(ctx, i, cell)
+		if err != nil {
+			return nil, err
+		}
+		row[j] = v
+	}
+
+	result, err := expr.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	result, _, err = col.TypeInfo.ToSqlType().Convert(result)
+	if err != nil {
+		return nil, fmt.Errorf("merge policy expression for column '%s' returned an invalid value: %w", col.Name, err)
+	}
+	return m.cellBytes(ctx, i, result)
+}
+
+// cellValue returns the SQL value of |cell|, a value of column |i| of the result schema.
+func (m *valueMerger) cellValue(ctx context.Context, i int, cell []byte) (interface{}, error) {
+	if cell == nil {
+		return nil, nil
+	}
+	typ := m.resultVD.Types[i]
+	typ.Nullable = true
+	desc := val.NewTupleDescriptor(typ)
+	return tree.GetField(ctx, desc, 0, val.NewTuple(m.syncPool, cell), m.ns)
+}
+
+// cellBytes serializes |v| as a value of column |i| of the result schema.
+func (m *valueMerger) cellBytes(ctx context.Context, i int, v interface{}) ([]byte, error) {
+	typ := m.resultVD.Types[i]
+	// As in convert, NULL values in non-null columns are validated once the merged row is built.
+	typ.Nullable = true
+	return tree.Serialize(ctx, m.ns, typ, v)
+}
+
+// takeResolutions returns the cells resolved by merge policies while merging the last row and formats them for
+// recording, keyed by |key|.
+func (m *valueMerger) takeResolutions(ctx context.Context, keyDesc val.TupleDesc, key val.Tuple) ([]PolicyResolution, error) {
+	if len(m.resolutions) == 0 {
+		return nil, nil
+	}
+
+	keyValues := make([]string, keyDesc.Count())
+	for j := range keyValues {
+		v, err := tree.GetField(ctx, keyDesc, j, key, m.ns)
+		if err != nil {
+			return nil, err
+		}
+		s, err := formatPolicyValue(v)
+		if err != nil {
+			return nil, err
+		}
+		if s == nil {
+			keyValues[j] = "NULL"
+		} else {
+			keyValues[j] = s.(string)
+		}
+	}
+	rowKey := strings.Join(keyValues, ",")
+
+	resolutions := make([]PolicyResolution, len(m.resolutions))
+	for j, r := range m.resolutions {
+		res := PolicyResolution{
+			RowKey:   rowKey,
+			Column:   m.resultSchema.GetNonPKCols().GetByIndex(r.idx).Name,
+			Strategy: r.strategy,
+		}
+		for _, f := range []struct {
+			cell []byte
+			dest *interface{}
+		}{{r.ours, &res.Ours}, {r.theirs, &res.Theirs}, {r.merged, &res.Merged}} {
+			v, err := m.cellValue(ctx, r.idx, f.cell)
+			if err != nil {
+				return nil, err
+			}
+			if *f.dest, err = formatPolicyValue(v); err != nil {
+				return nil, err
+			}
+		}
+		resolutions[j] = res
+	}
+	m.resolutions = m.resolutions[:0]
+	return resolutions, nil
+}
+
+// formatPolicyValue formats |v| as a string, or returns nil if |v| is NULL.
+func formatPolicyValue(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	s, _, err := types.LongText.Convert(v)
+	return s, err
+}
+
+// mergeResolutionsWriter streams the cells resolved by merge policies into a new dolt_merge_resolutions table as
+// the merge resolves them.
+type mergeResolutionsWriter struct {
+	tbl   *doltdb.Table
+	mut   *prolly.MutableMap
+	kb    *val.TupleBuilder
+	vb    *val.TupleBuilder
+	ns    tree.NodeStore
+	count int
+}
+
+func newMergeResolutionsWriter(ctx context.Context, root doltdb.RootValue) (*mergeResolutionsWriter, error) {
+	tbl, err := doltdb.NewEmptyTable(ctx, root.VRW(), root.NodeStore(), doltdb.MergeResolutionsSchema)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows := durable.ProllyMapFromIndex(idx)
+	keyDesc, valDesc := rows.Descriptors()
+	return &mergeResolutionsWriter{
+		tbl: tbl,
+		mut: rows.Mutate(),
+		kb:  val.NewTupleBuilder(keyDesc),
+		vb:  val.NewTupleBuilder(valDesc),
+		ns:  rows.NodeStore(),
+	}, nil
+}
+
+// put records |resolutions| of a row of table |tblName|. A nil writer discards them.
+func (w *mergeResolutionsWriter) put(ctx context.Context, tblName string, resolutions []PolicyResolution) error {
+	if w == nil {
+		return nil
+	}
+	for _, r := range resolutions {
+		if err := w.kb.PutString(0, tblName); err != nil {
+			return err
+		}
+		if err := w.kb.PutString(1, r.RowKey); err != nil {
+			return err
+		}
+		if err := w.kb.PutString(2, r.Column); err != nil {
+			return err
+		}
+		if err := w.vb.PutString(0, string(r.Strategy)); err != nil {
+			return err
+		}
+		for j, v := range []interface{}{r.Ours, r.Theirs, r.Merged} {
+			if err := tree.PutField(ctx, w.ns, w.vb, j+1, v); err != nil {
+				return err
+			}
+		}
+		if err := w.mut.Put(ctx, w.kb.Build(w.ns.Pool()), w.vb.Build(w.ns.Pool())); err != nil {
+			return err
+		}
+		w.count++
+	}
+	return nil
+}
+
+// writeMergeResolutions replaces the contents of the dolt_merge_resolutions table of |root| with the cells
+// recorded by |w|. The table is created only once a merge resolves a cell.
+func writeMergeResolutions(ctx context.Context, root doltdb.RootValue, w *mergeResolutionsWriter) (doltdb.RootValue, error) {
+	tblName := doltdb.TableName{Name: doltdb.MergeResolutionsTableName}
+	if w.count == 0 {
+		exists, err := root.HasTable(ctx, tblName)
+		if err != nil || !exists {
+			return root, err
+		}
+	}
+
+	m, err := w.mut.Map(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tbl, err := w.tbl.UpdateRows(ctx, durable.IndexFromProllyMap(m))
+	if err != nil {
+		return nil, err
+	}
+	return root.PutTable(ctx, tblName, tbl)
+}
@@ -75,6 +75,7 @@ func mergeProllyTable(
 	}
 	leftRows := durable.ProllyMapFromIndex(lr)
 	valueMerger := newValueMerger(mergedSch, tm.leftSch, tm.rightSch, tm.ancSch, leftRows.Pool(), tm.ns)
+	valueMerger.policies = tm.policies
 
 	if !valueMerger.leftMapping.IsIdentityMapping() {
 		mergeInfo.LeftNeedsRewrite = true
@@ -259,6 +260,14 @@ func mergeProllyTableData(ctx *sql.Context, tm *TableMerger, finalSch schema.Sch
 			// In this case, both sides of the merge have made different changes to a row, but we were able to
 			// resolve them automatically.
 			s.Modifications++
+			resolutions, err := valueMerger.takeResolutions(ctx, finalSch.GetKeyDescriptor(), diff.Key)
+			if err != nil {
+				return nil, nil, err
+			}
+			if err = tm.resolutions.put(ctx, tm.name.Name, resolutions); err != nil {
+				return nil, nil, err
+			}
+			s.PolicyResolutions += len(resolutions)
 			err = pri.merge(ctx, diff, nil)
 			if err != nil {
 				return nil, nil, err
@@ -1622,6 +1631,12 @@ type valueMerger struct {
 	syncPool                               pool.BuffPool
 	keyless                                bool
 	ns                                     tree.NodeStore
+
+	// policies are the merge policies declared for this table, keyed by lower case column name
+	policies    map[string]doltdb.MergePolicy
+	policyExprs map[int]sql.Expression
+	// resolutions are the cells of the last merged row that were resolved by a merge policy
+	resolutions []cellResolution
 }
 
 func newValueMerger(merged, leftSch, rightSch, baseSch schema.Schema, syncPool pool.BuffPool, ns tree.NodeStore) *valueMerger {
@@ -1707,6 +1722,7 @@ func (m *valueMerger) tryMerge(ctx *sql.Context, left, right, base val.Tuple) (v
 	if m.keyless {
 		return nil, false, nil
 	}
+	m.resolutions = m.resolutions[:0]
 
 	for i := 0; i < len(m.baseToRightMapping); i++ {
 		isConflict, err := m.processBaseColumn(ctx, i, left, right, base)
@@ -1893,8 +1909,8 @@ func (m *valueMerger) processColumn(ctx *sql.Context, i int, left, right, base v
 			return leftCol, false, nil
 		}
 
-		// conflicting inserts
-		return nil, true, nil
+		// conflicting inserts, unless a merge policy resolves them
+		return m.resolveWithPolicy(ctx, i, left, right, nil, leftCol, rightCol)
 	}
 
 	// We can now assume that both left and right contain byte-level changes to an existing column.
@@ -1958,6 +1974,10 @@ func (m *valueMerger) processColumn(ctx *sql.Context, i int, left, right, base v
 			return leftCol, false, nil
 		}
 		// concurrent modification
+		// a merge policy declared for the column takes precedence over merging the JSON changes.
+		if _, ok := m.policyFor(resultColumn); ok {
+			return m.resolveWithPolicy(ctx, i, left, right, baseCol, leftCol, rightCol)
+		}
 		// if the result type is JSON, we can attempt to merge the JSON changes.
 		dontMergeJsonVar, err := ctx.Session.GetSessionVariable(ctx, "dolt_dont_merge_json")
 		if err != nil {
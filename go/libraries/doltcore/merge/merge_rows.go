@@ -76,6 +76,11 @@ type TableMerger struct {
 	// exception is for the dolt_verify_constraints() stored procedure, which allows callers to
 	// only record constraint violations for a specified subset of tables.
 	recordViolations bool
+
+	// policies are the merge policies declared for this table in dolt_merge_policies, keyed by lower case column name
+	policies map[string]doltdb.MergePolicy
+	// resolutions records the cells resolved by |policies|, or is nil if they are not recorded
+	resolutions *mergeResolutionsWriter
 }
 
 func (tm TableMerger) tableHashes() (left, right, anc hash.Hash, err error) {
@@ -107,6 +112,11 @@ type RootMerger struct {
 
 	vrw types.ValueReadWriter
 	ns  tree.NodeStore
+
+	// policies are the merge policies declared in the dolt_merge_policies table of the left root
+	policies doltdb.MergePolicies
+	// resolutions records the cells resolved by |policies| across all tables of the merge
+	resolutions *mergeResolutionsWriter
 }
 
 // NewMerger creates a new merger utility object.
@@ -211,6 +221,8 @@ func (rm *RootMerger) makeTableMerger(ctx context.Context, tblName doltdb.TableN
 		vrw:              rm.vrw,
 		ns:               rm.ns,
 		recordViolations: recordViolations,
+		policies:         rm.policies.ForTable(tblName.Name),
+		resolutions:      rm.resolutions,
 	}
 
 	var err error
@@ -31,6 +31,8 @@ type MergeStats struct {
 	DataConflicts        int
 	SchemaConflicts      int
 	ConstraintViolations int
+	// PolicyResolutions is the number of cells whose conflicting changes were resolved by a merge policy
+	PolicyResolutions int
 }
 
 func (ms *MergeStats) HasArtifacts() bool {
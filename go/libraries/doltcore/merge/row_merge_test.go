@@ -21,7 +21,9 @@ import (
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/store/types"
 	"github.com/dolthub/dolt/go/store/val"
@@ -222,6 +224,75 @@ func TestRowMerge(t *testing.T) {
 	}
 }
 
+func TestRowMergeWithPolicies(t *testing.T) {
+	if types.Format_Default != types.Format_DOLT {
+		t.Skip()
+	}
+
+	ctx := sql.NewEmptyContext()
+	sch := calcSchema(3)
+	base := buildTup(sch, build(1, 1, 1))
+	left := buildTup(sch, build(2, 5, 9))
+	right := buildTup(sch, build(3, 4, 7))
+
+	policy := func(col, strategy, arg string) doltdb.MergePolicy {
+		p, err := doltdb.NewMergePolicy("t", col, strategy, arg)
+		require.NoError(t, err)
+		return p
+	}
+
+	tests := []struct {
+		name        string
+		policies    map[string]doltdb.MergePolicy
+		expected    []*int
+		resolutions int
+	}{
+		{
+			name: "policy per column",
+			policies: map[string]doltdb.MergePolicy{
+				"1": policy("1", "max", ""),
+				"2": policy("2", "sum", ""),
+				"3": policy("3", "ours", ""),
+			},
+			expected:    build(3, 8, 9),
+			resolutions: 3,
+		},
+		{
+			name: "table-wide policy",
+			policies: map[string]doltdb.MergePolicy{
+				"*": policy("*", "min", ""),
+				"3": policy("3", "expression", "ours * 10 + theirs"),
+			},
+			expected:    build(2, 4, 97),
+			resolutions: 3,
+		},
+		{
+			name: "column without policy",
+			policies: map[string]doltdb.MergePolicy{
+				"1": policy("1", "theirs", ""),
+				"2": policy("2", "theirs", ""),
+			},
+			expected: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := newValueMerger(sch, sch, sch, sch, syncPool, nil)
+			v.policies = test.policies
+
+			merged, ok, err := v.tryMerge(ctx, left, right, base)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected != nil, ok)
+			vD := sch.GetValueDescriptor()
+			assert.Equal(t, vD.Format(buildTup(sch, test.expected)), vD.Format(merged))
+			if ok {
+				assert.Len(t, v.resolutions, test.resolutions)
+			}
+		})
+	}
+}
+
 func TestNomsRowMerge(t *testing.T) {
 	if types.Format_Default == types.Format_DOLT {
 		t.Skip()
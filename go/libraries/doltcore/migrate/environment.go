@@ -137,7 +137,7 @@ func initMigrationDB(ctx context.Context, existing *env.DoltEnv, src, dest files
 		return err
 	}
 
-	params := map[string]any{dbfactory.ChunkJournalParam: struct{}{}}
+	params := map[string]any{dbfactory.ChunkJournalParam: struct{}{}, dbfactory.LocalDatabaseParam: struct{}{}}
 	ddb, err := doltdb.LoadDoltDBWithParams(ctx, targetFormat, u.String(), dest, params)
 	vrw := ddb.ValueReadWriter()
 	ns := ddb.NodeStore()
@@ -57,6 +57,12 @@ const (
 
 	// ParquetFile is the format of a data location that is a .paquet file
 	ParquetFile DataFormat = ".parquet"
+
+	// AvroFile is the format of a data location that is an .avro object container file
+	AvroFile DataFormat = ".avro"
+
+	// ArrowFile is the format of a data location that is an .arrow IPC file
+	ArrowFile DataFormat = ".arrow"
 )
 
 // ReadableStr returns a human readable string for a DataFormat
@@ -76,6 +82,10 @@ func (df DataFormat) ReadableStr() string {
 		return "sql file"
 	case ParquetFile:
 		return "parquet file"
+	case AvroFile:
+		return "avro file"
+	case ArrowFile:
+		return "arrow file"
 	default:
 		return "invalid"
 	}
@@ -120,6 +130,10 @@ func NewDataLocation(path, fileFmtStr string) DataLocation {
 			dataFmt = SqlFile
 		case string(ParquetFile):
 			dataFmt = ParquetFile
+		case string(AvroFile):
+			dataFmt = AvroFile
+		case string(ArrowFile):
+			dataFmt = ArrowFile
 		}
 	}
 
@@ -85,6 +85,8 @@ func TestBasics(t *testing.T) {
 		{NewDataLocation("file.csv", ""), CsvFile.ReadableStr() + ":file.csv", true},
 		{NewDataLocation("file.psv", ""), PsvFile.ReadableStr() + ":file.psv", true},
 		{NewDataLocation("file.json", ""), JsonFile.ReadableStr() + ":file.json", true},
+		{NewDataLocation("file.avro", ""), AvroFile.ReadableStr() + ":file.avro", true},
+		{NewDataLocation("file.arrow", ""), ArrowFile.ReadableStr() + ":file.arrow", true},
 		//{NewDataLocation("file.nbf", ""), NbfFile, "file.nbf", true},
 	}
 
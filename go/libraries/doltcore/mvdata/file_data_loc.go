@@ -28,6 +28,8 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/arrow"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/avro"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/json"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/parquet"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/csv"
@@ -51,6 +53,10 @@ func DFFromString(dfStr string) DataFormat {
 		return SqlFile
 	case "parquet", ".parquet":
 		return ParquetFile
+	case "avro", ".avro":
+		return AvroFile
+	case "arrow", ".arrow":
+		return ArrowFile
 	default:
 		return InvalidDataFormat
 	}
@@ -179,6 +185,14 @@ func (dl FileDataLocation) NewReader(ctx context.Context, dEnv *env.DoltEnv, opt
 		}
 		rd, rErr := parquet.OpenParquetReader(root.VRW(), dl.Path, tableSch)
 		return rd, false, rErr
+
+	case AvroFile:
+		rd, err := avro.OpenAvroReader(dl.Path, fs)
+		return rd, false, err
+
+	case ArrowFile:
+		rd, err := arrow.OpenArrowReader(dl.Path, fs)
+		return rd, false, err
 	}
 
 	return nil, false, errors.New("unsupported format")
@@ -204,6 +218,10 @@ func (dl FileDataLocation) NewCreatingWriter(ctx context.Context, mvOpts DataMov
 		}
 	case ParquetFile:
 		return parquet.NewParquetRowWriterForFile(outSch, mvOpts.DestName())
+	case AvroFile:
+		return avro.NewAvroRowWriterForSchema(outSch, wr)
+	case ArrowFile:
+		return arrow.NewArrowRowWriterForSchema(outSch, wr)
 	}
 
 	panic("Invalid Data Format." + string(dl.Format))
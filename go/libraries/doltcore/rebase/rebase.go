@@ -17,6 +17,7 @@ package rebase
 import (
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/shopspring/decimal"
@@ -33,11 +34,19 @@ const (
 	RebaseActionFixup  = "fixup"
 	RebaseActionDrop   = "drop"
 	RebaseActionReword = "reword"
+	RebaseActionEdit   = "edit"
+	RebaseActionExec   = "exec"
+	RebaseActionBreak  = "break"
+	RebaseActionMerge  = "merge"
 )
 
 // ErrInvalidRebasePlanSquashFixupWithoutPick is returned when a rebase plan attempts to squash or
 // fixup a commit without first picking or rewording a commit.
-var ErrInvalidRebasePlanSquashFixupWithoutPick = fmt.Errorf("invalid rebase plan: squash and fixup actions must appear after a pick or reword action")
+var ErrInvalidRebasePlanSquashFixupWithoutPick = fmt.Errorf("invalid rebase plan: squash and fixup actions must appear after a pick, reword or edit action")
+
+// ErrInvalidRebasePlanExecWithoutStatement is returned when a rebase plan contains an exec action without a SQL
+// statement to execute in its commit message.
+var ErrInvalidRebasePlanExecWithoutStatement = fmt.Errorf("invalid rebase plan: exec actions must specify the SQL to execute as their commit message")
 
 // RebasePlanDatabase is a database that can save and load a rebase plan.
 type RebasePlanDatabase interface {
@@ -54,7 +63,9 @@ type RebasePlan struct {
 }
 
 // RebasePlanStep describes a single step in a rebase plan, such as dropping a
-// commit, squashing a commit into the previous commit, etc.
+// commit, squashing a commit into the previous commit, etc. Steps with the exec
+// and break actions don't apply a commit, so their CommitHash is empty. For exec
+// steps, CommitMsg holds the SQL to execute.
 type RebasePlanStep struct {
 	RebaseOrder decimal.Decimal
 	Action      string
@@ -72,10 +83,18 @@ func (rps *RebasePlanStep) RebaseOrderAsFloat() float32 {
 // CreateDefaultRebasePlan creates and returns the default rebase plan for the commits between
 // |startCommit| and |upstreamCommit|, equivalent to the log of startCommit..upstreamCommit. The
 // default plan includes each of those commits, in the same order they were originally applied, and
-// each step in the plan will have the default, pick, action. If the plan cannot be generated for
-// any reason, such as disconnected or invalid commits specified, then an error is returned.
-func CreateDefaultRebasePlan(ctx *sql.Context, startCommit, upstreamCommit *doltdb.Commit) (*RebasePlan, error) {
-	commits, err := findRebaseCommits(ctx, startCommit, upstreamCommit)
+// each step in the plan will have the default, pick, action. If |rebaseMerges| is true, then the plan
+// follows the first parents of |startCommit| and includes merge commits with the merge action, instead
+// of linearizing the history and dropping merge commits. If the plan cannot be generated for any reason,
+// such as disconnected or invalid commits specified, then an error is returned.
+func CreateDefaultRebasePlan(ctx *sql.Context, startCommit, upstreamCommit *doltdb.Commit, rebaseMerges bool) (*RebasePlan, error) {
+	var commits []*doltdb.Commit
+	var err error
+	if rebaseMerges {
+		commits, err = findFirstParentRebaseCommits(ctx, startCommit, upstreamCommit)
+	} else {
+		commits, err = findRebaseCommits(ctx, startCommit, upstreamCommit)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -96,9 +115,14 @@ func CreateDefaultRebasePlan(ctx *sql.Context, startCommit, upstreamCommit *dolt
 			return nil, err
 		}
 
+		action := RebaseActionPick
+		if commit.NumParents() > 1 {
+			action = RebaseActionMerge
+		}
+
 		plan.Steps = append(plan.Steps, RebasePlanStep{
 			RebaseOrder: decimal.NewFromFloat32(float32(len(commits) - idx)),
-			Action:      RebaseActionPick,
+			Action:      action,
 			CommitHash:  hash.String(),
 			CommitMsg:   meta.Description,
 		})
@@ -107,8 +131,14 @@ func CreateDefaultRebasePlan(ctx *sql.Context, startCommit, upstreamCommit *dolt
 	return &plan, nil
 }
 
+// AppliesCommit returns true if this step applies the changes from its commit, and false for
+// steps, such as exec and break, that don't reference a commit.
+func (rps *RebasePlanStep) AppliesCommit() bool {
+	return rps.Action != RebaseActionExec && rps.Action != RebaseActionBreak
+}
+
 // ValidateRebasePlan returns a validation error for invalid states in a rebase plan, such as
-// squash or fixup actions appearing in the plan before a pick or reword action.
+// squash or fixup actions appearing in the plan before a pick, reword or edit action.
 func ValidateRebasePlan(ctx *sql.Context, plan *RebasePlan) error {
 	seenPick := false
 	seenReword := false
@@ -120,7 +150,7 @@ func ValidateRebasePlan(ctx *sql.Context, plan *RebasePlan) error {
 		}
 
 		switch step.Action {
-		case RebaseActionPick:
+		case RebaseActionPick, RebaseActionEdit, RebaseActionMerge:
 			seenPick = true
 
 		case RebaseActionReword:
@@ -130,8 +160,16 @@ func ValidateRebasePlan(ctx *sql.Context, plan *RebasePlan) error {
 			if !seenPick && !seenReword {
 				return ErrInvalidRebasePlanSquashFixupWithoutPick
 			}
+
+		case RebaseActionExec:
+			if strings.TrimSpace(step.CommitMsg) == "" {
+				return ErrInvalidRebasePlanExecWithoutStatement
+			}
 		}
 
+		if !step.AppliesCommit() {
+			continue
+		}
 		if err := validateCommit(ctx, step.CommitHash); err != nil {
 			return err
 		}
@@ -171,6 +209,68 @@ func validateCommit(ctx *sql.Context, commit string) error {
 // commits that are reachable from the current branch HEAD, but are NOT reachable from
 // |upstreamBranchCommit|. Additionally, any merge commits in that range are NOT included.
 func findRebaseCommits(ctx *sql.Context, currentBranchCommit, upstreamBranchCommit *doltdb.Commit) (commits []*doltdb.Commit, err error) {
+	rangeCommits, err := findCommitsInRange(ctx, currentBranchCommit, upstreamBranchCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Don't include merge commits in the rebase plan
+	for _, commit := range rangeCommits {
+		if commit.NumParents() == 1 {
+			commits = append(commits, commit)
+		}
+	}
+	return commits, nil
+}
+
+// findFirstParentRebaseCommits returns the commits that should be included in the default rebase plan when
+// rebasing with merge commits preserved. This is the first-parent history of |currentBranchCommit| that is
+// not reachable from |upstreamBranchCommit|, including merge commits. Commits that were merged in through
+// the second parent of a merge commit are not included, since replaying the merge commit brings them in.
+func findFirstParentRebaseCommits(ctx *sql.Context, currentBranchCommit, upstreamBranchCommit *doltdb.Commit) (commits []*doltdb.Commit, err error) {
+	rangeCommits, err := findCommitsInRange(ctx, currentBranchCommit, upstreamBranchCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	inRange := make(map[hash.Hash]struct{}, len(rangeCommits))
+	for _, commit := range rangeCommits {
+		h, err := commit.HashOf()
+		if err != nil {
+			return nil, err
+		}
+		inRange[h] = struct{}{}
+	}
+
+	commit := currentBranchCommit
+	for {
+		h, err := commit.HashOf()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := inRange[h]; !ok {
+			return commits, nil
+		}
+		commits = append(commits, commit)
+
+		if commit.NumParents() == 0 {
+			return commits, nil
+		}
+		optCmt, err := commit.GetParent(ctx, 0)
+		if err != nil {
+			return nil, err
+		}
+		parent, ok := optCmt.ToCommit()
+		if !ok {
+			return nil, doltdb.ErrGhostCommitEncountered
+		}
+		commit = parent
+	}
+}
+
+// findCommitsInRange returns all commits reachable from |currentBranchCommit| that are not reachable from
+// |upstreamBranchCommit|, including merge commits, with the most recent commits first.
+func findCommitsInRange(ctx *sql.Context, currentBranchCommit, upstreamBranchCommit *doltdb.Commit) (commits []*doltdb.Commit, err error) {
 	doltSession := dsess.DSessFromSess(ctx.Session)
 
 	ddb, ok := doltSession.GetDoltDB(ctx, ctx.GetCurrentDatabase())
@@ -211,10 +311,6 @@ func findRebaseCommits(ctx *sql.Context, currentBranchCommit, upstreamBranchComm
 		if !ok {
 			return nil, doltdb.ErrGhostCommitEncountered // Not sure if we can get this far. commit walk is going to be a bear.
 		}
-
-		// Don't include merge commits in the rebase plan
-		if commit.NumParents() == 1 {
-			commits = append(commits, commit)
-		}
+		commits = append(commits, commit)
 	}
 }
@@ -0,0 +1,43 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ref
+
+// MapRef is a reference to a prolly map which Dolt uses to store data about a database outside of its commit graph,
+// like the history of workflow runs.
+type MapRef struct {
+	name string
+}
+
+var _ DoltRef = MapRef{}
+
+// NewMapRef creates a reference to the map dataset head named.
+func NewMapRef(name string) MapRef {
+	return MapRef{name}
+}
+
+// GetType will return MapRefType
+func (r MapRef) GetType() RefType {
+	return MapRefType
+}
+
+// GetPath returns the name of the map
+func (r MapRef) GetPath() string {
+	return r.name
+}
+
+// String returns the fully qualified reference name e.g. refs/maps/ci_runs
+func (r MapRef) String() string {
+	return String(r)
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ref
+
+import "strings"
+
+// DefaultNotesName is the name of the notes ref that holds the notes of every commit, refs/notes/commits.
+const DefaultNotesName = "commits"
+
+// NotesRef is a reference to a set of notes attached to commits, in the format refs/notes/<name>. All the notes of a
+// database are stored under a single notes ref, refs/notes/commits, which maps the hash of each annotated commit to
+// its note. Unlike tags, notes can be overwritten and removed without changing the commit they annotate.
+type NotesRef struct {
+	name string
+}
+
+var _ DoltRef = NotesRef{}
+
+// NewNotesRef creates a notes reference from a name or a notes ref e.g. refs/notes/commits
+func NewNotesRef(name string) NotesRef {
+	if IsRef(name) {
+		prefix := PrefixForType(NotesRefType)
+		if strings.HasPrefix(name, prefix) {
+			name = name[len(prefix):]
+		} else {
+			panic(name + " is a ref that is not of type " + prefix)
+		}
+	}
+
+	return NotesRef{name}
+}
+
+// GetType will return NotesRefType
+func (nr NotesRef) GetType() RefType {
+	return NotesRefType
+}
+
+// GetPath returns the name of the notes ref
+func (nr NotesRef) GetPath() string {
+	return nr.name
+}
+
+// String returns the fully qualified reference name e.g. refs/notes/commits
+func (nr NotesRef) String() string {
+	return String(nr)
+}
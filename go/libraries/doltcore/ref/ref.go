@@ -60,6 +60,12 @@ const (
 
 	// TupleRefType is a reference to a statistics table
 	TupleRefType RefType = "tuples"
+
+	// NotesRefType is a reference to the notes attached to commits
+	NotesRefType RefType = "notes"
+
+	// MapRefType is a reference to a prolly map stored outside of the commit graph
+	MapRefType RefType = "maps"
 )
 
 // HeadRefTypes are the ref types that point to a HEAD and contain a Commit struct. These are the types that are
@@ -82,6 +88,11 @@ var StatsRefTypes = map[RefType]struct{}{
 	StatsRefType: {},
 }
 
+// NotesRefTypes point to a map of notes attached to commits, which is not a HEAD.
+var NotesRefTypes = map[RefType]struct{}{
+	NotesRefType: {},
+}
+
 // PrefixForType returns what a reference string for a given type should start with
 func PrefixForType(refType RefType) string {
 	return refPrefix + string(refType) + "/"
@@ -212,5 +223,13 @@ func Parse(str string) (DoltRef, error) {
 		return NewTupleRef(str[len(prefix):]), nil
 	}
 
+	if prefix := PrefixForType(NotesRefType); strings.HasPrefix(str, prefix) {
+		return NewNotesRef(str[len(prefix):]), nil
+	}
+
+	if prefix := PrefixForType(MapRefType); strings.HasPrefix(str, prefix) {
+		return NewMapRef(str[len(prefix):]), nil
+	}
+
 	return nil, ErrUnknownRefType
 }
@@ -106,6 +106,8 @@ func ParseRefSpecForRemote(remote, refSpecStr string) (RefSpec, error) {
 		return NewBranchToBranchRefSpec(fromRef.(BranchRef), toRef.(BranchRef))
 	} else if fromRef.GetType() == TagRefType && toRef.GetType() == TagRefType {
 		return NewTagToTagRefSpec(fromRef.(TagRef), toRef.(TagRef))
+	} else if fromRef.GetType() == NotesRefType && toRef.GetType() == NotesRefType {
+		return NewNotesToNotesRefSpec(remote, fromRef.(NotesRef), toRef.(NotesRef))
 	}
 
 	return nil, ErrUnsupportedMapping
@@ -197,6 +199,70 @@ func (rs TagToTagRefSpec) DestRef(r DoltRef) DoltRef {
 	return nil
 }
 
+// NotesToNotesRefSpec maps the notes refs of a database to the notes refs of the same name in another database, e.g.
+// refs/notes/* or refs/notes/commits. Notes are keyed by commit hash, so the source and destination must match.
+type NotesToNotesRefSpec struct {
+	remote  string
+	srcRef  NotesRef
+	pattern pattern
+}
+
+// NewNotesToNotesRefSpec takes a source and destination NotesRef and returns a RefSpec that maps the notes matching
+// the source to themselves. The source may contain a single wildcard.
+func NewNotesToNotesRefSpec(remote string, srcRef, destRef NotesRef) (RefSpec, error) {
+	if srcRef.GetPath() != destRef.GetPath() {
+		return nil, ErrInvalidMapping
+	}
+
+	var p pattern
+	switch strings.Count(srcRef.GetPath(), "*") {
+	case 0:
+		p = strPattern(srcRef.GetPath())
+	case 1:
+		p = newWildcardPattern(srcRef.GetPath())
+	default:
+		return nil, ErrInvalidRefSpec
+	}
+
+	return NotesToNotesRefSpec{
+		remote:  remote,
+		srcRef:  srcRef,
+		pattern: p,
+	}, nil
+}
+
+// SrcRef will always determine the DoltRef specified as the source ref regardless to the cwbRef
+func (rs NotesToNotesRefSpec) SrcRef(_ DoltRef) DoltRef {
+	return rs.srcRef
+}
+
+// DestRef returns |r| if it is a notes ref matching the source of the refspec, or nil if it does not match.
+func (rs NotesToNotesRefSpec) DestRef(r DoltRef) DoltRef {
+	if r.GetType() == NotesRefType {
+		if _, matches := rs.pattern.matches(r.GetPath()); matches {
+			return r
+		}
+	}
+
+	return nil
+}
+
+// IsWildcard returns whether the refspec matches every notes ref rather than a single one.
+func (rs NotesToNotesRefSpec) IsWildcard() bool {
+	_, ok := rs.pattern.(wcPattern)
+	return ok
+}
+
+// GetRemote returns the name of the remote being operated on.
+func (rs NotesToNotesRefSpec) GetRemote() string {
+	return rs.remote
+}
+
+// GetRemRefToLocal returns the mapping of remote notes to local notes, which is the identity.
+func (rs NotesToNotesRefSpec) GetRemRefToLocal() branchMapper {
+	return identityBranchMapper(rs.srcRef.String())
+}
+
 // BranchToTrackingBranchRefSpec maps a branch to the branch that should be tracking it
 type BranchToTrackingBranchRefSpec struct {
 	localPattern  pattern
@@ -87,6 +87,23 @@ func TestRefSpec(t *testing.T) {
 				"refs/tags/v1": "refs/tags/v1",
 			},
 			skip: true,
+		}, {
+			remote:     "origin",
+			refSpecStr: "refs/notes/*:refs/notes/*",
+			isValid:    true,
+			inToExpOut: map[string]string{
+				"refs/notes/commits": "refs/notes/commits",
+				"refs/tags/v1":       "refs/nil/",
+			},
+		}, {
+			refSpecStr: "refs/notes/commits",
+			isValid:    true,
+			inToExpOut: map[string]string{
+				"refs/notes/commits": "refs/notes/commits",
+				"refs/notes/reviews": "refs/nil/",
+			},
+		}, {
+			refSpecStr: "refs/notes/*:refs/notes/commits",
 		},
 	}
 
@@ -508,6 +508,10 @@ func (rs *RemoteChunkStore) Commit(ctx context.Context, req *remotesapi.CommitRe
 			"last_hash": lastHash.String(),
 			"curr_hash": currHash.String(),
 		}).Error("error calling Commit")
+		// stores may reject a commit with a specific status, like a failed precondition for a push to a protected branch
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
 		return nil, status.Errorf(codes.Internal, "failed to commit: %v", err)
 	}
 
@@ -35,6 +35,7 @@ import (
 
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/nbs"
 	"github.com/dolthub/dolt/go/store/types"
 )
 
@@ -359,21 +360,23 @@ func offsetAndLenFromRange(rngStr string) (int64, int64, string, error) {
 // getFileReader opens a file at the given path and returns an io.ReadCloser,
 // the corresponding file's filesize, and a http status.
 func getFileReader(path string) (io.ReadCloser, int64, error) {
-	return openFile(path)
-}
-
-func openFile(path string) (*os.File, int64, error) {
-	info, err := os.Stat(path)
+	r, fSize, err := openFile(path)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get stats for file at path %s: %w", path, err)
+		return nil, 0, err
 	}
+	return r, fSize, nil
+}
 
-	f, err := os.Open(path)
+// openFile opens the storage file at the given path. Storage files which are
+// encrypted at rest are decrypted, and the returned size is the size of their
+// decrypted contents.
+func openFile(path string) (closerReaderAtWrapper, int64, error) {
+	r, fSize, closer, err := nbs.OpenStorageFile(context.Background(), path)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to open file at path %s: %w", path, err)
+		return closerReaderAtWrapper{}, 0, fmt.Errorf("failed to open file at path %s: %w", path, err)
 	}
 
-	return f, info.Size(), nil
+	return closerReaderAtWrapper{io.NewSectionReader(r, 0, fSize), closer}, fSize, nil
 }
 
 type closerReaderWrapper struct {
@@ -381,6 +384,11 @@ type closerReaderWrapper struct {
 	io.Closer
 }
 
+type closerReaderAtWrapper struct {
+	*io.SectionReader
+	io.Closer
+}
+
 func getFileReaderAt(path string, offset int64, length int64) (io.ReadCloser, int64, error) {
 	f, fSize, err := openFile(path)
 	if err != nil {
@@ -388,15 +396,11 @@ func getFileReaderAt(path string, offset int64, length int64) (io.ReadCloser, in
 	}
 
 	if fSize < int64(offset+length) {
+		f.Close()
 		return nil, 0, fmt.Errorf("failed to read file %s at offset %d, length %d: %w", path, offset, length, ErrReadOutOfBounds)
 	}
 
-	_, err = f.Seek(int64(offset), 0)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to seek file at path %s to offset %d: %w", path, offset, err)
-	}
-
-	r := closerReaderWrapper{io.LimitReader(f, length), f}
+	r := closerReaderWrapper{io.NewSectionReader(f, offset, length), f}
 	return r, fSize, nil
 }
 
@@ -71,7 +71,7 @@ func (si *ServerInterceptor) Stream() grpc.StreamServerInterceptor {
 			return err
 		}
 
-		if err := si.authenticate(ss.Context(), needSuperUser); err != nil {
+		if _, err := si.authenticate(ss.Context(), needSuperUser); err != nil {
 			return err
 		}
 
@@ -86,7 +86,8 @@ func (si *ServerInterceptor) Unary() grpc.UnaryServerInterceptor {
 			return nil, err
 		}
 
-		if err := si.authenticate(ctx, needSuperUser); err != nil {
+		ctx, err = si.authenticate(ctx, needSuperUser)
+		if err != nil {
 			return nil, err
 		}
 
@@ -114,20 +115,21 @@ func requireSuperUser(path string) (bool, error) {
 }
 
 // authenticate checks the incoming request for authentication credentials and validates them.  If the user is
-// legitimate, an authorization check is performed. If no error is returned, the user should be allowed to proceed.
-func (si *ServerInterceptor) authenticate(ctx context.Context, needsSuperUser bool) error {
+// legitimate, an authorization check is performed. If no error is returned, the user should be allowed to proceed, and
+// the context returned by ApiAuthenticate is returned for handling the request.
+func (si *ServerInterceptor) authenticate(ctx context.Context, needsSuperUser bool) (context.Context, error) {
 	ctx, err := si.AccessController.ApiAuthenticate(ctx)
 	if err != nil {
 		si.Lgr.Warnf("authentication failed: %s", err.Error())
-		return status.Error(codes.Unauthenticated, err.Error())
+		return nil, status.Error(codes.Unauthenticated, err.Error())
 	}
 
 	// Have a valid user in the context.  Check authorization.
 	if authorized, err := si.AccessController.ApiAuthorize(ctx, needsSuperUser); !authorized {
 		si.Lgr.Warnf("authorization failed: %s", err.Error())
-		return status.Error(codes.PermissionDenied, err.Error())
+		return nil, status.Error(codes.PermissionDenied, err.Error())
 	}
 
 	// Access Granted.
-	return nil
+	return ctx, nil
 }
@@ -235,3 +235,22 @@ const (
 	// WorkflowSavedQueryStepExpectedRowColumnResultsUpdatedAtTag is the name of the tag on the updated at column on the workflow saved query step expected row column results table
 	WorkflowSavedQueryStepExpectedRowColumnResultsUpdatedAtTag
 )
+
+// Tags for the dolt_merge_policies table
+const (
+	DoltMergePoliciesTableNameTag = iota + SystemTableReservedMin + uint64(10000)
+	DoltMergePoliciesColumnNameTag
+	DoltMergePoliciesStrategyTag
+	DoltMergePoliciesArgumentTag
+)
+
+// Tags for the dolt_merge_resolutions table
+const (
+	DoltMergeResolutionsTableNameTag = iota + SystemTableReservedMin + uint64(10100)
+	DoltMergeResolutionsRowKeyTag
+	DoltMergeResolutionsColumnNameTag
+	DoltMergeResolutionsStrategyTag
+	DoltMergeResolutionsOurValueTag
+	DoltMergeResolutionsTheirValueTag
+	DoltMergeResolutionsMergedValueTag
+)
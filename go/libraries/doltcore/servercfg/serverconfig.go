@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/url"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -60,6 +61,13 @@ const (
 	DefaultMySQLUnixSocketFilePath = "/tmp/mysql.sock"
 	DefaultMaxLoggedQueryLen       = 0
 	DefaultEncodeLoggedQuery       = false
+	DefaultCIMaxRunHistory         = 1000
+	DefaultWebhookMaxRetries       = 3
+	DefaultWebhookRetryIntervalMs  = 1000
+	DefaultWebhookTimeoutMs        = 10000
+	DefaultWebhookQueueSize        = 1024
+	DefaultCDCHost                 = "localhost"
+	DefaultCDCPollIntervalMs       = 1000
 )
 
 func ptr[T any](t T) *T {
@@ -110,6 +118,70 @@ type ClusterStandbyRemoteConfig interface {
 	RemoteURLTemplate() string
 }
 
+type CIConfig interface {
+	// Enabled is true if sql-server should run Dolt CI workflows when branches are updated.
+	Enabled() bool
+	// MaxRunHistory is the number of workflow runs kept for each database.
+	MaxRunHistory() int
+	// RequiredWorkflows are the workflows which must pass before a push to a protected branch is accepted.
+	RequiredWorkflows() []CIRequiredWorkflowsConfig
+}
+
+type CIRequiredWorkflowsConfig interface {
+	Branch() string
+	Workflows() []string
+}
+
+type SignedCommitsConfig interface {
+	// Branches are expressions matching the branches which only accept commits signed by a trusted key. They use the
+	// same syntax as the branch column of dolt_branch_control.
+	Branches() []string
+	// AllowedSigners is the path of the allowed signers file listing the trusted keys. If empty, the file named by the
+	// allowedsigners system variable is used.
+	AllowedSigners() string
+}
+
+type WebhookConfig interface {
+	// URL is the HTTP endpoint the payload of each branch update is POSTed to.
+	URL() string
+	// Secret is the key used to sign payloads with HMAC-SHA256. Payloads are not signed if it is empty.
+	Secret() string
+	// Databases are the databases whose branch updates are delivered. If empty, updates of every database are
+	// delivered.
+	Databases() []string
+	// Branches are expressions matching the branches whose updates are delivered. They use the same syntax as the
+	// branch column of dolt_branch_control. If empty, updates of every branch are delivered.
+	Branches() []string
+	// MaxRetries is the number of times a failed delivery is retried.
+	MaxRetries() int
+	// RetryIntervalMillis is the delay before the first retry of a failed delivery. It doubles with each retry.
+	RetryIntervalMillis() int
+	// TimeoutMillis is the timeout of each request to the endpoint.
+	TimeoutMillis() int
+	// QueueSize is the number of branch updates which can wait to be delivered before further updates are dropped.
+	QueueSize() int
+}
+
+type CDCConfig interface {
+	// Host is the host the change data capture HTTP endpoint listens on.
+	Host() string
+	// Port is the port the change data capture HTTP endpoint listens on.
+	Port() int
+	// PollIntervalMillis is how often a stream following a branch checks for new commits.
+	PollIntervalMillis() int
+}
+
+type ReflogConfig interface {
+	// Persistent enables the persistent reflog, which periodically archives the reflog of each database, and before
+	// garbage collection, backups and pushes, so that it survives garbage collection and is included in backups.
+	Persistent() bool
+	// MaxAgeDays is the number of days entries are kept in the persistent reflog. Zero means no limit.
+	MaxAgeDays() int
+	// MaxEntries is the number of most recent entries kept in the persistent reflog of each database. Zero means no
+	// limit.
+	MaxEntries() int
+}
+
 type JwksConfig struct {
 	Name        string            `yaml:"name"`
 	LocationUrl string            `yaml:"location_url"`
@@ -192,6 +264,16 @@ type ServerConfig interface {
 	RemotesapiReadOnly() *bool
 	// ClusterConfig is the configuration for clustering in this sql-server.
 	ClusterConfig() ClusterConfig
+	// CIConfig is the configuration for running Dolt CI workflows in this sql-server.
+	CIConfig() CIConfig
+	// SignedCommitsConfig is the configuration for requiring signed commits in this sql-server.
+	SignedCommitsConfig() SignedCommitsConfig
+	// WebhooksConfig is the configuration of the webhooks notified of branch updates in this sql-server.
+	WebhooksConfig() []WebhookConfig
+	// CDCConfig is the configuration of the change data capture HTTP endpoint of this sql-server.
+	CDCConfig() CDCConfig
+	// ReflogConfig is the configuration of the persistent reflog of the databases of this sql-server.
+	ReflogConfig() ReflogConfig
 	// EventSchedulerStatus is the configuration for enabling or disabling the event scheduler in this server.
 	EventSchedulerStatus() string
 	// ValueSet returns whether the value string provided was explicitly set in the config
@@ -261,6 +343,21 @@ func ValidateConfig(config ServerConfig) error {
 	if config.RequireSecureTransport() && config.TLSCert() == "" && config.TLSKey() == "" {
 		return fmt.Errorf("require_secure_transport can only be `true` when a tls_key and tls_cert are provided.")
 	}
+	if err := ValidateCIConfig(config.CIConfig()); err != nil {
+		return err
+	}
+	if err := ValidateSignedCommitsConfig(config.SignedCommitsConfig()); err != nil {
+		return err
+	}
+	if err := ValidateWebhooksConfig(config.WebhooksConfig()); err != nil {
+		return err
+	}
+	if err := ValidateCDCConfig(config.CDCConfig()); err != nil {
+		return err
+	}
+	if err := ValidateReflogConfig(config.ReflogConfig()); err != nil {
+		return err
+	}
 	return ValidateClusterConfig(config.ClusterConfig())
 }
 
@@ -342,6 +439,95 @@ func ValidateClusterConfig(config ClusterConfig) error {
 	return nil
 }
 
+func ValidateCIConfig(config CIConfig) error {
+	if config == nil {
+		return nil
+	}
+	if config.MaxRunHistory() < 0 {
+		return fmt.Errorf("ci: max_run_history: is %d but must be >= 0", config.MaxRunHistory())
+	}
+	for i, required := range config.RequiredWorkflows() {
+		if required.Branch() == "" {
+			return fmt.Errorf("ci: required_workflows[%d]: branch: Cannot be empty", i)
+		}
+		if len(required.Workflows()) == 0 {
+			return fmt.Errorf("ci: required_workflows[%d]: workflows: must supply at least one workflow", i)
+		}
+	}
+	return nil
+}
+
+func ValidateSignedCommitsConfig(config SignedCommitsConfig) error {
+	if config == nil {
+		return nil
+	}
+	for i, branch := range config.Branches() {
+		if branch == "" {
+			return fmt.Errorf("signed_commits: branches[%d]: Cannot be empty", i)
+		}
+	}
+	return nil
+}
+
+func ValidateWebhooksConfig(config []WebhookConfig) error {
+	for i, webhook := range config {
+		if webhook.URL() == "" {
+			return fmt.Errorf("webhooks[%d]: url: Cannot be empty", i)
+		}
+		u, err := url.Parse(webhook.URL())
+		if err != nil {
+			return fmt.Errorf("webhooks[%d]: url: %w", i, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("webhooks[%d]: url: scheme must be http or https: %s", i, webhook.URL())
+		}
+		for j, branch := range webhook.Branches() {
+			if branch == "" {
+				return fmt.Errorf("webhooks[%d]: branches[%d]: Cannot be empty", i, j)
+			}
+		}
+		if webhook.MaxRetries() < 0 {
+			return fmt.Errorf("webhooks[%d]: max_retries: is %d but must be >= 0", i, webhook.MaxRetries())
+		}
+		if webhook.RetryIntervalMillis() < 0 {
+			return fmt.Errorf("webhooks[%d]: retry_interval_millis: is %d but must be >= 0", i, webhook.RetryIntervalMillis())
+		}
+		if webhook.TimeoutMillis() <= 0 {
+			return fmt.Errorf("webhooks[%d]: timeout_millis: is %d but must be > 0", i, webhook.TimeoutMillis())
+		}
+		if webhook.QueueSize() <= 0 {
+			return fmt.Errorf("webhooks[%d]: queue_size: is %d but must be > 0", i, webhook.QueueSize())
+		}
+	}
+	return nil
+}
+
+func ValidateCDCConfig(config CDCConfig) error {
+	if config == nil {
+		return nil
+	}
+	if config.Port() < 1 || config.Port() > 65535 {
+		return fmt.Errorf("cdc: port: is %d but must be in the range 1-65535", config.Port())
+	}
+	if config.PollIntervalMillis() <= 0 {
+		return fmt.Errorf("cdc: poll_interval_millis: is %d but must be > 0", config.PollIntervalMillis())
+	}
+	return nil
+}
+
+func ValidateReflogConfig(config ReflogConfig) error {
+	if config == nil {
+		return nil
+	}
+	if config.MaxAgeDays() < 0 {
+		return fmt.Errorf("reflog: max_age_days: is %d but must be >= 0", config.MaxAgeDays())
+	}
+	if config.MaxEntries() < 0 {
+		return fmt.Errorf("reflog: max_entries: is %d but must be >= 0", config.MaxEntries())
+	}
+	return nil
+}
+
 // ConnectionString returns a Data Source Name (DSN) to be used by go clients for connecting to a running server.
 // If unix socket file path is defined in ServerConfig, then `unix` DSN will be returned.
 func ConnectionString(config ServerConfig, database string) string {
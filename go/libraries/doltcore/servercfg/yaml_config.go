@@ -124,20 +124,25 @@ type UserSessionVars struct {
 
 // YAMLConfig is a ServerConfig implementation which is read from a yaml file
 type YAMLConfig struct {
-	LogLevelStr       *string                `yaml:"log_level,omitempty"`
-	MaxQueryLenInLogs *int                   `yaml:"max_logged_query_len,omitempty"`
-	EncodeLoggedQuery *bool                  `yaml:"encode_logged_query,omitempty"`
-	BehaviorConfig    BehaviorYAMLConfig     `yaml:"behavior"`
-	UserConfig        UserYAMLConfig         `yaml:"user"`
-	ListenerConfig    ListenerYAMLConfig     `yaml:"listener"`
-	PerformanceConfig *PerformanceYAMLConfig `yaml:"performance,omitempty"`
-	DataDirStr        *string                `yaml:"data_dir,omitempty"`
-	CfgDirStr         *string                `yaml:"cfg_dir,omitempty"`
-	MetricsConfig     MetricsYAMLConfig      `yaml:"metrics"`
-	RemotesapiConfig  RemotesapiYAMLConfig   `yaml:"remotesapi"`
-	ClusterCfg        *ClusterYAMLConfig     `yaml:"cluster,omitempty"`
-	PrivilegeFile     *string                `yaml:"privilege_file,omitempty"`
-	BranchControlFile *string                `yaml:"branch_control_file,omitempty"`
+	LogLevelStr       *string                  `yaml:"log_level,omitempty"`
+	MaxQueryLenInLogs *int                     `yaml:"max_logged_query_len,omitempty"`
+	EncodeLoggedQuery *bool                    `yaml:"encode_logged_query,omitempty"`
+	BehaviorConfig    BehaviorYAMLConfig       `yaml:"behavior"`
+	UserConfig        UserYAMLConfig           `yaml:"user"`
+	ListenerConfig    ListenerYAMLConfig       `yaml:"listener"`
+	PerformanceConfig *PerformanceYAMLConfig   `yaml:"performance,omitempty"`
+	DataDirStr        *string                  `yaml:"data_dir,omitempty"`
+	CfgDirStr         *string                  `yaml:"cfg_dir,omitempty"`
+	MetricsConfig     MetricsYAMLConfig        `yaml:"metrics"`
+	RemotesapiConfig  RemotesapiYAMLConfig     `yaml:"remotesapi"`
+	ClusterCfg        *ClusterYAMLConfig       `yaml:"cluster,omitempty"`
+	CICfg             *CIYAMLConfig            `yaml:"ci,omitempty" minver:"TBD"`
+	SignedCommitsCfg  *SignedCommitsYAMLConfig `yaml:"signed_commits,omitempty" minver:"TBD"`
+	WebhooksCfg       []WebhookYAMLConfig      `yaml:"webhooks,omitempty" minver:"TBD"`
+	CDCCfg            *CDCYAMLConfig           `yaml:"cdc,omitempty" minver:"TBD"`
+	ReflogCfg         *ReflogYAMLConfig        `yaml:"reflog,omitempty" minver:"TBD"`
+	PrivilegeFile     *string                  `yaml:"privilege_file,omitempty"`
+	BranchControlFile *string                  `yaml:"branch_control_file,omitempty"`
 	// TODO: Rename to UserVars_
 	Vars            []UserSessionVars      `yaml:"user_session_vars"`
 	SystemVars_     map[string]interface{} `yaml:"system_variables,omitempty" minver:"1.11.1"`
@@ -215,6 +220,11 @@ func ServerConfigAsYAMLConfig(cfg ServerConfig) *YAMLConfig {
 			ReadOnly_: cfg.RemotesapiReadOnly(),
 		},
 		ClusterCfg:        clusterConfigAsYAMLConfig(cfg.ClusterConfig()),
+		CICfg:             ciConfigAsYAMLConfig(cfg.CIConfig()),
+		SignedCommitsCfg:  signedCommitsConfigAsYAMLConfig(cfg.SignedCommitsConfig()),
+		WebhooksCfg:       webhooksConfigAsYAMLConfig(cfg.WebhooksConfig()),
+		CDCCfg:            cdcConfigAsYAMLConfig(cfg.CDCConfig()),
+		ReflogCfg:         reflogConfigAsYAMLConfig(cfg.ReflogConfig()),
 		PrivilegeFile:     ptr(cfg.PrivilegeFilePath()),
 		BranchControlFile: ptr(cfg.BranchControlFilePath()),
 		SystemVars_:       systemVars,
@@ -223,6 +233,78 @@ func ServerConfigAsYAMLConfig(cfg ServerConfig) *YAMLConfig {
 	}
 }
 
+func ciConfigAsYAMLConfig(config CIConfig) *CIYAMLConfig {
+	if config == nil {
+		return nil
+	}
+
+	var required []CIRequiredWorkflowsYAMLConfig
+	for _, r := range config.RequiredWorkflows() {
+		required = append(required, CIRequiredWorkflowsYAMLConfig{
+			Branch_:    ptr(r.Branch()),
+			Workflows_: r.Workflows(),
+		})
+	}
+
+	return &CIYAMLConfig{
+		Enabled_:           ptr(config.Enabled()),
+		MaxRunHistory_:     ptr(config.MaxRunHistory()),
+		RequiredWorkflows_: required,
+	}
+}
+
+func webhooksConfigAsYAMLConfig(config []WebhookConfig) []WebhookYAMLConfig {
+	var ret []WebhookYAMLConfig
+	for _, w := range config {
+		ret = append(ret, WebhookYAMLConfig{
+			URL_:                 ptr(w.URL()),
+			Secret_:              nillableStrPtr(w.Secret()),
+			Databases_:           w.Databases(),
+			Branches_:            w.Branches(),
+			MaxRetries_:          ptr(w.MaxRetries()),
+			RetryIntervalMillis_: ptr(w.RetryIntervalMillis()),
+			TimeoutMillis_:       ptr(w.TimeoutMillis()),
+			QueueSize_:           ptr(w.QueueSize()),
+		})
+	}
+	return ret
+}
+
+func cdcConfigAsYAMLConfig(config CDCConfig) *CDCYAMLConfig {
+	if config == nil {
+		return nil
+	}
+
+	return &CDCYAMLConfig{
+		Host_:               ptr(config.Host()),
+		Port_:               ptr(config.Port()),
+		PollIntervalMillis_: ptr(config.PollIntervalMillis()),
+	}
+}
+
+func reflogConfigAsYAMLConfig(config ReflogConfig) *ReflogYAMLConfig {
+	if config == nil {
+		return nil
+	}
+
+	return &ReflogYAMLConfig{
+		Persistent_: ptr(config.Persistent()),
+		MaxAgeDays_: ptr(config.MaxAgeDays()),
+		MaxEntries_: ptr(config.MaxEntries()),
+	}
+}
+
+func signedCommitsConfigAsYAMLConfig(config SignedCommitsConfig) *SignedCommitsYAMLConfig {
+	if config == nil {
+		return nil
+	}
+
+	return &SignedCommitsYAMLConfig{
+		Branches_:       config.Branches(),
+		AllowedSigners_: ptr(config.AllowedSigners()),
+	}
+}
+
 func clusterConfigAsYAMLConfig(config ClusterConfig) *ClusterYAMLConfig {
 	if config == nil {
 		return nil
@@ -561,6 +643,45 @@ func (cfg YAMLConfig) ClusterConfig() ClusterConfig {
 	return cfg.ClusterCfg
 }
 
+func (cfg YAMLConfig) CIConfig() CIConfig {
+	if cfg.CICfg == nil {
+		return nil
+	}
+	return cfg.CICfg
+}
+
+func (cfg YAMLConfig) SignedCommitsConfig() SignedCommitsConfig {
+	if cfg.SignedCommitsCfg == nil {
+		return nil
+	}
+	return cfg.SignedCommitsCfg
+}
+
+func (cfg YAMLConfig) WebhooksConfig() []WebhookConfig {
+	if len(cfg.WebhooksCfg) == 0 {
+		return nil
+	}
+	ret := make([]WebhookConfig, len(cfg.WebhooksCfg))
+	for i := range cfg.WebhooksCfg {
+		ret[i] = cfg.WebhooksCfg[i]
+	}
+	return ret
+}
+
+func (cfg YAMLConfig) CDCConfig() CDCConfig {
+	if cfg.CDCCfg == nil {
+		return nil
+	}
+	return cfg.CDCCfg
+}
+
+func (cfg YAMLConfig) ReflogConfig() ReflogConfig {
+	if cfg.ReflogCfg == nil {
+		return nil
+	}
+	return cfg.ReflogCfg
+}
+
 func (cfg YAMLConfig) EventSchedulerStatus() string {
 	if cfg.BehaviorConfig.EventSchedulerStatus == nil {
 		return "ON"
@@ -575,6 +696,181 @@ func (cfg YAMLConfig) EventSchedulerStatus() string {
 	}
 }
 
+type CIYAMLConfig struct {
+	Enabled_           *bool                           `yaml:"enabled,omitempty" minver:"TBD"`
+	MaxRunHistory_     *int                            `yaml:"max_run_history,omitempty" minver:"TBD"`
+	RequiredWorkflows_ []CIRequiredWorkflowsYAMLConfig `yaml:"required_workflows,omitempty" minver:"TBD"`
+}
+
+func (c *CIYAMLConfig) Enabled() bool {
+	if c.Enabled_ == nil {
+		return false
+	}
+	return *c.Enabled_
+}
+
+func (c *CIYAMLConfig) MaxRunHistory() int {
+	if c.MaxRunHistory_ == nil {
+		return DefaultCIMaxRunHistory
+	}
+	return *c.MaxRunHistory_
+}
+
+func (c *CIYAMLConfig) RequiredWorkflows() []CIRequiredWorkflowsConfig {
+	ret := make([]CIRequiredWorkflowsConfig, len(c.RequiredWorkflows_))
+	for i := range c.RequiredWorkflows_ {
+		ret[i] = c.RequiredWorkflows_[i]
+	}
+	return ret
+}
+
+type CIRequiredWorkflowsYAMLConfig struct {
+	Branch_    *string  `yaml:"branch,omitempty" minver:"TBD"`
+	Workflows_ []string `yaml:"workflows,omitempty" minver:"TBD"`
+}
+
+func (c CIRequiredWorkflowsYAMLConfig) Branch() string {
+	if c.Branch_ == nil {
+		return ""
+	}
+	return *c.Branch_
+}
+
+func (c CIRequiredWorkflowsYAMLConfig) Workflows() []string {
+	return c.Workflows_
+}
+
+type SignedCommitsYAMLConfig struct {
+	Branches_       []string `yaml:"branches,omitempty" minver:"TBD"`
+	AllowedSigners_ *string  `yaml:"allowed_signers,omitempty" minver:"TBD"`
+}
+
+func (c *SignedCommitsYAMLConfig) Branches() []string {
+	return c.Branches_
+}
+
+func (c *SignedCommitsYAMLConfig) AllowedSigners() string {
+	if c.AllowedSigners_ == nil {
+		return ""
+	}
+	return *c.AllowedSigners_
+}
+
+type CDCYAMLConfig struct {
+	Host_               *string `yaml:"host,omitempty" minver:"TBD"`
+	Port_               *int    `yaml:"port,omitempty" minver:"TBD"`
+	PollIntervalMillis_ *int    `yaml:"poll_interval_millis,omitempty" minver:"TBD"`
+}
+
+func (c *CDCYAMLConfig) Host() string {
+	if c.Host_ == nil {
+		return DefaultCDCHost
+	}
+	return *c.Host_
+}
+
+func (c *CDCYAMLConfig) Port() int {
+	if c.Port_ == nil {
+		return 0
+	}
+	return *c.Port_
+}
+
+func (c *CDCYAMLConfig) PollIntervalMillis() int {
+	if c.PollIntervalMillis_ == nil {
+		return DefaultCDCPollIntervalMs
+	}
+	return *c.PollIntervalMillis_
+}
+
+type ReflogYAMLConfig struct {
+	Persistent_ *bool `yaml:"persistent,omitempty" minver:"TBD"`
+	MaxAgeDays_ *int  `yaml:"max_age_days,omitempty" minver:"TBD"`
+	MaxEntries_ *int  `yaml:"max_entries,omitempty" minver:"TBD"`
+}
+
+func (c *ReflogYAMLConfig) Persistent() bool {
+	if c.Persistent_ == nil {
+		return true
+	}
+	return *c.Persistent_
+}
+
+func (c *ReflogYAMLConfig) MaxAgeDays() int {
+	if c.MaxAgeDays_ == nil {
+		return 0
+	}
+	return *c.MaxAgeDays_
+}
+
+func (c *ReflogYAMLConfig) MaxEntries() int {
+	if c.MaxEntries_ == nil {
+		return 0
+	}
+	return *c.MaxEntries_
+}
+
+type WebhookYAMLConfig struct {
+	URL_                 *string  `yaml:"url,omitempty" minver:"TBD"`
+	Secret_              *string  `yaml:"secret,omitempty" minver:"TBD"`
+	Databases_           []string `yaml:"databases,omitempty" minver:"TBD"`
+	Branches_            []string `yaml:"branches,omitempty" minver:"TBD"`
+	MaxRetries_          *int     `yaml:"max_retries,omitempty" minver:"TBD"`
+	RetryIntervalMillis_ *int     `yaml:"retry_interval_millis,omitempty" minver:"TBD"`
+	TimeoutMillis_       *int     `yaml:"timeout_millis,omitempty" minver:"TBD"`
+	QueueSize_           *int     `yaml:"queue_size,omitempty" minver:"TBD"`
+}
+
+func (c WebhookYAMLConfig) URL() string {
+	if c.URL_ == nil {
+		return ""
+	}
+	return *c.URL_
+}
+
+func (c WebhookYAMLConfig) Secret() string {
+	if c.Secret_ == nil {
+		return ""
+	}
+	return *c.Secret_
+}
+
+func (c WebhookYAMLConfig) Databases() []string {
+	return c.Databases_
+}
+
+func (c WebhookYAMLConfig) Branches() []string {
+	return c.Branches_
+}
+
+func (c WebhookYAMLConfig) MaxRetries() int {
+	if c.MaxRetries_ == nil {
+		return DefaultWebhookMaxRetries
+	}
+	return *c.MaxRetries_
+}
+
+func (c WebhookYAMLConfig) RetryIntervalMillis() int {
+	if c.RetryIntervalMillis_ == nil {
+		return DefaultWebhookRetryIntervalMs
+	}
+	return *c.RetryIntervalMillis_
+}
+
+func (c WebhookYAMLConfig) TimeoutMillis() int {
+	if c.TimeoutMillis_ == nil {
+		return DefaultWebhookTimeoutMs
+	}
+	return *c.TimeoutMillis_
+}
+
+func (c WebhookYAMLConfig) QueueSize() int {
+	if c.QueueSize_ == nil {
+		return DefaultWebhookQueueSize
+	}
+	return *c.QueueSize_
+}
+
 type ClusterYAMLConfig struct {
 	StandbyRemotes_ []StandbyRemoteYAMLConfig   `yaml:"standby_remotes"`
 	BootstrapRole_  string                      `yaml:"bootstrap_role"`
@@ -287,6 +287,264 @@ cluster:
 	}
 }
 
+func TestUnmarshallCI(t *testing.T) {
+	testStr := `
+ci:
+  enabled: true
+  max_run_history: 50
+  required_workflows:
+  - branch: main
+    workflows:
+    - lint
+    - tests
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.NotNil(t, config.CIConfig())
+	require.True(t, config.CIConfig().Enabled())
+	require.Equal(t, 50, config.CIConfig().MaxRunHistory())
+	require.Len(t, config.CIConfig().RequiredWorkflows(), 1)
+	require.Equal(t, "main", config.CIConfig().RequiredWorkflows()[0].Branch())
+	require.Equal(t, []string{"lint", "tests"}, config.CIConfig().RequiredWorkflows()[0].Workflows())
+
+	config, err = NewYamlConfig([]byte(`ci: {}`))
+	require.NoError(t, err)
+	require.False(t, config.CIConfig().Enabled())
+	require.Equal(t, DefaultCIMaxRunHistory, config.CIConfig().MaxRunHistory())
+}
+
+func TestValidateCIConfig(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Config string
+		Error  bool
+	}{
+		{
+			Name:   "no ci: config",
+			Config: "",
+			Error:  false,
+		},
+		{
+			Name: "all fields valid",
+			Config: `
+ci:
+  enabled: true
+  max_run_history: 10
+  required_workflows:
+  - branch: main
+    workflows:
+    - tests
+`,
+			Error: false,
+		},
+		{
+			Name: "negative max_run_history",
+			Config: `
+ci:
+  max_run_history: -1
+`,
+			Error: true,
+		},
+		{
+			Name: "required_workflows without branch",
+			Config: `
+ci:
+  required_workflows:
+  - workflows:
+    - tests
+`,
+			Error: true,
+		},
+		{
+			Name: "required_workflows without workflows",
+			Config: `
+ci:
+  required_workflows:
+  - branch: main
+`,
+			Error: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			cfg, err := NewYamlConfig([]byte(c.Config))
+			require.NoError(t, err)
+			if c.Error {
+				require.Error(t, ValidateCIConfig(cfg.CIConfig()))
+			} else {
+				require.NoError(t, ValidateCIConfig(cfg.CIConfig()))
+			}
+		})
+	}
+}
+
+func TestUnmarshallWebhooks(t *testing.T) {
+	testStr := `
+webhooks:
+- url: https://example.com/hooks/dolt
+  secret: shh
+  databases:
+  - mydb
+  branches:
+  - main
+  - release/%
+  max_retries: 5
+  retry_interval_millis: 200
+  timeout_millis: 3000
+  queue_size: 16
+- url: http://localhost:8080
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	webhooks := config.WebhooksConfig()
+	require.Len(t, webhooks, 2)
+	require.Equal(t, "https://example.com/hooks/dolt", webhooks[0].URL())
+	require.Equal(t, "shh", webhooks[0].Secret())
+	require.Equal(t, []string{"mydb"}, webhooks[0].Databases())
+	require.Equal(t, []string{"main", "release/%"}, webhooks[0].Branches())
+	require.Equal(t, 5, webhooks[0].MaxRetries())
+	require.Equal(t, 200, webhooks[0].RetryIntervalMillis())
+	require.Equal(t, 3000, webhooks[0].TimeoutMillis())
+	require.Equal(t, 16, webhooks[0].QueueSize())
+
+	require.Equal(t, "http://localhost:8080", webhooks[1].URL())
+	require.Equal(t, "", webhooks[1].Secret())
+	require.Empty(t, webhooks[1].Databases())
+	require.Empty(t, webhooks[1].Branches())
+	require.Equal(t, DefaultWebhookMaxRetries, webhooks[1].MaxRetries())
+	require.Equal(t, DefaultWebhookRetryIntervalMs, webhooks[1].RetryIntervalMillis())
+	require.Equal(t, DefaultWebhookTimeoutMs, webhooks[1].TimeoutMillis())
+	require.Equal(t, DefaultWebhookQueueSize, webhooks[1].QueueSize())
+
+	config, err = NewYamlConfig([]byte(``))
+	require.NoError(t, err)
+	require.Nil(t, config.WebhooksConfig())
+}
+
+func TestUnmarshallCDC(t *testing.T) {
+	testStr := `
+cdc:
+  host: 0.0.0.0
+  port: 8081
+  poll_interval_millis: 250
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.NotNil(t, config.CDCConfig())
+	require.Equal(t, "0.0.0.0", config.CDCConfig().Host())
+	require.Equal(t, 8081, config.CDCConfig().Port())
+	require.Equal(t, 250, config.CDCConfig().PollIntervalMillis())
+	require.NoError(t, ValidateCDCConfig(config.CDCConfig()))
+
+	config, err = NewYamlConfig([]byte(`cdc: {port: 8081}`))
+	require.NoError(t, err)
+	require.Equal(t, DefaultCDCHost, config.CDCConfig().Host())
+	require.Equal(t, DefaultCDCPollIntervalMs, config.CDCConfig().PollIntervalMillis())
+
+	config, err = NewYamlConfig([]byte(`cdc: {}`))
+	require.NoError(t, err)
+	require.Error(t, ValidateCDCConfig(config.CDCConfig()))
+
+	config, err = NewYamlConfig([]byte(`cdc: {port: 8081, poll_interval_millis: 0}`))
+	require.NoError(t, err)
+	require.Error(t, ValidateCDCConfig(config.CDCConfig()))
+
+	config, err = NewYamlConfig([]byte(``))
+	require.NoError(t, err)
+	require.Nil(t, config.CDCConfig())
+}
+
+func TestValidateWebhooksConfig(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Config string
+		Error  bool
+	}{
+		{
+			Name:   "no webhooks: config",
+			Config: "",
+			Error:  false,
+		},
+		{
+			Name: "all fields valid",
+			Config: `
+webhooks:
+- url: https://example.com/hooks/dolt
+  secret: shh
+  branches:
+  - main
+  max_retries: 0
+  retry_interval_millis: 0
+`,
+			Error: false,
+		},
+		{
+			Name: "no url",
+			Config: `
+webhooks:
+- secret: shh
+`,
+			Error: true,
+		},
+		{
+			Name: "url without http scheme",
+			Config: `
+webhooks:
+- url: ftp://example.com/hooks
+`,
+			Error: true,
+		},
+		{
+			Name: "empty branch",
+			Config: `
+webhooks:
+- url: https://example.com/hooks/dolt
+  branches:
+  - ""
+`,
+			Error: true,
+		},
+		{
+			Name: "negative max_retries",
+			Config: `
+webhooks:
+- url: https://example.com/hooks/dolt
+  max_retries: -1
+`,
+			Error: true,
+		},
+		{
+			Name: "zero timeout_millis",
+			Config: `
+webhooks:
+- url: https://example.com/hooks/dolt
+  timeout_millis: 0
+`,
+			Error: true,
+		},
+		{
+			Name: "zero queue_size",
+			Config: `
+webhooks:
+- url: https://example.com/hooks/dolt
+  queue_size: 0
+`,
+			Error: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			cfg, err := NewYamlConfig([]byte(c.Config))
+			require.NoError(t, err)
+			if c.Error {
+				require.Error(t, ValidateWebhooksConfig(cfg.WebhooksConfig()))
+			} else {
+				require.NoError(t, ValidateWebhooksConfig(cfg.WebhooksConfig()))
+			}
+		})
+	}
+}
+
 // Tests that a common YAML error (incorrect indentation) throws an error
 func TestUnmarshallError(t *testing.T) {
 	testStr := `
@@ -427,3 +685,57 @@ metrics:
 	assert.Equal(t, "localhost", cfg.MetricsHost())
 	assert.Equal(t, -1, cfg.MetricsPort())
 }
+
+func TestUnmarshallSignedCommits(t *testing.T) {
+	testStr := `
+signed_commits:
+  branches:
+  - main
+  - release/%
+  allowed_signers: /etc/dolt/allowed_signers
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.NotNil(t, config.SignedCommitsConfig())
+	require.Equal(t, []string{"main", "release/%"}, config.SignedCommitsConfig().Branches())
+	require.Equal(t, "/etc/dolt/allowed_signers", config.SignedCommitsConfig().AllowedSigners())
+	require.NoError(t, ValidateSignedCommitsConfig(config.SignedCommitsConfig()))
+
+	config, err = NewYamlConfig([]byte(`signed_commits: {}`))
+	require.NoError(t, err)
+	require.Empty(t, config.SignedCommitsConfig().Branches())
+	require.Equal(t, "", config.SignedCommitsConfig().AllowedSigners())
+
+	config, err = NewYamlConfig([]byte("signed_commits:\n  branches:\n  - \"\"\n"))
+	require.NoError(t, err)
+	require.Error(t, ValidateSignedCommitsConfig(config.SignedCommitsConfig()))
+}
+
+func TestUnmarshallReflog(t *testing.T) {
+	testStr := `
+reflog:
+  max_age_days: 30
+  max_entries: 100000
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.NotNil(t, config.ReflogConfig())
+	require.True(t, config.ReflogConfig().Persistent())
+	require.Equal(t, 30, config.ReflogConfig().MaxAgeDays())
+	require.Equal(t, 100000, config.ReflogConfig().MaxEntries())
+	require.NoError(t, ValidateReflogConfig(config.ReflogConfig()))
+
+	config, err = NewYamlConfig([]byte(`reflog: {persistent: false}`))
+	require.NoError(t, err)
+	require.False(t, config.ReflogConfig().Persistent())
+	require.Equal(t, 0, config.ReflogConfig().MaxAgeDays())
+	require.Equal(t, 0, config.ReflogConfig().MaxEntries())
+
+	config, err = NewYamlConfig([]byte(`reflog: {max_entries: -1}`))
+	require.NoError(t, err)
+	require.Error(t, ValidateReflogConfig(config.ReflogConfig()))
+
+	config, err = NewYamlConfig([]byte(``))
+	require.NoError(t, err)
+	require.Nil(t, config.ReflogConfig())
+}
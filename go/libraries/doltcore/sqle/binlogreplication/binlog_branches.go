@@ -0,0 +1,125 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binlogreplication
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// BinlogBranches specifies the branches used for generating binlog events for specific databases, keyed by
+// lowercase database name. Databases without an entry use |BinlogBranch|. When a single branch is replicated for a
+// database, its events are applied to the schema with the same name as the database on replicas. When several
+// branches are replicated for a database, the events of each branch are applied to a separate schema named
+// <database>_<branch>, so that replicas can hold all the branches side by side.
+var BinlogBranches = map[string][]string{}
+
+// ParseBinlogBranches parses |value|, the value of the @@log_bin_branches system variable, into a map of lowercase
+// database name to the branches to replicate for that database. |value| is a comma-separated list of
+// <database>:<branch> entries, e.g. "db1:prod,db2:main,db2:staging". An empty |value| returns an empty map. An error
+// is returned if two of the configured branches would be replicated to the same schema, e.g. with "a:b_c,a:d" and
+// "a_b:c,a_b:d", which both replicate a branch to the schema a_b_c.
+func ParseBinlogBranches(value string) (map[string][]string, error) {
+	branches := make(map[string][]string)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		databaseName, branchName, ok := strings.Cut(entry, ":")
+		databaseName, branchName = strings.TrimSpace(databaseName), strings.TrimSpace(branchName)
+		if !ok || databaseName == "" || branchName == "" {
+			return nil, fmt.Errorf("invalid entry '%s' in @@log_bin_branches: "+
+				"entries must be of the form <database>:<branch>", entry)
+		}
+		if strings.Contains(branchName, "/") {
+			return nil, fmt.Errorf("invalid entry '%s' in @@log_bin_branches: "+
+				"branch names containing '/' are not supported for binlog replication", entry)
+		}
+
+		databaseName = strings.ToLower(databaseName)
+		if !slices.Contains(branches[databaseName], branchName) {
+			branches[databaseName] = append(branches[databaseName], branchName)
+		}
+	}
+
+	if err := checkBinlogSchemaCollisions(branches); err != nil {
+		return nil, err
+	}
+	return branches, nil
+}
+
+// checkBinlogSchemaCollisions returns an error if two of the branches in |branches| are replicated to the same schema.
+// Schema names are compared case-insensitively, as they are on replicas.
+func checkBinlogSchemaCollisions(branches map[string][]string) error {
+	databaseNames := make([]string, 0, len(branches))
+	for databaseName := range branches {
+		databaseNames = append(databaseNames, databaseName)
+	}
+	slices.Sort(databaseNames)
+
+	owners := make(map[string]string)
+	for _, databaseName := range databaseNames {
+		for _, branchName := range branches[databaseName] {
+			schemaName := databaseName
+			if len(branches[databaseName]) > 1 {
+				schemaName = databaseName + "_" + branchName
+			}
+
+			owner := databaseName + ":" + branchName
+			if other, ok := owners[strings.ToLower(schemaName)]; ok {
+				return fmt.Errorf("invalid value for @@log_bin_branches: entries '%s' and '%s' "+
+					"are both replicated to the schema '%s'", other, owner, strings.ToLower(schemaName))
+			}
+			owners[strings.ToLower(schemaName)] = owner
+		}
+	}
+	return nil
+}
+
+// binlogBranchesForDatabase returns the branches used for generating binlog events for the database named
+// |databaseName|.
+func binlogBranchesForDatabase(databaseName string) []string {
+	if branches, ok := BinlogBranches[strings.ToLower(databaseName)]; ok && len(branches) > 0 {
+		return branches
+	}
+	return []string{BinlogBranch}
+}
+
+// binlogSchemaForBranch returns the name of the schema that binlog events for the branch |branchName| of the
+// database |databaseName| are applied to on replicas, and false if the branch is not replicated.
+func binlogSchemaForBranch(databaseName, branchName string) (string, bool) {
+	branches := binlogBranchesForDatabase(databaseName)
+	if !slices.Contains(branches, branchName) {
+		return "", false
+	}
+	if len(branches) == 1 {
+		return databaseName, true
+	}
+	return databaseName + "_" + branchName, true
+}
+
+// binlogSchemasForDatabase returns the names of the schemas that binlog events for the database |databaseName| are
+// applied to on replicas, one for each replicated branch.
+func binlogSchemasForDatabase(databaseName string) []string {
+	branches := binlogBranchesForDatabase(databaseName)
+	schemas := make([]string, len(branches))
+	for i, branchName := range branches {
+		schemas[i], _ = binlogSchemaForBranch(databaseName, branchName)
+	}
+	return schemas
+}
@@ -0,0 +1,144 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binlogreplication
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseBinlogBranches tests parsing the value of @@log_bin_branches.
+func TestParseBinlogBranches(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected map[string][]string
+		err      string
+	}{
+		{
+			name:     "empty",
+			value:    "",
+			expected: map[string][]string{},
+		},
+		{
+			name:     "single branch",
+			value:    "db01:prod",
+			expected: map[string][]string{"db01": {"prod"}},
+		},
+		{
+			name:  "several databases and branches",
+			value: " DB01:prod , db02:main,db02:staging,db02:main,",
+			expected: map[string][]string{
+				"db01": {"prod"},
+				"db02": {"main", "staging"},
+			},
+		},
+		{
+			name:  "missing branch",
+			value: "db01",
+			err:   "entries must be of the form <database>:<branch>",
+		},
+		{
+			name:  "empty database",
+			value: ":prod",
+			err:   "entries must be of the form <database>:<branch>",
+		},
+		{
+			name:  "branches replicated to the same schema",
+			value: "a:b_c,a:d,a_b:c,a_b:d",
+			err:   "entries 'a:b_c' and 'a_b:c' are both replicated to the schema 'a_b_c'",
+		},
+		{
+			name:  "branch replicated to the schema of a database",
+			value: "db01_prod:main,db01:prod,db01:main",
+			err:   "entries 'db01:prod' and 'db01_prod:main' are both replicated to the schema 'db01_prod'",
+		},
+		{
+			name:  "branch names differing in case",
+			value: "db01:main,db01:Main",
+			err:   "entries 'db01:main' and 'db01:Main' are both replicated to the schema 'db01_main'",
+		},
+		{
+			name:  "branch with slash",
+			value: "db01:feature/one",
+			err:   "branch names containing '/' are not supported",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			branches, err := ParseBinlogBranches(test.value)
+			if test.err != "" {
+				require.ErrorContains(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, branches)
+		})
+	}
+}
+
+// TestBinlogSchemaForBranch tests that branches are replicated to the schema named after their database when a
+// single branch is replicated, and to a schema for each branch when several branches are replicated.
+func TestBinlogSchemaForBranch(t *testing.T) {
+	defer func() { BinlogBranches = map[string][]string{} }()
+	BinlogBranches = map[string][]string{
+		"db01": {"prod"},
+		"db02": {"main", "staging"},
+	}
+
+	schema, ok := binlogSchemaForBranch("db01", "prod")
+	require.True(t, ok)
+	require.Equal(t, "db01", schema)
+	_, ok = binlogSchemaForBranch("db01", "main")
+	require.False(t, ok)
+
+	schema, ok = binlogSchemaForBranch("DB02", "staging")
+	require.True(t, ok)
+	require.Equal(t, "DB02_staging", schema)
+	require.Equal(t, []string{"db02_main", "db02_staging"}, binlogSchemasForDatabase("db02"))
+
+	// Databases without configured branches use @@log_bin_branch
+	schema, ok = binlogSchemaForBranch("db03", BinlogBranch)
+	require.True(t, ok)
+	require.Equal(t, "db03", schema)
+	require.Equal(t, []string{"db03"}, binlogSchemasForDatabase("db03"))
+}
+
+// TestLockSchema tests that the replication of a schema is serialized, independently of other schemas and of the case
+// of the schema name.
+func TestLockSchema(t *testing.T) {
+	b := &binlogProducer{mu: &sync.Mutex{}, schemaLocks: make(map[string]*sync.Mutex)}
+
+	unlock := b.lockSchema("db01_main")
+	b.lockSchema("db01_staging")()
+
+	locked := make(chan struct{})
+	go func() {
+		defer close(locked)
+		b.lockSchema("DB01_Main")()
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("schema was locked twice")
+	case <-time.After(50 * time.Millisecond):
+	}
+	unlock()
+	<-locked
+}
@@ -18,6 +18,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/mysql_db"
@@ -32,6 +34,11 @@ const replicationRunningStateDirectory = ".doltcfg"
 // replicaRunningFilename holds the name of the file that indicates replication was running on a replica server.
 const replicaRunningFilename = "replica-running"
 
+// replicaSourceInfoFilename holds the name of the file that stores the replication source configuration for a named
+// replication channel. The configuration for the default channel is stored in the "mysql" database instead, which
+// only supports a single channel.
+const replicaSourceInfoFilename = "replica-source-info"
+
 // replicaRunningState indicates if a replica was actively running replication.
 type replicaRunningState int
 
@@ -40,37 +47,34 @@ const (
 	notRunning
 )
 
-// persistReplicationConfiguration saves the specified |replicaSourceInfo| to the "mysql"
-// database |mysqlDb|. If any problems are encountered while saving to disk, an error is returned.
-func persistReplicationConfiguration(ctx *sql.Context, replicaSourceInfo *mysql_db.ReplicaSourceInfo, mysqlDb *mysql_db.MySQLDb) error {
+// persistReplicationConfiguration saves the specified |replicaSourceInfo| for the replication channel named |channel|.
+// The configuration for the default channel is saved to the "mysql" database |mysqlDb|, and the configuration for a
+// named channel is saved to a file in the .doltcfg directory. If any problems are encountered while saving to disk,
+// an error is returned.
+func persistReplicationConfiguration(ctx *sql.Context, channel string, replicaSourceInfo *mysql_db.ReplicaSourceInfo, mysqlDb *mysql_db.MySQLDb) error {
+	if channel != defaultChannel {
+		jsonString, err := replicaSourceInfo.ToJson(ctx)
+		if err != nil {
+			return err
+		}
+
+		filesys := dsess.DSessFromSess(ctx.Session).Provider().FileSystem()
+		if err = createDoltCfgDir(filesys); err != nil {
+			return err
+		}
+		return filesys.WriteFile(replicaSourceInfoFilepath(channel), []byte(jsonString), 0600)
+	}
+
 	ed := mysqlDb.Editor()
 	defer ed.Close()
 	ed.PutReplicaSourceInfo(replicaSourceInfo)
 	return mysqlDb.Persist(ctx, ed)
 }
 
-// loadReplicationRunningState loads the replication running state from disk by looking for a "replica-running" file
-// in the .doltcfg directory. An error is returned if any problems were encountered loading the state from disk.
-func loadReplicationRunningState(ctx *sql.Context) (replicaRunningState, error) {
-	doltSession := dsess.DSessFromSess(ctx.Session)
-	filesys := doltSession.Provider().FileSystem()
-
-	replicationRunningStateFilepath, err := filesys.Abs(
-		filepath.Join(replicationRunningStateDirectory, replicaRunningFilename))
-	if err != nil {
-		return notRunning, err
-	}
-
-	if replicaRunning, _ := filesys.Exists(replicationRunningStateFilepath); replicaRunning {
-		return running, nil
-	} else {
-		return notRunning, nil
-	}
-}
-
-// persistReplicaRunningState records the running |state| of a replica to disk by creating a "replica-running" empty
-// file in the .doltcfg directory. An error is returned if any problems were encountered saving the state to disk.
-func persistReplicaRunningState(ctx *sql.Context, state replicaRunningState) error {
+// persistReplicaRunningState records the running |state| of the replication channel named |channel| to disk by
+// creating a "replica-running" empty file (or "replica-running-<channel>" file for a named channel) in the .doltcfg
+// directory. An error is returned if any problems were encountered saving the state to disk.
+func persistReplicaRunningState(ctx *sql.Context, channel string, state replicaRunningState) error {
 	doltSession := dsess.DSessFromSess(ctx.Session)
 	filesys := doltSession.Provider().FileSystem()
 
@@ -80,8 +84,7 @@ func persistReplicaRunningState(ctx *sql.Context, state replicaRunningState) err
 		return err
 	}
 
-	replicationRunningStateFilepath, err := filesys.Abs(
-		filepath.Join(replicationRunningStateDirectory, replicaRunningFilename))
+	replicationRunningStateFilepath, err := filesys.Abs(replicaRunningFilepath(channel))
 	if err != nil {
 		return err
 	}
@@ -102,9 +105,89 @@ func persistReplicaRunningState(ctx *sql.Context, state replicaRunningState) err
 	}
 }
 
-// loadReplicationConfiguration loads the replication configuration for default channel ("") from
-// the "mysql" database, |mysqlDb|.
-func loadReplicationConfiguration(_ *sql.Context, mysqlDb *mysql_db.MySQLDb) (*mysql_db.ReplicaSourceInfo, error) {
+// loadRunningReplicationChannels returns the names of the replication channels that were running the last time the
+// server was running, by looking for "replica-running" files in the .doltcfg directory. An error is returned if any
+// problems were encountered loading the state from disk.
+func loadRunningReplicationChannels(ctx *sql.Context) ([]string, error) {
+	doltSession := dsess.DSessFromSess(ctx.Session)
+	filesys := doltSession.Provider().FileSystem()
+
+	if exists, isDir := filesys.Exists(replicationRunningStateDirectory); !exists || !isDir {
+		return nil, nil
+	}
+
+	var channels []string
+	err := filesys.Iter(replicationRunningStateDirectory, false, func(path string, _ int64, isDir bool) (stop bool) {
+		if isDir {
+			return false
+		}
+		filename := filepath.Base(path)
+		if filename == replicaRunningFilename {
+			channels = append(channels, defaultChannel)
+		} else if channel, ok := strings.CutPrefix(filename, replicaRunningFilename+"-"); ok && isValidChannelName(channel) {
+			channels = append(channels, channel)
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(channels)
+	return channels, nil
+}
+
+// loadConfiguredReplicationChannels returns the names of the named replication channels that have been configured with
+// CHANGE REPLICATION SOURCE TO, by looking for their replication source configuration files in the .doltcfg
+// directory. The default channel is not included.
+func loadConfiguredReplicationChannels(ctx *sql.Context) ([]string, error) {
+	doltSession := dsess.DSessFromSess(ctx.Session)
+	filesys := doltSession.Provider().FileSystem()
+
+	if exists, isDir := filesys.Exists(replicationRunningStateDirectory); !exists || !isDir {
+		return nil, nil
+	}
+
+	var channels []string
+	err := filesys.Iter(replicationRunningStateDirectory, false, func(path string, _ int64, isDir bool) (stop bool) {
+		if isDir {
+			return false
+		}
+		filename, ok := strings.CutSuffix(filepath.Base(path), ".json")
+		if !ok {
+			return false
+		}
+		if channel, ok := strings.CutPrefix(filename, replicaSourceInfoFilename+"-"); ok && isValidChannelName(channel) {
+			channels = append(channels, channel)
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(channels)
+	return channels, nil
+}
+
+// loadReplicationConfiguration loads the replication configuration for the replication channel named |channel|. The
+// configuration for the default channel ("") is loaded from the "mysql" database, |mysqlDb|, and the configuration
+// for a named channel is loaded from the .doltcfg directory. If no configuration has been saved for the channel, nil
+// is returned.
+func loadReplicationConfiguration(ctx *sql.Context, mysqlDb *mysql_db.MySQLDb, channel string) (*mysql_db.ReplicaSourceInfo, error) {
+	if channel != defaultChannel {
+		filesys := dsess.DSessFromSess(ctx.Session).Provider().FileSystem()
+		if exists, _ := filesys.Exists(replicaSourceInfoFilepath(channel)); !exists {
+			return nil, nil
+		}
+
+		bytes, err := filesys.ReadFile(replicaSourceInfoFilepath(channel))
+		if err != nil {
+			return nil, err
+		}
+		return (&mysql_db.ReplicaSourceInfo{}).FromJson(ctx, string(bytes))
+	}
+
 	rd := mysqlDb.Reader()
 	defer rd.Close()
 
@@ -118,9 +201,17 @@ func loadReplicationConfiguration(_ *sql.Context, mysqlDb *mysql_db.MySQLDb) (*m
 	return nil, nil
 }
 
-// deleteReplicationConfiguration deletes all replication configuration for the default channel ("")
-// from the specified "mysql" database, |mysqlDb|.
-func deleteReplicationConfiguration(ctx *sql.Context, mysqlDb *mysql_db.MySQLDb) error {
+// deleteReplicationConfiguration deletes all replication configuration for the replication channel named |channel|.
+// The configuration for the default channel ("") is deleted from the specified "mysql" database, |mysqlDb|.
+func deleteReplicationConfiguration(ctx *sql.Context, mysqlDb *mysql_db.MySQLDb, channel string) error {
+	if channel != defaultChannel {
+		filesys := dsess.DSessFromSess(ctx.Session).Provider().FileSystem()
+		if exists, _ := filesys.Exists(replicaSourceInfoFilepath(channel)); !exists {
+			return nil
+		}
+		return filesys.Delete(replicaSourceInfoFilepath(channel), false)
+	}
+
 	ed := mysqlDb.Editor()
 	defer ed.Close()
 
@@ -129,15 +220,27 @@ func deleteReplicationConfiguration(ctx *sql.Context, mysqlDb *mysql_db.MySQLDb)
 	return mysqlDb.Persist(ctx, ed)
 }
 
-// persistSourceUuid saves the specified |sourceUuid| to the "mysql" database, |mysqlDb|.
-func persistSourceUuid(ctx *sql.Context, sourceUuid string, mysqlDb *mysql_db.MySQLDb) error {
-	replicaSourceInfo, err := loadReplicationConfiguration(ctx, mysqlDb)
+// persistSourceUuid saves the specified |sourceUuid| for the replication channel named |channel|.
+func persistSourceUuid(ctx *sql.Context, channel string, sourceUuid string, mysqlDb *mysql_db.MySQLDb) error {
+	replicaSourceInfo, err := loadReplicationConfiguration(ctx, mysqlDb, channel)
 	if err != nil {
 		return err
 	}
 
 	replicaSourceInfo.Uuid = sourceUuid
-	return persistReplicationConfiguration(ctx, replicaSourceInfo, mysqlDb)
+	return persistReplicationConfiguration(ctx, channel, replicaSourceInfo, mysqlDb)
+}
+
+// replicaRunningFilepath returns the path, relative to the root of the provider's filesystem, of the file that
+// indicates replication was running for the replication channel named |channel|.
+func replicaRunningFilepath(channel string) string {
+	return filepath.Join(replicationRunningStateDirectory, channelFilename(replicaRunningFilename, channel))
+}
+
+// replicaSourceInfoFilepath returns the path, relative to the root of the provider's filesystem, of the file that
+// stores the replication source configuration for the named replication channel |channel|.
+func replicaSourceInfoFilepath(channel string) string {
+	return filepath.Join(replicationRunningStateDirectory, channelFilename(replicaSourceInfoFilename, channel)+".json")
 }
 
 // createEmptyFile creates an empty file at |fullFilepath| if a file does not exist already. If a file does exist
@@ -15,6 +15,7 @@
 package binlogreplication
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -30,6 +31,7 @@ import (
 
 const binlogPositionDirectory = ".doltcfg"
 const binlogPositionFilename = "binlog-position"
+const binlogUncommittedGtidsFilename = "binlog-uncommitted-gtids"
 const mysqlFlavor = "MySQL56"
 
 // binlogPositionStore manages loading and saving data to the binlog position file stored on disk. This provides
@@ -39,13 +41,14 @@ type binlogPositionStore struct {
 	mu sync.Mutex
 }
 
-// Load loads a mysql.Position instance from the .doltcfg/binlog-position file at the root of the specified |filesystem|.
-// This file MUST be stored at the root of the provider's filesystem, and NOT inside a nested database's .doltcfg directory,
-// since the binlog position contains events that cover all databases in a SQL server. The returned mysql.Position
-// represents the set of GTIDs that have been successfully executed and applied on this replica. Currently only the
-// default binlog channel ("") is supported. If no .doltcfg/binlog-position file is stored, this method returns a nil
-// mysql.Position and a nil error. If any errors are encountered, a nil mysql.Position and an error are returned.
-func (store *binlogPositionStore) Load(filesys filesys.Filesys) (*mysql.Position, error) {
+// Load loads a mysql.Position instance for the replication channel named |channel| from the .doltcfg/binlog-position
+// file (or the .doltcfg/binlog-position-<channel> file for a named channel) at the root of the specified |filesystem|.
+// This file MUST be stored at the root of the provider's filesystem, and NOT inside a nested database's .doltcfg
+// directory, since the binlog position contains events that cover all databases in a SQL server. The returned
+// mysql.Position represents the set of GTIDs that have been successfully executed and applied on this replica for
+// the channel. If no position file is stored, this method returns a nil mysql.Position and a nil error. If any
+// errors are encountered, a nil mysql.Position and an error are returned.
+func (store *binlogPositionStore) Load(filesys filesys.Filesys, channel string) (*mysql.Position, error) {
 	store.mu.Lock()
 	defer store.mu.Unlock()
 
@@ -54,12 +57,12 @@ func (store *binlogPositionStore) Load(filesys filesys.Filesys) (*mysql.Position
 		return nil, nil
 	}
 
-	positionFileExists, _ := filesys.Exists(filepath.Join(binlogPositionDirectory, binlogPositionFilename))
+	positionFileExists, _ := filesys.Exists(binlogPositionFilepath(channel))
 	if !positionFileExists {
 		return nil, nil
 	}
 
-	filePath, err := filesys.Abs(filepath.Join(binlogPositionDirectory, binlogPositionFilename))
+	filePath, err := filesys.Abs(binlogPositionFilepath(channel))
 	if err != nil {
 		return nil, err
 	}
@@ -84,13 +87,13 @@ func (store *binlogPositionStore) Load(filesys filesys.Filesys) (*mysql.Position
 	return &position, nil
 }
 
-// Save saves the specified |position| to disk in the .doltcfg/binlog-position file at the root of the provider's
-// filesystem. This file MUST be stored at the root of the provider's filesystem, and NOT inside a nested database's
-// .doltcfg directory, since the binlog position contains events that cover all databases in a SQL server. |position|
-// represents the set of GTIDs that have been successfully executed and applied on this replica. Currently only the
-// default binlog channel ("") is supported. If any errors are encountered persisting the position to disk, an
-// error is returned.
-func (store *binlogPositionStore) Save(ctx *sql.Context, position *mysql.Position) error {
+// Save saves the specified |position| for the replication channel named |channel| to disk in the
+// .doltcfg/binlog-position file (or the .doltcfg/binlog-position-<channel> file for a named channel) at the root of
+// the provider's filesystem. This file MUST be stored at the root of the provider's filesystem, and NOT inside a nested
+// database's .doltcfg directory, since the binlog position contains events that cover all databases in a SQL server.
+// |position| represents the set of GTIDs that have been successfully executed and applied on this replica for the
+// channel. If any errors are encountered persisting the position to disk, an error is returned.
+func (store *binlogPositionStore) Save(ctx *sql.Context, channel string, position *mysql.Position) error {
 	if position == nil {
 		return fmt.Errorf("unable to save binlog position: nil position passed")
 	}
@@ -106,7 +109,7 @@ func (store *binlogPositionStore) Save(ctx *sql.Context, position *mysql.Positio
 		return err
 	}
 
-	filePath, err := filesys.Abs(filepath.Join(binlogPositionDirectory, binlogPositionFilename))
+	filePath, err := filesys.Abs(binlogPositionFilepath(channel))
 	if err != nil {
 		return err
 	}
@@ -115,17 +118,96 @@ func (store *binlogPositionStore) Save(ctx *sql.Context, position *mysql.Positio
 	return os.WriteFile(filePath, []byte(encodedPosition), 0666)
 }
 
-// Delete deletes the stored mysql.Position information stored in .doltcfg/binlog-position in the root of the provider's
-// filesystem. This is useful for the "RESET REPLICA" command, since it clears out the current replication state. If
-// any errors are encountered removing the position file, an error is returned.
-func (store *binlogPositionStore) Delete(ctx *sql.Context) error {
+// Delete deletes the stored mysql.Position information for the replication channel named |channel| from the root of
+// the provider's filesystem. This is useful for the "RESET REPLICA" command, since it clears out the current
+// replication state. If any errors are encountered removing the position file, an error is returned.
+func (store *binlogPositionStore) Delete(ctx *sql.Context, channel string) error {
 	store.mu.Lock()
 	defer store.mu.Unlock()
 
 	doltSession := dsess.DSessFromSess(ctx.Session)
 	filesys := doltSession.Provider().FileSystem()
 
-	return filesys.Delete(filepath.Join(binlogPositionDirectory, binlogPositionFilename), false)
+	if exists, _ := filesys.Exists(binlogPositionFilepath(channel)); !exists {
+		return nil
+	}
+	return filesys.Delete(binlogPositionFilepath(channel), false)
+}
+
+// LoadUncommitted loads the GTIDs of the source transactions applied by the replication channel named |channel| which
+// had not been included in a Dolt commit yet, keyed by the name of the database they changed, from the
+// .doltcfg/binlog-uncommitted-gtids file (or the .doltcfg/binlog-uncommitted-gtids-<channel> file for a named
+// channel) at the root of the specified |filesystem|. If no such file is stored, this method returns a nil map and a
+// nil error.
+func (store *binlogPositionStore) LoadUncommitted(filesys filesys.Filesys, channel string) (map[string]mysql.GTIDSet, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if exists, _ := filesys.Exists(binlogUncommittedGtidsFilepath(channel)); !exists {
+		return nil, nil
+	}
+
+	bytes, err := filesys.ReadFile(binlogUncommittedGtidsFilepath(channel))
+	if err != nil {
+		return nil, err
+	}
+	var encoded map[string]string
+	if err = json.Unmarshal(bytes, &encoded); err != nil {
+		return nil, fmt.Errorf("unable to load uncommitted GTIDs: %s", err.Error())
+	}
+
+	gtids := make(map[string]mysql.GTIDSet, len(encoded))
+	for databaseName, gtidSet := range encoded {
+		position, err := mysql.ParsePosition(mysqlFlavor, gtidSet)
+		if err != nil {
+			return nil, err
+		}
+		gtids[databaseName] = position.GTIDSet
+	}
+	return gtids, nil
+}
+
+// SaveUncommitted saves |gtids|, the GTIDs of the source transactions applied by the replication channel named
+// |channel| which have not been included in a Dolt commit yet, keyed by the name of the database they changed. The
+// changes of those transactions are in the working sets of the databases, so the GTIDs are saved to include them in
+// the next Dolt commit after a restart. An empty |gtids| deletes the stored GTIDs.
+func (store *binlogPositionStore) SaveUncommitted(filesys filesys.Filesys, channel string, gtids map[string]mysql.GTIDSet) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if len(gtids) == 0 {
+		if exists, _ := filesys.Exists(binlogUncommittedGtidsFilepath(channel)); !exists {
+			return nil
+		}
+		return filesys.Delete(binlogUncommittedGtidsFilepath(channel), false)
+	}
+
+	if err := createDoltCfgDir(filesys); err != nil {
+		return err
+	}
+
+	encoded := make(map[string]string, len(gtids))
+	for databaseName, gtidSet := range gtids {
+		encoded[databaseName] = gtidSet.String()
+	}
+	bytes, err := json.Marshal(encoded)
+	if err != nil {
+		return err
+	}
+	return filesys.WriteFile(binlogUncommittedGtidsFilepath(channel), bytes, 0666)
+}
+
+// binlogPositionFilepath returns the path, relative to the root of the provider's filesystem, of the file that
+// stores the binlog position for the replication channel named |channel|.
+func binlogPositionFilepath(channel string) string {
+	return filepath.Join(binlogPositionDirectory, channelFilename(binlogPositionFilename, channel))
+}
+
+// binlogUncommittedGtidsFilepath returns the path, relative to the root of the provider's filesystem, of the file that
+// stores the GTIDs of the applied source transactions not included in a Dolt commit yet for the replication channel
+// named |channel|.
+func binlogUncommittedGtidsFilepath(channel string) string {
+	return filepath.Join(binlogPositionDirectory, channelFilename(binlogUncommittedGtidsFilename, channel))
 }
 
 // createDoltCfgDir creates the .doltcfg directory if it doesn't already exist.
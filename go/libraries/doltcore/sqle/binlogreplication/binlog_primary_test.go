@@ -572,6 +572,107 @@ func TestBinlogPrimary_ChangeReplicationBranch(t *testing.T) {
 	requireReplicaResults(t, "select * from db01.t;", [][]any{{"hundred", "100", "2000"}})
 }
 
+// TestBinlogPrimary_ReplicationBranchPerDatabase asserts that the log_bin_branches system variable can be used
+// to control what branch is replicated for a specific database, while other databases keep replicating the
+// branch from log_bin_branch.
+func TestBinlogPrimary_ReplicationBranchPerDatabase(t *testing.T) {
+	defer teardown(t)
+	mapCopy := copyMap(doltReplicationPrimarySystemVars)
+	mapCopy["log_bin_branches"] = "'db01:branch1'"
+	startSqlServersWithDoltSystemVars(t, mapCopy)
+	setupForDoltToMySqlReplication()
+	startReplicationAndCreateTestDb(t, doltPort)
+
+	// No events should be generated for db01 when we're not updating its replication branch
+	primaryDatabase.MustExec("create table db01.t (pk varchar(100) primary key, c1 int);")
+	primaryDatabase.MustExec("call dolt_commit('-Am', 'creating table t');")
+	waitForReplicaToCatchUp(t)
+	requireReplicaResults(t, "show tables;", [][]any{})
+
+	// Create the branch1 branch and make sure it gets replicated
+	primaryDatabase.MustExec("call dolt_checkout('-b', 'branch1');")
+	primaryDatabase.MustExec("insert into db01.t values('hundred', 100);")
+	waitForReplicaToCatchUp(t)
+	requireReplicaResults(t, "show tables;", [][]any{{"t"}})
+	requireReplicaResults(t, "select * from db01.t;", [][]any{{"hundred", "100"}})
+
+	// Other databases still replicate the main branch
+	primaryDatabase.MustExec("create database db02;")
+	primaryDatabase.MustExec("create table db02.t2 (pk int primary key);")
+	primaryDatabase.MustExec("insert into db02.t2 values (42);")
+	waitForReplicaToCatchUp(t)
+	requireReplicaResults(t, "select * from db02.t2;", [][]any{{"42"}})
+}
+
+// TestBinlogPrimary_ReplicateMultipleBranches asserts that when several branches of a database are configured
+// in log_bin_branches, each branch is replicated to a separate schema named <database>_<branch>.
+func TestBinlogPrimary_ReplicateMultipleBranches(t *testing.T) {
+	defer teardown(t)
+	mapCopy := copyMap(doltReplicationPrimarySystemVars)
+	mapCopy["log_bin_branches"] = "'db01:main,db01:branch1'"
+	startSqlServersWithDoltSystemVars(t, mapCopy)
+	setupForDoltToMySqlReplication()
+	startReplication(t, doltPort)
+
+	// Creating the database creates a schema for each replicated branch
+	primaryDatabase.MustExec("create database db01;")
+	primaryDatabase.MustExec("use db01;")
+	waitForReplicaToCatchUp(t)
+	requireReplicaResults(t, "show databases;", [][]any{
+		{"db01_branch1"}, {"db01_main"}, {"information_schema"}, {"mysql"}, {"performance_schema"}, {"sys"}})
+
+	primaryDatabase.MustExec("create table t (pk int primary key, c1 varchar(100));")
+	primaryDatabase.MustExec("call dolt_commit('-Am', 'creating table t');")
+	primaryDatabase.MustExec("call dolt_branch('branch1');")
+	primaryDatabase.MustExec("insert into t values (1, 'main');")
+	primaryDatabase.MustExec("call dolt_checkout('branch1');")
+	primaryDatabase.MustExec("insert into t values (2, 'branch1');")
+	waitForReplicaToCatchUp(t)
+	requireReplicaResults(t, "select * from db01_main.t;", [][]any{{"1", "main"}})
+	requireReplicaResults(t, "select * from db01_branch1.t;", [][]any{{"2", "branch1"}})
+
+	// Dropping the database drops the schema of each replicated branch
+	primaryDatabase.MustExec("use mysql;")
+	primaryDatabase.MustExec("drop database db01;")
+	waitForReplicaToCatchUp(t)
+	requireReplicaResults(t, "show databases;", [][]any{
+		{"information_schema"}, {"mysql"}, {"performance_schema"}, {"sys"}})
+}
+
+// TestBinlogPrimary_ReplicationBranchMoved asserts that when the replicated branch is moved to another commit, or
+// deleted and created again at another commit, row events are generated to bring the replica to the new state.
+func TestBinlogPrimary_ReplicationBranchMoved(t *testing.T) {
+	defer teardown(t)
+	mapCopy := copyMap(doltReplicationPrimarySystemVars)
+	mapCopy["log_bin_branch"] = "branch1"
+	startSqlServersWithDoltSystemVars(t, mapCopy)
+	setupForDoltToMySqlReplication()
+	startReplicationAndCreateTestDb(t, doltPort)
+
+	primaryDatabase.MustExec("create table db01.t (pk varchar(100) primary key, c1 int);")
+	primaryDatabase.MustExec("call dolt_commit('-Am', 'creating table t');")
+	primaryDatabase.MustExec("call dolt_checkout('-b', 'branch1');")
+	primaryDatabase.MustExec("insert into db01.t values('01', 1);")
+	primaryDatabase.MustExec("call dolt_commit('-am', 'inserting 01');")
+	primaryDatabase.MustExec("SET @OneRowCommit=dolt_hashof('HEAD');")
+	waitForReplicaToCatchUp(t)
+	requireReplicaResults(t, "select * from db01.t;", [][]any{{"01", "1"}})
+
+	// Force branch1 to point to the head of main
+	primaryDatabase.MustExec("call dolt_checkout('main');")
+	primaryDatabase.MustExec("insert into db01.t values('02', 2);")
+	primaryDatabase.MustExec("call dolt_commit('-am', 'inserting 02');")
+	primaryDatabase.MustExec("call dolt_branch('-f', 'branch1', 'main');")
+	waitForReplicaToCatchUp(t)
+	requireReplicaResults(t, "select * from db01.t;", [][]any{{"02", "2"}})
+
+	// Delete branch1 and create it again from an earlier commit
+	primaryDatabase.MustExec("call dolt_branch('-D', 'branch1');")
+	primaryDatabase.MustExec("call dolt_branch('branch1', @OneRowCommit);")
+	waitForReplicaToCatchUp(t)
+	requireReplicaResults(t, "select * from db01.t;", [][]any{{"01", "1"}})
+}
+
 // TestBinlogPrimary_SimpleSchemaChangesWithAutocommit tests that we can make simple schema changes (e.g. create table,
 // alter table, drop table) and replicate the DDL statements correctly.
 func TestBinlogPrimary_SimpleSchemaChangesWithAutocommit(t *testing.T) {
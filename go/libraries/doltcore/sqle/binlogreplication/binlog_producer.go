@@ -38,7 +38,8 @@ import (
 	"github.com/dolthub/dolt/go/store/val"
 )
 
-// BinlogBranch specifies the branch used for generating binlog events.
+// BinlogBranch specifies the branch used for generating binlog events for databases without branches configured in
+// |BinlogBranches|.
 var BinlogBranch = "main"
 
 // binlogProducer implements the doltdb.DatabaseUpdateListener interface so that it can listen for updates to Dolt
@@ -56,6 +57,14 @@ type binlogProducer struct {
 	gtidPosition *mysql.Position
 	gtidSequence int64
 
+	// replicatedRoots holds the last working root replicated for each replicated branch, keyed by the schema the
+	// branch is replicated to. Binlog events are generated from the changes since the last replicated root, so that
+	// replicas stay consistent when a branch is moved to a different commit outside of its working set history, for
+	// example when a branch is deleted and created again.
+	replicatedRoots map[string]doltdb.RootValue
+	// schemaLocks serialize the replication of each schema, keyed by lowercase schema name. See lockSchema.
+	schemaLocks map[string]*sync.Mutex
+
 	logManager *logManager
 }
 
@@ -75,6 +84,8 @@ func NewBinlogProducer(fs filesys.Filesys) (*binlogProducer, error) {
 		binlogEventMeta: *binlogEventMeta,
 		binlogFormat:    binlogFormat,
 		mu:              &sync.Mutex{},
+		replicatedRoots: make(map[string]doltdb.RootValue),
+		schemaLocks:     make(map[string]*sync.Mutex),
 	}
 
 	if err = b.initializeGtidPosition(fs); err != nil {
@@ -96,11 +107,24 @@ func (b *binlogProducer) LogManager(logManager *logManager) {
 // need to change this so that it writes to a binary log file as the intermediate, and the readers watch that
 // log to stream events back to the connected replicas.
 func (b *binlogProducer) WorkingRootUpdated(ctx *sql.Context, databaseName string, branchName string, before doltdb.RootValue, after doltdb.RootValue) error {
-	// We only support updates to a single branch for binlog events, so ignore all other updates
-	if branchName != BinlogBranch {
+	// Ignore updates to branches that aren't replicated
+	schemaName, ok := binlogSchemaForBranch(databaseName, branchName)
+	if !ok {
 		return nil
 	}
 
+	// Branches are updated concurrently, so the changes since the last replicated root must be written and recorded
+	// before the next update of the schema reads it
+	unlock := b.lockSchema(schemaName)
+	defer unlock()
+
+	if replicatedRoot, ok := b.replicatedRoot(schemaName); ok {
+		before = replicatedRoot
+	}
+
+	// Events are sent for the schema the branch is replicated to, which may differ from the database name
+	databaseName = schemaName
+
 	var binlogEvents []mysql.BinlogEvent
 	tableDeltas, err := diff.GetTableDeltas(ctx, before, after)
 	if err != nil {
@@ -143,7 +167,52 @@ func (b *binlogProducer) WorkingRootUpdated(ctx *sql.Context, databaseName strin
 		binlogEvents = append(binlogEvents, b.newXIDEvent())
 	}
 
-	return b.logManager.WriteEvents(binlogEvents...)
+	if err = b.logManager.WriteEvents(binlogEvents...); err != nil {
+		return err
+	}
+
+	b.setReplicatedRoot(schemaName, after)
+	return nil
+}
+
+// lockSchema locks the replication of the schema |schemaName| and returns a function that unlocks it. Reading the last
+// replicated root of the schema, writing the binlog events for the changes since then, and recording the new
+// replicated root happen while the schema is locked, so that concurrent updates neither replicate the same changes
+// twice nor skip any.
+func (b *binlogProducer) lockSchema(schemaName string) func() {
+	b.mu.Lock()
+	l, ok := b.schemaLocks[strings.ToLower(schemaName)]
+	if !ok {
+		l = &sync.Mutex{}
+		b.schemaLocks[strings.ToLower(schemaName)] = l
+	}
+	b.mu.Unlock()
+
+	l.Lock()
+	return l.Unlock
+}
+
+// replicatedRoot returns the last working root replicated to the schema |schemaName|, and false if no working root
+// has been replicated to it since this producer was created.
+func (b *binlogProducer) replicatedRoot(schemaName string) (doltdb.RootValue, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	root, ok := b.replicatedRoots[strings.ToLower(schemaName)]
+	return root, ok
+}
+
+// setReplicatedRoot records |root| as the last working root replicated to the schema |schemaName|. A nil |root|
+// clears the record.
+func (b *binlogProducer) setReplicatedRoot(schemaName string, root doltdb.RootValue) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if root == nil {
+		delete(b.replicatedRoots, strings.ToLower(schemaName))
+	} else {
+		b.replicatedRoots[strings.ToLower(schemaName)] = root
+	}
 }
 
 // DatabaseCreated implements the doltdb.DatabaseUpdateListener interface.
@@ -152,15 +221,23 @@ func (b *binlogProducer) DatabaseCreated(ctx *sql.Context, databaseName string)
 	//       assignment happens sequentially and safely. Also... if a database is created, we need to process that
 	//       update before any data updates to the database itself. Seems like that race could happen otherwise?
 
+	// A schema is created on replicas for each replicated branch. The schemas stay locked until their events are
+	// written, so that no update of a schema is written before its creation.
 	var binlogEvents []mysql.BinlogEvent
-	binlogEvent, err := b.createGtidEvent(ctx)
-	if err != nil {
-		return err
-	}
-	binlogEvents = append(binlogEvents, binlogEvent)
+	for _, schemaName := range binlogSchemasForDatabase(databaseName) {
+		unlock := b.lockSchema(schemaName)
+		defer unlock()
 
-	createDatabaseStatement := fmt.Sprintf("create database `%s`;", databaseName)
-	binlogEvents = append(binlogEvents, b.newQueryEvent(databaseName, createDatabaseStatement))
+		binlogEvent, err := b.createGtidEvent(ctx)
+		if err != nil {
+			return err
+		}
+		binlogEvents = append(binlogEvents, binlogEvent)
+
+		createDatabaseStatement := fmt.Sprintf("create database `%s`;", schemaName)
+		binlogEvents = append(binlogEvents, b.newQueryEvent(schemaName, createDatabaseStatement))
+		b.setReplicatedRoot(schemaName, nil)
+	}
 
 	return b.logManager.WriteEvents(binlogEvents...)
 }
@@ -168,14 +245,20 @@ func (b *binlogProducer) DatabaseCreated(ctx *sql.Context, databaseName string)
 // DatabaseDropped implements the doltdb.DatabaseUpdateListener interface.
 func (b *binlogProducer) DatabaseDropped(ctx *sql.Context, databaseName string) error {
 	var binlogEvents []mysql.BinlogEvent
-	binlogEvent, err := b.createGtidEvent(ctx)
-	if err != nil {
-		return err
-	}
-	binlogEvents = append(binlogEvents, binlogEvent)
+	for _, schemaName := range binlogSchemasForDatabase(databaseName) {
+		unlock := b.lockSchema(schemaName)
+		defer unlock()
 
-	dropDatabaseStatement := fmt.Sprintf("drop database `%s`;", databaseName)
-	binlogEvents = append(binlogEvents, b.newQueryEvent(databaseName, dropDatabaseStatement))
+		binlogEvent, err := b.createGtidEvent(ctx)
+		if err != nil {
+			return err
+		}
+		binlogEvents = append(binlogEvents, binlogEvent)
+
+		dropDatabaseStatement := fmt.Sprintf("drop database `%s`;", schemaName)
+		binlogEvents = append(binlogEvents, b.newQueryEvent(schemaName, dropDatabaseStatement))
+		b.setReplicatedRoot(schemaName, nil)
+	}
 
 	return b.logManager.WriteEvents(binlogEvents...)
 }
@@ -192,7 +275,7 @@ func (b *binlogProducer) initializeGtidPosition(fs filesys.Filesys) error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	position, err := positionStore.Load(fs)
+	position, err := positionStore.Load(fs, defaultChannel)
 	if err != nil {
 		return err
 	}
@@ -261,7 +344,7 @@ func (b *binlogProducer) createGtidEvent(ctx *sql.Context) (mysql.BinlogEvent, e
 
 	// Store the latest executed GTID to disk
 	b.gtidPosition.GTIDSet = b.gtidPosition.GTIDSet.AddGTID(gtid)
-	err = positionStore.Save(ctx, b.gtidPosition)
+	err = positionStore.Save(ctx, defaultChannel, b.gtidPosition)
 	if err != nil {
 		return nil, fmt.Errorf("unable to store GTID executed metadata to disk: %s", err.Error())
 	}
@@ -32,10 +32,11 @@ import (
 	"github.com/dolthub/vitess/go/mysql"
 	"github.com/dolthub/vitess/go/sqltypes"
 	vquery "github.com/dolthub/vitess/go/vt/proto/query"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
 	"github.com/sirupsen/logrus"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
-	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/writer"
@@ -52,8 +53,8 @@ const (
 
 // binlogReplicaApplier represents the process that applies updates from a binlog connection.
 //
-// This type is NOT used concurrently – there is currently only one single applier process running to process binlog
-// events, so the state in this type is NOT protected with a mutex.
+// This type is NOT used concurrently – there is only one single applier process running to process the binlog events
+// of each replication channel, so the state in this type is NOT protected with a mutex.
 type binlogReplicaApplier struct {
 	format                    *mysql.BinlogFormat
 	tableMapsById             map[uint64]*mysql.TableMap
@@ -61,17 +62,30 @@ type binlogReplicaApplier struct {
 	currentGtid               mysql.GTID
 	replicationSourceUuid     string
 	currentPosition           *mysql.Position // successfully executed GTIDs
-	filters                   *filterConfiguration
+	channel                   *replicaChannel
 	running                   atomic.Bool
 	engine                    *gms.Engine
 	dbsWithUncommittedChanges map[string]struct{}
+
+	// uncommittedGtids tracks, for each database, the GTIDs of the applied source transactions that have not been
+	// included in a Dolt commit yet.
+	uncommittedGtids                map[string]mysql.GTIDSet
+	uncommittedTransactions         int
+	firstUncommittedTransactionTime time.Time
+	heartbeatApplied                bool
+
+	// replicaBranch is the @@dolt_binlog_replica_branch branch that dbsOnReplicaBranch refers to.
+	replicaBranch      string
+	dbsOnReplicaBranch map[string]struct{}
 }
 
-func newBinlogReplicaApplier(filters *filterConfiguration) *binlogReplicaApplier {
+// newBinlogReplicaApplier creates a new binlogReplicaApplier that applies the binlog events of the
+// replication |channel|.
+func newBinlogReplicaApplier(channel *replicaChannel) *binlogReplicaApplier {
 	return &binlogReplicaApplier{
 		tableMapsById:       make(map[uint64]*mysql.TableMap),
 		stopReplicationChan: make(chan struct{}),
-		filters:             filters,
+		channel:             channel,
 	}
 }
 
@@ -94,7 +108,7 @@ func (a *binlogReplicaApplier) Go(ctx *sql.Context) {
 		a.running.Store(false)
 		if err != nil {
 			ctx.GetLogger().Errorf("unexpected error of type %T: '%v'", err, err.Error())
-			DoltBinlogReplicaController.setSqlError(mysql.ERUnknownError, err.Error())
+			a.channel.setSqlError(mysql.ERUnknownError, err.Error())
 		}
 	}()
 }
@@ -109,7 +123,7 @@ func (a *binlogReplicaApplier) IsRunning() bool {
 func (a *binlogReplicaApplier) connectAndStartReplicationEventStream(ctx *sql.Context) (*mysql.Conn, error) {
 	var maxConnectionAttempts uint64
 	var connectRetryDelay uint32
-	DoltBinlogReplicaController.updateStatus(func(status *binlogreplication.ReplicaStatus) {
+	a.channel.updateStatus(func(status *binlogreplication.ReplicaStatus) {
 		status.ReplicaIoRunning = binlogreplication.ReplicaIoConnecting
 		status.ReplicaSqlRunning = binlogreplication.ReplicaSqlRunning
 		maxConnectionAttempts = status.SourceRetryCount
@@ -119,21 +133,21 @@ func (a *binlogReplicaApplier) connectAndStartReplicationEventStream(ctx *sql.Co
 	var conn *mysql.Conn
 	var err error
 	for connectionAttempts := uint64(0); ; connectionAttempts++ {
-		replicaSourceInfo, err := loadReplicationConfiguration(ctx, a.engine.Analyzer.Catalog.MySQLDb)
+		replicaSourceInfo, err := loadReplicationConfiguration(ctx, a.engine.Analyzer.Catalog.MySQLDb, a.channel.name)
 
 		if replicaSourceInfo == nil {
 			err = ErrServerNotConfiguredAsReplica
-			DoltBinlogReplicaController.setIoError(ERFatalReplicaError, err.Error())
+			a.channel.setIoError(ERFatalReplicaError, err.Error())
 			return nil, err
 		} else if replicaSourceInfo.Uuid != "" {
 			a.replicationSourceUuid = replicaSourceInfo.Uuid
 		}
 
 		if replicaSourceInfo.Host == "" {
-			DoltBinlogReplicaController.setIoError(ERFatalReplicaError, ErrEmptyHostname.Error())
+			a.channel.setIoError(ERFatalReplicaError, ErrEmptyHostname.Error())
 			return nil, ErrEmptyHostname
 		} else if replicaSourceInfo.User == "" {
-			DoltBinlogReplicaController.setIoError(ERFatalReplicaError, ErrEmptyUsername.Error())
+			a.channel.setIoError(ERFatalReplicaError, ErrEmptyUsername.Error())
 			return nil, ErrEmptyUsername
 		}
 
@@ -177,7 +191,7 @@ func (a *binlogReplicaApplier) connectAndStartReplicationEventStream(ctx *sql.Co
 		return nil, err
 	}
 
-	DoltBinlogReplicaController.updateStatus(func(status *binlogreplication.ReplicaStatus) {
+	a.channel.updateStatus(func(status *binlogreplication.ReplicaStatus) {
 		status.ReplicaIoRunning = binlogreplication.ReplicaIoRunning
 	})
 
@@ -195,7 +209,7 @@ func (a *binlogReplicaApplier) startReplicationEventStream(ctx *sql.Context, con
 	doltSession := dsess.DSessFromSess(ctx.Session)
 	filesys := doltSession.Provider().FileSystem()
 
-	position, err := positionStore.Load(filesys)
+	position, err := positionStore.Load(filesys, a.channel.name)
 	if err != nil {
 		return err
 	}
@@ -263,6 +277,17 @@ func (a *binlogReplicaApplier) startReplicationEventStream(ctx *sql.Context, con
 func (a *binlogReplicaApplier) replicaBinlogEventHandler(ctx *sql.Context) error {
 	engine := a.engine
 
+	// Source transactions applied before a restart which were not committed yet are committed first
+	if err := a.loadUncommittedTransactions(ctx); err != nil {
+		return err
+	}
+	if a.uncommittedTransactions > 0 {
+		if err := a.checkoutReplicaBranch(ctx, engine, loadReplicaCommitSettings()); err != nil {
+			return err
+		}
+		a.commitAppliedTransactions(ctx, engine)
+	}
+
 	var conn *mysql.Conn
 	var eventProducer *binlogEventProducer
 
@@ -289,7 +314,7 @@ func (a *binlogReplicaApplier) replicaBinlogEventHandler(ctx *sql.Context) error
 			err := a.processBinlogEvent(ctx, engine, event)
 			if err != nil {
 				ctx.GetLogger().Errorf("unexpected error of type %T: '%v'", err, err.Error())
-				DoltBinlogReplicaController.setSqlError(mysql.ERUnknownError, err.Error())
+				a.channel.setSqlError(mysql.ERUnknownError, err.Error())
 			}
 
 		case err := <-eventProducer.ErrorChan():
@@ -297,7 +322,7 @@ func (a *binlogReplicaApplier) replicaBinlogEventHandler(ctx *sql.Context) error
 				badConnection := sqlError.Message == io.EOF.Error() ||
 					strings.HasPrefix(sqlError.Message, io.ErrUnexpectedEOF.Error())
 				if badConnection {
-					DoltBinlogReplicaController.updateStatus(func(status *binlogreplication.ReplicaStatus) {
+					a.channel.updateStatus(func(status *binlogreplication.ReplicaStatus) {
 						status.LastIoError = sqlError.Message
 						status.LastIoErrNumber = ERNetReadError
 						currentTime := time.Now()
@@ -311,12 +336,18 @@ func (a *binlogReplicaApplier) replicaBinlogEventHandler(ctx *sql.Context) error
 			} else {
 				// otherwise, log the error if it's something we don't expect and continue
 				ctx.GetLogger().Errorf("unexpected error of type %T: '%v'", err, err.Error())
-				DoltBinlogReplicaController.setIoError(mysql.ERUnknownError, err.Error())
+				a.channel.setIoError(mysql.ERUnknownError, err.Error())
 			}
 
+		case <-a.commitIntervalTimer(ctx):
+			a.commitAppliedTransactions(ctx, engine)
+
 		case <-a.stopReplicationChan:
 			ctx.GetLogger().Trace("received stop replication signal")
 			eventProducer.Stop()
+			if !a.inSourceTransaction(ctx) {
+				a.commitAppliedTransactions(ctx, engine)
+			}
 			return nil
 		}
 	}
@@ -340,7 +371,7 @@ func (a *binlogReplicaApplier) processBinlogEvent(ctx *sql.Context, engine *gms.
 		if err != nil {
 			msg := fmt.Sprintf("unable to strip checksum from binlog event: '%v'", err.Error())
 			ctx.GetLogger().Error(msg)
-			DoltBinlogReplicaController.setSqlError(mysql.ERUnknownError, msg)
+			a.channel.setSqlError(mysql.ERUnknownError, msg)
 		}
 	}
 
@@ -408,7 +439,8 @@ func (a *binlogReplicaApplier) processBinlogEvent(ctx *sql.Context, engine *gms.
 		}
 
 		ctx.SetCurrentDatabase(query.Database)
-		executeQueryWithEngine(ctx, engine, query.SQL)
+		// Errors are recorded in the channel's status, and replication continues with the next event
+		_ = a.executeQueryWithEngine(ctx, engine, query.SQL)
 		createCommit = !strings.EqualFold(query.SQL, "begin")
 
 	case event.IsRotate():
@@ -459,10 +491,13 @@ func (a *binlogReplicaApplier) processBinlogEvent(ctx *sql.Context, engine *gms.
 			"isBegin": isBegin,
 		}).Trace("Received binlog event: GTID")
 		a.currentGtid = gtid
+		if err = a.checkoutReplicaBranch(ctx, engine, loadReplicaCommitSettings()); err != nil {
+			return err
+		}
 		// if the source's UUID hasn't been set yet, set it and persist it
 		if a.replicationSourceUuid == "" {
 			uuid := fmt.Sprintf("%v", gtid.SourceServer())
-			err = persistSourceUuid(ctx, uuid, a.engine.Analyzer.Catalog.MySQLDb)
+			err = persistSourceUuid(ctx, a.channel.name, uuid, a.engine.Analyzer.Catalog.MySQLDb)
 			if err != nil {
 				return err
 			}
@@ -504,7 +539,7 @@ func (a *binlogReplicaApplier) processBinlogEvent(ctx *sql.Context, engine *gms.
 			if flags != 0 {
 				msg := fmt.Sprintf("unsupported binlog protocol message: TableMap event with unsupported flags '%x'", flags)
 				ctx.GetLogger().Errorf(msg)
-				DoltBinlogReplicaController.setSqlError(mysql.ERUnknownError, msg)
+				a.channel.setSqlError(mysql.ERUnknownError, msg)
 			}
 			a.tableMapsById[tableId] = tableMap
 		}
@@ -541,27 +576,28 @@ func (a *binlogReplicaApplier) processBinlogEvent(ctx *sql.Context, engine *gms.
 
 		// Record the last GTID processed after the commit
 		a.currentPosition.GTIDSet = a.currentPosition.GTIDSet.AddGTID(a.currentGtid)
-		err := sql.SystemVariables.AssignValues(map[string]interface{}{"gtid_executed": a.currentPosition.GTIDSet.String()})
+		err := sql.SystemVariables.AssignValues(map[string]interface{}{"gtid_executed": DoltBinlogReplicaController.executedGtidSet()})
 		if err != nil {
 			ctx.GetLogger().Errorf("unable to set @@GLOBAL.gtid_executed: %s", err.Error())
 		}
-		err = positionStore.Save(ctx, a.currentPosition)
+		err = positionStore.Save(ctx, a.channel.name, a.currentPosition)
 		if err != nil {
 			return fmt.Errorf("unable to store GTID executed metadata to disk: %s", err.Error())
 		}
 
-		// For now, create a Dolt commit from every data update. Eventually, we'll want to make this configurable.
-		// We commit to every database that we saw had a dirty session – these identify the databases where we have
-		// run DML commands through the engine. We also commit to every database that was modified through a RowEvent,
-		// which is all tracked through the applier's databasesWithUncommitedChanges property – these don't show up
-		// as dirty in our session, since we used TableWriter to update them.
+		// Dolt commits are created for every database that we saw had a dirty session – these identify the databases
+		// where we have run DML commands through the engine. We also commit to every database that was modified through
+		// a RowEvent, which is all tracked through the applier's databasesWithUncommitedChanges property – these don't
+		// show up as dirty in our session, since we used TableWriter to update them. Depending on the
+		// @@dolt_binlog_replica_commit_* settings, several source transactions may be batched into a single Dolt commit.
 		a.addDatabasesWithUncommittedChanges(databasesToCommit...)
-		for _, database := range a.databasesWithUncommittedChanges() {
-			executeQueryWithEngine(ctx, engine, "use `"+database+"`;")
-			executeQueryWithEngine(ctx, engine,
-				fmt.Sprintf("call dolt_commit('-Am', 'Dolt binlog replica commit: GTID %s');", a.currentGtid))
-		}
+		a.recordAppliedTransaction(a.currentGtid, a.databasesWithUncommittedChanges())
 		a.dbsWithUncommittedChanges = nil
+		if a.shouldCommit(loadReplicaCommitSettings()) {
+			a.commitAppliedTransactions(ctx, engine)
+		} else {
+			a.saveUncommittedTransactions(ctx)
+		}
 	}
 
 	return nil
@@ -613,7 +649,11 @@ func (a *binlogReplicaApplier) processRowEvent(ctx *sql.Context, event mysql.Bin
 		return fmt.Errorf("unable to find replication metadata for table ID: %d", tableId)
 	}
 
-	if a.filters.isTableFilteredOut(ctx, tableMap) {
+	if loadReplicaCommitSettings().isHeartbeatTable(tableMap.Database, tableMap.Name) {
+		a.heartbeatApplied = true
+	}
+
+	if a.channel.filters.isTableFilteredOut(ctx, tableMap) {
 		return nil
 	}
 
@@ -640,7 +680,7 @@ func (a *binlogReplicaApplier) processRowEvent(ctx *sql.Context, event mysql.Bin
 	if flags != 0 {
 		msg := fmt.Sprintf("unsupported binlog protocol message: row event with unsupported flags '%x'", flags)
 		ctx.GetLogger().Errorf(msg)
-		DoltBinlogReplicaController.setSqlError(mysql.ERUnknownError, msg)
+		a.channel.setSqlError(mysql.ERUnknownError, msg)
 	}
 	schema, tableName, err := getTableSchema(ctx, engine, tableMap.Name, tableMap.Database)
 	if err != nil {
@@ -766,7 +806,18 @@ func getTableWriter(ctx *sql.Context, engine *gms.Engine, tableName, databaseNam
 
 	binFormat := sqlDatabase.DbData().Ddb.Format()
 
-	ws, err := env.WorkingSet(ctx, sqlDatabase.GetDoltDB(), sqlDatabase.DbData().Rsr)
+	// Load the working set of the branch checked out in the applier's session, which may be a branch other than
+	// the database's default branch when @@dolt_binlog_replica_branch is set.
+	ds := dsess.DSessFromSess(ctx.Session)
+	headRef, err := ds.CWBHeadRef(ctx, databaseName)
+	if err != nil {
+		return nil, nil, err
+	}
+	wsRef, err := ref.WorkingSetRefForHead(headRef)
+	if err != nil {
+		return nil, nil, err
+	}
+	ws, err := sqlDatabase.GetDoltDB().ResolveWorkingSet(ctx, wsRef)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -780,7 +831,6 @@ func getTableWriter(ctx *sql.Context, engine *gms.Engine, tableName, databaseNam
 	options.ForeignKeyChecksDisabled = foreignKeyChecksDisabled
 	writeSession := writer.NewWriteSession(binFormat, ws, tracker, options)
 
-	ds := dsess.DSessFromSess(ctx.Session)
 	setter := ds.SetWorkingRoot
 
 	tableWriter, err := writeSession.GetTableWriter(ctx, doltdb.TableName{Name: tableName}, databaseName, setter, false)
@@ -965,7 +1015,9 @@ func loadReplicaServerId() (uint32, error) {
 	return serverId, nil
 }
 
-func executeQueryWithEngine(ctx *sql.Context, engine *gms.Engine, query string) {
+// executeQueryWithEngine executes |query| with |engine|, binding |args| to its ? placeholders in order, and returns
+// any error, which is also recorded in the applier's channel status. Commits with "nothing to commit" are not errors.
+func (a *binlogReplicaApplier) executeQueryWithEngine(ctx *sql.Context, engine *gms.Engine, query string, args ...string) error {
 	// Create a sub-context when running queries against the engine, so that we get an accurate query start time.
 	queryCtx := sql.NewContext(ctx, sql.WithSession(ctx.Session))
 
@@ -975,26 +1027,36 @@ func executeQueryWithEngine(ctx *sql.Context, engine *gms.Engine, query string)
 		}).Warn("No current database selected")
 	}
 
-	_, iter, _, err := engine.Query(queryCtx, query)
+	var bindings map[string]sqlparser.Expr
+	if len(args) > 0 {
+		bindings = make(map[string]sqlparser.Expr, len(args))
+		for i, arg := range args {
+			bindings[fmt.Sprintf("v%d", i+1)] = sqlparser.NewStrVal([]byte(arg))
+		}
+	}
+
+	_, iter, _, err := engine.QueryWithBindings(queryCtx, query, nil, bindings, nil)
 	if err != nil {
 		// Log any errors, except for commits with "nothing to commit"
-		if err.Error() != "nothing to commit" {
-			queryCtx.GetLogger().WithFields(logrus.Fields{
-				"error": err.Error(),
-				"query": query,
-			}).Errorf("Error executing query")
-			msg := fmt.Sprintf("Error executing query: %v", err.Error())
-			DoltBinlogReplicaController.setSqlError(mysql.ERUnknownError, msg)
+		if err.Error() == "nothing to commit" {
+			return nil
 		}
-		return
+		queryCtx.GetLogger().WithFields(logrus.Fields{
+			"error": err.Error(),
+			"query": query,
+		}).Errorf("Error executing query")
+		msg := fmt.Sprintf("Error executing query: %v", err.Error())
+		a.channel.setSqlError(mysql.ERUnknownError, msg)
+		return err
 	}
 	for {
 		_, err := iter.Next(queryCtx)
-		if err != nil {
-			if err != io.EOF {
-				queryCtx.GetLogger().Errorf("ERROR reading query results: %v ", err.Error())
-			}
-			return
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			queryCtx.GetLogger().Errorf("ERROR reading query results: %v ", err.Error())
+			a.channel.setSqlError(mysql.ERUnknownError, fmt.Sprintf("Error executing query: %v", err.Error()))
+			return err
 		}
 	}
 }
@@ -0,0 +1,398 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binlogreplication
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	gmsbinlog "github.com/dolthub/go-mysql-server/sql/binlogreplication"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/rowexec"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	ast "github.com/dolthub/vitess/go/vt/sqlparser"
+)
+
+// replicaStatementRegex matches the start of the replication statements that accept a FOR CHANNEL clause.
+var replicaStatementRegex = regexp.MustCompile(`(?is)^\s*(change\s+replication\s+(source|filter)|start\s+replica|` +
+	`stop\s+replica|reset\s+replica|show\s+(replica|slave)\s+status)\b`)
+
+// replicaChannelParser is a sql.Parser that adds support for the FOR CHANNEL clause of replication statements (e.g.
+// CHANGE REPLICATION SOURCE TO ... FOR CHANNEL 'shard1').
+//
+// Neither the vitess grammar nor the GMS plan nodes for replication statements support replication channels, and both
+// are dependencies of Dolt, so the clause is handled here instead: it is removed from the statement before the
+// statement is parsed, and the parsed statement is wrapped in an ast.InjectedStatement that builds a
+// replicaChannelNode, which executes the statement for the named channel. The channel is part of the parsed
+// statement, so prepared statements keep it for each execution. Until the grammar supports the clause, it is only
+// recognized at the end of a replication statement, and channel names given as user variables or placeholders are
+// not supported.
+type replicaChannelParser struct {
+	sql.Parser
+}
+
+var _ sql.Parser = replicaChannelParser{}
+
+// NewReplicaChannelParser returns a sql.Parser that wraps |parser| and adds support for the FOR CHANNEL clause of
+// replication statements.
+func NewReplicaChannelParser(parser sql.Parser) sql.Parser {
+	return replicaChannelParser{Parser: parser}
+}
+
+// Parse implements the sql.Parser interface.
+func (p replicaChannelParser) Parse(ctx *sql.Context, query string, multi bool) (ast.Statement, string, string, error) {
+	return p.ParseWithOptions(ctx, query, ';', multi, sql.LoadSqlMode(ctx).ParserOptions())
+}
+
+// ParseWithOptions implements the sql.Parser interface.
+func (p replicaChannelParser) ParseWithOptions(ctx context.Context, query string, delimiter rune, multi bool, options ast.ParserOptions) (ast.Statement, string, string, error) {
+	stmt, parsed, remainder, err := p.Parser.ParseWithOptions(ctx, query, delimiter, multi, options)
+	if err == nil {
+		return withReplicaChannel(stmt, defaultChannel, false), parsed, remainder, nil
+	}
+
+	channel, strippedQuery, ok := stripChannelClause(query, string(delimiter))
+	if !ok {
+		return stmt, parsed, remainder, err
+	}
+	strippedStmt, strippedParsed, strippedRemainder, strippedErr := p.Parser.ParseWithOptions(ctx, strippedQuery, delimiter, multi, options)
+	if strippedErr != nil || !isReplicaStatement(strippedStmt) {
+		return stmt, parsed, remainder, err
+	}
+
+	channel, err = normalizeChannelName(channel)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return withReplicaChannel(strippedStmt, channel, true), strippedParsed, strippedRemainder, nil
+}
+
+// ParseOneWithOptions implements the sql.Parser interface.
+func (p replicaChannelParser) ParseOneWithOptions(ctx context.Context, query string, options ast.ParserOptions) (ast.Statement, int, error) {
+	stmt, remainderIndex, err := p.Parser.ParseOneWithOptions(ctx, query, options)
+	if err == nil {
+		return withReplicaChannel(stmt, defaultChannel, false), remainderIndex, nil
+	}
+
+	channel, strippedQuery, ok := stripChannelClause(query, ";")
+	if !ok {
+		return stmt, remainderIndex, err
+	}
+	strippedStmt, strippedRemainderIndex, strippedErr := p.Parser.ParseOneWithOptions(ctx, strippedQuery, options)
+	if strippedErr != nil || !isReplicaStatement(strippedStmt) {
+		return stmt, remainderIndex, err
+	}
+
+	channel, err = normalizeChannelName(channel)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// The remainder index refers to the stripped query, so shift it past the removed clause
+	if strippedRemainderIndex > 0 {
+		strippedRemainderIndex += len(query) - len(strippedQuery)
+	}
+	return withReplicaChannel(strippedStmt, channel, true), strippedRemainderIndex, nil
+}
+
+// stripChannelClause removes the FOR CHANNEL clause from the end of the first statement in |query|, if that statement
+// is a replication statement, and returns the named channel and the resulting query. Statements in |query| are
+// separated by |delimiter|. If the first statement in |query| does not end with a FOR CHANNEL clause, false is
+// returned.
+func stripChannelClause(query, delimiter string) (string, string, bool) {
+	if !replicaStatementRegex.MatchString(query) {
+		return "", "", false
+	}
+
+	// Whitespace in the clause may include comments
+	ws := `(?:\s|/\*.*?\*/)`
+	channelClauseRegex, err := regexp.Compile(`(?is)` + ws + `+for` + ws + `+channel` + ws + `+('[^']*'|"[^"]*"|` +
+		"`[^`]*`" + `|[a-z0-9_]+)` + ws + `*(` + regexp.QuoteMeta(delimiter) + `|$)`)
+	if err != nil {
+		return "", "", false
+	}
+	match := channelClauseRegex.FindStringSubmatchIndex(query)
+	if match == nil {
+		return "", "", false
+	}
+
+	// The match must be the last tokens of the statement, not text within a string literal or a comment
+	channel, ok := trailingChannelClause(query[:match[3]])
+	if !ok {
+		return "", "", false
+	}
+	return channel, query[:match[0]] + query[match[4]:], true
+}
+
+// trailingChannelClause tokenizes |statement| and returns the channel named by the FOR CHANNEL clause its tokens end
+// with, or false if they don't end with one.
+func trailingChannelClause(statement string) (string, bool) {
+	type token struct {
+		typ int
+		val string
+	}
+	var tokens []token
+	tokenizer := ast.NewStringTokenizer(statement)
+	for {
+		typ, val := tokenizer.Scan()
+		if typ == 0 {
+			break
+		} else if typ == ast.LEX_ERROR || typ == ';' {
+			return "", false
+		} else if typ != ast.COMMENT {
+			tokens = append(tokens, token{typ: typ, val: string(val)})
+		}
+	}
+
+	n := len(tokens)
+	if n < 3 || tokens[n-3].typ != ast.FOR || tokens[n-2].typ != ast.CHANNEL {
+		return "", false
+	}
+	if tokens[n-1].typ != ast.ID && tokens[n-1].typ != ast.STRING {
+		return "", false
+	}
+	return tokens[n-1].val, true
+}
+
+// isReplicaStatement returns true if |stmt| is a replication statement that is executed by the replica controller.
+func isReplicaStatement(stmt ast.Statement) bool {
+	switch stmt := stmt.(type) {
+	case *ast.ChangeReplicationSource, *ast.ChangeReplicationFilter, *ast.StartReplica, *ast.StopReplica, *ast.ResetReplica:
+		return true
+	case *ast.Show:
+		return isShowReplicaStatus(stmt)
+	default:
+		return false
+	}
+}
+
+// isShowReplicaStatus returns true if |stmt| is a SHOW REPLICA STATUS statement, or its deprecated SHOW SLAVE STATUS
+// form.
+func isShowReplicaStatus(stmt ast.Statement) bool {
+	show, ok := stmt.(*ast.Show)
+	return ok && (strings.EqualFold(show.Type, "replica status") || strings.EqualFold(show.Type, "slave status"))
+}
+
+// withReplicaChannel returns the replication statement |stmt| executed for the replication channel |channel|. |named|
+// is true if the statement named the channel with a FOR CHANNEL clause. Statements which don't name a channel act on
+// the default channel, and are returned as-is, except for SHOW REPLICA STATUS, which shows the status of every channel.
+func withReplicaChannel(stmt ast.Statement, channel string, named bool) ast.Statement {
+	if !isReplicaStatement(stmt) || (!named && !isShowReplicaStatus(stmt)) {
+		return stmt
+	}
+	return ast.InjectedStatement{
+		Statement: replicaChannelStatement{stmt: stmt, channel: channel, allChannels: !named},
+		Auth:      stmt.(ast.AuthNode).GetAuthInformation(),
+	}
+}
+
+// replicaChannelStatement is the ast.Injectable for a replication statement executed for a replication channel.
+type replicaChannelStatement struct {
+	stmt        ast.Statement
+	channel     string
+	allChannels bool
+}
+
+var _ ast.Injectable = replicaChannelStatement{}
+
+// WithResolvedChildren implements the ast.Injectable interface.
+func (s replicaChannelStatement) WithResolvedChildren(children []any) (any, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("invalid child count, expected 0 but got %d", len(children))
+	}
+
+	var node sql.Node
+	switch stmt := s.stmt.(type) {
+	case *ast.ChangeReplicationSource:
+		options, err := replicationOptions(stmt.Options)
+		if err != nil {
+			return nil, err
+		}
+		changeSource := plan.NewChangeReplicationSource(options)
+		changeSource.ReplicaController = DoltBinlogReplicaController
+		node = changeSource
+	case *ast.ChangeReplicationFilter:
+		options, err := replicationOptions(stmt.Options)
+		if err != nil {
+			return nil, err
+		}
+		changeFilter := plan.NewChangeReplicationFilter(options)
+		changeFilter.ReplicaController = DoltBinlogReplicaController
+		node = changeFilter
+	case *ast.StartReplica:
+		startReplica := plan.NewStartReplica()
+		startReplica.ReplicaController = DoltBinlogReplicaController
+		node = startReplica
+	case *ast.StopReplica:
+		stopReplica := plan.NewStopReplica()
+		stopReplica.ReplicaController = DoltBinlogReplicaController
+		node = stopReplica
+	case *ast.ResetReplica:
+		resetReplica := plan.NewResetReplica(stmt.All)
+		resetReplica.ReplicaController = DoltBinlogReplicaController
+		node = resetReplica
+	case *ast.Show:
+		showStatus := plan.NewShowReplicaStatus()
+		if strings.EqualFold(stmt.Type, "slave status") {
+			showStatus = plan.NewShowSlaveStatus()
+		}
+		showStatus.ReplicaController = DoltBinlogReplicaController
+		node = showStatus
+	default:
+		return nil, fmt.Errorf("unsupported replication statement: %T", s.stmt)
+	}
+
+	return &replicaChannelNode{node: node, channel: s.channel, allChannels: s.allChannels}, nil
+}
+
+// String implements the fmt.Stringer interface.
+func (s replicaChannelStatement) String() string {
+	if s.allChannels {
+		return ast.String(s.stmt)
+	}
+	return fmt.Sprintf("%s for channel '%s'", ast.String(s.stmt), s.channel)
+}
+
+// replicationOptions converts the options of a CHANGE REPLICATION SOURCE or CHANGE REPLICATION FILTER statement, as
+// the GMS plan builder does.
+func replicationOptions(options []*ast.ReplicationOption) ([]gmsbinlog.ReplicationOption, error) {
+	converted := make([]gmsbinlog.ReplicationOption, 0, len(options))
+	for _, option := range options {
+		switch value := option.Value.(type) {
+		case string:
+			converted = append(converted, *gmsbinlog.NewReplicationOption(option.Name, gmsbinlog.StringReplicationOptionValue{Value: value}))
+		case int:
+			converted = append(converted, *gmsbinlog.NewReplicationOption(option.Name, gmsbinlog.IntegerReplicationOptionValue{Value: value}))
+		case ast.TableNames:
+			tables := make([]sql.UnresolvedTable, len(value))
+			for i, tableName := range value {
+				tables[i] = plan.NewUnresolvedTable(tableName.Name.String(), tableName.DbQualifier.String())
+			}
+			converted = append(converted, *gmsbinlog.NewReplicationOption(option.Name, gmsbinlog.TableNamesReplicationOptionValue{Value: tables}))
+		case nil:
+			return nil, fmt.Errorf("nil replication option specified for option %q", option.Name)
+		default:
+			return nil, fmt.Errorf("unsupported option value type '%T' specified for option %q", option.Value, option.Name)
+		}
+	}
+	return converted, nil
+}
+
+// replicaChannelNode executes the GMS plan node of a replication statement for a replication channel. SHOW REPLICA
+// STATUS statements which don't name a channel return a row for each channel, and every SHOW REPLICA STATUS row
+// ends with the Channel_Name column.
+type replicaChannelNode struct {
+	node        sql.Node
+	channel     string
+	allChannels bool
+}
+
+var _ sql.ExecSourceRel = (*replicaChannelNode)(nil)
+
+// Resolved implements the sql.Node interface.
+func (n *replicaChannelNode) Resolved() bool {
+	return true
+}
+
+// String implements the sql.Node interface.
+func (n *replicaChannelNode) String() string {
+	if n.allChannels {
+		return n.node.String()
+	}
+	return fmt.Sprintf("%s FOR CHANNEL '%s'", n.node.String(), n.channel)
+}
+
+// Schema implements the sql.Node interface.
+func (n *replicaChannelNode) Schema() sql.Schema {
+	if _, ok := n.node.(*plan.ShowReplicaStatus); !ok {
+		return n.node.Schema()
+	}
+	schema := n.node.Schema().Copy()
+	return append(schema, &sql.Column{Name: "Channel_Name", Type: types.LongText, Default: nil, Nullable: false})
+}
+
+// Children implements the sql.Node interface.
+func (n *replicaChannelNode) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (n *replicaChannelNode) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, sql.ErrInvalidChildrenNumber.New(n, len(children), 0)
+	}
+	return n, nil
+}
+
+// IsReadOnly implements the sql.Node interface.
+func (n *replicaChannelNode) IsReadOnly() bool {
+	return n.node.IsReadOnly()
+}
+
+// RowIter implements the sql.ExecSourceRel interface.
+func (n *replicaChannelNode) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if _, ok := n.node.(*plan.ShowReplicaStatus); !ok {
+		return rowexec.DefaultBuilder.Build(withStatementChannel(ctx, n.channel), n.node, row)
+	}
+
+	channels := []string{n.channel}
+	if n.allChannels {
+		configured, err := loadConfiguredReplicationChannels(ctx)
+		if err != nil {
+			return nil, err
+		}
+		channels = append([]string{defaultChannel}, configured...)
+	}
+
+	var rows []sql.Row
+	for _, channel := range channels {
+		iter, err := rowexec.DefaultBuilder.Build(withStatementChannel(ctx, channel), n.node, row)
+		if err != nil {
+			return nil, err
+		}
+		channelRows, err := sql.RowIterToRows(ctx, iter)
+		if err != nil {
+			return nil, err
+		}
+		for _, channelRow := range channelRows {
+			rows = append(rows, append(channelRow, channel))
+		}
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// statementChannelKey is the context key of the replication channel a replication statement is executed for.
+type statementChannelKey struct{}
+
+// withStatementChannel returns a copy of |ctx| for executing a replication statement for the replication channel
+// |channel|.
+func withStatementChannel(ctx *sql.Context, channel string) *sql.Context {
+	return ctx.WithContext(context.WithValue(ctx.Context, statementChannelKey{}, channel))
+}
+
+// statementChannel returns the replication channel the replication statement being executed with |ctx| is executed
+// for, or the default channel if the statement did not name a channel.
+func statementChannel(ctx *sql.Context) string {
+	if ctx == nil || ctx.Context == nil {
+		return defaultChannel
+	}
+	if channel, ok := ctx.Value(statementChannelKey{}).(string); ok {
+		return channel
+	}
+	return defaultChannel
+}
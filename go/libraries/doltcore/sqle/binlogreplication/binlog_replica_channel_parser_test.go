@@ -0,0 +1,168 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binlogreplication
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	ast "github.com/dolthub/vitess/go/vt/sqlparser"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReplicaChannelParser tests that the FOR CHANNEL clause of replication statements is parsed into a statement that
+// is executed for the named channel.
+func TestReplicaChannelParser(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		channel     string
+		allChannels bool
+		remainder   string
+		err         string
+	}{
+		{
+			name:    "no channel",
+			query:   "START REPLICA;",
+			channel: defaultChannel,
+		},
+		{
+			name:        "show status without a channel",
+			query:       "SHOW REPLICA STATUS",
+			channel:     defaultChannel,
+			allChannels: true,
+		},
+		{
+			name:    "quoted channel",
+			query:   "CHANGE REPLICATION SOURCE TO SOURCE_HOST='localhost', SOURCE_PORT=3306 FOR CHANNEL 'Shard1'",
+			channel: "shard1",
+		},
+		{
+			name:    "unquoted channel",
+			query:   "stop replica for channel shard_2;",
+			channel: "shard_2",
+		},
+		{
+			name:    "comments",
+			query:   "stop replica /* all */ for /* the */ channel shard_2",
+			channel: "shard_2",
+		},
+		{
+			name:      "channel with remainder",
+			query:     "SHOW REPLICA STATUS FOR CHANNEL \"shard-3\"; select 1;",
+			channel:   "shard-3",
+			remainder: " select 1",
+		},
+		{
+			name:    "reset replica all",
+			query:   "RESET REPLICA ALL FOR CHANNEL `shard4`",
+			channel: "shard4",
+		},
+		{
+			name:    "filters",
+			query:   "CHANGE REPLICATION FILTER REPLICATE_DO_TABLE=(db01.t1) FOR CHANNEL 'shard5'",
+			channel: "shard5",
+		},
+		{
+			name:    "clause in a string literal",
+			query:   "CHANGE REPLICATION SOURCE TO SOURCE_HOST='for channel x' FOR CHANNEL 'shard6'",
+			channel: "shard6",
+		},
+		{
+			name:  "invalid channel name",
+			query: "START REPLICA FOR CHANNEL 'shard/1'",
+			err:   "invalid replication channel name 'shard/1'",
+		},
+		{
+			name:  "not a replication statement",
+			query: "SELECT * FROM t FOR CHANNEL 'shard1'",
+			err:   "syntax error",
+		},
+	}
+
+	parser := NewReplicaChannelParser(sql.GlobalParser)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := sql.NewContext(context.Background())
+			stmt, _, remainder, err := parser.ParseWithOptions(ctx, test.query, ';', true, ast.ParserOptions{})
+			if test.err != "" {
+				require.ErrorContains(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.remainder, remainder)
+
+			injected, ok := stmt.(ast.InjectedStatement)
+			if test.channel == defaultChannel && !test.allChannels {
+				// Statements for the default channel are executed by GMS as-is
+				require.False(t, ok)
+				require.True(t, isReplicaStatement(stmt))
+				return
+			}
+			require.True(t, ok)
+
+			node, err := injected.Statement.WithResolvedChildren(nil)
+			require.NoError(t, err)
+			channelNode, ok := node.(*replicaChannelNode)
+			require.True(t, ok)
+			require.Equal(t, test.channel, channelNode.channel)
+			require.Equal(t, test.allChannels, channelNode.allChannels)
+		})
+	}
+}
+
+// TestReplicaChannelParserOne tests that ParseOneWithOptions returns the remainder index of the query it was given,
+// including the removed FOR CHANNEL clause.
+func TestReplicaChannelParserOne(t *testing.T) {
+	parser := NewReplicaChannelParser(sql.GlobalParser)
+	query := "START REPLICA FOR CHANNEL 'shard1'; SELECT 1"
+	stmt, remainderIndex, err := parser.ParseOneWithOptions(sql.NewContext(context.Background()), query, ast.ParserOptions{})
+	require.NoError(t, err)
+	require.IsType(t, ast.InjectedStatement{}, stmt)
+	require.Equal(t, " SELECT 1", query[remainderIndex:])
+}
+
+// TestTrailingChannelClause tests that the FOR CHANNEL clause is only recognized as the last tokens of a statement.
+func TestTrailingChannelClause(t *testing.T) {
+	channel, ok := trailingChannelClause("START REPLICA FOR CHANNEL 'shard1'")
+	require.True(t, ok)
+	require.Equal(t, "shard1", channel)
+
+	_, ok = trailingChannelClause("CHANGE REPLICATION SOURCE TO SOURCE_HOST='h for channel 'shard1'")
+	require.False(t, ok)
+	_, ok = trailingChannelClause("START REPLICA; STOP REPLICA FOR CHANNEL shard1")
+	require.False(t, ok)
+	_, ok = trailingChannelClause("START REPLICA /* FOR CHANNEL shard1 */")
+	require.False(t, ok)
+}
+
+// TestReplicaChannelNode tests that the channel of a statement is carried by its plan node, so that each execution of
+// the statement, e.g. of a prepared statement, is for that channel, and that SHOW REPLICA STATUS shows the channel.
+func TestReplicaChannelNode(t *testing.T) {
+	ctx := sql.NewContext(context.Background())
+	require.Equal(t, defaultChannel, statementChannel(ctx))
+	require.Equal(t, "shard1", statementChannel(withStatementChannel(ctx, "shard1")))
+	require.Equal(t, defaultChannel, statementChannel(ctx))
+
+	stmt, _, _, err := NewReplicaChannelParser(sql.GlobalParser).ParseWithOptions(ctx,
+		"SHOW REPLICA STATUS FOR CHANNEL 'shard1'", ';', false, ast.ParserOptions{})
+	require.NoError(t, err)
+	node, err := stmt.(ast.InjectedStatement).Statement.WithResolvedChildren(nil)
+	require.NoError(t, err)
+	schema := node.(sql.Node).Schema()
+	require.Equal(t, "Channel_Name", schema[len(schema)-1].Name)
+	require.Equal(t, "Replicate_Rewrite_DB", schema[len(schema)-2].Name)
+}
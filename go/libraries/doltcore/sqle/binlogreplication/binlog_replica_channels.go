@@ -0,0 +1,150 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binlogreplication
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/binlogreplication"
+	"github.com/dolthub/vitess/go/mysql"
+)
+
+// defaultChannel is the name of the default replication channel, which is used by replication statements that do not
+// name a channel with a FOR CHANNEL clause.
+const defaultChannel = ""
+
+// ERReplicaChannelDoesNotExist is the MySQL error code returned when a replication statement names a replication
+// channel that has not been configured with CHANGE REPLICATION SOURCE TO.
+const ERReplicaChannelDoesNotExist = 3074
+
+// channelNameRegex matches the replication channel names supported by Dolt. Channel names are used to name the files
+// that hold each channel's metadata, so they are limited to characters that are safe to use in file names.
+var channelNameRegex = regexp.MustCompile(`^[a-z0-9_\-]{1,64}$`)
+
+// replicaChannel holds the state of a single replication channel on a replica. Each replication channel replicates
+// from its own source server, with its own applier goroutine, executed GTID position, and replication filters, so
+// that a replica can consolidate the data from several source servers.
+type replicaChannel struct {
+	name    string
+	status  binlogreplication.ReplicaStatus
+	filters *filterConfiguration
+	applier *binlogReplicaApplier
+
+	// ctx is the execution context for the applier of a named channel; the default channel uses the
+	// controller's execution context
+	ctx *sql.Context
+
+	// statusMutex blocks concurrent access to the ReplicaStatus struct
+	statusMutex *sync.Mutex
+}
+
+// newReplicaChannel creates a new replicaChannel instance for the replication channel named |name|.
+func newReplicaChannel(name string) *replicaChannel {
+	channel := &replicaChannel{
+		name:        name,
+		filters:     newFilterConfiguration(),
+		statusMutex: &sync.Mutex{},
+	}
+	channel.status.ConnectRetry = 60
+	channel.status.SourceRetryCount = 86400
+	channel.status.AutoPosition = true
+	channel.status.ReplicaIoRunning = binlogreplication.ReplicaIoNotRunning
+	channel.status.ReplicaSqlRunning = binlogreplication.ReplicaSqlNotRunning
+	channel.applier = newBinlogReplicaApplier(channel)
+	return channel
+}
+
+// updateStatus allows the caller to safely update the channel's status. The channel locks its mutex before the
+// specified function |f| is called, and unlocks it after |f| is finished running. The current status is passed
+// into the callback function |f| and the caller can safely update or copy any fields they need.
+func (c *replicaChannel) updateStatus(f func(status *binlogreplication.ReplicaStatus)) {
+	c.statusMutex.Lock()
+	defer c.statusMutex.Unlock()
+	f(&c.status)
+}
+
+// setIoError updates the channel's replication status with the specific |errno| and |message| to describe an IO error.
+func (c *replicaChannel) setIoError(errno uint, message string) {
+	c.statusMutex.Lock()
+	defer c.statusMutex.Unlock()
+
+	// truncate the message to avoid errors when reporting replica status
+	if len(message) > 256 {
+		message = message[:256]
+	}
+
+	currentTime := time.Now()
+	c.status.LastIoErrorTimestamp = &currentTime
+	c.status.LastIoErrNumber = errno
+	c.status.LastIoError = message
+}
+
+// setSqlError updates the channel's replication status with the specific |errno| and |message| to describe an SQL
+// error.
+func (c *replicaChannel) setSqlError(errno uint, message string) {
+	c.statusMutex.Lock()
+	defer c.statusMutex.Unlock()
+
+	// truncate the message to avoid errors when reporting replica status
+	if len(message) > 256 {
+		message = message[:256]
+	}
+
+	currentTime := time.Now()
+	c.status.LastSqlErrorTimestamp = &currentTime
+	c.status.LastSqlErrNumber = errno
+	c.status.LastSqlError = message
+}
+
+// normalizeChannelName returns the normalized form of the replication channel name |name|, as used to identify the
+// channel. Channel names are case-insensitive, so they are normalized to lowercase. An error is returned if |name| is
+// not a valid channel name.
+func normalizeChannelName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == defaultChannel {
+		return defaultChannel, nil
+	}
+	if !isValidChannelName(name) {
+		return "", fmt.Errorf("invalid replication channel name '%s': channel names must be at most 64 characters "+
+			"and may only contain letters, digits, '_', and '-'", name)
+	}
+	return name, nil
+}
+
+// isValidChannelName returns true if |name| is a valid, normalized name for a named replication channel.
+func isValidChannelName(name string) bool {
+	return channelNameRegex.MatchString(name)
+}
+
+// channelFilename returns the name of the file that stores the data identified by |filename| for the replication
+// channel named |channel|. The default channel uses |filename| as-is, so that replicas configured before replication
+// channels were supported keep using the same files, and named channels append the channel name to |filename|.
+func channelFilename(filename, channel string) string {
+	if channel == defaultChannel {
+		return filename
+	}
+	return filename + "-" + channel
+}
+
+// errReplicaChannelDoesNotExist returns the error used when a replication statement names the replication channel
+// |channel|, but the channel has not been configured.
+func errReplicaChannelDoesNotExist(channel string) error {
+	return mysql.NewSQLError(ERReplicaChannelDoesNotExist, "HY000", "Replica channel '%s' does not exist.", channel)
+}
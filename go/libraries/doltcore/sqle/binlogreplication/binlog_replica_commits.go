@@ -0,0 +1,275 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binlogreplication
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	gms "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/dolthub/vitess/go/mysql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// replicaCommitSettings holds the settings that control when the replica applier creates Dolt commits for the
+// changes replicated from the source server, and which branch those changes are applied to.
+type replicaCommitSettings struct {
+	// branch is the branch that replicated changes are applied to in every database. If empty, changes are
+	// applied to each database's default branch.
+	branch string
+	// transactions is the number of source transactions to include in each Dolt commit. Zero disables
+	// committing by transaction count.
+	transactions int
+	// interval is the maximum time to wait after applying a source transaction before creating a Dolt commit
+	// for it. Zero disables committing by time.
+	interval time.Duration
+	// heartbeatTable is the name of the table, optionally qualified with its database, that triggers a Dolt
+	// commit whenever a source transaction changes it. Empty disables committing on heartbeats.
+	heartbeatTable string
+}
+
+// loadReplicaCommitSettings loads the replica commit settings from the @@dolt_binlog_replica_* system variables.
+func loadReplicaCommitSettings() replicaCommitSettings {
+	settings := replicaCommitSettings{transactions: 1}
+	if _, value, ok := sql.SystemVariables.GetGlobal(dsess.DoltBinlogReplicaBranch); ok {
+		settings.branch, _ = value.(string)
+	}
+	if _, value, ok := sql.SystemVariables.GetGlobal(dsess.DoltBinlogReplicaCommitTransactions); ok {
+		if transactions, _, err := types.Int64.Convert(value); err == nil {
+			settings.transactions = int(transactions.(int64))
+		}
+	}
+	if _, value, ok := sql.SystemVariables.GetGlobal(dsess.DoltBinlogReplicaCommitInterval); ok {
+		if seconds, _, err := types.Int64.Convert(value); err == nil {
+			settings.interval = time.Duration(seconds.(int64)) * time.Second
+		}
+	}
+	if _, value, ok := sql.SystemVariables.GetGlobal(dsess.DoltBinlogReplicaHeartbeatTable); ok {
+		settings.heartbeatTable, _ = value.(string)
+	}
+	settings.branch = strings.TrimSpace(settings.branch)
+	settings.heartbeatTable = strings.TrimSpace(settings.heartbeatTable)
+	return settings
+}
+
+// batching returns true if any of the settings that batch several source transactions into a Dolt commit are set.
+// When none are set, a Dolt commit is created for every source transaction.
+func (s replicaCommitSettings) batching() bool {
+	return s.transactions > 0 || s.interval > 0 || s.heartbeatTable != ""
+}
+
+// isHeartbeatTable returns true if the table named |tableName| in the database named |databaseName| is the
+// configured heartbeat table.
+func (s replicaCommitSettings) isHeartbeatTable(databaseName, tableName string) bool {
+	if s.heartbeatTable == "" {
+		return false
+	}
+	heartbeatDatabase, heartbeatTable, qualified := strings.Cut(s.heartbeatTable, ".")
+	if !qualified {
+		return strings.EqualFold(heartbeatDatabase, tableName)
+	}
+	return strings.EqualFold(heartbeatDatabase, databaseName) && strings.EqualFold(heartbeatTable, tableName)
+}
+
+// recordAppliedTransaction records that the source transaction |gtid|, which changed the databases named
+// |databaseNames|, has been applied, so that it is included in the next Dolt commit for those databases.
+func (a *binlogReplicaApplier) recordAppliedTransaction(gtid mysql.GTID, databaseNames []string) {
+	if a.uncommittedGtids == nil {
+		a.uncommittedGtids = make(map[string]mysql.GTIDSet)
+	}
+	for _, databaseName := range databaseNames {
+		if gtids, ok := a.uncommittedGtids[databaseName]; ok {
+			a.uncommittedGtids[databaseName] = gtids.AddGTID(gtid)
+		} else {
+			a.uncommittedGtids[databaseName] = gtid.GTIDSet()
+		}
+	}
+
+	if a.uncommittedTransactions == 0 {
+		a.firstUncommittedTransactionTime = time.Now()
+	}
+	a.uncommittedTransactions++
+}
+
+// shouldCommit returns true if the applied source transactions that have not been committed yet should be
+// committed to Dolt, according to |settings|.
+func (a *binlogReplicaApplier) shouldCommit(settings replicaCommitSettings) bool {
+	switch {
+	case a.uncommittedTransactions == 0:
+		return false
+	case !settings.batching():
+		return true
+	case a.heartbeatApplied:
+		return true
+	case settings.transactions > 0 && a.uncommittedTransactions >= settings.transactions:
+		return true
+	case settings.interval > 0 && time.Since(a.firstUncommittedTransactionTime) >= settings.interval:
+		return true
+	default:
+		return false
+	}
+}
+
+// commitIntervalTimer returns a channel that receives a value when the applied source transactions that have not
+// been committed yet must be committed to Dolt, because @@dolt_binlog_replica_commit_interval has elapsed since
+// the first of them was applied. If there are no uncommitted transactions, committing by time is disabled, or a
+// source transaction is partially applied, a nil channel, which never receives a value, is returned. In the last
+// case, the Dolt commit is created when the source transaction finishes.
+func (a *binlogReplicaApplier) commitIntervalTimer(ctx *sql.Context) <-chan time.Time {
+	settings := loadReplicaCommitSettings()
+	if a.uncommittedTransactions == 0 || settings.interval <= 0 || a.inSourceTransaction(ctx) {
+		return nil
+	}
+	return time.After(time.Until(a.firstUncommittedTransactionTime.Add(settings.interval)))
+}
+
+// inSourceTransaction returns true if the applier has applied some, but not all, of the changes from a source
+// transaction. Those changes must not be included in a Dolt commit until the rest of the transaction is applied.
+func (a *binlogReplicaApplier) inSourceTransaction(ctx *sql.Context) bool {
+	if len(a.dbsWithUncommittedChanges) > 0 {
+		return true
+	}
+	return len(dsess.DSessFromSess(ctx.Session).DirtyDatabases()) > 0
+}
+
+// saveUncommittedTransactions saves the GTIDs of the applied source transactions that have not been committed yet, so
+// that they are committed after a restart. Errors are recorded in the channel's status.
+func (a *binlogReplicaApplier) saveUncommittedTransactions(ctx *sql.Context) {
+	filesys := dsess.DSessFromSess(ctx.Session).Provider().FileSystem()
+	if err := positionStore.SaveUncommitted(filesys, a.channel.name, a.uncommittedGtids); err != nil {
+		msg := fmt.Sprintf("unable to store uncommitted GTIDs: %s", err.Error())
+		ctx.GetLogger().Error(msg)
+		a.channel.setSqlError(mysql.ERUnknownError, msg)
+	}
+}
+
+// loadUncommittedTransactions loads the GTIDs of the source transactions applied before the server was restarted
+// which had not been committed yet, unless the applier already tracks uncommitted transactions. The changes of those
+// transactions are in the working sets of the @@dolt_binlog_replica_branch branch, which is assumed to be unchanged
+// since the restart.
+func (a *binlogReplicaApplier) loadUncommittedTransactions(ctx *sql.Context) error {
+	if a.uncommittedTransactions > 0 {
+		return nil
+	}
+	filesys := dsess.DSessFromSess(ctx.Session).Provider().FileSystem()
+	gtids, err := positionStore.LoadUncommitted(filesys, a.channel.name)
+	if err != nil || len(gtids) == 0 {
+		return err
+	}
+
+	a.uncommittedGtids = gtids
+	a.uncommittedTransactions = 1
+	a.firstUncommittedTransactionTime = time.Now()
+	a.replicaBranch = loadReplicaCommitSettings().branch
+	a.dbsOnReplicaBranch = make(map[string]struct{})
+	return nil
+}
+
+// commitAppliedTransactions creates a Dolt commit in each database changed by the applied source transactions that
+// have not been committed yet. The message of each commit records the GTIDs of the source transactions it includes,
+// so that the Dolt commit history can be traced back to the source server. Databases which can't be committed are
+// kept uncommitted, and their commit is retried with the next commit.
+func (a *binlogReplicaApplier) commitAppliedTransactions(ctx *sql.Context, engine *gms.Engine) {
+	if a.uncommittedTransactions == 0 {
+		return
+	}
+	databaseNames := keys(a.uncommittedGtids)
+	sort.Strings(databaseNames)
+	for _, databaseName := range databaseNames {
+		if err := a.executeQueryWithEngine(ctx, engine, "use "+quoteIdentifier(databaseName)+";"); err != nil {
+			continue
+		}
+		message := fmt.Sprintf("Dolt binlog replica commit: GTID %s", a.uncommittedGtids[databaseName])
+		if err := a.executeQueryWithEngine(ctx, engine, "call dolt_commit('-Am', ?);", message); err != nil {
+			continue
+		}
+		delete(a.uncommittedGtids, databaseName)
+	}
+
+	if len(a.uncommittedGtids) == 0 {
+		a.uncommittedGtids = nil
+		a.uncommittedTransactions = 0
+		a.firstUncommittedTransactionTime = time.Time{}
+		a.heartbeatApplied = false
+	}
+	a.saveUncommittedTransactions(ctx)
+}
+
+// checkoutReplicaBranch switches the applier's session to the @@dolt_binlog_replica_branch branch in every user
+// database, creating the branch from the database's checked out branch if it doesn't exist yet. This must be called
+// between source transactions, since a branch created inside a transaction is not visible to that transaction. An
+// error is returned if a database could not be switched to the branch, since the changes of the next source
+// transaction would be applied to a different branch.
+func (a *binlogReplicaApplier) checkoutReplicaBranch(ctx *sql.Context, engine *gms.Engine, settings replicaCommitSettings) error {
+	if settings.branch == "" {
+		return nil
+	}
+	if a.replicaBranch != settings.branch {
+		// Changes applied to the previous branch are committed there before switching branches
+		a.commitAppliedTransactions(ctx, engine)
+		a.replicaBranch = settings.branch
+		a.dbsOnReplicaBranch = make(map[string]struct{})
+	}
+
+	var errs []error
+	doltSession := dsess.DSessFromSess(ctx.Session)
+	for _, databaseName := range getAllUserDatabaseNames(ctx, engine) {
+		if _, ok := a.dbsOnReplicaBranch[strings.ToLower(databaseName)]; ok {
+			continue
+		}
+
+		headRef, err := doltSession.CWBHeadRef(ctx, databaseName)
+		if err == nil && headRef.GetPath() == settings.branch {
+			a.dbsOnReplicaBranch[strings.ToLower(databaseName)] = struct{}{}
+			continue
+		}
+
+		ddb, ok := doltSession.GetDoltDB(ctx, databaseName)
+		if !ok {
+			continue
+		}
+		hasBranch, err := ddb.HasRef(ctx, ref.NewBranchRef(settings.branch))
+		if err == nil {
+			err = a.executeQueryWithEngine(ctx, engine, "use "+quoteIdentifier(databaseName)+";")
+		}
+		if err == nil && hasBranch {
+			err = a.executeQueryWithEngine(ctx, engine, "call dolt_checkout(?);", settings.branch)
+		} else if err == nil {
+			err = a.executeQueryWithEngine(ctx, engine, "call dolt_checkout('-b', ?);", settings.branch)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to check out branch '%s' in database '%s': %w", settings.branch, databaseName, err))
+			continue
+		}
+
+		headRef, err = doltSession.CWBHeadRef(ctx, databaseName)
+		if err == nil && headRef.GetPath() == settings.branch {
+			a.dbsOnReplicaBranch[strings.ToLower(databaseName)] = struct{}{}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// quoteIdentifier returns |identifier| quoted with backticks, for use in a query.
+func quoteIdentifier(identifier string) string {
+	return "`" + strings.ReplaceAll(identifier, "`", "``") + "`"
+}
@@ -0,0 +1,213 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binlogreplication
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+// TestBinlogReplicaCommitBatching tests that @@dolt_binlog_replica_commit_transactions batches several source
+// transactions into a single Dolt commit, and that the commit message records the GTIDs it includes.
+func TestBinlogReplicaCommitBatching(t *testing.T) {
+	defer teardown(t)
+	startSqlServersWithDoltSystemVars(t, doltReplicaSystemVars)
+	startReplicationAndCreateTestDb(t, mySqlPort)
+	replicaDatabase.MustExec("set @@GLOBAL.dolt_binlog_replica_commit_transactions=3;")
+
+	primaryDatabase.MustExec("create table t (pk int primary key);")
+	primaryDatabase.MustExec("insert into t values (1);")
+	primaryDatabase.MustExec("insert into t values (2);")
+	primaryDatabase.MustExec("insert into t values (3);")
+	primaryDatabase.MustExec("insert into t values (4);")
+	waitForReplicaToCatchUp(t)
+
+	// The first three transactions are committed, the last two are still pending
+	requireReplicaResults(t, "select count(*) from db01.dolt_log;", [][]any{{"2"}})
+	requireReplicaResults(t, "select * from db01.t order by pk;", [][]any{{"1"}, {"2"}, {"3"}, {"4"}})
+	requireReplicaResults(t, "select count(*) from db01.dolt_status;", [][]any{{"1"}})
+
+	primaryDatabase.MustExec("insert into t values (5);")
+	waitForReplicaToCatchUp(t)
+	requireReplicaResults(t, "select count(*) from db01.dolt_log;", [][]any{{"3"}})
+	requireReplicaResults(t, "select count(*) from db01.dolt_status;", [][]any{{"0"}})
+
+	rows, err := replicaDatabase.Queryx("select message from db01.dolt_log limit 1;")
+	require.NoError(t, err)
+	row := convertMapScanResultToStrings(readNextRow(t, rows))
+	require.Regexp(t, `^Dolt binlog replica commit: GTID [0-9a-f\-]+:\d+-\d+$`, row["message"])
+	require.NoError(t, rows.Close())
+}
+
+// TestBinlogReplicaCommitInterval tests that @@dolt_binlog_replica_commit_interval commits applied source
+// transactions once the interval elapses, even when no more transactions arrive.
+func TestBinlogReplicaCommitInterval(t *testing.T) {
+	defer teardown(t)
+	startSqlServersWithDoltSystemVars(t, doltReplicaSystemVars)
+	startReplicationAndCreateTestDb(t, mySqlPort)
+	replicaDatabase.MustExec("set @@GLOBAL.dolt_binlog_replica_commit_transactions=0;")
+	replicaDatabase.MustExec("set @@GLOBAL.dolt_binlog_replica_commit_interval=2;")
+
+	primaryDatabase.MustExec("create table t (pk int primary key);")
+	primaryDatabase.MustExec("insert into t values (1), (2);")
+	waitForReplicaToCatchUp(t)
+
+	time.Sleep(3 * time.Second)
+	requireReplicaResults(t, "select count(*) from db01.dolt_status;", [][]any{{"0"}})
+	requireReplicaResults(t, "select count(*) from db01.`t` as of 'HEAD';", [][]any{{"2"}})
+}
+
+// TestBinlogReplicaHeartbeatTable tests that changes to the @@dolt_binlog_replica_heartbeat_table table trigger a
+// Dolt commit of all applied source transactions.
+func TestBinlogReplicaHeartbeatTable(t *testing.T) {
+	defer teardown(t)
+	startSqlServersWithDoltSystemVars(t, doltReplicaSystemVars)
+	startReplicationAndCreateTestDb(t, mySqlPort)
+	replicaDatabase.MustExec("set @@GLOBAL.dolt_binlog_replica_commit_transactions=0;")
+	replicaDatabase.MustExec("set @@GLOBAL.dolt_binlog_replica_heartbeat_table='db01.heartbeat';")
+
+	primaryDatabase.MustExec("create table heartbeat (pk int primary key, ts datetime);")
+	primaryDatabase.MustExec("create table t (pk int primary key);")
+	primaryDatabase.MustExec("insert into t values (1), (2);")
+	primaryDatabase.MustExec("insert into t values (3);")
+	waitForReplicaToCatchUp(t)
+	requireReplicaResults(t, "select count(*) from db01.dolt_log;", [][]any{{"1"}})
+
+	primaryDatabase.MustExec("insert into heartbeat values (1, now());")
+	waitForReplicaToCatchUp(t)
+	requireReplicaResults(t, "select count(*) from db01.dolt_log;", [][]any{{"2"}})
+	requireReplicaResults(t, "select count(*) from db01.dolt_status;", [][]any{{"0"}})
+}
+
+// TestBinlogReplicaBranch tests that @@dolt_binlog_replica_branch applies replicated changes to the configured
+// branch, creating the branch if it doesn't exist, and leaves the default branch untouched.
+func TestBinlogReplicaBranch(t *testing.T) {
+	defer teardown(t)
+	startSqlServersWithDoltSystemVars(t, doltReplicaSystemVars)
+	replicaDatabase.MustExec("set @@GLOBAL.dolt_binlog_replica_branch='replica';")
+	startReplicationAndCreateTestDb(t, mySqlPort)
+
+	primaryDatabase.MustExec("create table t (pk int primary key);")
+	primaryDatabase.MustExec("insert into t values (1), (2);")
+	waitForReplicaToCatchUp(t)
+
+	requireReplicaResults(t, "select name from db01.dolt_branches order by name;", [][]any{{"main"}, {"replica"}})
+	requireReplicaResults(t, "select * from `db01/replica`.t;", [][]any{{"1"}, {"2"}})
+	requireReplicaResults(t, "select count(*) from `db01/replica`.dolt_log;", [][]any{{"3"}})
+	requireReplicaResults(t, "select count(*) from `db01/main`.dolt_log;", [][]any{{"1"}})
+}
+
+// TestBinlogReplicaUncommittedRestart tests that source transactions applied but not yet included in a Dolt commit
+// when the server stops are committed once replication is restarted.
+func TestBinlogReplicaUncommittedRestart(t *testing.T) {
+	defer teardown(t)
+	startSqlServersWithDoltSystemVars(t, doltReplicaSystemVars)
+	startReplicationAndCreateTestDb(t, mySqlPort)
+	replicaDatabase.MustExec("set @@GLOBAL.dolt_binlog_replica_commit_transactions=0;")
+
+	primaryDatabase.MustExec("create table t (pk int primary key);")
+	primaryDatabase.MustExec("insert into t values (1), (2);")
+	waitForReplicaToCatchUp(t)
+	requireReplicaResults(t, "select count(*) from db01.dolt_log;", [][]any{{"1"}})
+
+	stopDoltSqlServer(t)
+	var err error
+	doltPort, doltProcess, err = startDoltSqlServer(testDir, nil)
+	require.NoError(t, err)
+	replicaDatabase.MustExec("set @@global.server_id=123;")
+	replicaDatabase.MustExec("START REPLICA;")
+	waitForReplicaToCatchUp(t)
+
+	requireReplicaResults(t, "select count(*) from db01.dolt_status;", [][]any{{"0"}})
+	requireReplicaResults(t, "select count(*) from db01.`t` as of 'HEAD';", [][]any{{"2"}})
+	rows, err := replicaDatabase.Queryx("select message from db01.dolt_log limit 1;")
+	require.NoError(t, err)
+	row := convertMapScanResultToStrings(readNextRow(t, rows))
+	require.Regexp(t, `^Dolt binlog replica commit: GTID [0-9a-f\-]+:\d+-\d+$`, row["message"])
+	require.NoError(t, rows.Close())
+}
+
+// TestUncommittedGtids tests that the GTIDs of uncommitted source transactions are saved and loaded per channel,
+// and that saving no GTIDs removes them.
+func TestUncommittedGtids(t *testing.T) {
+	fs := filesys.EmptyInMemFS("/")
+	store := &binlogPositionStore{}
+
+	gtids, err := store.LoadUncommitted(fs, "")
+	require.NoError(t, err)
+	require.Nil(t, gtids)
+
+	position, err := mysql.ParsePosition(mysqlFlavor, "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5")
+	require.NoError(t, err)
+	require.NoError(t, store.SaveUncommitted(fs, "", map[string]mysql.GTIDSet{"db01": position.GTIDSet}))
+	require.NoError(t, store.SaveUncommitted(fs, "ch2", map[string]mysql.GTIDSet{"db02": position.GTIDSet}))
+
+	gtids, err = store.LoadUncommitted(fs, "")
+	require.NoError(t, err)
+	require.Len(t, gtids, 1)
+	require.Equal(t, position.GTIDSet.String(), gtids["db01"].String())
+	gtids, err = store.LoadUncommitted(fs, "ch2")
+	require.NoError(t, err)
+	require.Len(t, gtids, 1)
+	require.Equal(t, position.GTIDSet.String(), gtids["db02"].String())
+
+	require.NoError(t, store.SaveUncommitted(fs, "", nil))
+	gtids, err = store.LoadUncommitted(fs, "")
+	require.NoError(t, err)
+	require.Nil(t, gtids)
+	gtids, err = store.LoadUncommitted(fs, "ch2")
+	require.NoError(t, err)
+	require.Len(t, gtids, 1)
+}
+
+// TestQuoteIdentifier tests that database names are quoted safely for use in a query.
+func TestQuoteIdentifier(t *testing.T) {
+	require.Equal(t, "`db01`", quoteIdentifier("db01"))
+	require.Equal(t, "`my``db`", quoteIdentifier("my`db"))
+	require.Equal(t, "`db'); drop database x; --`", quoteIdentifier("db'); drop database x; --"))
+}
+
+// TestReplicaCommitSettings tests when the replica applier decides to create a Dolt commit for the source
+// transactions it has applied.
+func TestReplicaCommitSettings(t *testing.T) {
+	settings := replicaCommitSettings{heartbeatTable: "db01.heartbeat"}
+	require.True(t, settings.isHeartbeatTable("db01", "heartbeat"))
+	require.True(t, settings.isHeartbeatTable("DB01", "HeartBeat"))
+	require.False(t, settings.isHeartbeatTable("db02", "heartbeat"))
+	settings = replicaCommitSettings{heartbeatTable: "heartbeat"}
+	require.True(t, settings.isHeartbeatTable("db02", "heartbeat"))
+	require.False(t, settings.isHeartbeatTable("heartbeat", "t"))
+	require.False(t, replicaCommitSettings{}.isHeartbeatTable("db01", "heartbeat"))
+
+	applier := &binlogReplicaApplier{}
+	require.False(t, applier.shouldCommit(replicaCommitSettings{}))
+	applier.uncommittedTransactions = 1
+	require.True(t, applier.shouldCommit(replicaCommitSettings{}))
+	require.True(t, applier.shouldCommit(replicaCommitSettings{transactions: 1}))
+	require.False(t, applier.shouldCommit(replicaCommitSettings{transactions: 2}))
+	require.False(t, applier.shouldCommit(replicaCommitSettings{heartbeatTable: "heartbeat"}))
+	applier.heartbeatApplied = true
+	require.True(t, applier.shouldCommit(replicaCommitSettings{heartbeatTable: "heartbeat"}))
+	applier.heartbeatApplied = false
+	applier.firstUncommittedTransactionTime = time.Now()
+	require.False(t, applier.shouldCommit(replicaCommitSettings{interval: time.Minute}))
+	applier.firstUncommittedTransactionTime = time.Now().Add(-time.Minute)
+	require.True(t, applier.shouldCommit(replicaCommitSettings{interval: time.Minute}))
+}
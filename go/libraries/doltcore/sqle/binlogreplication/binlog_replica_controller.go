@@ -17,9 +17,9 @@ package binlogreplication
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/sirupsen/logrus"
 
@@ -52,18 +52,22 @@ var ErrEmptyUsername = fmt.Errorf("fatal error: Invalid (empty) username when at
 var ErrReplicationStopped = fmt.Errorf("replication stop requested")
 
 // doltBinlogReplicaController implements the BinlogReplicaController interface for a Dolt database in order to
-// provide support for a Dolt server to be a replica of a MySQL primary.
+// provide support for a Dolt server to be a replica of a MySQL primary. The controller supports several replication
+// channels, each replicating from its own source server. Replication statements act on the default channel ("")
+// unless they name a channel with a FOR CHANNEL clause, which is recognized by the parser returned from
+// NewReplicaChannelParser.
 //
 // This type is used concurrently – multiple sessions on the DB can call this interface concurrently,
 // so all state that the controller tracks MUST be protected with a mutex.
 type doltBinlogReplicaController struct {
-	status  binlogreplication.ReplicaStatus
-	filters *filterConfiguration
-	applier *binlogReplicaApplier
-	ctx     *sql.Context
+	channels map[string]*replicaChannel
+	ctx      *sql.Context
 
-	// statusMutex blocks concurrent access to the ReplicaStatus struct
-	statusMutex *sync.Mutex
+	// newExecutionContext creates the execution contexts for the appliers of named replication channels
+	newExecutionContext func() (*sql.Context, error)
+
+	// channelsMutex blocks concurrent access to the channels map
+	channelsMutex *sync.Mutex
 
 	// operationMutex blocks concurrent access to the START/STOP/RESET REPLICA operations
 	operationMutex *sync.Mutex
@@ -75,28 +79,30 @@ var _ binlogreplication.BinlogReplicaController = (*doltBinlogReplicaController)
 // newDoltBinlogReplicaController creates a new doltBinlogReplicaController instance.
 func newDoltBinlogReplicaController() *doltBinlogReplicaController {
 	controller := doltBinlogReplicaController{
-		filters:        newFilterConfiguration(),
-		statusMutex:    &sync.Mutex{},
+		channels:       make(map[string]*replicaChannel),
+		channelsMutex:  &sync.Mutex{},
 		operationMutex: &sync.Mutex{},
 	}
-	controller.status.ConnectRetry = 60
-	controller.status.SourceRetryCount = 86400
-	controller.status.AutoPosition = true
-	controller.status.ReplicaIoRunning = binlogreplication.ReplicaIoNotRunning
-	controller.status.ReplicaSqlRunning = binlogreplication.ReplicaSqlNotRunning
-	controller.applier = newBinlogReplicaApplier(controller.filters)
+	controller.channels[defaultChannel] = newReplicaChannel(defaultChannel)
 	return &controller
 }
 
 // StartReplica implements the BinlogReplicaController interface.
 func (d *doltBinlogReplicaController) StartReplica(ctx *sql.Context) error {
+	return d.startReplica(ctx, statementChannel(ctx))
+}
+
+// startReplica starts replication for the replication channel named |channelName|.
+func (d *doltBinlogReplicaController) startReplica(ctx *sql.Context, channelName string) error {
 	d.operationMutex.Lock()
 	defer d.operationMutex.Unlock()
 
+	channel := d.channel(channelName)
+
 	// START REPLICA may be called multiple times, but if replication is already running,
 	// it will log a warning and not start up new threads.
-	if d.applier.IsRunning() {
-		ctx.Warn(3083, "Replication thread(s) for channel '' are already running.")
+	if channel.applier.IsRunning() {
+		ctx.Warn(3083, "Replication thread(s) for channel '%s' are already running.", channelName)
 		return nil
 	}
 
@@ -116,21 +122,24 @@ func (d *doltBinlogReplicaController) StartReplica(ctx *sql.Context) error {
 		return fmt.Errorf("unable to start replication: %s", err.Error())
 	}
 
-	configuration, err := loadReplicationConfiguration(ctx, d.engine.Analyzer.Catalog.MySQLDb)
+	configuration, err := loadReplicationConfiguration(ctx, d.engine.Analyzer.Catalog.MySQLDb, channelName)
 	if err != nil {
 		return err
+	} else if configuration == nil && channelName != defaultChannel {
+		return errReplicaChannelDoesNotExist(channelName)
 	} else if configuration == nil {
 		return ErrServerNotConfiguredAsReplica
 	} else if configuration.Host == "" {
-		DoltBinlogReplicaController.setIoError(ERFatalReplicaError, ErrEmptyHostname.Error())
+		channel.setIoError(ERFatalReplicaError, ErrEmptyHostname.Error())
 		return ErrEmptyHostname
 	} else if configuration.User == "" {
-		DoltBinlogReplicaController.setIoError(ERFatalReplicaError, ErrEmptyUsername.Error())
+		channel.setIoError(ERFatalReplicaError, ErrEmptyUsername.Error())
 		return ErrEmptyUsername
 	}
 
-	if d.ctx == nil {
-		return fmt.Errorf("no execution context set for the replica controller")
+	executionCtx, err := d.executionContext(channelName)
+	if err != nil {
+		return err
 	}
 
 	err = d.configureReplicationUser(ctx)
@@ -139,23 +148,54 @@ func (d *doltBinlogReplicaController) StartReplica(ctx *sql.Context) error {
 	}
 
 	// Set execution context's user to the binlog replication user
-	d.ctx.SetClient(sql.Client{
+	executionCtx.SetClient(sql.Client{
 		User:    binlogApplierUser,
 		Address: "localhost",
 	})
 
-	ctx.GetLogger().Info("starting binlog replication...")
-	d.applier.Go(d.ctx)
+	if channelName == defaultChannel {
+		ctx.GetLogger().Info("starting binlog replication...")
+	} else {
+		ctx.GetLogger().Infof("starting binlog replication for channel '%s'...", channelName)
+	}
+	channel.applier.Go(executionCtx)
 
 	// Attempt to record that the replica has started replication so that it will
 	// start automatically the next time the replica server is started.
-	if err := persistReplicaRunningState(ctx, running); err != nil {
+	if err := persistReplicaRunningState(ctx, channelName, running); err != nil {
 		ctx.GetLogger().Errorf("unable to persist replica running state: %s", err.Error())
 	}
 
 	return nil
 }
 
+// executionContext returns the execution context for the applier of the replication channel named |channelName|.
+// The default channel uses the context set with SetExecutionContext, and named channels each use a new context from
+// the factory set with SetExecutionContextFactory, so that their appliers can run concurrently.
+func (d *doltBinlogReplicaController) executionContext(channelName string) (*sql.Context, error) {
+	if d.ctx == nil {
+		return nil, fmt.Errorf("no execution context set for the replica controller")
+	}
+	if channelName == defaultChannel {
+		return d.ctx, nil
+	}
+
+	channel := d.channel(channelName)
+	if channel.ctx != nil {
+		return channel.ctx, nil
+	}
+	if d.newExecutionContext == nil {
+		return nil, fmt.Errorf("no execution context factory set for the replica controller")
+	}
+
+	executionCtx, err := d.newExecutionContext()
+	if err != nil {
+		return nil, err
+	}
+	channel.ctx = executionCtx
+	return executionCtx, nil
+}
+
 // configureReplicationUser creates or configures the super user account needed to apply replication
 // changes and execute DDL statements on the running server. If the account doesn't exist, it will be
 // created and locked to disable log ins, and if it does exist, but is missing super privs or is not
@@ -193,30 +233,48 @@ func (d *doltBinlogReplicaController) SetExecutionContext(ctx *sql.Context) {
 	d.ctx = ctx
 }
 
+// SetExecutionContextFactory sets the function used to create a new, unique context for the applier of each named
+// replication channel. Like the context set with SetExecutionContext, these contexts cannot be shared with any other
+// routine.
+func (d *doltBinlogReplicaController) SetExecutionContextFactory(factory func() (*sql.Context, error)) {
+	d.newExecutionContext = factory
+}
+
 // SetEngine sets the SQL engine this replica will use when running replicated statements and
 // when loading the Catalog to find the "mysql" database.
 func (d *doltBinlogReplicaController) SetEngine(engine *sqle.Engine) {
+	d.channelsMutex.Lock()
+	defer d.channelsMutex.Unlock()
+
 	d.engine = engine
-	d.applier.engine = engine
+	for _, channel := range d.channels {
+		channel.applier.engine = engine
+	}
 }
 
 // StopReplica implements the BinlogReplicaController interface.
 func (d *doltBinlogReplicaController) StopReplica(ctx *sql.Context) error {
-	if d.applier.IsRunning() == false {
-		ctx.Warn(3084, "Replication thread(s) for channel '' are already stopped.")
+	channelName := statementChannel(ctx)
+	channel, err := d.configuredChannel(ctx, channelName)
+	if err != nil {
+		return err
+	}
+
+	if channel.applier.IsRunning() == false {
+		ctx.Warn(3084, "Replication thread(s) for channel '%s' are already stopped.", channelName)
 		return nil
 	}
 
-	d.applier.stopReplicationChan <- struct{}{}
+	channel.applier.stopReplicationChan <- struct{}{}
 
-	d.updateStatus(func(status *binlogreplication.ReplicaStatus) {
+	channel.updateStatus(func(status *binlogreplication.ReplicaStatus) {
 		status.ReplicaIoRunning = binlogreplication.ReplicaIoNotRunning
 		status.ReplicaSqlRunning = binlogreplication.ReplicaSqlNotRunning
 	})
 
 	// Attempt to record that the replica has stopped replication so that it will not
 	// start automatically the next time the replica server is started.
-	if err := persistReplicaRunningState(ctx, notRunning); err != nil {
+	if err := persistReplicaRunningState(ctx, channelName, notRunning); err != nil {
 		ctx.GetLogger().Errorf("unable to persist replica running state: %s", err.Error())
 	}
 
@@ -225,7 +283,8 @@ func (d *doltBinlogReplicaController) StopReplica(ctx *sql.Context) error {
 
 // SetReplicationSourceOptions implements the BinlogReplicaController interface.
 func (d *doltBinlogReplicaController) SetReplicationSourceOptions(ctx *sql.Context, options []binlogreplication.ReplicationOption) error {
-	replicaSourceInfo, err := loadReplicationConfiguration(ctx, d.engine.Analyzer.Catalog.MySQLDb)
+	channelName := statementChannel(ctx)
+	replicaSourceInfo, err := loadReplicationConfiguration(ctx, d.engine.Analyzer.Catalog.MySQLDb, channelName)
 	if err != nil {
 		return err
 	}
@@ -286,11 +345,16 @@ func (d *doltBinlogReplicaController) SetReplicationSourceOptions(ctx *sql.Conte
 	}
 
 	// Persist the updated replica source configuration to disk
-	return persistReplicationConfiguration(ctx, replicaSourceInfo, d.engine.Analyzer.Catalog.MySQLDb)
+	return persistReplicationConfiguration(ctx, channelName, replicaSourceInfo, d.engine.Analyzer.Catalog.MySQLDb)
 }
 
 // SetReplicationFilterOptions implements the BinlogReplicaController interface.
-func (d *doltBinlogReplicaController) SetReplicationFilterOptions(_ *sql.Context, options []binlogreplication.ReplicationOption) error {
+func (d *doltBinlogReplicaController) SetReplicationFilterOptions(ctx *sql.Context, options []binlogreplication.ReplicationOption) error {
+	channel, err := d.configuredChannel(ctx, statementChannel(ctx))
+	if err != nil {
+		return err
+	}
+
 	for _, option := range options {
 		switch strings.ToUpper(option.Name) {
 		case "REPLICATE_DO_TABLE":
@@ -298,7 +362,7 @@ func (d *doltBinlogReplicaController) SetReplicationFilterOptions(_ *sql.Context
 			if err != nil {
 				return err
 			}
-			err = d.filters.setDoTables(value)
+			err = channel.filters.setDoTables(value)
 			if err != nil {
 				return err
 			}
@@ -307,7 +371,7 @@ func (d *doltBinlogReplicaController) SetReplicationFilterOptions(_ *sql.Context
 			if err != nil {
 				return err
 			}
-			err = d.filters.setIgnoreTables(value)
+			err = channel.filters.setIgnoreTables(value)
 			if err != nil {
 				return err
 			}
@@ -327,15 +391,20 @@ func (d *doltBinlogReplicaController) SetReplicationFilterOptions(_ *sql.Context
 
 // GetReplicaStatus implements the BinlogReplicaController interface
 func (d *doltBinlogReplicaController) GetReplicaStatus(ctx *sql.Context) (*binlogreplication.ReplicaStatus, error) {
-	replicaSourceInfo, err := loadReplicationConfiguration(ctx, d.engine.Analyzer.Catalog.MySQLDb)
+	channelName := statementChannel(ctx)
+	replicaSourceInfo, err := loadReplicationConfiguration(ctx, d.engine.Analyzer.Catalog.MySQLDb, channelName)
 	if err != nil {
 		return nil, err
+	} else if replicaSourceInfo == nil && channelName != defaultChannel {
+		return nil, errReplicaChannelDoesNotExist(channelName)
 	}
 
+	channel := d.channel(channelName)
+
 	// Lock to read status consistently
-	d.statusMutex.Lock()
-	defer d.statusMutex.Unlock()
-	var copy = d.status
+	channel.statusMutex.Lock()
+	defer channel.statusMutex.Unlock()
+	var copy = channel.status
 
 	if replicaSourceInfo == nil {
 		return &copy, nil
@@ -347,11 +416,11 @@ func (d *doltBinlogReplicaController) GetReplicaStatus(ctx *sql.Context) (*binlo
 	copy.SourceServerUuid = replicaSourceInfo.Uuid
 	copy.ConnectRetry = replicaSourceInfo.ConnectRetryInterval
 	copy.SourceRetryCount = replicaSourceInfo.ConnectRetryCount
-	copy.ReplicateDoTables = d.filters.getDoTables()
-	copy.ReplicateIgnoreTables = d.filters.getIgnoreTables()
+	copy.ReplicateDoTables = channel.filters.getDoTables()
+	copy.ReplicateIgnoreTables = channel.filters.getIgnoreTables()
 
-	if d.applier.currentPosition != nil {
-		copy.ExecutedGtidSet = d.applier.currentPosition.GTIDSet.String()
+	if channel.applier.currentPosition != nil {
+		copy.ExecutedGtidSet = channel.applier.currentPosition.GTIDSet.String()
 		copy.RetrievedGtidSet = copy.ExecutedGtidSet
 	}
 
@@ -363,12 +432,18 @@ func (d *doltBinlogReplicaController) ResetReplica(ctx *sql.Context, resetAll bo
 	d.operationMutex.Lock()
 	defer d.operationMutex.Unlock()
 
-	if d.applier.IsRunning() {
+	channelName := statementChannel(ctx)
+	channel, err := d.configuredChannel(ctx, channelName)
+	if err != nil {
+		return err
+	}
+
+	if channel.applier.IsRunning() {
 		return fmt.Errorf("unable to reset replica while replication is running; stop replication and try again")
 	}
 
 	// Reset error status
-	d.updateStatus(func(status *binlogreplication.ReplicaStatus) {
+	channel.updateStatus(func(status *binlogreplication.ReplicaStatus) {
 		status.LastIoErrNumber = 0
 		status.LastSqlErrNumber = 0
 		status.LastIoErrorTimestamp = nil
@@ -378,76 +453,106 @@ func (d *doltBinlogReplicaController) ResetReplica(ctx *sql.Context, resetAll bo
 	})
 
 	if resetAll {
-		err := deleteReplicationConfiguration(ctx, d.engine.Analyzer.Catalog.MySQLDb)
+		err := deleteReplicationConfiguration(ctx, d.engine.Analyzer.Catalog.MySQLDb, channelName)
 		if err != nil {
 			return err
 		}
 
-		d.filters = newFilterConfiguration()
+		// Clear the filters in place, since the channel's applier shares them
+		if err = channel.filters.setDoTables(nil); err != nil {
+			return err
+		}
+		if err = channel.filters.setIgnoreTables(nil); err != nil {
+			return err
+		}
+
+		// RESET REPLICA ALL removes a named channel entirely
+		if channelName != defaultChannel {
+			d.channelsMutex.Lock()
+			delete(d.channels, channelName)
+			d.channelsMutex.Unlock()
+		}
 	}
 
 	return nil
 }
 
-// updateStatus allows the caller to safely update the replica controller's status. The controller locks it's mutex
-// before the specified function |f| is called, and unlocks it after |f| is finished running. The current status is
-// passed into the callback function |f| and the caller can safely update or copy any fields they need.
-func (d *doltBinlogReplicaController) updateStatus(f func(status *binlogreplication.ReplicaStatus)) {
-	d.statusMutex.Lock()
-	defer d.statusMutex.Unlock()
-	f(&d.status)
+// channel returns the replicaChannel for the replication channel named |channelName|, creating it if it does not
+// exist yet.
+func (d *doltBinlogReplicaController) channel(channelName string) *replicaChannel {
+	d.channelsMutex.Lock()
+	defer d.channelsMutex.Unlock()
+
+	channel, ok := d.channels[channelName]
+	if !ok {
+		channel = newReplicaChannel(channelName)
+		channel.applier.engine = d.engine
+		d.channels[channelName] = channel
+	}
+	return channel
 }
 
-// setIoError updates the current replication status with the specific |errno| and |message| to describe an IO error.
-func (d *doltBinlogReplicaController) setIoError(errno uint, message string) {
-	d.statusMutex.Lock()
-	defer d.statusMutex.Unlock()
-
-	// truncate the message to avoid errors when reporting replica status
-	if len(message) > 256 {
-		message = message[:256]
+// configuredChannel returns the replicaChannel for the replication channel named |channelName|. Named channels must
+// have been configured with CHANGE REPLICATION SOURCE TO, otherwise an error is returned.
+func (d *doltBinlogReplicaController) configuredChannel(ctx *sql.Context, channelName string) (*replicaChannel, error) {
+	if channelName != defaultChannel {
+		replicaSourceInfo, err := loadReplicationConfiguration(ctx, d.engine.Analyzer.Catalog.MySQLDb, channelName)
+		if err != nil {
+			return nil, err
+		} else if replicaSourceInfo == nil {
+			return nil, errReplicaChannelDoesNotExist(channelName)
+		}
 	}
-
-	currentTime := time.Now()
-	d.status.LastIoErrorTimestamp = &currentTime
-	d.status.LastIoErrNumber = errno
-	d.status.LastIoError = message
+	return d.channel(channelName), nil
 }
 
-// setSqlError updates the current replication status with the specific |errno| and |message| to describe an SQL error.
-func (d *doltBinlogReplicaController) setSqlError(errno uint, message string) {
-	d.statusMutex.Lock()
-	defer d.statusMutex.Unlock()
-
-	// truncate the message to avoid errors when reporting replica status
-	if len(message) > 256 {
-		message = message[:256]
+// executedGtidSet returns the set of GTIDs that have been executed across all replication channels, for use as the
+// value of @@gtid_executed. Each channel replicates from a different source server, so the GTIDs of each channel
+// have a different source UUID and the channels' sets can simply be joined.
+func (d *doltBinlogReplicaController) executedGtidSet() string {
+	d.channelsMutex.Lock()
+	defer d.channelsMutex.Unlock()
+
+	channelNames := keys(d.channels)
+	sort.Strings(channelNames)
+
+	var gtidSets []string
+	for _, channelName := range channelNames {
+		position := d.channels[channelName].applier.currentPosition
+		if position != nil && position.GTIDSet != nil && position.GTIDSet.String() != "" {
+			gtidSets = append(gtidSets, position.GTIDSet.String())
+		}
 	}
-
-	currentTime := time.Now()
-	d.status.LastSqlErrorTimestamp = &currentTime
-	d.status.LastSqlErrNumber = errno
-	d.status.LastSqlError = message
+	return strings.Join(gtidSets, ",")
 }
 
-// AutoStart starts up replication if replication was running before the server was shutdown. If
-// replication is not configured, hasn't been started, or has been stopped before the server was
-// shutdown, then this method will not start replication. This method should only be called during
-// the server startup process and should not be invoked after that.
+// AutoStart starts up replication for each replication channel that was running before the server was shutdown. If
+// replication is not configured, hasn't been started, or has been stopped before the server was shutdown, then this
+// method will not start replication. This method should only be called during the server startup process and should
+// not be invoked after that.
 func (d *doltBinlogReplicaController) AutoStart(_ context.Context) error {
-	runningState, err := loadReplicationRunningState(d.ctx)
+	channelNames, err := loadRunningReplicationChannels(d.ctx)
 	if err != nil {
 		logrus.Errorf("Unable to load replication running state: %s", err.Error())
 		return err
 	}
 
-	if runningState == notRunning {
+	if len(channelNames) == 0 {
 		logrus.Trace("no previous replication running state; not auto starting replication")
 		return nil
 	}
 
-	logrus.Info("auto-starting binlog replication from source...")
-	return d.StartReplica(d.ctx)
+	for _, channelName := range channelNames {
+		if channelName == defaultChannel {
+			logrus.Info("auto-starting binlog replication from source...")
+		} else {
+			logrus.Infof("auto-starting binlog replication from source for channel '%s'...", channelName)
+		}
+		if err := d.startReplica(d.ctx, channelName); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 //
@@ -0,0 +1,114 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binlogreplication
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql/binlogreplication"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBinlogReplicationNamedChannel tests that a replica can replicate from a source configured for a named
+// replication channel, and that the FOR CHANNEL clause directs replication statements to that channel.
+func TestBinlogReplicationNamedChannel(t *testing.T) {
+	defer teardown(t)
+	startSqlServersWithDoltSystemVars(t, doltReplicaSystemVars)
+
+	// Replication statements for a channel that hasn't been configured return an error
+	_, err := replicaDatabase.Queryx("START REPLICA FOR CHANNEL 'shard1';")
+	require.ErrorContains(t, err, "Replica channel 'shard1' does not exist.")
+	_, err = replicaDatabase.Queryx("SHOW REPLICA STATUS FOR CHANNEL 'shard1';")
+	require.ErrorContains(t, err, "Replica channel 'shard1' does not exist.")
+
+	replicaDatabase.MustExec(fmt.Sprintf("CHANGE REPLICATION SOURCE TO SOURCE_HOST='localhost', "+
+		"SOURCE_USER='replicator', SOURCE_PASSWORD='Zqr8_blrGm1!', "+
+		"SOURCE_PORT=%v, SOURCE_AUTO_POSITION=1, SOURCE_CONNECT_RETRY=5 FOR CHANNEL 'shard1';", mySqlPort))
+	replicaDatabase.MustExec("START REPLICA FOR CHANNEL 'shard1';")
+	require.FileExists(t, filepath.Join(testDir, "dolt", ".doltcfg", "replica-running-shard1"))
+
+	primaryDatabase.MustExec("create database db01;")
+	primaryDatabase.MustExec("create table db01.t (pk int primary key);")
+	primaryDatabase.MustExec("insert into db01.t values (1), (2);")
+	waitForReplicaToCatchUp(t)
+	requireReplicaResults(t, "select * from db01.t order by pk;", [][]any{{"1"}, {"2"}})
+
+	// The named channel reports its own status, and the default channel is left unconfigured
+	status := queryReplicaStatusForChannel(t, "shard1")
+	require.Equal(t, "localhost", status["Source_Host"])
+	require.Equal(t, "replicator", status["Source_User"])
+	require.True(t, status["Replica_IO_Running"] == binlogreplication.ReplicaIoRunning ||
+		status["Replica_IO_Running"] == binlogreplication.ReplicaIoConnecting)
+	status = queryReplicaStatus(t)
+	require.Equal(t, "", status["Source_Host"])
+	require.Equal(t, "No", status["Replica_IO_Running"])
+
+	// SHOW REPLICA STATUS without a channel returns a row for each channel
+	rows, err := replicaDatabase.Queryx("SHOW REPLICA STATUS;")
+	require.NoError(t, err)
+	var channelNames []string
+	for rows.Next() {
+		row := make(map[string]any)
+		require.NoError(t, rows.MapScan(row))
+		channelNames = append(channelNames, convertMapScanResultToStrings(row)["Channel_Name"].(string))
+	}
+	require.NoError(t, rows.Close())
+	require.Equal(t, []string{"", "shard1"}, channelNames)
+
+	// Prepared statements keep their channel for each execution
+	stmt, err := replicaDatabase.Preparex("SHOW REPLICA STATUS FOR CHANNEL 'shard1';")
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		replicaDatabase.MustExec("STOP REPLICA;")
+		row := make(map[string]any)
+		require.NoError(t, stmt.QueryRowx().MapScan(row))
+		require.Equal(t, "shard1", convertMapScanResultToStrings(row)["Channel_Name"])
+		require.Equal(t, "localhost", convertMapScanResultToStrings(row)["Source_Host"])
+	}
+	require.NoError(t, stmt.Close())
+
+	// Filters are configured per channel
+	replicaDatabase.MustExec("CHANGE REPLICATION FILTER REPLICATE_IGNORE_TABLE=(db01.t) FOR CHANNEL 'shard1';")
+	status = queryReplicaStatusForChannel(t, "shard1")
+	require.Equal(t, "db01.t", status["Replicate_Ignore_Table"])
+	status = queryReplicaStatus(t)
+	require.Equal(t, "", status["Replicate_Ignore_Table"])
+
+	// Stopping the named channel doesn't touch the default channel
+	replicaDatabase.MustExec("STOP REPLICA FOR CHANNEL 'shard1';")
+	status = queryReplicaStatusForChannel(t, "shard1")
+	require.Equal(t, "No", status["Replica_IO_Running"])
+	require.NoFileExists(t, filepath.Join(testDir, "dolt", ".doltcfg", "replica-running-shard1"))
+	replicaDatabase.MustExec("STOP REPLICA;")
+	assertWarning(t, replicaDatabase, 3084, "Replication thread(s) for channel '' are already stopped.")
+
+	// RESET REPLICA ALL removes the named channel
+	replicaDatabase.MustExec("RESET REPLICA ALL FOR CHANNEL 'shard1';")
+	_, err = replicaDatabase.Queryx("SHOW REPLICA STATUS FOR CHANNEL 'shard1';")
+	require.ErrorContains(t, err, "Replica channel 'shard1' does not exist.")
+}
+
+// queryReplicaStatusForChannel returns the results of `SHOW REPLICA STATUS FOR CHANNEL` as a map, for the
+// replication channel named |channel| on the replica database. If any errors are encountered, this function will
+// fail the current test.
+func queryReplicaStatusForChannel(t *testing.T, channel string) map[string]any {
+	rows, err := replicaDatabase.Queryx(fmt.Sprintf("SHOW REPLICA STATUS FOR CHANNEL '%s';", channel))
+	require.NoError(t, err)
+	status := convertMapScanResultToStrings(readNextRow(t, rows))
+	require.NoError(t, rows.Close())
+	return status
+}
@@ -0,0 +1,314 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package branchprotection
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	goerrors "gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/dolt_ci"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// ErrProtectedBranchPush is returned when a push updates a protected branch to a commit which may not be merged into it.
+var ErrProtectedBranchPush = goerrors.NewKind("push to protected branch `%s` rejected: %s")
+
+// CheckMerge returns an error if |source|, the head of |sourceBranch|, may not be merged into the protected branch
+// |targetBranch| of |db|. The head must be approved by at least as many users as |rule| requires, not counting the
+// authors of any of the merged commits, and the workflow required by |rule| must pass against it. The workflow
+// definition is read from the current database, which is expected to be |targetBranch|.
+func CheckMerge(ctx *sql.Context, db dsess.SqlDatabase, rule branch_control.ProtectionValue, targetBranch string, sourceBranch string, source *doltdb.Commit) error {
+	h, err := source.HashOf()
+	if err != nil {
+		return err
+	}
+	target, err := db.DbData().Ddb.ResolveCommitRef(ctx, ref.NewBranchRef(targetBranch))
+	if err != nil {
+		return err
+	}
+	targetHash, err := target.HashOf()
+	if err != nil {
+		return err
+	}
+
+	if err = ForgetSettledAuthors(ctx, db); err != nil {
+		return err
+	}
+	dbName, _ := dsess.SplitRevisionDbName(db.Name())
+	approvers, unrecorded, err := approversOf(ctx, db, h, targetHash, func(approvals *branch_control.Approvals) []string {
+		return approvals.Approvers(dbName, sourceBranch, h.String())
+	})
+	if err != nil {
+		return err
+	}
+	if uint32(len(approvers)) < rule.RequiredApprovals {
+		return branch_control.ErrProtectedBranchMerge.New(sourceBranch, targetBranch, approvalsMessage(h, approvers, unrecorded, rule))
+	}
+
+	run, err := RunRequiredWorkflow(ctx, db, rule, targetBranch, dolt_ci.WorkflowRunEventMerge, h)
+	if err != nil {
+		return err
+	}
+	if run != nil && run.Status != dolt_ci.WorkflowRunStatusPassed {
+		return branch_control.ErrProtectedBranchMerge.New(sourceBranch, targetBranch, workflowMessage(run))
+	}
+	return nil
+}
+
+// CheckPush returns an error if |newHead| may not be pushed to the protected branch |branch| of |db|, replacing
+// |oldHead|, which is the empty hash if the push creates the branch. As a push cannot be a merge, the pushed head must
+// itself have been approved on some branch of the database by as many users as |rule| requires, not counting the
+// authors of any of the pushed commits, and the workflow required by |rule| must pass against it. The workflow
+// definition is read from the current head of |branch|.
+func CheckPush(ctx *sql.Context, db dsess.SqlDatabase, rule branch_control.ProtectionValue, branch string, oldHead hash.Hash, newHead hash.Hash) error {
+	dbName, _ := dsess.SplitRevisionDbName(db.Name())
+	approvers, unrecorded, err := approversOf(ctx, db, newHead, oldHead, func(approvals *branch_control.Approvals) []string {
+		return approvals.CommitApprovers(dbName, newHead.String())
+	})
+	if err != nil {
+		return err
+	}
+	if uint32(len(approvers)) < rule.RequiredApprovals {
+		return ErrProtectedBranchPush.New(branch, approvalsMessage(newHead, approvers, unrecorded, rule))
+	}
+
+	run, err := RunRequiredWorkflow(ctx, db, rule, branch, dolt_ci.WorkflowRunEventPush, newHead)
+	if err != nil {
+		return err
+	}
+	if run != nil && run.Status != dolt_ci.WorkflowRunStatusPassed {
+		return ErrProtectedBranchPush.New(branch, workflowMessage(run))
+	}
+	return nil
+}
+
+// RunRequiredWorkflow runs the workflow required by |rule| against |head|, and records the run in the
+// dolt_ci.WorkflowRunHistory of |db|. The workflow definition is read from |branch| of |db|. Returns a nil run if |rule| does not
+// require a workflow.
+func RunRequiredWorkflow(ctx *sql.Context, db dsess.SqlDatabase, rule branch_control.ProtectionValue, branch string, event dolt_ci.WorkflowRunEvent, head hash.Hash) (*dolt_ci.WorkflowRun, error) {
+	if rule.RequiredWorkflow == "" {
+		return nil, nil
+	}
+
+	dbName, _ := dsess.SplitRevisionDbName(db.Name())
+	current := ctx.GetCurrentDatabase()
+	ctx.SetCurrentDatabase(dsess.RevisionDbName(dbName, branch))
+	defer ctx.SetCurrentDatabase(current)
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	wm := dolt_ci.NewWorkflowManager(dSess.Username(), dSess.Email(), dsess.RunQuery)
+	history := dolt_ci.NewWorkflowRunHistory(db.DbData().Ddb)
+
+	run := dolt_ci.NewWorkflowRun(rule.RequiredWorkflow, event, branch, head.String())
+	if err := history.Add(ctx, run); err != nil {
+		return nil, err
+	}
+	res, err := wm.RunWorkflow(ctx, db, rule.RequiredWorkflow, head.String())
+	run.Finish(res, err)
+	if err = history.Update(ctx, run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// RecordPushedCommits records the user of |ctx| as the author of the commits which a push to |db| adds, which are
+// those reachable from |heads| but not from any branch of |db|. The author named within a pushed commit may be set to
+// anyone, so the user that pushed the commit is the only author that can be trusted.
+func RecordPushedCommits(ctx *sql.Context, db dsess.SqlDatabase, heads []hash.Hash) error {
+	if !branch_control.RecordsCommitAuthors(ctx) {
+		return nil
+	}
+	ddb := db.DbData().Ddb
+	branches, err := ddb.GetBranchesWithHashes(ctx)
+	if err != nil {
+		return err
+	}
+	excluded := make([]hash.Hash, len(branches))
+	for i, branch := range branches {
+		excluded[i] = branch.Hash
+	}
+	itr, err := commitwalk.GetDotDotRevisionsIterator(ctx, ddb, heads, ddb, excluded, nil)
+	if err != nil {
+		return err
+	}
+	for {
+		h, _, err := itr.Next(ctx)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err = branch_control.RecordCommitAuthor(ctx, h.String()); err != nil {
+			return err
+		}
+	}
+}
+
+// approversOf returns the approvers selected by |approvers| from the branch controller of |ctx|, excluding the authors
+// of every commit reachable from |head| but not from |base|, as users may not approve their own changes. |base| is the
+// empty hash when all commits reachable from |head| are being added. Commits without a recorded author, such as those
+// created by local CLI commands, are treated as authored by every user, so no approvals are returned when any exist.
+// Their number is returned along with the approvers.
+func approversOf(ctx *sql.Context, db dsess.SqlDatabase, head hash.Hash, base hash.Hash, approvers func(*branch_control.Approvals) []string) ([]string, int, error) {
+	branchAwareSession := branch_control.GetBranchAwareSession(ctx)
+	if branchAwareSession == nil {
+		return nil, 0, nil
+	}
+	controller := branchAwareSession.GetController()
+	if controller == nil {
+		return nil, 0, branch_control.ErrMissingController.New()
+	}
+	commits, err := commitRange(ctx, db.DbData().Ddb, head, base)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	authors := make(map[string]struct{}, len(commits))
+	unrecorded := 0
+	controller.Access.RWMutex.RLock()
+	all := approvers(controller.Approvals)
+	for _, h := range commits {
+		if author, ok := controller.Approvals.Author(h.String()); ok {
+			authors[author.User] = struct{}{}
+		} else {
+			unrecorded++
+		}
+	}
+	controller.Access.RWMutex.RUnlock()
+	if unrecorded > 0 {
+		return nil, unrecorded, nil
+	}
+
+	var ret []string
+	for _, approver := range all {
+		if _, ok := authors[approver]; !ok {
+			ret = append(ret, approver)
+		}
+	}
+	return ret, 0, nil
+}
+
+// ForgetSettledAuthors forgets the recorded authors of the commits of |db| which no longer need them: those merged into
+// a protected branch, which approvals no longer apply to, and those no longer reachable from any branch. Commits that
+// do not exist in |db| may belong to another database, so their authors are kept. As commits being pushed are not yet
+// reachable, this must not run while a push is checked.
+func ForgetSettledAuthors(ctx *sql.Context, db dsess.SqlDatabase) error {
+	branchAwareSession := branch_control.GetBranchAwareSession(ctx)
+	if branchAwareSession == nil {
+		return nil
+	}
+	controller := branchAwareSession.GetController()
+	if controller == nil {
+		return branch_control.ErrMissingController.New()
+	}
+	controller.Access.RWMutex.RLock()
+	recorded := controller.Approvals.AuthorHashes()
+	controller.Access.RWMutex.RUnlock()
+	if len(recorded) == 0 {
+		return nil
+	}
+
+	ddb := db.DbData().Ddb
+	branches, err := ddb.GetBranchesWithHashes(ctx)
+	if err != nil {
+		return err
+	}
+	dbName, _ := dsess.SplitRevisionDbName(db.Name())
+	var heads, protectedHeads []hash.Hash
+	for _, branch := range branches {
+		heads = append(heads, branch.Hash)
+		if _, ok := branch_control.GetProtection(ctx, dbName, branch.Ref.GetPath()); ok {
+			protectedHeads = append(protectedHeads, branch.Hash)
+		}
+	}
+
+	var settled []string
+	for _, commitHash := range recorded {
+		addr, ok := hash.MaybeParse(commitHash)
+		if !ok {
+			settled = append(settled, commitHash)
+			continue
+		}
+		merged, err := ddb.IsCommitReachable(ctx, addr, protectedHeads)
+		if errors.Is(err, datas.ErrCommitNotFound) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		reachable := true
+		if !merged {
+			if reachable, err = ddb.IsCommitReachable(ctx, addr, heads); err != nil {
+				return err
+			}
+		}
+		if merged || !reachable {
+			settled = append(settled, commitHash)
+		}
+	}
+	if len(settled) == 0 {
+		return nil
+	}
+	controller.Access.RWMutex.Lock()
+	controller.Approvals.ForgetAuthors(settled)
+	controller.Access.RWMutex.Unlock()
+	return nil
+}
+
+// commitRange returns the commits reachable from |head| but not from |base|, which may be the empty hash.
+func commitRange(ctx *sql.Context, ddb *doltdb.DoltDB, head hash.Hash, base hash.Hash) ([]hash.Hash, error) {
+	var excluded []hash.Hash
+	if !base.IsEmpty() {
+		excluded = append(excluded, base)
+	}
+	itr, err := commitwalk.GetDotDotRevisionsIterator(ctx, ddb, []hash.Hash{head}, ddb, excluded, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []hash.Hash
+	for {
+		h, optCmt, err := itr.Next(ctx)
+		if err == io.EOF {
+			return commits, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if _, ok := optCmt.ToCommit(); !ok {
+			return nil, doltdb.ErrGhostCommitEncountered
+		}
+		commits = append(commits, h)
+	}
+}
+
+func approvalsMessage(h hash.Hash, approvers []string, unrecorded int, rule branch_control.ProtectionValue) string {
+	if unrecorded > 0 {
+		return fmt.Sprintf("commit %s has none of the %d required approvals, as %d of its commits have no recorded author", h.String(), rule.RequiredApprovals, unrecorded)
+	}
+	return fmt.Sprintf("commit %s has %d of the %d required approvals", h.String(), len(approvers), rule.RequiredApprovals)
+}
+
+func workflowMessage(run *dolt_ci.WorkflowRun) string {
+	return fmt.Sprintf("required workflow `%s` did not pass (%s: %s)", run.WorkflowName, run.Status, run.Message)
+}
@@ -0,0 +1,338 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package citrigger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/dolt_ci"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+const (
+	branchUpdateQueueSize = 1024
+	triggerThreadName     = "dolt_ci_workflow_trigger"
+)
+
+// branchUpdate is a new head of a branch, waiting for its workflows to be run.
+type branchUpdate struct {
+	dbName string
+	branch string
+	head   hash.Hash
+	// event is empty for updates made through sql-server, whose event depends on the new head being a merge commit.
+	event dolt_ci.WorkflowRunEvent
+}
+
+// Trigger runs the Dolt CI workflows of a database when one of its branches is updated, either by a commit or merge
+// made through sql-server, or by a client pushing to the remotesapi server. Each run is recorded in the
+// dolt_ci.WorkflowRunHistory of the database.
+//
+// Workflows can be required for a branch. Required workflows are run before a push to their branch is committed, and
+// the push is rejected if any of them fail.
+type Trigger struct {
+	ctxFactory func(context.Context) (*sql.Context, error)
+	required   map[string][]string
+	lgr        *logrus.Entry
+	updates    chan branchUpdate
+
+	mu        sync.Mutex
+	lastHeads map[string]hash.Hash
+}
+
+var _ sqle.RemoteSrvPushHook = (*Trigger)(nil)
+
+// NewTrigger returns a Trigger which runs workflows using sessions from |ctxFactory|. |required| maps branch names
+// to the workflows which must pass before a push to that branch is accepted.
+func NewTrigger(ctxFactory func(context.Context) (*sql.Context, error), required map[string][]string, lgr *logrus.Entry) *Trigger {
+	return &Trigger{
+		ctxFactory: ctxFactory,
+		required:   required,
+		lgr:        lgr,
+		updates:    make(chan branchUpdate, branchUpdateQueueSize),
+		lastHeads:  make(map[string]hash.Hash),
+	}
+}
+
+// Start installs the commit hooks of the Trigger on the databases of |pro|, including databases created later, and
+// starts running workflows for branch updates on a background thread.
+func (t *Trigger) Start(ctx context.Context, bThreads *sql.BackgroundThreads, pro *sqle.DoltDatabaseProvider) error {
+	for _, db := range pro.DoltDatabases() {
+		db.DbData().Ddb.PrependCommitHook(ctx, t.CommitHook(db.Name()))
+	}
+	pro.AddInitDatabaseHook(func(ctx *sql.Context, _ *sqle.DoltDatabaseProvider, name string, _ *env.DoltEnv, db dsess.SqlDatabase) error {
+		db.DbData().Ddb.PrependCommitHook(ctx, t.CommitHook(name))
+		return nil
+	})
+	return bThreads.Add(triggerThreadName, t.run)
+}
+
+func (t *Trigger) run(ctx context.Context) {
+	for {
+		select {
+		case u := <-t.updates:
+			t.handleUpdate(ctx, u)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// enqueue queues |u| to have its workflows run, unless workflows have already been run for the same head of the
+// branch, or the queue is full.
+func (t *Trigger) enqueue(u branchUpdate) {
+	key := strings.ToLower(u.dbName) + "/" + u.branch
+	t.mu.Lock()
+	if t.lastHeads[key] == u.head {
+		t.mu.Unlock()
+		return
+	}
+	t.lastHeads[key] = u.head
+	t.mu.Unlock()
+
+	select {
+	case t.updates <- u:
+	default:
+		t.lgr.Warnf("dolt ci: not running workflows for branch %s of database %s at %s: too many branch updates are queued", u.branch, u.dbName, u.head.String())
+	}
+}
+
+func (t *Trigger) handleUpdate(ctx context.Context, u branchUpdate) {
+	sqlCtx, err := t.ctxFactory(ctx)
+	if err != nil {
+		t.lgr.Errorf("dolt ci: error creating context to run workflows for branch %s of database %s: %v", u.branch, u.dbName, err)
+		return
+	}
+
+	var skip []string
+	if u.event == "" {
+		u.event, err = commitEvent(sqlCtx, u)
+		if err != nil {
+			t.lgr.Errorf("dolt ci: error reading commit %s of database %s: %v", u.head.String(), u.dbName, err)
+			return
+		}
+	} else if u.event == dolt_ci.WorkflowRunEventPush {
+		// required workflows were already run before the push was accepted
+		skip = t.required[u.branch]
+	}
+
+	_, err = t.runWorkflows(sqlCtx, u, u.branch, nil, skip)
+	if err != nil {
+		t.lgr.Errorf("dolt ci: error running workflows for branch %s of database %s: %v", u.branch, u.dbName, err)
+	}
+}
+
+// commitEvent returns whether the head of a branch update made through sql-server is a merge commit.
+func commitEvent(ctx *sql.Context, u branchUpdate) (dolt_ci.WorkflowRunEvent, error) {
+	db, err := sqlDatabase(ctx, u.dbName)
+	if err != nil {
+		return "", err
+	}
+	optCmt, err := db.DbData().Ddb.ReadCommit(ctx, u.head)
+	if err != nil {
+		return "", err
+	}
+	cm, ok := optCmt.ToCommit()
+	if !ok {
+		return "", doltdb.ErrGhostCommitEncountered
+	}
+	if cm.NumParents() > 1 {
+		return dolt_ci.WorkflowRunEventMerge, nil
+	}
+	return dolt_ci.WorkflowRunEventCommit, nil
+}
+
+func sqlDatabase(ctx *sql.Context, dbName string) (dsess.SqlDatabase, error) {
+	sqlDb, err := dsess.DSessFromSess(ctx.Session).Provider().Database(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	db, ok := sqlDb.(dsess.SqlDatabase)
+	if !ok {
+		return nil, fmt.Errorf("unexpected database type: %T", sqlDb)
+	}
+	return db, nil
+}
+
+// runWorkflows runs workflows against the head of |u| and records each run. Workflow definitions are read from
+// |definitionsRev| of the database, or from its default branch if |definitionsRev| is empty. If |names| is nil, every
+// workflow with a push event matching the branch of |u| is run. Workflows named in |skip| are not run.
+func (t *Trigger) runWorkflows(ctx *sql.Context, u branchUpdate, definitionsRev string, names []string, skip []string) ([]*dolt_ci.WorkflowRun, error) {
+	dbName := u.dbName
+	if definitionsRev != "" {
+		dbName = dsess.RevisionDbName(u.dbName, definitionsRev)
+	}
+	ctx.SetCurrentDatabase(dbName)
+
+	db, err := sqlDatabase(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	wm := dolt_ci.NewWorkflowManager(dSess.Username(), dSess.Email(), dsess.RunQuery)
+	history := dolt_ci.NewWorkflowRunHistory(db.DbData().Ddb)
+
+	if names == nil {
+		hasTables, err := dolt_ci.HasDoltCITables(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !hasTables {
+			return nil, nil
+		}
+		names, err = wm.ListPushWorkflowsForBranch(ctx, db, u.branch)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	skipped := make(map[string]struct{}, len(skip))
+	for _, name := range skip {
+		skipped[strings.ToLower(name)] = struct{}{}
+	}
+
+	var runs []*dolt_ci.WorkflowRun
+	for _, name := range names {
+		if _, ok := skipped[strings.ToLower(name)]; ok {
+			continue
+		}
+
+		run := dolt_ci.NewWorkflowRun(name, u.event, u.branch, u.head.String())
+		if err = history.Add(ctx, run); err != nil {
+			return nil, err
+		}
+		res, err := wm.RunWorkflow(ctx, db, name, u.head.String())
+		run.Finish(res, err)
+		if err = history.Update(ctx, run); err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, nil
+}
+
+// BeforePush implements sqle.RemoteSrvPushHook. It runs the workflows required for each updated branch against its
+// new head, and rejects the push if any of them do not pass. The definitions of required workflows are read from the
+// current head of the branch, so a push cannot change the workflows it is checked against.
+func (t *Trigger) BeforePush(ctx context.Context, dbName string, updates []sqle.BranchHeadUpdate) error {
+	for _, update := range updates {
+		required := t.required[update.Branch]
+		if len(required) == 0 {
+			continue
+		}
+
+		sqlCtx, err := t.ctxFactory(ctx)
+		if err != nil {
+			return err
+		}
+
+		definitionsRev := update.Branch
+		if update.OldHead.IsEmpty() {
+			// the push creates the branch, so use the workflows of the default branch
+			definitionsRev = ""
+		}
+
+		u := branchUpdate{dbName: dbName, branch: update.Branch, head: update.NewHead, event: dolt_ci.WorkflowRunEventPush}
+		runs, err := t.runWorkflows(sqlCtx, u, definitionsRev, required, nil)
+		if err != nil {
+			return status.Errorf(codes.Internal, "error running required workflows for branch %s: %v", update.Branch, err)
+		}
+
+		var failed []string
+		for _, run := range runs {
+			if run.Status != dolt_ci.WorkflowRunStatusPassed {
+				failed = append(failed, fmt.Sprintf("%s (%s: %s)", run.WorkflowName, run.Status, run.Message))
+			}
+		}
+		if len(failed) > 0 {
+			return status.Errorf(codes.FailedPrecondition, "push to protected branch %s rejected, required workflows did not pass: %s", update.Branch, strings.Join(failed, ", "))
+		}
+	}
+	return nil
+}
+
+// AfterPush implements sqle.RemoteSrvPushHook.
+func (t *Trigger) AfterPush(_ context.Context, dbName string, updates []sqle.BranchHeadUpdate) {
+	for _, update := range updates {
+		t.enqueue(branchUpdate{dbName: dbName, branch: update.Branch, head: update.NewHead, event: dolt_ci.WorkflowRunEventPush})
+	}
+}
+
+// CommitHook returns a doltdb.CommitHook which queues the branch updates made to the database named.
+func (t *Trigger) CommitHook(dbName string) doltdb.CommitHook {
+	return &commitHook{t: t, dbName: dbName}
+}
+
+type commitHook struct {
+	t      *Trigger
+	dbName string
+	out    io.Writer
+}
+
+var _ doltdb.CommitHook = (*commitHook)(nil)
+
+// Execute implements doltdb.CommitHook
+func (h *commitHook) Execute(_ context.Context, ds datas.Dataset, _ datas.Database) (func(context.Context) error, error) {
+	if !ref.IsRef(ds.ID()) {
+		return nil, nil
+	}
+	r, err := ref.Parse(ds.ID())
+	if err != nil || r.GetType() != ref.BranchRefType {
+		return nil, nil
+	}
+	addr, ok := ds.MaybeHeadAddr()
+	if !ok {
+		// the branch was deleted
+		return nil, nil
+	}
+
+	h.t.enqueue(branchUpdate{dbName: h.dbName, branch: r.GetPath(), head: addr})
+	return nil, nil
+}
+
+// HandleError implements doltdb.CommitHook
+func (h *commitHook) HandleError(_ context.Context, err error) error {
+	if h.out != nil {
+		h.out.Write([]byte(err.Error()))
+	}
+	return nil
+}
+
+// SetLogger implements doltdb.CommitHook
+func (h *commitHook) SetLogger(_ context.Context, wr io.Writer) error {
+	h.out = wr
+	return nil
+}
+
+// ExecuteForWorkingSets implements doltdb.CommitHook
+func (*commitHook) ExecuteForWorkingSets() bool {
+	return false
+}
@@ -174,6 +174,10 @@ func (n noopRepoStateWriter) AddRemote(r env.Remote) error {
 	return nil
 }
 
+func (n noopRepoStateWriter) UpdateRemote(r env.Remote) error {
+	return nil
+}
+
 func (n noopRepoStateWriter) AddBackup(r env.Remote) error {
 	return nil
 }
@@ -51,6 +51,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
 	"github.com/dolthub/dolt/go/libraries/utils/concurrentmap"
+	"github.com/dolthub/dolt/go/libraries/utils/set"
 	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/val"
 )
@@ -295,6 +296,9 @@ func (db Database) GetTableInsensitiveAsOf(ctx *sql.Context, tableName string, a
 		return table, ok, nil
 	}
 
+	// statistics requested for this table by name describe its data as of |asOf|
+	sess.RecordAsOfRead(ctx, db.Name(), tableName, asOf)
+
 	switch t := table.(type) {
 	case dtables.VersionableTable:
 		versionedTable, err := t.LockedToRoot(ctx, root)
@@ -621,6 +625,10 @@ func (db Database) getTableInsensitive(ctx *sql.Context, head *doltdb.Commit, ds
 		if !resolve.UseSearchPath || isDoltgresSystemTable {
 			dt, found = dtables.NewTagsTable(ctx, lwrName, db.ddb), true
 		}
+	case doltdb.NotesTableName:
+		dt, found = dtables.NewNotesTable(ctx, lwrName, db.ddb), true
+	case doltdb.UnfetchedTablesTableName:
+		dt, found = dtables.NewUnfetchedTablesTable(lwrName, db.schemaName, root), true
 	case dtables.AccessTableName:
 		basCtx := branch_control.GetBranchAwareSession(ctx)
 		if basCtx != nil {
@@ -635,6 +643,20 @@ func (db Database) getTableInsensitive(ctx *sql.Context, head *doltdb.Commit, ds
 				dt, found = dtables.NewBranchNamespaceControlTable(controller.Namespace), true
 			}
 		}
+	case dtables.ProtectionTableName:
+		basCtx := branch_control.GetBranchAwareSession(ctx)
+		if basCtx != nil {
+			if controller := basCtx.GetController(); controller != nil {
+				dt, found = dtables.NewBranchProtectionTable(controller.Protection), true
+			}
+		}
+	case dtables.ApprovalsTableName:
+		basCtx := branch_control.GetBranchAwareSession(ctx)
+		if basCtx != nil {
+			if controller := basCtx.GetController(); controller != nil {
+				dt, found = dtables.NewApprovalsTable(controller.Approvals), true
+			}
+		}
 	case doltdb.IgnoreTableName:
 		if resolve.UseSearchPath && db.schemaName == "" {
 			schemaName, err := resolve.FirstExistingSchemaOnSearchPath(ctx, root)
@@ -654,6 +676,27 @@ func (db Database) getTableInsensitive(ctx *sql.Context, head *doltdb.Commit, ds
 			versionableTable := backingTable.(dtables.VersionableTable)
 			dt, found = dtables.NewIgnoreTable(ctx, versionableTable, db.schemaName), true
 		}
+	case doltdb.MergePoliciesTableName:
+		backingTable, _, err := db.getTable(ctx, root, doltdb.MergePoliciesTableName)
+		if err != nil {
+			return nil, false, err
+		}
+		if backingTable == nil {
+			dt, found = dtables.NewEmptyMergePoliciesTable(ctx), true
+		} else {
+			versionableTable := backingTable.(dtables.VersionableTable)
+			dt, found = dtables.NewMergePoliciesTable(ctx, versionableTable), true
+		}
+	case doltdb.MergeResolutionsTableName:
+		backingTable, _, err := db.getTable(ctx, root, doltdb.MergeResolutionsTableName)
+		if err != nil {
+			return nil, false, err
+		}
+		if backingTable == nil {
+			dt, found = dtables.NewMergeResolutionsTable(ctx, nil), true
+		} else {
+			dt, found = dtables.NewMergeResolutionsTable(ctx, backingTable.(dtables.VersionableTable)), true
+		}
 	case doltdb.GetDocTableName(), doltdb.DocTableName:
 		isDoltgresSystemTable, err := resolve.IsDoltgresSystemTable(ctx, tname, root)
 		if err != nil {
@@ -695,6 +738,10 @@ func (db Database) getTableInsensitive(ctx *sql.Context, head *doltdb.Commit, ds
 			return nil, false, err
 		}
 		dt, found = dtables.NewStatisticsTable(ctx, db.Name(), db.schemaName, branch, tables), true
+	case doltdb.CIRunsTableName:
+		dt, found = dtables.NewCIRunsTable(ctx, db.AliasedName(), lwrName, db.ddb), true
+	case doltdb.CIRunStepsTableName:
+		dt, found = dtables.NewCIRunStepsTable(ctx, db.AliasedName(), lwrName, db.ddb), true
 	case doltdb.ProceduresTableName:
 		found = true
 		backingTable, _, err := db.getTable(ctx, root, doltdb.ProceduresTableName)
@@ -886,6 +933,10 @@ func (db Database) GetTableNamesAsOf(ctx *sql.Context, time interface{}) ([]stri
 	if err != nil {
 		return nil, err
 	}
+	tblNames, err = db.filterUnfetchedTables(ctx, root, tblNames)
+	if err != nil {
+		return nil, err
+	}
 
 	return filterDoltInternalTables(ctx, tblNames, db.schemaName), nil
 }
@@ -1081,9 +1132,9 @@ func (db Database) newDoltTable(tableName string, sch schema.Schema, tbl *doltdb
 }
 
 // GetTableNames returns the names of all user tables. System tables in user space (e.g. dolt_docs, dolt_query_catalog)
-// are filtered out. This method is used for queries that examine the schema of the database, e.g. show tables. Table
-// name resolution in queries is handled by GetTableInsensitive. Use GetAllTableNames for an unfiltered list of all
-// tables in user space.
+// and tables whose data wasn't fetched by a partial clone are filtered out. This method is used for queries that
+// examine the schema of the database, e.g. show tables. Table name resolution in queries is handled by
+// GetTableInsensitive. Use GetAllTableNames for an unfiltered list of all tables in user space.
 func (db Database) GetTableNames(ctx *sql.Context) ([]string, error) {
 	showSystemTablesVar, err := ctx.GetSessionVariable(ctx, dsess.ShowSystemTables)
 	if err != nil {
@@ -1091,7 +1142,15 @@ func (db Database) GetTableNames(ctx *sql.Context) ([]string, error) {
 	}
 
 	showSystemTables := showSystemTablesVar.(int8) == 1
-	tblNames, err := db.GetAllTableNames(ctx, showSystemTables)
+	root, err := db.GetRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tblNames, err := db.getAllTableNames(ctx, root, showSystemTables)
+	if err != nil {
+		return nil, err
+	}
+	tblNames, err = db.filterUnfetchedTables(ctx, root, tblNames)
 	if err != nil {
 		return nil, err
 	}
@@ -1150,6 +1209,48 @@ func (db Database) getAllTableNames(ctx *sql.Context, root doltdb.RootValue, sho
 	return result, nil
 }
 
+// filterUnfetchedTables removes the tables whose data wasn't fetched by a partial clone from |tblNames|. Reading them
+// is an error, so they are listed by the dolt_unfetched_tables system table instead.
+func (db Database) filterUnfetchedTables(ctx *sql.Context, root doltdb.RootValue, tblNames []string) ([]string, error) {
+	if !db.isPartialClone() {
+		return tblNames, nil
+	}
+
+	unfetched, err := doltdb.GetUnfetchedTableNames(ctx, root, db.schemaName)
+	if err != nil {
+		return nil, err
+	}
+	if len(unfetched) == 0 {
+		return tblNames, nil
+	}
+
+	unfetchedSet := set.NewStrSet(unfetched)
+	result := make([]string, 0, len(tblNames)-len(unfetched))
+	for _, name := range tblNames {
+		if !unfetchedSet.Contains(name) {
+			result = append(result, name)
+		}
+	}
+	return result, nil
+}
+
+// isPartialClone returns whether a remote of this database has a table filter.
+func (db Database) isPartialClone() bool {
+	if db.rsr == nil {
+		return false
+	}
+	remotes, err := db.rsr.GetRemotes()
+	if err != nil {
+		return false
+	}
+	partial := false
+	remotes.Iter(func(_ string, r env.Remote) bool {
+		partial = !r.TableFilter.IsEmpty()
+		return !partial
+	})
+	return partial
+}
+
 func filterDoltInternalTables(ctx *sql.Context, tblNames []string, schemaName string) []string {
 	result := []string{}
 
@@ -2528,6 +2629,10 @@ func (n noopRepoStateWriter) AddRemote(r env.Remote) error {
 	return nil
 }
 
+func (n noopRepoStateWriter) UpdateRemote(r env.Remote) error {
+	return nil
+}
+
 func (n noopRepoStateWriter) AddBackup(r env.Remote) error {
 	return nil
 }
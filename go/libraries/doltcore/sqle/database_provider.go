@@ -26,6 +26,7 @@ import (
 	"github.com/dolthub/go-mysql-server/sql"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
 	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
@@ -56,6 +57,8 @@ type DoltDatabaseProvider struct {
 	externalProcedures sql.ExternalStoredProcedureRegistry
 	InitDatabaseHooks  []InitDatabaseHook
 	DropDatabaseHooks  []DropDatabaseHook
+	commitValidators   []doltdb.CommitValidator
+	reflogPolicy       *doltdb.ReflogPolicy
 	mu                 *sync.RWMutex
 
 	droppedDatabaseManager *droppedDatabaseManager
@@ -66,6 +69,7 @@ type DoltDatabaseProvider struct {
 
 	dbFactoryUrl string
 	isStandby    *bool
+	runner       dsess.StatementRunner
 }
 
 var _ sql.DatabaseProvider = (*DoltDatabaseProvider)(nil)
@@ -193,12 +197,75 @@ func (p *DoltDatabaseProvider) AddInitDatabaseHook(hook InitDatabaseHook) {
 	p.InitDatabaseHooks = append(p.InitDatabaseHooks, hook)
 }
 
+// AddCommitValidator adds |v| to the validators of every database of this provider, including databases created or
+// cloned later, so that it checks every branch head update made through them.
+func (p *DoltDatabaseProvider) AddCommitValidator(v doltdb.CommitValidator) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.commitValidators = append(p.commitValidators, v)
+	for _, db := range p.databases {
+		if ddb := db.DbData().Ddb; ddb != nil {
+			ddb.AddCommitValidator(v)
+		}
+	}
+}
+
+// SetReflogPolicy sets the reflog policy of every database of this provider, including databases created or cloned
+// after it is called.
+func (p *DoltDatabaseProvider) SetReflogPolicy(policy doltdb.ReflogPolicy) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.reflogPolicy = &policy
+	for _, db := range p.databases {
+		if ddb := db.DbData().Ddb; ddb != nil {
+			ddb.SetReflogPolicy(policy)
+		}
+	}
+}
+
+// ArchiveReflogs archives the persistent reflog of each database of this provider which is due to be archived, see
+// doltdb.DoltDB.MaybeArchiveReflog. Every database is archived, even if archiving some of them fails. Standbys don't
+// archive their reflog, which is replicated from the primary.
+func (p *DoltDatabaseProvider) ArchiveReflogs(ctx context.Context) error {
+	p.mu.RLock()
+	if *p.isStandby {
+		p.mu.RUnlock()
+		return nil
+	}
+	ddbs := make(map[string]*doltdb.DoltDB, len(p.databases))
+	for name, db := range p.databases {
+		if ddb := db.DbData().Ddb; ddb != nil {
+			ddbs[name] = ddb
+		}
+	}
+	p.mu.RUnlock()
+
+	var errs []error
+	for name, ddb := range ddbs {
+		if err := ddb.MaybeArchiveReflog(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("unable to archive the reflog of database %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // AddDropDatabaseHook adds a DropDatabaseHook to this provider. The hook will be invoked
 // whenever this provider drops a database.
 func (p *DoltDatabaseProvider) AddDropDatabaseHook(hook DropDatabaseHook) {
 	p.DropDatabaseHooks = append(p.DropDatabaseHooks, hook)
 }
 
+// SetStatementRunner sets the StatementRunner used to execute statements on behalf of sessions of this provider. It
+// should be the engine this provider serves, and must be set before any sessions are created.
+func (p *DoltDatabaseProvider) SetStatementRunner(runner dsess.StatementRunner) {
+	p.runner = runner
+}
+
+// StatementRunner implements dsess.DoltDatabaseProvider.
+func (p *DoltDatabaseProvider) StatementRunner() dsess.StatementRunner {
+	return p.runner
+}
+
 func (p *DoltDatabaseProvider) FileSystem() filesys.Filesys {
 	return p.fs
 }
@@ -307,7 +374,7 @@ func (p *DoltDatabaseProvider) attemptCloneReplica(ctx *sql.Context, dbName stri
 	// TODO: remote params for AWS, others
 	// TODO: this needs to be robust in the face of the DB not having the default branch
 	// TODO: this treats every database not found error as a clone error, need to tighten
-	err := p.CloneDatabaseFromRemote(ctx, dbName, p.defaultBranch, remoteName, remoteUrl, -1, nil)
+	err := p.CloneDatabaseFromRemote(ctx, dbName, p.defaultBranch, remoteName, remoteUrl, -1, nil, nil)
 	if err != nil {
 		return err
 	}
@@ -391,8 +458,15 @@ func (p *DoltDatabaseProvider) allRevisionDbs(ctx *sql.Context, db dsess.SqlData
 		return nil, err
 	}
 
-	revDbs := make([]sql.Database, len(branches))
-	for i, branch := range branches {
+	revDbs := make([]sql.Database, 0, len(branches))
+	for _, branch := range branches {
+		// Branches hidden from the current user are not listed
+		err = branch_control.CheckReadAccess(ctx, db.Name(), branch.GetPath())
+		if branch_control.ErrCannotReadBranch.Is(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
 		revisionQualifiedName := fmt.Sprintf("%s/%s", db.Name(), branch.GetPath())
 		revDb, ok, err := p.databaseForRevision(ctx, revisionQualifiedName, revisionQualifiedName)
 		if err != nil {
@@ -401,7 +475,7 @@ func (p *DoltDatabaseProvider) allRevisionDbs(ctx *sql.Context, db dsess.SqlData
 		if !ok {
 			return nil, fmt.Errorf("cannot get revision database for %s/%s", db.Name(), branch.GetPath())
 		}
-		revDbs[i] = revDb
+		revDbs = append(revDbs, revDb)
 	}
 
 	return revDbs, nil
@@ -661,6 +735,7 @@ func (p *DoltDatabaseProvider) CloneDatabaseFromRemote(
 	ctx *sql.Context,
 	dbName, branch, remoteName, remoteUrl string,
 	depth int,
+	tableFilter *env.TableFilter,
 	remoteParams map[string]string,
 ) error {
 	p.mu.Lock()
@@ -673,7 +748,7 @@ func (p *DoltDatabaseProvider) CloneDatabaseFromRemote(
 		return fmt.Errorf("cannot create DB, file exists at %s", dbName)
 	}
 
-	err := p.cloneDatabaseFromRemote(ctx, dbName, remoteName, branch, remoteUrl, depth, remoteParams)
+	err := p.cloneDatabaseFromRemote(ctx, dbName, remoteName, branch, remoteUrl, depth, tableFilter, remoteParams)
 	if err != nil {
 		// Make a best effort to clean up any artifacts on disk from a failed clone
 		// before we return the error
@@ -698,6 +773,7 @@ func (p *DoltDatabaseProvider) cloneDatabaseFromRemote(
 	ctx *sql.Context,
 	dbName, remoteName, branch, remoteUrl string,
 	depth int,
+	tableFilter *env.TableFilter,
 	remoteParams map[string]string,
 ) error {
 	if p.remoteDialer == nil {
@@ -705,12 +781,13 @@ func (p *DoltDatabaseProvider) cloneDatabaseFromRemote(
 	}
 
 	r := env.NewRemote(remoteName, remoteUrl, remoteParams)
+	r.TableFilter = tableFilter
 	srcDB, err := r.GetRemoteDB(ctx, types.Format_Default, p.remoteDialer)
 	if err != nil {
 		return err
 	}
 
-	dEnv, err := actions.EnvForClone(ctx, srcDB.ValueReadWriter().Format(), r, dbName, p.fs, "VERSION", env.GetCurrentUserHomeDir)
+	dEnv, err := actions.EnvForClone(ctx, srcDB, r, dbName, p.fs, "VERSION", env.GetCurrentUserHomeDir)
 	if err != nil {
 		return err
 	}
@@ -856,6 +933,12 @@ func (p *DoltDatabaseProvider) registerNewDatabase(ctx *sql.Context, name string
 	if err != nil {
 		return err
 	}
+	for _, v := range p.commitValidators {
+		newEnv.DoltDB.AddCommitValidator(v)
+	}
+	if p.reflogPolicy != nil {
+		newEnv.DoltDB.SetReflogPolicy(*p.reflogPolicy)
+	}
 
 	// If we have any initialization hooks, invoke them, until any error is returned.
 	// By default, this will be ConfigureReplicationDatabaseHook, which will set up
@@ -930,6 +1013,9 @@ func (p *DoltDatabaseProvider) databaseForRevision(ctx *sql.Context, revisionQua
 	dbCache := sess.DatabaseCache(ctx)
 	db, ok := dbCache.GetCachedRevisionDb(revisionQualifiedName, requestedName)
 	if ok {
+		if err := checkRevisionDbReadAccess(ctx, db, revisionQualifiedName); err != nil {
+			return nil, false, err
+		}
 		return db, true, nil
 	}
 
@@ -965,6 +1051,9 @@ func (p *DoltDatabaseProvider) databaseForRevision(ctx *sql.Context, revisionQua
 		} else if err != nil {
 			return nil, false, err
 		}
+		if err = checkRevisionDbReadAccess(ctx, db, revisionQualifiedName); err != nil {
+			return nil, false, err
+		}
 
 		dbCache.CacheRevisionDb(db)
 		return db, true, nil
@@ -985,6 +1074,9 @@ func (p *DoltDatabaseProvider) databaseForRevision(ctx *sql.Context, revisionQua
 		if err != nil {
 			return nil, false, err
 		}
+		if err = checkRevisionDbReadAccess(ctx, db, revisionQualifiedName); err != nil {
+			return nil, false, err
+		}
 
 		dbCache.CacheRevisionDb(db)
 		return db, true, nil
@@ -1004,6 +1096,9 @@ func (p *DoltDatabaseProvider) databaseForRevision(ctx *sql.Context, revisionQua
 		if err != nil {
 			return nil, false, err
 		}
+		if err = checkRevisionDbReadAccess(ctx, db, revisionQualifiedName); err != nil {
+			return nil, false, err
+		}
 
 		dbCache.CacheRevisionDb(db)
 		return db, true, nil
@@ -1016,6 +1111,16 @@ func (p *DoltDatabaseProvider) databaseForRevision(ctx *sql.Context, revisionQua
 	}
 }
 
+// checkRevisionDbReadAccess returns an error if the current user may not read from the given revision database. Branches
+// that are hidden by branch control are reported as nonexistent databases, so that their existence is not revealed.
+func checkRevisionDbReadAccess(ctx *sql.Context, db dsess.SqlDatabase, revisionQualifiedName string) error {
+	err := dsess.CheckReadAccessForDb(ctx, db)
+	if branch_control.ErrCannotReadBranch.Is(err) || branch_control.ErrCannotReadCommit.Is(err) {
+		return sql.ErrDatabaseNotFound.New(revisionQualifiedName)
+	}
+	return err
+}
+
 // revisionDbType returns the type of revision spec given for the database given, and the resolved revision spec
 func revisionDbType(ctx *sql.Context, srcDb dsess.SqlDatabase, revSpec string) (revType dsess.RevisionType, resolvedRevSpec string, err error) {
 	resolvedRevSpec, err = resolveAncestorSpec(ctx, revSpec, srcDb.DbData().Ddb)
@@ -1684,6 +1789,14 @@ func (s staticRepoState) CWBHeadRef() (ref.DoltRef, error) {
 	return s.branch, nil
 }
 
+// CheckBranchNotCheckedOut implements env.WorktreeChecker
+func (s staticRepoState) CheckBranchNotCheckedOut(branch ref.BranchRef) error {
+	if wc, ok := s.RepoStateReader.(env.WorktreeChecker); ok {
+		return wc.CheckBranchNotCheckedOut(branch)
+	}
+	return nil
+}
+
 // formatDbMapKeyName returns formatted string of database name and/or branch name. Database name is case-insensitive,
 // so it's stored in lower case name. Branch name is case-sensitive, so not changed.
 // TODO: branch names should be case-insensitive too
@@ -0,0 +1,230 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+const DoltAtTimeFuncName = "dolt_at_time"
+
+const atTimeFormat = "2006-01-02 15:04:05"
+
+// AtTime is the dolt_at_time(ref, timestamp) function, which returns the hash of the commit that a branch or tag
+// pointed to at a point in time. Unlike AS OF with a timestamp, which walks the commit dates of the current branch,
+// this uses the reflog, so it reflects where the ref actually was at that time, including after resets and force
+// pushes, and works for refs that have since been deleted. The result can be used with AS OF, e.g.
+// SELECT * FROM t AS OF dolt_at_time('main', '2024-03-01 09:00:00').
+type AtTime struct {
+	expression.BinaryExpressionStub
+}
+
+// NewAtTime returns an AtTime sql function.
+func NewAtTime(refExpr, timeExpr sql.Expression) sql.Expression {
+	return &AtTime{expression.BinaryExpressionStub{LeftChild: refExpr, RightChild: timeExpr}}
+}
+
+// Eval implements the sql.Expression interface.
+func (a AtTime) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	refVal, err := a.Left().Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	timeVal, err := a.Right().Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	if refVal == nil || timeVal == nil {
+		return nil, nil
+	}
+
+	refName, ok := refVal.(string)
+	if !ok {
+		return nil, errors.New("ref value is not a string")
+	}
+
+	asOf, err := types.DatetimeMaxPrecision.ConvertWithoutRangeCheck(timeVal)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp for %s: %w", DoltAtTimeFuncName, err)
+	}
+
+	sess := dsess.DSessFromSess(ctx.Session)
+	dbName := ctx.GetCurrentDatabase()
+	ddb, ok := sess.GetDoltDB(ctx, dbName)
+	if !ok {
+		return nil, sql.ErrDatabaseNotFound.New(dbName)
+	}
+
+	root, ok, err := ddb.ReflogRootAtTime(asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	var refId string
+	var commitHash hash.Hash
+	if ok {
+		refId, err = resolveRefAtRoot(ctx, ddb, root, refName)
+		if err != nil {
+			return nil, err
+		}
+		if refId != "" {
+			h, err := ddb.GetHashForRefStrByNomsRoot(ctx, refId, root)
+			if err != nil {
+				return nil, err
+			}
+			commitHash = *h
+		}
+	} else {
+		// The roots recorded by the chunk journal don't go back as far as |asOf|, so fall back to the persistent reflog
+		var found bool
+		refId, commitHash, found, err = resolveRefInArchivedReflog(ctx, ddb, refName, asOf)
+		if err != nil {
+			return nil, err
+		} else if !found {
+			return nil, fmt.Errorf("no reflog entry found at or before %s", asOf.UTC().Format(atTimeFormat))
+		}
+	}
+
+	if refId == "" {
+		return nil, fmt.Errorf("ref '%s' did not exist at %s", refName, asOf.UTC().Format(atTimeFormat))
+	}
+
+	doltRef, err := ref.Parse(refId)
+	if err != nil {
+		return nil, err
+	}
+	if doltRef.GetType() == ref.BranchRefType {
+		if err = branch_control.CheckReadAccess(ctx, dbName, doltRef.GetPath()); err != nil {
+			return nil, err
+		}
+	} else if branch_control.MayHideBranches(ctx) {
+		// Like AS OF with a tag or commit hash, other refs may only be resolved to commits reachable from a branch the
+		// user may read
+		hidden, err := ddb.IsHiddenCommit(ctx, commitHash)
+		if err != nil {
+			return nil, err
+		}
+		if hidden {
+			bas := branch_control.GetBranchAwareSession(ctx)
+			return nil, branch_control.ErrCannotReadCommit.New(bas.GetUser(), bas.GetHost(), refName)
+		}
+	}
+
+	return commitHash.String(), nil
+}
+
+// refCandidates returns the full paths |refName| may refer to, in order of precedence. Like dolt_reflog, |refName|
+// may be a full ref path or the name of a branch or tag, and branches take precedence over tags with the same name.
+func refCandidates(refName string) []string {
+	if strings.HasPrefix(strings.ToLower(refName), "refs/") {
+		return []string{refName}
+	}
+	return []string{ref.NewBranchRef(refName).String(), ref.NewTagRef(refName).String()}
+}
+
+// resolveRefAtRoot returns the full path of the ref named |refName| in the database at |root|, or the empty string if
+// there is no such ref. |refName| is case-insensitive.
+func resolveRefAtRoot(ctx *sql.Context, ddb *doltdb.DoltDB, root hash.Hash, refName string) (string, error) {
+	datasets, err := ddb.DatasetsByRootHash(ctx, root)
+	if err != nil {
+		return "", err
+	}
+
+	candidates := refCandidates(refName)
+	matches := make([]string, len(candidates))
+	err = datasets.IterAll(ctx, func(id string, _ hash.Hash) error {
+		for i, candidate := range candidates {
+			if strings.EqualFold(id, candidate) {
+				matches[i] = id
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for _, match := range matches {
+		if match != "" {
+			return match, nil
+		}
+	}
+	return "", nil
+}
+
+// resolveRefInArchivedReflog returns the full path of the ref named |refName| and the commit it pointed to at |asOf|,
+// according to the latest entry of the persistent reflog at or before |asOf|. It returns false if the persistent
+// reflog has no entry that old, and an empty ref if none of its entries that old is for |refName|. Ref deletions are
+// not recorded in the reflog, so a deleted ref resolves to the last commit it pointed to.
+func resolveRefInArchivedReflog(ctx *sql.Context, ddb *doltdb.DoltDB, refName string, asOf time.Time) (string, hash.Hash, bool, error) {
+	entries, err := ddb.ArchivedReflog(ctx)
+	if err != nil {
+		return "", hash.Hash{}, false, err
+	}
+
+	candidates := refCandidates(refName)
+	matches := make([]*doltdb.ReflogEntry, len(candidates))
+	found := false
+	for i := range entries {
+		if entries[i].Timestamp == nil || entries[i].Timestamp.After(asOf) {
+			continue
+		}
+		found = true
+		for j, candidate := range candidates {
+			if strings.EqualFold(entries[i].Ref, candidate) {
+				matches[j] = &entries[i]
+			}
+		}
+	}
+
+	for _, match := range matches {
+		if match != nil {
+			return match.Ref, match.CommitHash, true, nil
+		}
+	}
+	return "", hash.Hash{}, found, nil
+}
+
+// String implements the sql.Expression interface.
+func (a AtTime) String() string {
+	return fmt.Sprintf("DOLT_AT_TIME(%s,%s)", a.Left().String(), a.Right().String())
+}
+
+// Type implements the sql.Expression interface.
+func (a AtTime) Type() sql.Type {
+	return types.Text
+}
+
+// WithChildren implements the sql.Expression interface.
+func (a AtTime) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 2 {
+		return nil, sql.ErrInvalidChildrenNumber.New(a, len(children), 2)
+	}
+	return NewAtTime(children[0], children[1]), nil
+}
@@ -187,7 +187,7 @@ func restoreBackup(ctx *sql.Context, _ env.DbData, apr *argparser.ArgParseResult
 		userDirExisted, _ := sess.Provider().FileSystem().Exists(dbName)
 
 		// Create a new Dolt env for the clone; use env.NoRemote to avoid origin upstream
-		clonedEnv, err := actions.EnvForClone(ctx, srcDb.ValueReadWriter().Format(), env.NoRemote, dbName,
+		clonedEnv, err := actions.EnvForClone(ctx, srcDb, env.NoRemote, dbName,
 			sess.Provider().FileSystem(), doltversion.Version, env.GetCurrentUserHomeDir)
 		if err != nil {
 			return errhand.VerboseErrorFromError(err)
@@ -309,6 +309,11 @@ func syncRootsToBackup(ctx *sql.Context, dbData env.DbData, sess *dsess.DoltSess
 		return err
 	}
 
+	// archive the reflog first, so that the backup includes it
+	if err = dbData.Ddb.ArchiveReflog(ctx); err != nil {
+		return err
+	}
+
 	err = actions.SyncRoots(ctx, dbData.Ddb, destDb, tmpDir, runProgFuncs, stopProgFuncs)
 	if err != nil && err != pull.ErrDBUpToDate {
 		return fmt.Errorf("error syncing backup: %w", err)
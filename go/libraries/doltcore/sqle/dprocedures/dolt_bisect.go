@@ -0,0 +1,396 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/bisect"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+const (
+	BisectStartCmd = "start"
+	BisectGoodCmd  = "good"
+	BisectBadCmd   = "bad"
+	BisectSkipCmd  = "skip"
+	BisectResetCmd = "reset"
+	BisectRunCmd   = "run"
+
+	BisectResetMessage = "Bisect reset."
+)
+
+var doltBisectProcedureSchema = []*sql.Column{
+	{
+		Name:     "status",
+		Type:     types.Int64,
+		Nullable: false,
+	},
+	{
+		Name:     "message",
+		Type:     types.LongText,
+		Nullable: true,
+	},
+}
+
+// doltBisect is the stored procedure version for the CLI command `dolt bisect`.
+func doltBisect(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	res, message, err := doDoltBisect(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(res), message), nil
+}
+
+func doDoltBisect(ctx *sql.Context, args []string) (int, string, error) {
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return 1, "", sql.ErrNoDatabaseSelected.New()
+	}
+
+	apr, err := cli.CreateBisectArgParser().Parse(args)
+	if err != nil {
+		return 1, "", err
+	}
+	if apr.NArg() == 0 {
+		return 1, "", fmt.Errorf("error: missing bisect subcommand, expected one of start, good, bad, skip, reset or run")
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	dbData, ok := dSess.GetDbData(ctx, dbName)
+	if !ok {
+		return 1, "", fmt.Errorf("Could not load database %s", dbName)
+	}
+	fs, err := dSess.Provider().FileSystemForDatabase(dbName)
+	if err != nil {
+		return 1, "", err
+	}
+
+	subcommand, revs := strings.ToLower(apr.Arg(0)), apr.Args[1:]
+	switch subcommand {
+	case BisectResetCmd:
+		err = env.UpdateBisectState(fs, func(*env.BisectState) (*env.BisectState, error) {
+			return nil, nil
+		})
+		if err != nil {
+			return 1, "", err
+		}
+		return 0, BisectResetMessage, nil
+	case BisectRunCmd:
+		if len(revs) == 0 {
+			return 1, "", fmt.Errorf("error: 'bisect run' requires a query")
+		}
+		message, err := runBisect(ctx, dbName, dbData.Ddb, fs, strings.Join(revs, " "))
+		if err != nil {
+			return 1, "", err
+		}
+		return 0, message, nil
+	case BisectStartCmd, BisectGoodCmd, BisectBadCmd, BisectSkipCmd:
+	default:
+		return 1, "", fmt.Errorf("error: unknown bisect subcommand: %s", apr.Arg(0))
+	}
+
+	var message string
+	err = env.UpdateBisectState(fs, func(state *env.BisectState) (*env.BisectState, error) {
+		switch subcommand {
+		case BisectStartCmd:
+			if state != nil {
+				return nil, bisect.ErrInProgress
+			}
+			state = &env.BisectState{}
+			if len(revs) > 0 {
+				if err := markBisectCommits(ctx, dbData, state, BisectBadCmd, revs[:1]); err != nil {
+					return nil, err
+				}
+				if err := markBisectCommits(ctx, dbData, state, BisectGoodCmd, revs[1:]); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			if state == nil {
+				return nil, bisect.ErrNotStarted
+			}
+			if subcommand == BisectBadCmd && len(revs) > 1 {
+				return nil, fmt.Errorf("error: 'bisect bad' takes at most one commit")
+			}
+			if err := markBisectCommits(ctx, dbData, state, subcommand, revs); err != nil {
+				return nil, err
+			}
+		}
+
+		res, err := bisect.Next(ctx, dbData.Ddb, state)
+		if err != nil {
+			return nil, err
+		}
+		message, err = bisectResultMessage(ctx, state, res)
+		if err != nil {
+			return nil, err
+		}
+		return state, nil
+	})
+	if err != nil {
+		return 1, "", err
+	}
+	return 0, message, nil
+}
+
+// markBisectCommits records the commits |revs| as good, bad or skipped in |state|. If no commits are given, the commit
+// being tested is marked, or the current HEAD if there isn't one.
+func markBisectCommits(ctx *sql.Context, dbData env.DbData, state *env.BisectState, term string, revs []string) error {
+	var hashes []string
+	if len(revs) == 0 {
+		if state.Current != "" {
+			hashes = append(hashes, state.Current)
+		} else {
+			revs = []string{"HEAD"}
+		}
+	}
+
+	if len(revs) > 0 {
+		headRef, err := dbData.Rsr.CWBHeadRef()
+		if err != nil {
+			return err
+		}
+		for _, rev := range revs {
+			h, err := resolveBisectCommit(ctx, dbData.Ddb, headRef, rev)
+			if err != nil {
+				return err
+			}
+			hashes = append(hashes, h)
+		}
+	}
+
+	for _, h := range hashes {
+		switch term {
+		case BisectBadCmd:
+			state.Bad = h
+		case BisectGoodCmd:
+			state.Good = appendIfMissing(state.Good, h)
+		case BisectSkipCmd:
+			state.Skip = appendIfMissing(state.Skip, h)
+		}
+	}
+	return nil
+}
+
+func resolveBisectCommit(ctx *sql.Context, ddb *doltdb.DoltDB, headRef ref.DoltRef, rev string) (string, error) {
+	cs, err := doltdb.NewCommitSpec(rev)
+	if err != nil {
+		return "", err
+	}
+	optCmt, err := ddb.Resolve(ctx, cs, headRef)
+	if err != nil {
+		return "", err
+	}
+	cm, ok := optCmt.ToCommit()
+	if !ok {
+		return "", doltdb.ErrGhostCommitEncountered
+	}
+	h, err := cm.HashOf()
+	if err != nil {
+		return "", err
+	}
+	return h.String(), nil
+}
+
+func appendIfMissing(hashes []string, h string) []string {
+	for _, existing := range hashes {
+		if existing == h {
+			return hashes
+		}
+	}
+	return append(hashes, h)
+}
+
+// runBisect tests commits with |query| until the first bad commit is found. A commit is good if the first column of
+// the first row |query| returns is truthy when it is run against that commit, and bad otherwise. The bisect state in
+// |fs| is updated as each commit is tested, so that marks made concurrently by other sessions are kept.
+func runBisect(ctx *sql.Context, dbName string, ddb *doltdb.DoltDB, fs filesys.Filesys, query string) (string, error) {
+	if err := validateBisectRunQuery(query); err != nil {
+		return "", err
+	}
+
+	baseName, _ := dsess.SplitRevisionDbName(dbName)
+
+	var sb strings.Builder
+	var current string
+	var good bool
+	for {
+		var res *bisect.Result
+		err := env.UpdateBisectState(fs, func(state *env.BisectState) (*env.BisectState, error) {
+			if state == nil {
+				return nil, bisect.ErrNotStarted
+			}
+			if current != "" {
+				if good {
+					state.Good = appendIfMissing(state.Good, current)
+				} else {
+					state.Bad = current
+				}
+			}
+
+			var err error
+			res, err = bisect.Next(ctx, ddb, state)
+			if err != nil {
+				return nil, err
+			}
+			if res.Waiting() {
+				return nil, fmt.Errorf("error: 'bisect run' requires a good and a bad commit, mark them with 'bisect good' and 'bisect bad'")
+			}
+			message, err := bisectResultMessage(ctx, state, res)
+			if err != nil {
+				return nil, err
+			}
+			sb.WriteString(message)
+			current = state.Current
+			return state, nil
+		})
+		if err != nil {
+			return "", err
+		}
+		if res.Next == nil {
+			return sb.String(), nil
+		}
+
+		good, err = evalBisectQuery(ctx, dsess.RevisionDbName(baseName, current), query)
+		if err != nil {
+			return "", err
+		}
+		if good {
+			sb.WriteString(fmt.Sprintf("\n%s is good\n", current))
+		} else {
+			sb.WriteString(fmt.Sprintf("\n%s is bad\n", current))
+		}
+	}
+}
+
+// validateBisectRunQuery only allows SELECT queries to be used to test commits, since they are run for each commit
+// tested.
+func validateBisectRunQuery(query string) error {
+	stmt, err := sqlparser.Parse(query)
+	if err != nil {
+		return err
+	}
+	switch s := stmt.(type) {
+	case *sqlparser.Select:
+		if s.Into == nil {
+			return nil
+		}
+	case *sqlparser.SetOp, *sqlparser.ParenSelect:
+		return nil
+	}
+	return fmt.Errorf("error: 'bisect run' query must be a SELECT statement")
+}
+
+// evalBisectQuery runs |query| against the database |revDbName| and returns whether the first column of its first
+// row is truthy. A query with no rows is falsy.
+func evalBisectQuery(ctx *sql.Context, revDbName, query string) (good bool, err error) {
+	current := ctx.GetCurrentDatabase()
+	ctx.SetCurrentDatabase(revDbName)
+	defer ctx.SetCurrentDatabase(current)
+
+	_, rowIter, _, err := dsess.RunQuery(ctx, query)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		cerr := rowIter.Close(ctx)
+		if err == nil {
+			err = cerr
+		}
+	}()
+
+	row, err := rowIter.Next(ctx)
+	if err == io.EOF {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if len(row) == 0 || row[0] == nil {
+		return false, nil
+	}
+	good, err = sql.ConvertToBool(ctx, row[0])
+	if err != nil {
+		return false, err
+	}
+
+	// drain the remaining rows so the iterator can be closed cleanly
+	for {
+		if _, err = rowIter.Next(ctx); err != nil {
+			break
+		}
+	}
+	if err != io.EOF {
+		return false, err
+	}
+	return good, nil
+}
+
+func bisectResultMessage(ctx *sql.Context, state *env.BisectState, res *bisect.Result) (string, error) {
+	switch {
+	case res.FirstBad != nil:
+		desc, err := bisectCommitDescription(ctx, res.FirstBad)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s is the first bad commit\n%s", state.Bad, desc), nil
+	case res.Next != nil:
+		desc, err := bisectCommitDescription(ctx, res.Next)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Bisecting: %d revisions left to test after this (roughly %d steps)\n%s", res.Remaining, res.Steps, desc), nil
+	case len(res.OnlySkipped) > 0:
+		var sb strings.Builder
+		sb.WriteString("There are only 'skip'ped commits left to test.\nThe first bad commit could be any of:")
+		for _, h := range res.OnlySkipped {
+			sb.WriteString("\n")
+			sb.WriteString(h.String())
+		}
+		sb.WriteString("\nWe cannot bisect more!")
+		return sb.String(), nil
+	case state.Bad == "" && len(state.Good) == 0:
+		return "status: waiting for both good and bad commits", nil
+	case state.Bad == "":
+		return fmt.Sprintf("status: waiting for bad commit, %d good commit(s) known", len(state.Good)), nil
+	default:
+		return "status: waiting for good commit(s), bad commit known", nil
+	}
+}
+
+func bisectCommitDescription(ctx *sql.Context, cm *doltdb.Commit) (string, error) {
+	h, err := cm.HashOf()
+	if err != nil {
+		return "", err
+	}
+	meta, err := cm.GetCommitMeta(ctx)
+	if err != nil {
+		return "", err
+	}
+	subject, _, _ := strings.Cut(meta.Description, "\n")
+	return fmt.Sprintf("[%s] %s", h.String(), subject), nil
+}
@@ -461,7 +461,7 @@ func copyABranch(ctx *sql.Context, dbData env.DbData, srcBr string, destBr strin
 		} else if err == doltdb.ErrInvBranchName {
 			return fmt.Errorf("fatal: '%s' is not a valid branch name.", destBr)
 		} else {
-			return fmt.Errorf("fatal: Unexpected error copying branch from '%s' to '%s'", srcBr, destBr)
+			return fmt.Errorf("fatal: Unexpected error copying branch from '%s' to '%s': %w", srcBr, destBr, err)
 		}
 	}
 	err = branch_control.AddAdminForContext(ctx, destBr)
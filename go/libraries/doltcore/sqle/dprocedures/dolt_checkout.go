@@ -146,6 +146,9 @@ func doDoltCheckout(ctx *sql.Context, args []string) (statusCode int, successMes
 	if isBranch, err := actions.IsBranch(ctx, dbData.Ddb, branchName); err != nil {
 		return 1, "", err
 	} else if isBranch {
+		if err = actions.CheckBranchNotInWorktree(dbData, ref.NewBranchRef(branchName)); err != nil {
+			return 1, "", err
+		}
 		err = checkoutExistingBranch(ctx, currentDbName, branchName, apr)
 		if errors.Is(err, doltdb.ErrWorkingSetNotFound) {
 			// If there is a branch but there is no working set,
@@ -419,6 +422,10 @@ func checkoutNewBranch(ctx *sql.Context, dbName string, dbData env.DbData, apr *
 		newBranchName = optionBBranch
 	}
 
+	if err = actions.CheckBranchNotInWorktree(dbData, ref.NewBranchRef(newBranchName)); err != nil {
+		return "", "", err
+	}
+
 	err = actions.CreateBranchWithStartPt(ctx, dbData, newBranchName, startPt, createBranchForcibly, rsc)
 	if err != nil {
 		return "", "", err
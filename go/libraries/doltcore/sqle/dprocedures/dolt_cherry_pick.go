@@ -74,6 +74,9 @@ func doDoltCherryPick(ctx *sql.Context, args []string) (string, int, int, int, e
 	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
 		return "", 0, 0, 0, err
 	}
+	if err := branch_control.CheckProtection(ctx); err != nil {
+		return "", 0, 0, 0, err
+	}
 
 	apr, err := cli.CreateCherryPickArgParser().Parse(args)
 	if err != nil {
@@ -0,0 +1,100 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/dolt_ci"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+const (
+	ciRunStatusPassed = "passed"
+	ciRunStatusFailed = "failed"
+)
+
+var doltCIRunSchema = []*sql.Column{
+	{Name: "job", Type: types.LongText, Nullable: false},
+	{Name: "step", Type: types.LongText, Nullable: false},
+	{Name: "saved_query", Type: types.LongText, Nullable: false},
+	{Name: "status", Type: types.LongText, Nullable: false},
+	{Name: "message", Type: types.LongText, Nullable: true},
+}
+
+// doltCIRun is the stored procedure version for the CLI command `dolt ci run`. It returns one row per workflow step.
+func doltCIRun(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	res, err := doDoltCIRun(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]sql.Row, len(res.Steps))
+	for i, step := range res.Steps {
+		status := ciRunStatusPassed
+		var message interface{}
+		if !step.Passed {
+			status = ciRunStatusFailed
+			message = step.Message
+		}
+		rows[i] = sql.Row{step.JobName, step.StepName, step.SavedQueryName, status, message}
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+func doDoltCIRun(ctx *sql.Context, args []string) (*dolt_ci.WorkflowRunResult, error) {
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return nil, fmt.Errorf("Empty database name.")
+	}
+
+	apr, err := cli.CreateCIRunArgParser().Parse(args)
+	if err != nil {
+		return nil, err
+	}
+	if apr.NArg() < 1 {
+		return nil, fmt.Errorf("error: missing workflow name")
+	}
+	workflowName := apr.Arg(0)
+	revision := ""
+	if apr.NArg() > 1 {
+		revision = apr.Arg(1)
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	sqlDb, err := dSess.Provider().Database(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	db, ok := sqlDb.(dsess.SqlDatabase)
+	if !ok {
+		return nil, fmt.Errorf("unexpected database type: %T", sqlDb)
+	}
+
+	hasTables, err := dolt_ci.HasDoltCITables(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !hasTables {
+		return nil, fmt.Errorf("dolt ci has not been initialized, please initialize with: dolt ci init")
+	}
+
+	wm := dolt_ci.NewWorkflowManager(dSess.Username(), dSess.Email(), dsess.RunQuery)
+	return wm.RunWorkflow(ctx, db, workflowName, revision)
+}
@@ -45,7 +45,7 @@ func doltClone(ctx *sql.Context, args ...string) (sql.RowIter, error) {
 	}
 
 	sess := dsess.DSessFromSess(ctx.Session)
-	_, remoteUrl, err := env.GetAbsRemoteUrl(sess.Provider().FileSystem(), emptyConfig(), urlStr)
+	scheme, remoteUrl, err := env.GetAbsRemoteUrl(sess.Provider().FileSystem(), emptyConfig(), urlStr)
 	if err != nil {
 		return nil, errhand.BuildDError("error: '%s' is not valid.", urlStr).Build()
 	}
@@ -56,13 +56,23 @@ func doltClone(ctx *sql.Context, args ...string) (sql.RowIter, error) {
 	if user, hasUser := apr.GetValue(cli.UserFlag); hasUser {
 		remoteParms[dbfactory.GRPCUsernameAuthParam] = user
 	}
+	if err = cli.AddEncryptionParams(scheme, apr, remoteParms); err != nil {
+		return nil, err
+	}
 
 	depth, ok := apr.GetInt(cli.DepthFlag)
 	if !ok {
 		depth = -1
 	}
 
-	err = sess.Provider().CloneDatabaseFromRemote(ctx, dir, branch, remoteName, remoteUrl, depth, remoteParms)
+	tables, _ := apr.GetValueList(cli.TablesFlag)
+	excludeTables, _ := apr.GetValueList(cli.ExcludeTablesFlag)
+	tableFilter, err := env.NewTableFilter(tables, excludeTables)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sess.Provider().CloneDatabaseFromRemote(ctx, dir, branch, remoteName, remoteUrl, depth, tableFilter, remoteParms)
 	if err != nil {
 		return nil, err
 	}
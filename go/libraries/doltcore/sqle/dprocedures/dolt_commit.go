@@ -17,7 +17,6 @@ package dprocedures
 import (
 	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/types"
@@ -28,8 +27,8 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
-	"github.com/dolthub/dolt/go/libraries/utils/gpg"
 	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
 )
 
 // doltCommit is the stored procedure version for the CLI command `dolt commit`.
@@ -66,6 +65,9 @@ func doDoltCommit(ctx *sql.Context, args []string) (string, bool, error) {
 	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
 		return "", false, err
 	}
+	if err := branch_control.CheckProtection(ctx); err != nil {
+		return "", false, err
+	}
 	// Get the information for the sql context.
 	dbName := ctx.GetCurrentDatabase()
 
@@ -195,12 +197,16 @@ func doDoltCommit(ctx *sql.Context, args []string) (string, bool, error) {
 			}
 		}
 
-		strToSign, err := commitSignatureStr(ctx, dbName, roots, csp)
+		strToSign, err := commitSignatureStr(ctx, dSess, dbName, roots, pendingCommit, csp)
 		if err != nil {
 			return "", false, err
 		}
 
-		signature, err := gpg.Sign(ctx, keyId, []byte(strToSign))
+		keys, err := dsess.GetSigningKeys(ctx)
+		if err != nil {
+			return "", false, err
+		}
+		signature, err := keys.Sign(ctx, keyId, []byte(strToSign))
 		if err != nil {
 			return "", false, err
 		}
@@ -242,26 +248,30 @@ func getDoltArgs(ctx *sql.Context, row sql.Row, children []sql.Expression) ([]st
 	return args, nil
 }
 
-func commitSignatureStr(ctx *sql.Context, dbName string, roots doltdb.Roots, csp actions.CommitStagedProps) (string, error) {
-	var lines []string
-	lines = append(lines, fmt.Sprint("db: ", dbName))
-	lines = append(lines, fmt.Sprint("Message: ", csp.Message))
-	lines = append(lines, fmt.Sprint("Name: ", csp.Name))
-	lines = append(lines, fmt.Sprint("Email: ", csp.Email))
-	lines = append(lines, fmt.Sprint("Date: ", csp.Date.String()))
-
-	head, err := roots.Head.HashOf()
+func commitSignatureStr(ctx *sql.Context, dSess *dsess.DoltSession, dbName string, roots doltdb.Roots, pendingCommit *doltdb.PendingCommit, csp actions.CommitStagedProps) (string, error) {
+	staged, err := roots.Staged.HashOf()
 	if err != nil {
 		return "", err
 	}
 
-	staged, err := roots.Staged.HashOf()
+	parents, err := pendingCommitParents(ctx, dSess, dbName, pendingCommit)
 	if err != nil {
 		return "", err
 	}
 
-	lines = append(lines, fmt.Sprint("Head: ", head.String()))
-	lines = append(lines, fmt.Sprint("Staged: ", staged.String()))
+	return doltdb.CommitSignaturePayload(dbName, csp.Message, csp.Name, csp.Email, csp.Date, parents, staged), nil
+}
 
-	return strings.Join(lines, "\n"), nil
+// pendingCommitParents returns the parents |pendingCommit| will have when it is committed to the current branch of
+// |dbName|
+func pendingCommitParents(ctx *sql.Context, dSess *dsess.DoltSession, dbName string, pendingCommit *doltdb.PendingCommit) ([]hash.Hash, error) {
+	headRef, err := dSess.CWBHeadRef(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	dbData, ok := dSess.GetDbData(ctx, dbName)
+	if !ok {
+		return nil, fmt.Errorf("could not load database %s", dbName)
+	}
+	return dbData.Ddb.PendingCommitParents(ctx, headRef, pendingCommit)
 }
@@ -74,6 +74,11 @@ func doDoltFetch(ctx *sql.Context, args []string) (int, error) {
 		return cmdFailure, err
 	}
 
+	filterChanged, err := updateRemoteTableFilter(apr, dbData, &remote)
+	if err != nil {
+		return cmdFailure, err
+	}
+
 	if user, hasUser := apr.GetValue(cli.UserFlag); hasUser {
 		remote = remote.WithParams(map[string]string{
 			dbfactory.GRPCUsernameAuthParam: user,
@@ -91,9 +96,41 @@ func doDoltFetch(ctx *sql.Context, args []string) (int, error) {
 	if err != nil {
 		return cmdFailure, fmt.Errorf("fetch failed: %w", err)
 	}
+
+	if filterChanged {
+		err = actions.FetchTables(ctx, dbData, srcDB, remote.TableFilter, runProgFuncs, stopProgFuncs)
+		if err != nil {
+			return cmdFailure, fmt.Errorf("fetch failed: %w", err)
+		}
+	}
 	return cmdSuccess, nil
 }
 
+// updateRemoteTableFilter applies the --tables and --exclude-tables arguments of a fetch or pull to the table filter of
+// |remote|, and persists the result so that later fetches from the remote use it too. Returns whether the arguments
+// were given, in which case the tables they select which were skipped by earlier fetches should be fetched.
+func updateRemoteTableFilter(apr *argparser.ArgParseResults, dbData env.DbData, remote *env.Remote) (bool, error) {
+	tables, _ := apr.GetValueList(cli.TablesFlag)
+	excludeTables, _ := apr.GetValueList(cli.ExcludeTablesFlag)
+	filter, err := env.NewTableFilter(tables, excludeTables)
+	if err != nil {
+		return false, err
+	}
+	if filter.IsEmpty() {
+		return false, nil
+	}
+
+	remote.TableFilter, err = remote.TableFilter.Merge(filter)
+	if err != nil {
+		return false, err
+	}
+	err = dbData.Rsw.UpdateRemote(*remote)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // validateFetchArgs returns an error if the arguments provided aren't valid.
 func validateFetchArgs(apr *argparser.ArgParseResults, refSpecArgs []string) error {
 	if len(refSpecArgs) > 0 && apr.Contains(cli.PruneFlag) {
@@ -30,6 +30,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/branchprotection"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
@@ -174,17 +175,60 @@ func doDoltMerge(ctx *sql.Context, args []string) (string, int, int, string, err
 		msg = userMsg
 	}
 
+	rule, protected := branch_control.GetProtection(ctx, dbName, headRef.GetPath())
+	if protected {
+		ctx, err = allowProtectedMerge(ctx, sess, dbName, apr, rule, headRef.GetPath(), branchName, mergeSpec)
+		if err != nil {
+			return "", noConflictsOrViolations, threeWayMerge, "", err
+		}
+	}
+
 	ws, commit, conflicts, fastForward, message, err := performMerge(ctx, sess, ws, dbName, mergeSpec, apr.Contains(cli.NoCommitFlag), msg)
 	if err != nil {
 		return commit, conflicts, fastForward, "", err
 	}
 	if conflicts != 0 {
+		if protected {
+			// conflicts could only be resolved by writing to the protected branch directly
+			return commit, conflicts, fastForward, "", branch_control.ErrProtectedBranchMerge.New(branchName, headRef.GetPath(), "the merge has conflicts or constraint violations")
+		}
 		return commit, conflicts, fastForward, "conflicts found", nil
 	}
 
 	return commit, conflicts, fastForward, message, nil
 }
 
+// allowProtectedMerge checks that |branchName| may be merged into the protected branch |targetBranch| according to
+// |rule|, and returns a context which may write to |targetBranch| to perform the merge. Merges into a protected branch
+// must be committed by the merge itself, as the branch may not be written to directly afterward.
+func allowProtectedMerge(
+	ctx *sql.Context,
+	sess *dsess.DoltSession,
+	dbName string,
+	apr *argparser.ArgParseResults,
+	rule branch_control.ProtectionValue,
+	targetBranch string,
+	branchName string,
+	spec *merge.MergeSpec,
+) (*sql.Context, error) {
+	if apr.Contains(cli.NoCommitFlag) || apr.Contains(cli.SquashParam) {
+		return nil, branch_control.ErrProtectedBranchMerge.New(branchName, targetBranch,
+			fmt.Sprintf("merges into protected branches may not use --%s or --%s", cli.NoCommitFlag, cli.SquashParam))
+	}
+	sqlDb, err := sess.Provider().Database(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	db, ok := sqlDb.(dsess.SqlDatabase)
+	if !ok {
+		return nil, fmt.Errorf("unexpected database type: %T", sqlDb)
+	}
+	if err = branchprotection.CheckMerge(ctx, db, rule, targetBranch, branchName, spec.MergeC); err != nil {
+		return nil, err
+	}
+	return ctx.WithContext(branch_control.AllowProtectedMerge(ctx.Context, dbName, targetBranch)), nil
+}
+
 // performMerge encapsulates server merge logic, switching between
 // fast-forward, no fast-forward, merge commit, and merging into working set.
 // Returns a new WorkingSet, whether there were merge conflicts, and whether a
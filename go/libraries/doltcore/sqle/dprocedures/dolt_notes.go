@@ -0,0 +1,117 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// doltNotes is the stored procedure version for the CLI command `dolt notes`.
+func doltNotes(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	res, err := doDoltNotes(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(res)), nil
+}
+
+// doDoltNotes is used as sql dolt_notes command for only adding or removing notes, not showing them.
+// To read/select notes, dolt_notes system table is used.
+func doDoltNotes(ctx *sql.Context, args []string) (int, error) {
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return 1, fmt.Errorf("Empty database name.")
+	}
+	dSess := dsess.DSessFromSess(ctx.Session)
+	dbData, ok := dSess.GetDbData(ctx, dbName)
+	if !ok {
+		return 1, fmt.Errorf("Could not load database %s", dbName)
+	}
+
+	apr, err := cli.CreateNotesArgParser().Parse(args)
+	if err != nil {
+		return 1, err
+	}
+
+	if len(apr.Args) == 0 {
+		return 1, fmt.Errorf("error: missing subcommand, expected add or remove")
+	}
+
+	headRef, err := dbData.Rsr.CWBHeadRef()
+	if err != nil {
+		return 1, err
+	}
+
+	subcommand, startPoints := apr.Arg(0), apr.Args[1:]
+	switch subcommand {
+	case "add":
+		if len(startPoints) > 1 {
+			return 1, fmt.Errorf("add note takes at most one commit")
+		}
+		msg, ok := apr.GetValue(cli.MessageArg)
+		if !ok {
+			return 1, fmt.Errorf("error: a note message must be given with -m")
+		}
+
+		var name, email string
+		if authorStr, ok := apr.GetValue(cli.AuthorParam); ok {
+			name, email, err = cli.ParseAuthor(authorStr)
+			if err != nil {
+				return 1, err
+			}
+		} else {
+			name = dSess.Username()
+			email = dSess.Email()
+		}
+
+		startPoint := "head"
+		if len(startPoints) == 1 {
+			startPoint = startPoints[0]
+		}
+		props := actions.NoteProps{
+			AuthorName:  name,
+			AuthorEmail: email,
+			Note:        msg,
+			Force:       apr.Contains(cli.ForceFlag),
+		}
+		err = actions.AddNoteOnDB(ctx, dbData.Ddb, startPoint, props, headRef)
+		if err != nil {
+			return 1, err
+		}
+	case "remove":
+		if apr.Contains(cli.MessageArg) {
+			return 1, fmt.Errorf("remove and note message options are incompatible")
+		}
+		if len(startPoints) == 0 {
+			startPoints = []string{"head"}
+		}
+		err = actions.RemoveNotesOnDB(ctx, dbData.Ddb, headRef, startPoints...)
+		if err != nil {
+			return 1, err
+		}
+	case "show", "list":
+		return 1, fmt.Errorf("error: invalid argument, use 'dolt_notes' system table to %s notes", subcommand)
+	default:
+		return 1, fmt.Errorf("error: unknown subcommand '%s', expected add or remove", subcommand)
+	}
+
+	return 0, nil
+}
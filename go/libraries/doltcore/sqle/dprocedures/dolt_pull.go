@@ -80,6 +80,9 @@ func doDoltPull(ctx *sql.Context, args []string) (int, int, string, error) {
 	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
 		return noConflictsOrViolations, threeWayMerge, "", err
 	}
+	if err := branch_control.CheckProtection(ctx); err != nil {
+		return noConflictsOrViolations, threeWayMerge, "", err
+	}
 
 	sess := dsess.DSessFromSess(ctx.Session)
 	dbData, ok := sess.GetDbData(ctx, dbName)
@@ -121,6 +124,11 @@ func doDoltPull(ctx *sql.Context, args []string) (int, int, string, error) {
 		return noConflictsOrViolations, threeWayMerge, "", err
 	}
 
+	filterChanged, err := updateRemoteTableFilter(apr, dbData, &pullSpec.Remote)
+	if err != nil {
+		return noConflictsOrViolations, threeWayMerge, "", err
+	}
+
 	if user, hasUser := apr.GetValue(cli.UserFlag); hasUser {
 		pullSpec.Remote = pullSpec.Remote.WithParams(map[string]string{
 			dbfactory.GRPCUsernameAuthParam: user,
@@ -158,6 +166,13 @@ func doDoltPull(ctx *sql.Context, args []string) (int, int, string, error) {
 		return noConflictsOrViolations, threeWayMerge, "", fmt.Errorf("fetch failed: %w", err)
 	}
 
+	if filterChanged {
+		err = actions.FetchTables(ctx, dbData, srcDB, pullSpec.Remote.TableFilter, runProgFuncs, stopProgFuncs)
+		if err != nil {
+			return noConflictsOrViolations, threeWayMerge, "", fmt.Errorf("fetch failed: %w", err)
+		}
+	}
+
 	var conflicts int
 	var fastForward int
 	var message string
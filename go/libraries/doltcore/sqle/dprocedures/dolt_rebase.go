@@ -17,13 +17,16 @@ package dprocedures
 import (
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
 	goerrors "gopkg.in/src-d/go-errors.v1"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
 	"github.com/dolthub/dolt/go/libraries/doltcore/cherry_pick"
 	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
@@ -53,7 +56,11 @@ var RebaseActionEnumType = types.MustCreateEnumType([]string{
 	rebase.RebaseActionPick,
 	rebase.RebaseActionReword,
 	rebase.RebaseActionSquash,
-	rebase.RebaseActionFixup}, sql.Collation_Default)
+	rebase.RebaseActionFixup,
+	rebase.RebaseActionEdit,
+	rebase.RebaseActionExec,
+	rebase.RebaseActionBreak,
+	rebase.RebaseActionMerge}, sql.Collation_Default)
 
 // GetDoltRebaseSystemTableSchema returns the schema for the dolt_rebase system table.
 // This is used by Doltgres to update the dolt_rebase schema using Doltgres types.
@@ -123,6 +130,24 @@ var ErrRebaseConflictWithAbortError = goerrors.NewKind(
 	"merge conflict detected while rebasing commit %s. " +
 		"attempted to abort rebase operation, but encountered error: %w")
 
+// ErrRebaseExecFailed is used when the SQL for an exec step in a rebase plan fails.
+var ErrRebaseExecFailed = goerrors.NewKind("exec failed while rebasing: %s\n%s\n\n" +
+	"Fix the problem, then continue the rebase by calling dolt_rebase('--continue')")
+
+// ErrRebaseExecLeftChanges is used when the SQL for an exec step in a rebase plan runs successfully, but leaves
+// uncommitted changes in the working set.
+var ErrRebaseExecLeftChanges = goerrors.NewKind("exec succeeded while rebasing but left uncommitted changes: %s\n\n" +
+	"Commit or reset the changes, then continue the rebase by calling dolt_rebase('--continue')")
+
+// ErrRebaseStagedChangesAfterStop is used when a rebase is continued after stopping at an exec or break step, but
+// there are staged changes that haven't been committed.
+var ErrRebaseStagedChangesAfterStop = goerrors.NewKind("cannot continue a rebase with staged changes after a %s step. " +
+	"Use dolt_commit() to commit the changes and then continue the rebase")
+
+// RebaseStoppedMessage is used when a rebase stops at an edit or break step in the rebase plan, so that the caller
+// can make changes before continuing the rebase.
+var RebaseStoppedMessage = "Stopped rebasing"
+
 // SuccessfulRebaseMessage is used when a rebase finishes successfully. The branch that was rebased should be appended
 // to the end of the message.
 var SuccessfulRebaseMessage = "Successfully rebased and updated refs/heads/"
@@ -157,11 +182,11 @@ func doDoltRebase(ctx *sql.Context, args []string) (int, string, error) {
 		}
 
 	case apr.Contains(cli.ContinueFlag):
-		rebaseBranch, err := continueRebase(ctx)
+		message, err := continueRebase(ctx)
 		if err != nil {
 			return 1, "", err
 		} else {
-			return 0, SuccessfulRebaseMessage + rebaseBranch, nil
+			return 0, message, nil
 		}
 
 	default:
@@ -182,7 +207,8 @@ func doDoltRebase(ctx *sql.Context, args []string) (int, string, error) {
 		if !apr.Contains(cli.InteractiveFlag) {
 			return 1, "", fmt.Errorf("non-interactive rebases not currently supported")
 		}
-		err = startRebase(ctx, apr.Arg(0), commitBecomesEmptyHandling, emptyCommitHandling)
+		onto, _ := apr.GetValue(cli.OntoParam)
+		err = startRebase(ctx, apr.Arg(0), onto, apr.Contains(cli.RebaseMergesFlag), commitBecomesEmptyHandling, emptyCommitHandling)
 		if err != nil {
 			return 1, "", err
 		}
@@ -222,17 +248,25 @@ func processCommitBecomesEmptyParams(apr *argparser.ArgParseResults) (doltdb.Emp
 }
 
 // startRebase starts a new interactive rebase operation. |upstreamPoint| specifies the commit where the new rebased
-// commits will be based off of, |commitBecomesEmptyHandling| specifies how to  handle commits that are not empty, but
-// do not produce any changes when applied, and |emptyCommitHandling| specifies how to handle empty commits.
-func startRebase(ctx *sql.Context, upstreamPoint string, commitBecomesEmptyHandling doltdb.EmptyCommitHandling, emptyCommitHandling doltdb.EmptyCommitHandling) error {
+// commits will be based off of, unless |ontoPoint| is specified, in which case the commits reachable from the current
+// branch but not from |upstreamPoint| are rebased onto |ontoPoint| instead. |rebaseMerges| specifies whether merge
+// commits are kept in the rebase plan, |commitBecomesEmptyHandling| specifies how to  handle commits that are not
+// empty, but do not produce any changes when applied, and |emptyCommitHandling| specifies how to handle empty commits.
+func startRebase(ctx *sql.Context, upstreamPoint, ontoPoint string, rebaseMerges bool, commitBecomesEmptyHandling doltdb.EmptyCommitHandling, emptyCommitHandling doltdb.EmptyCommitHandling) error {
 	if upstreamPoint == "" {
 		return fmt.Errorf("no upstream branch specified")
 	}
+	if ontoPoint == "" {
+		ontoPoint = upstreamPoint
+	}
 
 	err := validateWorkingSetCanStartRebase(ctx)
 	if err != nil {
 		return err
 	}
+	if err = branch_control.CheckProtection(ctx); err != nil {
+		return err
+	}
 
 	doltSession := dsess.DSessFromSess(ctx.Session)
 	headRef, err := doltSession.CWBHeadRef(ctx, ctx.GetCurrentDatabase())
@@ -268,11 +302,27 @@ func startRebase(ctx *sql.Context, upstreamPoint string, commitBecomesEmptyHandl
 		return doltdb.ErrGhostCommitEncountered
 	}
 
+	ontoCommit := upstreamCommit
+	if ontoPoint != upstreamPoint {
+		ontoSpec, err := doltdb.NewCommitSpec(ontoPoint)
+		if err != nil {
+			return err
+		}
+		optCmt, err = dbData.Ddb.Resolve(ctx, ontoSpec, headRef)
+		if err != nil {
+			return err
+		}
+		ontoCommit, ok = optCmt.ToCommit()
+		if !ok {
+			return doltdb.ErrGhostCommitEncountered
+		}
+	}
+
 	// rebaseWorkingBranch is the name of the temporary branch used when performing a rebase. In Git, a rebase
 	// happens with a detached HEAD, but Dolt doesn't support that, we use a temporary branch.
 	rebaseWorkingBranch := "dolt_rebase_" + rebaseBranch
 	var rsc doltdb.ReplicationStatusController
-	err = actions.CreateBranchWithStartPt(ctx, dbData, rebaseWorkingBranch, upstreamPoint, false, &rsc)
+	err = actions.CreateBranchWithStartPt(ctx, dbData, rebaseWorkingBranch, ontoPoint, false, &rsc)
 	if err != nil {
 		return err
 	}
@@ -311,7 +361,7 @@ func startRebase(ctx *sql.Context, upstreamPoint string, commitBecomesEmptyHandl
 		return err
 	}
 
-	newWorkingSet, err := workingSet.StartRebase(ctx, upstreamCommit, rebaseBranch, branchRoots.Working,
+	newWorkingSet, err := workingSet.StartRebase(ctx, ontoCommit, rebaseBranch, branchRoots.Working,
 		commitBecomesEmptyHandling, emptyCommitHandling)
 	if err != nil {
 		return err
@@ -323,7 +373,7 @@ func startRebase(ctx *sql.Context, upstreamPoint string, commitBecomesEmptyHandl
 	}
 
 	// Create the rebase plan and save it in the database
-	rebasePlan, err := rebase.CreateDefaultRebasePlan(ctx, startCommit, upstreamCommit)
+	rebasePlan, err := rebase.CreateDefaultRebasePlan(ctx, startCommit, upstreamCommit, rebaseMerges)
 	if err != nil {
 		abortErr := abortRebase(ctx)
 		if abortErr != nil {
@@ -592,6 +642,9 @@ func recordCurrentStep(ctx *sql.Context, step rebase.RebasePlanStep) error {
 	return nil
 }
 
+// continueRebase executes the remaining steps of the rebase plan and returns a message describing the result. If the
+// rebase plan stops at an edit or break step, the returned message describes how to continue the rebase, otherwise the
+// rebased branch is updated and the returned message reports that the rebase finished successfully.
 func continueRebase(ctx *sql.Context) (string, error) {
 	// Validate that we are in an interactive rebase
 	if err := validateActiveRebase(ctx); err != nil {
@@ -655,7 +708,10 @@ func continueRebase(ctx *sql.Context) (string, error) {
 
 		// If we've already executed this step, but the working set has staged changes,
 		// then we need to make the commit for the manual changes made for this step.
-		if rebasingStarted && rebaseStepOrder == lastAttemptedStep && hasStagedChanges {
+		// A merge step always needs its merge commit, even if resolving the conflicts
+		// left nothing to stage.
+		mergeStepPending := step.Action == rebase.RebaseActionMerge && workingSet.MergeActive()
+		if rebasingStarted && rebaseStepOrder == lastAttemptedStep && (hasStagedChanges || mergeStepPending) {
 			if err = commitManuallyStagedChangesForStep(ctx, step); err != nil {
 				return "", err
 			}
@@ -681,6 +737,17 @@ func continueRebase(ctx *sql.Context) (string, error) {
 			if err != nil {
 				return "", err
 			}
+
+			// Edit and break steps pause the rebase so the caller can make changes before continuing
+			switch step.Action {
+			case rebase.RebaseActionEdit:
+				return fmt.Sprintf("%s at commit %s (%s). Amend the commit with dolt_commit('--amend') or "+
+					"stage changes to amend it with, then continue rebasing by calling dolt_rebase('--continue')",
+					RebaseStoppedMessage, step.CommitHash, step.CommitMsg), nil
+			case rebase.RebaseActionBreak:
+				return fmt.Sprintf("%s at break. Continue rebasing by calling dolt_rebase('--continue')",
+					RebaseStoppedMessage), nil
+			}
 		}
 
 		// Ensure a transaction has been started, so that the session is in sync with the latest changes
@@ -742,15 +809,24 @@ func continueRebase(ctx *sql.Context) (string, error) {
 	if !ok {
 		return "", fmt.Errorf("unable to lookup dbdata")
 	}
-	return rebaseBranch, actions.DeleteBranch(ctx, dbData, rebaseWorkingBranch, actions.DeleteOptions{
+	err = actions.DeleteBranch(ctx, dbData, rebaseWorkingBranch, actions.DeleteOptions{
 		Force: true,
 	}, doltSession.Provider(), nil)
+	if err != nil {
+		return "", err
+	}
+	return SuccessfulRebaseMessage + rebaseBranch, nil
 }
 
 // commitManuallyStagedChangesForStep handles committing staged changes after a conflict has been manually
-// resolved by the caller before rebasing has been continued. This involves building the correct commit
-// message based on the details of the rebase plan |step| and then creating the commit.
+// resolved by the caller before rebasing has been continued, or after the rebase stopped to edit a commit.
+// This involves building the correct commit message based on the details of the rebase plan |step| and then
+// creating the commit.
 func commitManuallyStagedChangesForStep(ctx *sql.Context, step rebase.RebasePlanStep) error {
+	if !step.AppliesCommit() {
+		return ErrRebaseStagedChangesAfterStop.New(step.Action)
+	}
+
 	doltSession := dsess.DSessFromSess(ctx.Session)
 	workingSet, err := doltSession.WorkingSet(ctx, ctx.GetCurrentDatabase())
 	if err != nil {
@@ -765,13 +841,36 @@ func commitManuallyStagedChangesForStep(ctx *sql.Context, step rebase.RebasePlan
 		return err
 	}
 
-	// If the commit message wasn't set when we created the cherry-pick options, then set it to the step's commit
-	// message. For fixup commits, we don't use their commit message, so we keep it empty, and let the amend commit
-	// codepath use the previous commit's message.
-	if commitProps.Message == "" && step.Action != rebase.RebaseActionFixup {
+	// If the rebase stopped to edit a commit that applied cleanly, then the commit already exists, so the staged
+	// changes are amended into it, keeping its current commit message.
+	if step.Action == rebase.RebaseActionEdit && !workingSet.MergeActive() {
+		commitProps.Amend = true
+	} else if commitProps.Message == "" && step.Action != rebase.RebaseActionFixup {
+		// If the commit message wasn't set when we created the cherry-pick options, then set it to the step's commit
+		// message. For fixup commits, we don't use their commit message, so we keep it empty, and let the amend commit
+		// codepath use the previous commit's message.
 		commitProps.Message = step.CommitMsg
 	}
 
+	// A replayed merge commit records the merge of its second parent, even when it doesn't change any data, and
+	// keeps the author and date of the original merge commit
+	if step.Action == rebase.RebaseActionMerge {
+		commitProps.AllowEmpty = true
+		commitProps.SkipEmpty = false
+
+		mergeCommit, err := resolveRebaseStepCommit(ctx, &step)
+		if err != nil {
+			return err
+		}
+		meta, err := mergeCommit.GetCommitMeta(ctx)
+		if err != nil {
+			return err
+		}
+		commitProps.Name = meta.Name
+		commitProps.Email = meta.Email
+		commitProps.Date = meta.Time()
+	}
+
 	roots, ok := doltSession.GetRoots(ctx, ctx.GetCurrentDatabase())
 	if !ok {
 		return fmt.Errorf("unable to get roots for current session")
@@ -804,9 +903,14 @@ func processRebasePlanStep(ctx *sql.Context, planStep *rebase.RebasePlanStep,
 		}
 	}
 
-	// If the action is "drop", then we don't need to do anything
-	if planStep.Action == rebase.RebaseActionDrop {
+	switch planStep.Action {
+	case rebase.RebaseActionDrop, rebase.RebaseActionBreak:
+		// If the action is "drop" or "break", then we don't need to do anything
 		return nil
+	case rebase.RebaseActionExec:
+		return executeRebaseExecStep(ctx, planStep)
+	case rebase.RebaseActionMerge:
+		return handleRebaseMerge(ctx, planStep)
 	}
 
 	options, err := createCherryPickOptionsForRebaseStep(ctx, planStep, commitBecomesEmptyHandling, emptyCommitHandling)
@@ -825,11 +929,11 @@ func createCherryPickOptionsForRebaseStep(ctx *sql.Context, planStep *rebase.Reb
 	options.EmptyCommitHandling = emptyCommitHandling
 
 	switch planStep.Action {
-	case rebase.RebaseActionDrop, rebase.RebaseActionPick:
-		// Nothing to do – the drop action doesn't result in a cherry pick and the pick action
-		// doesn't require any special options (i.e. no amend, no custom commit message).
+	case rebase.RebaseActionDrop, rebase.RebaseActionPick, rebase.RebaseActionEdit:
+		// Nothing to do – the drop action doesn't result in a cherry pick and the pick and edit actions
+		// don't require any special options (i.e. no amend, no custom commit message).
 
-	case rebase.RebaseActionReword:
+	case rebase.RebaseActionReword, rebase.RebaseActionMerge:
 		options.CommitMessage = planStep.CommitMsg
 
 	case rebase.RebaseActionSquash:
@@ -903,6 +1007,150 @@ func handleRebaseCherryPick(ctx *sql.Context, planStep *rebase.RebasePlanStep, o
 	return err
 }
 
+// executeRebaseExecStep runs the SQL statements of the exec |planStep|. An error is returned if any statement fails,
+// if a stored procedure returns a non-zero status, or if the statements leave uncommitted changes in the working set.
+func executeRebaseExecStep(ctx *sql.Context, planStep *rebase.RebasePlanStep) error {
+	statements, err := sqlparser.SplitStatementToPieces(planStep.CommitMsg)
+	if err != nil {
+		return ErrRebaseExecFailed.New(planStep.CommitMsg, err.Error())
+	}
+
+	for _, statement := range statements {
+		if strings.TrimSpace(statement) == "" {
+			continue
+		}
+		if err = runRebaseExecStatement(ctx, statement); err != nil {
+			return ErrRebaseExecFailed.New(statement, err.Error())
+		}
+	}
+
+	// The statements may have committed the session's transaction, so start a new one to make sure the session is in
+	// sync with the latest changes on the branch before checking the working set
+	doltSession := dsess.DSessFromSess(ctx.Session)
+	if doltSession.GetTransaction() == nil {
+		if _, err = doltSession.StartTransaction(ctx, sql.ReadWrite); err != nil {
+			return err
+		}
+	}
+
+	hasStagedChanges, hasUnstagedChanges, err := workingSetStatus(ctx)
+	if err != nil {
+		return err
+	}
+	if hasStagedChanges || hasUnstagedChanges {
+		return ErrRebaseExecLeftChanges.New(planStep.CommitMsg)
+	}
+	return nil
+}
+
+// runRebaseExecStatement runs |statement| and drains its results. Dolt's stored procedures report failures with a
+// non-zero status column, so a non-zero status in the first column of a result named "status" is returned as an error.
+func runRebaseExecStatement(ctx *sql.Context, statement string) (err error) {
+	schema, rowIter, _, err := dsess.RunQuery(ctx, statement)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		cerr := rowIter.Close(ctx)
+		if err == nil {
+			err = cerr
+		}
+	}()
+
+	checkStatus := len(schema) > 0 && strings.EqualFold(schema[0].Name, "status")
+	for {
+		row, err := rowIter.Next(ctx)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if !checkStatus || len(row) == 0 || row[0] == nil {
+			continue
+		}
+
+		status, _, err := types.Int64.Convert(row[0])
+		if err != nil {
+			return err
+		}
+		if status.(int64) != 0 {
+			return fmt.Errorf("statement returned status %d", status)
+		}
+	}
+}
+
+// handleRebaseMerge replays the merge commit from |planStep| by merging the merge commit's original second parent into
+// HEAD, using the step's commit message for the new merge commit. If a data conflict is detected, then the
+// ErrRebaseDataConflict error is returned.
+func handleRebaseMerge(ctx *sql.Context, planStep *rebase.RebasePlanStep) error {
+	doltSession := dsess.DSessFromSess(ctx.Session)
+	mergeCommit, err := resolveRebaseStepCommit(ctx, planStep)
+	if err != nil {
+		return err
+	}
+	if mergeCommit.NumParents() < 2 {
+		return fmt.Errorf("unable to replay commit %s with the merge action: it is not a merge commit", planStep.CommitHash)
+	}
+	parentHashes, err := mergeCommit.ParentHashes(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The merge commit is created like the commit for a resolved conflict, with the original author and date
+	_, conflicts, _, _, err := doDoltMerge(ctx, []string{"--" + cli.NoFFParam, "--" + cli.NoCommitFlag, parentHashes[1].String()})
+	if err != nil {
+		return err
+	}
+	if conflicts == noConflictsOrViolations {
+		return commitManuallyStagedChangesForStep(ctx, *planStep)
+	}
+
+	if err := validateConflictsCanBeResolved(ctx, planStep); err != nil {
+		return err
+	}
+
+	// Make the conflicts visible to other sessions when @@dolt_allow_commit_conflicts is enabled, the same as
+	// conflicts from cherry-picking a commit
+	allowCommitConflictsEnabled, err := isAllowCommitConflictsEnabled(ctx)
+	if err != nil {
+		return err
+	}
+	if allowCommitConflictsEnabled {
+		if doltSession.GetTransaction() == nil {
+			if _, err = doltSession.StartTransaction(ctx, sql.ReadWrite); err != nil {
+				return err
+			}
+		}
+		if err = doltSession.CommitTransaction(ctx, doltSession.GetTransaction()); err != nil {
+			return err
+		}
+	}
+
+	return ErrRebaseDataConflict.New(planStep.CommitHash, planStep.CommitMsg)
+}
+
+// resolveRebaseStepCommit returns the commit replayed by |planStep|.
+func resolveRebaseStepCommit(ctx *sql.Context, planStep *rebase.RebasePlanStep) (*doltdb.Commit, error) {
+	doltSession := dsess.DSessFromSess(ctx.Session)
+	ddb, ok := doltSession.GetDoltDB(ctx, ctx.GetCurrentDatabase())
+	if !ok {
+		return nil, fmt.Errorf("unable to get doltdb!")
+	}
+	spec, err := doltdb.NewCommitSpec(planStep.CommitHash)
+	if err != nil {
+		return nil, err
+	}
+	optCmt, err := ddb.Resolve(ctx, spec, nil)
+	if err != nil {
+		return nil, err
+	}
+	cm, ok := optCmt.ToCommit()
+	if !ok {
+		return nil, doltdb.ErrGhostCommitEncountered
+	}
+	return cm, nil
+}
+
 // squashCommitMessage looks up the commit at HEAD and the commit identified by |nextCommitHash| and squashes their two
 // commit messages together.
 func squashCommitMessage(ctx *sql.Context, nextCommitHash string) (string, error) {
@@ -47,6 +47,9 @@ func doDoltReset(ctx *sql.Context, args []string) (int, error) {
 	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
 		return 1, err
 	}
+	if err := branch_control.CheckProtection(ctx); err != nil {
+		return 1, err
+	}
 
 	dSess := dsess.DSessFromSess(ctx.Session)
 	dbData, ok := dSess.GetDbData(ctx, dbName)
@@ -48,6 +48,9 @@ func doDoltRevert(ctx *sql.Context, args []string) (int, error) {
 	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
 		return 1, err
 	}
+	if err := branch_control.CheckProtection(ctx); err != nil {
+		return 1, err
+	}
 
 	roots, ok := dSess.GetRoots(ctx, dbName)
 	if !ok {
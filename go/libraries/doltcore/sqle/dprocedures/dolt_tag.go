@@ -92,6 +92,25 @@ func doDoltTag(ctx *sql.Context, args []string) (int, error) {
 		Description: msg,
 	}
 
+	if apr.Contains(cli.SignTagFlag) || apr.Contains(cli.SigningKeyParam) {
+		props.SigningKey = apr.GetValueOrDefault(cli.SigningKeyParam, "")
+		if props.SigningKey == "" {
+			v, err := ctx.GetSessionVariable(ctx, "signingkey")
+			if err != nil && !sql.ErrUnknownSystemVariable.Is(err) {
+				return 1, fmt.Errorf("failed to get signingkey: %w", err)
+			} else if err == nil {
+				props.SigningKey = v.(string)
+			}
+		}
+		if props.SigningKey == "" {
+			return 1, fmt.Errorf("no signing key configured, set the signingkey system variable or use --%s", cli.SigningKeyParam)
+		}
+		props.SigningKeys, err = dsess.GetSigningKeys(ctx)
+		if err != nil {
+			return 1, err
+		}
+	}
+
 	tagName := apr.Arg(0)
 	startPoint := "head"
 	if len(apr.Args) > 1 {
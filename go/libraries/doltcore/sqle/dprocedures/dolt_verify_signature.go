@@ -0,0 +1,152 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+func verifySignatureSchema(nameCol string) []*sql.Column {
+	return []*sql.Column{
+		{
+			Name:     nameCol,
+			Type:     types.LongText,
+			Nullable: false,
+		},
+		{
+			Name:     "status",
+			Type:     types.LongText,
+			Nullable: false,
+		},
+		{
+			Name:     "message",
+			Type:     types.LongText,
+			Nullable: false,
+		},
+	}
+}
+
+var doltVerifyCommitSchema = verifySignatureSchema("commit_hash")
+var doltVerifyTagSchema = verifySignatureSchema("tag_name")
+
+// doltVerifyCommit is the stored procedure version for the CLI command `dolt verify-commit`. It returns a row with the
+// signature status of each commit given.
+func doltVerifyCommit(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	apr, err := cli.CreateVerifyCommitArgParser().Parse(args)
+	if err != nil {
+		return nil, err
+	}
+	if apr.NArg() == 0 {
+		return nil, fmt.Errorf("error: verify-commit requires at least one commit")
+	}
+
+	dbData, err := verifySignatureDbData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	signers, err := dsess.GetAllowedSigners()
+	if err != nil {
+		return nil, err
+	}
+	headRef, err := dbData.Rsr.CWBHeadRef()
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []sql.Row
+	for _, rev := range apr.Args {
+		cs, err := doltdb.NewCommitSpec(rev)
+		if err != nil {
+			return nil, err
+		}
+		optCmt, err := dbData.Ddb.Resolve(ctx, cs, headRef)
+		if err != nil {
+			return nil, err
+		}
+		cm, ok := optCmt.ToCommit()
+		if !ok {
+			return nil, doltdb.ErrGhostCommitEncountered
+		}
+		h, err := cm.HashOf()
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := doltdb.VerifyCommitSignature(ctx, cm, signers)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, sql.NewRow(h.String(), string(v.Status), v.String()))
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// doltVerifyTag is the stored procedure version for the CLI command `dolt verify-tag`. It returns a row with the
+// signature status of each tag given.
+func doltVerifyTag(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	apr, err := cli.CreateVerifyTagArgParser().Parse(args)
+	if err != nil {
+		return nil, err
+	}
+	if apr.NArg() == 0 {
+		return nil, fmt.Errorf("error: verify-tag requires at least one tag")
+	}
+
+	dbData, err := verifySignatureDbData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	signers, err := dsess.GetAllowedSigners()
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []sql.Row
+	for _, name := range apr.Args {
+		t, err := dbData.Ddb.ResolveTag(ctx, ref.NewTagRef(name))
+		if err != nil {
+			return nil, fmt.Errorf("error: tag '%s' not found: %w", name, err)
+		}
+
+		v, err := doltdb.VerifyTagSignature(ctx, t, signers)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, sql.NewRow(t.Name, string(v.Status), v.String()))
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+func verifySignatureDbData(ctx *sql.Context) (dbData env.DbData, err error) {
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return dbData, sql.ErrNoDatabaseSelected.New()
+	}
+	dSess := dsess.DSessFromSess(ctx.Session)
+	dbData, ok := dSess.GetDbData(ctx, dbName)
+	if !ok {
+		return dbData, fmt.Errorf("Could not load database %s", dbName)
+	}
+	return dbData, nil
+}
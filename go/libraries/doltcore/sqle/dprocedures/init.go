@@ -22,8 +22,10 @@ import (
 var DoltProcedures = []sql.ExternalStoredProcedureDetails{
 	{Name: "dolt_add", Schema: int64Schema("status"), Function: doltAdd},
 	{Name: "dolt_backup", Schema: int64Schema("status"), Function: doltBackup, ReadOnly: true, AdminOnly: true},
+	{Name: "dolt_bisect", Schema: doltBisectProcedureSchema, Function: doltBisect, ReadOnly: true},
 	{Name: "dolt_branch", Schema: int64Schema("status"), Function: doltBranch},
 	{Name: "dolt_checkout", Schema: doltCheckoutSchema, Function: doltCheckout, ReadOnly: true},
+	{Name: "dolt_ci_run", Schema: doltCIRunSchema, Function: doltCIRun, ReadOnly: true},
 	{Name: "dolt_cherry_pick", Schema: cherryPickSchema, Function: doltCherryPick},
 	{Name: "dolt_clean", Schema: int64Schema("status"), Function: doltClean},
 	{Name: "dolt_clone", Schema: int64Schema("status"), Function: doltClone, AdminOnly: true},
@@ -46,7 +48,10 @@ var DoltProcedures = []sql.ExternalStoredProcedureDetails{
 	{Name: "dolt_reset", Schema: int64Schema("status"), Function: doltReset},
 	{Name: "dolt_revert", Schema: int64Schema("status"), Function: doltRevert},
 	{Name: "dolt_tag", Schema: int64Schema("status"), Function: doltTag},
+	{Name: "dolt_notes", Schema: int64Schema("status"), Function: doltNotes},
 	{Name: "dolt_verify_constraints", Schema: int64Schema("violations"), Function: doltVerifyConstraints},
+	{Name: "dolt_verify_commit", Schema: doltVerifyCommitSchema, Function: doltVerifyCommit, ReadOnly: true},
+	{Name: "dolt_verify_tag", Schema: doltVerifyTagSchema, Function: doltVerifyTag, ReadOnly: true},
 
 	{Name: "dolt_stats_drop", Schema: statsFuncSchema, Function: statsFunc(statsDrop)},
 	{Name: "dolt_stats_restart", Schema: statsFuncSchema, Function: statsFunc(statsRestart)},
@@ -404,7 +404,8 @@ func (a *AutoIncrementTracker) InitWithRoots(ctx context.Context, roots ...doltd
 			return err
 		}
 
-		err = r.IterTables(ctx, func(tableName doltdb.TableName, table *doltdb.Table, sch schema.Schema) (bool, error) {
+		// tables excluded from a partial clone or fetch can't be written to, so they don't need a sequence
+		_, err = doltdb.IterFetchedTables(ctx, r, func(tableName doltdb.TableName, table *doltdb.Table, sch schema.Schema) (bool, error) {
 			if !schema.HasAutoIncrement(sch) {
 				return false, nil
 			}
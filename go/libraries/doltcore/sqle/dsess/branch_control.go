@@ -18,6 +18,8 @@ import (
 	"context"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/hash"
 )
 
 // CheckAccessForDb checks whether the current user has the given permissions for the given database.
@@ -36,9 +38,6 @@ func CheckAccessForDb(ctx context.Context, db SqlDatabase, flags branch_control.
 		return branch_control.ErrMissingController.New()
 	}
 
-	controller.Access.RWMutex.RLock()
-	defer controller.Access.RWMutex.RUnlock()
-
 	user := branchAwareSession.GetUser()
 	host := branchAwareSession.GetHost()
 
@@ -50,10 +49,56 @@ func CheckAccessForDb(ctx context.Context, db SqlDatabase, flags branch_control.
 	dbName, branch := SplitRevisionDbName(db.RevisionQualifiedName())
 
 	// Get the permissions for the branch, user, and host combination
+	controller.Access.RWMutex.RLock()
 	_, perms := controller.Access.Match(dbName, branch, user, host)
+	controller.Access.RWMutex.RUnlock()
 	// If either the flags match or the user is an admin for this branch, then we allow access
-	if (perms&flags == flags) || (perms&branch_control.Permissions_Admin == branch_control.Permissions_Admin) {
-		return nil
+	if (perms&flags != flags) && (perms&branch_control.Permissions_Admin != branch_control.Permissions_Admin) {
+		return branch_control.ErrIncorrectPermissions.New(user, host, branch)
+	}
+	// Even with the correct permissions, protected branches may not be written to directly
+	if flags&branch_control.Permissions_Write == branch_control.Permissions_Write {
+		return branch_control.CheckBranchProtection(ctx, dbName, branch)
+	}
+	return nil
+}
+
+// CheckReadAccessForDb checks whether the current user may read from the revision of the given database. Branches
+// are checked against branch control, and tags and commits may only be read when they are reachable from a branch the
+// user may read.
+func CheckReadAccessForDb(ctx context.Context, db SqlDatabase) error {
+	dbName, revision := SplitRevisionDbName(db.RevisionQualifiedName())
+	switch db.RevisionType() {
+	case RevisionTypeBranch:
+		return branch_control.CheckReadAccess(ctx, dbName, revision)
+	case RevisionTypeTag, RevisionTypeCommit:
+		if !branch_control.MayHideBranches(ctx) {
+			return nil
+		}
+		ddb := db.DbData().Ddb
+		var addr hash.Hash
+		if db.RevisionType() == RevisionTypeTag {
+			tag, err := ddb.ResolveTag(ctx, ref.NewTagRef(revision))
+			if err != nil {
+				return err
+			}
+			if addr, err = tag.Commit.HashOf(); err != nil {
+				return err
+			}
+		} else {
+			var ok bool
+			if addr, ok = hash.MaybeParse(revision); !ok {
+				return nil
+			}
+		}
+		hidden, err := ddb.IsHiddenCommit(ctx, addr)
+		if err != nil {
+			return err
+		}
+		if hidden {
+			bas := branch_control.GetBranchAwareSession(ctx)
+			return branch_control.ErrCannotReadCommit.New(bas.GetUser(), bas.GetHost(), revision)
+		}
 	}
-	return branch_control.ErrIncorrectPermissions.New(user, host, branch)
+	return nil
 }
@@ -267,6 +267,10 @@ func (e emptyRevisionDatabaseProvider) PurgeDroppedDatabases(ctx *sql.Context) e
 	return nil
 }
 
+func (e emptyRevisionDatabaseProvider) StatementRunner() StatementRunner {
+	return nil
+}
+
 func (e emptyRevisionDatabaseProvider) BaseDatabase(ctx *sql.Context, dbName string) (SqlDatabase, bool) {
 	return nil, false
 }
@@ -307,7 +311,7 @@ func (e emptyRevisionDatabaseProvider) FileSystemForDatabase(dbname string) (fil
 	return nil, nil
 }
 
-func (e emptyRevisionDatabaseProvider) CloneDatabaseFromRemote(ctx *sql.Context, dbName, branch, remoteName, remoteUrl string, depth int, remoteParams map[string]string) error {
+func (e emptyRevisionDatabaseProvider) CloneDatabaseFromRemote(ctx *sql.Context, dbName, branch, remoteName, remoteUrl string, depth int, tableFilter *env.TableFilter, remoteParams map[string]string) error {
 	return nil
 }
 
@@ -63,6 +63,7 @@ type DoltSession struct {
 	mu               *sync.Mutex
 	fs               filesys.Filesys
 	writeSessProv    WriteSessFunc
+	asOfReads        asOfReads
 
 	// If non-nil, this will be returned from ValidateSession.
 	// Used by sqle/cluster to put a session into a terminal err state.
@@ -134,6 +135,19 @@ func (d *DoltSession) StatsProvider() sql.StatsProvider {
 	return d.statsProv
 }
 
+// ErrNoStatementRunner is returned by RunQuery when the provider of a session has no StatementRunner configured.
+var ErrNoStatementRunner = errors.New("no engine is configured to run statements for this session")
+
+// RunQuery runs |query| in the session of |ctx| with the StatementRunner of the session's provider, so that it is
+// executed with the privileges of the session's user.
+func RunQuery(ctx *sql.Context, query string) (sql.Schema, sql.RowIter, *sql.QueryFlags, error) {
+	runner := DSessFromSess(ctx.Session).Provider().StatementRunner()
+	if runner == nil {
+		return nil, nil, nil, ErrNoStatementRunner
+	}
+	return runner.QueryWithBindings(ctx, query, nil, nil, nil)
+}
+
 // DSessFromSess retrieves a dolt session from a standard sql.Session
 func DSessFromSess(sess sql.Session) *DoltSession {
 	return sess.(*DoltSession)
@@ -1109,9 +1123,18 @@ func (d *DoltSession) SwitchWorkingSet(
 		return err
 	}
 
+	baseName, _ := SplitRevisionDbName(dbName)
+	// Branches hidden by branch control are treated as nonexistent, and must be rejected before the checked out
+	// revision is changed
+	err = branch_control.CheckReadAccess(ctx, baseName, headRef.GetPath())
+	if branch_control.ErrCannotReadBranch.Is(err) {
+		return sql.ErrDatabaseNotFound.New(baseName + DbRevisionDelimiter + headRef.GetPath())
+	} else if err != nil {
+		return err
+	}
+
 	d.mu.Lock()
 
-	baseName, _ := SplitRevisionDbName(dbName)
 	dbState, ok := d.dbStates[strings.ToLower(baseName)]
 	if !ok {
 		d.mu.Unlock()
@@ -1314,6 +1337,7 @@ func (d *DoltSession) addDB(ctx *sql.Context, db SqlDatabase) error {
 	//  database is deleted out from under a running server
 	branchState.dbData = dbState.DbData
 	adapter := NewSessionStateAdapter(d, db.Name(), dbState.Remotes, dbState.Branches, dbState.Backups)
+	adapter.worktrees, _ = dbState.DbData.Rsr.(env.WorktreeChecker)
 	branchState.dbData.Rsr = adapter
 	branchState.dbData.Rsw = adapter
 	branchState.readOnly = dbState.ReadOnly
@@ -1365,6 +1389,51 @@ func (d *DoltSession) DatabaseCache(ctx *sql.Context) *DatabaseCache {
 	return d.dbCache
 }
 
+// asOfReads records the tables read AS OF a revision by a single query.
+type asOfReads struct {
+	pid       uint64
+	queryTime time.Time
+	tables    map[string]interface{}
+}
+
+// ambiguousAsOf marks a table read as of more than one revision.
+type ambiguousAsOf struct{}
+
+// RecordAsOfRead records that the current query reads |table| of database
+// |db| as of |asOf|.
+func (d *DoltSession) RecordAsOfRead(ctx *sql.Context, db, table string, asOf interface{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.asOfReads.tables == nil || d.asOfReads.pid != ctx.Pid() || !d.asOfReads.queryTime.Equal(ctx.QueryTime()) {
+		d.asOfReads = asOfReads{
+			pid:       ctx.Pid(),
+			queryTime: ctx.QueryTime(),
+			tables:    make(map[string]interface{}),
+		}
+	}
+	key := strings.ToLower(db) + "." + strings.ToLower(table)
+	if prev, ok := d.asOfReads.tables[key]; ok && fmt.Sprint(prev) != fmt.Sprint(asOf) {
+		asOf = ambiguousAsOf{}
+	}
+	d.asOfReads.tables[key] = asOf
+}
+
+// AsOfRead returns the revision that the current query reads |table| of
+// database |db| as of. It returns false if the query does not read the
+// table AS OF a revision, or reads it as of more than one revision.
+func (d *DoltSession) AsOfRead(ctx *sql.Context, db, table string) (interface{}, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.asOfReads.pid != ctx.Pid() || !d.asOfReads.queryTime.Equal(ctx.QueryTime()) {
+		return nil, false
+	}
+	asOf, ok := d.asOfReads.tables[strings.ToLower(db)+"."+strings.ToLower(table)]
+	if _, ambiguous := asOf.(ambiguousAsOf); !ok || ambiguous {
+		return nil, false
+	}
+	return asOf, true
+}
+
 func (d *DoltSession) AddTemporaryTable(ctx *sql.Context, db string, tbl sql.Table) {
 	d.tempTables[strings.ToLower(db)] = append(d.tempTables[strings.ToLower(db)], tbl)
 }
@@ -1640,6 +1709,13 @@ func validatePersistableSysVar(name string) (sql.SystemVariable, interface{}, er
 	return sysVar, val, nil
 }
 
+// configOnlySysVars are read only system variables that can still be persisted in the dolt config. They name files on
+// the server, so they can't be set by SQL clients.
+var configOnlySysVars = map[string]struct{}{
+	AllowedSigners: {},
+	SigningKeys:    {},
+}
+
 // getPersistedValue reads and converts a config value to the associated MysqlSystemVariable type
 func getPersistedValue(conf config.ReadableConfig, k string) (interface{}, error) {
 	v, err := conf.GetString(k)
@@ -1647,8 +1723,12 @@ func getPersistedValue(conf config.ReadableConfig, k string) (interface{}, error
 		return nil, err
 	}
 
-	_, value, err := validatePersistableSysVar(k)
-	if err != nil {
+	var value interface{}
+	if _, ok := configOnlySysVars[strings.ToLower(k)]; ok {
+		if _, value, ok = sql.SystemVariables.GetGlobal(k); !ok {
+			return nil, sql.ErrUnknownSystemVariable.New(k)
+		}
+	} else if _, value, err = validatePersistableSysVar(k); err != nil {
 		return nil, err
 	}
 
@@ -35,6 +35,8 @@ type SessionStateAdapter struct {
 	remotes  *concurrentmap.Map[string, env.Remote]
 	backups  *concurrentmap.Map[string, env.Remote]
 	branches *concurrentmap.Map[string, env.BranchConfig]
+	// worktrees is the repo state of the database's environment, if it can have worktrees
+	worktrees env.WorktreeChecker
 }
 
 func (s SessionStateAdapter) SetCWBHeadRef(ctx context.Context, newRef ref.MarshalableRef) error {
@@ -44,6 +46,7 @@ func (s SessionStateAdapter) SetCWBHeadRef(ctx context.Context, newRef ref.Marsh
 var _ env.RepoStateReader = SessionStateAdapter{}
 var _ env.RepoStateWriter = SessionStateAdapter{}
 var _ env.RootsProvider = SessionStateAdapter{}
+var _ env.WorktreeChecker = SessionStateAdapter{}
 
 func NewSessionStateAdapter(session *DoltSession, dbName string, remotes *concurrentmap.Map[string, env.Remote], branches *concurrentmap.Map[string, env.BranchConfig], backups *concurrentmap.Map[string, env.Remote]) SessionStateAdapter {
 	if branches == nil {
@@ -52,6 +55,14 @@ func NewSessionStateAdapter(session *DoltSession, dbName string, remotes *concur
 	return SessionStateAdapter{session: session, dbName: dbName, remotes: remotes, branches: branches, backups: backups}
 }
 
+// CheckBranchNotCheckedOut implements env.WorktreeChecker
+func (s SessionStateAdapter) CheckBranchNotCheckedOut(branch ref.BranchRef) error {
+	if s.worktrees == nil {
+		return nil
+	}
+	return s.worktrees.CheckBranchNotCheckedOut(branch)
+}
+
 func (s SessionStateAdapter) GetRoots(ctx context.Context) (doltdb.Roots, error) {
 	sqlCtx := sql.NewContext(ctx)
 	state, _, err := s.session.lookupDbState(sqlCtx, s.dbName)
@@ -146,6 +157,26 @@ func (s SessionStateAdapter) AddRemote(remote env.Remote) error {
 	return repoState.Save(fs)
 }
 
+func (s SessionStateAdapter) UpdateRemote(remote env.Remote) error {
+	if _, ok := s.remotes.Get(remote.Name); !ok {
+		return env.ErrRemoteNotFound
+	}
+
+	fs, err := s.session.Provider().FileSystemForDatabase(s.dbName)
+	if err != nil {
+		return err
+	}
+
+	repoState, err := env.LoadRepoState(fs)
+	if err != nil {
+		return err
+	}
+
+	s.remotes.Set(remote.Name, remote)
+	repoState.AddRemote(remote)
+	return repoState.Save(fs)
+}
+
 func (s SessionStateAdapter) AddBackup(backup env.Remote) error {
 	if _, ok := s.backups.Get(backup.Name); ok {
 		return env.ErrBackupAlreadyExists
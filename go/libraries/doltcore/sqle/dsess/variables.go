@@ -20,6 +20,8 @@ import (
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/utils/gpg"
 )
 
 // Per-DB system variables
@@ -54,6 +56,8 @@ const (
 	ShowBranchDatabases                  = "dolt_show_branch_databases"
 	DoltLogLevel                         = "dolt_log_level"
 	ShowSystemTables                     = "dolt_show_system_tables"
+	AllowedSigners                       = "allowedsigners"
+	SigningKeys                          = "signingkeys"
 
 	DoltClusterRoleVariable         = "dolt_cluster_role"
 	DoltClusterRoleEpochVariable    = "dolt_cluster_role_epoch"
@@ -65,6 +69,19 @@ const (
 	DoltStatsAutoRefreshInterval  = "dolt_stats_auto_refresh_interval"
 	DoltStatsMemoryOnly           = "dolt_stats_memory_only"
 	DoltStatsBranches             = "dolt_stats_branches"
+	// DoltStatsHistoryEnabled plans queries that read a table AS OF a
+	// commit, or from a revision database, with statistics for the table's
+	// data at that commit instead of the branch's statistics. The first
+	// query to read a table at a commit starts building them in the
+	// background and is planned with the branch's statistics; later
+	// queries use the built statistics, which are cached in memory by
+	// index contents. DOLT_STATS_FOR() builds and returns them directly.
+	DoltStatsHistoryEnabled = "dolt_stats_history_enabled"
+
+	DoltBinlogReplicaBranch             = "dolt_binlog_replica_branch"
+	DoltBinlogReplicaCommitTransactions = "dolt_binlog_replica_commit_transactions"
+	DoltBinlogReplicaCommitInterval     = "dolt_binlog_replica_commit_interval"
+	DoltBinlogReplicaHeartbeatTable     = "dolt_binlog_replica_heartbeat_table"
 )
 
 const URLTemplateDatabasePlaceholder = "{database}"
@@ -187,6 +204,38 @@ func GetBooleanSystemVar(ctx *sql.Context, varName string) (bool, error) {
 	return i8 == int8(1), nil
 }
 
+// GetAllowedSigners loads the allowed signers file named by the allowedsigners system variable, which lists the keys
+// trusted to sign commits and tags. The variable can't be set from SQL, so every session trusts the same keys. If it
+// isn't set, no keys are trusted.
+func GetAllowedSigners() (*gpg.AllowedSigners, error) {
+	_, val, ok := sql.SystemVariables.GetGlobal(AllowedSigners)
+	if !ok {
+		return &gpg.AllowedSigners{}, nil
+	}
+
+	path, _ := val.(string)
+	if path == "" {
+		return &gpg.AllowedSigners{}, nil
+	}
+	return gpg.LoadAllowedSigners(path)
+}
+
+// GetSigningKeys loads the private keys named by the signingkeys system variable, a comma separated list of key files
+// the server may sign commits and tags with. The variable can't be set from SQL, so clients can only choose between the
+// keys configured for the server. If it isn't set, commits are signed with the keys in the server's gpg keyring.
+func GetSigningKeys(ctx *sql.Context) (*gpg.SigningKeys, error) {
+	_, val, ok := sql.SystemVariables.GetGlobal(SigningKeys)
+	if !ok {
+		return nil, nil
+	}
+
+	paths, _ := val.(string)
+	if paths == "" {
+		return nil, nil
+	}
+	return gpg.LoadSigningKeys(strings.Split(paths, ","))
+}
+
 // IgnoreReplicationErrors returns true if the dolt_skip_replication_errors system variable is set to true, which means
 // that errors that occur during replication should be logged and ignored.
 func IgnoreReplicationErrors() bool {
@@ -0,0 +1,774 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtablefunctions
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"golang.org/x/sync/errgroup"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	storetypes "github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+const (
+	changesTableDefaultRowCount = 100
+
+	ChangeTypeInsert = "insert"
+	ChangeTypeUpdate = "update"
+	ChangeTypeDelete = "delete"
+)
+
+var ErrChangesCommitNotInHistory = errors.NewKind("commit %s is not in the first-parent history of branch %s")
+var ErrInvalidChangesCursor = errors.NewKind("invalid cursor: %s")
+
+var _ sql.TableFunction = (*ChangesTableFunction)(nil)
+var _ sql.ExecSourceRel = (*ChangesTableFunction)(nil)
+var _ sql.AuthorizationCheckerNode = (*ChangesTableFunction)(nil)
+
+// ChangesTableFunction is the dolt_changes table function, a change data capture feed of the row changes made by
+// each commit on the first-parent history of a branch. Each row of its result is an insert, update or delete of a
+// row of a table. Changes are ordered by commit, oldest first, then by table name and primary key.
+//
+// Each change has a cursor, made of the hash of its commit and its offset within the changes of that commit. Passing
+// a cursor as the second argument returns the changes following it, so a consumer can resume the feed where it
+// stopped. Passing a commit instead returns the changes of the commits following it.
+type ChangesTableFunction struct {
+	ctx      *sql.Context
+	database sql.Database
+
+	branchExpr sql.Expression
+	fromExpr   sql.Expression
+}
+
+var changesTableSchema = sql.Schema{
+	&sql.Column{Name: "event_cursor", Type: types.Text},
+	&sql.Column{Name: "commit_hash", Type: types.Text},
+	&sql.Column{Name: "event_offset", Type: types.Int64},
+	&sql.Column{Name: "committer", Type: types.Text},
+	&sql.Column{Name: "email", Type: types.Text},
+	&sql.Column{Name: "date", Type: types.Datetime},
+	&sql.Column{Name: "message", Type: types.Text},
+	&sql.Column{Name: "table_name", Type: types.Text},
+	&sql.Column{Name: "change_type", Type: types.Text},
+	&sql.Column{Name: "from_row", Type: types.JSON, Nullable: true},
+	&sql.Column{Name: "to_row", Type: types.JSON, Nullable: true},
+}
+
+// NewInstance creates a new instance of TableFunction interface
+func (ctf *ChangesTableFunction) NewInstance(ctx *sql.Context, db sql.Database, expressions []sql.Expression) (sql.Node, error) {
+	newInstance := &ChangesTableFunction{
+		ctx:      ctx,
+		database: db,
+	}
+
+	node, err := newInstance.WithExpressions(expressions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// Name implements the sql.TableFunction interface
+func (ctf *ChangesTableFunction) Name() string {
+	return "dolt_changes"
+}
+
+// Database implements the sql.Databaser interface
+func (ctf *ChangesTableFunction) Database() sql.Database {
+	return ctf.database
+}
+
+// WithDatabase implements the sql.Databaser interface
+func (ctf *ChangesTableFunction) WithDatabase(database sql.Database) (sql.Node, error) {
+	nctf := *ctf
+	nctf.database = database
+	return &nctf, nil
+}
+
+func (ctf *ChangesTableFunction) DataLength(ctx *sql.Context) (uint64, error) {
+	numBytesPerRow := schema.SchemaAvgLength(ctf.Schema())
+	numRows, _, err := ctf.RowCount(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return numBytesPerRow * numRows, nil
+}
+
+func (ctf *ChangesTableFunction) RowCount(_ *sql.Context) (uint64, bool, error) {
+	return changesTableDefaultRowCount, false, nil
+}
+
+// Expressions implements the sql.Expressioner interface
+func (ctf *ChangesTableFunction) Expressions() []sql.Expression {
+	exprs := []sql.Expression{ctf.branchExpr}
+	if ctf.fromExpr != nil {
+		exprs = append(exprs, ctf.fromExpr)
+	}
+	return exprs
+}
+
+// WithExpressions implements the sql.Expressioner interface
+func (ctf *ChangesTableFunction) WithExpressions(expressions ...sql.Expression) (sql.Node, error) {
+	if len(expressions) < 1 || len(expressions) > 2 {
+		return nil, sql.ErrInvalidArgumentNumber.New(ctf.Name(), "1 or 2", len(expressions))
+	}
+
+	for _, expr := range expressions {
+		if !expr.Resolved() {
+			return nil, ErrInvalidNonLiteralArgument.New(ctf.Name(), expr.String())
+		}
+		// prepared statements resolve functions beforehand, so above check fails
+		if _, ok := expr.(sql.FunctionExpression); ok {
+			return nil, ErrInvalidNonLiteralArgument.New(ctf.Name(), expr.String())
+		}
+	}
+
+	nctf := *ctf
+	nctf.branchExpr = expressions[0]
+	nctf.fromExpr = nil
+	if len(expressions) == 2 {
+		nctf.fromExpr = expressions[1]
+	}
+	return &nctf, nil
+}
+
+// Resolved implements the sql.Resolvable interface
+func (ctf *ChangesTableFunction) Resolved() bool {
+	for _, expr := range ctf.Expressions() {
+		if !expr.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+func (ctf *ChangesTableFunction) IsReadOnly() bool {
+	return true
+}
+
+// String implements the Stringer interface
+func (ctf *ChangesTableFunction) String() string {
+	var args []string
+	for _, expr := range ctf.Expressions() {
+		args = append(args, expr.String())
+	}
+	return fmt.Sprintf("DOLT_CHANGES(%s)", strings.Join(args, ", "))
+}
+
+// Schema implements the sql.Node interface.
+func (ctf *ChangesTableFunction) Schema() sql.Schema {
+	return changesTableSchema
+}
+
+// Children implements the sql.Node interface.
+func (ctf *ChangesTableFunction) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (ctf *ChangesTableFunction) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("unexpected children")
+	}
+	return ctf, nil
+}
+
+// CheckAuth implements the interface sql.AuthorizationCheckerNode.
+func (ctf *ChangesTableFunction) CheckAuth(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	tblNames, err := ctf.database.GetTableNames(ctx)
+	if err != nil {
+		return false
+	}
+
+	var operations []sql.PrivilegedOperation
+	for _, tblName := range tblNames {
+		subject := sql.PrivilegeCheckSubject{Database: ctf.database.Name(), Table: tblName}
+		operations = append(operations, sql.NewPrivilegedOperation(subject, sql.PrivilegeType_Select))
+	}
+
+	return opChecker.UserHasPrivileges(ctx, operations...)
+}
+
+// RowIter implements the sql.Node interface
+func (ctf *ChangesTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	sqlDb, ok := ctf.database.(dsess.SqlDatabase)
+	if !ok {
+		return nil, fmt.Errorf("unexpected database type: %T", ctf.database)
+	}
+	ddb := sqlDb.DbData().Ddb
+	if !storetypes.IsFormat_DOLT(ddb.Format()) {
+		return nil, fmt.Errorf("%s is not supported for the storage format of this database", ctf.Name())
+	}
+
+	branch, err := evalStringArg(ctx, ctf.branchExpr, row)
+	if err != nil {
+		return nil, err
+	}
+	var from string
+	if ctf.fromExpr != nil {
+		from, err = evalStringArg(ctx, ctf.fromExpr, row)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Branches hidden by branch control are read as though they do not exist
+	if err = branch_control.CheckReadAccess(ctx, sqlDb.Name(), branch); branch_control.ErrCannotReadBranch.Is(err) {
+		return nil, fmt.Errorf("%w: %s", doltdb.ErrBranchNotFound, branch)
+	} else if err != nil {
+		return nil, err
+	}
+	branchRef := ref.NewBranchRef(branch)
+	head, err := ddb.ResolveCommitRef(ctx, branchRef)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor := ChangesCursor{Offset: -1}
+	if from != "" {
+		cursor, err = resolveChangesCursor(ctx, ddb, branchRef, from)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	commits, err := firstParentCommitsSince(ctx, head, cursor.Commit)
+	if err != nil {
+		if ErrChangesCommitNotInHistory.Is(err) {
+			return nil, ErrChangesCommitNotInHistory.New(cursor.Commit.String(), branch)
+		}
+		return nil, err
+	}
+
+	// resuming within a commit starts with the changes of that commit
+	if cursor.Offset >= 0 {
+		optCmt, err := ddb.ReadCommit(ctx, cursor.Commit)
+		if err != nil {
+			return nil, err
+		}
+		cm, ok := optCmt.ToCommit()
+		if !ok {
+			return nil, doltdb.ErrGhostCommitEncountered
+		}
+		commits = append([]*doltdb.Commit{cm}, commits...)
+	}
+
+	return &changesRowIter{commits: commits, cursor: cursor}, nil
+}
+
+func evalStringArg(ctx *sql.Context, expr sql.Expression, row sql.Row) (string, error) {
+	v, err := expr.Eval(ctx, row)
+	if err != nil {
+		return "", err
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("argument (%v) is not a string value, but a %T", v, v)
+	}
+	return s, nil
+}
+
+// ChangesCursor is the position of a change in the dolt_changes feed of a branch.
+type ChangesCursor struct {
+	// Commit is the commit which made the change.
+	Commit hash.Hash
+	// Offset is the offset of the change in the changes made by the commit, or -1 for a cursor positioned after every
+	// change of the commit.
+	Offset int64
+}
+
+// String returns the cursor in the form <commit hash>:<offset>, as returned in the event_cursor column of dolt_changes.
+func (c ChangesCursor) String() string {
+	return fmt.Sprintf("%s:%d", c.Commit.String(), c.Offset)
+}
+
+// ParseChangesCursor parses a cursor returned by dolt_changes.
+func ParseChangesCursor(s string) (ChangesCursor, bool) {
+	hashStr, offsetStr, ok := strings.Cut(s, ":")
+	if !ok {
+		return ChangesCursor{}, false
+	}
+	h, ok := hash.MaybeParse(hashStr)
+	if !ok {
+		return ChangesCursor{}, false
+	}
+	offset, err := strconv.ParseInt(offsetStr, 10, 64)
+	if err != nil || offset < 0 {
+		return ChangesCursor{}, false
+	}
+	return ChangesCursor{Commit: h, Offset: offset}, true
+}
+
+// resolveChangesCursor resolves |from|, either a cursor or a commit spec, to the position after which changes are
+// returned.
+func resolveChangesCursor(ctx context.Context, ddb *doltdb.DoltDB, branchRef ref.DoltRef, from string) (ChangesCursor, error) {
+	if strings.Contains(from, ":") {
+		cursor, ok := ParseChangesCursor(from)
+		if !ok {
+			return ChangesCursor{}, ErrInvalidChangesCursor.New(from)
+		}
+		return cursor, nil
+	}
+
+	cs, err := doltdb.NewCommitSpec(from)
+	if err != nil {
+		return ChangesCursor{}, err
+	}
+	optCmt, err := ddb.Resolve(ctx, cs, branchRef)
+	if err != nil {
+		return ChangesCursor{}, err
+	}
+	cm, ok := optCmt.ToCommit()
+	if !ok {
+		return ChangesCursor{}, doltdb.ErrGhostCommitEncountered
+	}
+	h, err := cm.HashOf()
+	if err != nil {
+		return ChangesCursor{}, err
+	}
+	return ChangesCursor{Commit: h, Offset: -1}, nil
+}
+
+// firstParentCommitsSince returns the commits on the first-parent history of |head| after |since|, oldest first. If
+// |since| is empty, every commit of the history is returned.
+func firstParentCommitsSince(ctx context.Context, head *doltdb.Commit, since hash.Hash) ([]*doltdb.Commit, error) {
+	var commits []*doltdb.Commit
+	cm := head
+	for {
+		h, err := cm.HashOf()
+		if err != nil {
+			return nil, err
+		}
+		if h == since {
+			break
+		}
+		commits = append(commits, cm)
+
+		if cm.NumParents() == 0 {
+			if !since.IsEmpty() {
+				return nil, ErrChangesCommitNotInHistory.New(since.String(), "")
+			}
+			break
+		}
+		optCmt, err := cm.GetParent(ctx, 0)
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		cm, ok = optCmt.ToCommit()
+		if !ok {
+			return nil, doltdb.ErrGhostCommitEncountered
+		}
+	}
+
+	for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
+		commits[i], commits[j] = commits[j], commits[i]
+	}
+	return commits, nil
+}
+
+// changeEvent is a row change made by a commit.
+type changeEvent struct {
+	table      string
+	changeType string
+	from, to   sql.JSONWrapper
+}
+
+// changesEventBufferSize is the number of changes of a commit computed ahead of the rows returned by a changesRowIter.
+const changesEventBufferSize = 128
+
+type changesRowIter struct {
+	commits []*doltdb.Commit
+	cursor  ChangesCursor
+
+	commitRow sql.Row
+	commitStr string
+	// events are the changes of the current commit, which are computed by a background goroutine as they are read.
+	events <-chan changeEvent
+	eg     *errgroup.Group
+	cancel context.CancelFunc
+	offset int64
+	skip   int64
+}
+
+var _ sql.RowIter = (*changesRowIter)(nil)
+
+// Next implements the sql.RowIter interface
+func (itr *changesRowIter) Next(ctx *sql.Context) (sql.Row, error) {
+	for {
+		if itr.events == nil {
+			if len(itr.commits) == 0 {
+				return nil, io.EOF
+			}
+			if err := itr.loadCommit(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		var ev changeEvent
+		var ok bool
+		select {
+		case ev, ok = <-itr.events:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if !ok {
+			err := itr.eg.Wait()
+			itr.events, itr.eg = nil, nil
+			itr.cancel()
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		offset := itr.offset
+		itr.offset++
+		if offset < itr.skip {
+			continue
+		}
+		row := sql.Row{fmt.Sprintf("%s:%d", itr.commitStr, offset), itr.commitStr, offset}
+		row = append(row, itr.commitRow...)
+		row = append(row, ev.table, ev.changeType, nullableJSON(ev.from), nullableJSON(ev.to))
+		return row, nil
+	}
+}
+
+func nullableJSON(doc sql.JSONWrapper) interface{} {
+	if doc == nil {
+		return nil
+	}
+	return doc
+}
+
+// loadCommit starts computing the changes of the next commit.
+func (itr *changesRowIter) loadCommit(ctx *sql.Context) error {
+	cm := itr.commits[0]
+	itr.commits = itr.commits[1:]
+
+	h, err := cm.HashOf()
+	if err != nil {
+		return err
+	}
+	meta, err := cm.GetCommitMeta(ctx)
+	if err != nil {
+		return err
+	}
+
+	itr.commitStr = h.String()
+	itr.commitRow = sql.Row{meta.Name, meta.Email, meta.Time(), meta.Description}
+	itr.offset, itr.skip = 0, 0
+	if h == itr.cursor.Commit && itr.cursor.Offset >= 0 {
+		itr.skip = itr.cursor.Offset + 1
+	}
+
+	events := make(chan changeEvent, changesEventBufferSize)
+	egCtx, cancel := context.WithCancel(ctx)
+	eg, egCtx := errgroup.WithContext(egCtx)
+	sqlCtx := ctx.WithContext(egCtx)
+	eg.Go(func() error {
+		defer close(events)
+		return commitChanges(sqlCtx, cm, func(ev changeEvent) error {
+			select {
+			case events <- ev:
+				return nil
+			case <-egCtx.Done():
+				return egCtx.Err()
+			}
+		})
+	})
+	itr.events, itr.eg, itr.cancel = events, eg, cancel
+	return nil
+}
+
+// Close implements the sql.RowIter interface
+func (itr *changesRowIter) Close(*sql.Context) error {
+	if itr.eg == nil {
+		return nil
+	}
+	// any error of the canceled goroutine is a result of the cancellation, since Next returns the errors of each
+	// commit before moving on to the next one
+	itr.cancel()
+	_ = itr.eg.Wait()
+	itr.events, itr.eg = nil, nil
+	return nil
+}
+
+// commitChanges calls |cb| with each row change made by |cm|, relative to its first parent, ordered by table name and
+// key.
+func commitChanges(ctx *sql.Context, cm *doltdb.Commit, cb func(changeEvent) error) error {
+	toRoot, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return err
+	}
+
+	var fromRoot doltdb.RootValue
+	if cm.NumParents() > 0 {
+		optCmt, err := cm.GetParent(ctx, 0)
+		if err != nil {
+			return err
+		}
+		parent, ok := optCmt.ToCommit()
+		if !ok {
+			return doltdb.ErrGhostCommitEncountered
+		}
+		fromRoot, err = parent.GetRootValue(ctx)
+		if err != nil {
+			return err
+		}
+	} else {
+		fromRoot, err = doltdb.EmptyRootValue(ctx, toRoot.VRW(), toRoot.NodeStore())
+		if err != nil {
+			return err
+		}
+	}
+
+	deltas, err := diff.GetTableDeltas(ctx, fromRoot, toRoot)
+	if err != nil {
+		return err
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		return deltas[i].CurName() < deltas[j].CurName()
+	})
+
+	for _, td := range deltas {
+		changed, err := td.HasDataChanged(ctx)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			continue
+		}
+		if err = tableChanges(ctx, td, cb); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// changesTableVersion converts the rows of one side of a table delta.
+type changesTableVersion struct {
+	sch  schema.Schema
+	rows prolly.Map
+	conv dtables.ProllyRowConverter
+}
+
+func newChangesTableVersion(ctx context.Context, tbl *doltdb.Table) (*changesTableVersion, error) {
+	if tbl == nil {
+		return nil, nil
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conv, err := dtables.NewProllyRowConverter(sch, sch, nil, tbl.NodeStore())
+	if err != nil {
+		return nil, err
+	}
+	return &changesTableVersion{sch: sch, rows: durable.ProllyMapFromIndex(idx), conv: conv}, nil
+}
+
+// toJSON converts a row of the table to a JSON object with a field for each stored column.
+func (v *changesTableVersion) toJSON(ctx *sql.Context, key, value val.Tuple) (sql.JSONWrapper, error) {
+	cols := v.sch.GetAllCols().GetColumns()
+	row := make(sql.Row, len(cols))
+	if err := v.conv.PutConverted(ctx, key, value, row); err != nil {
+		return nil, err
+	}
+
+	obj := make(map[string]interface{}, len(cols))
+	for i, col := range cols {
+		if col.Virtual {
+			continue
+		}
+		jv, err := changesJSONValue(ctx, col.TypeInfo.ToSqlType(), row[i])
+		if err != nil {
+			return nil, err
+		}
+		obj[col.Name] = jv
+	}
+	return types.JSONDocument{Val: obj}, nil
+}
+
+// changesJSONValue converts a column value to a JSON value. Numbers are kept as JSON numbers, and other values use
+// their SQL string representation.
+func changesJSONValue(ctx *sql.Context, typ sql.Type, v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	switch {
+	case types.IsJSON(typ):
+		if doc, ok := v.(sql.JSONWrapper); ok {
+			return doc.ToInterface()
+		}
+	case (types.IsInteger(typ) || types.IsFloat(typ)) && !types.IsEnum(typ) && !types.IsSet(typ):
+		return v, nil
+	}
+	sqlVal, err := typ.SQL(ctx, nil, v)
+	if err != nil {
+		return nil, err
+	}
+	return sqlVal.ToString(), nil
+}
+
+// rowCount returns the number of rows represented by a value tuple, which is more than one for duplicate rows of
+// keyless tables.
+func (v *changesTableVersion) rowCount(value val.Tuple) uint64 {
+	if !schema.IsKeyless(v.sch) {
+		return 1
+	}
+	n, _ := v.sch.GetValueDescriptor().GetUint64(0, value)
+	return n
+}
+
+// tableChanges calls |cb| with each row change of |td|, ordered by key.
+func tableChanges(ctx *sql.Context, td diff.TableDelta, cb func(changeEvent) error) error {
+	name := td.CurName()
+	from, err := newChangesTableVersion(ctx, td.FromTable)
+	if err != nil {
+		return err
+	}
+	to, err := newChangesTableVersion(ctx, td.ToTable)
+	if err != nil {
+		return err
+	}
+
+	emit := func(changeType string, n uint64, fromDoc, toDoc sql.JSONWrapper) error {
+		for i := uint64(0); i < n; i++ {
+			if err := cb(changeEvent{table: name, changeType: changeType, from: fromDoc, to: toDoc}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// tables which were added or dropped, or whose primary key changed, are reported as deleting every row of the
+	// old table and inserting every row of the new one.
+	if from == nil || to == nil || !schema.ArePrimaryKeySetsDiffable(td.Format(), from.sch, to.sch) {
+		if from != nil {
+			err = iterAllRows(ctx, from.rows, func(key, value val.Tuple) error {
+				doc, err := from.toJSON(ctx, key, value)
+				if err != nil {
+					return err
+				}
+				return emit(ChangeTypeDelete, from.rowCount(value), doc, nil)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if to != nil {
+			err = iterAllRows(ctx, to.rows, func(key, value val.Tuple) error {
+				doc, err := to.toJSON(ctx, key, value)
+				if err != nil {
+					return err
+				}
+				return emit(ChangeTypeInsert, to.rowCount(value), nil, doc)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	err = prolly.DiffMaps(ctx, from.rows, to.rows, false, func(_ context.Context, d tree.Diff) error {
+		key := val.Tuple(d.Key)
+		switch d.Type {
+		case tree.AddedDiff:
+			doc, err := to.toJSON(ctx, key, val.Tuple(d.To))
+			if err != nil {
+				return err
+			}
+			return emit(ChangeTypeInsert, to.rowCount(val.Tuple(d.To)), nil, doc)
+		case tree.RemovedDiff:
+			doc, err := from.toJSON(ctx, key, val.Tuple(d.From))
+			if err != nil {
+				return err
+			}
+			return emit(ChangeTypeDelete, from.rowCount(val.Tuple(d.From)), doc, nil)
+		case tree.ModifiedDiff:
+			fromDoc, err := from.toJSON(ctx, key, val.Tuple(d.From))
+			if err != nil {
+				return err
+			}
+			toDoc, err := to.toJSON(ctx, key, val.Tuple(d.To))
+			if err != nil {
+				return err
+			}
+			if !schema.IsKeyless(to.sch) {
+				return emit(ChangeTypeUpdate, 1, fromDoc, toDoc)
+			}
+			// a modified keyless row is a change in the number of duplicates of the row
+			fromCount, toCount := from.rowCount(val.Tuple(d.From)), to.rowCount(val.Tuple(d.To))
+			if toCount > fromCount {
+				return emit(ChangeTypeInsert, toCount-fromCount, nil, toDoc)
+			} else if fromCount > toCount {
+				return emit(ChangeTypeDelete, fromCount-toCount, fromDoc, nil)
+			}
+		default:
+			return fmt.Errorf("unexpected diff type: %v", d.Type)
+		}
+		return nil
+	})
+	if err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+func iterAllRows(ctx context.Context, m prolly.Map, cb func(key, value val.Tuple) error) error {
+	iter, err := m.IterAll(ctx)
+	if err != nil {
+		return err
+	}
+	for {
+		k, v, err := iter.Next(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err = cb(k, v); err != nil {
+			return err
+		}
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"gopkg.in/src-d/go-errors.v1"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
 	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
@@ -49,6 +50,7 @@ type LogTableFunction struct {
 	minParents    int
 	showParents   bool
 	showSignature bool
+	showNotes     bool
 	decoration    string
 
 	database sql.Database
@@ -149,6 +151,10 @@ func (ltf *LogTableFunction) getOptionsString() string {
 		options = append(options, fmt.Sprintf("--%s", cli.ShowSignatureFlag))
 	}
 
+	if ltf.showNotes {
+		options = append(options, fmt.Sprintf("--%s", cli.NotesFlag))
+	}
+
 	if len(ltf.decoration) > 0 && ltf.decoration != "auto" {
 		options = append(options, fmt.Sprintf("--%s %s", cli.DecorateFlag, ltf.decoration))
 	}
@@ -173,6 +179,9 @@ func (ltf *LogTableFunction) Schema() sql.Schema {
 	if ltf.showSignature {
 		logSchema = append(logSchema, &sql.Column{Name: "signature", Type: types.Text})
 	}
+	if ltf.showNotes {
+		logSchema = append(logSchema, &sql.Column{Name: "notes", Type: types.Text})
+	}
 
 	return logSchema
 }
@@ -266,6 +275,7 @@ func (ltf *LogTableFunction) addOptions(expression []sql.Expression) error {
 	ltf.minParents = minParents
 	ltf.showParents = apr.Contains(cli.ParentsFlag)
 	ltf.showSignature = apr.Contains(cli.ShowSignatureFlag)
+	ltf.showNotes = apr.Contains(cli.NotesFlag)
 
 	decorateOption := apr.GetValueOrDefault(cli.DecorateFlag, "auto")
 	switch decorateOption {
@@ -423,7 +433,7 @@ func (ltf *LogTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter
 		return commit.NumParents() >= ltf.minParents, nil
 	}
 
-	cHashToRefs, err := getCommitHashToRefs(ctx, sqledb.DbData().Ddb, ltf.decoration)
+	cHashToRefs, err := getCommitHashToRefs(ctx, sqledb.Name(), sqledb.DbData().Ddb, ltf.decoration)
 	if err != nil {
 		return nil, err
 	}
@@ -552,7 +562,7 @@ func (ltf *LogTableFunction) evaluateArguments() (revisionValStrs []string, notR
 	return revisionValStrs, notRevisionValStrs, false, nil
 }
 
-func getCommitHashToRefs(ctx *sql.Context, ddb *doltdb.DoltDB, decoration string) (map[hash.Hash][]string, error) {
+func getCommitHashToRefs(ctx *sql.Context, dbName string, ddb *doltdb.DoltDB, decoration string) (map[hash.Hash][]string, error) {
 	cHashToRefs := map[hash.Hash][]string{}
 
 	// Get all branches
@@ -561,6 +571,13 @@ func getCommitHashToRefs(ctx *sql.Context, ddb *doltdb.DoltDB, decoration string
 		return nil, err
 	}
 	for _, b := range branches {
+		// Branches hidden from the current user by branch control are not used as decorations
+		err = branch_control.CheckReadAccess(ctx, dbName, b.Ref.GetPath())
+		if branch_control.ErrCannotReadBranch.Is(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
 		refName := b.Ref.String()
 		if decoration != "full" {
 			refName = b.Ref.GetPath() // trim out "refs/heads/"
@@ -609,6 +626,9 @@ type logTableFunctionRowIter struct {
 	child         doltdb.CommitItr
 	showParents   bool
 	showSignature bool
+	signers       *gpg.AllowedSigners
+	showNotes     bool
+	notes         map[hash.Hash]string
 	decoration    string
 	cHashToRefs   map[hash.Hash][]string
 	headHash      hash.Hash
@@ -627,10 +647,23 @@ func (ltf *LogTableFunction) NewLogTableFunctionRowIter(ctx *sql.Context, ddb *d
 		return nil, err
 	}
 
+	signers, err := ltf.allowedSigners(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := ltf.commitNotes(ctx, ddb)
+	if err != nil {
+		return nil, err
+	}
+
 	return &logTableFunctionRowIter{
 		child:         child,
 		showParents:   ltf.showParents,
 		showSignature: ltf.showSignature,
+		signers:       signers,
+		showNotes:     ltf.showNotes,
+		notes:         notes,
 		decoration:    ltf.decoration,
 		cHashToRefs:   cHashToRefs,
 		headHash:      h,
@@ -662,6 +695,16 @@ func (ltf *LogTableFunction) NewDotDotLogTableFunctionRowIter(ctx *sql.Context,
 		return nil, err
 	}
 
+	signers, err := ltf.allowedSigners(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := ltf.commitNotes(ctx, ddb)
+	if err != nil {
+		return nil, err
+	}
+
 	var headHash hash.Hash
 
 	if len(hashes) == 1 {
@@ -672,6 +715,9 @@ func (ltf *LogTableFunction) NewDotDotLogTableFunctionRowIter(ctx *sql.Context,
 		child:         child,
 		showParents:   ltf.showParents,
 		showSignature: ltf.showSignature,
+		signers:       signers,
+		showNotes:     ltf.showNotes,
+		notes:         notes,
 		decoration:    ltf.decoration,
 		cHashToRefs:   cHashToRefs,
 		headHash:      headHash,
@@ -679,6 +725,35 @@ func (ltf *LogTableFunction) NewDotDotLogTableFunctionRowIter(ctx *sql.Context,
 	}, nil
 }
 
+// allowedSigners returns the signers trusted to sign commits, which are only needed to show signatures. Like
+// `git log --show-signature`, keys in the gpg keyring are trusted for the emails of their user ids.
+func (ltf *LogTableFunction) allowedSigners(ctx *sql.Context) (*gpg.AllowedSigners, error) {
+	if !ltf.showSignature {
+		return nil, nil
+	}
+	signers, err := dsess.GetAllowedSigners()
+	if err != nil {
+		return nil, err
+	}
+	return signers.WithGPGKeyring(), nil
+}
+
+// commitNotes returns the note of each annotated commit, which are only needed to show notes
+func (ltf *LogTableFunction) commitNotes(ctx *sql.Context, ddb *doltdb.DoltDB) (map[hash.Hash]string, error) {
+	if !ltf.showNotes {
+		return nil, nil
+	}
+	notes, err := ddb.GetNotes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[hash.Hash]string, len(notes))
+	for _, note := range notes {
+		ret[note.CommitHash] = note.Meta.Description
+	}
+	return ret, nil
+}
+
 // Next retrieves the next row. It will return io.EOF if it's the last row.
 // After retrieving the last row, Close will be automatically closed.
 func (itr *logTableFunctionRowIter) Next(ctx *sql.Context) (sql.Row, error) {
@@ -782,17 +857,21 @@ func (itr *logTableFunctionRowIter) Next(ctx *sql.Context) (sql.Row, error) {
 
 	if itr.showSignature {
 		if len(meta.Signature) > 0 {
-			out, err := gpg.Verify(ctx, []byte(meta.Signature))
+			v, err := doltdb.VerifyCommitSignature(ctx, commit, itr.signers)
 			if err != nil {
 				return nil, err
 			}
 
-			row = row.Append(sql.NewRow(string(out)))
+			row = row.Append(sql.NewRow(v.String()))
 		} else {
 			row = row.Append(sql.NewRow(""))
 		}
 	}
 
+	if itr.showNotes {
+		row = row.Append(sql.NewRow(itr.notes[commitHash]))
+	}
+
 	return row, nil
 }
 
@@ -0,0 +1,192 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtablefunctions
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/stats"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// StatsForTableFunction is the dolt_stats_for table function, which shows the
+// index statistics of a database as of a commit, in the format of the
+// dolt_statistics system table.
+type StatsForTableFunction struct {
+	ctx        *sql.Context
+	database   sql.Database
+	commitExpr sql.Expression
+}
+
+var _ sql.TableFunction = (*StatsForTableFunction)(nil)
+var _ sql.ExecSourceRel = (*StatsForTableFunction)(nil)
+var _ sql.AuthorizationCheckerNode = (*StatsForTableFunction)(nil)
+
+// RevisionStatsProvider is a sql.StatsProvider that can collect statistics
+// for past commits.
+type RevisionStatsProvider interface {
+	GetRevisionDoltStats(ctx *sql.Context, db, revision string) ([]sql.Statistic, error)
+}
+
+// NewInstance creates a new instance of TableFunction interface
+func (sf *StatsForTableFunction) NewInstance(ctx *sql.Context, database sql.Database, expressions []sql.Expression) (sql.Node, error) {
+	newInstance := &StatsForTableFunction{
+		ctx:      ctx,
+		database: database,
+	}
+
+	node, err := newInstance.WithExpressions(expressions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// RowIter implements the sql.Node interface
+func (sf *StatsForTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	sqlDb, ok := sf.database.(dsess.SqlDatabase)
+	if !ok {
+		return nil, fmt.Errorf("unexpected database type: %T", sf.database)
+	}
+
+	commitVal, err := sf.commitExpr.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	commitStr, ok := commitVal.(string)
+	if !ok {
+		return nil, fmt.Errorf("argument (%v) is not a string value, but a %T", commitVal, commitVal)
+	}
+
+	sess := dsess.DSessFromSess(ctx.Session)
+	statsPro, ok := sess.StatsProvider().(RevisionStatsProvider)
+	if !ok {
+		return sql.RowsToRowIter(), nil
+	}
+
+	headRef, err := sess.CWBHeadRef(ctx, sqlDb.Name())
+	if err != nil {
+		return nil, err
+	}
+	cs, err := doltdb.NewCommitSpec(commitStr)
+	if err != nil {
+		return nil, err
+	}
+	optCmt, err := sqlDb.DbData().Ddb.Resolve(ctx, cs, headRef)
+	if err != nil {
+		return nil, err
+	}
+	commit, ok := optCmt.ToCommit()
+	if !ok {
+		return nil, doltdb.ErrGhostCommitEncountered
+	}
+	commitHash, err := commit.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	dStats, err := statsPro.GetRevisionDoltStats(ctx, sqlDb.Name(), commitHash.String())
+	if err != nil {
+		return nil, err
+	}
+	return stats.NewStatsIter(ctx, dStats...)
+}
+
+// Schema implements the sql.Node interface
+func (sf *StatsForTableFunction) Schema() sql.Schema {
+	return schema.StatsTableSqlSchema(sf.database.Name()).Schema
+}
+
+// Resolved implements the sql.Resolvable interface
+func (sf *StatsForTableFunction) Resolved() bool {
+	return sf.commitExpr != nil && sf.commitExpr.Resolved()
+}
+
+// String implements the Stringer interface
+func (sf *StatsForTableFunction) String() string {
+	return fmt.Sprintf("DOLT_STATS_FOR(%s)", sf.commitExpr.String())
+}
+
+// Children implements the sql.Node interface
+func (sf *StatsForTableFunction) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the sql.Node interface
+func (sf *StatsForTableFunction) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("unexpected children")
+	}
+	return sf, nil
+}
+
+// IsReadOnly implements the sql.Node interface
+func (sf *StatsForTableFunction) IsReadOnly() bool {
+	return true
+}
+
+// CheckAuth implements the interface sql.AuthorizationCheckerNode.
+func (sf *StatsForTableFunction) CheckAuth(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	tblNames, err := sf.database.GetTableNames(ctx)
+	if err != nil {
+		return false
+	}
+
+	var operations []sql.PrivilegedOperation
+	for _, tblName := range tblNames {
+		subject := sql.PrivilegeCheckSubject{Database: sf.database.Name(), Table: tblName}
+		operations = append(operations, sql.NewPrivilegedOperation(subject, sql.PrivilegeType_Select))
+	}
+
+	return opChecker.UserHasPrivileges(ctx, operations...)
+}
+
+// Expressions implements the sql.Expressioner interface
+func (sf *StatsForTableFunction) Expressions() []sql.Expression {
+	return []sql.Expression{sf.commitExpr}
+}
+
+// WithExpressions implements the sql.Expressioner interface
+func (sf *StatsForTableFunction) WithExpressions(expressions ...sql.Expression) (sql.Node, error) {
+	if len(expressions) != 1 {
+		return nil, sql.ErrInvalidArgumentNumber.New(sf.Name(), 1, len(expressions))
+	}
+
+	newSf := *sf
+	newSf.commitExpr = expressions[0]
+	return &newSf, nil
+}
+
+// Name implements the sql.TableFunction interface
+func (sf *StatsForTableFunction) Name() string {
+	return "dolt_stats_for"
+}
+
+// Database implements the sql.Databaser interface
+func (sf *StatsForTableFunction) Database() sql.Database {
+	return sf.database
+}
+
+// WithDatabase implements the sql.Databaser interface
+func (sf *StatsForTableFunction) WithDatabase(database sql.Database) (sql.Node, error) {
+	newSf := *sf
+	newSf.database = database
+	return &newSf, nil
+}
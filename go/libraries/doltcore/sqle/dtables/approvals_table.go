@@ -0,0 +1,233 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/dolthub/vitess/go/sqltypes"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+const (
+	ApprovalsTableName = "dolt_approvals"
+)
+
+// approvalsSchema is the schema for the "dolt_approvals" table.
+var approvalsSchema = sql.Schema{
+	&sql.Column{
+		Name:       "database",
+		Type:       types.MustCreateString(sqltypes.VarChar, 16383, sql.Collation_utf8mb4_0900_ai_ci),
+		Source:     ApprovalsTableName,
+		PrimaryKey: true,
+	},
+	&sql.Column{
+		Name:       "branch",
+		Type:       types.MustCreateString(sqltypes.VarChar, 16383, sql.Collation_utf8mb4_0900_ai_ci),
+		Source:     ApprovalsTableName,
+		PrimaryKey: true,
+	},
+	&sql.Column{
+		Name:       "approver",
+		Type:       types.MustCreateString(sqltypes.VarChar, 16383, sql.Collation_utf8mb4_0900_bin),
+		Source:     ApprovalsTableName,
+		PrimaryKey: true,
+	},
+	&sql.Column{
+		Name:       "commit_hash",
+		Type:       types.MustCreateString(sqltypes.VarChar, 32, sql.Collation_ascii_bin),
+		Source:     ApprovalsTableName,
+		PrimaryKey: false,
+	},
+}
+
+// ApprovalsTable provides a layer over the branch_control.Approvals structure, exposing it as a system table. Users
+// approve a branch by inserting a row naming themselves and the commit at the head of the branch.
+type ApprovalsTable struct {
+	*branch_control.Approvals
+}
+
+var _ sql.Table = ApprovalsTable{}
+var _ sql.InsertableTable = ApprovalsTable{}
+var _ sql.DeletableTable = ApprovalsTable{}
+var _ sql.RowInserter = ApprovalsTable{}
+var _ sql.RowDeleter = ApprovalsTable{}
+
+// NewApprovalsTable returns a new ApprovalsTable.
+func NewApprovalsTable(approvals *branch_control.Approvals) ApprovalsTable {
+	return ApprovalsTable{approvals}
+}
+
+// Name implements the interface sql.Table.
+func (tbl ApprovalsTable) Name() string {
+	return ApprovalsTableName
+}
+
+// String implements the interface sql.Table.
+func (tbl ApprovalsTable) String() string {
+	return ApprovalsTableName
+}
+
+// Schema implements the interface sql.Table.
+func (tbl ApprovalsTable) Schema() sql.Schema {
+	return approvalsSchema
+}
+
+// Collation implements the interface sql.Table.
+func (tbl ApprovalsTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions implements the interface sql.Table.
+func (tbl ApprovalsTable) Partitions(context *sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows implements the interface sql.Table. Approvals of branches that the context may not read are omitted.
+func (tbl ApprovalsTable) PartitionRows(ctx *sql.Context, partition sql.Partition) (sql.RowIter, error) {
+	tbl.RWMutex.RLock()
+	values := make([]branch_control.ApprovalValue, len(tbl.Values))
+	copy(values, tbl.Values)
+	tbl.RWMutex.RUnlock()
+
+	var rows []sql.Row
+	for _, value := range values {
+		if branch_control.CheckReadAccess(ctx, value.Database, value.Branch) != nil {
+			continue
+		}
+		rows = append(rows, sql.Row{
+			value.Database,
+			value.Branch,
+			value.Approver,
+			value.CommitHash,
+		})
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// Inserter implements the interface sql.InsertableTable.
+func (tbl ApprovalsTable) Inserter(context *sql.Context) sql.RowInserter {
+	return tbl
+}
+
+// Deleter implements the interface sql.DeletableTable.
+func (tbl ApprovalsTable) Deleter(context *sql.Context) sql.RowDeleter {
+	return tbl
+}
+
+// StatementBegin implements the interface sql.TableEditor.
+func (tbl ApprovalsTable) StatementBegin(ctx *sql.Context) {}
+
+// DiscardChanges implements the interface sql.TableEditor.
+func (tbl ApprovalsTable) DiscardChanges(ctx *sql.Context, errorEncountered error) error {
+	return nil
+}
+
+// StatementComplete implements the interface sql.TableEditor.
+func (tbl ApprovalsTable) StatementComplete(ctx *sql.Context) error {
+	return nil
+}
+
+// Insert implements the interface sql.RowInserter. Users may only record their own approvals, of the commit currently
+// at the head of a branch that they did not author.
+func (tbl ApprovalsTable) Insert(ctx *sql.Context, row sql.Row) error {
+	value := approvalValueFromRow(row)
+	user, host := sessionUserAndHost(ctx)
+	if branch_control.GetBranchAwareSession(ctx) != nil && value.Approver != user {
+		return branch_control.ErrApprovingForAnotherUser.New(user, host, value.Approver)
+	}
+	if err := branch_control.CheckReadAccess(ctx, value.Database, value.Branch); err != nil {
+		return err
+	}
+
+	head, err := branchHead(ctx, value.Database, row[1].(string))
+	if err != nil {
+		return err
+	}
+	headHash, err := head.HashOf()
+	if err != nil {
+		return err
+	}
+	if headHash.String() != value.CommitHash {
+		return branch_control.ErrApprovingStaleCommit.New(value.CommitHash, value.Branch, headHash.String())
+	}
+	tbl.RWMutex.Lock()
+	defer tbl.RWMutex.Unlock()
+	if author, ok := tbl.Author(value.CommitHash); ok && author.User == value.Approver {
+		return branch_control.ErrApprovingOwnCommit.New(value.Approver, value.CommitHash, value.Branch)
+	}
+	if idx := tbl.GetIndex(value.Database, value.Branch, value.Approver); idx != -1 && tbl.Values[idx].CommitHash == value.CommitHash {
+		return sql.NewUniqueKeyErr(
+			fmt.Sprintf(`[%q, %q, %q]`, value.Database, value.Branch, value.Approver),
+			true,
+			sql.Row{value.Database, value.Branch, value.Approver})
+	}
+	// An approval of an earlier head of the branch is replaced by this one
+	tbl.Approvals.Insert(value)
+	return nil
+}
+
+// Delete implements the interface sql.RowDeleter. Users may revoke their own approvals, while users with the database
+// privileges may revoke any approval.
+func (tbl ApprovalsTable) Delete(ctx *sql.Context, row sql.Row) error {
+	value := approvalValueFromRow(row)
+	if branchAwareSession := branch_control.GetBranchAwareSession(ctx); branchAwareSession != nil &&
+		branchAwareSession.GetUser() != value.Approver &&
+		!branch_control.HasDatabasePrivileges(branchAwareSession, value.Database) {
+		return branch_control.ErrApprovingForAnotherUser.New(branchAwareSession.GetUser(), branchAwareSession.GetHost(), value.Approver)
+	}
+
+	tbl.RWMutex.Lock()
+	defer tbl.RWMutex.Unlock()
+	tbl.Approvals.Delete(value.Database, value.Branch, value.Approver)
+	return nil
+}
+
+// Close implements the interface sql.Closer.
+func (tbl ApprovalsTable) Close(context *sql.Context) error {
+	return branch_control.SaveData(context)
+}
+
+// approvalValueFromRow returns the approval represented by the given row.
+func approvalValueFromRow(row sql.Row) branch_control.ApprovalValue {
+	return branch_control.ApprovalValue{
+		// Database and Branch are case-insensitive
+		Database:   strings.ToLower(row[0].(string)),
+		Branch:     strings.ToLower(row[1].(string)),
+		Approver:   row[2].(string),
+		CommitHash: row[3].(string),
+	}
+}
+
+// branchHead returns the commit at the head of the given branch of the given database.
+func branchHead(ctx *sql.Context, database string, branch string) (*doltdb.Commit, error) {
+	sqlDb, err := dsess.DSessFromSess(ctx.Session).Provider().Database(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+	db, ok := sqlDb.(dsess.SqlDatabase)
+	if !ok {
+		return nil, fmt.Errorf("unexpected database type: %T", sqlDb)
+	}
+	return db.DbData().Ddb.ResolveCommitRef(ctx, ref.NewBranchRef(branch))
+}
@@ -33,7 +33,7 @@ const (
 
 // PermissionsStrings is a slice of strings representing the available branch_control.branch_control.Permissions. The order of the
 // strings should exactly match the order of the branch_control.Permissions according to their flag value.
-var PermissionsStrings = []string{"admin", "write", "read"}
+var PermissionsStrings = []string{"admin", "write", "read", "deny"}
 
 // accessSchema is the schema for the "dolt_branch_control" table.
 var accessSchema = sql.Schema{
@@ -205,7 +205,8 @@ func (tbl BranchControlTable) Insert(ctx *sql.Context, row sql.Row) error {
 	// We check if we're inserting a subset of an already-existing row. We only consider this a subset if the
 	// permissions are as permissible as the existing ones, or are more restrictive (i.e. write is a "subset permission"
 	// of admin). If we are, we deny the insertion as the existing row will already match against ALL possible values for this row.
-	if ok, modPerms := tbl.Match(database, branch, user, host); ok && perms.Consolidate() >= modPerms.Consolidate() {
+	// The deny permission is only a subset of another deny row, as it must be able to narrow any broader permissions.
+	if ok, modPerms := tbl.Match(database, branch, user, host); ok && isPermissionSubset(perms, modPerms) {
 		permBits := uint64(modPerms)
 		permStr, _ := accessSchema[4].Type.(sql.SetType).BitsToString(permBits)
 		return sql.NewUniqueKeyErr(
@@ -311,3 +312,13 @@ func (tbl BranchControlTable) Delete(ctx *sql.Context, row sql.Row) error {
 func (tbl BranchControlTable) Close(context *sql.Context) error {
 	return branch_control.SaveData(context)
 }
+
+// isPermissionSubset returns whether the permissions |perms| are as permissible as |existing|, or are more restrictive.
+func isPermissionSubset(perms branch_control.Permissions, existing branch_control.Permissions) bool {
+	perms = perms.Consolidate()
+	existing = existing.Consolidate()
+	if perms == branch_control.Permissions_Deny {
+		return existing == branch_control.Permissions_Deny
+	}
+	return perms >= existing
+}
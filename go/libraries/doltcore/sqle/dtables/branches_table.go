@@ -21,6 +21,7 @@ import (
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/types"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
@@ -143,9 +144,19 @@ func NewBranchItr(ctx *sql.Context, table *BranchesTable) (*BranchItr, error) {
 		}
 	}
 
-	branchNames := make([]string, len(branchRefs))
-	commits := make([]*doltdb.Commit, len(branchRefs))
-	for i, branch := range branchRefs {
+	branchNames := make([]string, 0, len(branchRefs))
+	commits := make([]*doltdb.Commit, 0, len(branchRefs))
+	for _, branch := range branchRefs {
+		if branch.GetType() == ref.BranchRefType {
+			// Branches hidden from the current user are omitted entirely
+			err = branch_control.CheckReadAccess(ctx, db.Name(), branch.GetPath())
+			if branch_control.ErrCannotReadBranch.Is(err) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+		}
+
 		commit, err := ddb.ResolveCommitRefAtRoot(ctx, branch, txRoot)
 
 		if err != nil {
@@ -153,12 +164,27 @@ func NewBranchItr(ctx *sql.Context, table *BranchesTable) (*BranchItr, error) {
 		}
 
 		if branch.GetType() == ref.RemoteRefType {
-			branchNames[i] = "remotes/" + branch.GetPath()
+			// Remote branches whose head is only reachable from hidden branches are omitted as well
+			h, err := commit.HashOf()
+			if err != nil {
+				return nil, err
+			}
+			hidden, err := ddb.IsHiddenCommit(ctx, h)
+			if err != nil {
+				return nil, err
+			}
+			if hidden {
+				continue
+			}
+		}
+
+		if branch.GetType() == ref.RemoteRefType {
+			branchNames = append(branchNames, "remotes/"+branch.GetPath())
 		} else {
-			branchNames[i] = branch.GetPath()
+			branchNames = append(branchNames, branch.GetPath())
 		}
 
-		commits[i] = commit
+		commits = append(commits, commit)
 	}
 
 	return &BranchItr{
@@ -0,0 +1,183 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/dolt_ci"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+const (
+	ciRunStepStatusPassed = "passed"
+	ciRunStepStatusFailed = "failed"
+)
+
+var _ sql.Table = (*CIRunsTable)(nil)
+var _ sql.Table = (*CIRunStepsTable)(nil)
+
+// CIRunsTable is a sql.Table implementation that implements a system table which shows the Dolt CI workflow runs
+// triggered by branch updates made to a database.
+type CIRunsTable struct {
+	dbName    string
+	tableName string
+	ddb       *doltdb.DoltDB
+}
+
+// NewCIRunsTable creates a CIRunsTable
+func NewCIRunsTable(_ *sql.Context, dbName, tableName string, ddb *doltdb.DoltDB) sql.Table {
+	return &CIRunsTable{dbName: dbName, tableName: tableName, ddb: ddb}
+}
+
+// Name is a sql.Table interface function which returns the name of the table
+func (t *CIRunsTable) Name() string {
+	return t.tableName
+}
+
+// String is a sql.Table interface function which returns the name of the table
+func (t *CIRunsTable) String() string {
+	return t.tableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the CI runs system table
+func (t *CIRunsTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "id", Type: types.Text, Source: t.tableName, PrimaryKey: true, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "workflow_name", Type: types.Text, Source: t.tableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "event", Type: types.Text, Source: t.tableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "branch", Type: types.Text, Source: t.tableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "commit_hash", Type: types.Text, Source: t.tableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "status", Type: types.Text, Source: t.tableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "message", Type: types.Text, Source: t.tableName, PrimaryKey: false, Nullable: true, DatabaseSource: t.dbName},
+		{Name: "started_at", Type: types.Datetime, Source: t.tableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "finished_at", Type: types.Datetime, Source: t.tableName, PrimaryKey: false, Nullable: true, DatabaseSource: t.dbName},
+	}
+}
+
+// Collation implements the sql.Table interface.
+func (t *CIRunsTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data.  Currently the data is unpartitioned.
+func (t *CIRunsTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (t *CIRunsTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	runs, err := dolt_ci.NewWorkflowRunHistory(t.ddb).Runs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]sql.Row, len(runs))
+	for i, run := range runs {
+		rows[i] = sql.NewRow(
+			run.Id,
+			run.WorkflowName,
+			string(run.Event),
+			run.Branch,
+			run.CommitHash,
+			string(run.Status),
+			nullableString(run.Message),
+			run.StartedAt,
+			nullableTime(run.FinishedAt),
+		)
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// CIRunStepsTable is a sql.Table implementation that implements a system table which shows the outcome of each step
+// of the workflow runs in the CI runs system table.
+type CIRunStepsTable struct {
+	dbName    string
+	tableName string
+	ddb       *doltdb.DoltDB
+}
+
+// NewCIRunStepsTable creates a CIRunStepsTable
+func NewCIRunStepsTable(_ *sql.Context, dbName, tableName string, ddb *doltdb.DoltDB) sql.Table {
+	return &CIRunStepsTable{dbName: dbName, tableName: tableName, ddb: ddb}
+}
+
+// Name is a sql.Table interface function which returns the name of the table
+func (t *CIRunStepsTable) Name() string {
+	return t.tableName
+}
+
+// String is a sql.Table interface function which returns the name of the table
+func (t *CIRunStepsTable) String() string {
+	return t.tableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the CI run steps system table
+func (t *CIRunStepsTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "run_id", Type: types.Text, Source: t.tableName, PrimaryKey: true, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "job", Type: types.Text, Source: t.tableName, PrimaryKey: true, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "step", Type: types.Text, Source: t.tableName, PrimaryKey: true, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "saved_query", Type: types.Text, Source: t.tableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "status", Type: types.Text, Source: t.tableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "message", Type: types.Text, Source: t.tableName, PrimaryKey: false, Nullable: true, DatabaseSource: t.dbName},
+	}
+}
+
+// Collation implements the sql.Table interface.
+func (t *CIRunStepsTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data.  Currently the data is unpartitioned.
+func (t *CIRunStepsTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (t *CIRunStepsTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	runs, err := dolt_ci.NewWorkflowRunHistory(t.ddb).Runs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var rows []sql.Row
+	for _, run := range runs {
+		for _, step := range run.Steps {
+			status := ciRunStepStatusPassed
+			if !step.Passed {
+				status = ciRunStepStatusFailed
+			}
+			rows = append(rows, sql.NewRow(run.Id, step.JobName, step.StepName, step.SavedQueryName, status, nullableString(step.Message)))
+		}
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+func nullableString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func nullableTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
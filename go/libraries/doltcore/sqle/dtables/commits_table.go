@@ -78,6 +78,7 @@ func (ct *CommitsTable) Schema() sql.Schema {
 		{Name: "email", Type: types.Text, Source: ct.tableName, PrimaryKey: false, DatabaseSource: ct.dbName},
 		{Name: "date", Type: types.Datetime, Source: ct.tableName, PrimaryKey: false, DatabaseSource: ct.dbName},
 		{Name: "message", Type: types.Text, Source: ct.tableName, PrimaryKey: false, DatabaseSource: ct.dbName},
+		{Name: "signature_status", Type: types.Text, Source: ct.tableName, PrimaryKey: false, DatabaseSource: ct.dbName},
 	}
 }
 
@@ -96,7 +97,11 @@ func (ct *CommitsTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
 func (ct *CommitsTable) PartitionRows(ctx *sql.Context, p sql.Partition) (sql.RowIter, error) {
 	switch p := p.(type) {
 	case *doltdb.CommitPart:
-		return sql.RowsToRowIter(formatCommitTableRow(p.Hash(), p.Meta())), nil
+		status, err := (&signatureStatuses{}).status(ctx, p.Commit(), p.Meta())
+		if err != nil {
+			return nil, err
+		}
+		return sql.RowsToRowIter(formatCommitTableRow(p.Hash(), p.Meta(), status)), nil
 	default:
 		return NewCommitsRowItr(ctx, ct.ddb)
 	}
@@ -136,7 +141,8 @@ func (ct *CommitsTable) LookupPartitions(ctx *sql.Context, lookup sql.IndexLooku
 
 // CommitsRowItr is a sql.RowItr which iterates over each commit as if it's a row in the table.
 type CommitsRowItr struct {
-	itr doltdb.CommitItr
+	itr        doltdb.CommitItr
+	signatures *signatureStatuses
 }
 
 // NewCommitsRowItr creates a CommitsRowItr from the current environment.
@@ -146,7 +152,7 @@ func NewCommitsRowItr(ctx *sql.Context, ddb *doltdb.DoltDB) (CommitsRowItr, erro
 		return CommitsRowItr{}, err
 	}
 
-	return CommitsRowItr{itr: itr}, nil
+	return CommitsRowItr{itr: itr, signatures: &signatureStatuses{}}, nil
 }
 
 // Next retrieves the next row. It will return io.EOF if it's the last row.
@@ -166,7 +172,12 @@ func (itr CommitsRowItr) Next(ctx *sql.Context) (sql.Row, error) {
 		return nil, err
 	}
 
-	return formatCommitTableRow(h, meta), nil
+	status, err := itr.signatures.status(ctx, cm, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return formatCommitTableRow(h, meta, status), nil
 }
 
 // Close closes the iterator.
@@ -174,6 +185,6 @@ func (itr CommitsRowItr) Close(*sql.Context) error {
 	return nil
 }
 
-func formatCommitTableRow(h hash.Hash, meta *datas.CommitMeta) sql.Row {
-	return sql.NewRow(h.String(), meta.Name, meta.Email, meta.Time(), meta.Description)
+func formatCommitTableRow(h hash.Hash, meta *datas.CommitMeta, signatureStatus string) sql.Row {
+	return sql.NewRow(h.String(), meta.Name, meta.Email, meta.Time(), meta.Description, signatureStatus)
 }
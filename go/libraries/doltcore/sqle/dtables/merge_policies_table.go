@@ -0,0 +1,376 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	sqlTypes "github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/dolthub/vitess/go/sqltypes"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+var _ sql.Table = (*MergePoliciesTable)(nil)
+var _ sql.UpdatableTable = (*MergePoliciesTable)(nil)
+var _ sql.DeletableTable = (*MergePoliciesTable)(nil)
+var _ sql.InsertableTable = (*MergePoliciesTable)(nil)
+var _ sql.ReplaceableTable = (*MergePoliciesTable)(nil)
+var _ sql.IndexAddressableTable = (*MergePoliciesTable)(nil)
+
+// MergePoliciesTable is the system table that declares how merge resolves cells that were changed differently on
+// both sides of a merge.
+type MergePoliciesTable struct {
+	backingTable VersionableTable
+}
+
+// NewMergePoliciesTable creates a MergePoliciesTable
+func NewMergePoliciesTable(_ *sql.Context, backingTable VersionableTable) sql.Table {
+	return &MergePoliciesTable{backingTable: backingTable}
+}
+
+// NewEmptyMergePoliciesTable creates a MergePoliciesTable
+func NewEmptyMergePoliciesTable(_ *sql.Context) sql.Table {
+	return &MergePoliciesTable{}
+}
+
+func (mt *MergePoliciesTable) Name() string {
+	return doltdb.MergePoliciesTableName
+}
+
+func (mt *MergePoliciesTable) String() string {
+	return doltdb.MergePoliciesTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the dolt_merge_policies system table.
+func (mt *MergePoliciesTable) Schema() sql.Schema {
+	varchar := sqlTypes.MustCreateString(sqltypes.VarChar, defaultStringsLen, sql.Collation_Default)
+	return []*sql.Column{
+		{Name: doltdb.MergePoliciesTableNameCol, Type: varchar, Source: doltdb.MergePoliciesTableName, PrimaryKey: true, Nullable: false},
+		{Name: doltdb.MergePoliciesColumnNameCol, Type: varchar, Source: doltdb.MergePoliciesTableName, PrimaryKey: true, Nullable: false},
+		{Name: doltdb.MergePoliciesStrategyCol, Type: varchar, Source: doltdb.MergePoliciesTableName, PrimaryKey: false, Nullable: false},
+		{Name: doltdb.MergePoliciesArgumentCol, Type: sqlTypes.LongText, Source: doltdb.MergePoliciesTableName, PrimaryKey: false, Nullable: true},
+	}
+}
+
+func (mt *MergePoliciesTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data.
+func (mt *MergePoliciesTable) Partitions(context *sql.Context) (sql.PartitionIter, error) {
+	if mt.backingTable == nil {
+		// no backing table; return an empty iter.
+		return index.SinglePartitionIterFromNomsMap(nil), nil
+	}
+	return mt.backingTable.Partitions(context)
+}
+
+func (mt *MergePoliciesTable) PartitionRows(context *sql.Context, partition sql.Partition) (sql.RowIter, error) {
+	if mt.backingTable == nil {
+		// no backing table; return an empty iter.
+		return sql.RowsToRowIter(), nil
+	}
+
+	return mt.backingTable.PartitionRows(context, partition)
+}
+
+// Replacer returns a RowReplacer for this table. The RowReplacer will have Insert and optionally Delete called once
+// for each row, followed by a call to Close() when all rows have been processed.
+func (mt *MergePoliciesTable) Replacer(ctx *sql.Context) sql.RowReplacer {
+	return newMergePoliciesWriter(mt)
+}
+
+// Updater returns a RowUpdater for this table. The RowUpdater will have Update called once for each row to be
+// updated, followed by a call to Close() when all rows have been processed.
+func (mt *MergePoliciesTable) Updater(ctx *sql.Context) sql.RowUpdater {
+	return newMergePoliciesWriter(mt)
+}
+
+// Inserter returns an Inserter for this table. The Inserter will get one call to Insert() for each row to be
+// inserted, and will end with a call to Close() to finalize the insert operation.
+func (mt *MergePoliciesTable) Inserter(*sql.Context) sql.RowInserter {
+	return newMergePoliciesWriter(mt)
+}
+
+// Deleter returns a RowDeleter for this table. The RowDeleter will get one call to Delete for each row to be deleted,
+// and will end with a call to Close() to finalize the delete operation.
+func (mt *MergePoliciesTable) Deleter(*sql.Context) sql.RowDeleter {
+	return newMergePoliciesWriter(mt)
+}
+
+func (mt *MergePoliciesTable) LockedToRoot(ctx *sql.Context, root doltdb.RootValue) (sql.IndexAddressableTable, error) {
+	if mt.backingTable == nil {
+		return mt, nil
+	}
+	return mt.backingTable.LockedToRoot(ctx, root)
+}
+
+// IndexedAccess implements IndexAddressableTable, but MergePoliciesTable has no indexes.
+// Thus, this should never be called.
+func (mt *MergePoliciesTable) IndexedAccess(lookup sql.IndexLookup) sql.IndexedTable {
+	panic("Unreachable")
+}
+
+// GetIndexes implements IndexAddressableTable, but MergePoliciesTable has no indexes.
+func (mt *MergePoliciesTable) GetIndexes(ctx *sql.Context) ([]sql.Index, error) {
+	return nil, nil
+}
+
+func (mt *MergePoliciesTable) PreciseMatch() bool {
+	return true
+}
+
+var _ sql.RowReplacer = (*mergePoliciesWriter)(nil)
+var _ sql.RowUpdater = (*mergePoliciesWriter)(nil)
+var _ sql.RowInserter = (*mergePoliciesWriter)(nil)
+var _ sql.RowDeleter = (*mergePoliciesWriter)(nil)
+
+type mergePoliciesWriter struct {
+	mt                      *MergePoliciesTable
+	errDuringStatementBegin error
+	tableWriter             dsess.TableWriter
+}
+
+func newMergePoliciesWriter(mt *MergePoliciesTable) *mergePoliciesWriter {
+	return &mergePoliciesWriter{mt: mt}
+}
+
+// validateMergePolicyRow returns an error if |r| does not declare a valid merge policy.
+func validateMergePolicyRow(r sql.Row) error {
+	var fields [4]string
+	for i := range fields {
+		if r[i] == nil {
+			continue
+		}
+		s, ok := r[i].(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T in %s", r[i], doltdb.MergePoliciesTableName)
+		}
+		fields[i] = s
+	}
+	_, err := doltdb.NewMergePolicy(fields[0], fields[1], fields[2], fields[3])
+	return err
+}
+
+// Insert inserts the row given, returning an error if it cannot. Insert will be called once for each row to process
+// for the insert operation, which may involve many rows. After all rows in an operation have been processed, Close
+// is called.
+func (mw *mergePoliciesWriter) Insert(ctx *sql.Context, r sql.Row) error {
+	if err := mw.errDuringStatementBegin; err != nil {
+		return err
+	}
+	if err := validateMergePolicyRow(r); err != nil {
+		return err
+	}
+	return mw.tableWriter.Insert(ctx, r)
+}
+
+// Update the given row. Provides both the old and new rows.
+func (mw *mergePoliciesWriter) Update(ctx *sql.Context, old sql.Row, new sql.Row) error {
+	if err := mw.errDuringStatementBegin; err != nil {
+		return err
+	}
+	if err := validateMergePolicyRow(new); err != nil {
+		return err
+	}
+	return mw.tableWriter.Update(ctx, old, new)
+}
+
+// Delete deletes the given row. Returns ErrDeleteRowNotFound if the row was not found. Delete will be called once for
+// each row to process for the delete operation, which may involve many rows. After all rows have been processed,
+// Close is called.
+func (mw *mergePoliciesWriter) Delete(ctx *sql.Context, r sql.Row) error {
+	if err := mw.errDuringStatementBegin; err != nil {
+		return err
+	}
+	return mw.tableWriter.Delete(ctx, r)
+}
+
+// StatementBegin is called before the first operation of a statement. Integrators should mark the state of the data
+// in some way that it may be returned to in the case of an error.
+func (mw *mergePoliciesWriter) StatementBegin(ctx *sql.Context) {
+	dbName := ctx.GetCurrentDatabase()
+	dSess := dsess.DSessFromSess(ctx.Session)
+
+	// TODO: this needs to use a revision qualified name
+	roots, _ := dSess.GetRoots(ctx, dbName)
+	dbState, ok, err := dSess.LookupDbState(ctx, dbName)
+	if err != nil {
+		mw.errDuringStatementBegin = err
+		return
+	}
+	if !ok {
+		mw.errDuringStatementBegin = fmt.Errorf("no root value found in session")
+		return
+	}
+
+	tname := doltdb.TableName{Name: doltdb.MergePoliciesTableName}
+	found, err := roots.Working.HasTable(ctx, tname)
+	if err != nil {
+		mw.errDuringStatementBegin = err
+		return
+	}
+
+	if !found {
+		// underlying table doesn't exist. Record this, then create the table.
+		newRootValue, err := doltdb.CreateEmptyTable(ctx, roots.Working, tname, doltdb.MergePoliciesSchema)
+		if err != nil {
+			mw.errDuringStatementBegin = err
+			return
+		}
+
+		if dbState.WorkingSet() == nil {
+			mw.errDuringStatementBegin = doltdb.ErrOperationNotSupportedInDetachedHead
+			return
+		}
+
+		// We use WriteSession.SetWorkingSet instead of DoltSession.SetWorkingRoot because we want to avoid modifying the root
+		// until the end of the transaction, but we still want the WriteSession to be able to find the newly
+		// created table.
+		if ws := dbState.WriteSession(); ws != nil {
+			err = ws.SetWorkingSet(ctx, dbState.WorkingSet().WithWorkingRoot(newRootValue))
+			if err != nil {
+				mw.errDuringStatementBegin = err
+				return
+			}
+		}
+
+		err = dSess.SetWorkingRoot(ctx, dbName, newRootValue)
+		if err != nil {
+			mw.errDuringStatementBegin = err
+			return
+		}
+	}
+
+	if ws := dbState.WriteSession(); ws != nil {
+		tableWriter, err := ws.GetTableWriter(ctx, tname, dbName, dSess.SetWorkingRoot, false)
+		if err != nil {
+			mw.errDuringStatementBegin = err
+			return
+		}
+		mw.tableWriter = tableWriter
+		tableWriter.StatementBegin(ctx)
+	}
+}
+
+// DiscardChanges is called if a statement encounters an error, and all current changes since the statement beginning
+// should be discarded.
+func (mw *mergePoliciesWriter) DiscardChanges(ctx *sql.Context, errorEncountered error) error {
+	if mw.tableWriter != nil {
+		return mw.tableWriter.DiscardChanges(ctx, errorEncountered)
+	}
+	return nil
+}
+
+// StatementComplete is called after the last operation of the statement, indicating that it has successfully completed.
+// The mark set in StatementBegin may be removed, and a new one should be created on the next StatementBegin.
+func (mw *mergePoliciesWriter) StatementComplete(ctx *sql.Context) error {
+	if mw.tableWriter != nil {
+		return mw.tableWriter.StatementComplete(ctx)
+	}
+	return nil
+}
+
+// Close finalizes the write operation, persisting the result.
+func (mw mergePoliciesWriter) Close(ctx *sql.Context) error {
+	if mw.tableWriter != nil {
+		return mw.tableWriter.Close(ctx)
+	}
+	return nil
+}
+
+var _ sql.Table = (*MergeResolutionsTable)(nil)
+var _ sql.IndexAddressableTable = (*MergeResolutionsTable)(nil)
+
+// MergeResolutionsTable is the read-only system table listing the cells that merge policies resolved during the
+// last merge into the branch that used a policy.
+type MergeResolutionsTable struct {
+	backingTable VersionableTable
+}
+
+// NewMergeResolutionsTable creates a MergeResolutionsTable. |backingTable| is nil if no merge has recorded any
+// resolutions yet.
+func NewMergeResolutionsTable(_ *sql.Context, backingTable VersionableTable) sql.Table {
+	return &MergeResolutionsTable{backingTable: backingTable}
+}
+
+func (rt *MergeResolutionsTable) Name() string {
+	return doltdb.MergeResolutionsTableName
+}
+
+func (rt *MergeResolutionsTable) String() string {
+	return doltdb.MergeResolutionsTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the dolt_merge_resolutions system table.
+func (rt *MergeResolutionsTable) Schema() sql.Schema {
+	varchar := sqlTypes.MustCreateString(sqltypes.VarChar, defaultStringsLen, sql.Collation_Default)
+	return []*sql.Column{
+		{Name: doltdb.MergeResolutionsTableNameCol, Type: varchar, Source: doltdb.MergeResolutionsTableName, PrimaryKey: true, Nullable: false},
+		{Name: doltdb.MergeResolutionsRowKeyCol, Type: varchar, Source: doltdb.MergeResolutionsTableName, PrimaryKey: true, Nullable: false},
+		{Name: doltdb.MergeResolutionsColumnNameCol, Type: varchar, Source: doltdb.MergeResolutionsTableName, PrimaryKey: true, Nullable: false},
+		{Name: doltdb.MergeResolutionsStrategyCol, Type: varchar, Source: doltdb.MergeResolutionsTableName, PrimaryKey: false, Nullable: false},
+		{Name: doltdb.MergeResolutionsOurValueCol, Type: sqlTypes.LongText, Source: doltdb.MergeResolutionsTableName, PrimaryKey: false, Nullable: true},
+		{Name: doltdb.MergeResolutionsTheirValueCol, Type: sqlTypes.LongText, Source: doltdb.MergeResolutionsTableName, PrimaryKey: false, Nullable: true},
+		{Name: doltdb.MergeResolutionsMergedValueCol, Type: sqlTypes.LongText, Source: doltdb.MergeResolutionsTableName, PrimaryKey: false, Nullable: true},
+	}
+}
+
+func (rt *MergeResolutionsTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data.
+func (rt *MergeResolutionsTable) Partitions(context *sql.Context) (sql.PartitionIter, error) {
+	if rt.backingTable == nil {
+		// no backing table; return an empty iter.
+		return index.SinglePartitionIterFromNomsMap(nil), nil
+	}
+	return rt.backingTable.Partitions(context)
+}
+
+func (rt *MergeResolutionsTable) PartitionRows(context *sql.Context, partition sql.Partition) (sql.RowIter, error) {
+	if rt.backingTable == nil {
+		// no backing table; return an empty iter.
+		return sql.RowsToRowIter(), nil
+	}
+	return rt.backingTable.PartitionRows(context, partition)
+}
+
+func (rt *MergeResolutionsTable) LockedToRoot(ctx *sql.Context, root doltdb.RootValue) (sql.IndexAddressableTable, error) {
+	if rt.backingTable == nil {
+		return rt, nil
+	}
+	return rt.backingTable.LockedToRoot(ctx, root)
+}
+
+// IndexedAccess implements IndexAddressableTable, but MergeResolutionsTable has no indexes.
+// Thus, this should never be called.
+func (rt *MergeResolutionsTable) IndexedAccess(lookup sql.IndexLookup) sql.IndexedTable {
+	panic("Unreachable")
+}
+
+// GetIndexes implements IndexAddressableTable, but MergeResolutionsTable has no indexes.
+func (rt *MergeResolutionsTable) GetIndexes(ctx *sql.Context) ([]sql.Index, error) {
+	return nil, nil
+}
+
+func (rt *MergeResolutionsTable) PreciseMatch() bool {
+	return true
+}
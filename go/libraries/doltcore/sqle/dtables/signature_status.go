@@ -0,0 +1,50 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/utils/gpg"
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+// signatureStatuses computes the signature_status column of the dolt_log and dolt_commits tables. The allowed signers
+// are only loaded once a signed commit is found, so tables without signed commits don't depend on them.
+type signatureStatuses struct {
+	signers *gpg.AllowedSigners
+}
+
+func (s *signatureStatuses) status(ctx *sql.Context, cm *doltdb.Commit, meta *datas.CommitMeta) (string, error) {
+	if meta.Signature == "" {
+		return string(gpg.SignatureStatusUnsigned), nil
+	}
+
+	if s.signers == nil {
+		signers, err := dsess.GetAllowedSigners()
+		if err != nil {
+			return "", err
+		}
+		s.signers = signers
+	}
+
+	v, err := doltdb.VerifyCommitSignature(ctx, cm, s.signers)
+	if err != nil {
+		return "", err
+	}
+	return string(v.Status), nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+// UnfetchedTablesTable is a sql.Table implementation that implements a system table which shows the tables whose
+// data wasn't fetched by a partial clone or fetch. These tables are left out of the table names of the database.
+type UnfetchedTablesTable struct {
+	tableName  string
+	schemaName string
+	root       doltdb.RootValue
+}
+
+var _ sql.Table = (*UnfetchedTablesTable)(nil)
+
+// NewUnfetchedTablesTable creates an UnfetchedTablesTable.
+func NewUnfetchedTablesTable(tableName, schemaName string, root doltdb.RootValue) sql.Table {
+	return &UnfetchedTablesTable{tableName: tableName, schemaName: schemaName, root: root}
+}
+
+// Name implements the interface sql.Table.
+func (ut *UnfetchedTablesTable) Name() string {
+	return ut.tableName
+}
+
+// String implements the interface sql.Table.
+func (ut *UnfetchedTablesTable) String() string {
+	return ut.tableName
+}
+
+// Schema implements the interface sql.Table.
+func (ut *UnfetchedTablesTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "table_name", Type: types.Text, Source: ut.tableName, PrimaryKey: true},
+	}
+}
+
+// Collation implements the interface sql.Table.
+func (ut *UnfetchedTablesTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions implements the interface sql.Table.
+func (ut *UnfetchedTablesTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows implements the interface sql.Table.
+func (ut *UnfetchedTablesTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	names, err := doltdb.GetUnfetchedTableNames(ctx, ut.root, ut.schemaName)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]sql.Row, len(names))
+	for i, name := range names {
+		rows[i] = sql.Row{name}
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
@@ -15,6 +15,7 @@
 package enginetest
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/dolthub/go-mysql-server/enginetest"
@@ -45,6 +46,8 @@ type BranchControlTestAssertion struct {
 	Expected       []sql.Row
 	ExpectedErr    *errors.Kind
 	ExpectedErrStr string
+	// SkipResultsCheck only asserts that the query succeeds, for queries whose results include commit hashes.
+	SkipResultsCheck bool
 }
 
 // BranchControlBlockTest are tests for quickly verifying that a command is blocked before the appropriate entry is
@@ -1361,6 +1364,513 @@ var BranchControlTests = []BranchControlTest{
 			},
 		},
 	},
+	{
+		Name: "Deny hides branches from reads",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"INSERT INTO dolt_branch_control VALUES ('%', '%', 'root', 'localhost', 'admin');",
+			"INSERT INTO dolt_branch_control VALUES ('%', '%', '%', '%', 'write');",
+			"CREATE USER testuser@localhost;",
+			"GRANT ALL ON *.* TO testuser@localhost;",
+			"REVOKE SUPER ON *.* FROM testuser@localhost;",
+			"CREATE USER alice@localhost;",
+			"GRANT ALL ON *.* TO alice@localhost;",
+			"REVOKE SUPER ON *.* FROM alice@localhost;",
+			"CREATE TABLE test (pk BIGINT PRIMARY KEY);",
+			"INSERT INTO test VALUES (1);",
+			"CALL DOLT_ADD('-A');",
+			"CALL DOLT_COMMIT('-m', 'setup commit');",
+			"CALL DOLT_BRANCH('customer_a');",
+			"CALL DOLT_CHECKOUT('customer_a');",
+			"INSERT INTO test VALUES (2);",
+			"CALL DOLT_COMMIT('-am', 'customer a commit');",
+			"CALL DOLT_TAG('customer_a_tag', 'customer_a');",
+			"SET @customer_a_hash = HASHOF('customer_a');",
+			"CALL DOLT_CHECKOUT('main');",
+			"INSERT INTO dolt_branch_control VALUES ('%', 'customer_a', '%', '%', 'deny');",
+			"INSERT INTO dolt_branch_control VALUES ('%', 'customer_a', 'alice', 'localhost', 'read');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT name FROM dolt_branches ORDER BY name;",
+				Expected: []sql.Row{{"customer_a"}, {"main"}},
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT * FROM `mydb/customer_a`.test ORDER BY pk;",
+				Expected: []sql.Row{{1}, {2}},
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT * FROM test AS OF 'customer_a' ORDER BY pk;",
+				Expected: []sql.Row{{1}, {2}},
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "INSERT INTO `mydb/customer_a`.test VALUES (3);",
+				ExpectedErr: branch_control.ErrIncorrectPermissions,
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT name FROM dolt_branches ORDER BY name;",
+				Expected: []sql.Row{{"main"}},
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "SELECT * FROM `mydb/customer_a`.test;",
+				ExpectedErr: sql.ErrDatabaseNotFound,
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "USE `mydb/customer_a`;",
+				ExpectedErr: sql.ErrDatabaseNotFound,
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "CALL DOLT_CHECKOUT('customer_a');",
+				ExpectedErr: sql.ErrDatabaseNotFound,
+			},
+			{ // The failed checkout leaves the session on its original branch
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT * FROM test;",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				User:           "testuser",
+				Host:           "localhost",
+				Query:          "SELECT * FROM test AS OF 'customer_a';",
+				ExpectedErrStr: "branch not found: customer_a",
+			},
+			{
+				User:           "testuser",
+				Host:           "localhost",
+				Query:          "SELECT * FROM dolt_diff('main', 'customer_a', 'test');",
+				ExpectedErrStr: "branch not found: customer_a",
+			},
+			{
+				User:           "testuser",
+				Host:           "localhost",
+				Query:          "SELECT * FROM dolt_log('customer_a');",
+				ExpectedErrStr: "branch not found: customer_a",
+			},
+			{
+				User:           "testuser",
+				Host:           "localhost",
+				Query:          "SELECT * FROM dolt_changes('customer_a');",
+				ExpectedErrStr: "branch not found: customer_a",
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM dolt_changes('customer_a');",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				User:           "testuser",
+				Host:           "localhost",
+				Query:          "SELECT HASHOF('customer_a');",
+				ExpectedErrStr: "invalid ref spec",
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM dolt_commits WHERE message = 'customer a commit';",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:           "testuser",
+				Host:           "localhost",
+				Query:          "SELECT * FROM test AS OF 'customer_a_tag';",
+				ExpectedErrStr: "branch not found: customer_a_tag",
+			},
+			{
+				User:           "testuser",
+				Host:           "localhost",
+				Query:          "SELECT * FROM test AS OF @customer_a_hash;",
+				ExpectedErrStr: "target commit not found",
+			},
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "SELECT * FROM `mydb/customer_a_tag`.test;",
+				ExpectedErr: sql.ErrDatabaseNotFound,
+			},
+			{
+				User:           "testuser",
+				Host:           "localhost",
+				Query:          "SELECT * FROM dolt_log(@customer_a_hash);",
+				ExpectedErrStr: "target commit not found",
+			},
+			{
+				User:           "testuser",
+				Host:           "localhost",
+				Query:          "SELECT * FROM dolt_log('customer_a_tag');",
+				ExpectedErrStr: "branch not found: customer_a_tag",
+			},
+			{
+				User:           "testuser",
+				Host:           "localhost",
+				Query:          "SELECT * FROM dolt_diff('main', @customer_a_hash, 'test');",
+				ExpectedErrStr: "target commit not found",
+			},
+			{
+				User:           "testuser",
+				Host:           "localhost",
+				Query:          "SELECT * FROM dolt_diff_summary('main', 'customer_a_tag');",
+				ExpectedErrStr: "branch not found: customer_a_tag",
+			},
+			{
+				User:           "testuser",
+				Host:           "localhost",
+				Query:          "SELECT * FROM dolt_history_test AS OF 'customer_a_tag';",
+				ExpectedErrStr: "branch not found: customer_a_tag",
+			},
+			{
+				User:           "testuser",
+				Host:           "localhost",
+				Query:          "SELECT * FROM dolt_diff_test AS OF @customer_a_hash;",
+				ExpectedErrStr: "target commit not found",
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM dolt_history_test WHERE pk = 2;",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM dolt_diff_test WHERE to_pk = 2;",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT * FROM test AS OF 'customer_a_tag' ORDER BY pk;",
+				Expected: []sql.Row{{1}, {2}},
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "SELECT * FROM `mydb/customer_a_tag`.test ORDER BY pk;",
+				Expected: []sql.Row{{1}, {2}},
+			},
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM dolt_log('--decorate', 'full') WHERE refs LIKE '%customer_a%';",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT name FROM dolt_branches ORDER BY name;",
+				Expected: []sql.Row{{"customer_a"}, {"main"}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM dolt_commits WHERE message = 'customer a commit';",
+				Expected: []sql.Row{{1}},
+			},
+		},
+	},
+	{
+		Name: "Protected branches are only updated by merging approved branches",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"INSERT INTO dolt_branch_control VALUES ('%', '%', 'root', 'localhost', 'admin');",
+			"INSERT INTO dolt_branch_control VALUES ('%', '%', '%', '%', 'write');",
+			"CREATE USER alice@localhost;",
+			"GRANT ALL ON *.* TO alice@localhost;",
+			"CREATE USER bob@localhost;",
+			"GRANT ALL ON *.* TO bob@localhost;",
+			"CREATE TABLE test (pk BIGINT PRIMARY KEY);",
+			"INSERT INTO test VALUES (1);",
+			"CALL DOLT_ADD('-A');",
+			"CALL DOLT_COMMIT('-m', 'setup commit');",
+			"INSERT INTO dolt_branch_protection VALUES ('%', 'main', 2, NULL);",
+			"CALL DOLT_BRANCH('feature');",
+			"CALL DOLT_CHECKOUT('feature');",
+			"INSERT INTO test VALUES (2);",
+			"CALL DOLT_COMMIT('-am', 'feature commit');",
+			"CALL DOLT_CHECKOUT('main');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT * FROM dolt_branch_protection;",
+				Expected: []sql.Row{{"%", "main", uint32(2), nil}},
+			},
+			{
+				User:        "root",
+				Host:        "localhost",
+				Query:       "INSERT INTO test VALUES (10);",
+				ExpectedErr: branch_control.ErrProtectedBranch,
+			},
+			{
+				User:        "root",
+				Host:        "localhost",
+				Query:       "CALL DOLT_COMMIT('--allow-empty', '-m', 'direct commit');",
+				ExpectedErr: branch_control.ErrProtectedBranch,
+			},
+			{
+				User:        "root",
+				Host:        "localhost",
+				Query:       "CALL DOLT_BRANCH('-m', 'main', 'main2');",
+				ExpectedErr: branch_control.ErrProtectedBranch,
+			},
+			{
+				User:        "root",
+				Host:        "localhost",
+				Query:       "CALL DOLT_MERGE('feature');",
+				ExpectedErr: branch_control.ErrProtectedBranchMerge,
+			},
+			{
+				User:        "root",
+				Host:        "localhost",
+				Query:       "INSERT INTO dolt_approvals SELECT 'mydb', 'feature', 'root', HASHOF('feature');",
+				ExpectedErr: branch_control.ErrApprovingOwnCommit,
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "INSERT INTO dolt_approvals SELECT 'mydb', 'feature', 'bob', HASHOF('feature');",
+				ExpectedErr: branch_control.ErrApprovingForAnotherUser,
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "INSERT INTO dolt_approvals SELECT 'mydb', 'feature', 'alice', HASHOF('main');",
+				ExpectedErr: branch_control.ErrApprovingStaleCommit,
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "INSERT INTO dolt_approvals SELECT 'mydb', 'feature', 'alice', HASHOF('feature');",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				User:        "root",
+				Host:        "localhost",
+				Query:       "CALL DOLT_MERGE('feature');",
+				ExpectedErr: branch_control.ErrProtectedBranchMerge,
+			},
+			{
+				User:     "bob",
+				Host:     "localhost",
+				Query:    "INSERT INTO dolt_approvals SELECT 'mydb', 'feature', 'bob', HASHOF('feature');",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT approver FROM dolt_approvals WHERE commit_hash = HASHOF('feature') ORDER BY approver;",
+				Expected: []sql.Row{{"alice"}, {"bob"}},
+			},
+			{ // Moving the head of the approved branch invalidates its approvals
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL DOLT_CHECKOUT('feature');",
+				Expected: []sql.Row{{0, "Switched to branch 'feature'"}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "INSERT INTO test VALUES (3);",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				User:             "root",
+				Host:             "localhost",
+				Query:            "CALL DOLT_COMMIT('-am', 'another feature commit');",
+				SkipResultsCheck: true,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL DOLT_CHECKOUT('main');",
+				Expected: []sql.Row{{0, "Switched to branch 'main'"}},
+			},
+			{
+				User:        "root",
+				Host:        "localhost",
+				Query:       "CALL DOLT_MERGE('feature');",
+				ExpectedErr: branch_control.ErrProtectedBranchMerge,
+			},
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "INSERT INTO dolt_approvals SELECT 'mydb', 'feature', 'alice', HASHOF('feature');",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				User:     "bob",
+				Host:     "localhost",
+				Query:    "INSERT INTO dolt_approvals SELECT 'mydb', 'feature', 'bob', HASHOF('feature');",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				User:             "root",
+				Host:             "localhost",
+				Query:            "CALL DOLT_MERGE('feature');",
+				SkipResultsCheck: true,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT * FROM test ORDER BY pk;",
+				Expected: []sql.Row{{1}, {2}, {3}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "DELETE FROM dolt_branch_protection;",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "INSERT INTO test VALUES (10);",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+		},
+	},
+	{
+		Name: "Protected branches exclude the authenticated authors of every merged commit from approvals",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"INSERT INTO dolt_branch_control VALUES ('%', '%', 'root', 'localhost', 'admin');",
+			"INSERT INTO dolt_branch_control VALUES ('%', '%', '%', '%', 'write');",
+			"CREATE USER alice@localhost;",
+			"GRANT ALL ON *.* TO alice@localhost;",
+			"CREATE USER bob@localhost;",
+			"GRANT ALL ON *.* TO bob@localhost;",
+			"CREATE USER carol@localhost;",
+			"GRANT ALL ON *.* TO carol@localhost;",
+			"CREATE TABLE test (pk BIGINT PRIMARY KEY);",
+			"INSERT INTO test VALUES (1);",
+			"CALL DOLT_ADD('-A');",
+			"CALL DOLT_COMMIT('-m', 'setup commit');",
+			"INSERT INTO dolt_branch_protection VALUES ('%', 'main', 1, NULL);",
+			"CALL DOLT_BRANCH('feature');",
+			"CALL DOLT_CHECKOUT('feature');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "INSERT INTO test VALUES (2);",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{ // The author named within the commit does not change who authored it
+				User:             "alice",
+				Host:             "localhost",
+				Query:            "CALL DOLT_COMMIT('-am', 'alice commit', '--author', 'bob <bob@example.com>');",
+				SkipResultsCheck: true,
+			},
+			{
+				User:        "alice",
+				Host:        "localhost",
+				Query:       "INSERT INTO dolt_approvals SELECT 'mydb', 'feature', 'alice', HASHOF('feature');",
+				ExpectedErr: branch_control.ErrApprovingOwnCommit,
+			},
+			{
+				User:     "bob",
+				Host:     "localhost",
+				Query:    "INSERT INTO test VALUES (3);",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				User:             "bob",
+				Host:             "localhost",
+				Query:            "CALL DOLT_COMMIT('-am', 'bob commit');",
+				SkipResultsCheck: true,
+			},
+			{ // Alice did not author the head, so she may approve it...
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "INSERT INTO dolt_approvals SELECT 'mydb', 'feature', 'alice', HASHOF('feature');",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL DOLT_CHECKOUT('main');",
+				Expected: []sql.Row{{0, "Switched to branch 'main'"}},
+			},
+			{ // ...but her approval does not count, as she authored a commit that would be merged
+				User:        "root",
+				Host:        "localhost",
+				Query:       "CALL DOLT_MERGE('feature');",
+				ExpectedErr: branch_control.ErrProtectedBranchMerge,
+			},
+			{
+				User:     "carol",
+				Host:     "localhost",
+				Query:    "INSERT INTO dolt_approvals SELECT 'mydb', 'feature', 'carol', HASHOF('feature');",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				User:             "root",
+				Host:             "localhost",
+				Query:            "CALL DOLT_MERGE('feature');",
+				SkipResultsCheck: true,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT * FROM test ORDER BY pk;",
+				Expected: []sql.Row{{1}, {2}, {3}},
+			},
+		},
+	},
+	{
+		Name: "Protected branches treat commits without a recorded author as authored by every user",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"INSERT INTO dolt_branch_control VALUES ('%', '%', 'root', 'localhost', 'admin');",
+			"INSERT INTO dolt_branch_control VALUES ('%', '%', '%', '%', 'write');",
+			"CREATE USER alice@localhost;",
+			"GRANT ALL ON *.* TO alice@localhost;",
+			"CREATE TABLE test (pk BIGINT PRIMARY KEY);",
+			"INSERT INTO test VALUES (1);",
+			"CALL DOLT_ADD('-A');",
+			"CALL DOLT_COMMIT('-m', 'setup commit');",
+			"CALL DOLT_BRANCH('feature');",
+			"CALL DOLT_CHECKOUT('feature');",
+			"INSERT INTO test VALUES (2);",
+			// authors are only recorded while protection rules exist
+			"CALL DOLT_COMMIT('-am', 'feature commit', '--author', 'bob <bob@example.com>');",
+			"CALL DOLT_CHECKOUT('main');",
+			"INSERT INTO dolt_branch_protection VALUES ('%', 'main', 1, NULL);",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:     "alice",
+				Host:     "localhost",
+				Query:    "INSERT INTO dolt_approvals SELECT 'mydb', 'feature', 'alice', HASHOF('feature');",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{ // Any user may have created the commit, so no approval counts
+				User:        "root",
+				Host:        "localhost",
+				Query:       "CALL DOLT_MERGE('feature');",
+				ExpectedErr: branch_control.ErrProtectedBranchMerge,
+			},
+		},
+	},
 }
 
 func TestBranchControl(t *testing.T) {
@@ -1406,6 +1916,10 @@ func TestBranchControl(t *testing.T) {
 					t.Run(assertion.Query, func(t *testing.T) {
 						enginetest.AssertErrWithCtx(t, engine, harness, ctx, assertion.Query, nil, nil, assertion.ExpectedErrStr)
 					})
+				} else if assertion.SkipResultsCheck {
+					t.Run(assertion.Query, func(t *testing.T) {
+						enginetest.RunQueryWithContext(t, engine, harness, ctx, assertion.Query)
+					})
 				} else {
 					t.Run(assertion.Query, func(t *testing.T) {
 						enginetest.TestQueryWithContext(t, ctx, engine, harness, assertion.Query, assertion.Expected, nil, nil, nil)
@@ -1416,6 +1930,78 @@ func TestBranchControl(t *testing.T) {
 	}
 }
 
+// TestBranchControlRemoteRefs verifies that remote tracking branches, and refs resolved through the reflog, do not
+// expose the commits of denied branches. The remote and the reflog are backed by the local filesystem, so these
+// assertions cannot be run by the in-memory TestBranchControl.
+func TestBranchControlRemoteRefs(t *testing.T) {
+	harness := newDoltHarnessForLocalFilesystem(t)
+	defer harness.Close()
+	engine, err := harness.NewEngine(t)
+	require.NoError(t, err)
+	defer engine.Close()
+
+	ctx := enginetest.NewContext(harness)
+	ctx.NewCtxWithClient(sql.Client{
+		User:    "root",
+		Address: "localhost",
+	})
+	engine.EngineAnalyzer().Catalog.MySQLDb.AddRootAccount()
+	engine.EngineAnalyzer().Catalog.MySQLDb.SetPersister(&mysql_db.NoopPersister{})
+
+	setUpScript := []string{
+		"DELETE FROM dolt_branch_control WHERE user = '%';",
+		"INSERT INTO dolt_branch_control VALUES ('%', '%', 'root', 'localhost', 'admin');",
+		"INSERT INTO dolt_branch_control VALUES ('%', '%', '%', '%', 'write');",
+		"CREATE USER testuser@localhost;",
+		"GRANT ALL ON *.* TO testuser@localhost;",
+		"REVOKE SUPER ON *.* FROM testuser@localhost;",
+		"CREATE TABLE test (pk BIGINT PRIMARY KEY);",
+		"INSERT INTO test VALUES (1);",
+		"CALL DOLT_ADD('-A');",
+		"CALL DOLT_COMMIT('-m', 'setup commit');",
+		"CALL DOLT_BRANCH('customer_a');",
+		"CALL DOLT_CHECKOUT('customer_a');",
+		"INSERT INTO test VALUES (2);",
+		"CALL DOLT_COMMIT('-am', 'customer a commit');",
+		"CALL DOLT_TAG('customer_a_tag', 'customer_a');",
+		"CALL DOLT_CHECKOUT('main');",
+		"CALL DOLT_REMOTE('add', 'origin', 'file://" + filepath.ToSlash(t.TempDir()) + "');",
+		"CALL DOLT_PUSH('origin', 'main');",
+		"CALL DOLT_PUSH('origin', 'customer_a');",
+		"INSERT INTO dolt_branch_control VALUES ('%', 'customer_a', '%', '%', 'deny');",
+	}
+	for _, statement := range setUpScript {
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, statement)
+	}
+
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "SELECT name FROM dolt_remote_branches ORDER BY name;",
+		[]sql.Row{{"remotes/origin/customer_a"}, {"remotes/origin/main"}}, nil, nil, nil)
+
+	ctx = ctx.NewCtxWithClient(sql.Client{
+		User:    "testuser",
+		Address: "localhost",
+	})
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "SELECT name FROM dolt_remote_branches ORDER BY name;",
+		[]sql.Row{{"remotes/origin/main"}}, nil, nil, nil)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "SELECT * FROM test AS OF 'origin/main';",
+		[]sql.Row{{1}}, nil, nil, nil)
+	enginetest.AssertErrWithCtx(t, engine, harness, ctx, "SELECT * FROM test AS OF 'origin/customer_a';", nil, nil,
+		"branch not found: origin/customer_a")
+	enginetest.AssertErrWithCtx(t, engine, harness, ctx, "SELECT * FROM test AS OF 'remotes/origin/customer_a';", nil, nil,
+		"branch not found: remotes/origin/customer_a")
+	enginetest.AssertErrWithCtx(t, engine, harness, ctx, "SELECT * FROM dolt_log('origin/customer_a');", nil, nil,
+		"branch not found: origin/customer_a")
+
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "SELECT dolt_at_time('main', sysdate(6)) = HASHOF('main');",
+		[]sql.Row{{true}}, nil, nil, nil)
+	enginetest.AssertErrWithCtx(t, engine, harness, ctx, "SELECT dolt_at_time('customer_a', sysdate(6));", nil,
+		branch_control.ErrCannotReadBranch)
+	enginetest.AssertErrWithCtx(t, engine, harness, ctx, "SELECT dolt_at_time('customer_a_tag', sysdate(6));", nil,
+		branch_control.ErrCannotReadCommit)
+	enginetest.AssertErrWithCtx(t, engine, harness, ctx, "SELECT dolt_at_time('refs/remotes/origin/customer_a', sysdate(6));", nil,
+		branch_control.ErrCannotReadCommit)
+}
+
 func TestBranchControlBlocks(t *testing.T) {
 	for _, test := range BranchControlBlockTests {
 		t.Run(test.Name, func(t *testing.T) {
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -32,10 +33,14 @@ import (
 	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
 	"github.com/stretchr/testify/require"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/dolt_ci"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/signedcommits"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/statspro"
 	"github.com/dolthub/dolt/go/libraries/utils/config"
 	"github.com/dolthub/dolt/go/store/types"
@@ -684,6 +689,172 @@ func TestDoltUserPrivileges(t *testing.T) {
 	}
 }
 
+// TestDoltCIRunPrivileges tests that the saved queries of a workflow run by dolt_ci_run() are checked against the
+// privileges of the calling user.
+func TestDoltCIRunPrivileges(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	harness.Setup(setup.MydbData)
+	engine, err := harness.NewEngine(t)
+	require.NoError(t, err)
+	defer engine.Close()
+
+	engine.EngineAnalyzer().Catalog.MySQLDb.AddRootAccount()
+	engine.EngineAnalyzer().Catalog.MySQLDb.SetPersister(&mysql_db.NoopPersister{})
+	ctx := enginetest.NewContextWithClient(harness, sql.Client{User: "root", Address: "localhost"})
+	for _, q := range []string{
+		"create table secret (pk int primary key);",
+		"insert into secret values (1), (2);",
+		"create user tester@localhost;",
+		"grant execute on *.* to tester@localhost;",
+	} {
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, q)
+	}
+	dSess := dsess.DSessFromSess(ctx.Session)
+	tx, err := dSess.StartTransaction(ctx, sql.ReadWrite)
+	require.NoError(t, err)
+	ctx.SetTransaction(tx)
+	roots, ok := dSess.GetRoots(ctx, "mydb")
+	require.True(t, ok)
+	_, root, err := dtables.NewQueryCatalogEntryWithNameAsID(ctx, roots.Working, "read secret", "select * from secret", "")
+	require.NoError(t, err)
+	require.NoError(t, dSess.SetWorkingRoot(ctx, "mydb", root))
+	require.NoError(t, dSess.CommitTransaction(ctx, tx))
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_commit('-Am', 'add secret');")
+
+	sqlDb, err := dSess.Provider().Database(ctx, "mydb")
+	require.NoError(t, err)
+	db := sqlDb.(dsess.SqlDatabase)
+	require.NoError(t, dolt_ci.CreateDoltCITables(ctx, db, dsess.RunQuery, "root", "root@localhost"))
+	config, err := dolt_ci.ParseWorkflowConfig(strings.NewReader(`name: wf
+on:
+  push:
+    branches:
+      - main
+jobs:
+  - name: job
+    steps:
+      - name: step
+        saved_query_name: read secret
+        expected_rows: "== 2"
+`))
+	require.NoError(t, err)
+	wm := dolt_ci.NewWorkflowManager("root", "root@localhost", dsess.RunQuery)
+	require.NoError(t, wm.StoreAndCommit(ctx, db, config))
+	for _, table := range append(dolt_ci.ExpectedDoltCITablesOrdered.ActiveTableNames(), doltdb.TableName{Name: doltdb.DoltQueryCatalogTableName}) {
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, fmt.Sprintf("grant select on mydb.%s to tester@localhost;", table.Name))
+	}
+
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "call dolt_ci_run('wf');",
+		[]sql.Row{{"job", "step", "read secret", "passed", nil}}, nil, nil, nil)
+
+	testerCtx := enginetest.NewContextWithClient(harness, sql.Client{User: "tester", Address: "localhost"})
+	testerCtx.SetCurrentDatabase("mydb")
+	_, iter, _, err := engine.Query(testerCtx, "call dolt_ci_run('wf');")
+	require.NoError(t, err)
+	rows, err := sql.RowIterToRows(testerCtx, iter)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, "failed", rows[0][3])
+	require.Contains(t, rows[0][4], "command denied to user 'tester'@'localhost'")
+}
+
+// TestSignedCommitsPolicy tests that the server's signed commits policy is enforced on every way a protected branch
+// can be updated, not only dolt_commit.
+func TestSignedCommitsPolicy(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	harness.Setup(setup.MydbData)
+	engine, err := harness.NewEngine(t)
+	require.NoError(t, err)
+	defer engine.Close()
+
+	ctx := enginetest.NewContext(harness)
+	for _, q := range []string{
+		"create table t (pk int primary key, c int);",
+		"call dolt_commit('-Am', 'create t');",
+		"call dolt_branch('feature');",
+		"call dolt_checkout('feature');",
+		"insert into t values (1, 1);",
+		"call dolt_commit('-am', 'feature commit');",
+		"call dolt_checkout('main');",
+		"insert into t values (2, 2);",
+		"call dolt_commit('-am', 'main commit');",
+	} {
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, q)
+	}
+
+	harness.provider.(*sqle.DoltDatabaseProvider).AddCommitValidator(signedcommits.NewPolicy([]string{"main"}, ""))
+
+	rejected := func(t *testing.T, query string) {
+		_, iter, _, err := engine.Query(ctx, query)
+		if err == nil {
+			_, err = sql.RowIterToRows(ctx, iter)
+		}
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "requires commits signed by a trusted key")
+	}
+
+	t.Run("commit", func(t *testing.T) {
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, "insert into t values (3, 3);")
+		rejected(t, "call dolt_commit('-am', 'unsigned');")
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_reset('--hard');")
+	})
+	t.Run("merge", func(t *testing.T) {
+		rejected(t, "call dolt_merge('feature', '--no-ff', '-m', 'unsigned merge');")
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_reset('--hard');")
+	})
+	t.Run("cherry-pick", func(t *testing.T) {
+		rejected(t, "call dolt_cherry_pick('feature');")
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_reset('--hard');")
+	})
+	t.Run("revert", func(t *testing.T) {
+		rejected(t, "call dolt_revert('HEAD');")
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_reset('--hard');")
+	})
+	t.Run("reset", func(t *testing.T) {
+		rejected(t, "call dolt_reset('--hard', 'feature');")
+	})
+	t.Run("transaction commit", func(t *testing.T) {
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, "set @@dolt_transaction_commit = 1;")
+		rejected(t, "insert into t values (3, 3);")
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_reset('--hard');")
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, "set @@dolt_transaction_commit = 0;")
+	})
+	t.Run("unprotected branch", func(t *testing.T) {
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_checkout('feature');")
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, "insert into t values (4, 4);")
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_commit('-am', 'unsigned feature commit');")
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_checkout('main');")
+	})
+	t.Run("fast-forward merge", func(t *testing.T) {
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_branch('ahead', 'main');")
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_checkout('ahead');")
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, "insert into t values (5, 5);")
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_commit('-am', 'unsigned ahead commit');")
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_checkout('main');")
+		rejected(t, "call dolt_merge('ahead');")
+	})
+	t.Run("rebase", func(t *testing.T) {
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_rebase('-i', 'feature');")
+		rejected(t, "call dolt_rebase('--continue');")
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_rebase('--abort');")
+	})
+	t.Run("branch", func(t *testing.T) {
+		rejected(t, "call dolt_branch('-f', 'main', 'feature');")
+	})
+	t.Run("new database", func(t *testing.T) {
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, "create database newdb;")
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, "use newdb;")
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, "create table t (pk int primary key);")
+		rejected(t, "call dolt_commit('-Am', 'unsigned');")
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, "use mydb;")
+	})
+
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select message from dolt_log where commit_hash = hashof('main');",
+		[]sql.Row{{"main commit"}}, nil, nil, nil)
+}
+
 func TestJoinOps(t *testing.T) {
 	if types.IsFormat_LD(types.Format_Default) {
 		t.Skip("DOLT_LD keyless indexes are not sorted")
@@ -1320,6 +1491,11 @@ func TestDoltTag(t *testing.T) {
 	RunDoltTagTests(t, h)
 }
 
+func TestDoltNotes(t *testing.T) {
+	h := newDoltEnginetestHarness(t)
+	RunDoltNotesTests(t, h)
+}
+
 func TestDoltRemote(t *testing.T) {
 	h := newDoltEnginetestHarness(t)
 	RunDoltRemoteTests(t, h)
@@ -1483,6 +1659,16 @@ func TestDiffStatTableFunctionPrepared(t *testing.T) {
 	RunDiffStatTableFunctionTestsPrepared(t, harness)
 }
 
+func TestChangesTableFunction(t *testing.T) {
+	harness := newDoltEnginetestHarness(t)
+	RunChangesTableFunctionTests(t, harness)
+}
+
+func TestChangesTableFunctionPrepared(t *testing.T) {
+	harness := newDoltEnginetestHarness(t)
+	RunChangesTableFunctionTestsPrepared(t, harness)
+}
+
 func TestDiffSummaryTableFunction(t *testing.T) {
 	harness := newDoltEnginetestHarness(t)
 	RunDiffSummaryTableFunctionTests(t, harness)
@@ -1523,6 +1709,11 @@ func TestDoltReflogPrepared(t *testing.T) {
 	RunDoltReflogTestsPrepared(t, h)
 }
 
+func TestDoltPersistentReflog(t *testing.T) {
+	h := newDoltEnginetestHarness(t)
+	RunDoltPersistentReflogTests(t, h)
+}
+
 func TestCommitDiffSystemTable(t *testing.T) {
 	harness := newDoltEnginetestHarness(t)
 	RunCommitDiffSystemTableTests(t, harness)
@@ -2048,6 +2239,51 @@ func TestStatsAutoRefreshConcurrency(t *testing.T) {
 	}
 }
 
+// TestStatsHistoryAsOf tests that statistics requested by name for a table
+// that a query reads AS OF a commit describe the table at that commit once
+// they are built in the background.
+func TestStatsHistoryAsOf(t *testing.T) {
+	harness := newDoltHarness(t)
+	harness.Setup(setup.MydbData)
+	harness.configureStats = true
+	engine := mustNewEngine(t, harness)
+	defer engine.Close()
+
+	for _, q := range []string{
+		"create table xy (x bigint primary key, y int, key(y))",
+		"insert into xy select x, x % 2 from (with recursive inputs(x) as (select 1 union select x+1 from inputs where x < 2000) select * from inputs) dt",
+		"call dolt_commit('-Am', 'first')",
+		"call dolt_tag('v1')",
+		"delete from xy where x > 100",
+		"call dolt_commit('-am', 'second')",
+		"analyze table xy",
+		"set @@global.dolt_stats_history_enabled = 1",
+	} {
+		enginetest.RunQueryWithContext(t, engine, harness, nil, q)
+	}
+	defer sql.SystemVariables.SetGlobal(dsess.DoltStatsHistoryEnabled, int8(0))
+
+	statsProv := engine.EngineAnalyzer().Catalog.StatsProvider.(*statspro.Provider)
+	qual := sql.NewStatQualifier("mydb", "", "xy", "primary")
+	rowCount := func(ctx *sql.Context) uint64 {
+		stat, ok := statsProv.GetStats(ctx, qual, []string{"x"})
+		require.True(t, ok)
+		return stat.RowCount()
+	}
+
+	asOfCtx := enginetest.NewSession(harness)
+	_, iter, _, err := engine.Query(asOfCtx, "select count(*) from xy as of 'v1'")
+	require.NoError(t, err)
+	_, err = sql.RowIterToRows(asOfCtx, iter)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return rowCount(asOfCtx) == 2000
+	}, 10*time.Second, 10*time.Millisecond)
+
+	// other queries get the branch's statistics
+	require.Equal(t, uint64(100), rowCount(enginetest.NewSession(harness)))
+}
+
 func TestDoltWorkspace(t *testing.T) {
 	harness := newDoltEnginetestHarness(t)
 	RunDoltWorkspaceTests(t, harness)
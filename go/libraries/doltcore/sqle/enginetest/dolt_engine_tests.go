@@ -1060,6 +1060,16 @@ func RunDoltTagTests(t *testing.T, h DoltEnginetestHarness) {
 	}
 }
 
+func RunDoltNotesTests(t *testing.T, h DoltEnginetestHarness) {
+	for _, script := range DoltNotesTestScripts {
+		func() {
+			h := h.NewHarness(t)
+			defer h.Close()
+			enginetest.TestScript(t, h, script)
+		}()
+	}
+}
+
 func RunDoltRemoteTests(t *testing.T, h DoltEnginetestHarness) {
 	for _, script := range DoltRemoteTestScripts {
 		func() {
@@ -1220,6 +1230,26 @@ func RunDiffStatTableFunctionTestsPrepared(t *testing.T, harness DoltEnginetestH
 	}
 }
 
+func RunChangesTableFunctionTests(t *testing.T, harness DoltEnginetestHarness) {
+	for _, test := range ChangesTableFunctionScriptTests {
+		harness = harness.NewHarness(t)
+		harness.Setup(setup.MydbData)
+		t.Run(test.Name, func(t *testing.T) {
+			enginetest.TestScript(t, harness, test)
+		})
+	}
+}
+
+func RunChangesTableFunctionTestsPrepared(t *testing.T, harness DoltEnginetestHarness) {
+	for _, test := range ChangesTableFunctionScriptTests {
+		harness = harness.NewHarness(t)
+		harness.Setup(setup.MydbData)
+		t.Run(test.Name, func(t *testing.T) {
+			enginetest.TestScriptPrepared(t, harness, test)
+		})
+	}
+}
+
 func RunDiffSummaryTableFunctionTests(t *testing.T, harness DoltEnginetestHarness) {
 	for _, test := range DiffSummaryTableFunctionScriptTests {
 		t.Run(test.Name, func(t *testing.T) {
@@ -1973,6 +2003,21 @@ func RunDoltReflogTestsPrepared(t *testing.T, h DoltEnginetestHarness) {
 	}
 }
 
+func RunDoltPersistentReflogTests(t *testing.T, h DoltEnginetestHarness) {
+	for _, script := range DoltPersistentReflogTestScripts {
+		func() {
+			h = h.NewHarness(t)
+			defer h.Close()
+			h.UseLocalFileSystem()
+			h.SkipSetupCommit()
+			e := mustNewEngine(t, h)
+			defer e.Close()
+			h.Engine().Analyzer.Catalog.DbProvider.(*sqle.DoltDatabaseProvider).SetReflogPolicy(script.Policy)
+			enginetest.TestScriptWithEngine(t, e, h, script.ScriptTest)
+		}()
+	}
+}
+
 func RunDoltWorkspaceTests(t *testing.T, h DoltEnginetestHarness) {
 	for _, script := range DoltWorkspaceScriptTests {
 		func() {
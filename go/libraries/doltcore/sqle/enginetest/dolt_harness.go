@@ -254,6 +254,7 @@ func (d *DoltHarness) NewEngine(t *testing.T) (enginetest.QueryEngine, error) {
 		}
 		e.Analyzer.ExecBuilder = rowexec.NewOverrideBuilder(kvexec.Builder{})
 		d.engine = e
+		doltProvider.SetStatementRunner(e)
 
 		ctx := enginetest.NewContext(d)
 		databases := pro.AllDatabases(ctx)
@@ -436,6 +437,7 @@ func (d *DoltHarness) NewDatabases(names ...string) []sql.Database {
 	e := enginetest.NewEngineWithProvider(d.t, d, d.provider)
 	require.NoError(d.t, err)
 	d.engine = e
+	doltProvider.SetStatementRunner(e)
 
 	for _, name := range names {
 		err := d.provider.CreateDatabase(enginetest.NewContext(d), name)
@@ -488,7 +490,9 @@ func (d *DoltHarness) NewReadOnlyEngine(provider sql.DatabaseProvider) (enginete
 	d.session, err = dsess.NewDoltSession(enginetest.NewBaseSession(), readOnlyProvider, d.multiRepoEnv.Config(), d.branchControl, d.statsPro, writer.NewWriteSession)
 	require.NoError(d.t, err)
 
-	return enginetest.NewEngineWithProvider(nil, d, readOnlyProvider), nil
+	e := enginetest.NewEngineWithProvider(nil, d, readOnlyProvider)
+	readOnlyProvider.SetStatementRunner(e)
+	return e, nil
 }
 
 func (d *DoltHarness) NewDatabaseProvider() sql.MutableDatabaseProvider {
@@ -24,6 +24,7 @@ import (
 	"github.com/dolthub/go-mysql-server/sql/types"
 	"github.com/dolthub/vitess/go/vt/sqlparser"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtablefunctions"
 )
 
@@ -4937,6 +4938,89 @@ var DoltTagTestScripts = []queries.ScriptTest{
 	},
 }
 
+var DoltNotesTestScripts = []queries.ScriptTest{
+	{
+		Name: "dolt-notes: add, replace and remove notes",
+		SetUpScript: []string{
+			"CREATE TABLE test(pk int primary key);",
+			"CALL DOLT_COMMIT('-Am','created table test')",
+			"INSERT INTO test VALUES (0),(1),(2);",
+			"CALL DOLT_COMMIT('-am','inserted rows')",
+			"SET @head = hashof('HEAD');",
+			"SET @parent = hashof('HEAD~1');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL DOLT_NOTES('add', '-m', 'ticket 123')",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "SELECT commit_hash = @head, author, email, IF(date IS NULL, NULL, 'not null'), note FROM dolt_notes",
+				Expected: []sql.Row{{true, "billy bob", "bigbillieb@fake.horse", "not null", "ticket 123"}},
+			},
+			{
+				Query:          "CALL DOLT_NOTES('add', '-m', 'ticket 456')",
+				ExpectedErrStr: "cannot add note: the commit already has a note, use --force to replace it",
+			},
+			{
+				Query:    "CALL DOLT_NOTES('add', '-m', 'signed off', '--author', 'John Doe <john@doe.com>', 'HEAD~1')",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "SELECT commit_hash = @parent, author, note FROM dolt_notes WHERE commit_hash = @parent",
+				Expected: []sql.Row{{true, "John Doe", "signed off"}},
+			},
+			{
+				Query:    "CALL DOLT_NOTES('add', '-f', '-m', 'ticket 456')",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "SELECT note FROM dolt_notes WHERE commit_hash = @head",
+				Expected: []sql.Row{{"ticket 456"}},
+			},
+			{
+				Query:    "SELECT commit_hash = @head, message, notes FROM dolt_log('--notes') LIMIT 2",
+				Expected: []sql.Row{{true, "inserted rows", "ticket 456"}, {false, "created table test", "signed off"}},
+			},
+			{
+				Query:    "CALL DOLT_NOTES('remove', 'HEAD~1')",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "SELECT count(*) FROM dolt_notes",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:          "CALL DOLT_NOTES('remove', 'HEAD~1')",
+				ExpectedErrStr: "note not found",
+			},
+			{
+				Query:          "CALL DOLT_NOTES('show')",
+				ExpectedErrStr: "error: invalid argument, use 'dolt_notes' system table to show notes",
+			},
+		},
+	},
+	{
+		Name: "dolt-notes: notes do not change commits",
+		SetUpScript: []string{
+			"CREATE TABLE test(pk int primary key);",
+			"CALL DOLT_COMMIT('-Am','created table test')",
+			"SET @head = hashof('HEAD');",
+			"CALL DOLT_NOTES('add', '-m', 'job 42')",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "SELECT hashof('HEAD') = @head",
+				Expected: []sql.Row{{true}},
+			},
+			{
+				Query:    "SELECT count(*) FROM dolt_log",
+				Expected: []sql.Row{{3}},
+			},
+		},
+	},
+}
+
 var DoltRemoteTestScripts = []queries.ScriptTest{
 	{
 		Name: "dolt-remote: SQL add remotes",
@@ -5322,6 +5406,160 @@ var DoltReflogTestScripts = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "dolt_at_time: resolve refs by wall-clock time",
+		SetUpScript: []string{
+			"create table t1(pk int primary key);",
+			"call dolt_commit('-Am', 'creating table t1');",
+			"call dolt_checkout('-b', 'branch1');",
+			"insert into t1 values(1);",
+			"call dolt_commit('-Am', 'inserting row 1');",
+			"call dolt_tag('tag1');",
+			"set @beforeReset = hashof('branch1');",
+			"set @t = sysdate(6);",
+			"call dolt_reset('--hard', 'HEAD~1');",
+			"set @afterReset = hashof('branch1');",
+			"call dolt_checkout('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select dolt_at_time('branch1', @t) = @beforeReset, dolt_at_time('branch1', sysdate(6)) = @afterReset;",
+				Expected: []sql.Row{{true, true}},
+			},
+			{
+				// resetting a branch doesn't change where it was in the past
+				Query:    "select * from t1 as of dolt_at_time('branch1', @t);",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "select * from t1 as of dolt_at_time('branch1', sysdate(6));",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select dolt_at_time('refs/heads/BRANCH1', @t) = @beforeReset, dolt_at_time('tag1', @t) = @beforeReset;",
+				Expected: []sql.Row{{true, true}},
+			},
+			{
+				Query:    "call dolt_branch('-D', 'branch1');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				// deleted branches can still be resolved at times they existed
+				Query:    "select dolt_at_time('branch1', @t) = @beforeReset;",
+				Expected: []sql.Row{{true}},
+			},
+			{
+				Query:          "select dolt_at_time('branch1', '2999-01-01 00:00:00');",
+				ExpectedErrStr: "ref 'branch1' did not exist at 2999-01-01 00:00:00",
+			},
+			{
+				Query:          "select dolt_at_time('main', '2000-01-01 00:00:00');",
+				ExpectedErrStr: "no reflog entry found at or before 2000-01-01 00:00:00",
+			},
+			{
+				Query:    "select dolt_at_time('main', NULL), dolt_at_time(NULL, utc_timestamp());",
+				Expected: []sql.Row{{nil, nil}},
+			},
+		},
+	},
+}
+
+// PersistentReflogScriptTest is a script test run with a persistent reflog that uses the retention policy of the test.
+type PersistentReflogScriptTest struct {
+	queries.ScriptTest
+	Policy doltdb.ReflogPolicy
+}
+
+var DoltPersistentReflogTestScripts = []PersistentReflogScriptTest{
+	{
+		Policy: doltdb.ReflogPolicy{Persistent: true},
+		ScriptTest: queries.ScriptTest{
+			Name: "persistent dolt_reflog: entries survive garbage collection",
+			SetUpScript: []string{
+				"create table t1(pk int primary key);",
+				"call dolt_commit('-Am', 'creating table t1');",
+				"call dolt_checkout('-b', 'branch1');",
+				"insert into t1 values(1);",
+				"call dolt_commit('-Am', 'inserting row 1');",
+				"call dolt_tag('tag1');",
+				"call dolt_checkout('main');",
+				"call dolt_branch('-D', 'branch1');",
+			},
+			Assertions: []queries.ScriptTestAssertion{
+				{
+					Query:    "call dolt_gc();",
+					Expected: []sql.Row{{0}},
+				},
+				{
+					// Calling dolt_gc() invalidates the session, so we have to ask this assertion to create a new session
+					NewSession: true,
+					Query:      "select ref, commit_hash, commit_message from dolt_reflog()",
+					Expected: []sql.Row{
+						{"refs/tags/tag1", doltCommit, "inserting row 1"},
+						{"refs/heads/branch1", doltCommit, "inserting row 1"},
+						{"refs/heads/branch1", doltCommit, "creating table t1"},
+						{"refs/heads/main", doltCommit, "creating table t1"},
+						{"refs/heads/main", doltCommit, "Initialize data repository"},
+					},
+				},
+				{
+					Query:    "insert into t1 values(2);",
+					Expected: []sql.Row{{types.NewOkResult(1)}},
+				},
+				{
+					Query:    "call dolt_commit('-Am', 'inserting row 2');",
+					Expected: []sql.Row{{doltCommit}},
+				},
+				{
+					Query:    "call dolt_gc();",
+					Expected: []sql.Row{{0}},
+				},
+				{
+					NewSession: true,
+					Query:      "select ref, commit_hash, commit_message from dolt_reflog('main')",
+					Expected: []sql.Row{
+						{"refs/heads/main", doltCommit, "inserting row 2"},
+						{"refs/heads/main", doltCommit, "creating table t1"},
+						{"refs/heads/main", doltCommit, "Initialize data repository"},
+					},
+				},
+				{
+					Query:    "select count(*) from dolt_reflog('branch1')",
+					Expected: []sql.Row{{2}},
+				},
+			},
+		},
+	},
+	{
+		Policy: doltdb.ReflogPolicy{Persistent: true, MaxEntries: 2},
+		ScriptTest: queries.ScriptTest{
+			Name: "persistent dolt_reflog: retention policy",
+			SetUpScript: []string{
+				"create table t1(pk int primary key);",
+				"call dolt_commit('-Am', 'creating table t1');",
+				"insert into t1 values(1);",
+				"call dolt_commit('-Am', 'inserting row 1');",
+			},
+			Assertions: []queries.ScriptTestAssertion{
+				{
+					Query:    "select count(*) from dolt_reflog()",
+					Expected: []sql.Row{{3}},
+				},
+				{
+					Query:    "call dolt_gc();",
+					Expected: []sql.Row{{0}},
+				},
+				{
+					NewSession: true,
+					Query:      "select ref, commit_hash, commit_message from dolt_reflog()",
+					Expected: []sql.Row{
+						{"refs/heads/main", doltCommit, "inserting row 1"},
+						{"refs/heads/main", doltCommit, "creating table t1"},
+					},
+				},
+			},
+		},
+	},
 }
 
 // DoltAutoIncrementTests is tests of dolt's global auto increment logic
@@ -6485,3 +6485,175 @@ var QueryDiffTableScriptTests = []queries.ScriptTest{
 		},
 	},
 }
+
+var ChangesTableFunctionScriptTests = []queries.ScriptTest{
+	{
+		Name: "row changes of each commit",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c1 varchar(20));",
+			"call dolt_add('.')",
+			"set @Commit0 = '';",
+			"call dolt_commit_hash_out(@Commit0, '-am', 'creating table t');",
+
+			"insert into t values (1, 'one'), (2, 'two');",
+			"set @Commit1 = '';",
+			"call dolt_commit_hash_out(@Commit1, '-am', 'inserting into t');",
+
+			"update t set c1 = 'uno' where pk = 1;",
+			"delete from t where pk = 2;",
+			"set @Commit2 = '';",
+			"call dolt_commit_hash_out(@Commit2, '-am', 'updating and deleting from t');",
+
+			"create table k (a int, b varchar(10));",
+			"insert into k values (1, 'x'), (1, 'x');",
+			"call dolt_add('.')",
+			"set @Commit3 = '';",
+			"call dolt_commit_hash_out(@Commit3, '-am', 'creating keyless table k');",
+
+			"set @Cursor = concat(@Commit2, ':0');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "select commit_hash = @Commit1, event_offset, message, table_name, change_type, from_row, to_row from dolt_changes('main', @Commit0);",
+				Expected: []sql.Row{
+					{true, int64(0), "inserting into t", "t", "insert", nil, gmstypes.MustJSON(`{"pk": 1, "c1": "one"}`)},
+					{true, int64(1), "inserting into t", "t", "insert", nil, gmstypes.MustJSON(`{"pk": 2, "c1": "two"}`)},
+					{false, int64(0), "updating and deleting from t", "t", "update", gmstypes.MustJSON(`{"pk": 1, "c1": "one"}`), gmstypes.MustJSON(`{"pk": 1, "c1": "uno"}`)},
+					{false, int64(1), "updating and deleting from t", "t", "delete", gmstypes.MustJSON(`{"pk": 2, "c1": "two"}`), nil},
+					{false, int64(0), "creating keyless table k", "k", "insert", nil, gmstypes.MustJSON(`{"a": 1, "b": "x"}`)},
+					{false, int64(1), "creating keyless table k", "k", "insert", nil, gmstypes.MustJSON(`{"a": 1, "b": "x"}`)},
+				},
+			},
+			{
+				Query:    "select count(*) from dolt_changes('main', @Commit0) where event_cursor = concat(commit_hash, ':', event_offset);",
+				Expected: []sql.Row{{6}},
+			},
+			{
+				Query:    "select count(*) from dolt_changes('main');",
+				Expected: []sql.Row{{6}},
+			},
+			{
+				Query:    "select count(*) from dolt_changes('main', 'HEAD');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query: "select table_name, change_type from dolt_changes('main', @Cursor);",
+				Expected: []sql.Row{
+					{"t", "delete"},
+					{"k", "insert"},
+					{"k", "insert"},
+				},
+			},
+			{
+				Query: "select table_name, change_type from dolt_changes('main', 'HEAD~2');",
+				Expected: []sql.Row{
+					{"t", "update"},
+					{"t", "delete"},
+					{"k", "insert"},
+					{"k", "insert"},
+				},
+			},
+		},
+	},
+	{
+		Name: "dropped tables and primary key changes",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c1 int);",
+			"insert into t values (1, 10), (2, 20);",
+			"call dolt_add('.')",
+			"set @Commit1 = '';",
+			"call dolt_commit_hash_out(@Commit1, '-am', 'creating table t');",
+
+			"alter table t drop primary key, add primary key (c1);",
+			"call dolt_commit('-am', 'changing primary key of t');",
+
+			"drop table t;",
+			"call dolt_commit('-am', 'dropping table t');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "select message, change_type, from_row, to_row from dolt_changes('main', @Commit1);",
+				Expected: []sql.Row{
+					{"changing primary key of t", "delete", gmstypes.MustJSON(`{"pk": 1, "c1": 10}`), nil},
+					{"changing primary key of t", "delete", gmstypes.MustJSON(`{"pk": 2, "c1": 20}`), nil},
+					{"changing primary key of t", "insert", nil, gmstypes.MustJSON(`{"pk": 1, "c1": 10}`)},
+					{"changing primary key of t", "insert", nil, gmstypes.MustJSON(`{"pk": 2, "c1": 20}`)},
+					{"dropping table t", "delete", gmstypes.MustJSON(`{"pk": 1, "c1": 10}`), nil},
+					{"dropping table t", "delete", gmstypes.MustJSON(`{"pk": 2, "c1": 20}`), nil},
+				},
+			},
+		},
+	},
+	{
+		Name: "commits with more changes than are buffered",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"call dolt_add('.')",
+			"set @Commit0 = '';",
+			"call dolt_commit_hash_out(@Commit0, '-am', 'creating table t');",
+			"insert into t with recursive r(n) as (select 1 union all select n + 1 from r where n < 1000) select n from r;",
+			"set @Commit1 = '';",
+			"call dolt_commit_hash_out(@Commit1, '-am', 'inserting 1000 rows');",
+			"delete from t where pk > 500;",
+			"call dolt_commit('-am', 'deleting 500 rows');",
+			"set @Cursor = concat(@Commit1, ':899');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select count(*), min(event_offset), max(event_offset) from dolt_changes('main', @Commit0) where change_type = 'insert';",
+				Expected: []sql.Row{{1000, int64(0), int64(999)}},
+			},
+			{
+				Query:    "select event_offset, to_row from dolt_changes('main', @Commit0) limit 1;",
+				Expected: []sql.Row{{int64(0), gmstypes.MustJSON(`{"pk": 1}`)}},
+			},
+			{
+				Query:    "select change_type, count(*) from dolt_changes('main', @Cursor) group by change_type order by 1;",
+				Expected: []sql.Row{{"delete", 500}, {"insert", 100}},
+			},
+		},
+	},
+	{
+		Name: "invalid arguments",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"call dolt_add('.')",
+			"call dolt_commit('-am', 'creating table t');",
+			"call dolt_checkout('-b', 'other');",
+			"insert into t values (1);",
+			"set @OtherCommit = '';",
+			"call dolt_commit_hash_out(@OtherCommit, '-am', 'inserting into t on other');",
+			"call dolt_checkout('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:       "select * from dolt_changes();",
+				ExpectedErr: sql.ErrInvalidArgumentNumber,
+			},
+			{
+				Query:       "select * from dolt_changes('main', 'HEAD', 'extra');",
+				ExpectedErr: sql.ErrInvalidArgumentNumber,
+			},
+			{
+				Query:       "select * from dolt_changes('main', 'abc:1');",
+				ExpectedErr: dtablefunctions.ErrInvalidChangesCursor,
+			},
+			{
+				Query:       "select * from dolt_changes('main', @OtherCommit);",
+				ExpectedErr: dtablefunctions.ErrChangesCommitNotInHistory,
+			},
+			{
+				Query:          "select * from dolt_changes('fake-branch');",
+				ExpectedErrStr: "branch not found",
+			},
+			{
+				Query:    "select count(*) from dolt_changes('other', @OtherCommit);",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select change_type, to_row from dolt_changes('other', 'main');",
+				Expected: []sql.Row{{"insert", gmstypes.MustJSON(`{"pk": 1}`)}},
+			},
+		},
+	},
+}
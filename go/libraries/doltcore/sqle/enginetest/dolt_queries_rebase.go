@@ -710,6 +710,488 @@ var DoltRebaseScriptTests = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "dolt_rebase: edit, exec, and break actions",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c1 int);",
+			"call dolt_commit('-Am', 'creating table t');",
+			"call dolt_branch('branch1');",
+
+			"insert into t values (0, 0);",
+			"call dolt_commit('-am', 'inserting row 0');",
+
+			"call dolt_checkout('branch1');",
+			"insert into t values (1, 1);",
+			"call dolt_commit('-am', 'inserting row 1');",
+			"insert into t values (10, 10);",
+			"call dolt_commit('-am', 'inserting row 10');",
+			"insert into t values (100, 100);",
+			"call dolt_commit('-am', 'inserting row 100');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "call dolt_rebase('-i', 'main');",
+				Expected: []sql.Row{{0, "interactive rebase started on branch dolt_rebase_branch1; " +
+					"adjust the rebase plan in the dolt_rebase table, then " +
+					"continue rebasing by calling dolt_rebase('--continue')"}},
+			},
+			{
+				Query: "update dolt_rebase set action='edit' where rebase_order = 1;",
+				Expected: []sql.Row{{gmstypes.OkResult{RowsAffected: uint64(1), Info: plan.UpdateInfo{
+					Matched: 1,
+					Updated: 1,
+				}}}},
+			},
+			{
+				Query: "insert into dolt_rebase values " +
+					"(1.5, 'exec', '', 'insert into t values (5, 5); call dolt_commit(''-am'', ''inserting row 5'')'), " +
+					"(2.5, 'break', '', '');",
+				Expected: []sql.Row{{gmstypes.NewOkResult(2)}},
+			},
+			{
+				// The rebase stops after applying the commit to edit
+				Query:            "call dolt_rebase('--continue');",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:    "select active_branch();",
+				Expected: []sql.Row{{"dolt_rebase_branch1"}},
+			},
+			{
+				Query:    "select message from dolt_log limit 1;",
+				Expected: []sql.Row{{"inserting row 1"}},
+			},
+			{
+				Query:    "update t set c1 = 11 where pk = 1;",
+				Expected: []sql.Row{{gmstypes.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "call dolt_add('t');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				// Staged changes are amended into the edited commit, then the exec step runs,
+				// and the rebase stops again at the break step
+				Query:    "call dolt_rebase('--continue');",
+				Expected: []sql.Row{{0, "Stopped rebasing at break. Continue rebasing by calling dolt_rebase('--continue')"}},
+			},
+			{
+				Query: "select message from dolt_log;",
+				Expected: []sql.Row{
+					{"inserting row 10"},
+					{"inserting row 5"},
+					{"inserting row 1"},
+					{"inserting row 0"},
+					{"creating table t"},
+					{"Initialize data repository"}},
+			},
+			{
+				Query:    "call dolt_rebase('--continue');",
+				Expected: []sql.Row{{0, "Successfully rebased and updated refs/heads/branch1"}},
+			},
+			{
+				Query:    "select active_branch();",
+				Expected: []sql.Row{{"branch1"}},
+			},
+			{
+				Query: "select message from dolt_log;",
+				Expected: []sql.Row{
+					{"inserting row 100"},
+					{"inserting row 10"},
+					{"inserting row 5"},
+					{"inserting row 1"},
+					{"inserting row 0"},
+					{"creating table t"},
+					{"Initialize data repository"}},
+			},
+			{
+				Query:    "select * from t;",
+				Expected: []sql.Row{{0, 0}, {1, 11}, {5, 5}, {10, 10}, {100, 100}},
+			},
+		},
+	},
+	{
+		Name: "dolt_rebase: exec failures stop the rebase",
+		SetUpScript: []string{
+			"create table parent (pk int primary key);",
+			"create table child (pk int primary key, parent_id int, foreign key (parent_id) references parent(pk));",
+			"call dolt_commit('-Am', 'creating tables');",
+			"call dolt_branch('branch1');",
+
+			"insert into parent values (0);",
+			"call dolt_commit('-am', 'inserting parent 0');",
+
+			"call dolt_checkout('branch1');",
+			"insert into parent values (1);",
+			"call dolt_commit('-am', 'inserting parent 1');",
+			"insert into parent values (2);",
+			"call dolt_commit('-am', 'inserting parent 2');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "call dolt_rebase('-i', 'main');",
+				Expected: []sql.Row{{0, "interactive rebase started on branch dolt_rebase_branch1; " +
+					"adjust the rebase plan in the dolt_rebase table, then " +
+					"continue rebasing by calling dolt_rebase('--continue')"}},
+			},
+			{
+				Query:    "insert into dolt_rebase values (0.5, 'exec', '', '');",
+				Expected: []sql.Row{{gmstypes.NewOkResult(1)}},
+			},
+			{
+				Query:          "call dolt_rebase('--continue');",
+				ExpectedErrStr: rebase.ErrInvalidRebasePlanExecWithoutStatement.Error(),
+			},
+			{
+				Query: "update dolt_rebase set commit_message = 'select * from doesnotexist' where rebase_order = 0.5;",
+				Expected: []sql.Row{{gmstypes.OkResult{RowsAffected: uint64(1), Info: plan.UpdateInfo{
+					Matched: 1,
+					Updated: 1,
+				}}}},
+			},
+			{
+				Query:       "call dolt_rebase('--continue');",
+				ExpectedErr: dprocedures.ErrRebaseExecFailed,
+			},
+			{
+				Query: "insert into dolt_rebase values (1.5, 'exec', '', " +
+					"'set @@foreign_key_checks=0; insert into child values (1, 42); set @@foreign_key_checks=1; " +
+					"call dolt_verify_constraints()');",
+				Expected: []sql.Row{{gmstypes.NewOkResult(1)}},
+			},
+			{
+				// The failed exec step isn't run again, and the next exec step fails because
+				// dolt_verify_constraints returns a non-zero status for the orphaned child row
+				Query:       "call dolt_rebase('--continue');",
+				ExpectedErr: dprocedures.ErrRebaseExecFailed,
+			},
+			{
+				Query:    "select message from dolt_log limit 1;",
+				Expected: []sql.Row{{"inserting parent 1"}},
+			},
+			{
+				Query:       "call dolt_rebase('--continue');",
+				ExpectedErr: dprocedures.ErrRebaseUnstagedChanges,
+			},
+			{
+				Query:    "call dolt_checkout('child');",
+				Expected: []sql.Row{{0, ""}},
+			},
+			{
+				Query:    "call dolt_rebase('--continue');",
+				Expected: []sql.Row{{0, "Successfully rebased and updated refs/heads/branch1"}},
+			},
+			{
+				Query:    "select * from parent;",
+				Expected: []sql.Row{{0}, {1}, {2}},
+			},
+			{
+				Query:    "select * from child;",
+				Expected: []sql.Row{},
+			},
+		},
+	},
+	{
+		Name: "dolt_rebase: exec that leaves uncommitted changes stops the rebase",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"call dolt_commit('-Am', 'creating table t');",
+			"call dolt_branch('branch1');",
+			"call dolt_checkout('branch1');",
+			"insert into t values (1);",
+			"call dolt_commit('-am', 'inserting row 1');",
+			"insert into t values (2);",
+			"call dolt_commit('-am', 'inserting row 2');",
+			"call dolt_rebase('-i', 'main');",
+			"insert into dolt_rebase values (1.5, 'exec', '', 'insert into t values (3)');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:       "call dolt_rebase('--continue');",
+				ExpectedErr: dprocedures.ErrRebaseExecLeftChanges,
+			},
+			{
+				Query:    "call dolt_add('t');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:       "call dolt_rebase('--continue');",
+				ExpectedErr: dprocedures.ErrRebaseStagedChangesAfterStop,
+			},
+			{
+				Query:    "call dolt_commit('-m', 'inserting row 3');",
+				Expected: []sql.Row{{doltCommit}},
+			},
+			{
+				Query:    "call dolt_rebase('--continue');",
+				Expected: []sql.Row{{0, "Successfully rebased and updated refs/heads/branch1"}},
+			},
+			{
+				Query: "select message from dolt_log;",
+				Expected: []sql.Row{
+					{"inserting row 2"},
+					{"inserting row 3"},
+					{"inserting row 1"},
+					{"creating table t"},
+					{"Initialize data repository"}},
+			},
+		},
+	},
+	{
+		Name: "dolt_rebase: --rebase-merges replays merge commits",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c1 int);",
+			"create table f (pk int primary key);",
+			"call dolt_commit('-Am', 'creating tables');",
+			"call dolt_branch('release');",
+			"call dolt_branch('feature');",
+
+			"insert into t values (0, 0);",
+			"call dolt_commit('-am', 'inserting row 0');",
+
+			"call dolt_checkout('feature');",
+			"insert into f values (1);",
+			"call dolt_commit('-am', 'feature row 1');",
+
+			"call dolt_checkout('release');",
+			"insert into t values (1, 1);",
+			"call dolt_commit('-am', 'inserting row 1');",
+			"call dolt_merge('--no-ff', '--no-commit', 'feature');",
+			"call dolt_commit('-m', 'merging feature', '--author', 'Jane Doe <jane@doe.com>', '--date', '2022-08-06T12:00:00');",
+			"insert into t values (2, 2);",
+			"call dolt_commit('-am', 'inserting row 2');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "call dolt_rebase('-i', '--rebase-merges', 'main');",
+				Expected: []sql.Row{{0, "interactive rebase started on branch dolt_rebase_release; " +
+					"adjust the rebase plan in the dolt_rebase table, then " +
+					"continue rebasing by calling dolt_rebase('--continue')"}},
+			},
+			{
+				// The commits merged in from feature aren't in the plan, since the merge step brings them in
+				Query: "select * from dolt_rebase order by rebase_order ASC;",
+				Expected: []sql.Row{
+					{"1", "pick", doltCommit, "inserting row 1"},
+					{"2", "merge", doltCommit, "merging feature"},
+					{"3", "pick", doltCommit, "inserting row 2"},
+				},
+			},
+			{
+				Query: "update dolt_rebase set commit_message = 'merging feature again' where rebase_order = 2;",
+				Expected: []sql.Row{{gmstypes.OkResult{RowsAffected: uint64(1), Info: plan.UpdateInfo{
+					Matched: 1,
+					Updated: 1,
+				}}}},
+			},
+			{
+				Query:    "call dolt_rebase('--continue');",
+				Expected: []sql.Row{{0, "Successfully rebased and updated refs/heads/release"}},
+			},
+			{
+				Query: "select message from dolt_log;",
+				Expected: []sql.Row{
+					{"inserting row 2"},
+					{"merging feature again"},
+					{"inserting row 1"},
+					{"feature row 1"},
+					{"inserting row 0"},
+					{"creating tables"},
+					{"Initialize data repository"}},
+			},
+			{
+				// The replayed merge commit keeps the original second parent
+				Query:    "select count(*) from dolt_commit_ancestors where commit_hash = hashof('HEAD~1') and parent_hash = hashof('feature');",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				// The replayed merge commit keeps the original author and date
+				Query:    "select committer, email, date_format(date, '%Y-%m-%dT%H:%i:%s') from dolt_log where commit_hash = hashof('HEAD~1');",
+				Expected: []sql.Row{{"Jane Doe", "jane@doe.com", "2022-08-06T12:00:00"}},
+			},
+			{
+				Query:    "select * from t;",
+				Expected: []sql.Row{{0, 0}, {1, 1}, {2, 2}},
+			},
+			{
+				Query:    "select * from f;",
+				Expected: []sql.Row{{1}},
+			},
+		},
+	},
+	{
+		Name: "dolt_rebase: merge commits are dropped without --rebase-merges",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c1 int);",
+			"call dolt_commit('-Am', 'creating table t');",
+			"call dolt_branch('release');",
+			"call dolt_branch('feature');",
+
+			"insert into t values (0, 0);",
+			"call dolt_commit('-am', 'inserting row 0');",
+
+			"call dolt_checkout('feature');",
+			"insert into t values (10, 10);",
+			"call dolt_commit('-am', 'inserting row 10');",
+
+			"call dolt_checkout('release');",
+			"insert into t values (1, 1);",
+			"call dolt_commit('-am', 'inserting row 1');",
+			"call dolt_merge('--no-ff', '-m', 'merging feature', 'feature');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "call dolt_rebase('-i', 'main');",
+				Expected: []sql.Row{{0, "interactive rebase started on branch dolt_rebase_release; " +
+					"adjust the rebase plan in the dolt_rebase table, then " +
+					"continue rebasing by calling dolt_rebase('--continue')"}},
+			},
+			{
+				Query: "select action, commit_message from dolt_rebase order by commit_message;",
+				Expected: []sql.Row{
+					{"pick", "inserting row 1"},
+					{"pick", "inserting row 10"},
+				},
+			},
+			{
+				Query:    "call dolt_rebase('--abort');",
+				Expected: []sql.Row{{0, "Interactive rebase aborted"}},
+			},
+		},
+	},
+	{
+		Name: "dolt_rebase: data conflicts with merge",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c1 varchar(100));",
+			"call dolt_commit('-Am', 'creating table t');",
+			"call dolt_branch('release');",
+			"call dolt_branch('feature');",
+
+			"insert into t values (1, 'main');",
+			"call dolt_commit('-am', 'inserting row 1 on main');",
+
+			"call dolt_checkout('feature');",
+			"insert into t values (1, 'feature');",
+			"call dolt_commit('-am', 'inserting row 1 on feature');",
+
+			"call dolt_checkout('release');",
+			"call dolt_merge('--no-ff', '-m', 'merging feature', '--author', 'Jane Doe <jane@doe.com>', 'feature');",
+			"set @@autocommit=0;",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "call dolt_rebase('-i', '--rebase-merges', 'main');",
+				Expected: []sql.Row{{0, "interactive rebase started on branch dolt_rebase_release; " +
+					"adjust the rebase plan in the dolt_rebase table, then " +
+					"continue rebasing by calling dolt_rebase('--continue')"}},
+			},
+			{
+				Query: "select action, commit_message from dolt_rebase order by rebase_order ASC;",
+				Expected: []sql.Row{
+					{"merge", "merging feature"},
+				},
+			},
+			{
+				Query:       "call dolt_rebase('--continue');",
+				ExpectedErr: dprocedures.ErrRebaseDataConflict,
+			},
+			{
+				Query:    "select our_c1, their_c1 from dolt_conflicts_t;",
+				Expected: []sql.Row{{"main", "feature"}},
+			},
+			{
+				Query:    "delete from dolt_conflicts_t;",
+				Expected: []sql.Row{{gmstypes.NewOkResult(1)}},
+			},
+			{
+				Query:    "call dolt_add('t');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "call dolt_rebase('--continue');",
+				Expected: []sql.Row{{0, "Successfully rebased and updated refs/heads/release"}},
+			},
+			{
+				Query: "select message from dolt_log;",
+				Expected: []sql.Row{
+					{"merging feature"},
+					{"inserting row 1 on main"},
+					{"inserting row 1 on feature"},
+					{"creating table t"},
+					{"Initialize data repository"}},
+			},
+			{
+				Query:    "select count(*) from dolt_commit_ancestors where commit_hash = hashof('HEAD');",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				Query:    "select committer, email from dolt_log where commit_hash = hashof('HEAD');",
+				Expected: []sql.Row{{"Jane Doe", "jane@doe.com"}},
+			},
+			{
+				Query:    "select * from t;",
+				Expected: []sql.Row{{1, "main"}},
+			},
+		},
+	},
+	{
+		Name: "dolt_rebase: --onto",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"call dolt_commit('-Am', 'creating table t');",
+			"call dolt_branch('next');",
+
+			"insert into t values (0);",
+			"call dolt_commit('-am', 'inserting row 0');",
+
+			"call dolt_checkout('next');",
+			"insert into t values (10);",
+			"call dolt_commit('-am', 'inserting row 10');",
+
+			"call dolt_checkout('-b', 'topic');",
+			"insert into t values (1);",
+			"call dolt_commit('-am', 'inserting row 1');",
+			"insert into t values (2);",
+			"call dolt_commit('-am', 'inserting row 2');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "call dolt_rebase('-i', '--onto', 'doesnotexist', 'next');",
+				ExpectedErrStr: "branch not found: doesnotexist",
+			},
+			{
+				Query: "call dolt_rebase('-i', '--onto', 'main', 'next');",
+				Expected: []sql.Row{{0, "interactive rebase started on branch dolt_rebase_topic; " +
+					"adjust the rebase plan in the dolt_rebase table, then " +
+					"continue rebasing by calling dolt_rebase('--continue')"}},
+			},
+			{
+				// Only the commits in next..topic are in the plan
+				Query: "select action, commit_message from dolt_rebase order by rebase_order ASC;",
+				Expected: []sql.Row{
+					{"pick", "inserting row 1"},
+					{"pick", "inserting row 2"},
+				},
+			},
+			{
+				Query:    "call dolt_rebase('--continue');",
+				Expected: []sql.Row{{0, "Successfully rebased and updated refs/heads/topic"}},
+			},
+			{
+				Query: "select message from dolt_log;",
+				Expected: []sql.Row{
+					{"inserting row 2"},
+					{"inserting row 1"},
+					{"inserting row 0"},
+					{"creating table t"},
+					{"Initialize data repository"}},
+			},
+			{
+				Query:    "select * from t;",
+				Expected: []sql.Row{{0}, {1}, {2}},
+			},
+		},
+	},
 	{
 		Name: "dolt_rebase: negative rebase order",
 		SetUpScript: []string{
@@ -293,6 +293,123 @@ var DoltHistogramTests = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "unindexed column statistics",
+		SetUpScript: []string{
+			"CREATE table xy (x bigint primary key, y int, z int, w varchar(500));",
+			"insert into xy select x, x % 4, x % 2, 'x' from (with recursive inputs(x) as (select 1 union select x+1 from inputs where x < 10000) select * from inputs) dt",
+			"update xy set y = NULL where x % 10 = 0",
+			"analyze table xy",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "analyze table xy update histogram on (y) using data '{}'",
+				Expected: []sql.Row{{"xy", "histogram", "status", "OK"}},
+			},
+			{
+				Query:    "analyze table xy update histogram on (y, z) using data '{}'",
+				Expected: []sql.Row{{"xy", "histogram", "status", "OK"}},
+			},
+			{
+				Query:    "analyze table xy update histogram on (w) using data '{}'",
+				Expected: []sql.Row{{"xy", "histogram", "status", "OK"}},
+			},
+			{
+				Query:    "select column_name from information_schema.column_statistics where table_name = 'xy' order by 1",
+				Expected: []sql.Row{{"w"}, {"x"}, {"y"}, {"y,z"}},
+			},
+			{
+				Query:    "select json_value(histogram, '$.statistic.row_count', 'signed'), json_value(histogram, '$.statistic.distinct_count', 'signed') from information_schema.column_statistics where column_name = 'y'",
+				Expected: []sql.Row{{10000, 5}},
+			},
+			{
+				Query:    "select sum(b.cnt) from information_schema.column_statistics, json_table(histogram, '$.statistic.buckets[*]' columns (cnt int path '$.null_count')) as b where column_name = 'y'",
+				Expected: []sql.Row{{float64(1000)}},
+			},
+			{
+				// buckets are ordered by value rather than by primary key
+				Query:    "select b.ub, b.cnt from information_schema.column_statistics, json_table(histogram, '$.statistic.buckets[*]' columns (ub int path '$.upper_bound[0]', cnt int path '$.row_count')) as b where column_name = 'y'",
+				Expected: []sql.Row{{nil, 1000}, {0, 2000}, {1, 2500}, {2, 2000}, {3, 2500}},
+			},
+			{
+				// z is determined by y
+				Query:    "select json_value(histogram, '$.statistic.distinct_count', 'signed') from information_schema.column_statistics where column_name = 'y,z'",
+				Expected: []sql.Row{{5}},
+			},
+			{
+				Query:    "select json_value(histogram, '$.statistic.distinct_count', 'signed') from information_schema.column_statistics where column_name = 'w'",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "analyze table xy update histogram on (y, x, z) using data '{}'",
+				Expected: []sql.Row{{"xy", "histogram", "status", "OK"}},
+			},
+			{
+				Query:    "select json_value(histogram, '$.statistic.distinct_count', 'signed') from information_schema.column_statistics where column_name = 'y,x,z'",
+				Expected: []sql.Row{{10000}},
+			},
+			{
+				Query:    "analyze table xy drop histogram on (y, x, z)",
+				Expected: []sql.Row{{"xy", "histogram", "status", "OK"}},
+			},
+			{
+				Query:    "analyze table xy drop histogram on (w)",
+				Expected: []sql.Row{{"xy", "histogram", "status", "OK"}},
+			},
+			{
+				Query: "update xy set y = 7 where x > 9900",
+			},
+			{
+				// column statistics are refreshed with the table's index statistics
+				Query:    "analyze table xy",
+				Expected: []sql.Row{{"xy", "analyze", "status", "OK"}},
+			},
+			{
+				Query:    "select column_name, count(*) from information_schema.column_statistics, json_table(histogram, '$.statistic.buckets[*]' columns (ub int path '$.upper_bound[0]')) as b where column_name like 'y%' and b.ub = 7 group by 1 order by 1",
+				Expected: []sql.Row{{"y", 1}, {"y,z", 1}},
+			},
+		},
+	},
+	{
+		Name: "statistics for past commits",
+		SetUpScript: []string{
+			"CREATE table xy (x bigint primary key, y int, key(y));",
+			"insert into xy select x, x % 2 from (with recursive inputs(x) as (select 1 union select x+1 from inputs where x < 2000) select * from inputs) dt",
+			"call dolt_commit('-Am', 'first')",
+			"call dolt_tag('v1')",
+			"delete from xy where x > 100",
+			"call dolt_commit('-am', 'second')",
+			"analyze table xy",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select index_name, sum(row_count) from dolt_statistics group by 1 order by 1",
+				Expected: []sql.Row{{"primary", float64(100)}, {"y", float64(100)}},
+			},
+			{
+				Query:    "select index_name, sum(row_count) from dolt_stats_for('v1') group by 1 order by 1",
+				Expected: []sql.Row{{"primary", float64(2000)}, {"y", float64(2000)}},
+			},
+			{
+				Query:    "select index_name, sum(row_count) from dolt_stats_for('HEAD') group by 1 order by 1",
+				Expected: []sql.Row{{"primary", float64(100)}, {"y", float64(100)}},
+			},
+			{
+				// the branch statistics are unchanged
+				Query:    "select index_name, sum(row_count) from dolt_statistics group by 1 order by 1",
+				Expected: []sql.Row{{"primary", float64(100)}, {"y", float64(100)}},
+			},
+			{
+				// commits with the same data share statistics
+				Query:    "select count(*) from (select * from dolt_stats_for('v1') except select * from dolt_stats_for('HEAD~1')) dt",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:          "select * from dolt_stats_for('nonexistent')",
+				ExpectedErrStr: "branch not found: nonexistent",
+			},
+		},
+	},
 }
 
 var DoltStatsIOTests = []queries.ScriptTest{
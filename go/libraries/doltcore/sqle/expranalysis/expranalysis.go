@@ -26,6 +26,7 @@ import (
 	"github.com/dolthub/go-mysql-server/sql/transform"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlfmt"
 )
 
@@ -71,6 +72,36 @@ func ResolveCheckExpression(ctx *sql.Context, tableName string, sch schema.Schem
 	return nil, fmt.Errorf("unable to find check expression")
 }
 
+// ResolveMergePolicyExpression returns a sql.Expression for the expression of a merge policy that resolves
+// conflicting values of a column with type |typ|. The expression is resolved against a row of three columns of
+// that type, `ours`, `theirs` and `base`, and is evaluated with the values of the conflicting cell in that order.
+func ResolveMergePolicyExpression(ctx *sql.Context, typ typeinfo.TypeInfo, expr string) (sql.Expression, error) {
+	var cols []schema.Column
+	for i, name := range []string{"ours", "theirs", "base", "merged"} {
+		col, err := schema.NewColumnWithTypeInfo(name, uint64(i+1), typ, false, "", false, "")
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, col)
+	}
+	cols[3].Generated = expr
+
+	sch, err := schema.SchemaFromCols(schema.NewColCollection(cols...))
+	if err != nil {
+		return nil, err
+	}
+	ct, err := parseCreateTable(ctx, "dolt_merge_policy", sch)
+	if err != nil {
+		return nil, fmt.Errorf("invalid merge policy expression '%s': %w", expr, err)
+	}
+
+	generated := ct.PkSchema().Schema[3].Generated
+	if generated == nil || generated.Expr == nil {
+		return nil, fmt.Errorf("invalid merge policy expression '%s'", expr)
+	}
+	return generated, nil
+}
+
 func stripTableNamesFromExpression(expr sql.Expression) sql.Expression {
 	e, _, _ := transform.Expr(expr, func(e sql.Expression) (sql.Expression, transform.TreeIdentity, error) {
 		if col, ok := e.(*expression.GetField); ok {
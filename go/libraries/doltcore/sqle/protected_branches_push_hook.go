@@ -0,0 +1,105 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/branchprotection"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// protectedBranchesPushHook rejects pushes which update the branches protected by the dolt_branch_protection table to
+// commits which do not satisfy the branch's protection rule. It also records the pushing user as the author of the
+// pushed commits, so that the user may not approve them.
+type protectedBranchesPushHook struct {
+	ctxFactory func(context.Context) (*sql.Context, error)
+	lgr        *logrus.Entry
+}
+
+var _ RemoteSrvPushHook = protectedBranchesPushHook{}
+
+// NewProtectedBranchesPushHook returns a RemoteSrvPushHook which enforces the rules of the dolt_branch_protection table
+// on pushes, using sessions from |ctxFactory| to read the branch controller and the pushed commits. The client of the
+// sessions must be the user that authenticated the push.
+func NewProtectedBranchesPushHook(ctxFactory func(context.Context) (*sql.Context, error), lgr *logrus.Entry) RemoteSrvPushHook {
+	return protectedBranchesPushHook{ctxFactory: ctxFactory, lgr: lgr}
+}
+
+// BeforePush implements RemoteSrvPushHook.
+func (h protectedBranchesPushHook) BeforePush(ctx context.Context, dbName string, updates []BranchHeadUpdate) error {
+	sqlCtx, db, err := h.database(ctx, dbName)
+	if err != nil {
+		return err
+	}
+
+	heads := make([]hash.Hash, len(updates))
+	for i, update := range updates {
+		heads[i] = update.NewHead
+	}
+	if err = branchprotection.RecordPushedCommits(sqlCtx, db, heads); err != nil {
+		return status.Errorf(codes.Internal, "error recording the authors of pushed commits: %v", err)
+	}
+
+	for _, update := range updates {
+		rule, ok := branch_control.GetProtection(sqlCtx, dbName, update.Branch)
+		if !ok {
+			continue
+		}
+		err := branchprotection.CheckPush(sqlCtx, db, rule, update.Branch, update.OldHead, update.NewHead)
+		if branchprotection.ErrProtectedBranchPush.Is(err) {
+			return status.Error(codes.FailedPrecondition, err.Error())
+		} else if err != nil {
+			return status.Errorf(codes.Internal, "error checking protection rules for branch %s: %v", update.Branch, err)
+		}
+	}
+	return nil
+}
+
+// AfterPush implements RemoteSrvPushHook. Once the pushed commits are reachable, the authors of the commits which have
+// been merged into protected branches, or which are no longer reachable, are forgotten.
+func (h protectedBranchesPushHook) AfterPush(ctx context.Context, dbName string, _ []BranchHeadUpdate) {
+	sqlCtx, db, err := h.database(ctx, dbName)
+	if err == nil {
+		err = branchprotection.ForgetSettledAuthors(sqlCtx, db)
+	}
+	if err != nil {
+		h.lgr.Warnf("error forgetting the recorded commit authors of database %s: %v", dbName, err)
+	}
+}
+
+func (h protectedBranchesPushHook) database(ctx context.Context, dbName string) (*sql.Context, dsess.SqlDatabase, error) {
+	sqlCtx, err := h.ctxFactory(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	sqlDb, err := dsess.DSessFromSess(sqlCtx.Session).Provider().Database(sqlCtx, dbName)
+	if err != nil {
+		return nil, nil, err
+	}
+	db, ok := sqlDb.(dsess.SqlDatabase)
+	if !ok {
+		return nil, nil, fmt.Errorf("unexpected database type: %T", sqlDb)
+	}
+	return sqlCtx, db, nil
+}
@@ -216,7 +216,10 @@ func (rrd ReadReplicaDatabase) PullFromRemote(ctx *sql.Context) error {
 		return nil
 	}
 
-	return nil
+	_, err = rrd.limiter.Run(ctx, "-reflog", func() (any, error) {
+		return nil, rrd.ddb.ReplicateReflog(ctx, rrd.tmpDir, rrd.srcDB)
+	})
+	return err
 }
 
 // CreateLocalBranchFromRemote pulls the given branch from the remote database and creates a local tracking branch for
@@ -16,19 +16,23 @@ package sqle
 
 import (
 	"context"
+	"sort"
 
 	"github.com/dolthub/go-mysql-server/sql"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/remotesrv"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
 )
 
 type remotesrvStore struct {
 	ctxFactory func(context.Context) (*sql.Context, error)
 	createDBs  bool
+	pushHooks  []RemoteSrvPushHook
 }
 
 var _ remotesrv.DBCache = remotesrvStore{}
@@ -65,9 +69,114 @@ func (s remotesrvStore) Get(ctx context.Context, path, _ string) (remotesrv.Remo
 	if !ok {
 		return nil, remotesrv.ErrUnimplemented
 	}
+	if len(s.pushHooks) > 0 {
+		return pushHookStore{
+			RemoteSrvStore: rss,
+			ddb:            sdb.DbData().Ddb,
+			dbName:         path,
+			hooks:          s.pushHooks,
+		}, nil
+	}
 	return rss, nil
 }
 
+// BranchHeadUpdate is a change to the head of a branch made by a push.
+type BranchHeadUpdate struct {
+	Branch string
+	// OldHead is the empty hash if the push created the branch.
+	OldHead hash.Hash
+	NewHead hash.Hash
+}
+
+// RemoteSrvPushHook is notified of the branch heads updated by clients pushing to a database through the remotesapi
+// server. The chunks of the new heads are readable from the database while the hooks run.
+type RemoteSrvPushHook interface {
+	// BeforePush is called before the new root of the database is committed. Returning an error rejects the push,
+	// and the error is returned to the client.
+	BeforePush(ctx context.Context, dbName string, updates []BranchHeadUpdate) error
+	// AfterPush is called after the new root of the database has been committed.
+	AfterPush(ctx context.Context, dbName string, updates []BranchHeadUpdate)
+}
+
+// pushHookStore is a remotesrv.RemoteSrvStore which runs RemoteSrvPushHooks around commits of new roots that update
+// branch heads.
+type pushHookStore struct {
+	remotesrv.RemoteSrvStore
+	ddb    *doltdb.DoltDB
+	dbName string
+	hooks  []RemoteSrvPushHook
+}
+
+func (s pushHookStore) Commit(ctx context.Context, current, last hash.Hash) (bool, error) {
+	updates, err := s.branchHeadUpdates(ctx, current, last)
+	if err != nil {
+		return false, err
+	}
+	if len(updates) == 0 {
+		return s.RemoteSrvStore.Commit(ctx, current, last)
+	}
+
+	for _, h := range s.hooks {
+		if err := h.BeforePush(ctx, s.dbName, updates); err != nil {
+			return false, err
+		}
+	}
+
+	ok, err := s.RemoteSrvStore.Commit(ctx, current, last)
+	if err != nil || !ok {
+		return ok, err
+	}
+
+	for _, h := range s.hooks {
+		h.AfterPush(ctx, s.dbName, updates)
+	}
+	return true, nil
+}
+
+// branchHeadUpdates returns the branches whose heads are created or moved by changing the root of the database from
+// |last| to |current|. Deleted branches are not included.
+func (s pushHookStore) branchHeadUpdates(ctx context.Context, current, last hash.Hash) ([]BranchHeadUpdate, error) {
+	heads := func(root hash.Hash) (map[string]hash.Hash, error) {
+		ret := make(map[string]hash.Hash)
+		if root.IsEmpty() {
+			return ret, nil
+		}
+		dss, err := s.ddb.DatasetsByRootHash(ctx, root)
+		if err != nil {
+			return nil, err
+		}
+		err = dss.IterAll(ctx, func(id string, addr hash.Hash) error {
+			if ref.IsRef(id) {
+				if r, err := ref.Parse(id); err == nil && r.GetType() == ref.BranchRefType {
+					ret[r.GetPath()] = addr
+				}
+			}
+			return nil
+		})
+		return ret, err
+	}
+
+	lastHeads, err := heads(last)
+	if err != nil {
+		return nil, err
+	}
+	currentHeads, err := heads(current)
+	if err != nil {
+		return nil, err
+	}
+
+	var updates []BranchHeadUpdate
+	for branch, addr := range currentHeads {
+		if old := lastHeads[branch]; old != addr {
+			updates = append(updates, BranchHeadUpdate{Branch: branch, OldHead: old, NewHead: addr})
+		}
+	}
+	sort.Slice(updates, func(i, j int) bool {
+		return updates[i].Branch < updates[j].Branch
+	})
+	return updates, nil
+}
+
 // In the SQL context, the database provider that we use to expose the
 // remotesapi interface can choose to either create a newly accessed database
 // on first access or to return NotFound. Currently we allow creation in the
@@ -82,15 +191,16 @@ const CreateUnknownDatabases CreateUnknownDatabasesSetting = true
 const DoNotCreateUnknownDatabases CreateUnknownDatabasesSetting = false
 
 // Considers |args| and returns a new |remotesrv.ServerArgs| instance which
-// will serve databases accessible through |ctxFactory|.
-func RemoteSrvFSAndDBCache(ctxFactory func(context.Context) (*sql.Context, error), createSetting CreateUnknownDatabasesSetting) (filesys.Filesys, remotesrv.DBCache, error) {
+// will serve databases accessible through |ctxFactory|. |pushHooks| are run
+// for every push that updates the head of a branch.
+func RemoteSrvFSAndDBCache(ctxFactory func(context.Context) (*sql.Context, error), createSetting CreateUnknownDatabasesSetting, pushHooks ...RemoteSrvPushHook) (filesys.Filesys, remotesrv.DBCache, error) {
 	sqlCtx, err := ctxFactory(context.Background())
 	if err != nil {
 		return nil, nil, err
 	}
 	sess := dsess.DSessFromSess(sqlCtx.Session)
 	fs := sess.Provider().FileSystem()
-	dbcache := remotesrvStore{ctxFactory, bool(createSetting)}
+	dbcache := remotesrvStore{ctxFactory, bool(createSetting), pushHooks}
 	return fs, dbcache, nil
 }
 
@@ -0,0 +1,86 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/signedcommits"
+)
+
+// signedCommitsPushHook rejects pushes which add commits to the branches protected by a signedcommits.Policy that are
+// not signed by a trusted key.
+type signedCommitsPushHook struct {
+	ctxFactory func(context.Context) (*sql.Context, error)
+	policy     *signedcommits.Policy
+}
+
+var _ RemoteSrvPushHook = signedCommitsPushHook{}
+
+// NewSignedCommitsPushHook returns a RemoteSrvPushHook which enforces |policy| on pushes, using sessions from
+// |ctxFactory| to read the pushed commits.
+func NewSignedCommitsPushHook(ctxFactory func(context.Context) (*sql.Context, error), policy *signedcommits.Policy) RemoteSrvPushHook {
+	return signedCommitsPushHook{ctxFactory: ctxFactory, policy: policy}
+}
+
+// BeforePush implements RemoteSrvPushHook. Only the commits which are not already reachable from a protected branch
+// are checked, so a push is never rejected because of commits made before the policy was in place.
+func (h signedCommitsPushHook) BeforePush(ctx context.Context, dbName string, updates []BranchHeadUpdate) error {
+	protected := false
+	for _, update := range updates {
+		protected = protected || h.policy.Protects(update.Branch)
+	}
+	if !protected {
+		return nil
+	}
+
+	sqlCtx, err := h.ctxFactory(ctx)
+	if err != nil {
+		return err
+	}
+	db, err := dsess.DSessFromSess(sqlCtx.Session).Provider().Database(sqlCtx, dbName)
+	if err != nil {
+		return err
+	}
+	sdb, ok := db.(dsess.SqlDatabase)
+	if !ok {
+		return fmt.Errorf("unexpected database type: %T", db)
+	}
+	ddb := sdb.DbData().Ddb
+
+	trustedHeads, err := h.policy.ProtectedHeads(sqlCtx, ddb)
+	if err != nil {
+		return err
+	}
+
+	for _, update := range updates {
+		err = h.policy.CheckBranchUpdate(sqlCtx, ddb, update.Branch, update.NewHead, trustedHeads)
+		if signedcommits.ErrUntrustedCommitUpdate.Is(err) {
+			return status.Error(codes.FailedPrecondition, err.Error())
+		} else if err != nil {
+			return status.Errorf(codes.Internal, "error checking commit signatures for branch %s: %v", update.Branch, err)
+		}
+	}
+	return nil
+}
+
+// AfterPush implements RemoteSrvPushHook.
+func (h signedCommitsPushHook) AfterPush(context.Context, string, []BranchHeadUpdate) {}
@@ -0,0 +1,181 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signedcommits
+
+import (
+	"context"
+	"io"
+
+	goerrors "gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/utils/gpg"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// ErrUntrustedCommit is returned when a commit to a protected branch is not signed by a trusted key.
+var ErrUntrustedCommit = goerrors.NewKind("branch %s requires commits signed by a trusted key: %s")
+
+// ErrUntrustedCommitUpdate is returned when an update to a protected branch adds a commit which is not signed by a
+// trusted key.
+var ErrUntrustedCommitUpdate = goerrors.NewKind("branch %s requires commits signed by a trusted key, commit %s was rejected: %s")
+
+// Policy requires that commits to protected branches are signed by a key in the allowed signers. A signature is only
+// accepted if it is good, that is, made by a key trusted for the email of the commit's author. A Policy is a
+// doltdb.CommitValidator, so when it is added to a DoltDB it is checked before every branch head update, covering
+// commits, merges, cherry-picks, reverts, rebases, resets and branch creation alike.
+type Policy struct {
+	branches           []branch_control.MatchExpression
+	allowedSignersPath string
+}
+
+// NewPolicy returns a Policy protecting the branches matching any of |branchExprs|, which use the same syntax as the
+// branch column of dolt_branch_control. The allowed signers are read from |allowedSignersPath|, or the allowedsigners
+// system variable if it is empty.
+func NewPolicy(branchExprs []string, allowedSignersPath string) *Policy {
+	return &Policy{
+		branches:           branch_control.ParseBranchExpressions(branchExprs),
+		allowedSignersPath: allowedSignersPath,
+	}
+}
+
+// Protects returns whether commits to |branch| must be signed.
+func (p *Policy) Protects(branch string) bool {
+	return branch_control.MatchesBranch(p.branches, branch)
+}
+
+// AllowedSigners returns the keys trusted to sign commits to protected branches.
+func (p *Policy) AllowedSigners() (*gpg.AllowedSigners, error) {
+	if p.allowedSignersPath != "" {
+		return gpg.LoadAllowedSigners(p.allowedSignersPath)
+	}
+	return dsess.GetAllowedSigners()
+}
+
+// CheckPendingCommit returns an error if a commit to |branch| with |meta| and |parents| of the root |rootHash| would
+// not be allowed.
+func (p *Policy) CheckPendingCommit(ctx context.Context, branch string, meta *datas.CommitMeta, parents []hash.Hash, rootHash hash.Hash) error {
+	if !p.Protects(branch) {
+		return nil
+	}
+	signers, err := p.AllowedSigners()
+	if err != nil {
+		return err
+	}
+	v := doltdb.VerifyCommitMetaSignature(meta, parents, rootHash, signers)
+	if v.Status != gpg.SignatureStatusGood {
+		return ErrUntrustedCommit.New(branch, v.String())
+	}
+	return nil
+}
+
+// CheckBranchUpdate returns an error if moving |branch| to |newHead| would add a commit to it which is not signed by a
+// trusted key. Commits reachable from |trustedHeads|, such as the heads of the protected branches, were already
+// checked and are not checked again.
+func (p *Policy) CheckBranchUpdate(ctx context.Context, ddb *doltdb.DoltDB, branch string, newHead hash.Hash, trustedHeads []hash.Hash) error {
+	if !p.Protects(branch) {
+		return nil
+	}
+	signers, err := p.AllowedSigners()
+	if err != nil {
+		return err
+	}
+
+	itr, err := commitwalk.GetDotDotRevisionsIterator(ctx, ddb, []hash.Hash{newHead}, ddb, trustedHeads, nil)
+	if err != nil {
+		return err
+	}
+	for {
+		h, optCmt, err := itr.Next(ctx)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		cm, ok := optCmt.ToCommit()
+		if !ok {
+			return doltdb.ErrGhostCommitEncountered
+		}
+
+		v, err := doltdb.VerifyCommitSignature(ctx, cm, signers)
+		if err != nil {
+			return err
+		}
+		if v.Status != gpg.SignatureStatusGood {
+			return ErrUntrustedCommitUpdate.New(branch, h.String(), v.String())
+		}
+	}
+}
+
+// ProtectedHeads returns the heads of the branches in |ddb| protected by the policy. Commits reachable from them have
+// already been checked.
+func (p *Policy) ProtectedHeads(ctx context.Context, ddb *doltdb.DoltDB) ([]hash.Hash, error) {
+	branches, err := ddb.GetBranchesWithHashes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var heads []hash.Hash
+	for _, b := range branches {
+		if p.Protects(b.Ref.GetPath()) {
+			heads = append(heads, b.Hash)
+		}
+	}
+	return heads, nil
+}
+
+var _ doltdb.CommitValidator = (*Policy)(nil)
+
+// ValidateCommit implements doltdb.CommitValidator. The new commit must be signed, and so must the commits it merges
+// which aren't already on a protected branch.
+func (p *Policy) ValidateCommit(ctx context.Context, ddb *doltdb.DoltDB, branch ref.DoltRef, meta *datas.CommitMeta, parents []hash.Hash, rootHash hash.Hash) error {
+	if !p.Protects(branch.GetPath()) {
+		return nil
+	}
+	if err := p.CheckPendingCommit(ctx, branch.GetPath(), meta, parents, rootHash); err != nil {
+		return err
+	}
+	if len(parents) < 2 {
+		return nil
+	}
+
+	trusted, err := p.ProtectedHeads(ctx, ddb)
+	if err != nil {
+		return err
+	}
+	trusted = append(trusted, parents[0])
+	for _, parent := range parents[1:] {
+		if err := p.CheckBranchUpdate(ctx, ddb, branch.GetPath(), parent, trusted); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateBranchUpdate implements doltdb.CommitValidator.
+func (p *Policy) ValidateBranchUpdate(ctx context.Context, ddb *doltdb.DoltDB, branch ref.DoltRef, newHead hash.Hash) error {
+	if !p.Protects(branch.GetPath()) {
+		return nil
+	}
+	trusted, err := p.ProtectedHeads(ctx, ddb)
+	if err != nil {
+		return err
+	}
+	return p.CheckBranchUpdate(ctx, ddb, branch.GetPath(), newHead, trusted)
+}
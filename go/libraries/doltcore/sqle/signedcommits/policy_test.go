@@ -0,0 +1,97 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signedcommits
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/utils/gpg"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestPolicyProtects(t *testing.T) {
+	p := NewPolicy([]string{"main", "release/%"}, "")
+	require.True(t, p.Protects("main"))
+	require.True(t, p.Protects("release/1.0"))
+	require.False(t, p.Protects("feature"))
+	require.False(t, p.Protects("mainline"))
+}
+
+func TestPolicyCheckPendingCommit(t *testing.T) {
+	dir := t.TempDir()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	require.NoError(t, err)
+	keyPath := filepath.Join(dir, "id_ed25519")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(block), 0600))
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+	signersPath := filepath.Join(dir, "allowed_signers")
+	require.NoError(t, os.WriteFile(signersPath, append([]byte("test@dolthub.com "), ssh.MarshalAuthorizedKey(sshPub)...), 0600))
+
+	ctx := context.Background()
+	p := NewPolicy([]string{"main"}, signersPath)
+	date := time.UnixMilli(1000)
+	rootHash := hash.Of([]byte("root"))
+	parents := []hash.Hash{hash.Of([]byte("parent"))}
+
+	keys, err := gpg.LoadSigningKeys([]string{keyPath})
+	require.NoError(t, err)
+	signedMeta := func(email string) *datas.CommitMeta {
+		payload := doltdb.CommitSignaturePayload("db", "message", "Test User", email, date, parents, rootHash)
+		signature, err := keys.Sign(ctx, ssh.FingerprintSHA256(sshPub), []byte(payload))
+		require.NoError(t, err)
+		return &datas.CommitMeta{Name: "Test User", Email: email, Description: "message", UserTimestamp: date.UnixMilli(), Signature: string(signature)}
+	}
+
+	unsigned := &datas.CommitMeta{Name: "Test User", Email: "test@dolthub.com", Description: "message"}
+	err = p.CheckPendingCommit(ctx, "main", unsigned, parents, rootHash)
+	require.True(t, ErrUntrustedCommit.Is(err))
+	require.NoError(t, p.CheckPendingCommit(ctx, "feature", unsigned, parents, rootHash))
+
+	require.NoError(t, p.CheckPendingCommit(ctx, "main", signedMeta("test@dolthub.com"), parents, rootHash))
+
+	// the key is not trusted for other emails
+	err = p.CheckPendingCommit(ctx, "main", signedMeta("other@dolthub.com"), parents, rootHash)
+	require.True(t, ErrUntrustedCommit.Is(err))
+
+	// the signature must be of the commit's data
+	err = p.CheckPendingCommit(ctx, "main", signedMeta("test@dolthub.com"), parents, hash.Of([]byte("other root")))
+	require.True(t, ErrUntrustedCommit.Is(err))
+
+	// the signature can't be replayed onto other parents
+	err = p.CheckPendingCommit(ctx, "main", signedMeta("test@dolthub.com"), []hash.Hash{hash.Of([]byte("other parent"))}, rootHash)
+	require.True(t, ErrUntrustedCommit.Is(err))
+
+	// or with another date
+	redated := signedMeta("test@dolthub.com")
+	redated.UserTimestamp += 1000
+	err = p.CheckPendingCommit(ctx, "main", redated, parents, rootHash)
+	require.True(t, ErrUntrustedCommit.Is(err))
+}
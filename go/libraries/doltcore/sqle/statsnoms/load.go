@@ -76,24 +76,27 @@ func loadStats(ctx *sql.Context, db dsess.SqlDatabase, m prolly.Map) (map[sql.St
 		qual := sql.NewStatQualifier(dbName, schemaName, tableName, indexName)
 		if currentStat.Statistic.Qual.String() != qual.String() {
 			if !currentStat.Statistic.Qual.Empty() {
-				currentStat.Statistic.LowerBnd, currentStat.Tb, err = loadLowerBound(ctx, db, currentStat.Statistic.Qual, len(currentStat.Columns()))
+				currentStat.Statistic.LowerBnd, currentStat.Tb, err = loadLowerBound(ctx, db, currentStat.Statistic.Qual, currentStat.Columns())
 				if err != nil {
 					return nil, err
 				}
-				fds, colSet, err := loadFuncDeps(ctx, db, currentStat.Statistic.Qual)
+				fds, colSet, err := loadFuncDeps(ctx, db, currentStat.Statistic.Qual, currentStat.Columns())
 				if err != nil {
 					return nil, err
 				}
 				currentStat.Statistic.Fds = fds
 				currentStat.Statistic.Colset = colSet
 				currentStat.UpdateActive()
+				if statspro.IsColumnStats(currentStat.Statistic.Qual) {
+					statspro.UpdateColumnStatCounts(currentStat)
+				}
 				qualToStats[currentStat.Statistic.Qual] = currentStat
 			}
 
 			currentStat = statspro.NewDoltStats()
 			currentStat.Statistic.Qual = qual
 			currentStat.Statistic.Cols = columns
-			currentStat.Statistic.LowerBnd, currentStat.Tb, err = loadLowerBound(ctx, db, currentStat.Statistic.Qual, len(currentStat.Columns()))
+			currentStat.Statistic.LowerBnd, currentStat.Tb, err = loadLowerBound(ctx, db, currentStat.Statistic.Qual, currentStat.Columns())
 			if err != nil {
 				return nil, err
 			}
@@ -168,17 +171,20 @@ func loadStats(ctx *sql.Context, db dsess.SqlDatabase, m prolly.Map) (map[sql.St
 			currentStat.Statistic.Created = createdAt
 		}
 	}
-	currentStat.Statistic.LowerBnd, currentStat.Tb, err = loadLowerBound(ctx, db, currentStat.Statistic.Qual, len(currentStat.Columns()))
+	currentStat.Statistic.LowerBnd, currentStat.Tb, err = loadLowerBound(ctx, db, currentStat.Statistic.Qual, currentStat.Columns())
 	if err != nil {
 		return nil, err
 	}
-	fds, colSet, err := loadFuncDeps(ctx, db, currentStat.Statistic.Qual)
+	fds, colSet, err := loadFuncDeps(ctx, db, currentStat.Statistic.Qual, currentStat.Columns())
 	if err != nil {
 		return nil, err
 	}
 	currentStat.Statistic.Fds = fds
 	currentStat.Statistic.Colset = colSet
 	currentStat.UpdateActive()
+	if statspro.IsColumnStats(currentStat.Statistic.Qual) {
+		statspro.UpdateColumnStatCounts(currentStat)
+	}
 	qualToStats[currentStat.Statistic.Qual] = currentStat
 	return qualToStats, nil
 }
@@ -195,7 +201,7 @@ func parseTypeStrings(typs []string) ([]sql.Type, error) {
 	return ret, nil
 }
 
-func loadLowerBound(ctx *sql.Context, db dsess.SqlDatabase, qual sql.StatQualifier, cols int) (sql.Row, *val.TupleBuilder, error) {
+func loadLowerBound(ctx *sql.Context, db dsess.SqlDatabase, qual sql.StatQualifier, cols []string) (sql.Row, *val.TupleBuilder, error) {
 	root, err := db.GetRoot(ctx)
 	table, ok, err := root.GetTable(ctx, doltdb.TableName{Name: qual.Table()})
 	if !ok {
@@ -205,6 +211,16 @@ func loadLowerBound(ctx *sql.Context, db dsess.SqlDatabase, qual sql.StatQualifi
 		return nil, nil, err
 	}
 
+	if statspro.IsColumnStats(qual) {
+		// column values are not ordered by any index, the lower bound is unknown
+		sch, err := table.GetSchema(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		tb, _, err := statspro.ColumnStatsTupleBuilder(sch, cols)
+		return nil, tb, err
+	}
+
 	var idx durable.Index
 	if qual.Index() == "primary" {
 		idx, err = table.GetRowData(ctx)
@@ -216,7 +232,7 @@ func loadLowerBound(ctx *sql.Context, db dsess.SqlDatabase, qual sql.StatQualifi
 	}
 
 	prollyMap := durable.ProllyMapFromIndex(idx)
-	keyBuilder := val.NewTupleBuilder(prollyMap.KeyDesc().PrefixDesc(cols))
+	keyBuilder := val.NewTupleBuilder(prollyMap.KeyDesc().PrefixDesc(len(cols)))
 	buffPool := prollyMap.NodeStore().Pool()
 
 	if cnt, err := prollyMap.Count(); err != nil {
@@ -247,7 +263,7 @@ func loadLowerBound(ctx *sql.Context, db dsess.SqlDatabase, qual sql.StatQualifi
 	return firstRow, keyBuilder, nil
 }
 
-func loadFuncDeps(ctx *sql.Context, db dsess.SqlDatabase, qual sql.StatQualifier) (*sql.FuncDepSet, sql.ColSet, error) {
+func loadFuncDeps(ctx *sql.Context, db dsess.SqlDatabase, qual sql.StatQualifier, cols []string) (*sql.FuncDepSet, sql.ColSet, error) {
 	tab, ok, err := db.GetTableInsensitive(ctx, qual.Table())
 	if err != nil {
 		return nil, sql.ColSet{}, err
@@ -255,6 +271,10 @@ func loadFuncDeps(ctx *sql.Context, db dsess.SqlDatabase, qual sql.StatQualifier
 		return nil, sql.ColSet{}, fmt.Errorf("%w: table not found: '%s'", statspro.ErrFailedToLoad, qual.Table())
 	}
 
+	if statspro.IsColumnStats(qual) {
+		return statspro.ColumnStatsFds(tab.Schema(), cols)
+	}
+
 	iat, ok := tab.(sql.IndexAddressable)
 	if !ok {
 		return nil, sql.ColSet{}, fmt.Errorf("%w: table does not have indexes: '%s'", statspro.ErrFailedToLoad, qual.Table())
@@ -26,6 +26,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly"
 	"github.com/dolthub/dolt/go/store/prolly/tree"
 )
 
@@ -84,6 +85,33 @@ func (p *Provider) BootstrapDatabaseStats(ctx *sql.Context, db string) error {
 }
 
 func (p *Provider) RefreshTableStatsWithBranch(ctx *sql.Context, table sql.Table, db string, branch string) error {
+	return p.refreshTableStats(ctx, table, db, branch, nil)
+}
+
+// UpdateColumnStats collects statistics for the columns |cols| of |table|,
+// which do not need to be indexed, leaving its other statistics as they
+// are. Once collected, column statistics are refreshed along with the
+// table's index statistics.
+func (p *Provider) UpdateColumnStats(ctx *sql.Context, table sql.Table, db string, cols []string) error {
+	dSess := dsess.DSessFromSess(ctx.Session)
+	branch, err := dSess.GetBranch()
+	if err != nil {
+		return err
+	}
+	if len(cols) == 0 {
+		return fmt.Errorf("no columns specified for column statistics")
+	}
+	lowerCols := make([]string, len(cols))
+	for i, c := range cols {
+		lowerCols[i] = strings.ToLower(c)
+	}
+	return p.refreshTableStats(ctx, table, db, branch, [][]string{lowerCols})
+}
+
+// refreshTableStats updates the index statistics and column statistics of
+// |table|. If |newColStats| is not empty, only the column statistics for
+// each of its column lists are updated.
+func (p *Provider) refreshTableStats(ctx *sql.Context, table sql.Table, db string, branch string, newColStats [][]string) error {
 	if !p.TryLockForUpdate(branch, db, table.Name()) {
 		return fmt.Errorf("already updating statistics")
 	}
@@ -139,6 +167,21 @@ func (p *Provider) RefreshTableStatsWithBranch(ctx *sql.Context, table sql.Table
 		p.setStatDb(dbName, statDb)
 	}
 
+	// column statistics are not tied to indexes, and outlive schema changes
+	// to other columns
+	colStats, err := p.tableColumnStats(ctx, branch, dbName, schemaName, tableName)
+	if err != nil {
+		return err
+	}
+	colStatCols := newColStats
+	if len(newColStats) > 0 {
+		indexes = nil
+	} else {
+		for _, stat := range colStats {
+			colStatCols = append(colStatCols, stat.Columns())
+		}
+	}
+
 	schHash, err := dTab.GetSchemaHash(ctx)
 	if err != nil {
 		return err
@@ -210,6 +253,35 @@ func (p *Provider) RefreshTableStatsWithBranch(ctx *sql.Context, table sql.Table
 		}
 	}
 
+	var colMetas []indexMeta
+	colMetaQuals := make(map[sql.StatQualifier]bool)
+	for _, cols := range colStatCols {
+		qual := sql.NewStatQualifier(db, schemaName, table.Name(), ColumnStatsIndexName(cols))
+		if colMetaQuals[qual] {
+			continue
+		}
+		colMetaQuals[qual] = true
+		if !hasColumns(sqlTable.Schema(), cols) {
+			// a column was dropped
+			statDb.DeleteStats(ctx, branch, qual)
+			continue
+		}
+		curStat, ok := statDb.GetStat(branch, qual)
+		if !ok {
+			curStat = NewDoltStats()
+			curStat.Statistic.Qual = qual
+		}
+		colMeta, err := newColumnMeta(ctx, curStat, dTab, cols)
+		if err != nil {
+			return err
+		}
+		colMetas = append(colMetas, colMeta)
+	}
+
+	if err := updateColumnStats(ctx, statDb, branch, sqlTable, dTab, colMetas); err != nil {
+		return err
+	}
+
 	p.UpdateStatus(dbName, fmt.Sprintf("refreshed %s", dbName))
 	return statDb.Flush(ctx, branch)
 }
@@ -243,17 +315,7 @@ func GetLatestTable(ctx *sql.Context, tableName string, sqlDb sql.Database) (sql
 		return nil, nil, fmt.Errorf("statistics refresh error: table not found %s", tableName)
 	}
 
-	var dTab *doltdb.Table
-	switch t := sqlTable.(type) {
-	case *sqle.AlterableDoltTable:
-		dTab, err = t.DoltTable.DoltTable(ctx)
-	case *sqle.WritableDoltTable:
-		dTab, err = t.DoltTable.DoltTable(ctx)
-	case *sqle.DoltTable:
-		dTab, err = t.DoltTable(ctx)
-	default:
-		err = fmt.Errorf("failed to unwrap dolt table from type: %T", sqlTable)
-	}
+	dTab, err := doltTableFromSql(ctx, sqlTable)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -272,8 +334,13 @@ func newIdxMeta(ctx *sql.Context, curStats *DoltStats, doltTable *doltdb.Table,
 		return indexMeta{}, err
 	}
 
-	prollyMap := durable.ProllyMapFromIndex(idx)
+	return newChunkMeta(ctx, curStats, durable.ProllyMapFromIndex(idx), cols)
+}
 
+// newChunkMeta partitions the histogram level chunks of |prollyMap| into the
+// chunks already summarized by |curStats| and the chunks that need to be read
+// to bring the statistic up to date.
+func newChunkMeta(ctx *sql.Context, curStats *DoltStats, prollyMap prolly.Map, cols []string) (indexMeta, error) {
 	if cnt, err := prollyMap.Count(); err != nil {
 		return indexMeta{}, err
 	} else if cnt == 0 {
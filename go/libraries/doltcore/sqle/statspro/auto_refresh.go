@@ -168,6 +168,11 @@ func (p *Provider) checkRefresh(ctx *sql.Context, sqlDb sql.Database, dbName, br
 			schemaName = strings.ToLower(schTab.DatabaseSchema().SchemaName())
 		}
 
+		colStats, err := p.tableColumnStats(ctx, branch, dbName, schemaName, table)
+		if err != nil {
+			return err
+		}
+
 		if oldSchHash, err := statDb.GetSchemaHash(ctx, branch, table); oldSchHash.IsEmpty() {
 			if err := statDb.SetSchemaHash(ctx, branch, table, schHash); err != nil {
 				return err
@@ -239,6 +244,39 @@ func (p *Provider) checkRefresh(ctx *sql.Context, sqlDb sql.Database, dbName, br
 			}
 		}
 
+		// collect column statistics to be updated
+		var colMetas []indexMeta
+		for _, colStat := range colStats {
+			qual := colStat.Qualifier()
+			if !hasColumns(sqlTable.Schema(), colStat.Columns()) {
+				// a column was dropped
+				continue
+			}
+			qualExists[qual] = true
+			curStat, ok := statDb.GetStat(branch, qual)
+			if !ok {
+				// statistics were reset by a schema change
+				curStat = NewDoltStats()
+				curStat.Statistic.Qual = qual
+			}
+			updateMeta, err := newColumnMeta(ctx, curStat, dTab, colStat.Columns())
+			if err != nil {
+				ctx.GetLogger().Debugf("statistics refresh error: %s", err.Error())
+				continue
+			}
+			if len(curStat.Active) == 0 || columnStatsChanged(curStat, updateMeta) > updateThresh {
+				if len(curStat.Active) == 0 && len(updateMeta.newNodes) == 0 {
+					continue
+				}
+				ctx.GetLogger().Debugf("statistics updating: %s", updateMeta.qual)
+				colMetas = append(colMetas, updateMeta)
+				statDb.SetTableHash(branch, table, tableHash)
+			}
+		}
+		if err := updateColumnStats(ctx, statDb, branch, sqlTable, dTab, colMetas); err != nil {
+			return err
+		}
+
 		// get new buckets for index chunks to update
 		newTableStats, err := createNewStatsBuckets(ctx, sqlTable, dTab, indexes, idxMetas)
 		if err != nil {
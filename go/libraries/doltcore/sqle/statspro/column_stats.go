@@ -0,0 +1,464 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statspro
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/stats"
+	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// Column statistics describe table columns that are not necessarily the
+// prefix of an index. They are qualified by a pseudo index name built
+// from their columns, and are collected from a uniform sample of the rows
+// of the primary index. The sample is sorted by value and cut into
+// equal-height buckets, so that buckets are ordered by value like the
+// buckets of index statistics. The histogram level chunks of the primary
+// index are tracked to detect changes, but because a changed chunk can
+// move the bounds of any bucket, an update resamples the whole table.
+const (
+	columnStatsPrefix = "$column"
+	// columnSampleCnt is the maximum number of rows sampled from a table.
+	// Tables with fewer rows are read in full.
+	columnSampleCnt = 16384
+	// columnBucketCnt is the maximum number of buckets in a column
+	// statistic, and columnBucketRows is the minimum number of sampled
+	// rows summarized by each bucket.
+	columnBucketCnt  = 64
+	columnBucketRows = 128
+)
+
+// ColumnStatsIndexName returns the index name that qualifies the column
+// statistic over |cols|.
+func ColumnStatsIndexName(cols []string) string {
+	return fmt.Sprintf("%s(%s)", columnStatsPrefix, strings.ToLower(strings.Join(cols, ",")))
+}
+
+// IsColumnStats returns whether |qual| identifies a column statistic
+// rather than an index statistic.
+func IsColumnStats(qual sql.StatQualifier) bool {
+	return strings.HasPrefix(qual.Index(), columnStatsPrefix+"(")
+}
+
+// ColumnStatsTupleBuilder returns the tuple builder used to encode the
+// bounds and most common values of the column statistic over |cols|,
+// along with the SQL types of those columns.
+func ColumnStatsTupleBuilder(sch schema.Schema, cols []string) (*val.TupleBuilder, []sql.Type, error) {
+	valTypes := make([]val.Type, len(cols))
+	sqlTypes := make([]sql.Type, len(cols))
+	for i, c := range cols {
+		col, ok := sch.GetAllCols().GetByNameCaseInsensitive(c)
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: column not found: '%s'", ErrFailedToLoad, c)
+		}
+		if col.Virtual {
+			return nil, nil, fmt.Errorf("histograms are not supported for generated column '%s'", col.Name)
+		}
+		typ := col.TypeInfo.ToSqlType()
+		if gmstypes.IsTextBlob(typ) || gmstypes.IsJSON(typ) || gmstypes.IsGeometry(typ) {
+			return nil, nil, fmt.Errorf("histograms are not supported for column '%s' of type %s", col.Name, typ.String())
+		}
+		valTypes[i] = val.Type{Enc: val.Encoding(schema.EncodingFromSqlType(typ)), Nullable: true}
+		sqlTypes[i] = typ
+	}
+	return val.NewTupleBuilder(val.NewTupleDescriptor(valTypes...)), sqlTypes, nil
+}
+
+// ColumnStatsFds returns the functional dependencies and column set of the
+// column statistic over |cols| of a table with schema |sch|. Unlike index
+// statistics, column statistics never describe a key.
+func ColumnStatsFds(sch sql.Schema, cols []string) (*sql.FuncDepSet, sql.ColSet, error) {
+	var statCols sql.ColSet
+	for _, c := range cols {
+		i := sch.IndexOfColName(strings.ToLower(c))
+		if i < 0 {
+			return nil, statCols, fmt.Errorf("column not found on table during stats building: %s", c)
+		}
+		statCols.Add(sql.ColumnId(i + 1))
+	}
+
+	var all sql.ColSet
+	var notNull sql.ColSet
+	for i, col := range sch {
+		all.Add(sql.ColumnId(i + 1))
+		if !col.Nullable {
+			notNull.Add(sql.ColumnId(i + 1))
+		}
+	}
+	return sql.NewTablescanFDs(all, nil, nil, notNull), statCols, nil
+}
+
+// hasColumns returns whether every column in |cols| is in |sch|.
+func hasColumns(sch sql.Schema, cols []string) bool {
+	for _, c := range cols {
+		if sch.IndexOfColName(c) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// tableColumnStats returns the column statistics currently tracked for a table.
+func (p *Provider) tableColumnStats(ctx *sql.Context, branch, db, schemaName, table string) ([]*DoltStats, error) {
+	tableStats, err := p.GetTableDoltStats(ctx, branch, db, schemaName, table)
+	if err != nil {
+		return nil, err
+	}
+	var ret []*DoltStats
+	for _, s := range tableStats {
+		if ds, ok := s.(*DoltStats); ok && IsColumnStats(ds.Qualifier()) {
+			ret = append(ret, ds)
+		}
+	}
+	return ret, nil
+}
+
+// newColumnMeta returns the update metadata for the column statistic
+// over |cols|. The chunks of the metadata are the histogram level chunks of
+// the primary index of |doltTable|, and its new nodes are the chunks that
+// were not summarized by |curStats|.
+func newColumnMeta(ctx *sql.Context, curStats *DoltStats, doltTable *doltdb.Table, cols []string) (indexMeta, error) {
+	idx, err := doltTable.GetRowData(ctx)
+	if err != nil {
+		return indexMeta{}, err
+	}
+	prollyMap := durable.ProllyMapFromIndex(idx)
+	ret := indexMeta{
+		qual: curStats.Statistic.Qual,
+		cols: cols,
+	}
+	if cnt, err := prollyMap.Count(); err != nil {
+		return indexMeta{}, err
+	} else if cnt == 0 {
+		return ret, nil
+	}
+
+	levelNodes, err := tree.GetHistogramLevel(ctx, prollyMap.Tuples(), bucketLowCnt)
+	if err != nil {
+		return indexMeta{}, err
+	}
+	for _, n := range levelNodes {
+		ret.allAddrs = append(ret.allAddrs, n.HashOf())
+		if _, ok := curStats.Active[n.HashOf()]; !ok {
+			ret.newNodes = append(ret.newNodes, n)
+		}
+	}
+	return ret, nil
+}
+
+// columnStatsChanged returns the fraction of the chunks summarized by
+// |curStat| that were added or removed according to |meta|.
+func columnStatsChanged(curStat *DoltStats, meta indexMeta) float64 {
+	if len(curStat.Active) == 0 {
+		return 1
+	}
+	kept := len(meta.allAddrs) - len(meta.newNodes)
+	return float64(len(meta.newNodes)+len(curStat.Active)-kept) / float64(len(curStat.Active))
+}
+
+// newColumnStats samples the primary index of |dTab| to build the column
+// statistic described by |meta|.
+func newColumnStats(ctx *sql.Context, sqlTable sql.Table, dTab *doltdb.Table, meta indexMeta) (*DoltStats, error) {
+	sch, err := dTab.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := dTab.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	prollyMap := durable.ProllyMapFromIndex(idx)
+
+	tb, types, err := ColumnStatsTupleBuilder(sch, meta.cols)
+	if err != nil {
+		return nil, err
+	}
+	fds, colSet, err := ColumnStatsFds(sqlTable.Schema(), meta.cols)
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]uint64, len(meta.cols))
+	for i, c := range meta.cols {
+		col, _ := sch.GetAllCols().GetByNameCaseInsensitive(c)
+		tags[i] = col.Tag
+	}
+
+	stat := NewDoltStats()
+	stat.Chunks = meta.allAddrs
+	stat.Statistic.Created = time.Now()
+	stat.Statistic.Cols = meta.cols
+	stat.Statistic.Typs = types
+	stat.Statistic.Qual = meta.qual
+	stat.Statistic.Fds = fds
+	stat.Statistic.Colset = colSet
+	stat.Tb = tb
+
+	rowCnt, err := prollyMap.Count()
+	if err != nil {
+		return nil, err
+	}
+	sample, err := sampleColumnRows(ctx, sch, prollyMap, tags, uint64(rowCnt))
+	if err != nil {
+		return nil, err
+	}
+	stat.Hist, err = columnBuckets(sample, types, uint64(rowCnt))
+	if err != nil {
+		return nil, err
+	}
+	stat.UpdateActive()
+	UpdateColumnStatCounts(stat)
+	return stat, nil
+}
+
+// updateColumnStats rebuilds each column statistic in |colMetas| whose
+// chunks changed, and replaces the statistics in |statDb|.
+func updateColumnStats(ctx *sql.Context, statDb Database, branch string, sqlTable sql.Table, dTab *doltdb.Table, colMetas []indexMeta) error {
+	for _, colMeta := range colMetas {
+		if curStat, ok := statDb.GetStat(branch, colMeta.qual); ok && len(colMeta.newNodes) == 0 && len(curStat.Active) == len(colMeta.allAddrs) {
+			// no data changes since the last update
+			continue
+		}
+		stat, err := newColumnStats(ctx, sqlTable, dTab, colMeta)
+		if err != nil {
+			return err
+		}
+		if err := statDb.SetStat(ctx, branch, colMeta.qual, stat); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateColumnStatCounts sets the table-wide counts of a column statistic
+// from its buckets. Buckets summarize disjoint ranges of values, so their
+// distinct counts add up.
+func UpdateColumnStatCounts(stat *DoltStats) {
+	var rows, distinct, nulls uint64
+	for _, b := range stat.Hist {
+		rows += b.RowCount()
+		distinct += b.DistinctCount()
+		nulls += b.NullCount()
+	}
+	stat.Statistic.RowCnt = rows
+	stat.Statistic.DistinctCnt = distinct
+	stat.Statistic.NullCnt = nulls
+}
+
+// sampleColumnRows reads the values of the columns |tags| from at most
+// |columnSampleCnt| evenly spaced rows of the |rowCnt| rows in |m|.
+func sampleColumnRows(ctx *sql.Context, sch schema.Schema, m prolly.Map, tags []uint64, rowCnt uint64) ([]sql.Row, error) {
+	var sample []sql.Row
+	if rowCnt <= columnSampleCnt {
+		iter, err := m.IterOrdinalRange(ctx, 0, rowCnt)
+		if err != nil {
+			return nil, err
+		}
+		rowIter := index.NewProllyRowIterForMap(sch, m, iter, tags)
+		for {
+			row, err := rowIter.Next(ctx)
+			if errors.Is(err, io.EOF) {
+				return sample, nil
+			} else if err != nil {
+				return nil, err
+			}
+			sample = append(sample, row)
+		}
+	}
+
+	stride := float64(rowCnt) / columnSampleCnt
+	for i := 0; i < columnSampleCnt; i++ {
+		ordinal := uint64(float64(i) * stride)
+		iter, err := m.IterOrdinalRange(ctx, ordinal, ordinal+1)
+		if err != nil {
+			return nil, err
+		}
+		row, err := index.NewProllyRowIterForMap(sch, m, iter, tags).Next(ctx)
+		if errors.Is(err, io.EOF) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		sample = append(sample, row)
+	}
+	return sample, nil
+}
+
+// columnBuckets sorts |sample|, a uniform sample of |rowCnt| rows, and cuts
+// it into buckets holding about the same number of sampled rows. Rows with
+// equal values always share a bucket, so the buckets summarize disjoint,
+// increasing ranges of values. The sampled counts of each bucket are scaled
+// to the size of the table.
+func columnBuckets(sample []sql.Row, types []sql.Type, rowCnt uint64) ([]sql.HistogramBucket, error) {
+	if len(sample) == 0 {
+		return nil, nil
+	}
+
+	var sortErr error
+	sort.SliceStable(sample, func(i, j int) bool {
+		cmp, err := compareColumnRows(types, sample[i], sample[j])
+		if err != nil {
+			sortErr = err
+		}
+		return cmp < 0
+	})
+	if sortErr != nil {
+		return nil, sortErr
+	}
+
+	// collapse the sorted sample into runs of equal values
+	var runs []int
+	var runVals []sql.Row
+	for _, row := range sample {
+		if len(runVals) > 0 {
+			cmp, err := compareColumnRows(types, runVals[len(runVals)-1], row)
+			if err != nil {
+				return nil, err
+			}
+			if cmp == 0 {
+				runs[len(runs)-1]++
+				continue
+			}
+		}
+		runs = append(runs, 1)
+		runVals = append(runVals, row)
+	}
+
+	bucketCnt := min(columnBucketCnt, max(1, len(sample)/columnBucketRows))
+	target := (len(sample) + bucketCnt - 1) / bucketCnt
+	scale := float64(rowCnt) / float64(len(sample))
+
+	var ret []sql.HistogramBucket
+	var scaled uint64
+	start, sampled := 0, 0
+	for i := range runs {
+		sampled += runs[i]
+		if sampled < target && i < len(runs)-1 {
+			continue
+		}
+		bucketRows := uint64(math.Round(float64(sampled) * scale))
+		if i == len(runs)-1 {
+			// assign rounding errors to the last bucket
+			bucketRows = rowCnt - min(scaled, rowCnt)
+		}
+		scaled += bucketRows
+		ret = append(ret, newColumnBucket(runs[start:i+1], runVals[start:i+1], sampled, bucketRows, scale))
+		start, sampled = i+1, 0
+	}
+	return ret, nil
+}
+
+// newColumnBucket summarizes |bucketRows| rows whose values are the sorted
+// |runVals|, each seen |runs| times in |sampled| sampled rows.
+func newColumnBucket(runs []int, runVals []sql.Row, sampled int, bucketRows uint64, scale float64) DoltBucket {
+	var nulls int
+	for i, row := range runVals {
+		for _, v := range row {
+			if v == nil {
+				nulls += runs[i]
+				break
+			}
+		}
+	}
+
+	// keep the most common values that are > twice as common as average
+	order := make([]int, len(runs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return runs[order[i]] > runs[order[j]]
+	})
+	var mcvVals []sql.Row
+	var mcvCnts []uint64
+	cutoff := 2 * float64(sampled) / float64(len(runs))
+	for _, i := range order {
+		if len(mcvVals) == mcvCnt || float64(runs[i]) < cutoff {
+			break
+		}
+		mcvVals = append(mcvVals, runVals[i])
+		mcvCnts = append(mcvCnts, uint64(math.Round(float64(runs[i])*scale)))
+	}
+
+	return DoltBucket{
+		Bucket: &stats.Bucket{
+			RowCnt:      bucketRows,
+			DistinctCnt: estimateDistinct(runs, sampled, bucketRows),
+			NullCnt:     uint64(math.Round(float64(nulls) * scale)),
+			BoundCnt:    uint64(math.Round(float64(runs[len(runs)-1]) * scale)),
+			BoundVal:    runVals[len(runVals)-1],
+			McvVals:     mcvVals,
+			McvsCnt:     mcvCnts,
+		},
+	}
+}
+
+// estimateDistinct estimates the number of distinct values in |rowCnt| rows
+// from the lengths of the value |runs| in a uniform sample of |sampleCnt| of those rows,
+// using the guaranteed-error estimator: values seen more than once are
+// assumed to be common, and values seen once are scaled up by the square
+// root of the sampling ratio.
+func estimateDistinct(runs []int, sampleCnt int, rowCnt uint64) uint64 {
+	if uint64(sampleCnt) >= rowCnt {
+		return uint64(len(runs))
+	}
+	var singletons, repeated float64
+	for _, r := range runs {
+		if r == 1 {
+			singletons++
+		} else {
+			repeated++
+		}
+	}
+	est := math.Sqrt(float64(rowCnt)/float64(sampleCnt))*singletons + repeated
+	return max(uint64(len(runs)), min(uint64(math.Round(est)), rowCnt))
+}
+
+// compareColumnRows orders rows of column values, sorting NULLs first.
+func compareColumnRows(types []sql.Type, l, r sql.Row) (int, error) {
+	for i, t := range types {
+		switch {
+		case l[i] == nil && r[i] == nil:
+			continue
+		case l[i] == nil:
+			return -1, nil
+		case r[i] == nil:
+			return 1, nil
+		}
+		cmp, err := t.Compare(l[i], r[i])
+		if err != nil {
+			return 0, err
+		}
+		if cmp != 0 {
+			return cmp, nil
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statspro
+
+import (
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/stats"
+	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumnBuckets(t *testing.T) {
+	types := []sql.Type{gmstypes.Int64}
+
+	// a sample of half of a table whose values are unrelated to its
+	// primary key order: each of 0..999 appears 10 times, plus 500 NULLs
+	var sample []sql.Row
+	for i := 0; i < 10000; i++ {
+		sample = append(sample, sql.Row{int64(i * 7919 % 1000)})
+	}
+	for i := 0; i < 500; i++ {
+		sample = append(sample, sql.Row{nil})
+	}
+	hist, err := columnBuckets(sample, types, 21000)
+	require.NoError(t, err)
+	require.LessOrEqual(t, len(hist), columnBucketCnt)
+	require.Greater(t, len(hist), columnBucketCnt/2)
+
+	stat := NewDoltStats()
+	stat.Hist = hist
+	UpdateColumnStatCounts(stat)
+	require.Equal(t, uint64(21000), stat.RowCount())
+	require.Equal(t, uint64(1000), stat.NullCount())
+	require.Equal(t, uint64(1001), stat.DistinctCount())
+
+	for i := 1; i < len(hist); i++ {
+		cmp, err := compareColumnRows(types, hist[i-1].UpperBound(), hist[i].UpperBound())
+		require.NoError(t, err)
+		require.Less(t, cmp, 0, "bucket bounds must increase")
+	}
+
+	// the histogram filters used for costing depend on value ordered buckets
+	bucketRows := float64(21000) / float64(len(hist))
+	gt, err := stats.PrefixGt(hist, types, int64(899))
+	require.NoError(t, err)
+	rows, _, _ := stats.GetNewCounts(gt)
+	require.InDelta(t, 2000, float64(rows), bucketRows)
+
+	lt, err := stats.PrefixLt(hist, types, int64(100))
+	require.NoError(t, err)
+	rows, _, _ = stats.GetNewCounts(lt)
+	require.InDelta(t, 2000, float64(rows), bucketRows)
+
+	null, err := stats.PrefixIsNull(hist)
+	require.NoError(t, err)
+	rows, _, _ = stats.GetNewCounts(null)
+	require.InDelta(t, 1000, float64(rows), bucketRows)
+}
+
+func TestColumnBucketsKeepRunsTogether(t *testing.T) {
+	types := []sql.Type{gmstypes.Int64}
+	var sample []sql.Row
+	for i := 0; i < 4*columnBucketRows; i++ {
+		sample = append(sample, sql.Row{int64(i % 3)})
+	}
+	hist, err := columnBuckets(sample, types, uint64(len(sample)))
+	require.NoError(t, err)
+	require.Len(t, hist, 3)
+	for i, b := range hist {
+		require.Equal(t, sql.Row{int64(i)}, b.UpperBound())
+		require.Equal(t, uint64(1), b.DistinctCount())
+		require.Equal(t, b.RowCount(), b.BoundCount())
+	}
+}
@@ -33,6 +33,9 @@ var helpMsg = "call dolt_stats_purge() to reset statistics"
 
 func (p *Provider) Configure(ctx context.Context, ctxFactory func(ctx context.Context) (*sql.Context, error), bThreads *sql.BackgroundThreads, dbs []dsess.SqlDatabase) error {
 	p.SetStarter(NewStatsInitDatabaseHook(p, ctxFactory, bThreads))
+	if err := p.startHistoryWorker(ctxFactory, bThreads); err != nil {
+		return err
+	}
 
 	if _, disabled, _ := sql.SystemVariables.GetGlobal(dsess.DoltStatsMemoryOnly); disabled == int8(1) {
 		return nil
@@ -0,0 +1,489 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statspro
+
+// Historical statistics describe the indexes of a table as of a commit
+// other than the head of a stats branch, as read by AS OF queries and
+// revision databases. They are cached in memory by the root hash of each
+// index, so commits that share an index tree share its statistics.
+// Building reuses the branch's histogram buckets for the chunks a
+// historical index has in common with the branch, and only reads the
+// chunks that differ. The query planner never waits for a build: it queues
+// one for the history worker and plans with the branch's statistics until
+// the build is cached. DOLT_STATS_FOR() builds them synchronously.
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/stats"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// historyCacheSize is the maximum number of historical index statistics
+// kept in memory.
+const historyCacheSize = 1024
+
+// historyQueueSize is the maximum number of background builds waiting for
+// the history worker. Builds requested while the queue is full are dropped,
+// and requested again by the next query that reads the commit.
+const historyQueueSize = 16
+
+const asyncStatsHistory = "async_stats_history"
+
+// historyKey identifies the statistics for a prefix of an index tree.
+type historyKey struct {
+	root      hash.Hash
+	prefixLen int
+}
+
+// historyBuild is a background build of the statistics described by
+// |idxMetas|, waiting in the history queue.
+type historyBuild struct {
+	sqlTable  sql.Table
+	dTab      *doltdb.Table
+	indexes   []sql.Index
+	idxMetas  []indexMeta
+	keys      map[sql.StatQualifier]historyKey
+	buildKeys []historyKey
+}
+
+// historyCache is a FIFO cache of historical index statistics, which also
+// tracks the statistics being built in the background.
+type historyCache struct {
+	mu       *sync.Mutex
+	stats    map[historyKey]*DoltStats
+	keys     []historyKey
+	building map[historyKey]struct{}
+	// queue is nil until the history worker is started
+	queue chan historyBuild
+}
+
+func newHistoryCache() *historyCache {
+	return &historyCache{
+		mu:       &sync.Mutex{},
+		stats:    make(map[historyKey]*DoltStats),
+		building: make(map[historyKey]struct{}),
+	}
+}
+
+// startBuild marks |keys| as being built, returning false if any of them
+// is already being built.
+func (c *historyCache) startBuild(keys []historyKey) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, k := range keys {
+		if _, ok := c.building[k]; ok {
+			return false
+		}
+	}
+	for _, k := range keys {
+		c.building[k] = struct{}{}
+	}
+	return true
+}
+
+// enqueue adds |b| to the history queue, returning false if the worker is
+// not running or the queue is full.
+func (c *historyCache) enqueue(b historyBuild) bool {
+	c.mu.Lock()
+	queue := c.queue
+	c.mu.Unlock()
+	select {
+	case queue <- b:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *historyCache) finishBuild(keys []historyKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, k := range keys {
+		delete(c.building, k)
+	}
+}
+
+func (c *historyCache) get(key historyKey) (*DoltStats, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stat, ok := c.stats[key]
+	return stat, ok
+}
+
+func (c *historyCache) put(key historyKey, stat *DoltStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.stats[key]; ok {
+		return
+	}
+	if len(c.keys) >= historyCacheSize {
+		delete(c.stats, c.keys[0])
+		c.keys = c.keys[1:]
+	}
+	c.stats[key] = stat
+	c.keys = append(c.keys, key)
+}
+
+// historyEnabled returns true if queries against past commits should be
+// planned with statistics for that commit.
+func historyEnabled() bool {
+	_, enabled, _ := sql.SystemVariables.GetGlobal(dsess.DoltStatsHistoryEnabled)
+	return enabled == int8(1)
+}
+
+// isHistoricalTable returns true if |dTab| is not the WORKING table of the
+// stats branch |branch|.
+func (p *Provider) isHistoricalTable(ctx *sql.Context, branch, db, schemaName, tableName string, dTab *doltdb.Table) (bool, error) {
+	dSess := dsess.DSessFromSess(ctx.Session)
+	baseName, _ := dsess.SplitRevisionDbName(db)
+	roots, ok := dSess.GetRoots(ctx, p.branchQualifiedDatabase(baseName, branch))
+	if !ok {
+		return true, nil
+	}
+	curTab, ok, err := roots.Working.GetTable(ctx, doltdb.TableName{Name: tableName, Schema: schemaName})
+	if err != nil {
+		return false, err
+	} else if !ok {
+		return true, nil
+	}
+
+	curHash, err := curTab.HashOf()
+	if err != nil {
+		return false, err
+	}
+	tabHash, err := dTab.HashOf()
+	if err != nil {
+		return false, err
+	}
+	return curHash != tabHash, nil
+}
+
+// GetRevisionDoltStats returns the index statistics of every table in
+// database |db| as of |revision|, building and caching any that are
+// missing.
+func (p *Provider) GetRevisionDoltStats(ctx *sql.Context, db, revision string) ([]sql.Statistic, error) {
+	dSess := dsess.DSessFromSess(ctx.Session)
+	branch, err := dSess.GetBranch()
+	if err != nil {
+		return nil, err
+	}
+
+	baseName, _ := dsess.SplitRevisionDbName(db)
+	sqlDb, err := dSess.Provider().Database(ctx, dsess.RevisionDbName(baseName, revision))
+	if err != nil {
+		return nil, err
+	}
+	tableNames, err := sqlDb.GetTableNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []sql.Statistic
+	for _, tableName := range tableNames {
+		if doltdb.HasDoltPrefix(tableName) {
+			continue
+		}
+		sqlTable, ok, err := sqlDb.GetTableInsensitive(ctx, tableName)
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+		dTab, err := doltTableFromSql(ctx, sqlTable)
+		if err != nil {
+			return nil, err
+		}
+		tableStats, _, err := p.historicalTableStats(ctx, branch, strings.ToLower(baseName), sqlTable, dTab, true)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, tableStats...)
+	}
+	return ret, nil
+}
+
+// getRevisionStats returns the statistic for |qual|, an index of a table
+// that the current query reads from a revision database, or AS OF |asOf|
+// if it is not nil. Until the table's statistics are built, the statistic
+// is the branch's.
+func (p *Provider) getRevisionStats(ctx *sql.Context, qual sql.StatQualifier, asOf interface{}) (sql.Statistic, bool) {
+	dSess := dsess.DSessFromSess(ctx.Session)
+	baseName, _ := dsess.SplitRevisionDbName(qual.Db())
+	branchStat := func() (sql.Statistic, bool) {
+		stat, ok := p.getQualStats(ctx, sql.NewStatQualifier(baseName, qual.Schema(), qual.Table(), qual.Index()))
+		if !ok {
+			return nil, false
+		}
+		return stat, true
+	}
+
+	branch, err := dSess.GetBranch()
+	if err != nil {
+		return nil, false
+	}
+	sqlDb, err := dSess.Provider().Database(ctx, qual.Db())
+	if err != nil {
+		return nil, false
+	}
+	var sqlTable sql.Table
+	var ok bool
+	if asOf != nil {
+		versionedDb, isVersioned := sqlDb.(sql.VersionedDatabase)
+		if !isVersioned {
+			return branchStat()
+		}
+		sqlTable, ok, err = versionedDb.GetTableInsensitiveAsOf(ctx, qual.Table(), asOf)
+	} else {
+		sqlTable, ok, err = sqlDb.GetTableInsensitive(ctx, qual.Table())
+	}
+	if err != nil || !ok {
+		return nil, false
+	}
+	dTab, err := doltTableFromSql(ctx, sqlTable)
+	if err != nil {
+		return nil, false
+	}
+	historical, err := p.isHistoricalTable(ctx, branch, qual.Db(), qual.Schema(), qual.Table(), dTab)
+	if err != nil {
+		return nil, false
+	} else if !historical {
+		return branchStat()
+	}
+	tableStats, complete, err := p.historicalTableStats(ctx, branch, qual.Db(), sqlTable, dTab, false)
+	if err != nil {
+		return nil, false
+	} else if !complete {
+		return branchStat()
+	}
+	for _, stat := range tableStats {
+		if stat.Qualifier() == qual {
+			return stat, true
+		}
+	}
+	return nil, false
+}
+
+// historicalTableStats returns the index statistics of |sqlTable|, whose
+// data is |dTab|, from the history cache. Missing statistics are built
+// before returning if |build| is true. Otherwise they are built in the
+// background and omitted, and the returned bool is false.
+func (p *Provider) historicalTableStats(ctx *sql.Context, branch, db string, sqlTable sql.Table, dTab *doltdb.Table, build bool) ([]sql.Statistic, bool, error) {
+	iat, ok := sqlTable.(sql.IndexAddressableTable)
+	if !ok {
+		return nil, true, nil
+	}
+	indexes, err := iat.GetIndexes(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var schemaName string
+	if schTab, ok := sqlTable.(sql.DatabaseSchemaTable); ok {
+		schemaName = strings.ToLower(schTab.DatabaseSchema().SchemaName())
+	}
+	tableName := strings.ToLower(sqlTable.Name())
+	tablePrefix := fmt.Sprintf("%s.", tableName)
+	baseName, _ := dsess.SplitRevisionDbName(db)
+	statDb, _ := p.getStatDb(baseName)
+
+	var ret []sql.Statistic
+	var idxMetas []indexMeta
+	keys := make(map[sql.StatQualifier]historyKey)
+	for _, idx := range indexes {
+		cols := make([]string, len(idx.Expressions()))
+		for i, c := range idx.Expressions() {
+			cols[i] = strings.TrimPrefix(strings.ToLower(c), tablePrefix)
+		}
+		qual := sql.NewStatQualifier(db, schemaName, tableName, strings.ToLower(idx.ID()))
+
+		var rows durable.Index
+		if strings.EqualFold(idx.ID(), "PRIMARY") {
+			rows, err = dTab.GetRowData(ctx)
+		} else {
+			rows, err = dTab.GetIndexRowData(ctx, idx.ID())
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		key := historyKey{root: durable.ProllyMapFromIndex(rows).HashOf(), prefixLen: len(cols)}
+
+		if cached, ok := p.history.get(key); ok {
+			fds, colSet, err := stats.IndexFds(tableName, sqlTable.Schema(), idx)
+			if err != nil {
+				return nil, false, err
+			}
+			ret = append(ret, cached.relabel(qual, cols, fds, colSet))
+			continue
+		}
+
+		// reuse the buckets of the branch's statistics for shared chunks
+		var curStat *DoltStats
+		if statDb != nil {
+			branchQual := sql.NewStatQualifier(baseName, schemaName, tableName, strings.ToLower(idx.ID()))
+			if branchStat, ok := statDb.GetStat(branch, branchQual); ok && len(branchStat.Columns()) == len(cols) {
+				curStat = branchStat
+			}
+		}
+		if curStat == nil {
+			curStat = NewDoltStats()
+		}
+		idxMeta, err := newIdxMeta(ctx, curStat, dTab, idx, cols)
+		if err != nil {
+			return nil, false, err
+		}
+		idxMeta.qual = qual
+		idxMetas = append(idxMetas, idxMeta)
+		keys[qual] = key
+	}
+
+	if len(idxMetas) == 0 {
+		return ret, true, nil
+	}
+
+	if !build {
+		var buildKeys []historyKey
+		for _, k := range keys {
+			buildKeys = append(buildKeys, k)
+		}
+		if p.history.startBuild(buildKeys) {
+			b := historyBuild{sqlTable: sqlTable, dTab: dTab, indexes: indexes, idxMetas: idxMetas, keys: keys, buildKeys: buildKeys}
+			if !p.history.enqueue(b) {
+				p.history.finishBuild(buildKeys)
+				ctx.GetLogger().Debugf("statistics history: build queue full, skipping %s", tableName)
+			}
+		}
+		return ret, false, nil
+	}
+
+	newStats, err := p.newHistoricalStats(ctx, sqlTable, dTab, indexes, idxMetas, keys)
+	if err != nil {
+		return nil, false, err
+	}
+	return append(ret, newStats...), true, nil
+}
+
+// startHistoryWorker starts the background thread that builds the
+// statistics queued by historicalTableStats. The thread stops when the
+// engine's background threads are shut down.
+func (p *Provider) startHistoryWorker(ctxFactory func(ctx context.Context) (*sql.Context, error), bThreads *sql.BackgroundThreads) error {
+	p.history.mu.Lock()
+	if p.history.queue != nil {
+		p.history.mu.Unlock()
+		return nil
+	}
+	queue := make(chan historyBuild, historyQueueSize)
+	p.history.queue = queue
+	p.history.mu.Unlock()
+
+	return bThreads.Add(asyncStatsHistory, func(ctx context.Context) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case b := <-queue:
+				p.runHistoryBuild(ctx, ctxFactory, b)
+			}
+		}
+	})
+}
+
+func (p *Provider) runHistoryBuild(ctx context.Context, ctxFactory func(ctx context.Context) (*sql.Context, error), b historyBuild) {
+	defer p.history.finishBuild(b.buildKeys)
+	sqlCtx, err := ctxFactory(ctx)
+	if err != nil {
+		sql.NewContext(ctx).GetLogger().Warnf("statistics history error: %s", err.Error())
+		return
+	}
+	if _, err := p.newHistoricalStats(sqlCtx, b.sqlTable, b.dTab, b.indexes, b.idxMetas, b.keys); err != nil && ctx.Err() == nil {
+		sqlCtx.GetLogger().Warnf("statistics history error for %s: %s", b.sqlTable.Name(), err.Error())
+	}
+}
+
+// newHistoricalStats builds the statistics described by |idxMetas| and adds
+// them to the history cache under |keys|.
+func (p *Provider) newHistoricalStats(ctx *sql.Context, sqlTable sql.Table, dTab *doltdb.Table, indexes []sql.Index, idxMetas []indexMeta, keys map[sql.StatQualifier]historyKey) ([]sql.Statistic, error) {
+	newTableStats, err := createNewStatsBuckets(ctx, sqlTable, dTab, indexes, idxMetas)
+	if err != nil {
+		return nil, err
+	}
+	var ret []sql.Statistic
+	for _, idxMeta := range idxMetas {
+		stat := newTableStats[idxMeta.qual]
+		if len(idxMeta.allAddrs) > 0 {
+			targetChunks, err := MergeNewChunks(idxMeta.allAddrs, idxMeta.keepChunks, stat.Hist)
+			if err != nil {
+				return nil, err
+			}
+			stat.SetChunks(idxMeta.allAddrs)
+			stat.Hist = targetChunks
+			stat.UpdateActive()
+		}
+		updateIndexStatCounts(stat)
+		p.history.put(keys[idxMeta.qual], stat)
+		ret = append(ret, stat)
+	}
+	return ret, nil
+}
+
+// updateIndexStatCounts sets the row, distinct and null counts of |stat|
+// from its buckets.
+func updateIndexStatCounts(stat *DoltStats) {
+	var rows, distinct, nulls uint64
+	for _, b := range stat.Hist {
+		rows += b.RowCount()
+		distinct += b.DistinctCount()
+		nulls += b.NullCount()
+	}
+	stat.Statistic.RowCnt = rows
+	stat.Statistic.DistinctCnt = distinct
+	stat.Statistic.NullCnt = nulls
+}
+
+// relabel returns a copy of |s| describing the index |qual|, which shares
+// its contents.
+func (s *DoltStats) relabel(qual sql.StatQualifier, cols []string, fds *sql.FuncDepSet, colSet sql.ColSet) *DoltStats {
+	ret := *s
+	statistic := *s.Statistic
+	statistic.Qual = qual
+	statistic.Cols = cols
+	statistic.Fds = fds
+	statistic.Colset = colSet
+	ret.Statistic = &statistic
+	return &ret
+}
+
+// doltTableFromSql returns the *doltdb.Table read by |sqlTable|.
+func doltTableFromSql(ctx *sql.Context, sqlTable sql.Table) (*doltdb.Table, error) {
+	switch t := sqlTable.(type) {
+	case *sqle.AlterableDoltTable:
+		return t.DoltTable.DoltTable(ctx)
+	case *sqle.WritableDoltTable:
+		return t.DoltTable.DoltTable(ctx)
+	case *sqle.DoltTable:
+		return t.DoltTable(ctx)
+	default:
+		return nil, fmt.Errorf("failed to unwrap dolt table from type: %T", sqlTable)
+	}
+}
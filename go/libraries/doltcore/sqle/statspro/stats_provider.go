@@ -59,6 +59,7 @@ func NewProvider(pro *sqle.DoltDatabaseProvider, sf StatsFactory) *Provider {
 		analyzeCtxCancelers: make(map[string]context.CancelFunc),
 		status:              make(map[string]string),
 		lockedTables:        make(map[string]bool),
+		history:             newHistoryCache(),
 	}
 }
 
@@ -75,6 +76,7 @@ type Provider struct {
 	starter             sqle.InitDatabaseHook
 	status              map[string]string
 	lockedTables        map[string]bool
+	history             *historyCache
 }
 
 // each database has one statistics table that is a collection of the
@@ -162,6 +164,10 @@ func (p *Provider) ThreadStatus(dbName string) string {
 	return "no active stats thread"
 }
 
+// GetTableStats returns the statistics of |table|. If
+// @@dolt_stats_history_enabled is set, a table read as of a commit other
+// than the current branch head, as in an AS OF query, gets statistics
+// for its own data once they are built.
 func (p *Provider) GetTableStats(ctx *sql.Context, db string, table sql.Table) ([]sql.Statistic, error) {
 	dSess := dsess.DSessFromSess(ctx.Session)
 	branch, err := dSess.GetBranch()
@@ -174,6 +180,22 @@ func (p *Provider) GetTableStats(ctx *sql.Context, db string, table sql.Table) (
 		schemaName = strings.ToLower(schTab.DatabaseSchema().SchemaName())
 	}
 
+	if historyEnabled() {
+		if dTab, err := doltTableFromSql(ctx, table); err == nil {
+			historical, err := p.isHistoricalTable(ctx, branch, db, schemaName, table.Name(), dTab)
+			if err != nil {
+				return nil, err
+			} else if historical {
+				tableStats, complete, err := p.historicalTableStats(ctx, branch, strings.ToLower(db), table, dTab, false)
+				if err != nil {
+					return nil, err
+				} else if complete {
+					return tableStats, nil
+				}
+			}
+		}
+	}
+
 	return p.GetTableDoltStats(ctx, branch, db, schemaName, table.Name())
 }
 
@@ -222,18 +244,34 @@ func (p *Provider) deleteStatDb(name string) {
 	delete(p.statDbs, strings.ToLower(name))
 }
 
+// SetStats replaces a statistic with |s|. A statistic without a histogram,
+// as in "ANALYZE TABLE t UPDATE HISTOGRAM ON (c) USING DATA '{}'", instead
+// collects column statistics for the columns of |s|.
 func (p *Provider) SetStats(ctx *sql.Context, s sql.Statistic) error {
-	statDb, ok := p.getStatDb(s.Qualifier().Db())
-	if !ok {
-		return nil
-	}
-
 	dSess := dsess.DSessFromSess(ctx.Session)
 	branch, err := dSess.GetBranch()
 	if err != nil {
 		return nil
 	}
 
+	if len(s.Histogram()) == 0 && len(s.Columns()) > 0 {
+		qual := s.Qualifier()
+		sqlDb, err := dSess.Provider().Database(ctx, p.branchQualifiedDatabase(qual.Db(), branch))
+		if err != nil {
+			return err
+		}
+		sqlTable, _, err := GetLatestTable(ctx, qual.Table(), sqlDb)
+		if err != nil {
+			return err
+		}
+		return p.UpdateColumnStats(ctx, sqlTable, qual.Db(), s.Columns())
+	}
+
+	statDb, ok := p.getStatDb(s.Qualifier().Db())
+	if !ok {
+		return nil
+	}
+
 	doltStat, err := DoltStatsFromSql(s)
 	if err != nil {
 		return err
@@ -259,7 +297,18 @@ func (p *Provider) getQualStats(ctx *sql.Context, qual sql.StatQualifier) (*Dolt
 	return statDb.GetStat(branch, qual)
 }
 
+// GetStats returns the statistic for |qual|. If @@dolt_stats_history_enabled
+// is set, statistics for the tables that the current query reads AS OF a
+// commit, or from a revision database, describe their own data once they
+// are built.
 func (p *Provider) GetStats(ctx *sql.Context, qual sql.StatQualifier, _ []string) (sql.Statistic, bool) {
+	if historyEnabled() {
+		if asOf, ok := dsess.DSessFromSess(ctx.Session).AsOfRead(ctx, qual.Db(), qual.Table()); ok {
+			return p.getRevisionStats(ctx, qual, asOf)
+		} else if _, rev := dsess.SplitRevisionDbName(qual.Db()); rev != "" {
+			return p.getRevisionStats(ctx, qual, nil)
+		}
+	}
 	stat, ok := p.getQualStats(ctx, qual)
 	if !ok {
 		return nil, false
@@ -298,7 +347,10 @@ func (p *Provider) DropDbStats(ctx *sql.Context, db string, flush bool) error {
 	return nil
 }
 
-func (p *Provider) DropStats(ctx *sql.Context, qual sql.StatQualifier, _ []string) error {
+// DropStats deletes the statistic identified by |qual|. A qualifier without
+// an index, as in "ANALYZE TABLE t DROP HISTOGRAM ON (c)", identifies the
+// column statistic over |cols|.
+func (p *Provider) DropStats(ctx *sql.Context, qual sql.StatQualifier, cols []string) error {
 	statDb, ok := p.getStatDb(qual.Db())
 	if !ok {
 		return nil
@@ -310,6 +362,10 @@ func (p *Provider) DropStats(ctx *sql.Context, qual sql.StatQualifier, _ []strin
 		return nil
 	}
 
+	if qual.Index() == "" && len(cols) > 0 {
+		qual = sql.NewStatQualifier(qual.Db(), qual.Schema(), qual.Table(), ColumnStatsIndexName(cols))
+	}
+
 	if _, ok := statDb.GetStat(branch, qual); ok {
 		statDb.DeleteStats(ctx, branch, qual)
 		p.UpdateStatus(qual.Db(), fmt.Sprintf("dropped statisic: %s", qual.String()))
@@ -38,6 +38,14 @@ var DoltSystemVariables = []sql.SystemVariable{
 		Type:              types.NewSystemStringType("log_bin_branch"),
 		Default:           "main",
 	},
+	&sql.MysqlSystemVariable{
+		Name:              "log_bin_branches",
+		Scope:             sql.GetMysqlScope(sql.SystemVariableScope_Persist),
+		Dynamic:           true,
+		SetVarHintApplies: false,
+		Type:              types.NewSystemStringType("log_bin_branches"),
+		Default:           "",
+	},
 	&sql.MysqlSystemVariable{
 		Name:              dsess.DoltOverrideSchema,
 		Scope:             sql.GetMysqlScope(sql.SystemVariableScope_Both),
@@ -253,6 +261,41 @@ var DoltSystemVariables = []sql.SystemVariable{
 		Type:    types.NewSystemStringType(dsess.DoltStatsBranches),
 		Default: "",
 	},
+	&sql.MysqlSystemVariable{
+		Name:    dsess.DoltStatsHistoryEnabled,
+		Dynamic: true,
+		Scope:   sql.GetMysqlScope(sql.SystemVariableScope_Global),
+		Type:    types.NewSystemBoolType(dsess.DoltStatsHistoryEnabled),
+		Default: int8(0),
+	},
+	&sql.MysqlSystemVariable{
+		Name:    dsess.DoltBinlogReplicaBranch,
+		Dynamic: true,
+		Scope:   sql.GetMysqlScope(sql.SystemVariableScope_Global),
+		Type:    types.NewSystemStringType(dsess.DoltBinlogReplicaBranch),
+		Default: "",
+	},
+	&sql.MysqlSystemVariable{
+		Name:    dsess.DoltBinlogReplicaCommitTransactions,
+		Dynamic: true,
+		Scope:   sql.GetMysqlScope(sql.SystemVariableScope_Global),
+		Type:    types.NewSystemIntType(dsess.DoltBinlogReplicaCommitTransactions, 0, math.MaxInt, false),
+		Default: 1,
+	},
+	&sql.MysqlSystemVariable{
+		Name:    dsess.DoltBinlogReplicaCommitInterval,
+		Dynamic: true,
+		Scope:   sql.GetMysqlScope(sql.SystemVariableScope_Global),
+		Type:    types.NewSystemIntType(dsess.DoltBinlogReplicaCommitInterval, 0, math.MaxInt, false),
+		Default: 0,
+	},
+	&sql.MysqlSystemVariable{
+		Name:    dsess.DoltBinlogReplicaHeartbeatTable,
+		Dynamic: true,
+		Scope:   sql.GetMysqlScope(sql.SystemVariableScope_Global),
+		Type:    types.NewSystemStringType(dsess.DoltBinlogReplicaHeartbeatTable),
+		Default: "",
+	},
 }
 
 func AddDoltSystemVariables() {
@@ -265,6 +308,14 @@ func AddDoltSystemVariables() {
 			Type:              types.NewSystemStringType("log_bin_branch"),
 			Default:           "main",
 		},
+		&sql.MysqlSystemVariable{
+			Name:              "log_bin_branches",
+			Scope:             sql.GetMysqlScope(sql.SystemVariableScope_Persist),
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemStringType("log_bin_branches"),
+			Default:           "",
+		},
 		&sql.MysqlSystemVariable{
 			Name:              dsess.DoltOverrideSchema,
 			Scope:             sql.GetMysqlScope(sql.SystemVariableScope_Both),
@@ -480,6 +531,41 @@ func AddDoltSystemVariables() {
 			Type:    types.NewSystemStringType(dsess.DoltStatsBranches),
 			Default: "",
 		},
+		&sql.MysqlSystemVariable{
+			Name:    dsess.DoltStatsHistoryEnabled,
+			Dynamic: true,
+			Scope:   sql.GetMysqlScope(sql.SystemVariableScope_Global),
+			Type:    types.NewSystemBoolType(dsess.DoltStatsHistoryEnabled),
+			Default: int8(0),
+		},
+		&sql.MysqlSystemVariable{
+			Name:    dsess.DoltBinlogReplicaBranch,
+			Dynamic: true,
+			Scope:   sql.GetMysqlScope(sql.SystemVariableScope_Global),
+			Type:    types.NewSystemStringType(dsess.DoltBinlogReplicaBranch),
+			Default: "",
+		},
+		&sql.MysqlSystemVariable{
+			Name:    dsess.DoltBinlogReplicaCommitTransactions,
+			Dynamic: true,
+			Scope:   sql.GetMysqlScope(sql.SystemVariableScope_Global),
+			Type:    types.NewSystemIntType(dsess.DoltBinlogReplicaCommitTransactions, 0, math.MaxInt, false),
+			Default: 1,
+		},
+		&sql.MysqlSystemVariable{
+			Name:    dsess.DoltBinlogReplicaCommitInterval,
+			Dynamic: true,
+			Scope:   sql.GetMysqlScope(sql.SystemVariableScope_Global),
+			Type:    types.NewSystemIntType(dsess.DoltBinlogReplicaCommitInterval, 0, math.MaxInt, false),
+			Default: 0,
+		},
+		&sql.MysqlSystemVariable{
+			Name:    dsess.DoltBinlogReplicaHeartbeatTable,
+			Dynamic: true,
+			Scope:   sql.GetMysqlScope(sql.SystemVariableScope_Global),
+			Type:    types.NewSystemStringType(dsess.DoltBinlogReplicaHeartbeatTable),
+			Default: "",
+		},
 		&sql.MysqlSystemVariable{
 			Name:    "signingkey",
 			Dynamic: true,
@@ -494,6 +580,21 @@ func AddDoltSystemVariables() {
 			Type:    types.NewSystemBoolType("gpgsign"),
 			Default: int8(0),
 		},
+		&sql.MysqlSystemVariable{
+			// allowedsigners and signingkeys name files on the server, so they can only be set in the server's config
+			Name:    dsess.AllowedSigners,
+			Dynamic: false,
+			Scope:   sql.GetMysqlScope(sql.SystemVariableScope_PersistOnly),
+			Type:    types.NewSystemStringType(dsess.AllowedSigners),
+			Default: "",
+		},
+		&sql.MysqlSystemVariable{
+			Name:    dsess.SigningKeys,
+			Dynamic: false,
+			Scope:   sql.GetMysqlScope(sql.SystemVariableScope_PersistOnly),
+			Type:    types.NewSystemStringType(dsess.SigningKeys),
+			Default: "",
+		},
 	})
 	sql.SystemVariables.AddSystemVariables(DoltSystemVariables)
 }
@@ -0,0 +1,329 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+const (
+	event          = "branch_update"
+	signatureHdr   = "X-Dolt-Signature-256"
+	eventHdr       = "X-Dolt-Event"
+	deliveryHdr    = "X-Dolt-Delivery"
+	maxRetryDelay  = time.Minute
+	DefaultTimeout = 10 * time.Second
+)
+
+// Payload is the JSON body POSTed by a Sender for an update of the head of a branch.
+type Payload struct {
+	Database string `json:"database"`
+	Branch   string `json:"branch"`
+	// OldCommit is the previous head of the branch, or empty if the branch was created.
+	OldCommit string `json:"old_commit"`
+	// NewCommit is the new head of the branch, or empty if the branch was deleted.
+	NewCommit   string        `json:"new_commit"`
+	Author      string        `json:"author,omitempty"`
+	AuthorEmail string        `json:"author_email,omitempty"`
+	Message     string        `json:"message,omitempty"`
+	Timestamp   *time.Time    `json:"timestamp,omitempty"`
+	Tables      []TableChange `json:"tables"`
+}
+
+// TableChange is a table changed between the old and new heads of a branch, with the row counts of its
+// diff_stat.
+type TableChange struct {
+	Table        string `json:"table"`
+	RowsAdded    uint64 `json:"rows_added"`
+	RowsDeleted  uint64 `json:"rows_deleted"`
+	RowsModified uint64 `json:"rows_modified"`
+}
+
+// TableChangesFunc returns the tables changed between commits |from| and |to| of |ddb|.
+type TableChangesFunc func(ctx context.Context, ddb *doltdb.DoltDB, from, to hash.Hash) ([]TableChange, error)
+
+// Config configures a Sender.
+type Config struct {
+	// URL is the endpoint payloads are POSTed to.
+	URL string
+	// Secret, if not empty, is the key of the HMAC-SHA256 signature of each payload, sent in the X-Dolt-Signature-256
+	// header.
+	Secret string
+	// MaxRetries is the number of times the delivery of a payload is retried after failing.
+	MaxRetries int
+	// RetryInterval is the delay before the first retry. It doubles with each retry.
+	RetryInterval time.Duration
+	// Timeout is the timeout of each request.
+	Timeout time.Duration
+	// QueueSize is the number of branch updates which can be waiting to be delivered. Updates are dropped when the
+	// queue is full.
+	QueueSize int
+	// IncludeBranch, if not nil, returns whether updates of a branch are delivered.
+	IncludeBranch func(branch string) bool
+}
+
+type branchUpdate struct {
+	ddb     *doltdb.DoltDB
+	dbName  string
+	branch  string
+	oldHead hash.Hash
+	newHead hash.Hash
+}
+
+// Sender POSTs a Payload to an HTTP endpoint for every update of the head of a branch reported by its
+// Hooks. Payloads are delivered in order by a single background thread, see Run.
+type Sender struct {
+	cfg          Config
+	tableChanges TableChangesFunc
+	client       *http.Client
+	queue        chan branchUpdate
+	lgr          *logrus.Entry
+}
+
+// NewSender creates a Sender. |tableChanges| computes the changed tables of each payload.
+func NewSender(cfg Config, tableChanges TableChangesFunc, lgr *logrus.Entry) *Sender {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	return &Sender{
+		cfg:          cfg,
+		tableChanges: tableChanges,
+		client:       &http.Client{Timeout: cfg.Timeout},
+		queue:        make(chan branchUpdate, cfg.QueueSize),
+		lgr:          lgr,
+	}
+}
+
+// Hook returns a Hook reporting the branch updates of |ddb|, the database named |dbName|, to the sender.
+func (s *Sender) Hook(ctx context.Context, dbName string, ddb *doltdb.DoltDB) (*Hook, error) {
+	heads := make(map[string]hash.Hash)
+	branches, err := ddb.GetBranchesWithHashes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range branches {
+		heads[b.Ref.GetPath()] = b.Hash
+	}
+	return &Hook{sender: s, ddb: ddb, dbName: dbName, heads: heads}, nil
+}
+
+// Run delivers queued branch updates until |ctx| is canceled.
+func (s *Sender) Run(ctx context.Context) {
+	for {
+		select {
+		case u := <-s.queue:
+			s.deliver(ctx, u)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Sender) enqueue(u branchUpdate) {
+	select {
+	case s.queue <- u:
+	default:
+		s.lgr.Warnf("webhook: dropping update of branch %s of database %s to %s: too many updates are queued for %s", u.branch, u.dbName, u.newHead.String(), s.cfg.URL)
+	}
+}
+
+func (s *Sender) deliver(ctx context.Context, u branchUpdate) {
+	payload, err := s.payload(ctx, u)
+	if err != nil {
+		s.lgr.Errorf("webhook: error creating payload for update of branch %s of database %s: %v", u.branch, u.dbName, err)
+		return
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		s.lgr.Errorf("webhook: error encoding payload for update of branch %s of database %s: %v", u.branch, u.dbName, err)
+		return
+	}
+
+	deliveryID := uuid.NewString()
+	delay := s.cfg.RetryInterval
+	for attempt := 0; ; attempt++ {
+		err = s.post(ctx, deliveryID, body)
+		if err == nil {
+			return
+		}
+		if attempt >= s.cfg.MaxRetries {
+			s.lgr.Errorf("webhook: giving up on delivery of update of branch %s of database %s to %s after %d attempts: %v", u.branch, u.dbName, s.cfg.URL, attempt+1, err)
+			return
+		}
+		s.lgr.Warnf("webhook: error delivering update of branch %s of database %s to %s, retrying in %s: %v", u.branch, u.dbName, s.cfg.URL, delay, err)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+		delay *= 2
+		if delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+	}
+}
+
+func (s *Sender) payload(ctx context.Context, u branchUpdate) (*Payload, error) {
+	payload := &Payload{
+		Database: u.dbName,
+		Branch:   u.branch,
+		Tables:   []TableChange{},
+	}
+	if !u.oldHead.IsEmpty() {
+		payload.OldCommit = u.oldHead.String()
+	}
+	if u.newHead.IsEmpty() {
+		return payload, nil
+	}
+	payload.NewCommit = u.newHead.String()
+
+	optCmt, err := u.ddb.ReadCommit(ctx, u.newHead)
+	if err != nil {
+		return nil, err
+	}
+	cm, ok := optCmt.ToCommit()
+	if !ok {
+		return nil, doltdb.ErrGhostCommitEncountered
+	}
+	meta, err := cm.GetCommitMeta(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ts := meta.Time().UTC()
+	payload.Author, payload.AuthorEmail, payload.Message, payload.Timestamp = meta.Name, meta.Email, meta.Description, &ts
+
+	if !u.oldHead.IsEmpty() && s.tableChanges != nil {
+		tables, err := s.tableChanges(ctx, u.ddb, u.oldHead, u.newHead)
+		if err != nil {
+			return nil, err
+		}
+		if tables != nil {
+			payload.Tables = tables
+		}
+	}
+	return payload, nil
+}
+
+func (s *Sender) post(ctx context.Context, deliveryID string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(eventHdr, event)
+	req.Header.Set(deliveryHdr, deliveryID)
+	if s.cfg.Secret != "" {
+		req.Header.Set(signatureHdr, Signature(s.cfg.Secret, body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return nil
+}
+
+// Signature returns the value of the X-Dolt-Signature-256 header of a payload signed with |secret|.
+func Signature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Hook is a doltdb.CommitHook reporting the updates of the branches of a database to a Sender.
+type Hook struct {
+	sender *Sender
+	ddb    *doltdb.DoltDB
+	dbName string
+	out    io.Writer
+
+	mu    sync.Mutex
+	heads map[string]hash.Hash
+}
+
+var _ doltdb.CommitHook = (*Hook)(nil)
+
+// Execute implements doltdb.CommitHook, queues the update of the branch to be delivered
+func (wh *Hook) Execute(ctx context.Context, ds datas.Dataset, db datas.Database) (func(context.Context) error, error) {
+	if !ref.IsRef(ds.ID()) {
+		return nil, nil
+	}
+	r, err := ref.Parse(ds.ID())
+	if err != nil || r.GetType() != ref.BranchRefType {
+		return nil, nil
+	}
+	branch := r.GetPath()
+	if wh.sender.cfg.IncludeBranch != nil && !wh.sender.cfg.IncludeBranch(branch) {
+		return nil, nil
+	}
+
+	addr, _ := ds.MaybeHeadAddr()
+	wh.mu.Lock()
+	oldHead := wh.heads[branch]
+	if addr.IsEmpty() {
+		delete(wh.heads, branch)
+	} else {
+		wh.heads[branch] = addr
+	}
+	wh.mu.Unlock()
+	if oldHead == addr {
+		return nil, nil
+	}
+
+	wh.sender.enqueue(branchUpdate{ddb: wh.ddb, dbName: wh.dbName, branch: branch, oldHead: oldHead, newHead: addr})
+	return nil, nil
+}
+
+// HandleError implements doltdb.CommitHook
+func (wh *Hook) HandleError(ctx context.Context, err error) error {
+	if wh.out != nil {
+		wh.out.Write([]byte(err.Error()))
+	}
+	return nil
+}
+
+// SetLogger implements doltdb.CommitHook
+func (wh *Hook) SetLogger(ctx context.Context, wr io.Writer) error {
+	wh.out = wr
+	return nil
+}
+
+func (*Hook) ExecuteForWorkingSets() bool {
+	return false
+}
@@ -0,0 +1,160 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestHook(t *testing.T) {
+	ctx := context.Background()
+	ddb, err := doltdb.LoadDoltDB(ctx, types.Format_Default, doltdb.InMemDoltDB, filesys.EmptyInMemFS("/"))
+	require.NoError(t, err)
+	require.NoError(t, ddb.WriteEmptyRepo(ctx, "main", "Bill Billerson", "bigbillieb@fake.horse"))
+
+	type delivery struct {
+		header  http.Header
+		payload Payload
+		body    []byte
+	}
+	deliveries := make(chan delivery, 10)
+	failures := 1
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var p Payload
+		require.NoError(t, json.Unmarshal(body, &p))
+		deliveries <- delivery{header: r.Header, payload: p, body: body}
+	}))
+	defer srv.Close()
+
+	var changesFrom, changesTo hash.Hash
+	tableChanges := func(ctx context.Context, ddb *doltdb.DoltDB, from, to hash.Hash) ([]TableChange, error) {
+		changesFrom, changesTo = from, to
+		return []TableChange{{Table: "test", RowsAdded: 3}}, nil
+	}
+	sender := NewSender(Config{
+		URL:           srv.URL,
+		Secret:        "shh",
+		MaxRetries:    2,
+		RetryInterval: time.Millisecond,
+		QueueSize:     10,
+		IncludeBranch: func(branch string) bool { return branch != "ignored" },
+	}, tableChanges, logrus.NewEntry(logrus.StandardLogger()))
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go sender.Run(runCtx)
+
+	hook, err := sender.Hook(ctx, "mydb", ddb)
+	require.NoError(t, err)
+	ddb.SetCommitHooks(ctx, []doltdb.CommitHook{hook})
+
+	next := func() delivery {
+		select {
+		case d := <-deliveries:
+			return d
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for webhook delivery")
+			return delivery{}
+		}
+	}
+
+	branches, err := ddb.GetBranchesWithHashes(ctx)
+	require.NoError(t, err)
+	require.Len(t, branches, 1)
+	oldHead := branches[0].Hash
+
+	cs, _ := doltdb.NewCommitSpec("main")
+	optCmt, err := ddb.Resolve(ctx, cs, nil)
+	require.NoError(t, err)
+	commit, ok := optCmt.ToCommit()
+	require.True(t, ok)
+	root, err := commit.GetRootValue(ctx)
+	require.NoError(t, err)
+	_, valHash, err := ddb.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+
+	t.Run("branch update", func(t *testing.T) {
+		meta, err := datas.NewCommitMeta("Rob Robertson", "rob@fake.horse", "update main")
+		require.NoError(t, err)
+		newCommit, err := ddb.Commit(ctx, valHash, ref.NewBranchRef("main"), meta)
+		require.NoError(t, err)
+		newHead, err := newCommit.HashOf()
+		require.NoError(t, err)
+
+		d := next()
+		assert.Equal(t, "application/json", d.header.Get("Content-Type"))
+		assert.Equal(t, "branch_update", d.header.Get("X-Dolt-Event"))
+		assert.NotEmpty(t, d.header.Get("X-Dolt-Delivery"))
+		assert.Equal(t, Signature("shh", d.body), d.header.Get("X-Dolt-Signature-256"))
+
+		assert.Equal(t, "mydb", d.payload.Database)
+		assert.Equal(t, "main", d.payload.Branch)
+		assert.Equal(t, oldHead.String(), d.payload.OldCommit)
+		assert.Equal(t, newHead.String(), d.payload.NewCommit)
+		assert.Equal(t, "Rob Robertson", d.payload.Author)
+		assert.Equal(t, "rob@fake.horse", d.payload.AuthorEmail)
+		assert.Equal(t, "update main", d.payload.Message)
+		assert.NotNil(t, d.payload.Timestamp)
+		assert.Equal(t, []TableChange{{Table: "test", RowsAdded: 3}}, d.payload.Tables)
+		assert.Equal(t, oldHead, changesFrom)
+		assert.Equal(t, newHead, changesTo)
+		assert.Equal(t, 0, failures)
+	})
+
+	t.Run("branch create and ignored branch", func(t *testing.T) {
+		require.NoError(t, ddb.NewBranchAtCommit(ctx, ref.NewBranchRef("ignored"), commit, nil))
+		require.NoError(t, ddb.NewBranchAtCommit(ctx, ref.NewBranchRef("feature"), commit, nil))
+
+		d := next()
+		assert.Equal(t, "feature", d.payload.Branch)
+		assert.Equal(t, "", d.payload.OldCommit)
+		assert.Equal(t, oldHead.String(), d.payload.NewCommit)
+		assert.Equal(t, []TableChange{}, d.payload.Tables)
+	})
+
+	t.Run("branch delete", func(t *testing.T) {
+		require.NoError(t, ddb.DeleteBranch(ctx, ref.NewBranchRef("feature"), nil))
+
+		d := next()
+		assert.Equal(t, "feature", d.payload.Branch)
+		assert.Equal(t, oldHead.String(), d.payload.OldCommit)
+		assert.Equal(t, "", d.payload.NewCommit)
+		assert.Nil(t, d.payload.Timestamp)
+	})
+}
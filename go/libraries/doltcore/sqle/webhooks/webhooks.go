@@ -0,0 +1,172 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+const senderThreadName = "dolt_webhook_sender"
+
+// Webhook is an HTTP endpoint notified of the branch updates of some of the databases of a server.
+type Webhook struct {
+	Config
+	// Databases are the names of the databases whose branch updates are delivered. If empty, updates of every
+	// database are delivered.
+	Databases []string
+}
+
+// Start installs a Hook for each of |webhooks| on the databases of |pro|, including databases created
+// later, and starts a background thread delivering the branch updates of each webhook.
+func Start(ctx context.Context, bThreads *sql.BackgroundThreads, pro *sqle.DoltDatabaseProvider, webhooks []Webhook, lgr *logrus.Entry) error {
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	senders := make([]*Sender, len(webhooks))
+	for i, wh := range webhooks {
+		senders[i] = NewSender(wh.Config, TableChanges, lgr.WithField("webhook", wh.URL))
+	}
+
+	install := func(ctx context.Context, name string, ddb *doltdb.DoltDB) error {
+		for i, wh := range webhooks {
+			if !includesDatabase(wh.Databases, name) {
+				continue
+			}
+			hook, err := senders[i].Hook(ctx, name, ddb)
+			if err != nil {
+				return err
+			}
+			ddb.PrependCommitHook(ctx, hook)
+		}
+		return nil
+	}
+
+	for _, db := range pro.DoltDatabases() {
+		if err := install(ctx, db.Name(), db.DbData().Ddb); err != nil {
+			return err
+		}
+	}
+	pro.AddInitDatabaseHook(func(ctx *sql.Context, _ *sqle.DoltDatabaseProvider, name string, _ *env.DoltEnv, db dsess.SqlDatabase) error {
+		return install(ctx, name, db.DbData().Ddb)
+	})
+
+	for i, sender := range senders {
+		if err := bThreads.Add(fmt.Sprintf("%s_%d", senderThreadName, i), sender.Run); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func includesDatabase(databases []string, name string) bool {
+	if len(databases) == 0 {
+		return true
+	}
+	for _, db := range databases {
+		if strings.EqualFold(db, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// TableChanges is a TableChangesFunc returning the tables changed between two commits, with the row
+// counts of their diff_stat. The row counts of tables whose primary key changed are not computed.
+func TableChanges(ctx context.Context, ddb *doltdb.DoltDB, from, to hash.Hash) ([]TableChange, error) {
+	fromRoot, err := commitRoot(ctx, ddb, from)
+	if err != nil {
+		return nil, err
+	}
+	toRoot, err := commitRoot(ctx, ddb, to)
+	if err != nil {
+		return nil, err
+	}
+
+	deltas, err := diff.GetTableDeltas(ctx, fromRoot, toRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]TableChange, 0, len(deltas))
+	for _, td := range deltas {
+		changed, err := td.HasChanges()
+		if err != nil {
+			return nil, err
+		}
+		if !changed {
+			continue
+		}
+
+		change := TableChange{Table: td.CurName()}
+		stat, err := diffStat(ctx, td)
+		if err != nil && !errors.Is(err, diff.ErrPrimaryKeySetChanged) {
+			return nil, err
+		}
+		change.RowsAdded, change.RowsDeleted, change.RowsModified = stat.Adds, stat.Removes, stat.Changes
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+func commitRoot(ctx context.Context, ddb *doltdb.DoltDB, h hash.Hash) (doltdb.RootValue, error) {
+	optCmt, err := ddb.ReadCommit(ctx, h)
+	if err != nil {
+		return nil, err
+	}
+	cm, ok := optCmt.ToCommit()
+	if !ok {
+		return nil, doltdb.ErrGhostCommitEncountered
+	}
+	return cm.GetRootValue(ctx)
+}
+
+func diffStat(ctx context.Context, td diff.TableDelta) (diff.DiffStatProgress, error) {
+	ch := make(chan diff.DiffStatProgress)
+	grp, ctx := errgroup.WithContext(ctx)
+	grp.Go(func() error {
+		defer close(ch)
+		return diff.StatForTableDelta(ctx, ch, td)
+	})
+
+	acc := diff.DiffStatProgress{}
+	grp.Go(func() error {
+		for p := range ch {
+			acc.Adds += p.Adds
+			acc.Removes += p.Removes
+			acc.Changes += p.Changes
+		}
+		return nil
+	})
+
+	if err := grp.Wait(); err != nil {
+		return diff.DiffStatProgress{}, err
+	}
+	return acc, nil
+}
@@ -0,0 +1,317 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrow
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/ipc"
+	"github.com/dolthub/go-mysql-server/sql"
+	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/shopspring/decimal"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+// fileMagic is the magic string at the start of an Arrow IPC file. Arrow IPC streams don't have it.
+const fileMagic = "ARROW1"
+
+// ArrowReader implements TableReader. It reads the record batches of an Arrow IPC file or stream and returns their
+// rows. The schema of the rows is read from the file.
+type ArrowReader struct {
+	closer  io.Closer
+	records recordReader
+	sch     schema.Schema
+	decode  []func(arr arrow.Array, i int) interface{}
+	rec     arrow.Record
+	recRow  int
+}
+
+var _ table.SqlTableReader = (*ArrowReader)(nil)
+
+// recordReader reads the record batches of an Arrow IPC file or stream.
+type recordReader interface {
+	Schema() *arrow.Schema
+	// next returns the next record batch, or io.EOF when there are none left.
+	next() (arrow.Record, error)
+	Release()
+}
+
+type fileRecordReader struct {
+	*ipc.FileReader
+	idx int
+}
+
+func (r *fileRecordReader) next() (arrow.Record, error) {
+	if r.idx >= r.NumRecords() {
+		return nil, io.EOF
+	}
+	rec, err := r.Record(r.idx)
+	r.idx++
+	return rec, err
+}
+
+func (r *fileRecordReader) Release() {
+	_ = r.Close()
+}
+
+type streamRecordReader struct {
+	*ipc.Reader
+}
+
+func (r streamRecordReader) next() (arrow.Record, error) {
+	if r.Next() {
+		return r.Record(), nil
+	} else if err := r.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// OpenArrowReader opens a reader at a given path within the filesystem.
+func OpenArrowReader(path string, fs filesys.ReadableFS) (*ArrowReader, error) {
+	r, err := fs.OpenForRead(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewArrowReader(r)
+}
+
+// NewArrowReader creates an ArrowReader from the Arrow IPC file or stream read from |r|.
+func NewArrowReader(r io.ReadCloser) (*ArrowReader, error) {
+	records, err := newRecordReader(r)
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+
+	fields := records.Schema().Fields()
+	cols := make([]schema.Column, len(fields))
+	decode := make([]func(arr arrow.Array, i int) interface{}, len(fields))
+	for i, field := range fields {
+		var ti typeinfo.TypeInfo
+		ti, decode[i], err = mapArrowTypeToTypeInfo(field.Type)
+		if err != nil {
+			records.Release()
+			r.Close()
+			return nil, fmt.Errorf("cannot read field %s: %w", field.Name, err)
+		}
+
+		var constraints []schema.ColConstraint
+		if !field.Nullable {
+			constraints = append(constraints, schema.NotNullConstraint{})
+		}
+		cols[i], err = schema.NewColumnWithTypeInfo(field.Name, uint64(i), ti, false, "", false, "", constraints...)
+		if err != nil {
+			records.Release()
+			r.Close()
+			return nil, err
+		}
+	}
+
+	sch, err := schema.SchemaFromCols(schema.NewColCollection(cols...))
+	if err != nil {
+		records.Release()
+		r.Close()
+		return nil, err
+	}
+
+	return &ArrowReader{closer: r, records: records, sch: sch, decode: decode}, nil
+}
+
+// newRecordReader returns a recordReader for |r|, which is read as an Arrow IPC file if it starts with the file's
+// magic string, and as an Arrow IPC stream otherwise.
+func newRecordReader(r io.Reader) (recordReader, error) {
+	if ras, ok := r.(ipc.ReadAtSeeker); ok {
+		magic := make([]byte, len(fileMagic))
+		n, _ := ras.ReadAt(magic, 0)
+		if n == len(fileMagic) && string(magic) == fileMagic {
+			fr, err := ipc.NewFileReader(ras)
+			if err != nil {
+				return nil, err
+			}
+			return &fileRecordReader{FileReader: fr}, nil
+		}
+	} else {
+		br := bufio.NewReader(r)
+		magic, _ := br.Peek(len(fileMagic))
+		if string(magic) == fileMagic {
+			// the file format can only be read with random access
+			data, err := io.ReadAll(br)
+			if err != nil {
+				return nil, err
+			}
+			fr, err := ipc.NewFileReader(bytes.NewReader(data))
+			if err != nil {
+				return nil, err
+			}
+			return &fileRecordReader{FileReader: fr}, nil
+		}
+		r = br
+	}
+
+	sr, err := ipc.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return streamRecordReader{Reader: sr}, nil
+}
+
+func (ar *ArrowReader) ReadRow(ctx context.Context) (row.Row, error) {
+	panic("deprecated")
+}
+
+func (ar *ArrowReader) ReadSqlRow(ctx context.Context) (sql.Row, error) {
+	for ar.rec == nil || ar.recRow >= int(ar.rec.NumRows()) {
+		rec, err := ar.records.next()
+		if err != nil {
+			return nil, err
+		}
+		ar.rec, ar.recRow = rec, 0
+	}
+
+	r := make(sql.Row, len(ar.decode))
+	for i, decode := range ar.decode {
+		col := ar.rec.Column(i)
+		if col.IsNull(ar.recRow) {
+			continue
+		}
+		r[i] = decode(col, ar.recRow)
+	}
+	ar.recRow++
+	return r, nil
+}
+
+// HasTypedSchema implements actions.TypedTableReader. The types of the columns are those of the fields of the file.
+func (ar *ArrowReader) HasTypedSchema() bool {
+	return true
+}
+
+func (ar *ArrowReader) GetSchema() schema.Schema {
+	return ar.sch
+}
+
+// Close should release resources being held
+func (ar *ArrowReader) Close(ctx context.Context) error {
+	ar.records.Release()
+	return ar.closer.Close()
+}
+
+// mapArrowTypeToTypeInfo maps |t|, the type of a field of an Arrow schema, to the TypeInfo of a column, and returns a
+// function reading the values of the column from an array of the field.
+func mapArrowTypeToTypeInfo(t arrow.DataType) (typeinfo.TypeInfo, func(arr arrow.Array, i int) interface{}, error) {
+	switch t.ID() {
+	case arrow.NULL:
+		return typeinfo.StringDefaultType, func(arr arrow.Array, i int) interface{} { return nil }, nil
+	case arrow.BOOL:
+		return typeinfo.BoolType, func(arr arrow.Array, i int) interface{} {
+			if arr.(*array.Boolean).Value(i) {
+				return int8(1)
+			}
+			return int8(0)
+		}, nil
+	case arrow.INT8:
+		return typeinfo.Int8Type, func(arr arrow.Array, i int) interface{} { return arr.(*array.Int8).Value(i) }, nil
+	case arrow.INT16:
+		return typeinfo.Int16Type, func(arr arrow.Array, i int) interface{} { return arr.(*array.Int16).Value(i) }, nil
+	case arrow.INT32:
+		return typeinfo.Int32Type, func(arr arrow.Array, i int) interface{} { return arr.(*array.Int32).Value(i) }, nil
+	case arrow.INT64:
+		return typeinfo.Int64Type, func(arr arrow.Array, i int) interface{} { return arr.(*array.Int64).Value(i) }, nil
+	case arrow.UINT8:
+		return typeinfo.Uint8Type, func(arr arrow.Array, i int) interface{} { return arr.(*array.Uint8).Value(i) }, nil
+	case arrow.UINT16:
+		return typeinfo.Uint16Type, func(arr arrow.Array, i int) interface{} { return arr.(*array.Uint16).Value(i) }, nil
+	case arrow.UINT32:
+		return typeinfo.Uint32Type, func(arr arrow.Array, i int) interface{} { return arr.(*array.Uint32).Value(i) }, nil
+	case arrow.UINT64:
+		return typeinfo.Uint64Type, func(arr arrow.Array, i int) interface{} { return arr.(*array.Uint64).Value(i) }, nil
+	case arrow.FLOAT32:
+		return typeinfo.Float32Type, func(arr arrow.Array, i int) interface{} { return arr.(*array.Float32).Value(i) }, nil
+	case arrow.FLOAT64:
+		return typeinfo.Float64Type, func(arr arrow.Array, i int) interface{} { return arr.(*array.Float64).Value(i) }, nil
+	case arrow.STRING:
+		return typeinfo.StringDefaultType, func(arr arrow.Array, i int) interface{} { return arr.(*array.String).Value(i) }, nil
+	case arrow.BINARY:
+		return typeinfo.VarbinaryDefaultType, func(arr arrow.Array, i int) interface{} {
+			return append([]byte(nil), arr.(*array.Binary).Value(i)...)
+		}, nil
+	case arrow.FIXED_SIZE_BINARY:
+		return typeinfo.VarbinaryDefaultType, func(arr arrow.Array, i int) interface{} {
+			return append([]byte(nil), arr.(*array.FixedSizeBinary).Value(i)...)
+		}, nil
+	case arrow.DATE32:
+		return typeinfo.DateType, func(arr arrow.Array, i int) interface{} {
+			return time.Unix(0, 0).UTC().AddDate(0, 0, int(arr.(*array.Date32).Value(i)))
+		}, nil
+	case arrow.DATE64:
+		return typeinfo.DateType, func(arr arrow.Array, i int) interface{} {
+			return time.UnixMilli(int64(arr.(*array.Date64).Value(i))).UTC()
+		}, nil
+	case arrow.TIMESTAMP:
+		unit := t.(*arrow.TimestampType).Unit
+		return typeinfo.DatetimeType, func(arr arrow.Array, i int) interface{} {
+			return fromUnitsSinceEpoch(int64(arr.(*array.Timestamp).Value(i)), unit)
+		}, nil
+	case arrow.TIME32:
+		unit := t.(*arrow.Time32Type).Unit
+		return typeinfo.TimeType, func(arr arrow.Array, i int) interface{} {
+			return toTimespan(int64(arr.(*array.Time32).Value(i)), unit)
+		}, nil
+	case arrow.TIME64:
+		unit := t.(*arrow.Time64Type).Unit
+		return typeinfo.TimeType, func(arr arrow.Array, i int) interface{} {
+			return toTimespan(int64(arr.(*array.Time64).Value(i)), unit)
+		}, nil
+	case arrow.DURATION:
+		unit := t.(*arrow.DurationType).Unit
+		return typeinfo.TimeType, func(arr arrow.Array, i int) interface{} {
+			return toTimespan(int64(arr.(*array.Duration).Value(i)), unit)
+		}, nil
+	case arrow.DECIMAL128:
+		dt := t.(*arrow.Decimal128Type)
+		decimalType, err := gmstypes.CreateDecimalType(uint8(dt.Precision), uint8(dt.Scale))
+		if err != nil {
+			return nil, nil, err
+		}
+		ti, err := typeinfo.FromSqlType(decimalType)
+		return ti, func(arr arrow.Array, i int) interface{} {
+			return decimal.NewFromBigInt(arr.(*array.Decimal128).Value(i).BigInt(), -dt.Scale).StringFixed(dt.Scale)
+		}, err
+	default:
+		return nil, nil, fmt.Errorf("unsupported arrow type: %s", t)
+	}
+}
+
+func fromUnitsSinceEpoch(v int64, unit arrow.TimeUnit) time.Time {
+	d := unit.Multiplier()
+	return time.Unix(0, 0).UTC().Add(time.Duration(v/int64(time.Second/d))*time.Second + time.Duration(v%int64(time.Second/d))*d)
+}
+
+func toTimespan(v int64, unit arrow.TimeUnit) gmstypes.Timespan {
+	return gmstypes.Timespan((time.Duration(v) * unit.Multiplier()).Microseconds())
+}
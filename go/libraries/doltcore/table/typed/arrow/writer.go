@@ -0,0 +1,387 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrow
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/decimal128"
+	"github.com/apache/arrow/go/v13/arrow/ipc"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/dolthub/vitess/go/vt/proto/query"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+)
+
+// rowsPerBatch is the number of rows buffered by an ArrowRowWriter before they're written as a record batch.
+const rowsPerBatch = 1024
+
+// maxDecimal128Precision is the largest precision of the decimals which can be written as Arrow decimals.
+const maxDecimal128Precision = 38
+
+// ArrowRowWriter writes rows as record batches of an Arrow IPC file, or of an Arrow IPC stream when its destination
+// isn't seekable.
+type ArrowRowWriter struct {
+	ipcWriter recordWriter
+	builder   *array.RecordBuilder
+	sch       sql.Schema
+	appenders []appendFunc
+	buffered  int
+	closer    io.Closer
+}
+
+var _ table.SqlRowWriter = (*ArrowRowWriter)(nil)
+
+// recordWriter writes record batches to an Arrow IPC file or stream.
+type recordWriter interface {
+	Write(rec arrow.Record) error
+	Close() error
+}
+
+// appendFunc appends |v|, a non-null value of |sqlType|, to |b|.
+type appendFunc func(b array.Builder, sqlType sql.Type, v interface{}) error
+
+// NewArrowRowWriter creates a new ArrowRowWriter instance for the specified schema and writing to the specified
+// WriteCloser. The Arrow IPC file format is written if |w| is seekable, the stream format otherwise.
+func NewArrowRowWriter(outSch sql.Schema, w io.WriteCloser) (*ArrowRowWriter, error) {
+	fields := make([]arrow.Field, len(outSch))
+	appenders := make([]appendFunc, len(outSch))
+	for i, col := range outSch {
+		dataType, appender, err := mapTypeToArrowType(col.Type)
+		if err != nil {
+			return nil, err
+		}
+		fields[i] = arrow.Field{Name: col.Name, Type: dataType, Nullable: col.Nullable}
+		appenders[i] = appender
+	}
+	arrowSch := arrow.NewSchema(fields, nil)
+
+	var ipcWriter recordWriter
+	if ws, ok := w.(io.WriteSeeker); ok && isSeekable(ws) {
+		fw, err := ipc.NewFileWriter(ws, ipc.WithSchema(arrowSch))
+		if err != nil {
+			return nil, err
+		}
+		ipcWriter = fw
+	} else {
+		ipcWriter = ipc.NewWriter(w, ipc.WithSchema(arrowSch))
+	}
+
+	return &ArrowRowWriter{
+		ipcWriter: ipcWriter,
+		builder:   array.NewRecordBuilder(memory.NewGoAllocator(), arrowSch),
+		sch:       outSch,
+		appenders: appenders,
+		closer:    w,
+	}, nil
+}
+
+// NewArrowRowWriterForSchema creates a new ArrowRowWriter instance for the specified dolt schema and writing to the
+// specified WriteCloser.
+func NewArrowRowWriterForSchema(outSch schema.Schema, w io.WriteCloser) (*ArrowRowWriter, error) {
+	sqlSch, err := sqlutil.FromDoltSchema("", "", outSch)
+	if err != nil {
+		return nil, err
+	}
+	return NewArrowRowWriter(sqlSch.Schema, w)
+}
+
+// isSeekable returns whether |ws| supports seeking. Files such as pipes and terminals implement io.Seeker but fail
+// to seek.
+func isSeekable(ws io.WriteSeeker) bool {
+	_, err := ws.Seek(0, io.SeekCurrent)
+	return err == nil
+}
+
+func (w *ArrowRowWriter) WriteSqlRow(_ context.Context, r sql.Row) error {
+	for i, appender := range w.appenders {
+		b := w.builder.Field(i)
+		if r[i] == nil {
+			b.AppendNull()
+			continue
+		}
+
+		if err := appender(b, w.sch[i].Type, r[i]); err != nil {
+			return fmt.Errorf("cannot write value of column %s: %w", w.sch[i].Name, err)
+		}
+	}
+
+	w.buffered++
+	if w.buffered >= rowsPerBatch {
+		return w.flush()
+	}
+	return nil
+}
+
+func (w *ArrowRowWriter) flush() error {
+	if w.buffered == 0 {
+		return nil
+	}
+	rec := w.builder.NewRecord()
+	defer rec.Release()
+	w.buffered = 0
+	return w.ipcWriter.Write(rec)
+}
+
+// Close should flush all writes, release resources being held
+func (w *ArrowRowWriter) Close(_ context.Context) error {
+	defer w.builder.Release()
+
+	err := w.flush()
+	if err != nil {
+		return err
+	}
+
+	err = w.ipcWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	if w.closer != nil {
+		return w.closer.Close()
+	}
+	return nil
+}
+
+// mapTypeToArrowType maps |t| from a sql.Type to an Arrow data type, and returns a function appending its values to a
+// builder of that type.
+func mapTypeToArrowType(t sql.Type) (arrow.DataType, appendFunc, error) {
+	switch t.Type() {
+	case query.Type_INT8:
+		return arrow.PrimitiveTypes.Int8, appendInt8, nil
+	case query.Type_INT16, query.Type_YEAR:
+		return arrow.PrimitiveTypes.Int16, appendInt16, nil
+	case query.Type_INT24, query.Type_INT32:
+		return arrow.PrimitiveTypes.Int32, appendInt32, nil
+	case query.Type_INT64:
+		return arrow.PrimitiveTypes.Int64, appendInt64, nil
+	case query.Type_UINT8:
+		return arrow.PrimitiveTypes.Uint8, appendUint8, nil
+	case query.Type_UINT16:
+		return arrow.PrimitiveTypes.Uint16, appendUint16, nil
+	case query.Type_UINT24, query.Type_UINT32:
+		return arrow.PrimitiveTypes.Uint32, appendUint32, nil
+	case query.Type_UINT64, query.Type_BIT:
+		return arrow.PrimitiveTypes.Uint64, appendUint64, nil
+	case query.Type_FLOAT32:
+		return arrow.PrimitiveTypes.Float32, appendFloat32, nil
+	case query.Type_FLOAT64:
+		return arrow.PrimitiveTypes.Float64, appendFloat64, nil
+	case query.Type_DECIMAL:
+		dt := t.(types.DecimalType_)
+		if dt.Precision() > maxDecimal128Precision {
+			// too large for a decimal128, written as text
+			return arrow.BinaryTypes.String, appendString, nil
+		}
+		return &arrow.Decimal128Type{Precision: int32(dt.Precision()), Scale: int32(dt.Scale())}, appendDecimal, nil
+	case query.Type_DATE:
+		return arrow.FixedWidthTypes.Date32, appendDate, nil
+	case query.Type_DATETIME, query.Type_TIMESTAMP:
+		return &arrow.TimestampType{Unit: arrow.Microsecond}, appendTimestamp, nil
+	case query.Type_TIME:
+		return arrow.FixedWidthTypes.Time64us, appendTime, nil
+	case query.Type_BLOB, query.Type_BINARY, query.Type_VARBINARY:
+		return arrow.BinaryTypes.Binary, appendBytes, nil
+	case query.Type_ENUM, query.Type_SET, query.Type_TUPLE, query.Type_JSON, query.Type_CHAR, query.Type_VARCHAR,
+		query.Type_TEXT, query.Type_GEOMETRY:
+		return arrow.BinaryTypes.String, appendString, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported type: %v", t.Type())
+	}
+}
+
+func convertInRange(t sql.Type, v interface{}) (interface{}, error) {
+	converted, inRange, err := t.Convert(v)
+	if err != nil {
+		return nil, err
+	} else if inRange != sql.InRange {
+		return nil, sql.ErrValueOutOfRange.New(v, t)
+	}
+	return converted, nil
+}
+
+func appendInt8(b array.Builder, _ sql.Type, v interface{}) error {
+	i, err := convertInRange(types.Int8, v)
+	if err != nil {
+		return err
+	}
+	b.(*array.Int8Builder).Append(i.(int8))
+	return nil
+}
+
+func appendInt16(b array.Builder, _ sql.Type, v interface{}) error {
+	i, err := convertInRange(types.Int16, v)
+	if err != nil {
+		return err
+	}
+	b.(*array.Int16Builder).Append(i.(int16))
+	return nil
+}
+
+func appendInt32(b array.Builder, _ sql.Type, v interface{}) error {
+	i, err := convertInRange(types.Int32, v)
+	if err != nil {
+		return err
+	}
+	b.(*array.Int32Builder).Append(i.(int32))
+	return nil
+}
+
+func appendInt64(b array.Builder, _ sql.Type, v interface{}) error {
+	i, err := convertInRange(types.Int64, v)
+	if err != nil {
+		return err
+	}
+	b.(*array.Int64Builder).Append(i.(int64))
+	return nil
+}
+
+func appendUint8(b array.Builder, _ sql.Type, v interface{}) error {
+	i, err := convertInRange(types.Uint8, v)
+	if err != nil {
+		return err
+	}
+	b.(*array.Uint8Builder).Append(i.(uint8))
+	return nil
+}
+
+func appendUint16(b array.Builder, _ sql.Type, v interface{}) error {
+	i, err := convertInRange(types.Uint16, v)
+	if err != nil {
+		return err
+	}
+	b.(*array.Uint16Builder).Append(i.(uint16))
+	return nil
+}
+
+func appendUint32(b array.Builder, _ sql.Type, v interface{}) error {
+	i, err := convertInRange(types.Uint32, v)
+	if err != nil {
+		return err
+	}
+	b.(*array.Uint32Builder).Append(i.(uint32))
+	return nil
+}
+
+func appendUint64(b array.Builder, _ sql.Type, v interface{}) error {
+	i, err := convertInRange(types.Uint64, v)
+	if err != nil {
+		return err
+	}
+	b.(*array.Uint64Builder).Append(i.(uint64))
+	return nil
+}
+
+func appendFloat32(b array.Builder, _ sql.Type, v interface{}) error {
+	f, _, err := types.Float32.Convert(v)
+	if err != nil {
+		return err
+	}
+	b.(*array.Float32Builder).Append(f.(float32))
+	return nil
+}
+
+func appendFloat64(b array.Builder, _ sql.Type, v interface{}) error {
+	f, _, err := types.Float64.Convert(v)
+	if err != nil {
+		return err
+	}
+	b.(*array.Float64Builder).Append(f.(float64))
+	return nil
+}
+
+func appendDecimal(b array.Builder, t sql.Type, v interface{}) error {
+	dt := t.(types.DecimalType_)
+	d, err := dt.ConvertToNullDecimal(v)
+	if err != nil {
+		return err
+	}
+	b.(*array.Decimal128Builder).Append(decimal128.FromBigInt(d.Decimal.Shift(int32(dt.Scale())).BigInt()))
+	return nil
+}
+
+func appendDate(b array.Builder, t sql.Type, v interface{}) error {
+	tm, err := convertToTime(t, v)
+	if err != nil {
+		return err
+	}
+	days := tm.Unix() / 86400
+	if tm.Unix()%86400 < 0 {
+		days--
+	}
+	b.(*array.Date32Builder).Append(arrow.Date32(days))
+	return nil
+}
+
+func appendTimestamp(b array.Builder, t sql.Type, v interface{}) error {
+	tm, err := convertToTime(t, v)
+	if err != nil {
+		return err
+	}
+	b.(*array.TimestampBuilder).Append(arrow.Timestamp(tm.UnixMicro()))
+	return nil
+}
+
+func appendTime(b array.Builder, _ sql.Type, v interface{}) error {
+	d, err := types.Time.ConvertToTimeDuration(v)
+	if err != nil {
+		return err
+	}
+	b.(*array.Time64Builder).Append(arrow.Time64(d.Microseconds()))
+	return nil
+}
+
+func appendBytes(b array.Builder, t sql.Type, v interface{}) error {
+	switch bs := v.(type) {
+	case []byte:
+		b.(*array.BinaryBuilder).Append(bs)
+		return nil
+	case string:
+		b.(*array.BinaryBuilder).Append([]byte(bs))
+		return nil
+	}
+	s, err := sqlutil.SqlColToStr(t, v)
+	if err != nil {
+		return err
+	}
+	b.(*array.BinaryBuilder).Append([]byte(s))
+	return nil
+}
+
+func appendString(b array.Builder, t sql.Type, v interface{}) error {
+	s, err := sqlutil.SqlColToStr(t, v)
+	if err != nil {
+		return err
+	}
+	b.(*array.StringBuilder).Append(s)
+	return nil
+}
+
+func convertToTime(t sql.Type, v interface{}) (time.Time, error) {
+	tm, _, err := t.Convert(v)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return tm.(time.Time).UTC(), nil
+}
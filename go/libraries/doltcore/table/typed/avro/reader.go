@@ -0,0 +1,273 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package avro
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/linkedin/goavro/v2"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+// AvroReader implements TableReader. It reads the records of an Avro object container file and returns them as rows.
+// The schema of the rows is read from the file.
+type AvroReader struct {
+	closer    io.Closer
+	ocfReader *goavro.OCFReader
+	sch       schema.Schema
+	fields    []fieldReader
+}
+
+var _ table.SqlTableReader = (*AvroReader)(nil)
+
+// fieldReader converts the values of an Avro record field to the values of a column.
+type fieldReader struct {
+	name   string
+	decode func(v interface{}) (interface{}, error)
+}
+
+// OpenAvroReader opens a reader at a given path within the filesystem.
+func OpenAvroReader(path string, fs filesys.ReadableFS) (*AvroReader, error) {
+	r, err := fs.OpenForRead(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewAvroReader(r)
+}
+
+// NewAvroReader creates an AvroReader from the Avro object container file read from |r|.
+func NewAvroReader(r io.ReadCloser) (*AvroReader, error) {
+	ocfReader, err := goavro.NewOCFReader(r)
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+
+	var recordSchema struct {
+		Type   interface{}              `json:"type"`
+		Fields []map[string]interface{} `json:"fields"`
+	}
+	err = json.Unmarshal([]byte(ocfReader.Codec().Schema()), &recordSchema)
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+	if recordSchema.Type != "record" {
+		r.Close()
+		return nil, fmt.Errorf("the schema of an avro file must be a record, found %v", recordSchema.Type)
+	}
+
+	cols := make([]schema.Column, len(recordSchema.Fields))
+	fields := make([]fieldReader, len(recordSchema.Fields))
+	for i, field := range recordSchema.Fields {
+		name, _ := field["name"].(string)
+		ti, nullable, decode, err := mapAvroTypeToTypeInfo(field["type"])
+		if err != nil {
+			r.Close()
+			return nil, fmt.Errorf("cannot read field %s: %w", name, err)
+		}
+
+		var constraints []schema.ColConstraint
+		if !nullable {
+			constraints = append(constraints, schema.NotNullConstraint{})
+		}
+		cols[i], err = schema.NewColumnWithTypeInfo(name, uint64(i), ti, false, "", false, "", constraints...)
+		if err != nil {
+			r.Close()
+			return nil, err
+		}
+		fields[i] = fieldReader{name: name, decode: decode}
+	}
+
+	sch, err := schema.SchemaFromCols(schema.NewColCollection(cols...))
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+
+	return &AvroReader{closer: r, ocfReader: ocfReader, sch: sch, fields: fields}, nil
+}
+
+func (ar *AvroReader) ReadRow(ctx context.Context) (row.Row, error) {
+	panic("deprecated")
+}
+
+func (ar *AvroReader) ReadSqlRow(ctx context.Context) (sql.Row, error) {
+	if !ar.ocfReader.Scan() {
+		if err := ar.ocfReader.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+
+	datum, err := ar.ocfReader.Read()
+	if err != nil {
+		return nil, err
+	}
+	record, ok := datum.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected avro record: %v", datum)
+	}
+
+	r := make(sql.Row, len(ar.fields))
+	for i, field := range ar.fields {
+		v := record[field.name]
+		if v == nil {
+			continue
+		}
+		r[i], err = field.decode(v)
+		if err != nil {
+			return r, table.NewBadRow(nil, fmt.Sprintf("cannot read field %s: %s", field.name, err.Error()))
+		}
+	}
+	return r, nil
+}
+
+// HasTypedSchema implements actions.TypedTableReader. The types of the columns are those of the fields of the file.
+func (ar *AvroReader) HasTypedSchema() bool {
+	return true
+}
+
+func (ar *AvroReader) GetSchema() schema.Schema {
+	return ar.sch
+}
+
+// Close should release resources being held
+func (ar *AvroReader) Close(ctx context.Context) error {
+	return ar.closer.Close()
+}
+
+// mapAvroTypeToTypeInfo maps |avroType|, the type of a field of an Avro record, to the TypeInfo of a column. Returns
+// whether the field is nullable, and a function converting the native values of the field to the values of the
+// column.
+func mapAvroTypeToTypeInfo(avroType interface{}) (typeinfo.TypeInfo, bool, func(interface{}) (interface{}, error), error) {
+	if union, ok := avroType.([]interface{}); ok {
+		var members []interface{}
+		nullable := false
+		for _, member := range union {
+			if member == "null" {
+				nullable = true
+			} else {
+				members = append(members, member)
+			}
+		}
+		if len(members) == 1 {
+			ti, _, decode, err := mapAvroTypeToTypeInfo(members[0])
+			return ti, nullable, unwrapUnion(decode), err
+		}
+		// a union of several types doesn't map to any column type, its values are kept as JSON
+		return typeinfo.JSONType, nullable, decodeJSON, nil
+	}
+
+	typeName, logicalType := avroType, interface{}(nil)
+	if m, ok := avroType.(map[string]interface{}); ok {
+		typeName, logicalType = m["type"], m["logicalType"]
+		if logicalType == "decimal" {
+			precision, _ := m["precision"].(float64)
+			scale, _ := m["scale"].(float64)
+			decimalType, err := gmstypes.CreateDecimalType(uint8(precision), uint8(scale))
+			if err != nil {
+				return nil, false, nil, err
+			}
+			ti, err := typeinfo.FromSqlType(decimalType)
+			return ti, false, decodeDecimal(int(scale)), err
+		}
+	}
+
+	switch logicalType {
+	case "date":
+		return typeinfo.DateType, false, decodeIdentity, nil
+	case "time-millis", "time-micros":
+		return typeinfo.TimeType, false, decodeTime, nil
+	case "timestamp-millis", "timestamp-micros":
+		return typeinfo.DatetimeType, false, decodeIdentity, nil
+	case "uuid":
+		return typeinfo.UuidType, false, decodeIdentity, nil
+	}
+
+	switch typeName {
+	case "null", "string", "enum":
+		return typeinfo.StringDefaultType, typeName == "null", decodeIdentity, nil
+	case "boolean":
+		return typeinfo.BoolType, false, decodeBool, nil
+	case "int":
+		return typeinfo.Int32Type, false, decodeIdentity, nil
+	case "long":
+		return typeinfo.Int64Type, false, decodeIdentity, nil
+	case "float":
+		return typeinfo.Float32Type, false, decodeIdentity, nil
+	case "double":
+		return typeinfo.Float64Type, false, decodeIdentity, nil
+	case "bytes", "fixed":
+		return typeinfo.VarbinaryDefaultType, false, decodeIdentity, nil
+	default:
+		// records, arrays, maps and references to named types are kept as JSON
+		return typeinfo.JSONType, false, decodeJSON, nil
+	}
+}
+
+// unwrapUnion returns a function decoding the values of a union with a single non-null member with |decode|.
+func unwrapUnion(decode func(interface{}) (interface{}, error)) func(interface{}) (interface{}, error) {
+	return func(v interface{}) (interface{}, error) {
+		if m, ok := v.(map[string]interface{}); ok && len(m) == 1 {
+			for _, member := range m {
+				return decode(member)
+			}
+		}
+		return decode(v)
+	}
+}
+
+func decodeIdentity(v interface{}) (interface{}, error) {
+	return v, nil
+}
+
+func decodeBool(v interface{}) (interface{}, error) {
+	if v.(bool) {
+		return int8(1), nil
+	}
+	return int8(0), nil
+}
+
+func decodeTime(v interface{}) (interface{}, error) {
+	return gmstypes.Timespan(v.(time.Duration).Microseconds()), nil
+}
+
+func decodeDecimal(scale int) func(interface{}) (interface{}, error) {
+	return func(v interface{}) (interface{}, error) {
+		return v.(*big.Rat).FloatString(scale), nil
+	}
+}
+
+func decodeJSON(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
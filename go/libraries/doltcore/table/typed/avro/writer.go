@@ -0,0 +1,328 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package avro
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/dolthub/vitess/go/vt/proto/query"
+	"github.com/linkedin/goavro/v2"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+)
+
+// recordName is the name of the Avro record type of the rows written by an AvroRowWriter
+const recordName = "row"
+
+// rowsPerBlock is the number of rows buffered by an AvroRowWriter before they're written as a block of the container
+// file.
+const rowsPerBlock = 1024
+
+// AvroRowWriter writes rows to an Avro object container file.
+type AvroRowWriter struct {
+	ocfWriter *goavro.OCFWriter
+	sch       sql.Schema
+	fields    []avroField
+	buffered  []interface{}
+	closer    io.Closer
+}
+
+var _ table.SqlRowWriter = (*AvroRowWriter)(nil)
+
+// avroField describes how the values of a column are written to an Avro record.
+type avroField struct {
+	name string
+	// unionName is the name of the non-null type of the field's union, or empty if the field isn't nullable.
+	unionName string
+	encode    func(sqlType sql.Type, v interface{}) (interface{}, error)
+}
+
+// NewAvroRowWriter creates a new AvroRowWriter instance for the specified schema and writing to the specified
+// WriteCloser.
+func NewAvroRowWriter(outSch sql.Schema, w io.WriteCloser) (*AvroRowWriter, error) {
+	fieldSchemas := make([]map[string]interface{}, len(outSch))
+	fields := make([]avroField, len(outSch))
+	names := make(map[string]struct{}, len(outSch))
+	for i, col := range outSch {
+		avroType, encode, err := mapTypeToAvroType(col.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		name := avroFieldName(col.Name)
+		if _, ok := names[name]; ok {
+			return nil, fmt.Errorf("column %s cannot be written to avro, its field name %s is not unique", col.Name, name)
+		}
+		names[name] = struct{}{}
+
+		fields[i] = avroField{name: name, encode: encode}
+		fieldSchema := map[string]interface{}{"name": name, "type": avroType}
+		if col.Nullable {
+			fields[i].unionName = avroTypeName(avroType)
+			fieldSchema["type"] = []interface{}{"null", avroType}
+			fieldSchema["default"] = nil
+		}
+		fieldSchemas[i] = fieldSchema
+	}
+
+	avroSchema, err := json.Marshal(map[string]interface{}{
+		"type":   "record",
+		"name":   recordName,
+		"fields": fieldSchemas,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ocfWriter, err := goavro.NewOCFWriter(goavro.OCFConfig{
+		W:               w,
+		Schema:          string(avroSchema),
+		CompressionName: goavro.CompressionSnappyLabel,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &AvroRowWriter{ocfWriter: ocfWriter, sch: outSch, fields: fields, closer: w}, nil
+}
+
+// NewAvroRowWriterForSchema creates a new AvroRowWriter instance for the specified dolt schema and writing to the
+// specified WriteCloser.
+func NewAvroRowWriterForSchema(outSch schema.Schema, w io.WriteCloser) (*AvroRowWriter, error) {
+	sqlSch, err := sqlutil.FromDoltSchema("", "", outSch)
+	if err != nil {
+		return nil, err
+	}
+	return NewAvroRowWriter(sqlSch.Schema, w)
+}
+
+func (w *AvroRowWriter) WriteSqlRow(_ context.Context, r sql.Row) error {
+	record := make(map[string]interface{}, len(w.fields))
+	for i, field := range w.fields {
+		if r[i] == nil {
+			record[field.name] = nil
+			continue
+		}
+
+		v, err := field.encode(w.sch[i].Type, r[i])
+		if err != nil {
+			return fmt.Errorf("cannot write value of column %s: %w", w.sch[i].Name, err)
+		}
+		if field.unionName != "" {
+			v = goavro.Union(field.unionName, v)
+		}
+		record[field.name] = v
+	}
+
+	w.buffered = append(w.buffered, record)
+	if len(w.buffered) >= rowsPerBlock {
+		return w.flush()
+	}
+	return nil
+}
+
+func (w *AvroRowWriter) flush() error {
+	if len(w.buffered) == 0 {
+		return nil
+	}
+	err := w.ocfWriter.Append(w.buffered)
+	w.buffered = w.buffered[:0]
+	return err
+}
+
+// Close should flush all writes, release resources being held
+func (w *AvroRowWriter) Close(_ context.Context) error {
+	err := w.flush()
+	if err != nil {
+		return err
+	}
+
+	if w.closer != nil {
+		return w.closer.Close()
+	}
+	return nil
+}
+
+// mapTypeToAvroType maps |t| from a sql.Type to the Avro schema of its values, and returns a function converting its
+// values to the native representation of that Avro type.
+func mapTypeToAvroType(t sql.Type) (interface{}, func(sql.Type, interface{}) (interface{}, error), error) {
+	switch t.Type() {
+	case query.Type_INT8, query.Type_INT16, query.Type_INT24, query.Type_INT32,
+		query.Type_UINT8, query.Type_UINT16, query.Type_UINT24, query.Type_YEAR:
+		return "int", encodeInt32, nil
+	case query.Type_INT64, query.Type_UINT32, query.Type_UINT64, query.Type_BIT:
+		return "long", encodeInt64, nil
+	case query.Type_FLOAT32:
+		return "float", encodeFloat32, nil
+	case query.Type_FLOAT64:
+		return "double", encodeFloat64, nil
+	case query.Type_DECIMAL:
+		dt := t.(types.DecimalType_)
+		return map[string]interface{}{
+			"type":        "bytes",
+			"logicalType": "decimal",
+			"precision":   dt.Precision(),
+			"scale":       dt.Scale(),
+		}, encodeDecimal, nil
+	case query.Type_DATE:
+		return map[string]interface{}{"type": "int", "logicalType": "date"}, encodeDate, nil
+	case query.Type_DATETIME, query.Type_TIMESTAMP:
+		return map[string]interface{}{"type": "long", "logicalType": "timestamp-micros"}, encodeTimestamp, nil
+	case query.Type_TIME:
+		return map[string]interface{}{"type": "long", "logicalType": "time-micros"}, encodeTime, nil
+	case query.Type_BLOB, query.Type_BINARY, query.Type_VARBINARY:
+		return "bytes", encodeBytes, nil
+	case query.Type_ENUM, query.Type_SET, query.Type_TUPLE, query.Type_JSON, query.Type_CHAR, query.Type_VARCHAR,
+		query.Type_TEXT, query.Type_GEOMETRY:
+		return "string", encodeString, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported type: %v", t.Type())
+	}
+}
+
+// avroTypeName returns the name of |avroType| as a member of a union.
+func avroTypeName(avroType interface{}) string {
+	switch t := avroType.(type) {
+	case string:
+		return t
+	case map[string]interface{}:
+		return fmt.Sprintf("%s.%s", t["type"], t["logicalType"])
+	default:
+		panic(fmt.Sprintf("unexpected avro type: %v", avroType))
+	}
+}
+
+// avroFieldName returns |colName| with the characters which are not allowed in the names of Avro fields replaced.
+func avroFieldName(colName string) string {
+	var sb strings.Builder
+	for i, r := range colName {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+			sb.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				sb.WriteRune('_')
+			}
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune('_')
+		}
+	}
+	if sb.Len() == 0 {
+		return "_"
+	}
+	return sb.String()
+}
+
+func encodeInt32(_ sql.Type, v interface{}) (interface{}, error) {
+	i, inRange, err := types.Int32.Convert(v)
+	if err != nil {
+		return nil, err
+	} else if inRange != sql.InRange {
+		return nil, sql.ErrValueOutOfRange.New(v, types.Int32)
+	}
+	return i, nil
+}
+
+func encodeInt64(_ sql.Type, v interface{}) (interface{}, error) {
+	i, inRange, err := types.Int64.Convert(v)
+	if err != nil {
+		return nil, err
+	} else if inRange != sql.InRange {
+		return nil, sql.ErrValueOutOfRange.New(v, types.Int64)
+	}
+	return i, nil
+}
+
+func encodeFloat32(_ sql.Type, v interface{}) (interface{}, error) {
+	f, _, err := types.Float32.Convert(v)
+	return f, err
+}
+
+func encodeFloat64(_ sql.Type, v interface{}) (interface{}, error) {
+	f, _, err := types.Float64.Convert(v)
+	return f, err
+}
+
+func encodeDecimal(t sql.Type, v interface{}) (interface{}, error) {
+	d, err := t.(types.DecimalType_).ConvertToNullDecimal(v)
+	if err != nil {
+		return nil, err
+	}
+	return d.Decimal.Rat(), nil
+}
+
+func encodeDate(t sql.Type, v interface{}) (interface{}, error) {
+	tm, err := convertToTime(t, v)
+	if err != nil {
+		return nil, err
+	}
+	days := tm.Unix() / 86400
+	if tm.Unix()%86400 < 0 {
+		days--
+	}
+	return int32(days), nil
+}
+
+func encodeTimestamp(t sql.Type, v interface{}) (interface{}, error) {
+	tm, err := convertToTime(t, v)
+	if err != nil {
+		return nil, err
+	}
+	return tm.UnixMicro(), nil
+}
+
+func encodeTime(_ sql.Type, v interface{}) (interface{}, error) {
+	d, err := types.Time.ConvertToTimeDuration(v)
+	if err != nil {
+		return nil, err
+	}
+	return d.Microseconds(), nil
+}
+
+func encodeBytes(t sql.Type, v interface{}) (interface{}, error) {
+	switch b := v.(type) {
+	case []byte:
+		return b, nil
+	case string:
+		return []byte(b), nil
+	}
+	s, err := sqlutil.SqlColToStr(t, v)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
+
+func encodeString(t sql.Type, v interface{}) (interface{}, error) {
+	return sqlutil.SqlColToStr(t, v)
+}
+
+func convertToTime(t sql.Type, v interface{}) (time.Time, error) {
+	tm, _, err := t.Convert(v)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return tm.(time.Time).UTC(), nil
+}
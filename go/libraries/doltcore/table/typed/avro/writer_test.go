@@ -0,0 +1,102 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package avro
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
+)
+
+var outSch = sql.Schema{
+	{Name: "id", Type: gmstypes.Int64, Nullable: false},
+	{Name: "name", Type: gmstypes.LongText, Nullable: true},
+	{Name: "age", Type: gmstypes.Uint8, Nullable: true},
+	{Name: "balance", Type: gmstypes.MustCreateDecimalType(10, 2), Nullable: true},
+	{Name: "score", Type: gmstypes.Float64, Nullable: true},
+	{Name: "birthday", Type: gmstypes.Date, Nullable: true},
+	{Name: "updated at", Type: gmstypes.DatetimeMaxPrecision, Nullable: true},
+	{Name: "duration", Type: gmstypes.Time, Nullable: true},
+	{Name: "data", Type: gmstypes.Blob, Nullable: true},
+}
+
+func getSampleRows() []sql.Row {
+	return []sql.Row{
+		{int64(1), "Bill Billerson", uint8(32), decimal.RequireFromString("1234.56"), 1.5,
+			time.Date(1950, 3, 14, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC),
+			gmstypes.Timespan(-3723000000), []byte{0, 1, 2}},
+		{int64(2), "Rob Robertson", nil, nil, nil, nil, nil, nil, nil},
+	}
+}
+
+func TestWriter(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	wr, err := NewAvroRowWriter(outSch, iohelp.NopWrCloser(&buf))
+	require.NoError(t, err)
+	for _, r := range getSampleRows() {
+		require.NoError(t, wr.WriteSqlRow(ctx, r))
+	}
+	require.NoError(t, wr.Close(ctx))
+
+	rd, err := NewAvroReader(io.NopCloser(&buf))
+	require.NoError(t, err)
+	defer rd.Close(ctx)
+
+	expected := []struct {
+		name    string
+		sqlType string
+	}{
+		{"id", "bigint"},
+		{"name", "varchar(1023)"},
+		{"age", "int"},
+		{"balance", "decimal(10,2)"},
+		{"score", "double"},
+		{"birthday", "date"},
+		{"updated_at", "datetime(6)"},
+		{"duration", "time(6)"},
+		{"data", "varbinary(16383)"},
+	}
+	cols := rd.GetSchema().GetAllCols().GetColumns()
+	require.Equal(t, len(expected), len(cols))
+	for i, col := range cols {
+		assert.Equal(t, expected[i].name, col.Name)
+		assert.Equal(t, expected[i].sqlType, col.TypeInfo.ToSqlType().String())
+		assert.Equal(t, i != 0, col.IsNullable())
+	}
+
+	r, err := rd.ReadSqlRow(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, sql.Row{int64(1), "Bill Billerson", int32(32), "1234.56", 1.5,
+		time.Date(1950, 3, 14, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC),
+		gmstypes.Timespan(-3723000000), []byte{0, 1, 2}}, r)
+
+	r, err = rd.ReadSqlRow(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, sql.Row{int64(2), "Rob Robertson", nil, nil, nil, nil, nil, nil, nil}, r)
+
+	_, err = rd.ReadSqlRow(ctx)
+	assert.Equal(t, io.EOF, err)
+}
@@ -31,6 +31,7 @@ var ConfigOptions = map[string]struct{}{
 	PushAutoSetupRemote:   {},
 	ProfileKey:            {},
 	VersionCheckDisabled:  {},
+	GPGSigningKeyKey:      {},
 }
 
 const UserEmailKey = "user.email"
@@ -0,0 +1,318 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gpg
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"golang.org/x/crypto/ssh"
+)
+
+// openPGPKeyType is the key type used in an allowed signers file for OpenPGP public keys
+const openPGPKeyType = "openpgp"
+
+// AllowedSigners is the set of keys trusted to sign commits and tags. It is read from a file in the format of the
+// allowed signers file used by `ssh-keygen -Y verify` and git's gpg.ssh.allowedSignersFile. Each line has the form
+//
+//	principals [options] keytype base64-key [comment]
+//
+// where principals is a comma separated list of patterns matching the emails the key may sign for. Patterns may use
+// the * and ? wildcards, and are negated by a leading !. The only supported option is namespaces="...", which limits
+// the signature namespaces the key is trusted for. OpenPGP keys are listed with the key type "openpgp", followed by
+// the base64 encoding of the binary public key, e.g. the output of `gpg --export <key id> | base64 -w0`.
+type AllowedSigners struct {
+	entries []allowedSigner
+	keyring openpgp.EntityList
+	// useGPGKeyring enables looking up OpenPGP keys that are not allowed signers in the local gpg keyring
+	useGPGKeyring bool
+}
+
+type allowedSigner struct {
+	principals []string
+	namespaces []string
+	sshKey     ssh.PublicKey
+	pgpKey     *openpgp.Entity
+}
+
+// LoadAllowedSigners reads the allowed signers file at |path|
+func LoadAllowedSigners(path string) (*AllowedSigners, error) {
+	path, err := expandHome(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read allowed signers: %w", err)
+	}
+	defer f.Close()
+	return ParseAllowedSigners(f)
+}
+
+// ParseAllowedSigners reads an allowed signers file from |r|
+func ParseAllowedSigners(r io.Reader) (*AllowedSigners, error) {
+	signers := &AllowedSigners{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		entry, err := parseAllowedSigner(line)
+		if err != nil {
+			return nil, fmt.Errorf("allowed signers line %d: %w", lineNum, err)
+		}
+		signers.entries = append(signers.entries, entry)
+		if entry.pgpKey != nil {
+			signers.keyring = append(signers.keyring, entry.pgpKey)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return signers, nil
+}
+
+func parseAllowedSigner(line string) (allowedSigner, error) {
+	var principals, rest string
+	if strings.HasPrefix(line, `"`) {
+		end := strings.Index(line[1:], `"`)
+		if end < 0 {
+			return allowedSigner{}, fmt.Errorf("unterminated quoted principals")
+		}
+		principals, rest = line[1:end+1], line[end+2:]
+	} else {
+		principals, rest, _ = strings.Cut(line, " ")
+	}
+
+	entry := allowedSigner{principals: strings.Split(principals, ",")}
+	rest = strings.TrimSpace(rest)
+
+	var options []string
+	if fields := strings.Fields(rest); len(fields) > 1 && (fields[0] == openPGPKeyType || fields[1] == openPGPKeyType) {
+		if fields[1] == openPGPKeyType {
+			options, fields = splitOptions(fields[0]), fields[1:]
+		}
+		if len(fields) < 2 {
+			return allowedSigner{}, fmt.Errorf("missing OpenPGP public key")
+		}
+		key, err := base64.StdEncoding.DecodeString(fields[1])
+		if err != nil {
+			return allowedSigner{}, fmt.Errorf("invalid OpenPGP public key: %w", err)
+		}
+		keyring, err := openpgp.ReadKeyRing(bytes.NewReader(key))
+		if err != nil {
+			return allowedSigner{}, fmt.Errorf("invalid OpenPGP public key: %w", err)
+		}
+		if len(keyring) != 1 {
+			return allowedSigner{}, fmt.Errorf("expected one OpenPGP public key, found %d", len(keyring))
+		}
+		entry.pgpKey = keyring[0]
+	} else {
+		key, _, opts, _, err := ssh.ParseAuthorizedKey([]byte(rest))
+		if err != nil {
+			return allowedSigner{}, fmt.Errorf("invalid public key: %w", err)
+		}
+		entry.sshKey, options = key, opts
+	}
+
+	for _, opt := range options {
+		name, val, _ := strings.Cut(opt, "=")
+		switch strings.ToLower(name) {
+		case "namespaces":
+			entry.namespaces = strings.Split(strings.Trim(val, `"`), ",")
+		default:
+			return allowedSigner{}, fmt.Errorf("unsupported option '%s'", name)
+		}
+	}
+	return entry, nil
+}
+
+// splitOptions splits a comma separated list of options, ignoring commas inside of quotes
+func splitOptions(s string) []string {
+	var options []string
+	inQuotes, start := false, 0
+	for i, c := range s {
+		switch {
+		case c == '"':
+			inQuotes = !inQuotes
+		case c == ',' && !inQuotes:
+			options = append(options, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(options, s[start:])
+}
+
+// WithGPGKeyring returns a copy of |s| that also trusts the public keys in the local gpg keyring to sign for the emails
+// of their user ids. This is how `gpg --verify` trusts keys, so it is used when displaying signatures to a user, but
+// should not be used to enforce a signing policy.
+func (s *AllowedSigners) WithGPGKeyring() *AllowedSigners {
+	ret := &AllowedSigners{useGPGKeyring: true}
+	if s != nil {
+		ret.entries, ret.keyring = s.entries, s.keyring
+	}
+	return ret
+}
+
+// keyringFor returns the keyring used to check |sig|
+func (s *AllowedSigners) keyringFor(sig *packet.Signature) openpgp.EntityList {
+	if !s.useGPGKeyring || sig.IssuerKeyId == nil || len(s.keyring.KeysById(*sig.IssuerKeyId)) > 0 {
+		return s.keyring
+	}
+	if entity := gpgKeyringLookup(*sig.IssuerKeyId); entity != nil {
+		return append(s.keyring[:len(s.keyring):len(s.keyring)], entity)
+	}
+	return s.keyring
+}
+
+// trustSSHKey returns the principal |key| is trusted to sign for, and whether it is trusted
+func (s *AllowedSigners) trustSSHKey(key ssh.PublicKey, principal, namespace string) (string, SignatureStatus) {
+	marshalled := key.Marshal()
+	for _, entry := range s.entries {
+		if entry.sshKey == nil || !bytes.Equal(entry.sshKey.Marshal(), marshalled) {
+			continue
+		}
+		if len(entry.namespaces) > 0 && !matchesPatternList(entry.namespaces, namespace) {
+			continue
+		}
+		if p, ok := entry.trustedFor(principal); ok {
+			return p, SignatureStatusGood
+		}
+	}
+	return "", SignatureStatusUntrusted
+}
+
+// trustOpenPGPKey returns the principal |entity| is trusted to sign for, and whether it is trusted
+func (s *AllowedSigners) trustOpenPGPKey(entity *openpgp.Entity, principal string) (string, SignatureStatus) {
+	for _, entry := range s.entries {
+		if entry.pgpKey == nil || !bytes.Equal(entry.pgpKey.PrimaryKey.Fingerprint, entity.PrimaryKey.Fingerprint) {
+			continue
+		}
+		if p, ok := entry.trustedFor(principal); ok {
+			return p, SignatureStatusGood
+		}
+	}
+
+	if s.useGPGKeyring {
+		for _, id := range entity.Identities {
+			if id.UserId != nil && (principal == "" || id.UserId.Email == principal) {
+				return id.Name, SignatureStatusGood
+			}
+		}
+	}
+	return "", SignatureStatusUntrusted
+}
+
+// gpgKeyringLookupTimeout bounds how long verification waits on gpg
+const gpgKeyringLookupTimeout = 5 * time.Second
+
+// gpgKeyringMissTTL is how long a key that is not in the gpg keyring is remembered as missing
+const gpgKeyringMissTTL = time.Minute
+
+type gpgKeyringEntry struct {
+	entity  *openpgp.Entity
+	fetched time.Time
+}
+
+var gpgKeyringCache = struct {
+	mu      sync.Mutex
+	entries map[uint64]gpgKeyringEntry
+}{entries: make(map[uint64]gpgKeyringEntry)}
+
+// gpgKeyringLookup returns the public key with |keyId| exported from the local gpg keyring, or nil if there is no such
+// key or gpg isn't available.
+func gpgKeyringLookup(keyId uint64) *openpgp.Entity {
+	gpgKeyringCache.mu.Lock()
+	defer gpgKeyringCache.mu.Unlock()
+	if e, ok := gpgKeyringCache.entries[keyId]; ok && (e.entity != nil || time.Since(e.fetched) < gpgKeyringMissTTL) {
+		return e.entity
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), gpgKeyringLookupTimeout)
+	defer cancel()
+
+	var entity *openpgp.Entity
+	out, _, err := execGpgAndReadOutput(ctx, nil, []string{"--export", fmt.Sprintf("%016X", keyId)})
+	if err == nil && out.Len() > 0 {
+		if keyring, err := openpgp.ReadKeyRing(out); err == nil && len(keyring) == 1 {
+			entity = keyring[0]
+		}
+	}
+	gpgKeyringCache.entries[keyId] = gpgKeyringEntry{entity: entity, fetched: time.Now()}
+	return entity
+}
+
+// trustedFor returns whether this entry's key may sign for |principal|. An empty |principal| matches any entry.
+func (e allowedSigner) trustedFor(principal string) (string, bool) {
+	if principal == "" {
+		return strings.Join(e.principals, ","), true
+	}
+	return principal, matchesPatternList(e.principals, principal)
+}
+
+// matchesPatternList returns whether |s| matches any of |patterns| and none of the negated patterns
+func matchesPatternList(patterns []string, s string) bool {
+	matched := false
+	for _, pattern := range patterns {
+		if negated := strings.HasPrefix(pattern, "!"); negated {
+			if matchesWildcard(pattern[1:], s) {
+				return false
+			}
+		} else if matchesWildcard(pattern, s) {
+			matched = true
+		}
+	}
+	return matched
+}
+
+// matchesWildcard matches |s| against |pattern|, where * matches any number of characters and ? matches one
+func matchesWildcard(pattern, s string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for i := len(s); i >= 0; i-- {
+				if matchesWildcard(pattern[1:], s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(s) == 0 {
+				return false
+			}
+		default:
+			if len(s) == 0 || pattern[0] != s[0] {
+				return false
+			}
+		}
+		pattern, s = pattern[1:], s[1:]
+	}
+	return len(s) == 0
+}
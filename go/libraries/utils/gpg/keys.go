@@ -0,0 +1,124 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gpg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"golang.org/x/crypto/ssh"
+)
+
+var ErrNoSigningKey = errors.New("no signing key given, pass a key id to -S or set user.signingkey")
+
+// SigningKeys are the private keys a server is configured to sign commits and tags with. Keys are selected the way
+// gpg -u selects them, by OpenPGP fingerprint, key id or user id email, or for SSH keys by SHA256 fingerprint. When no
+// keys are configured, signing falls back to the keys in the local gpg keyring.
+type SigningKeys struct {
+	pgpKeys []*openpgp.Entity
+	sshKeys []ssh.Signer
+}
+
+// LoadSigningKeys reads the OpenPGP and OpenSSH private keys in the files at |paths|
+func LoadSigningKeys(paths []string) (*SigningKeys, error) {
+	keys := &SigningKeys{}
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		path, err := expandHome(path)
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read signing key: %w", err)
+		}
+
+		if isOpenPGPKey(data) {
+			entity, err := readOpenPGPPrivateKey(data)
+			if err != nil {
+				return nil, err
+			}
+			if _, err = openPGPSigningKey(entity); err != nil {
+				return nil, fmt.Errorf("signing key '%s': %w", path, err)
+			}
+			keys.pgpKeys = append(keys.pgpKeys, entity)
+			continue
+		}
+
+		signer, err := ssh.ParsePrivateKey(data)
+		if err != nil {
+			var missing *ssh.PassphraseMissingError
+			if errors.As(err, &missing) {
+				return nil, fmt.Errorf("signing key '%s': %w", path, ErrEncryptedKey)
+			}
+			return nil, fmt.Errorf("failed to read signing key '%s': not an OpenPGP or OpenSSH private key", path)
+		}
+		keys.sshKeys = append(keys.sshKeys, signer)
+	}
+	return keys, nil
+}
+
+// Sign signs |message| with the key identified by |keyId|. OpenPGP keys produce a clear signed message, like
+// `gpg --clear-sign`, and SSH keys produce the message followed by an SSH signature block. If no keys are configured
+// the message is signed by gpg using its keyring.
+func (k *SigningKeys) Sign(ctx context.Context, keyId string, message []byte) ([]byte, error) {
+	keyId = strings.TrimSpace(keyId)
+	if keyId == "" {
+		return nil, ErrNoSigningKey
+	}
+	if k == nil || (len(k.pgpKeys) == 0 && len(k.sshKeys) == 0) {
+		return Sign(ctx, keyId, message)
+	}
+
+	if entity := k.findOpenPGPKey(keyId); entity != nil {
+		return signOpenPGP(entity, message)
+	}
+	for _, signer := range k.sshKeys {
+		if ssh.FingerprintSHA256(signer.PublicKey()) == keyId {
+			return signSSH(signer, message)
+		}
+	}
+	return nil, fmt.Errorf("signing key '%s' is not one of the server's signing keys", keyId)
+}
+
+// findOpenPGPKey returns the key whose fingerprint, key id, short key id or user id email is |keyId|
+func (k *SigningKeys) findOpenPGPKey(keyId string) *openpgp.Entity {
+	hexId := strings.ToUpper(strings.TrimPrefix(strings.TrimPrefix(keyId, "0x"), "0X"))
+	email := strings.TrimSuffix(strings.TrimPrefix(keyId, "<"), ">")
+	for _, entity := range k.pgpKeys {
+		fingerprints := []string{fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint)}
+		for _, subkey := range entity.Subkeys {
+			fingerprints = append(fingerprints, fmt.Sprintf("%X", subkey.PublicKey.Fingerprint))
+		}
+		for _, fp := range fingerprints {
+			if len(hexId) >= 8 && strings.HasSuffix(fp, hexId) && (len(hexId) == 8 || len(hexId) == 16 || len(hexId) == len(fp)) {
+				return entity
+			}
+		}
+		for _, id := range entity.Identities {
+			if id.UserId != nil && (strings.EqualFold(id.UserId.Email, email) || id.Name == keyId) {
+				return entity
+			}
+		}
+	}
+	return nil
+}
@@ -23,7 +23,7 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-var keyId = "573DA8C6366D04E35CDB1A44E09A0B208F666373"
+var keyId = "D734F7535E0B95F2A990F665E7940B58CC9ED591"
 
 func signTestSetup(t *testing.T, ctx context.Context) {
 	err := ImportKey(ctx, "testdata/private.pgp")
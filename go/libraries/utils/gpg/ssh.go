@@ -0,0 +1,205 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gpg
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// SSH signatures use the format of `ssh-keygen -Y sign`, described in
+// https://github.com/openssh/openssh-portable/blob/master/PROTOCOL.sshsig. They can be checked with
+// `ssh-keygen -Y verify -n dolt`.
+
+const (
+	sshSigMagic     = "SSHSIG"
+	sshSigVersion   = 1
+	sshSigPEMType   = "SSH SIGNATURE"
+	sshSigNamespace = "dolt"
+	sshSigLineLen   = 70
+
+	sshSigHashSHA256 = "sha256"
+	sshSigHashSHA512 = "sha512"
+)
+
+const sshSignatureBegin = "-----BEGIN " + sshSigPEMType + "-----"
+
+// sshSigBlob is the wire format of an SSH signature
+type sshSigBlob struct {
+	Magic         [6]byte
+	Version       uint32
+	PublicKey     string
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     string
+}
+
+// sshSignedData is the data that is actually signed by the key
+type sshSignedData struct {
+	Magic         [6]byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Hash          string
+}
+
+func sshSigHash(alg string) (hash.Hash, error) {
+	switch alg {
+	case sshSigHashSHA256:
+		return sha256.New(), nil
+	case sshSigHashSHA512:
+		return sha512.New(), nil
+	default:
+		return nil, fmt.Errorf("unsupported ssh signature hash algorithm '%s'", alg)
+	}
+}
+
+func sshSignedDataFor(message []byte, namespace, hashAlg string) ([]byte, error) {
+	h, err := sshSigHash(hashAlg)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(message)
+
+	sd := sshSignedData{
+		Namespace:     namespace,
+		HashAlgorithm: hashAlg,
+		Hash:          string(h.Sum(nil)),
+	}
+	copy(sd.Magic[:], sshSigMagic)
+	return ssh.Marshal(sd), nil
+}
+
+// signSSH returns |message| followed by an armored SSH signature of it.
+func signSSH(signer ssh.Signer, message []byte) ([]byte, error) {
+	signedData, err := sshSignedDataFor(message, sshSigNamespace, sshSigHashSHA512)
+	if err != nil {
+		return nil, err
+	}
+
+	var sig *ssh.Signature
+	if algSigner, ok := signer.(ssh.AlgorithmSigner); ok && signer.PublicKey().Type() == ssh.KeyAlgoRSA {
+		// ssh-rsa signatures use SHA-1, which is rejected by ssh-keygen for signatures
+		sig, err = algSigner.SignWithAlgorithm(rand.Reader, signedData, ssh.KeyAlgoRSASHA512)
+	} else {
+		sig, err = signer.Sign(rand.Reader, signedData)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	blob := sshSigBlob{
+		Version:       sshSigVersion,
+		PublicKey:     string(signer.PublicKey().Marshal()),
+		Namespace:     sshSigNamespace,
+		HashAlgorithm: sshSigHashSHA512,
+		Signature:     string(ssh.Marshal(sig)),
+	}
+	copy(blob.Magic[:], sshSigMagic)
+
+	buf := bytes.NewBuffer(nil)
+	buf.Write(message)
+	buf.WriteByte('\n')
+	buf.WriteString(sshSignatureBegin + "\n")
+	encoded := base64.StdEncoding.EncodeToString(ssh.Marshal(blob))
+	for len(encoded) > sshSigLineLen {
+		buf.WriteString(encoded[:sshSigLineLen] + "\n")
+		encoded = encoded[sshSigLineLen:]
+	}
+	buf.WriteString(encoded + "\n")
+	buf.WriteString("-----END " + sshSigPEMType + "-----\n")
+	return buf.Bytes(), nil
+}
+
+// isSSHSigned returns whether |signed| was created by signSSH
+func isSSHSigned(signed []byte) bool {
+	return bytes.Contains(signed, []byte(sshSignatureBegin))
+}
+
+// verifySSH checks a message signed by signSSH.
+func verifySSH(signed []byte, signers *AllowedSigners, principal string) (*Verification, error) {
+	idx := bytes.LastIndex(signed, []byte(sshSignatureBegin))
+	if idx < 0 {
+		return nil, errors.New("no ssh signature found")
+	}
+	message := bytes.TrimSuffix(signed[:idx], []byte("\n"))
+
+	block, _ := pem.Decode(signed[idx:])
+	if block == nil || block.Type != sshSigPEMType {
+		return nil, errors.New("malformed ssh signature")
+	}
+
+	var blob sshSigBlob
+	if err := ssh.Unmarshal(block.Bytes, &blob); err != nil {
+		return nil, fmt.Errorf("malformed ssh signature: %w", err)
+	}
+	if string(blob.Magic[:]) != sshSigMagic || blob.Version != sshSigVersion {
+		return nil, errors.New("malformed ssh signature: unsupported signature version")
+	}
+
+	pub, err := ssh.ParsePublicKey([]byte(blob.PublicKey))
+	if err != nil {
+		return nil, fmt.Errorf("malformed ssh signature public key: %w", err)
+	}
+
+	v := &Verification{
+		Format:      FormatSSH,
+		KeyType:     sshKeyTypeName(pub),
+		Fingerprint: ssh.FingerprintSHA256(pub),
+		Payload:     message,
+	}
+
+	var sig ssh.Signature
+	if err := ssh.Unmarshal([]byte(blob.Signature), &sig); err != nil {
+		return nil, fmt.Errorf("malformed ssh signature: %w", err)
+	}
+
+	signedData, err := sshSignedDataFor(message, blob.Namespace, blob.HashAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	if blob.Namespace != sshSigNamespace {
+		v.Status = SignatureStatusBad
+		v.Reason = fmt.Sprintf("signature namespace is '%s', expected '%s'", blob.Namespace, sshSigNamespace)
+		return v, nil
+	}
+	if err := pub.Verify(signedData, &sig); err != nil {
+		v.Status = SignatureStatusBad
+		v.Reason = err.Error()
+		return v, nil
+	}
+
+	v.Principal, v.Status = signers.trustSSHKey(pub, principal, sshSigNamespace)
+	return v, nil
+}
+
+func sshKeyTypeName(pub ssh.PublicKey) string {
+	t := strings.TrimPrefix(pub.Type(), "ssh-")
+	if i := strings.Index(t, "@"); i > 0 {
+		t = t[:i]
+	}
+	return strings.ToUpper(t)
+}
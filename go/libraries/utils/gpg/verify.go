@@ -0,0 +1,121 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gpg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SignatureStatus is the result of verifying a signature
+type SignatureStatus string
+
+const (
+	// SignatureStatusUnsigned means there was no signature to verify
+	SignatureStatusUnsigned SignatureStatus = "unsigned"
+	// SignatureStatusGood means the signature is valid and was made by a key trusted for the signer
+	SignatureStatusGood SignatureStatus = "good"
+	// SignatureStatusUntrusted means the signature is valid, but the key that made it is not trusted
+	SignatureStatusUntrusted SignatureStatus = "untrusted"
+	// SignatureStatusBad means the signature is malformed or does not match the signed message
+	SignatureStatusBad SignatureStatus = "bad"
+	// SignatureStatusUnknownKey means the signature could not be checked because the public key that made it is unknown
+	SignatureStatusUnknownKey SignatureStatus = "unknown_key"
+)
+
+// SignatureFormat is the kind of key used to make a signature
+type SignatureFormat string
+
+const (
+	FormatOpenPGP SignatureFormat = "openpgp"
+	FormatSSH     SignatureFormat = "ssh"
+)
+
+var ErrEncryptedKey = errors.New("signing keys protected by a passphrase are not supported")
+
+// Verification is the result of verifying a signed message
+type Verification struct {
+	Status SignatureStatus
+	Format SignatureFormat
+	// KeyType is the algorithm of the key that made the signature, e.g. RSA or ED25519
+	KeyType string
+	// Fingerprint identifies the key that made the signature. It is the SHA256 fingerprint for SSH keys, and the
+	// fingerprint, or key id if the key is unknown, for OpenPGP keys.
+	Fingerprint string
+	// Principal is the trusted signer the key belongs to
+	Principal string
+	// Reason describes why a signature is bad
+	Reason string
+	// Payload is the message that was signed
+	Payload []byte
+}
+
+// String returns a human readable description of the verification
+func (v *Verification) String() string {
+	switch v.Status {
+	case SignatureStatusUnsigned:
+		return "No signature"
+	case SignatureStatusGood:
+		return fmt.Sprintf("Good signature from \"%s\" with %s key %s", v.Principal, v.KeyType, v.Fingerprint)
+	case SignatureStatusUntrusted:
+		return fmt.Sprintf("Good signature with %s key %s\nWARNING: This key is not trusted by the allowed signers.", v.KeyType, v.Fingerprint)
+	case SignatureStatusUnknownKey:
+		return fmt.Sprintf("Can't check signature: no public key for %s key %s", v.KeyType, v.Fingerprint)
+	default:
+		if v.KeyType == "" {
+			return fmt.Sprintf("BAD signature: %s", v.Reason)
+		}
+		return fmt.Sprintf("BAD signature with %s key %s: %s", v.KeyType, v.Fingerprint, v.Reason)
+	}
+}
+
+// VerifySignature checks the signature of |signed|, a message produced by SigningKeys.Sign or Sign. The signature is
+// trusted if the key that made it is listed in |signers| for |principal|. If |principal| is empty, a key listed for
+// any principal is trusted. Malformed signatures are reported as bad.
+func VerifySignature(signed []byte, signers *AllowedSigners, principal string) *Verification {
+	if len(bytes.TrimSpace(signed)) == 0 {
+		return &Verification{Status: SignatureStatusUnsigned}
+	}
+	if signers == nil {
+		signers = &AllowedSigners{}
+	}
+
+	var v *Verification
+	var err error
+	if isSSHSigned(signed) {
+		v, err = verifySSH(signed, signers, principal)
+	} else {
+		v, err = verifyOpenPGP(signed, signers, principal)
+	}
+	if err != nil {
+		return &Verification{Status: SignatureStatusBad, Reason: err.Error()}
+	}
+	return v
+}
+
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, path[1:]), nil
+}
@@ -0,0 +1,232 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gpg
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+const testOpenPGPKeyPath = "testdata/private.pgp"
+const testOpenPGPFingerprint = "D734F7535E0B95F2A990F665E7940B58CC9ED591"
+
+func TestSignOpenPGP(t *testing.T) {
+	ctx := context.Background()
+	keys, err := LoadSigningKeys([]string{testOpenPGPKeyPath})
+	require.NoError(t, err)
+
+	message := []byte("I did a thing")
+	signed, err := keys.Sign(ctx, testOpenPGPFingerprint, message)
+	require.NoError(t, err)
+	require.Contains(t, string(signed), "-----BEGIN PGP SIGNED MESSAGE-----")
+
+	t.Run("key ids", func(t *testing.T) {
+		for _, keyId := range []string{"E7940B58CC9ED591", "0xe7940b58cc9ed591", "CC9ED591", "test@dolthub.com", "<test@dolthub.com>"} {
+			_, err := keys.Sign(ctx, keyId, message)
+			require.NoError(t, err, keyId)
+		}
+		_, err := keys.Sign(ctx, "0123456789ABCDEF", message)
+		require.Error(t, err)
+		_, err = keys.Sign(ctx, testOpenPGPKeyPath, message)
+		require.Error(t, err)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		v := VerifySignature(signed, nil, "")
+		require.Equal(t, SignatureStatusUnknownKey, v.Status)
+		require.Equal(t, FormatOpenPGP, v.Format)
+		require.Equal(t, message, v.Payload)
+	})
+
+	signers, err := ParseAllowedSigners(strings.NewReader(openPGPAllowedSigner(t, "*@dolthub.com")))
+	require.NoError(t, err)
+
+	t.Run("good", func(t *testing.T) {
+		v := VerifySignature(signed, signers, "test@dolthub.com")
+		require.Equal(t, SignatureStatusGood, v.Status, v.String())
+		require.Equal(t, "test@dolthub.com", v.Principal)
+		require.Equal(t, message, v.Payload)
+	})
+
+	t.Run("wrong principal", func(t *testing.T) {
+		v := VerifySignature(signed, signers, "test@example.com")
+		require.Equal(t, SignatureStatusUntrusted, v.Status)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		tampered := bytes.Replace(signed, []byte("I did a thing"), []byte("I did another thing"), 1)
+		v := VerifySignature(tampered, signers, "")
+		require.Equal(t, SignatureStatusBad, v.Status)
+	})
+}
+
+func TestSignSSH(t *testing.T) {
+	ctx := context.Background()
+	keyPath, pub := writeTestSSHKey(t)
+	keys, err := LoadSigningKeys([]string{keyPath})
+	require.NoError(t, err)
+
+	message := []byte("I did a thing")
+	signed, err := keys.Sign(ctx, ssh.FingerprintSHA256(pub), message)
+	require.NoError(t, err)
+	require.Contains(t, string(signed), "-----BEGIN SSH SIGNATURE-----")
+
+	t.Run("untrusted", func(t *testing.T) {
+		v := VerifySignature(signed, nil, "")
+		require.Equal(t, SignatureStatusUntrusted, v.Status)
+		require.Equal(t, FormatSSH, v.Format)
+		require.Equal(t, "ED25519", v.KeyType)
+		require.Equal(t, ssh.FingerprintSHA256(pub), v.Fingerprint)
+		require.Equal(t, message, v.Payload)
+	})
+
+	authorizedKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pub)))
+
+	t.Run("good", func(t *testing.T) {
+		signers, err := ParseAllowedSigners(strings.NewReader("# comment\n\ntest@dolthub.com,other@dolthub.com " + authorizedKey + " test key\n"))
+		require.NoError(t, err)
+		v := VerifySignature(signed, signers, "test@dolthub.com")
+		require.Equal(t, SignatureStatusGood, v.Status, v.String())
+		require.Equal(t, "test@dolthub.com", v.Principal)
+
+		v = VerifySignature(signed, signers, "")
+		require.Equal(t, SignatureStatusGood, v.Status)
+		require.Equal(t, "test@dolthub.com,other@dolthub.com", v.Principal)
+	})
+
+	t.Run("namespaces", func(t *testing.T) {
+		signers, err := ParseAllowedSigners(strings.NewReader(`* namespaces="git,file" ` + authorizedKey))
+		require.NoError(t, err)
+		v := VerifySignature(signed, signers, "test@dolthub.com")
+		require.Equal(t, SignatureStatusUntrusted, v.Status)
+
+		signers, err = ParseAllowedSigners(strings.NewReader(`* namespaces="git,dolt" ` + authorizedKey))
+		require.NoError(t, err)
+		v = VerifySignature(signed, signers, "test@dolthub.com")
+		require.Equal(t, SignatureStatusGood, v.Status)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		tampered := bytes.Replace(signed, []byte("I did a thing"), []byte("I did another thing"), 1)
+		v := VerifySignature(tampered, nil, "")
+		require.Equal(t, SignatureStatusBad, v.Status)
+	})
+}
+
+func TestLoadSigningKeysErrors(t *testing.T) {
+	_, err := LoadSigningKeys([]string{filepath.Join(t.TempDir(), "missing")})
+	require.Error(t, err)
+
+	notAKey := filepath.Join(t.TempDir(), "not_a_key")
+	require.NoError(t, os.WriteFile(notAKey, []byte("not a key"), 0600))
+	_, err = LoadSigningKeys([]string{notAKey})
+	require.Error(t, err)
+
+	keys, err := LoadSigningKeys(nil)
+	require.NoError(t, err)
+	_, err = keys.Sign(context.Background(), "", []byte("message"))
+	require.ErrorIs(t, err, ErrNoSigningKey)
+}
+
+func TestVerifyUnsigned(t *testing.T) {
+	require.Equal(t, SignatureStatusUnsigned, VerifySignature(nil, nil, "").Status)
+	require.Equal(t, SignatureStatusBad, VerifySignature([]byte("garbage"), nil, "").Status)
+}
+
+func TestMatchesPatternList(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		s        string
+		expected bool
+	}{
+		{[]string{"test@dolthub.com"}, "test@dolthub.com", true},
+		{[]string{"test@dolthub.com"}, "other@dolthub.com", false},
+		{[]string{"*@dolthub.com"}, "test@dolthub.com", true},
+		{[]string{"*@dolthub.com", "!bad@dolthub.com"}, "bad@dolthub.com", false},
+		{[]string{"te?t@dolthub.com"}, "test@dolthub.com", true},
+		{[]string{"te?t@dolthub.com"}, "tet@dolthub.com", false},
+		{[]string{"*"}, "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(strings.Join(test.patterns, ",")+" "+test.s, func(t *testing.T) {
+			require.Equal(t, test.expected, matchesPatternList(test.patterns, test.s))
+		})
+	}
+}
+
+func TestParseAllowedSignersErrors(t *testing.T) {
+	_, err := ParseAllowedSigners(strings.NewReader("test@dolthub.com ssh-ed25519 notbase64"))
+	require.Error(t, err)
+	_, err = ParseAllowedSigners(strings.NewReader("test@dolthub.com openpgp notbase64"))
+	require.Error(t, err)
+	_, err = ParseAllowedSigners(strings.NewReader("test@dolthub.com cert-authority openpgp AAAA"))
+	require.Error(t, err)
+}
+
+func openPGPAllowedSigner(t *testing.T, principals string) string {
+	data, err := os.ReadFile(testOpenPGPKeyPath)
+	require.NoError(t, err)
+	entity, err := readOpenPGPPrivateKey(data)
+	require.NoError(t, err)
+
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, entity.Serialize(buf))
+	return principals + " openpgp " + base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func writeTestSSHKey(t *testing.T) (string, ssh.PublicKey) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(priv, "test key")
+	require.NoError(t, err)
+
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(block), 0600))
+
+	signer, err := ssh.NewSignerFromKey(priv)
+	require.NoError(t, err)
+	return keyPath, signer.PublicKey()
+}
+
+func TestVerifyWithGPGKeyring(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, ImportKey(ctx, testOpenPGPKeyPath))
+
+	signed, err := Sign(ctx, testOpenPGPFingerprint, []byte("I did a thing"))
+	require.NoError(t, err)
+
+	v := VerifySignature(signed, nil, "test@dolthub.com")
+	require.Equal(t, SignatureStatusUnknownKey, v.Status)
+
+	var signers *AllowedSigners
+	v = VerifySignature(signed, signers.WithGPGKeyring(), "test@dolthub.com")
+	require.Equal(t, SignatureStatusGood, v.Status, v.String())
+	require.Equal(t, "Test User <test@dolthub.com>", v.Principal)
+
+	v = VerifySignature(signed, signers.WithGPGKeyring(), "test@example.com")
+	require.Equal(t, SignatureStatusUntrusted, v.Status)
+}
@@ -18,6 +18,8 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"strings"
+	"time"
 )
 
 // Blobstore is an interface for storing and retrieving blobs of data by key
@@ -39,6 +41,26 @@ type Blobstore interface {
 
 	// Concatenate creates a new blob named |key| by concatenating |sources|.
 	Concatenate(ctx context.Context, key string, sources []string) (version string, err error)
+
+	// List returns the key and modification time of every blob in the blobstore.
+	List(ctx context.Context) (blobs []BlobInfo, err error)
+
+	// Delete removes the blob keyed by |key|. Deleting a blob that does not exist is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// BlobInfo describes a blob returned by Blobstore.List.
+type BlobInfo struct {
+	Key      string
+	Modified time.Time
+}
+
+// listPrefix returns the prefix of the absolute keys of the blobs in a blobstore whose keys are joined to |prefix|.
+func listPrefix(prefix string) string {
+	if prefix == "" {
+		return ""
+	}
+	return strings.TrimSuffix(prefix, "/") + "/"
 }
 
 // GetBytes is a utility method calls bs.Get and handles reading the data from the returned
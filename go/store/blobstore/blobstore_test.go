@@ -468,6 +468,50 @@ func testConcatenate(t *testing.T, bs Blobstore, cnt int) {
 	}
 }
 
+func TestListAndDelete(t *testing.T) {
+	for _, bsTest := range newBlobStoreTests() {
+		t.Run(bsTest.bsType, func(t *testing.T) {
+			testListAndDelete(t, bsTest.bs)
+		})
+	}
+}
+
+func testListAndDelete(t *testing.T, bs Blobstore) {
+	ctx := context.Background()
+	keys := []string{uuid.New().String(), uuid.New().String()}
+	for _, k := range keys {
+		_, err := PutBytes(ctx, bs, k, randBytes(32))
+		require.NoError(t, err)
+	}
+
+	listed := func() map[string]BlobInfo {
+		blobs, err := bs.List(ctx)
+		require.NoError(t, err)
+		m := make(map[string]BlobInfo, len(blobs))
+		for _, b := range blobs {
+			m[b.Key] = b
+		}
+		return m
+	}
+
+	blobs := listed()
+	for _, k := range keys {
+		require.Contains(t, blobs, k)
+		assert.False(t, blobs[k].Modified.IsZero())
+	}
+
+	require.NoError(t, bs.Delete(ctx, keys[0]))
+	blobs = listed()
+	assert.NotContains(t, blobs, keys[0])
+	assert.Contains(t, blobs, keys[1])
+	ok, err := bs.Exists(ctx, keys[0])
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// deleting a missing blob is not an error
+	require.NoError(t, bs.Delete(ctx, keys[0]))
+}
+
 func blobName(b []byte) string {
 	h := maphash.Bytes(maphash.MakeSeed(), b)
 	return strconv.Itoa(int(h))
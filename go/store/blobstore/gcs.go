@@ -20,11 +20,13 @@ import (
 	"io"
 	"path"
 	"strconv"
+	"strings"
 
 	"cloud.google.com/go/storage"
 	"github.com/google/uuid"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/api/googleapi"
+	"google.golang.org/api/iterator"
 )
 
 const (
@@ -241,3 +243,29 @@ func min(l, r int) (m int) {
 	}
 	return
 }
+
+// List returns the key and modification time of every blob in the blobstore.
+func (bs *GCSBlobstore) List(ctx context.Context) ([]BlobInfo, error) {
+	prefix := listPrefix(bs.prefix)
+	it := bs.bucket.Objects(ctx, &storage.Query{Prefix: prefix})
+
+	var blobs []BlobInfo
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			return blobs, nil
+		} else if err != nil {
+			return nil, err
+		}
+		blobs = append(blobs, BlobInfo{Key: strings.TrimPrefix(attrs.Name, prefix), Modified: attrs.Updated})
+	}
+}
+
+// Delete removes the blob keyed by |key|.
+func (bs *GCSBlobstore) Delete(ctx context.Context, key string) error {
+	err := bs.bucket.Object(path.Join(bs.prefix, key)).Delete(ctx)
+	if err == storage.ErrObjectNotExist {
+		return nil
+	}
+	return err
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io"
 	"sync"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
@@ -42,6 +43,7 @@ type InMemoryBlobstore struct {
 	mutex    sync.RWMutex
 	blobs    map[string][]byte
 	versions map[string]string
+	modified map[string]time.Time
 }
 
 var _ Blobstore = &InMemoryBlobstore{}
@@ -52,6 +54,7 @@ func NewInMemoryBlobstore(path string) *InMemoryBlobstore {
 		path:     path,
 		blobs:    make(map[string][]byte),
 		versions: make(map[string]string),
+		modified: make(map[string]time.Time),
 	}
 }
 
@@ -157,6 +160,29 @@ func (bs *InMemoryBlobstore) Concatenate(ctx context.Context, key string, source
 	return bs.Put(ctx, key, int64(len(blob)), bytes.NewReader(blob))
 }
 
+// List returns the key and modification time of every blob in the blobstore.
+func (bs *InMemoryBlobstore) List(ctx context.Context) ([]BlobInfo, error) {
+	bs.mutex.RLock()
+	defer bs.mutex.RUnlock()
+
+	blobs := make([]BlobInfo, 0, len(bs.blobs))
+	for key := range bs.blobs {
+		blobs = append(blobs, BlobInfo{Key: key, Modified: bs.modified[key]})
+	}
+	return blobs, nil
+}
+
+// Delete removes the blob keyed by |key|.
+func (bs *InMemoryBlobstore) Delete(ctx context.Context, key string) error {
+	bs.mutex.Lock()
+	defer bs.mutex.Unlock()
+
+	delete(bs.blobs, key)
+	delete(bs.versions, key)
+	delete(bs.modified, key)
+	return nil
+}
+
 func (bs *InMemoryBlobstore) put(ctx context.Context, key string, reader io.Reader) (string, error) {
 	ver := uuid.New().String()
 	data, err := io.ReadAll(reader)
@@ -167,6 +193,7 @@ func (bs *InMemoryBlobstore) put(ctx context.Context, key string, reader io.Read
 
 	bs.blobs[key] = data
 	bs.versions[key] = ver
+	bs.modified[key] = time.Now()
 
 	return ver, nil
 }
@@ -20,6 +20,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/dolthub/fslock"
@@ -244,3 +245,35 @@ func (bs *LocalBlobstore) Concatenate(ctx context.Context, key string, sources [
 	}
 	return
 }
+
+// List returns the key and modification time of every blob in the blobstore.
+func (bs *LocalBlobstore) List(ctx context.Context) ([]BlobInfo, error) {
+	entries, err := os.ReadDir(bs.RootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var blobs []BlobInfo
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), bsExt) {
+			continue
+		}
+		info, err := e.Info()
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		blobs = append(blobs, BlobInfo{Key: strings.TrimSuffix(e.Name(), bsExt), Modified: info.ModTime()})
+	}
+	return blobs, nil
+}
+
+// Delete removes the blob keyed by |key|.
+func (bs *LocalBlobstore) Delete(ctx context.Context, key string) error {
+	err := os.Remove(filepath.Join(bs.RootDir, key) + bsExt)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -24,6 +24,7 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"strings"
 
 	"github.com/oracle/oci-go-sdk/v65/common"
 	"github.com/oracle/oci-go-sdk/v65/objectstorage"
@@ -168,6 +169,51 @@ func (bs *OCIBlobstore) Concatenate(ctx context.Context, key string, sources []s
 	return "", fmt.Errorf("concatenate is unimplemented on the oci blobstore")
 }
 
+// List returns the key and modification time of every blob in the blobstore.
+func (bs *OCIBlobstore) List(ctx context.Context) ([]BlobInfo, error) {
+	prefix := listPrefix(bs.prefix)
+	fields := "name,timeModified"
+	req := objectstorage.ListObjectsRequest{
+		NamespaceName: &bs.namespace,
+		BucketName:    &bs.bucketName,
+		Prefix:        &prefix,
+		Fields:        &fields,
+	}
+
+	var blobs []BlobInfo
+	for {
+		res, err := bs.client.ListObjects(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range res.Objects {
+			info := BlobInfo{Key: strings.TrimPrefix(fmtstr(obj.Name), prefix)}
+			if obj.TimeModified != nil {
+				info.Modified = obj.TimeModified.Time
+			}
+			blobs = append(blobs, info)
+		}
+		if res.NextStartWith == nil {
+			return blobs, nil
+		}
+		req.Start = res.NextStartWith
+	}
+}
+
+// Delete removes the blob keyed by |key|.
+func (bs *OCIBlobstore) Delete(ctx context.Context, key string) error {
+	absKey := path.Join(bs.prefix, key)
+	_, err := bs.client.DeleteObject(ctx, objectstorage.DeleteObjectRequest{
+		NamespaceName: &bs.namespace,
+		BucketName:    &bs.bucketName,
+		ObjectName:    &absKey,
+	})
+	if serr, ok := common.IsServiceError(err); ok && serr.GetHTTPStatusCode() == 404 {
+		return nil
+	}
+	return err
+}
+
 func (bs *OCIBlobstore) upload(ctx context.Context, expectedVersion, key string, totalSize int64, reader io.Reader) (string, error) {
 	numParts, _ := getNumPartsAndPartSize(totalSize, defaultPartSize, maxPartNum)
 	if totalSize == 0 {
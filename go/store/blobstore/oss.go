@@ -21,6 +21,7 @@ import (
 	"net/http"
 	"path"
 	"strconv"
+	"strings"
 
 	"github.com/aliyun/aliyun-oss-go-sdk/oss"
 )
@@ -126,6 +127,36 @@ func (ob *OSSBlobstore) Concatenate(ctx context.Context, key string, sources []s
 	return "", fmt.Errorf("Conjoin is not implemented for OSSBlobstore")
 }
 
+// List returns the key and modification time of every blob in the blobstore.
+func (ob *OSSBlobstore) List(ctx context.Context) ([]BlobInfo, error) {
+	prefix := listPrefix(ob.prefix)
+	options := []oss.Option{oss.Prefix(prefix)}
+
+	var blobs []BlobInfo
+	for {
+		res, err := ob.bucket.ListObjectsV2(options...)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range res.Objects {
+			blobs = append(blobs, BlobInfo{Key: strings.TrimPrefix(obj.Key, prefix), Modified: obj.LastModified})
+		}
+		if !res.IsTruncated {
+			return blobs, nil
+		}
+		options = []oss.Option{oss.Prefix(prefix), oss.ContinuationToken(res.NextContinuationToken)}
+	}
+}
+
+// Delete removes the blob keyed by |key|.
+func (ob *OSSBlobstore) Delete(ctx context.Context, key string) error {
+	err := ob.bucket.DeleteObject(ob.absKey(key))
+	if isNotFoundErr(err) {
+		return nil
+	}
+	return err
+}
+
 func (ob *OSSBlobstore) absKey(key string) string {
 	return path.Join(ob.prefix, key)
 }
@@ -0,0 +1,169 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+const s3UploadPartSize = 64 * 1024 * 1024
+
+// S3Blobstore provides an AWS S3 implementation of the Blobstore interface. S3 does not support conditional writes or
+// composing objects, so CheckAndPut and Concatenate are not implemented, and S3Blobstore can't be used to store a
+// manifest.
+type S3Blobstore struct {
+	s3     s3iface.S3API
+	bucket string
+	prefix string
+}
+
+var _ Blobstore = &S3Blobstore{}
+
+// NewS3Blobstore creates a new instance of an S3Blobstore
+func NewS3Blobstore(s3 s3iface.S3API, bucket, prefix string) *S3Blobstore {
+	for len(prefix) > 0 && prefix[0] == '/' {
+		prefix = prefix[1:]
+	}
+	return &S3Blobstore{s3, bucket, prefix}
+}
+
+func (bs *S3Blobstore) Path() string {
+	return path.Join(bs.bucket, bs.prefix)
+}
+
+// Exists returns true if a blob exists for the given key, and false if it does not.
+func (bs *S3Blobstore) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := bs.s3.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bs.bucket),
+		Key:    aws.String(path.Join(bs.prefix, key)),
+	})
+	if isS3NotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Get retrieves an io.reader for the portion of a blob specified by br along with
+// its version
+func (bs *S3Blobstore) Get(ctx context.Context, key string, br BlobRange) (io.ReadCloser, string, error) {
+	absKey := path.Join(bs.prefix, key)
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(bs.bucket),
+		Key:    aws.String(absKey),
+	}
+	if !br.isAllRange() {
+		input.Range = aws.String(s3RangeHeader(br))
+	}
+
+	out, err := bs.s3.GetObjectWithContext(ctx, input)
+	if isS3NotFound(err) {
+		return nil, "", NotFound{"s3://" + path.Join(bs.bucket, absKey)}
+	} else if err != nil {
+		return nil, "", err
+	}
+	return out.Body, aws.StringValue(out.ETag), nil
+}
+
+// s3RangeHeader returns the HTTP Range header for |br|.
+func s3RangeHeader(br BlobRange) string {
+	if br.offset < 0 {
+		return fmt.Sprintf("bytes=%d", br.offset)
+	} else if br.length == 0 {
+		return fmt.Sprintf("bytes=%d-", br.offset)
+	}
+	return fmt.Sprintf("bytes=%d-%d", br.offset, br.offset+br.length-1)
+}
+
+// Put sets the blob and the version for a key
+func (bs *S3Blobstore) Put(ctx context.Context, key string, totalSize int64, reader io.Reader) (string, error) {
+	uploader := s3manager.NewUploaderWithClient(bs.s3, func(u *s3manager.Uploader) {
+		u.PartSize = s3UploadPartSize
+	})
+	out, err := uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket: aws.String(bs.bucket),
+		Key:    aws.String(path.Join(bs.prefix, key)),
+		Body:   reader,
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(out.VersionID), nil
+}
+
+// CheckAndPut is not implemented, since S3 does not support conditional writes.
+func (bs *S3Blobstore) CheckAndPut(ctx context.Context, expectedVersion, key string, totalSize int64, reader io.Reader) (string, error) {
+	return "", fmt.Errorf("check and put is unimplemented on the s3 blobstore")
+}
+
+// Concatenate is not implemented. S3 can only compose objects through multipart uploads, which require every part
+// but the last to be at least 5MiB.
+func (bs *S3Blobstore) Concatenate(ctx context.Context, key string, sources []string) (string, error) {
+	return "", fmt.Errorf("concatenate is unimplemented on the s3 blobstore")
+}
+
+// List returns the key and modification time of every blob in the blobstore.
+func (bs *S3Blobstore) List(ctx context.Context) ([]BlobInfo, error) {
+	prefix := listPrefix(bs.prefix)
+	var blobs []BlobInfo
+	err := bs.s3.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bs.bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, obj := range page.Contents {
+			blobs = append(blobs, BlobInfo{
+				Key:      strings.TrimPrefix(aws.StringValue(obj.Key), prefix),
+				Modified: aws.TimeValue(obj.LastModified),
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return blobs, nil
+}
+
+// Delete removes the blob keyed by |key|.
+func (bs *S3Blobstore) Delete(ctx context.Context, key string) error {
+	_, err := bs.s3.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(bs.bucket),
+		Key:    aws.String(path.Join(bs.prefix, key)),
+	})
+	if isS3NotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func isS3NotFound(err error) bool {
+	if rf, ok := err.(awserr.RequestFailure); ok {
+		return rf.StatusCode() == http.StatusNotFound
+	}
+	if ae, ok := err.(awserr.Error); ok {
+		return ae.Code() == s3.ErrCodeNoSuchKey
+	}
+	return false
+}
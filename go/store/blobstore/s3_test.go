@@ -0,0 +1,28 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestS3RangeHeader(t *testing.T) {
+	assert.Equal(t, "bytes=-100", s3RangeHeader(NewBlobRange(-100, 0)))
+	assert.Equal(t, "bytes=-100", s3RangeHeader(NewBlobRange(-100, 10)))
+	assert.Equal(t, "bytes=10-", s3RangeHeader(NewBlobRange(10, 0)))
+	assert.Equal(t, "bytes=10-19", s3RangeHeader(NewBlobRange(10, 10)))
+}
@@ -125,8 +125,8 @@ type ChunkStore interface {
 
 	// PersistGhostHashes is used to persist a set of addresses that are known to exist, but
 	// are not currently stored here. Only the GenerationalChunkStore implementation allows use of this method, as
-	// shallow clones are only allowed in local copies currently. Note that at the application level, the only
-	// hashes which can be ghosted are commit ids, but the chunk store doesn't know what those are.
+	// shallow and partial clones are only allowed in local copies currently. Note that at the application level, the only
+	// hashes which can be ghosted are commit ids and table addresses, but the chunk store doesn't know what those are.
 	PersistGhostHashes(ctx context.Context, refs hash.HashSet) error
 
 	// Close tears down any resources in use by the implementation. After
@@ -243,6 +243,18 @@ type GenerationalCS interface {
 	GhostGen() ChunkStore
 }
 
+// GhostChunkStore is implemented by the ghost generation of a GenerationalCS, which can forget ghost hashes once the
+// chunks they stand for are fetched.
+type GhostChunkStore interface {
+	ChunkStore
+
+	// DeleteGhostHashes removes the given addresses from the set persisted with PersistGhostHashes.
+	DeleteGhostHashes(ctx context.Context, refs hash.HashSet) error
+
+	// GhostCount returns the number of ghost addresses.
+	GhostCount() int
+}
+
 var ErrUnsupportedOperation = errors.New("operation not supported")
 
 var ErrGCGenerationExpired = errors.New("garbage collection generation expired")
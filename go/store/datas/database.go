@@ -110,6 +110,13 @@ type Database interface {
 	// SetStatsRef updates the singleton statisics ref for this database.
 	SetStatsRef(context.Context, Dataset, hash.Hash) (Dataset, error)
 
+	// SetMapHead sets the head of the dataset given to the root node of a
+	// prolly map, |mapAddr|. If the dataset already had a head, it must
+	// match |prevHash| or this method returns ErrOptimisticLockFailed and
+	// the caller must retry. Use an empty |prevHash| for a dataset which
+	// is expected not to exist.
+	SetMapHead(ctx context.Context, ds Dataset, mapAddr hash.Hash, prevHash hash.Hash) (Dataset, error)
+
 	// UpdateWorkingSet updates the dataset given, setting its value to a new
 	// working set value object with the ref and meta given. If the dataset given
 	// already had a value, it must match the hash given or this method returns
@@ -172,9 +179,17 @@ type Database interface {
 
 	// PersistGhostCommitIDs persists the given set of ghost commit IDs to the storage layer of the database. Ghost
 	// commits are commits which are real but have not been replicated to this instance of the database. Currently,
-	// it is only appropriate to use this method during a shallow clone operation.
+	// it is only appropriate to use this method during a shallow or partial clone operation, which also uses it to
+	// record the addresses of the tables it didn't fetch.
 	PersistGhostCommitIDs(ctx context.Context, ghosts hash.HashSet) error
 
+	// DeleteGhostHashes removes the given addresses from the set of ghosts of the database, so that they can be fetched.
+	DeleteGhostHashes(ctx context.Context, ghosts hash.HashSet) error
+
+	// HasGhosts returns whether the database has ghosts, the commits skipped by a shallow clone or the tables skipped
+	// by a partial clone.
+	HasGhosts(ctx context.Context) (bool, error)
+
 	// chunkStore returns the ChunkStore used to read and write
 	// groups of values to the database efficiently. This interface is a low-
 	// level detail of the database that should infrequently be needed by
@@ -784,6 +784,29 @@ func (db *database) SetStatsRef(ctx context.Context, ds Dataset, mapAddr hash.Ha
 	})
 }
 
+func (db *database) SetMapHead(ctx context.Context, ds Dataset, mapAddr hash.Hash, prevHash hash.Hash) (Dataset, error) {
+	return db.doHeadUpdate(ctx, ds, func(ds Dataset) error {
+		return db.update(ctx, func(_ context.Context, datasets types.Map) (types.Map, error) {
+			// this is for old format, so this should not happen
+			return datasets, errors.New("SetMapHead: maps are not supported for old storage format")
+		}, func(ctx context.Context, am prolly.AddressMap) (prolly.AddressMap, error) {
+			curr, err := am.Get(ctx, ds.ID())
+			if err != nil {
+				return prolly.AddressMap{}, err
+			}
+			if curr != prevHash {
+				return prolly.AddressMap{}, ErrOptimisticLockFailed
+			}
+			ae := am.Editor()
+			err = ae.Update(ctx, ds.ID(), mapAddr)
+			if err != nil {
+				return prolly.AddressMap{}, err
+			}
+			return ae.Flush(ctx)
+		})
+	})
+}
+
 // UpdateStashList updates the stash list dataset only with given address hash to the updated stash list.
 // The new/updated stash list address should be obtained before calling this function depending on
 // whether add or remove a stash actions have been performed. This function does not perform any actions
@@ -885,6 +908,33 @@ func (db *database) PersistGhostCommitIDs(ctx context.Context, ghosts hash.HashS
 	return err
 }
 
+func (db *database) DeleteGhostHashes(ctx context.Context, ghosts hash.HashSet) error {
+	cs := db.ChunkStore()
+
+	gcs, ok := cs.(chunks.GenerationalCS)
+	if !ok {
+		return errors.New("Generational Chunk Store expected. database does not support partial clone instances.")
+	}
+	ggs, ok := gcs.GhostGen().(chunks.GhostChunkStore)
+	if !ok {
+		return errors.New("database does not support partial clone instances.")
+	}
+
+	return ggs.DeleteGhostHashes(ctx, ghosts)
+}
+
+func (db *database) HasGhosts(ctx context.Context) (bool, error) {
+	gcs, ok := db.ChunkStore().(chunks.GenerationalCS)
+	if !ok {
+		return false, nil
+	}
+	ggs, ok := gcs.GhostGen().(chunks.GhostChunkStore)
+	if !ok {
+		return false, nil
+	}
+	return ggs.GhostCount() > 0, nil
+}
+
 // CommitWithWorkingSet updates two Datasets atomically: the working set, and its corresponding HEAD. Uses the same
 // global locking mechanism as UpdateWorkingSet.
 // The current dataset head will be filled in as the first parent of the new commit if not already present.
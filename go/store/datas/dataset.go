@@ -376,6 +376,7 @@ func (h serialTagHead) HeadTag() (*TagMeta, hash.Hash, error) {
 		Timestamp:     h.msg.TimestampMillis(),
 		Description:   string(h.msg.Desc()),
 		UserTimestamp: h.msg.UserTimestampMillis(),
+		Signature:     string(h.msg.Signature()),
 	}
 	return meta, addr, nil
 }
@@ -594,6 +595,40 @@ func (s tupleHead) value() types.Value {
 	return s.msg
 }
 
+const mapName = "Map"
+
+type mapHead struct {
+	msg  types.SerialMessage
+	addr hash.Hash
+}
+
+var _ dsHead = mapHead{}
+
+// TypeName implements dsHead
+func (s mapHead) TypeName() string {
+	return mapName
+}
+
+// Addr implements dsHead
+func (s mapHead) Addr() hash.Hash {
+	return s.addr
+}
+
+// HeadTag implements dsHead
+func (s mapHead) HeadTag() (*TagMeta, hash.Hash, error) {
+	return nil, hash.Hash{}, errors.New("HeadTag called on map")
+}
+
+// HeadWorkingSet implements dsHead
+func (s mapHead) HeadWorkingSet() (*WorkingSetHead, error) {
+	return nil, errors.New("HeadWorkingSet called on map")
+}
+
+// value implements dsHead
+func (s mapHead) value() types.Value {
+	return s.msg
+}
+
 // Dataset is a named value within a Database. Different head values may be stored in a dataset. Most commonly, this is
 // a commit, but other values are also supported in some cases.
 type Dataset struct {
@@ -653,6 +688,8 @@ func newHead(ctx context.Context, head types.Value, addr hash.Hash) (dsHead, err
 			return newStatisticHead(sm, addr), nil
 		case serial.TupleFileID:
 			return newTupleHead(sm, addr), nil
+		case serial.ProllyTreeNodeFileID:
+			return mapHead{sm, addr}, nil
 		}
 	}
 
@@ -752,6 +789,11 @@ func (ds Dataset) IsTag() bool {
 	return ds.head != nil && ds.head.TypeName() == tagName
 }
 
+// IsMap returns whether the head of the dataset is the root of a prolly map, written with Database.SetMapHead.
+func (ds Dataset) IsMap() bool {
+	return ds.head != nil && ds.head.TypeName() == mapName
+}
+
 func (ds Dataset) IsWorkingSet() bool {
 	return ds.head != nil && ds.head.TypeName() == workingSetName
 }
@@ -27,12 +27,17 @@ import (
 	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
 	"github.com/dolthub/dolt/go/store/chunks"
 	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/nbs"
 )
 
 var ErrNoData = errors.New("no data")
 var ErrCloneUnsupported = errors.New("clone unsupported")
 
 func Clone(ctx context.Context, srcCS, sinkCS chunks.ChunkStore, eventCh chan<- TableFileEvent) error {
+	if err := nbs.CheckEncryptedDestination(srcCS, sinkCS); err != nil {
+		return err
+	}
+
 	srcTS, srcOK := srcCS.(chunks.TableFileStore)
 
 	if !srcOK {
@@ -71,6 +71,9 @@ type PullTableFileWriterConfig struct {
 
 	TempDir string
 
+	// EncryptTempFiles encrypts the temporary table files written in TempDir, for destinations encrypted at rest.
+	EncryptTempFiles bool
+
 	DestStore DestTableFileStore
 }
 
@@ -229,7 +232,11 @@ LOOP:
 			}
 
 			if curWr == nil {
-				curWr, err = nbs.NewCmpChunkTableWriter(w.cfg.TempDir)
+				if w.cfg.EncryptTempFiles {
+					curWr, err = nbs.NewEncryptedCmpChunkTableWriter(w.cfg.TempDir)
+				} else {
+					curWr, err = nbs.NewCmpChunkTableWriter(w.cfg.TempDir)
+				}
 				if err != nil {
 					return err
 				}
@@ -96,6 +96,10 @@ func NewPuller(
 		return nil, fmt.Errorf("cannot pull from src to sink; src version is %v and sink version is %v", srcCS.Version(), sinkCS.Version())
 	}
 
+	if err := nbs.CheckEncryptedDestination(srcCS, sinkCS); err != nil {
+		return nil, err
+	}
+
 	srcChunkStore, ok := srcCS.(nbs.NBSCompressedChunkStore)
 	if !ok {
 		return nil, ErrIncompatibleSourceChunkStore
@@ -106,6 +110,7 @@ func NewPuller(
 		ChunksPerFile:        chunksPerTF,
 		MaximumBufferedFiles: 8,
 		TempDir:              tempDir,
+		EncryptTempFiles:     nbs.EncryptsAtRest(sinkCS),
 		DestStore:            sinkCS.(chunks.TableFileStore),
 	})
 
@@ -29,6 +29,7 @@ const (
 	tagMetaTimestampKey = "timestamp"
 	tagMetaUserTSKey    = "user_timestamp"
 	tagMetaVersionKey   = "metaversion"
+	tagMetaSignatureKey = "signature"
 
 	tagMetaStName  = "metadata"
 	tagMetaVersion = "1.0"
@@ -44,6 +45,7 @@ type TagMeta struct {
 	Timestamp     uint64
 	Description   string
 	UserTimestamp int64
+	Signature     string
 }
 
 // NewTagMetaWithUserTS returns TagMeta that can be used to create a tag.
@@ -61,7 +63,7 @@ func NewTagMetaWithUserTS(name, email, desc string, userTS time.Time) *TagMeta {
 	ms := uint64(TagNowFunc().UnixMilli())
 	userMS := userTS.UnixMilli()
 
-	return &TagMeta{n, e, ms, d, userMS}
+	return &TagMeta{Name: n, Email: e, Timestamp: ms, Description: d, UserTimestamp: userMS}
 }
 
 func tagMetaFromNomsSt(st types.Struct) (*TagMeta, error) {
@@ -97,12 +99,21 @@ func tagMetaFromNomsSt(st types.Struct) (*TagMeta, error) {
 		userTS = types.Int(int64(uint64(ts.(types.Uint))))
 	}
 
+	signature, ok, err := st.MaybeGet(tagMetaSignatureKey)
+
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		signature = types.String("")
+	}
+
 	return &TagMeta{
-		string(n.(types.String)),
-		string(e.(types.String)),
-		uint64(ts.(types.Uint)),
-		string(d.(types.String)),
-		int64(userTS.(types.Int)),
+		Name:          string(n.(types.String)),
+		Email:         string(e.(types.String)),
+		Timestamp:     uint64(ts.(types.Uint)),
+		Description:   string(d.(types.String)),
+		UserTimestamp: int64(userTS.(types.Int)),
+		Signature:     string(signature.(types.String)),
 	}, nil
 }
 
@@ -116,6 +127,10 @@ func (tm *TagMeta) toNomsStruct(nbf *types.NomsBinFormat) (types.Struct, error)
 		commitMetaUserTSKey: types.Int(tm.UserTimestamp),
 	}
 
+	if tm.Signature != "" {
+		metadata[tagMetaSignatureKey] = types.String(tm.Signature)
+	}
+
 	return types.NewStruct(nbf, tagMetaStName, metadata)
 }
 
@@ -34,15 +34,24 @@ import (
 	"github.com/dolthub/dolt/go/cmd/dolt/doltversion"
 	"github.com/dolthub/dolt/go/store/chunks"
 	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/util/tempfiles"
 )
 
 const defaultDictionarySize = 1 << 12 // NM4 - maybe just select the largest chunk. TBD.
 const maxSamples = 1000
 const minSamples = 25
 
+// errOldGenNotLocal is returned when archiving or unarchiving a store whose old generation is neither on local disk nor
+// in tiered storage.
+var errOldGenNotLocal = errors.New("archives can only be built in an old generation stored on local disk or in tiered storage")
+
 func UnArchive(ctx context.Context, cs chunks.ChunkStore, smd StorageMetadata, progress chan interface{}) error {
 	if gs, ok := cs.(*GenerationalNBS); ok {
-		outPath, _ := gs.oldGen.Path()
+		outPath, ok := gs.oldGen.Path()
+		tp, tiered := gs.oldGen.p.(*tieredTablePersister)
+		if !ok && !tiered {
+			return errOldGenNotLocal
+		}
 		oldgen := gs.oldGen.tables.upstream
 
 		swapMap := make(map[hash.Hash]hash.Hash)
@@ -51,8 +60,22 @@ func UnArchive(ctx context.Context, cs chunks.ChunkStore, smd StorageMetadata, p
 
 		for id, ogcs := range oldgen {
 			if arc, ok := ogcs.(archiveChunkSource); ok {
-				orginTfId := revertMap[id]
-				exists, err := smd.oldGenTableExists(orginTfId)
+				var exists bool
+				var err error
+				orginTfId, ok := revertMap[id]
+				if !ok {
+					// The storage metadata only covers local files, so read the original table file id from the archive.
+					md, err := newArchiveMetadata(arc.aRdr.reader, arc.aRdr.footer.fileSize)
+					if err != nil {
+						return err
+					}
+					orginTfId, ok = hash.MaybeParse(md.originalTableFileId)
+				}
+				if ok && tiered {
+					exists, err = tp.bs.Exists(ctx, orginTfId.String())
+				} else if ok {
+					exists, err = smd.oldGenTableExists(orginTfId)
+				}
 				if err != nil {
 					return err
 				}
@@ -61,7 +84,7 @@ func UnArchive(ctx context.Context, cs chunks.ChunkStore, smd StorageMetadata, p
 					swapMap[arc.hash()] = orginTfId
 				} else {
 					// We don't have the original table file id, so we have to create a new one.
-					classicTable, err := NewCmpChunkTableWriter("")
+					classicTable, err := newCmpChunkTableWriter("", encryptionOf(gs.oldGen.p) != nil)
 					if err != nil {
 						return err
 					}
@@ -84,10 +107,20 @@ func UnArchive(ctx context.Context, cs chunks.ChunkStore, smd StorageMetadata, p
 					if err != nil {
 						return err
 					}
-					err = classicTable.FlushToFile(filepath.Join(outPath, id))
+					if tiered {
+						err = uploadTableFile(ctx, tp, classicTable, id)
+					} else {
+						err = flushSinkToEncryptedFile(ctx, encryptionOf(gs.oldGen.p), classicTable.sink, filepath.Join(outPath, id))
+					}
 					if err != nil {
 						return err
 					}
+					if tiered || encryptionOf(gs.oldGen.p) != nil {
+						// the unencrypted table file was copied or uploaded rather than moved into place
+						if err = classicTable.Remove(); err != nil {
+							return err
+						}
+					}
 
 					swapMap[arc.hash()] = hash.Parse(id)
 				}
@@ -119,7 +152,18 @@ func BuildArchive(ctx context.Context, cs chunks.ChunkStore, dagGroups *ChunkRel
 	var stats Stats
 
 	if gs, ok := cs.(*GenerationalNBS); ok {
-		outPath, _ := gs.oldGen.Path()
+		outPath, ok := gs.oldGen.Path()
+		tp, tiered := gs.oldGen.p.(*tieredTablePersister)
+		if tiered {
+			// Archives of a tiered old generation are built in a local temporary directory and uploaded once verified.
+			outPath, err = os.MkdirTemp(tempfiles.MovableTempFileProvider.GetTempDir(), "archive_")
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(outPath)
+		} else if !ok {
+			return errOldGenNotLocal
+		}
 		oldgen := gs.oldGen.tables.upstream
 
 		swapMap := make(map[hash.Hash]hash.Hash)
@@ -138,7 +182,7 @@ func BuildArchive(ctx context.Context, cs chunks.ChunkStore, dagGroups *ChunkRel
 
 			archivePath := ""
 			archiveName := hash.Hash{}
-			archivePath, archiveName, err = convertTableFileToArchive(ctx, ogcs, idx, dagGroups, outPath, progress, &stats)
+			archivePath, archiveName, err = convertTableFileToArchive(ctx, ogcs, idx, dagGroups, outPath, encryptionOf(gs.oldGen.p), progress, &stats)
 			if err != nil {
 				return err
 			}
@@ -150,11 +194,20 @@ func BuildArchive(ctx context.Context, cs chunks.ChunkStore, dagGroups *ChunkRel
 			}
 			archiveSize := fileInfo.Size()
 
-			err = verifyAllChunks(idx, archivePath, progress)
+			err = verifyAllChunks(ctx, idx, archivePath, encryptionOf(gs.oldGen.p), progress)
 			if err != nil {
 				return err
 			}
 
+			if tiered {
+				if err = tp.uploadArchive(ctx, archiveName, archivePath); err != nil {
+					return err
+				}
+				if err = os.Remove(archivePath); err != nil {
+					return err
+				}
+			}
+
 			percentReduction := -100.0 * (float64(archiveSize)/float64(originalSize) - 1.0)
 			progress <- fmt.Sprintf("Archived %s (%d -> %d bytes, %.2f%% reduction)", archiveName, originalSize, archiveSize, percentReduction)
 
@@ -185,12 +238,24 @@ func BuildArchive(ctx context.Context, cs chunks.ChunkStore, dagGroups *ChunkRel
 	return nil
 }
 
+// uploadTableFile uploads the finished table file written by |tw| to the Blobstore of |tp| as |name|.
+func uploadTableFile(ctx context.Context, tp *tieredTablePersister, tw *CmpChunkTableWriter, name string) error {
+	r, err := tw.Reader()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	return tp.CopyTableFile(ctx, r, name, tw.ContentLength(), uint32(tw.ChunkCount()))
+}
+
 func convertTableFileToArchive(
 	ctx context.Context,
 	cs chunkSource,
 	idx tableIndex,
 	dagGroups *ChunkRelations,
 	archivePath string,
+	enc *storeEncryption,
 	progress chan interface{},
 	stats *Stats,
 ) (string, hash.Hash, error) {
@@ -231,7 +296,7 @@ func convertTableFileToArchive(
 	cmpDefDict := gozstd.Compress(cmpBuff, defaultDict)
 	// p("Default Dict Raw vs Compressed: %d , %d\n", len(defaultDict), len(cmpDefDict))
 
-	arcW, err := newArchiveWriter()
+	arcW, err := newArchiveWriter(enc != nil)
 	if err != nil {
 		return "", hash.Hash{}, err
 	}
@@ -246,7 +311,7 @@ func convertTableFileToArchive(
 		return "", hash.Hash{}, err
 	}
 
-	err = indexAndFinalizeArchive(arcW, archivePath, cs.hash())
+	err = indexAndFinalizeArchive(ctx, arcW, archivePath, cs.hash(), enc)
 	if err != nil {
 		return "", hash.Hash{}, err
 	}
@@ -266,8 +331,9 @@ func convertTableFileToArchive(
 }
 
 // indexAndFinalizeArchive writes the index, metadata, and footer to the archive file. It also flushes the archive writer
-// to the directory provided. The name is calculated from the footer, and can be obtained by calling getName on the archive.
-func indexAndFinalizeArchive(arcW *archiveWriter, archivePath string, originTableFile hash.Hash) error {
+// to the directory provided, encrypting it if |enc| is non-nil. The name is calculated from the footer, and can be
+// obtained by calling getName on the archive.
+func indexAndFinalizeArchive(ctx context.Context, arcW *archiveWriter, archivePath string, originTableFile hash.Hash, enc *storeEncryption) error {
 	err := arcW.finalizeByteSpans()
 	if err != nil {
 		return err
@@ -303,7 +369,7 @@ func indexAndFinalizeArchive(arcW *archiveWriter, archivePath string, originTabl
 		return err
 	}
 
-	return arcW.flushToFile(fileName)
+	return arcW.flushToFile(ctx, enc, fileName)
 }
 
 func writeDataToArchive(
@@ -440,19 +506,13 @@ func gatherAllChunks(ctx context.Context, cs chunkSource, idx tableIndex, stats
 
 	return chkCache, defaultSamples, nil
 }
-func verifyAllChunks(idx tableIndex, archiveFile string, progress chan interface{}) error {
-	file, err := os.Open(archiveFile)
-	if err != nil {
-		return err
-	}
-
-	stat, err := file.Stat()
+func verifyAllChunks(ctx context.Context, idx tableIndex, archiveFile string, enc *storeEncryption, progress chan interface{}) error {
+	file, fileSize, err := openReader(ctx, archiveFile, enc)
 	if err != nil {
 		return err
 	}
-	fileSize := stat.Size()
 
-	index, err := newArchiveReader(file, uint64(fileSize))
+	index, err := newArchiveReader(file, fileSize)
 	if err != nil {
 		return err
 	}
@@ -31,14 +31,15 @@ import (
 type archiveChunkSource struct {
 	file string
 	aRdr archiveReader
+	enc  *storeEncryption
 }
 
 var _ chunkSource = &archiveChunkSource{}
 
-func newArchiveChunkSource(ctx context.Context, dir string, h hash.Hash, chunkCount uint32, q MemoryQuotaProvider) (archiveChunkSource, error) {
+func newArchiveChunkSource(ctx context.Context, dir string, h hash.Hash, chunkCount uint32, q MemoryQuotaProvider, enc *storeEncryption) (archiveChunkSource, error) {
 	archiveFile := filepath.Join(dir, h.String()+archiveFileSuffix)
 
-	file, size, err := openReader(archiveFile)
+	file, size, err := openReader(ctx, archiveFile, enc)
 	if err != nil {
 		return archiveChunkSource{}, err
 	}
@@ -47,10 +48,11 @@ func newArchiveChunkSource(ctx context.Context, dir string, h hash.Hash, chunkCo
 	if err != nil {
 		return archiveChunkSource{}, err
 	}
-	return archiveChunkSource{archiveFile, aRdr}, nil
+	return archiveChunkSource{archiveFile, aRdr, enc}, nil
 }
 
-func openReader(file string) (io.ReaderAt, uint64, error) {
+// openReader opens the archive |file|, decrypting it with |enc| if it is encrypted.
+func openReader(ctx context.Context, file string, enc *storeEncryption) (io.ReaderAt, uint64, error) {
 	f, err := os.Open(file)
 	if err != nil {
 		return nil, 0, err
@@ -61,7 +63,14 @@ func openReader(file string) (io.ReaderAt, uint64, error) {
 		return nil, 0, err
 	}
 
-	return f, uint64(stat.Size()), nil
+	c, err := openFileCipher(ctx, enc, f)
+	if err != nil {
+		f.Close()
+		return nil, 0, err
+	}
+
+	r, sz := newDecryptingReaderAt(f, stat.Size(), c)
+	return r, uint64(sz), nil
 }
 
 func (acs archiveChunkSource) has(h hash.Hash) (bool, error) {
@@ -136,14 +145,19 @@ func (acs archiveChunkSource) index() (tableIndex, error) {
 }
 
 func (acs archiveChunkSource) clone() (chunkSource, error) {
-	newReader, _, err := openReader(acs.file)
+	if tr, ok := acs.aRdr.reader.(*tieredReaderAt); ok {
+		// archives in tiered storage are read through a shared, goroutine safe reader.
+		return archiveChunkSource{acs.file, acs.aRdr.clone(tr), acs.enc}, nil
+	}
+
+	newReader, _, err := openReader(context.Background(), acs.file, acs.enc)
 	if err != nil {
 		return nil, err
 	}
 
 	rdr := acs.aRdr.clone(newReader)
 
-	return archiveChunkSource{acs.file, rdr}, nil
+	return archiveChunkSource{acs.file, rdr, acs.enc}, nil
 }
 
 func (acs archiveChunkSource) getRecordRanges(_ context.Context, _ []getRecord) (map[hash.Hash]Range, error) {
@@ -16,10 +16,12 @@ package nbs
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha512"
 	"encoding/binary"
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
 	"sort"
 
@@ -81,8 +83,9 @@ to complete the archive writing process.
 */
 
 // newArchiveWriter - Create an *archiveWriter with the given output ByteSync, which will be used to materialize an archive on disk.
-func newArchiveWriter() (*archiveWriter, error) {
-	bs, err := NewBufferedFileByteSink("", defaultTableSinkBlockSize, defaultChBufferSize)
+// If |encrypt| is true, the archive is encrypted while it is being written, see newBufferedFileByteSink.
+func newArchiveWriter(encrypt bool) (*archiveWriter, error) {
+	bs, err := newBufferedFileByteSink("", defaultTableSinkBlockSize, defaultChBufferSize, encrypt)
 	if err != nil {
 		return nil, err
 	}
@@ -386,13 +389,14 @@ func writeVarUint64(w io.Writer, val uint64) error {
 }
 
 // flushToFile writes the archive to disk. The input is the directory where the file should be written, the file name
-// will be the footer hash + ".darc" as a suffix.
-func (aw *archiveWriter) flushToFile(fullPath string) error {
+// will be the footer hash + ".darc" as a suffix. If |enc| is non-nil, the archive is encrypted.
+func (aw *archiveWriter) flushToFile(ctx context.Context, enc *storeEncryption, fullPath string) error {
 	if aw.workflowStage != stageFlush {
 		return fmt.Errorf("Runtime error: flushToFile called out of order")
 	}
 
-	if bs, ok := aw.output.backingSink.(*BufferedFileByteSink); ok {
+	bs, ok := aw.output.backingSink.(*BufferedFileByteSink)
+	if ok {
 		err := bs.finish()
 		if err != nil {
 			return err
@@ -400,10 +404,16 @@ func (aw *archiveWriter) flushToFile(fullPath string) error {
 	}
 
 	aw.finalPath = fullPath
-	err := aw.output.FlushToFile(fullPath)
+	err := flushSinkToEncryptedFile(ctx, enc, aw.output, fullPath)
 	if err != nil {
 		return err
 	}
+	if ok && enc != nil {
+		// the unencrypted archive was copied rather than moved into place
+		if err = os.Remove(bs.path); err != nil {
+			return err
+		}
+	}
 	aw.workflowStage = stageDone
 	return nil
 }
@@ -182,10 +182,29 @@ type BufferedFileByteSink struct {
 
 	wr   io.WriteCloser
 	path string
+	// c encrypts the temporary file with an ephemeral key, or is nil if it is not encrypted
+	c *fileCipher
 }
 
+var errEncryptedTempFileMove = errors.New("cannot move an encrypted temporary file into place")
+
 // NewBufferedFileByteSink creates a BufferedFileByteSink
 func NewBufferedFileByteSink(tempDir string, blockSize, chBufferSize int) (*BufferedFileByteSink, error) {
+	return newBufferedFileByteSink(tempDir, blockSize, chBufferSize, false)
+}
+
+// newBufferedFileByteSink creates a BufferedFileByteSink. If |encrypt| is true, the temporary file it writes is
+// encrypted with an ephemeral key, which is never persisted, so that data written to a store encrypted at rest is
+// never written to disk in plaintext. The contents of an encrypted sink can't be moved into place with FlushToFile.
+func newBufferedFileByteSink(tempDir string, blockSize, chBufferSize int, encrypt bool) (*BufferedFileByteSink, error) {
+	var c *fileCipher
+	if encrypt {
+		var err error
+		if c, err = newEphemeralFileCipher(); err != nil {
+			return nil, err
+		}
+	}
+
 	f, err := tempfiles.MovableTempFileProvider.NewFile(tempDir, "buffered_file_byte_sink_")
 
 	if err != nil {
@@ -200,6 +219,7 @@ func NewBufferedFileByteSink(tempDir string, blockSize, chBufferSize int) (*Buff
 		wg:           &sync.WaitGroup{},
 		wr:           f,
 		path:         f.Name(),
+		c:            c,
 	}
 
 	sink.wg.Add(1)
@@ -240,11 +260,17 @@ func (sink *BufferedFileByteSink) Write(src []byte) (int, error) {
 
 func (sink *BufferedFileByteSink) backgroundWrite() {
 	var err error
+	var off int64
 	for buff := range sink.writeCh {
 		if err != nil {
 			continue // drain
 		}
 
+		if sink.c != nil {
+			// |buff| is owned by the sink once it's been sent, it is encrypted in place
+			sink.c.xorKeyStreamAt(buff, buff, off)
+			off += int64(len(buff))
+		}
 		err = iohelp.WriteAll(sink.wr, buff)
 		sink.ae.SetIfError(err)
 	}
@@ -278,22 +304,22 @@ func (sink *BufferedFileByteSink) Flush(wr io.Writer) (err error) {
 		return err
 	}
 
-	var f *os.File
-	f, err = os.Open(sink.path)
+	var r io.ReadCloser
+	r, err = sink.Reader()
 
 	if err != nil {
 		return err
 	}
 
 	defer func() {
-		closeErr := f.Close()
+		closeErr := r.Close()
 
 		if err == nil {
 			err = closeErr
 		}
 	}()
 
-	_, err = io.Copy(wr, f)
+	_, err = io.Copy(wr, r)
 
 	return err
 }
@@ -305,6 +331,9 @@ func (sink *BufferedFileByteSink) FlushToFile(path string) (err error) {
 		return err
 	}
 
+	if sink.c != nil {
+		return errEncryptedTempFileMove
+	}
 	return file.Rename(sink.path, path)
 }
 
@@ -313,7 +342,11 @@ func (sink *BufferedFileByteSink) Reader() (io.ReadCloser, error) {
 	if err != nil {
 		return nil, err
 	}
-	return os.Open(sink.path)
+	f, err := os.Open(sink.path)
+	if err != nil {
+		return nil, err
+	}
+	return newDecryptingReadCloser(f, sink.c)
 }
 
 // HashingByteSink is a ByteSink that keeps an md5 hash of all the data written to it.
@@ -16,6 +16,7 @@ package nbs
 
 import (
 	"bytes"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
@@ -75,6 +76,30 @@ func TestBufferedFileByteSink(t *testing.T) {
 	})
 }
 
+func TestEncryptedBufferedFileByteSink(t *testing.T) {
+	sink, err := newBufferedFileByteSink("", 4*1024, 16, true)
+	require.NoError(t, err)
+	require.NoError(t, writeToSink(sink))
+
+	bb := bytes.NewBuffer(nil)
+	require.NoError(t, sink.Flush(bb))
+	verifyContents(t, bb.Bytes())
+
+	r, err := sink.Reader()
+	require.NoError(t, err)
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	verifyContents(t, data)
+
+	// the temporary file is not written in plaintext, and can't be moved into place
+	temp, err := os.ReadFile(sink.path)
+	require.NoError(t, err)
+	assert.Len(t, temp, len(data))
+	assert.NotEqual(t, data, temp)
+	assert.ErrorIs(t, sink.FlushToFile(filepath.Join(t.TempDir(), "table")), errEncryptedTempFileMove)
+}
+
 type TableSinkSuite struct {
 	sinkFactory func() ByteSink
 	t           *testing.T
@@ -55,6 +55,37 @@ func (csa chunkSourceAdapter) clone() (chunkSource, error) {
 	return &chunkSourceAdapter{tr, csa.h}, nil
 }
 
+// iterateAllChunks reads each chunk of the table individually, in index order, since the underlying tableReaderAt
+// may not support seeking.
 func (csa chunkSourceAdapter) iterateAllChunks(ctx context.Context, cb func(chunks.Chunk)) error {
-	panic("unimplemented")
+	stats := NewStats()
+	count := csa.idx.chunkCount()
+	for i := uint32(0); i < count; i++ {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		var h hash.Hash
+		ie, err := csa.idx.indexEntry(i, &h)
+		if err != nil {
+			return err
+		}
+
+		buf := make([]byte, ie.Length())
+		if _, err = csa.r.ReadAtWithStats(ctx, buf, int64(ie.Offset()), stats); err != nil {
+			return err
+		}
+
+		cchk, err := NewCompressedChunk(h, buf)
+		if err != nil {
+			return err
+		}
+		chk, err := cchk.ToChunk()
+		if err != nil {
+			return err
+		}
+
+		cb(chk)
+	}
+	return nil
 }
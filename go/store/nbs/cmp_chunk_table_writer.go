@@ -52,7 +52,18 @@ type CmpChunkTableWriter struct {
 
 // NewCmpChunkTableWriter creates a new CmpChunkTableWriter instance with a default ByteSink
 func NewCmpChunkTableWriter(tempDir string) (*CmpChunkTableWriter, error) {
-	s, err := NewBufferedFileByteSink(tempDir, defaultTableSinkBlockSize, defaultChBufferSize)
+	return newCmpChunkTableWriter(tempDir, false)
+}
+
+// NewEncryptedCmpChunkTableWriter creates a new CmpChunkTableWriter instance whose temporary table file is encrypted
+// with an ephemeral key, for table files written to a store encrypted at rest. The table file must be read with
+// Reader or Flush, it can't be moved into place with FlushToFile.
+func NewEncryptedCmpChunkTableWriter(tempDir string) (*CmpChunkTableWriter, error) {
+	return newCmpChunkTableWriter(tempDir, true)
+}
+
+func newCmpChunkTableWriter(tempDir string, encrypt bool) (*CmpChunkTableWriter, error) {
+	s, err := newBufferedFileByteSink(tempDir, defaultTableSinkBlockSize, defaultChBufferSize, encrypt)
 	if err != nil {
 		return nil, err
 	}
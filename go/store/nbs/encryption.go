@@ -0,0 +1,681 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/dolthub/dolt/go/libraries/utils/file"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/util/tempfiles"
+)
+
+// Storage files (table files, archives and the chunk journal) written by a store with encryption at rest enabled
+// start with a header that identifies the data key used to encrypt the rest of the file:
+//
+//	magic (8 bytes)
+//	version (uint8)
+//	algorithm (uint8)
+//	master key ID length (uint16) and master key ID
+//	wrapped data key length (uint16) and wrapped data key
+//	IV (16 bytes, AES-256-CTR only)
+//
+// Every file has its own randomly generated data key, which is wrapped (encrypted) by the master key identified by
+// the key ID through the store's KeyProvider. All offsets used by the rest of NBS (table indexes, journal ranges,
+// etc) are offsets into the decrypted contents, not into the file.
+//
+// Table files and archives are written once and never modified, so their contents following the header are
+// encrypted with AES-256 in CTR mode, so that any byte range of the file can be decrypted independently. The chunk
+// journal is appended to in place, so it is encrypted as a sequence of AES-256-GCM frames, each sealed with a fresh
+// nonce (see journal_encryption.go).
+//
+// The magic number can't be confused with the start of a plaintext storage file: table files start with a snappy
+// varint that can't have five continuation bytes, archives start with a zstd frame and journals start with a
+// record length that is bounded by journalRecMaxSz.
+const (
+	encryptedFileMagic   = "\xff\xff\xff\xff\xffENC"
+	encryptedFileVersion = 1
+
+	encryptionAlgAES256CTR       = 1
+	encryptionAlgAES256GCMFramed = 2
+
+	dataKeySize = 32
+
+	// EncryptionConfigFileName is the name of the file in a store's directory that configures encryption at rest
+	// for the store.
+	EncryptionConfigFileName = "encryption.json"
+)
+
+// ErrEncryptionNotConfigured is returned when reading an encrypted storage file in a store that doesn't have
+// encryption at rest configured.
+var ErrEncryptionNotConfigured = errors.New("storage file is encrypted, but no encryption key provider is configured for this database")
+
+// EncryptionConfig is the configuration for encryption at rest of a store, persisted as JSON in the
+// EncryptionConfigFileName file in the store's directory.
+type EncryptionConfig struct {
+	// KeyProvider is the name of the registered KeyProvider that wraps and unwraps data keys, e.g. "file" or "exec".
+	KeyProvider string `json:"key_provider"`
+	// KeyFile is the path to the key file used by the "file" key provider.
+	KeyFile string `json:"key_file,omitempty"`
+	// Command is the command line of the plugin used by the "exec" key provider.
+	Command []string `json:"command,omitempty"`
+}
+
+// KeyProvider wraps and unwraps the data keys that encrypt storage files with the master keys it manages. A
+// KeyProvider may be backed by a local key file, or by an external key management service.
+type KeyProvider interface {
+	// WrapKey encrypts |dataKey| with the provider's current master key, and returns the ID of the master key along
+	// with the wrapped data key.
+	WrapKey(ctx context.Context, dataKey []byte) (keyID string, wrapped []byte, err error)
+	// UnwrapKey decrypts the data key |wrapped| with the master key identified by |keyID|.
+	UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+// KeyProviderFactory creates a KeyProvider from a store's encryption config.
+type KeyProviderFactory func(cfg EncryptionConfig) (KeyProvider, error)
+
+var keyProviders = struct {
+	sync.RWMutex
+	factories map[string]KeyProviderFactory
+}{factories: make(map[string]KeyProviderFactory)}
+
+// RegisterKeyProvider registers the KeyProviderFactory |factory| under |name|, so that stores whose encryption
+// config names it can use it. This allows integrating with key management services that aren't supported natively.
+func RegisterKeyProvider(name string, factory KeyProviderFactory) {
+	keyProviders.Lock()
+	defer keyProviders.Unlock()
+	keyProviders.factories[name] = factory
+}
+
+func newKeyProvider(cfg EncryptionConfig) (KeyProvider, error) {
+	keyProviders.RLock()
+	factory, ok := keyProviders.factories[cfg.KeyProvider]
+	keyProviders.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown encryption key provider: '%s'", cfg.KeyProvider)
+	}
+	return factory(cfg)
+}
+
+// LoadEncryptionConfig loads the encryption config of the store in |dir|. If encryption at rest is not configured
+// for the store, nil is returned.
+func LoadEncryptionConfig(dir string) (*EncryptionConfig, error) {
+	b, err := os.ReadFile(filepath.Join(dir, EncryptionConfigFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var cfg EncryptionConfig
+	if err = json.Unmarshal(b, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid encryption config %s: %w", filepath.Join(dir, EncryptionConfigFileName), err)
+	}
+	return &cfg, nil
+}
+
+// WriteEncryptionConfig writes |cfg| as the encryption config of the store in |dir|. Storage files written to the
+// store after this are encrypted; existing storage files are encrypted when they are rewritten by garbage collection.
+func WriteEncryptionConfig(dir string, cfg EncryptionConfig) error {
+	if _, err := newKeyProvider(cfg); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, EncryptionConfigFileName), b, 0600)
+}
+
+// EnableEncryption enables encryption at rest with |cfg| for the store in |dir| and its old generation store in the
+// oldgen subdirectory, which is created if it does not exist.
+func EnableEncryption(dir string, cfg EncryptionConfig) error {
+	oldgen := filepath.Join(dir, "oldgen")
+	if err := os.MkdirAll(oldgen, os.ModePerm); err != nil {
+		return err
+	}
+	if tiered, err := LoadTieredStorageConfig(oldgen); err != nil {
+		return err
+	} else if tiered != nil {
+		return errors.New("encryption at rest is not supported for databases with tiered storage")
+	}
+	for _, d := range []string{dir, oldgen} {
+		if err := WriteEncryptionConfig(d, cfg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// storeEncryption encrypts the storage files written by a store, and decrypts the encrypted storage files it reads.
+type storeEncryption struct {
+	cfg      EncryptionConfig
+	provider KeyProvider
+
+	mu sync.Mutex
+	// dataKeys caches unwrapped data keys by master key ID and wrapped data key
+	dataKeys map[string][]byte
+}
+
+// loadStoreEncryption returns the storeEncryption for the store in |dir|, or nil if encryption at rest is not
+// configured for the store. The encryption config names the key provider, which may run a command, so it must only
+// be loaded from the directory of a store that is trusted, like the local database of a repository.
+func loadStoreEncryption(dir string) (*storeEncryption, error) {
+	cfg, err := LoadEncryptionConfig(dir)
+	if err != nil {
+		return nil, err
+	}
+	return newStoreEncryption(cfg)
+}
+
+// newStoreEncryption returns the storeEncryption for |cfg|, or nil if |cfg| is nil.
+func newStoreEncryption(cfg *EncryptionConfig) (*storeEncryption, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	provider, err := newKeyProvider(*cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &storeEncryption{cfg: *cfg, provider: provider, dataKeys: make(map[string][]byte)}, nil
+}
+
+// newDataKey generates a new data key and wraps it with the current master key. It returns the ID of the master key,
+// the data key, and the start of the header of a file encrypted with the data key and |algorithm|.
+func (e *storeEncryption) newDataKey(ctx context.Context, algorithm uint8) (string, []byte, *bytes.Buffer, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", nil, nil, err
+	}
+
+	keyID, wrapped, err := e.provider.WrapKey(ctx, dataKey)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("unable to wrap data key: %w", err)
+	}
+	if len(keyID) > 0xffff || len(wrapped) > 0xffff {
+		return "", nil, nil, errors.New("encryption key provider returned an oversized key")
+	}
+
+	header := bytes.NewBufferString(encryptedFileMagic)
+	header.WriteByte(encryptedFileVersion)
+	header.WriteByte(algorithm)
+	_ = binary.Write(header, binary.BigEndian, uint16(len(keyID)))
+	header.WriteString(keyID)
+	_ = binary.Write(header, binary.BigEndian, uint16(len(wrapped)))
+	header.Write(wrapped)
+	return keyID, dataKey, header, nil
+}
+
+// newFileCipher generates a new data key, wraps it with the current master key, and returns a fileCipher using it
+// along with the header that must be written at the start of the encrypted file.
+func (e *storeEncryption) newFileCipher(ctx context.Context) (*fileCipher, []byte, error) {
+	keyID, dataKey, header, err := e.newDataKey(ctx, encryptionAlgAES256CTR)
+	if err != nil {
+		return nil, nil, err
+	}
+	var iv [aes.BlockSize]byte
+	if _, err := rand.Read(iv[:]); err != nil {
+		return nil, nil, err
+	}
+	header.Write(iv[:])
+
+	c, err := newFileCipher(keyID, dataKey, iv, int64(header.Len()))
+	if err != nil {
+		return nil, nil, err
+	}
+	return c, header.Bytes(), nil
+}
+
+func (e *storeEncryption) unwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	cacheKey := keyID + "\x00" + string(wrapped)
+	e.mu.Lock()
+	dataKey, ok := e.dataKeys[cacheKey]
+	e.mu.Unlock()
+	if ok {
+		return dataKey, nil
+	}
+
+	dataKey, err := e.provider.UnwrapKey(ctx, keyID, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unwrap data key with master key '%s': %w", keyID, err)
+	}
+	e.mu.Lock()
+	e.dataKeys[cacheKey] = dataKey
+	e.mu.Unlock()
+	return dataKey, nil
+}
+
+// newEphemeralFileCipher returns a fileCipher with a random data key which is never persisted, for temporary files
+// that are only read back by the process writing them. Files it encrypts have no header.
+func newEphemeralFileCipher() (*fileCipher, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	var iv [aes.BlockSize]byte
+	if _, err := rand.Read(iv[:]); err != nil {
+		return nil, err
+	}
+	return newFileCipher("", dataKey, iv, 0)
+}
+
+// encryptedFileHeader is the parsed header of an encrypted storage file.
+type encryptedFileHeader struct {
+	algorithm uint8
+	keyID     string
+	wrapped   []byte
+	iv        [aes.BlockSize]byte
+	length    int64
+}
+
+// readEncryptedFileHeader reads the header of the storage file |r|. If the file is not encrypted, nil is returned.
+func readEncryptedFileHeader(r io.ReaderAt) (*encryptedFileHeader, error) {
+	magic := make([]byte, len(encryptedFileMagic))
+	if n, err := r.ReadAt(magic, 0); n < len(magic) {
+		if err == nil || errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if string(magic) != encryptedFileMagic {
+		return nil, nil
+	}
+
+	sr := io.NewSectionReader(r, int64(len(magic)), 1<<20)
+	var fixed struct {
+		Version   uint8
+		Algorithm uint8
+		KeyIDLen  uint16
+	}
+	if err := binary.Read(sr, binary.BigEndian, &fixed); err != nil {
+		return nil, fmt.Errorf("invalid encrypted file header: %w", err)
+	}
+	if fixed.Version != encryptedFileVersion || (fixed.Algorithm != encryptionAlgAES256CTR && fixed.Algorithm != encryptionAlgAES256GCMFramed) {
+		return nil, fmt.Errorf("unsupported encrypted file version %d, algorithm %d", fixed.Version, fixed.Algorithm)
+	}
+
+	keyID := make([]byte, fixed.KeyIDLen)
+	if _, err := io.ReadFull(sr, keyID); err != nil {
+		return nil, fmt.Errorf("invalid encrypted file header: %w", err)
+	}
+	var wrappedLen uint16
+	if err := binary.Read(sr, binary.BigEndian, &wrappedLen); err != nil {
+		return nil, fmt.Errorf("invalid encrypted file header: %w", err)
+	}
+	hdr := &encryptedFileHeader{algorithm: fixed.Algorithm, keyID: string(keyID), wrapped: make([]byte, wrappedLen)}
+	if _, err := io.ReadFull(sr, hdr.wrapped); err != nil {
+		return nil, fmt.Errorf("invalid encrypted file header: %w", err)
+	}
+	if hdr.algorithm == encryptionAlgAES256CTR {
+		if _, err := io.ReadFull(sr, hdr.iv[:]); err != nil {
+			return nil, fmt.Errorf("invalid encrypted file header: %w", err)
+		}
+	}
+	offset, _ := sr.Seek(0, io.SeekCurrent)
+	hdr.length = int64(len(magic)) + offset
+	return hdr, nil
+}
+
+// openFileCipher reads the header of the storage file |r| and returns the fileCipher that decrypts it, or nil if
+// the file is not encrypted. |enc| may be nil for stores without encryption at rest, in which case reading an
+// encrypted file returns ErrEncryptionNotConfigured.
+func openFileCipher(ctx context.Context, enc *storeEncryption, r io.ReaderAt) (*fileCipher, error) {
+	hdr, err := readEncryptedFileHeader(r)
+	if err != nil || hdr == nil {
+		return nil, err
+	}
+	if enc == nil {
+		return nil, ErrEncryptionNotConfigured
+	}
+	if hdr.algorithm != encryptionAlgAES256CTR {
+		return nil, fmt.Errorf("unexpected encryption algorithm %d for a table file", hdr.algorithm)
+	}
+	dataKey, err := enc.unwrapKey(ctx, hdr.keyID, hdr.wrapped)
+	if err != nil {
+		return nil, err
+	}
+	return newFileCipher(hdr.keyID, dataKey, hdr.iv, hdr.length)
+}
+
+// fileCipher encrypts and decrypts the contents of a single storage file.
+type fileCipher struct {
+	keyID     string
+	block     cipher.Block
+	iv        [aes.BlockSize]byte
+	headerLen int64
+}
+
+func newFileCipher(keyID string, dataKey []byte, iv [aes.BlockSize]byte, headerLen int64) (*fileCipher, error) {
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return &fileCipher{keyID: keyID, block: block, iv: iv, headerLen: headerLen}, nil
+}
+
+// xorKeyStreamAt encrypts or decrypts |src| into |dst|, where |src| starts at offset |off| of the file's contents.
+func (c *fileCipher) xorKeyStreamAt(dst, src []byte, off int64) {
+	iv := c.iv
+	// add the block number of |off| to the 128-bit big endian counter
+	carry := uint64(off / aes.BlockSize)
+	for i := aes.BlockSize - 1; i >= 0 && carry > 0; i-- {
+		sum := uint64(iv[i]) + (carry & 0xff)
+		iv[i] = byte(sum)
+		carry = (carry >> 8) + (sum >> 8)
+	}
+
+	stream := cipher.NewCTR(c.block, iv[:])
+	if skip := off % aes.BlockSize; skip > 0 {
+		var discard [aes.BlockSize]byte
+		stream.XORKeyStream(discard[:skip], discard[:skip])
+	}
+	stream.XORKeyStream(dst, src)
+}
+
+// encryptingWriter encrypts the data written to it before writing it to the underlying writer.
+type encryptingWriter struct {
+	w   io.Writer
+	c   *fileCipher
+	off int64
+	buf []byte
+}
+
+func (w *encryptingWriter) Write(p []byte) (int, error) {
+	if cap(w.buf) < len(p) {
+		w.buf = make([]byte, len(p))
+	}
+	buf := w.buf[:len(p)]
+	w.c.xorKeyStreamAt(buf, p, w.off)
+	n, err := w.w.Write(buf)
+	w.off += int64(n)
+	return n, err
+}
+
+// newEncryptingWriter returns a writer that writes the contents of a new storage file to |w|. If |enc| is nil, |w|
+// is returned and the contents are written unencrypted.
+func newEncryptingWriter(ctx context.Context, enc *storeEncryption, w io.Writer) (io.Writer, error) {
+	if enc == nil {
+		return w, nil
+	}
+	c, header, err := enc.newFileCipher(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(header); err != nil {
+		return nil, err
+	}
+	return &encryptingWriter{w: w, c: c}, nil
+}
+
+// decryptingReaderAt decrypts the contents of an encrypted storage file.
+type decryptingReaderAt struct {
+	r io.ReaderAt
+	c *fileCipher
+}
+
+func (r decryptingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	n, err := r.r.ReadAt(p, off+r.c.headerLen)
+	r.c.xorKeyStreamAt(p[:n], p[:n], off)
+	return n, err
+}
+
+// Close closes the underlying reader, if it is an io.Closer.
+func (r decryptingReaderAt) Close() error {
+	if closer, ok := r.r.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// newDecryptingReaderAt returns an io.ReaderAt over the decrypted contents of the storage file |r| with size
+// |size|, along with the size of the decrypted contents. If |c| is nil, the file is not encrypted and |r| is returned.
+func newDecryptingReaderAt(r io.ReaderAt, size int64, c *fileCipher) (io.ReaderAt, int64) {
+	if c == nil {
+		return r, size
+	}
+	return decryptingReaderAt{r: r, c: c}, size - c.headerLen
+}
+
+// newDecryptingReadCloser returns an io.ReadCloser that reads the decrypted contents of the file |f|, which must be
+// positioned at the start of the file. If |c| is nil, the file is not encrypted and |f| is returned.
+func newDecryptingReadCloser(f *os.File, c *fileCipher) (io.ReadCloser, error) {
+	if c == nil {
+		return f, nil
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return &decryptingReadCloser{
+		SectionReader: io.NewSectionReader(decryptingReaderAt{r: f, c: c}, 0, info.Size()-c.headerLen),
+		f:             f,
+	}, nil
+}
+
+type decryptingReadCloser struct {
+	*io.SectionReader
+	f *os.File
+}
+
+func (r *decryptingReadCloser) Close() error {
+	return r.f.Close()
+}
+
+// encryptionOf returns the storeEncryption of the store persisting table files with |p|, or nil if the store does
+// not encrypt its storage files.
+func encryptionOf(p tablePersister) *storeEncryption {
+	switch p := p.(type) {
+	case *fsTablePersister:
+		return p.enc
+	case *ChunkJournal:
+		return p.persister.enc
+	}
+	return nil
+}
+
+// ErrUnencryptedDestination is returned when copying the chunks of a store encrypted at rest to a store that is not.
+var ErrUnencryptedDestination = errors.New("cannot copy a database encrypted at rest to a destination that is not encrypted at rest; " +
+	"use a file destination with encryption enabled, or a remotesapi server that encrypts the databases it stores")
+
+// blockStoreOf returns the NomsBlockStore that persists the storage files written to |cs|, or nil if |cs| is not
+// persisted by a NomsBlockStore.
+func blockStoreOf(cs chunks.ChunkStore) *NomsBlockStore {
+	switch cs := cs.(type) {
+	case *NomsBlockStore:
+		return cs
+	case *GenerationalNBS:
+		return cs.newGen
+	case *NBSMetricWrapper:
+		return cs.nbs
+	}
+	return nil
+}
+
+// EncryptsAtRest returns whether |cs| is a store that encrypts the storage files written to it at rest.
+func EncryptsAtRest(cs chunks.ChunkStore) bool {
+	st := blockStoreOf(cs)
+	return st != nil && encryptionOf(st.p) != nil
+}
+
+// EncryptionConfigOf returns the encryption config the store |cs| was opened with, or nil if |cs| does not encrypt
+// the storage files written to it at rest.
+func EncryptionConfigOf(cs chunks.ChunkStore) (*EncryptionConfig, error) {
+	st := blockStoreOf(cs)
+	if st == nil {
+		return nil, nil
+	}
+	enc := encryptionOf(st.p)
+	if enc == nil {
+		return nil, nil
+	}
+	cfg := enc.cfg
+	return &cfg, nil
+}
+
+// CheckEncryptedDestination returns ErrUnencryptedDestination if the chunks of |src| are encrypted at rest, but
+// |sink| is a local or cloud blobstore database that does not encrypt them. Databases accessed through a remotesapi
+// server are not checked, the server encrypts the databases it stores according to its own configuration.
+func CheckEncryptedDestination(src, sink chunks.ChunkStore) error {
+	if EncryptsAtRest(src) && blockStoreOf(sink) != nil && !EncryptsAtRest(sink) {
+		return ErrUnencryptedDestination
+	}
+	return nil
+}
+
+// flushSinkToEncryptedFile writes the contents of |sink| to a file at |path|, encrypting them if |enc| is non-nil.
+func flushSinkToEncryptedFile(ctx context.Context, enc *storeEncryption, sink ByteSink, path string) error {
+	if enc == nil {
+		return sink.FlushToFile(path)
+	}
+
+	temp, err := tempfiles.MovableTempFileProvider.NewFile(filepath.Dir(path), tempTablePrefix)
+	if err != nil {
+		return err
+	}
+	err = func() (err error) {
+		defer func() {
+			cerr := temp.Close()
+			if err == nil {
+				err = cerr
+			}
+		}()
+		w, err := newEncryptingWriter(ctx, enc, temp)
+		if err != nil {
+			return err
+		}
+		if err = sink.Flush(w); err != nil {
+			return err
+		}
+		return temp.Sync()
+	}()
+	if err != nil {
+		os.Remove(temp.Name())
+		return err
+	}
+	return file.Rename(temp.Name(), path)
+}
+
+// OpenStorageFile opens the storage file at |path| for reading, decrypting it with the encryption config of the
+// store it belongs to if it is encrypted. Files which have been offloaded by an open tiered store are read from its
+// Blobstore. It returns an io.ReaderAt over the decrypted contents of the file, the size of the decrypted contents,
+// and an io.Closer that closes the file.
+func OpenStorageFile(ctx context.Context, path string) (io.ReaderAt, int64, io.Closer, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		r, sz, err := openTieredStorageFile(path)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		return r, sz, r, nil
+	} else if err != nil {
+		return nil, 0, nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, 0, nil, err
+	}
+
+	hdr, err := readEncryptedFileHeader(f)
+	if err != nil {
+		f.Close()
+		return nil, 0, nil, err
+	} else if hdr == nil {
+		return f, info.Size(), f, nil
+	}
+
+	enc, err := loadStoreEncryption(filepath.Dir(path))
+	if err != nil {
+		f.Close()
+		return nil, 0, nil, err
+	}
+	if hdr.algorithm == encryptionAlgAES256GCMFramed {
+		r, sz, err := openJournalFrameReader(ctx, enc, f)
+		if err != nil {
+			f.Close()
+			return nil, 0, nil, err
+		}
+		return r, sz, f, nil
+	}
+	c, err := openFileCipher(ctx, enc, f)
+	if err != nil {
+		f.Close()
+		return nil, 0, nil, err
+	}
+	r, sz := newDecryptingReaderAt(f, info.Size(), c)
+	return r, sz, f, nil
+}
+
+// StorageFileEncryption is the encryption at rest state of a storage file.
+type StorageFileEncryption struct {
+	// Path is the path of the storage file.
+	Path string
+	// KeyID is the ID of the master key that wraps the file's data key, or empty if the file is not encrypted.
+	KeyID string
+}
+
+// ListStorageFileEncryption returns the encryption at rest state of the table files, archives and chunk journal of
+// the store in |dir| and its old generation store.
+func ListStorageFileEncryption(dir string) ([]StorageFileEncryption, error) {
+	var files []StorageFileEncryption
+	for _, d := range []string{dir, filepath.Join(dir, "oldgen")} {
+		entries, err := os.ReadDir(d)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		for _, e := range entries {
+			if !e.Type().IsRegular() || !hash.IsValid(strings.TrimSuffix(e.Name(), archiveFileSuffix)) {
+				continue
+			}
+			path := filepath.Join(d, e.Name())
+			hdr, err := readStorageFileHeader(path)
+			if err != nil {
+				return nil, err
+			}
+			file := StorageFileEncryption{Path: path}
+			if hdr != nil {
+				file.KeyID = hdr.keyID
+			}
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+func readStorageFileHeader(path string) (*encryptedFileHeader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readEncryptedFileHeader(f)
+}
@@ -0,0 +1,242 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// FileKeyProviderName is the name of the KeyProvider that reads master keys from a local key file.
+	FileKeyProviderName = "file"
+	// ExecKeyProviderName is the name of the KeyProvider that delegates wrapping and unwrapping data keys to an
+	// external plugin command, e.g. a client for a key management service.
+	ExecKeyProviderName = "exec"
+)
+
+func init() {
+	RegisterKeyProvider(FileKeyProviderName, func(cfg EncryptionConfig) (KeyProvider, error) {
+		if cfg.KeyFile == "" {
+			return nil, errors.New("the file encryption key provider requires a key file")
+		} else if !filepath.IsAbs(cfg.KeyFile) {
+			return nil, fmt.Errorf("the key file of the file encryption key provider must be an absolute path: %s", cfg.KeyFile)
+		}
+		return fileKeyProvider{path: cfg.KeyFile}, nil
+	})
+	RegisterKeyProvider(ExecKeyProviderName, func(cfg EncryptionConfig) (KeyProvider, error) {
+		if len(cfg.Command) == 0 {
+			return nil, errors.New("the exec encryption key provider requires a command")
+		}
+		return execKeyProvider{command: cfg.Command}, nil
+	})
+}
+
+// EncryptionKeyFile is the contents of a key file used by the file KeyProvider. It holds every master key that may
+// have wrapped the data key of a storage file, and identifies the current master key used to wrap new data keys.
+// Keys are rotated by adding a new current key and running garbage collection, which rewrites every storage file
+// with data keys wrapped by the new key. Old keys can be removed from the key file once that's done.
+type EncryptionKeyFile struct {
+	Current string             `json:"current"`
+	Keys    []EncryptionKeyDef `json:"keys"`
+}
+
+// EncryptionKeyDef is a master key in an EncryptionKeyFile.
+type EncryptionKeyDef struct {
+	ID  string `json:"id"`
+	Key string `json:"key"` // base64 encoded AES-256 key
+}
+
+// ReadEncryptionKeyFile reads the key file at |path|.
+func ReadEncryptionKeyFile(path string) (EncryptionKeyFile, error) {
+	var kf EncryptionKeyFile
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return kf, err
+	}
+	if err = json.Unmarshal(b, &kf); err != nil {
+		return kf, fmt.Errorf("invalid encryption key file %s: %w", path, err)
+	}
+	return kf, nil
+}
+
+// AddEncryptionKey generates a new random master key, adds it to the key file at |path|, creating the file if it
+// doesn't exist, and makes it the current key. It returns the ID of the new key.
+func AddEncryptionKey(path string) (string, error) {
+	kf, err := ReadEncryptionKeyFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+
+	key := make([]byte, dataKeySize)
+	if _, err = rand.Read(key); err != nil {
+		return "", err
+	}
+	id := make([]byte, 8)
+	if _, err = rand.Read(id); err != nil {
+		return "", err
+	}
+
+	kf.Current = hex.EncodeToString(id)
+	kf.Keys = append(kf.Keys, EncryptionKeyDef{ID: kf.Current, Key: base64.StdEncoding.EncodeToString(key)})
+	b, err := json.MarshalIndent(kf, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err = os.WriteFile(path, b, 0600); err != nil {
+		return "", err
+	}
+	return kf.Current, nil
+}
+
+func (kf EncryptionKeyFile) key(id string) ([]byte, error) {
+	for _, k := range kf.Keys {
+		if k.ID == id {
+			key, err := base64.StdEncoding.DecodeString(k.Key)
+			if err != nil {
+				return nil, fmt.Errorf("invalid encryption key '%s': %w", id, err)
+			}
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("encryption key '%s' not found", id)
+}
+
+// fileKeyProvider wraps data keys with AES-256-GCM, using master keys read from a local key file. The key file is
+// read for every operation, so that a newly added current key is used without restarting the server.
+type fileKeyProvider struct {
+	path string
+}
+
+var _ KeyProvider = fileKeyProvider{}
+
+func (p fileKeyProvider) WrapKey(_ context.Context, dataKey []byte) (string, []byte, error) {
+	kf, err := ReadEncryptionKeyFile(p.path)
+	if err != nil {
+		return "", nil, err
+	}
+	aead, err := p.aead(kf, kf.Current)
+	if err != nil {
+		return "", nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", nil, err
+	}
+	return kf.Current, aead.Seal(nonce, nonce, dataKey, []byte(kf.Current)), nil
+}
+
+func (p fileKeyProvider) UnwrapKey(_ context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	kf, err := ReadEncryptionKeyFile(p.path)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := p.aead(kf, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, errors.New("invalid wrapped data key")
+	}
+	nonce, ciphertext := wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, []byte(keyID))
+}
+
+func (p fileKeyProvider) aead(kf EncryptionKeyFile, id string) (cipher.AEAD, error) {
+	key, err := kf.key(id)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// execKeyProvider delegates wrapping and unwrapping data keys to an external plugin. The plugin is run once per
+// operation, with a JSON request written to its stdin, and must write a JSON response to its stdout:
+//
+//	{"op": "wrap", "plaintext": "<base64>"}                    => {"key_id": "...", "ciphertext": "<base64>"}
+//	{"op": "unwrap", "key_id": "...", "ciphertext": "<base64>"} => {"plaintext": "<base64>"}
+//
+// A plugin exiting with a non-zero status fails the operation, and its stderr is included in the error.
+type execKeyProvider struct {
+	command []string
+}
+
+var _ KeyProvider = execKeyProvider{}
+
+type execKeyProviderMessage struct {
+	Op         string `json:"op,omitempty"`
+	KeyID      string `json:"key_id,omitempty"`
+	Plaintext  []byte `json:"plaintext,omitempty"`
+	Ciphertext []byte `json:"ciphertext,omitempty"`
+}
+
+func (p execKeyProvider) WrapKey(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	resp, err := p.run(ctx, execKeyProviderMessage{Op: "wrap", Plaintext: dataKey})
+	if err != nil {
+		return "", nil, err
+	}
+	if resp.KeyID == "" || len(resp.Ciphertext) == 0 {
+		return "", nil, errors.New("encryption key plugin returned an incomplete response")
+	}
+	return resp.KeyID, resp.Ciphertext, nil
+}
+
+func (p execKeyProvider) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	resp, err := p.run(ctx, execKeyProviderMessage{Op: "unwrap", KeyID: keyID, Ciphertext: wrapped})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Plaintext) != dataKeySize {
+		return nil, errors.New("encryption key plugin returned an invalid data key")
+	}
+	return resp.Plaintext, nil
+}
+
+func (p execKeyProvider) run(ctx context.Context, req execKeyProviderMessage) (execKeyProviderMessage, error) {
+	var resp execKeyProviderMessage
+	in, err := json.Marshal(req)
+	if err != nil {
+		return resp, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.command[0], p.command[1:]...)
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err = cmd.Run(); err != nil {
+		return resp, fmt.Errorf("encryption key plugin failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	if err = json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return resp, fmt.Errorf("invalid response from encryption key plugin: %w", err)
+	}
+	return resp, nil
+}
@@ -0,0 +1,420 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/constants"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func makeTestEncryptionConfig(t *testing.T) EncryptionConfig {
+	keyFile := filepath.Join(t.TempDir(), "keys.json")
+	_, err := AddEncryptionKey(keyFile)
+	require.NoError(t, err)
+	return EncryptionConfig{KeyProvider: FileKeyProviderName, KeyFile: keyFile}
+}
+
+func makeTestStoreEncryption(t *testing.T) *storeEncryption {
+	dir := t.TempDir()
+	require.NoError(t, WriteEncryptionConfig(dir, makeTestEncryptionConfig(t)))
+	enc, err := loadStoreEncryption(dir)
+	require.NoError(t, err)
+	require.NotNil(t, enc)
+	return enc
+}
+
+func TestEncryptedLocalStoreSuite(t *testing.T) {
+	cfg := makeTestEncryptionConfig(t)
+	fn := func(ctx context.Context, dir string) (*NomsBlockStore, error) {
+		if err := EnableEncryption(dir, cfg); err != nil {
+			return nil, err
+		}
+		nbf := constants.FormatDefaultString
+		qp := NewUnlimitedMemQuotaProvider()
+		return NewLocalStore(ctx, nbf, dir, testMemTableSize, qp)
+	}
+	suite.Run(t, &BlockStoreSuite{factory: fn})
+}
+
+func TestEncryptedChunkJournalBlockStoreSuite(t *testing.T) {
+	cacheOnce.Do(makeGlobalCaches)
+	cfg := makeTestEncryptionConfig(t)
+	fn := func(ctx context.Context, dir string) (*NomsBlockStore, error) {
+		if err := EnableEncryption(dir, cfg); err != nil {
+			return nil, err
+		}
+		q := NewUnlimitedMemQuotaProvider()
+		nbf := types.Format_Default.VersionString()
+		return NewLocalJournalingStore(ctx, nbf, dir, q)
+	}
+	suite.Run(t, &BlockStoreSuite{
+		factory:        fn,
+		skipInterloper: true,
+	})
+}
+
+func TestEncryptedFileRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	enc := makeTestStoreEncryption(t)
+
+	data := make([]byte, 100_000)
+	rand.Read(data)
+
+	var buf bytes.Buffer
+	w, err := newEncryptingWriter(ctx, enc, &buf)
+	require.NoError(t, err)
+	for rem := data; len(rem) > 0; {
+		n := rand.Intn(5000) + 1
+		if n > len(rem) {
+			n = len(rem)
+		}
+		_, err = w.Write(rem[:n])
+		require.NoError(t, err)
+		rem = rem[n:]
+	}
+	assert.False(t, bytes.Contains(buf.Bytes(), data[:64]))
+
+	c, err := openFileCipher(ctx, enc, bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.NotNil(t, c)
+	r, sz := newDecryptingReaderAt(bytes.NewReader(buf.Bytes()), int64(buf.Len()), c)
+	assert.Equal(t, int64(len(data)), sz)
+
+	for i := 0; i < 100; i++ {
+		off := rand.Intn(len(data))
+		p := make([]byte, rand.Intn(len(data)-off)+1)
+		n, err := r.ReadAt(p, int64(off))
+		require.NoError(t, err)
+		assert.Equal(t, data[off:off+n], p)
+	}
+
+	all, err := io.ReadAll(io.NewSectionReader(r, 0, sz))
+	require.NoError(t, err)
+	assert.Equal(t, data, all)
+}
+
+func TestOpenFileCipher(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("plaintext", func(t *testing.T) {
+		c, err := openFileCipher(ctx, makeTestStoreEncryption(t), bytes.NewReader([]byte("not an encrypted file")))
+		require.NoError(t, err)
+		assert.Nil(t, c)
+		c, err = openFileCipher(ctx, nil, bytes.NewReader(nil))
+		require.NoError(t, err)
+		assert.Nil(t, c)
+	})
+	t.Run("not configured", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := newEncryptingWriter(ctx, makeTestStoreEncryption(t), &buf)
+		require.NoError(t, err)
+		_, err = openFileCipher(ctx, nil, bytes.NewReader(buf.Bytes()))
+		assert.ErrorIs(t, err, ErrEncryptionNotConfigured)
+	})
+	t.Run("wrong key", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := newEncryptingWriter(ctx, makeTestStoreEncryption(t), &buf)
+		require.NoError(t, err)
+		_, err = openFileCipher(ctx, makeTestStoreEncryption(t), bytes.NewReader(buf.Bytes()))
+		assert.Error(t, err)
+	})
+}
+
+func TestFileKeyProviderRotation(t *testing.T) {
+	ctx := context.Background()
+	cfg := makeTestEncryptionConfig(t)
+	p, err := newKeyProvider(cfg)
+	require.NoError(t, err)
+
+	dataKey := []byte("0123456789abcdef0123456789abcdef")
+	oldID, oldWrapped, err := p.WrapKey(ctx, dataKey)
+	require.NoError(t, err)
+
+	newID, err := AddEncryptionKey(cfg.KeyFile)
+	require.NoError(t, err)
+	assert.NotEqual(t, oldID, newID)
+
+	id, wrapped, err := p.WrapKey(ctx, dataKey)
+	require.NoError(t, err)
+	assert.Equal(t, newID, id)
+
+	for _, w := range []struct {
+		id      string
+		wrapped []byte
+	}{{oldID, oldWrapped}, {id, wrapped}} {
+		unwrapped, err := p.UnwrapKey(ctx, w.id, w.wrapped)
+		require.NoError(t, err)
+		assert.Equal(t, dataKey, unwrapped)
+	}
+	_, err = p.UnwrapKey(ctx, "unknown", wrapped)
+	assert.Error(t, err)
+}
+
+func TestEncryptedJournalReopen(t *testing.T) {
+	cacheOnce.Do(makeGlobalCaches)
+	ctx := context.Background()
+	dir := t.TempDir()
+	require.NoError(t, EnableEncryption(dir, makeTestEncryptionConfig(t)))
+	nbf := types.Format_Default.VersionString()
+
+	st, err := NewLocalJournalingStore(ctx, nbf, dir, NewUnlimitedMemQuotaProvider())
+	require.NoError(t, err)
+	data := []byte("a chunk which must not be stored in plaintext")
+	c := chunks.NewChunk(data)
+	require.NoError(t, st.Put(ctx, c, noopGetAddrs))
+	root, err := st.Root(ctx)
+	require.NoError(t, err)
+	ok, err := st.Commit(ctx, c.Hash(), root)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.NoError(t, st.Close())
+
+	b, err := os.ReadFile(filepath.Join(dir, chunkJournalName))
+	require.NoError(t, err)
+	assert.False(t, bytes.Contains(b, data))
+
+	files, err := ListStorageFileEncryption(dir)
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, f := range files {
+		assert.NotEmpty(t, f.KeyID, f.Path)
+	}
+
+	st, err = NewLocalJournalingStore(ctx, nbf, dir, NewUnlimitedMemQuotaProvider())
+	require.NoError(t, err)
+	defer st.Close()
+	root, err = st.Root(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, c.Hash(), root)
+	got, err := st.Get(ctx, c.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, data, got.Data())
+	assert.Equal(t, hash.Of(data), got.Hash())
+}
+
+func TestEncryptedJournalFrames(t *testing.T) {
+	ctx := context.Background()
+	enc := makeTestStoreEncryption(t)
+
+	newJournal := func(t *testing.T) (*os.File, *encryptedJournal, int64) {
+		f, err := os.Create(filepath.Join(t.TempDir(), chunkJournalName))
+		require.NoError(t, err)
+		t.Cleanup(func() { f.Close() })
+		aead, header, err := enc.newJournalAEAD(ctx)
+		require.NoError(t, err)
+		_, err = f.Write(header)
+		require.NoError(t, err)
+		_, err = f.Write(make([]byte, 1<<20)) // zero fill
+		require.NoError(t, err)
+		ej, err := newEncryptedJournal(f, aead, int64(len(header)))
+		require.NoError(t, err)
+		assert.Empty(t, ej.frames)
+		return f, ej, int64(len(header))
+	}
+	reopen := func(t *testing.T, f *os.File) *encryptedJournal {
+		aead, headerLen, err := openJournalAEAD(ctx, enc, f)
+		require.NoError(t, err)
+		require.NotNil(t, aead)
+		ej, err := newEncryptedJournal(f, aead, headerLen)
+		require.NoError(t, err)
+		return ej
+	}
+
+	data := make([]byte, 100_000)
+	rand.Read(data)
+	write := func(t *testing.T, ej *encryptedJournal, data []byte) {
+		for off := 0; off < len(data); {
+			n := min(rand.Intn(40_000)+1, len(data)-off)
+			_, err := ej.WriteAt(data[off:off+n], int64(off))
+			require.NoError(t, err)
+			off += n
+		}
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		f, ej, _ := newJournal(t)
+		write(t, ej, data)
+		_, err := ej.WriteAt([]byte{1}, int64(len(data)+1))
+		assert.Error(t, err)
+
+		b, err := os.ReadFile(f.Name())
+		require.NoError(t, err)
+		assert.False(t, bytes.Contains(b, data[:64]))
+		// the zero fill following the frames is left unencrypted
+		assert.Equal(t, make([]byte, 1024), b[ej.end:ej.end+1024])
+
+		for _, ej := range []*encryptedJournal{ej, reopen(t, f)} {
+			for i := 0; i < 100; i++ {
+				off := rand.Intn(len(data))
+				p := make([]byte, rand.Intn(len(data)-off)+1)
+				n, err := ej.ReadAt(p, int64(off))
+				require.NoError(t, err)
+				assert.Equal(t, data[off:off+n], p)
+			}
+			end, err := ej.Seek(0, io.SeekEnd)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(data)), end)
+			_, err = ej.Seek(0, io.SeekStart)
+			require.NoError(t, err)
+			all, err := io.ReadAll(ej)
+			require.NoError(t, err)
+			assert.Equal(t, data, all)
+		}
+	})
+	t.Run("rewrite", func(t *testing.T) {
+		f, ej, _ := newJournal(t)
+		write(t, ej, data)
+		_, err := ej.WriteAt([]byte("rewritten"), 50_000)
+		require.NoError(t, err)
+		expected := append(append([]byte{}, data[:50_000]...), "rewritten"...)
+
+		for _, ej := range []*encryptedJournal{ej, reopen(t, f)} {
+			_, err = ej.Seek(0, io.SeekStart)
+			require.NoError(t, err)
+			all, err := io.ReadAll(ej)
+			require.NoError(t, err)
+			assert.Equal(t, expected, all)
+		}
+	})
+	t.Run("torn frame", func(t *testing.T) {
+		f, ej, _ := newJournal(t)
+		write(t, ej, data)
+		last := ej.frames[len(ej.frames)-1]
+		_, err := f.WriteAt([]byte{0xff}, last.pos+journalFrameHeaderSize)
+		require.NoError(t, err)
+
+		ej = reopen(t, f)
+		end, err := ej.Seek(0, io.SeekEnd)
+		require.NoError(t, err)
+		assert.Equal(t, last.off, end)
+		info, err := f.Stat()
+		require.NoError(t, err)
+		assert.Equal(t, last.pos, info.Size())
+
+		_, err = ej.WriteAt(data[last.off:], last.off)
+		require.NoError(t, err)
+		ej = reopen(t, f)
+		all, err := io.ReadAll(ej)
+		require.NoError(t, err)
+		assert.Equal(t, data, all)
+	})
+	t.Run("tampered frame", func(t *testing.T) {
+		f, ej, _ := newJournal(t)
+		write(t, ej, data)
+		first := ej.frames[0]
+		_, err := f.WriteAt([]byte{0xff}, first.pos+journalFrameHeaderSize)
+		require.NoError(t, err)
+		_, err = ej.ReadAt(make([]byte, 10), first.off)
+		assert.Error(t, err)
+	})
+}
+
+func TestFileKeyProviderRelativeKeyFile(t *testing.T) {
+	_, err := newStoreEncryption(&EncryptionConfig{KeyProvider: FileKeyProviderName, KeyFile: "keys.json"})
+	assert.Error(t, err)
+}
+
+// TestLocalStoreWithEncryption asserts that a store opened with an encryption config ignores the encryption config
+// in its directory, which may name a command for the exec key provider.
+func TestLocalStoreWithEncryption(t *testing.T) {
+	cacheOnce.Do(makeGlobalCaches)
+	ctx := context.Background()
+	nbf := types.Format_Default.VersionString()
+	cfg := makeTestEncryptionConfig(t)
+	marker := filepath.Join(t.TempDir(), "marker")
+	dir := t.TempDir()
+	require.NoError(t, WriteEncryptionConfig(dir, EncryptionConfig{KeyProvider: ExecKeyProviderName, Command: []string{"touch", marker}}))
+
+	data := []byte("a chunk which must not be stored in plaintext")
+	c := chunks.NewChunk(data)
+	st, err := NewLocalStoreWithEncryption(ctx, nbf, dir, testMemTableSize, NewUnlimitedMemQuotaProvider(), &cfg)
+	require.NoError(t, err)
+	require.NoError(t, st.Put(ctx, c, noopGetAddrs))
+	root, err := st.Root(ctx)
+	require.NoError(t, err)
+	ok, err := st.Commit(ctx, c.Hash(), root)
+	require.NoError(t, err)
+	require.True(t, ok)
+	got, err := EncryptionConfigOf(st)
+	require.NoError(t, err)
+	assert.Equal(t, &cfg, got)
+	require.NoError(t, st.Close())
+
+	st, err = NewLocalJournalingStoreWithEncryption(ctx, nbf, dir, NewUnlimitedMemQuotaProvider(), &cfg)
+	require.NoError(t, err)
+	read, err := st.Get(ctx, c.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, data, read.Data())
+	require.NoError(t, st.Close())
+
+	st, err = NewLocalStoreWithEncryption(ctx, nbf, t.TempDir(), testMemTableSize, NewUnlimitedMemQuotaProvider(), nil)
+	require.NoError(t, err)
+	assert.False(t, EncryptsAtRest(st))
+	require.NoError(t, st.Close())
+
+	_, err = os.Stat(marker)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCheckEncryptedDestination(t *testing.T) {
+	cacheOnce.Do(makeGlobalCaches)
+	ctx := context.Background()
+	nbf := types.Format_Default.VersionString()
+	cfg := makeTestEncryptionConfig(t)
+
+	newStore := func(t *testing.T, encrypted bool) *NomsBlockStore {
+		dir := t.TempDir()
+		if encrypted {
+			require.NoError(t, EnableEncryption(dir, cfg))
+		}
+		st, err := NewLocalJournalingStore(ctx, nbf, dir, NewUnlimitedMemQuotaProvider())
+		require.NoError(t, err)
+		t.Cleanup(func() { st.Close() })
+		return st
+	}
+	encrypted, plaintext := newStore(t, true), newStore(t, false)
+
+	assert.True(t, EncryptsAtRest(encrypted))
+	assert.False(t, EncryptsAtRest(plaintext))
+	assert.False(t, EncryptsAtRest(chunks.NewMemoryStoreFactory().CreateStore(ctx, "db")))
+
+	got, err := EncryptionConfigOf(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, &cfg, got)
+	got, err = EncryptionConfigOf(plaintext)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	assert.NoError(t, CheckEncryptedDestination(encrypted, newStore(t, true)))
+	assert.NoError(t, CheckEncryptedDestination(plaintext, encrypted))
+	assert.NoError(t, CheckEncryptedDestination(plaintext, newStore(t, false)))
+	assert.ErrorIs(t, CheckEncryptedDestination(encrypted, plaintext), ErrUnencryptedDestination)
+	// stores which aren't persisted by this package, such as remotesapi databases, aren't checked
+	assert.NoError(t, CheckEncryptedDestination(encrypted, chunks.NewMemoryStoreFactory().CreateStore(ctx, "db")))
+}
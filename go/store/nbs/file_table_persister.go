@@ -42,13 +42,18 @@ import (
 
 const tempTablePrefix = "nbs_table_"
 
-func newFSTablePersister(dir string, q MemoryQuotaProvider) tablePersister {
-	return &fsTablePersister{dir, q, sync.Mutex{}, nil, make(map[string]struct{})}
+var errEncryptedTableFileMove = errors.New("cannot move table file into an encrypted store")
+
+// newFSTablePersister returns a tablePersister that stores table files in |dir|. If |enc| is non-nil, table files
+// written by the persister are encrypted with it.
+func newFSTablePersister(dir string, q MemoryQuotaProvider, enc *storeEncryption) tablePersister {
+	return &fsTablePersister{dir: dir, q: q, enc: enc, curTmps: make(map[string]struct{})}
 }
 
 type fsTablePersister struct {
 	dir string
 	q   MemoryQuotaProvider
+	enc *storeEncryption
 
 	// Protects the following two maps.
 	removeMu sync.Mutex
@@ -69,7 +74,7 @@ var _ tablePersister = &fsTablePersister{}
 var _ tableFilePersister = &fsTablePersister{}
 
 func (ftp *fsTablePersister) Open(ctx context.Context, name hash.Hash, chunkCount uint32, stats *Stats) (chunkSource, error) {
-	return newFileTableReader(ctx, ftp.dir, name, chunkCount, ftp.q)
+	return newFileTableReader(ctx, ftp.dir, name, chunkCount, ftp.q, ftp.enc)
 }
 
 func (ftp *fsTablePersister) Exists(ctx context.Context, name hash.Hash, chunkCount uint32, stats *Stats) (bool, error) {
@@ -127,7 +132,12 @@ func (ftp *fsTablePersister) CopyTableFile(ctx context.Context, r io.Reader, fil
 			}
 		}()
 
-		_, err = io.Copy(temp, r)
+		w, err := newEncryptingWriter(ctx, ftp.enc, temp)
+		if err != nil {
+			return "", cleanup, err
+		}
+
+		_, err = io.Copy(w, r)
 		if err != nil {
 			return "", cleanup, err
 		}
@@ -154,6 +164,10 @@ func (ftp *fsTablePersister) CopyTableFile(ctx context.Context, r io.Reader, fil
 }
 
 func (ftp *fsTablePersister) TryMoveCmpChunkTableWriter(ctx context.Context, filename string, w *CmpChunkTableWriter) error {
+	if ftp.enc != nil {
+		// |w| holds an unencrypted table file, it must be written through CopyTableFile instead
+		return errEncryptedTableFileMove
+	}
 	path := filepath.Join(ftp.dir, filename)
 	ftp.removeMu.Lock()
 	if ftp.toKeep != nil {
@@ -192,7 +206,13 @@ func (ftp *fsTablePersister) persistTable(ctx context.Context, name hash.Hash, d
 			}
 		}()
 
-		_, ferr = io.Copy(temp, bytes.NewReader(data))
+		var w io.Writer
+		w, ferr = newEncryptingWriter(ctx, ftp.enc, temp)
+		if ferr != nil {
+			return "", cleanup, ferr
+		}
+
+		_, ferr = io.Copy(w, bytes.NewReader(data))
 		if ferr != nil {
 			return "", cleanup, ferr
 		}
@@ -258,6 +278,12 @@ func (ftp *fsTablePersister) ConjoinAll(ctx context.Context, sources chunkSource
 			}
 		}()
 
+		var w io.Writer
+		w, ferr = newEncryptingWriter(ctx, ftp.enc, temp)
+		if ferr != nil {
+			return "", cleanup, ferr
+		}
+
 		for _, sws := range plan.sources.sws {
 			var r io.ReadCloser
 			r, _, ferr = sws.source.reader(ctx)
@@ -265,7 +291,7 @@ func (ftp *fsTablePersister) ConjoinAll(ctx context.Context, sources chunkSource
 				return "", cleanup, ferr
 			}
 
-			n, ferr := io.CopyN(temp, r, int64(sws.dataLen))
+			n, ferr := io.CopyN(w, r, int64(sws.dataLen))
 			if ferr != nil {
 				r.Close()
 				return "", cleanup, ferr
@@ -282,7 +308,7 @@ func (ftp *fsTablePersister) ConjoinAll(ctx context.Context, sources chunkSource
 			}
 		}
 
-		_, ferr = temp.Write(plan.mergedIndex)
+		_, ferr = w.Write(plan.mergedIndex)
 
 		if ferr != nil {
 			return "", cleanup, ferr
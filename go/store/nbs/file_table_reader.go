@@ -67,26 +67,27 @@ func archiveFileExists(ctx context.Context, dir string, h hash.Hash) (bool, erro
 	return err == nil, err
 }
 
-func newFileTableReader(ctx context.Context, dir string, h hash.Hash, chunkCount uint32, q MemoryQuotaProvider) (cs chunkSource, err error) {
+func newFileTableReader(ctx context.Context, dir string, h hash.Hash, chunkCount uint32, q MemoryQuotaProvider, enc *storeEncryption) (cs chunkSource, err error) {
 	// we either have a table file or an archive file
 	tfExists, err := tableFileExists(ctx, dir, h)
 	if err != nil {
 		return nil, err
 	} else if tfExists {
-		return nomsFileTableReader(ctx, filepath.Join(dir, h.String()), h, chunkCount, q)
+		return nomsFileTableReader(ctx, filepath.Join(dir, h.String()), h, chunkCount, q, enc)
 	}
 
 	afExists, err := archiveFileExists(ctx, dir, h)
 	if err != nil {
 		return nil, err
 	} else if afExists {
-		return newArchiveChunkSource(ctx, dir, h, chunkCount, q)
+		return newArchiveChunkSource(ctx, dir, h, chunkCount, q, enc)
 	}
 	return nil, errors.New(fmt.Sprintf("table file %s/%s not found", dir, h.String()))
 }
 
-func nomsFileTableReader(ctx context.Context, path string, h hash.Hash, chunkCount uint32, q MemoryQuotaProvider) (cs chunkSource, err error) {
+func nomsFileTableReader(ctx context.Context, path string, h hash.Hash, chunkCount uint32, q MemoryQuotaProvider, enc *storeEncryption) (cs chunkSource, err error) {
 	var f *os.File
+	var c *fileCipher
 	index, sz, err := func() (ti onHeapTableIndex, sz int64, err error) {
 		// Be careful with how |f| is used below. |RefFile| returns a cached
 		// os.File pointer so the code needs to use f in a concurrency-safe
@@ -111,10 +112,17 @@ func nomsFileTableReader(ctx context.Context, path string, h hash.Hash, chunkCou
 			return
 		}
 
+		// If the table file is encrypted, all reads below go through |fr|, which decrypts them
+		c, err = openFileCipher(ctx, enc, f)
+		if err != nil {
+			return
+		}
+		fr, fsz := newDecryptingReaderAt(f, fi.Size(), c)
+
 		idxSz := int64(indexSize(chunkCount) + footerSize)
-		sz = fi.Size()
+		sz = fsz
 		indexOffset := sz - idxSz
-		r := io.NewSectionReader(f, indexOffset, idxSz)
+		r := io.NewSectionReader(fr, indexOffset, idxSz)
 
 		if int64(int(idxSz)) != idxSz {
 			err = fmt.Errorf("table file %s is too large to read on this platform. index size %d > max int.", path, idxSz)
@@ -154,7 +162,7 @@ func nomsFileTableReader(ctx context.Context, path string, h hash.Hash, chunkCou
 		return nil, errors.New("unexpected chunk count")
 	}
 
-	tr, err := newTableReader(index, &fileReaderAt{f, path, sz}, fileBlockSize)
+	tr, err := newTableReader(index, &fileReaderAt{f, path, sz, c}, fileBlockSize)
 	if err != nil {
 		index.Close()
 		f.Close()
@@ -229,6 +237,8 @@ type fileReaderAt struct {
 	f    *os.File
 	path string
 	sz   int64
+	// c decrypts the table file, or is nil if it is not encrypted
+	c *fileCipher
 }
 
 func (fra *fileReaderAt) clone() (tableReaderAt, error) {
@@ -240,6 +250,7 @@ func (fra *fileReaderAt) clone() (tableReaderAt, error) {
 		f,
 		fra.path,
 		fra.sz,
+		fra.c,
 	}, nil
 }
 
@@ -248,7 +259,11 @@ func (fra *fileReaderAt) Close() error {
 }
 
 func (fra *fileReaderAt) Reader(ctx context.Context) (io.ReadCloser, error) {
-	return os.Open(fra.path)
+	f, err := os.Open(fra.path)
+	if err != nil {
+		return nil, err
+	}
+	return newDecryptingReadCloser(f, fra.c)
 }
 
 func (fra *fileReaderAt) ReadAtWithStats(ctx context.Context, p []byte, off int64, stats *Stats) (n int, err error) {
@@ -257,5 +272,8 @@ func (fra *fileReaderAt) ReadAtWithStats(ctx context.Context, p []byte, off int6
 		stats.FileBytesPerRead.Sample(uint64(len(p)))
 		stats.FileReadLatency.SampleTimeSince(t1)
 	}()
+	if fra.c != nil {
+		return decryptingReaderAt{fra.f, fra.c}.ReadAt(p, off)
+	}
 	return fra.f.ReadAt(p, off)
 }
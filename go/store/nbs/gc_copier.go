@@ -47,8 +47,10 @@ type gcCopier struct {
 	writer *CmpChunkTableWriter
 }
 
-func newGarbageCollectionCopier() (*gcCopier, error) {
-	writer, err := NewCmpChunkTableWriter("")
+// newGarbageCollectionCopier returns a gcCopier. If |encrypt| is true, the table file it writes is encrypted while it
+// is being written, see NewEncryptedCmpChunkTableWriter.
+func newGarbageCollectionCopier(encrypt bool) (*gcCopier, error) {
+	writer, err := newCmpChunkTableWriter("", encrypt)
 	if err != nil {
 		return nil, err
 	}
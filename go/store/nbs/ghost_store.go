@@ -33,7 +33,7 @@ type GhostBlockStore struct {
 }
 
 // We use the Has, HasMany, Get, GetMany, and PersistGhostHashes methods from the ChunkStore interface. All other methods are not supported.
-var _ chunks.ChunkStore = &GhostBlockStore{}
+var _ chunks.GhostChunkStore = &GhostBlockStore{}
 
 // NewGhostBlockStore returns a new GhostBlockStore instance. Currently the only parameter is the path to the directory
 // where we will create a text file called ghostObjects.txt. This file will contain the hashes of the ghost objects. Creation
@@ -87,27 +87,57 @@ func (g GhostBlockStore) GetMany(ctx context.Context, hashes hash.HashSet, found
 	return nil
 }
 
+// PersistGhostHashes adds |hashes| to the set of ghost objects, and writes the resulting set to the ghostObjects.txt file.
 func (g *GhostBlockStore) PersistGhostHashes(ctx context.Context, hashes hash.HashSet) error {
 	if hashes.Size() == 0 {
 		return fmt.Errorf("runtime error. PersistGhostHashes called with empty hash set")
 	}
 
+	skipped := g.skippedRefs.Copy()
+	skipped.InsertAll(hashes)
+	return g.writeGhostObjects(skipped)
+}
+
+// DeleteGhostHashes removes |hashes| from the set of ghost objects. This is used once the chunks they stand for have
+// been fetched, so that they are no longer reported as present before they are written.
+func (g *GhostBlockStore) DeleteGhostHashes(ctx context.Context, hashes hash.HashSet) error {
+	skipped := g.skippedRefs.Copy()
+	for h := range hashes {
+		skipped.Remove(h)
+	}
+	return g.writeGhostObjects(skipped)
+}
+
+// GhostCount returns the number of ghost objects.
+func (g *GhostBlockStore) GhostCount() int {
+	if g == nil {
+		return 0
+	}
+	return g.skippedRefs.Size()
+}
+
+func (g *GhostBlockStore) writeGhostObjects(skipped hash.HashSet) error {
 	f, err := os.OpenFile(g.ghostObjectsFile, os.O_TRUNC|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
 		return err
 	}
 
-	for h := range hashes {
-		if _, err := f.WriteString(h.String() + "\n"); err != nil {
+	w := bufio.NewWriter(f)
+	for h := range skipped {
+		if _, err := w.WriteString(h.String() + "\n"); err != nil {
+			f.Close()
 			return err
 		}
 	}
-
-	g.skippedRefs = &hash.HashSet{}
-	for h := range hashes {
-		g.skippedRefs.Insert(h)
+	if err = w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
 	}
 
+	g.skippedRefs = &skipped
 	return nil
 }
 
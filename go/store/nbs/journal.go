@@ -23,6 +23,8 @@ import (
 	"path/filepath"
 	"sort"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/dolthub/fslock"
@@ -46,10 +48,18 @@ var reflogDisabled = false
 // This default can be overridden by setting the DOLT_REFLOG_RECORD_LIMIT before Dolt starts.
 const defaultReflogBufferSize = 5_000
 
+// defaultPersistentReflog indicates whether chunk journals keep every root in their reflog when they are opened,
+// which is controlled by the DOLT_PERSISTENT_REFLOG env var. The reflog policy of a database changes it for its own
+// chunk journal with ChunkJournal.SetPersistentReflog. Like reflogDisabled, it is only written during initialization.
+var defaultPersistentReflog = false
+
 func init() {
 	if os.Getenv(dconfig.EnvDisableReflog) != "" {
 		reflogDisabled = true
 	}
+	if os.Getenv(dconfig.EnvPersistentReflog) != "" {
+		defaultPersistentReflog = true
+	}
 }
 
 // ChunkJournal is a persistence abstraction for a NomsBlockStore.
@@ -63,9 +73,14 @@ type ChunkJournal struct {
 	backing   *journalManifest
 	persister *fsTablePersister
 
-	// reflogRingBuffer holds the most recent roots written to the chunk journal so that they can be
-	// quickly loaded for reflog queries without having to re-read the journal file from disk.
-	reflogRingBuffer *reflogRingBuffer
+	// reflogRingBuffer holds the roots written to the chunk journal so that they can be quickly loaded
+	// for reflog queries without having to re-read the journal file from disk. Unless the persistent
+	// reflog is enabled, only the most recent roots are kept. It is accessed through reflog().
+	reflogRingBuffer reflogBuffer
+	reflogMu         sync.Mutex
+	// persistentReflog indicates whether the reflog keeps every root recorded in this journal, instead of only
+	// the most recent roots.
+	persistentReflog atomic.Bool
 }
 
 var _ tablePersister = &ChunkJournal{}
@@ -82,7 +97,8 @@ func newChunkJournal(ctx context.Context, nbfVers, dir string, m *journalManifes
 
 	j := &ChunkJournal{path: path, backing: m, persister: p}
 	j.contents.nbfVers = nbfVers
-	j.reflogRingBuffer = newReflogRingBuffer(reflogBufferSize())
+	j.persistentReflog.Store(defaultPersistentReflog)
+	j.reflogRingBuffer = newReflogBuffer(defaultPersistentReflog)
 
 	ok, err := fileExists(path)
 	if err != nil {
@@ -97,6 +113,75 @@ func newChunkJournal(ctx context.Context, nbfVers, dir string, m *journalManifes
 	return j, nil
 }
 
+// newReflogBuffer returns the buffer used to store in-memory root references when new roots are written to a chunk
+// journal. When the persistent reflog is enabled, the buffer is unbounded and roots are only dropped from it once
+// they have been archived, see TrimReflog. Otherwise, a ring buffer sized by reflogBufferSize is used, and it is
+// emptied by garbage collection.
+func newReflogBuffer(persistent bool) reflogBuffer {
+	if persistent && !reflogDisabled {
+		return newReflogUnboundedBuffer()
+	}
+	return newReflogRingBuffer(reflogBufferSize())
+}
+
+// SetPersistentReflog sets whether this journal's reflog keeps every root recorded in it, instead of only the most
+// recent roots. The reflog stops dropping roots the next time it is accessed, but roots it already dropped are not
+// recovered. When it is disabled, only the most recent roots are kept.
+func (j *ChunkJournal) SetPersistentReflog(enabled bool) {
+	j.persistentReflog.Store(enabled)
+}
+
+// ReflogLen returns the number of roots held by this journal's reflog which have not been archived by the persistent
+// reflog yet. It is zero unless the persistent reflog is enabled.
+func (j *ChunkJournal) ReflogLen() int {
+	if ub, ok := j.reflog().(*reflogUnboundedBuffer); ok {
+		return ub.Len()
+	}
+	return 0
+}
+
+// reflog returns the buffer holding the roots of this journal's reflog. If the persistent reflog has been enabled
+// or disabled since the buffer was created, the buffer is first replaced by one of the other kind holding the same
+// roots.
+func (j *ChunkJournal) reflog() reflogBuffer {
+	j.reflogMu.Lock()
+	defer j.reflogMu.Unlock()
+	return j.lockedReflog()
+}
+
+// pushReflog records |entry| in this journal's reflog. The push is serialized with replacing the ring buffer, so
+// that no entry is pushed to a ring buffer which has already been replaced.
+func (j *ChunkJournal) pushReflog(entry reflogRootHashEntry) {
+	j.reflogMu.Lock()
+	defer j.reflogMu.Unlock()
+	j.lockedReflog().Push(entry)
+}
+
+// TrimReflog drops the roots up to and including |root| from this journal's reflog, after the persistent reflog has
+// archived the entries read from them. It does nothing unless the persistent reflog is enabled.
+func (j *ChunkJournal) TrimReflog(root hash.Hash) {
+	j.reflogMu.Lock()
+	defer j.reflogMu.Unlock()
+	if ub, ok := j.lockedReflog().(*reflogUnboundedBuffer); ok {
+		ub.TruncateThrough(root.String())
+	}
+}
+
+func (j *ChunkJournal) lockedReflog() reflogBuffer {
+	persistent := j.persistentReflog.Load() && !reflogDisabled
+	_, unbounded := j.reflogRingBuffer.(*reflogUnboundedBuffer)
+	if persistent != unbounded {
+		buf := newReflogBuffer(persistent)
+		// nothing is pushed to the old buffer while |reflogMu| is held, so iteration can't fail
+		_ = j.reflogRingBuffer.Iterate(func(item reflogRootHashEntry) error {
+			buf.Push(item)
+			return nil
+		})
+		j.reflogRingBuffer = buf
+	}
+	return j.reflogRingBuffer
+}
+
 // reflogBufferSize returns the size of the ring buffer to allocate to store in-memory roots references when
 // new roots are written to a chunk journal. If reflog queries have been disabled, this function will return 0.
 // If the default buffer size has been overridden via DOLT_REFLOG_RECORD_LIMIT, that value will be returned if
@@ -144,12 +229,12 @@ func (j *ChunkJournal) bootstrapJournalWriter(ctx context.Context) (err error) {
 	}
 
 	if !ok { // create new journal file
-		j.wr, err = createJournalWriter(ctx, j.path)
+		j.wr, err = createJournalWriter(ctx, j.path, j.persister.enc)
 		if err != nil {
 			return err
 		}
 
-		_, err = j.wr.bootstrapJournal(ctx, j.reflogRingBuffer)
+		_, err = j.wr.bootstrapJournal(ctx, j.reflog())
 		if err != nil {
 			return err
 		}
@@ -169,7 +254,7 @@ func (j *ChunkJournal) bootstrapJournalWriter(ctx context.Context) (err error) {
 		return
 	}
 
-	j.wr, ok, err = openJournalWriter(ctx, j.path)
+	j.wr, ok, err = openJournalWriter(ctx, j.path, j.persister.enc)
 	if err != nil {
 		return err
 	} else if !ok {
@@ -177,7 +262,7 @@ func (j *ChunkJournal) bootstrapJournalWriter(ctx context.Context) (err error) {
 	}
 
 	// parse existing journal file
-	root, err := j.wr.bootstrapJournal(ctx, j.reflogRingBuffer)
+	root, err := j.wr.bootstrapJournal(ctx, j.reflog())
 	if err != nil {
 		return err
 	}
@@ -225,7 +310,7 @@ func trueUpBackingManifest(ctx context.Context, root hash.Hash, backing *journal
 // and passes the root and associated timestamp to a callback function, |f|. If |f| returns an error, iteration
 // is stopped and the error is returned.
 func (j *ChunkJournal) IterateRoots(f func(root string, timestamp *time.Time) error) error {
-	return j.reflogRingBuffer.Iterate(func(entry reflogRootHashEntry) error {
+	return j.reflog().Iterate(func(entry reflogRootHashEntry) error {
 		// If we're reading a chunk journal written with an older version of Dolt, the root hash journal record may
 		// not have a timestamp value, so we'll have a time.Time instance in its zero value. If we see this, pass
 		// nil instead to signal to callers that there is no valid timestamp available.
@@ -362,7 +447,7 @@ func (j *ChunkJournal) Update(ctx context.Context, lastLock hash.Hash, next mani
 
 	// Update the in-memory structures so that the ChunkJournal can be queried for reflog data
 	if !reflogDisabled {
-		j.reflogRingBuffer.Push(reflogRootHashEntry{
+		j.pushReflog(reflogRootHashEntry{
 			root:      next.root.String(),
 			timestamp: time.Now(),
 		})
@@ -403,9 +488,13 @@ func (j *ChunkJournal) UpdateGCGen(ctx context.Context, lastLock hash.Hash, next
 		}
 	}
 
-	// Truncate the in-memory root and root timestamp metadata
+	// Truncate the in-memory root and root timestamp metadata. The persistent reflog only holds the roots which
+	// haven't been archived yet, including the roots written while garbage collection ran, and keeps them to be
+	// archived once it completes. Their chunks were written during garbage collection, so they were kept by it.
 	if !reflogDisabled {
-		j.reflogRingBuffer.Truncate()
+		if _, ok := j.reflog().(*reflogUnboundedBuffer); !ok {
+			j.reflog().Truncate()
+		}
 	}
 
 	return latest, nil
@@ -0,0 +1,372 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"bufio"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// A chunk journal encrypted at rest is appended to in place, so it can't use a single keystream over its contents
+// like table files do: rewriting a range of the file, or the zero fill preallocated past its end, would reuse the
+// keystream. Instead, its contents following the header are written as a sequence of frames, each encrypted and
+// authenticated with AES-256-GCM under a fresh random nonce:
+//
+//	sealed length (uint32)
+//	nonce (12 bytes)
+//	ciphertext and GCM tag (sealed length bytes)
+//
+// The offset of the frame's contents in the decrypted journal is authenticated as additional data, so frames can't be
+// reordered. A frame with a zero length, or the end of the file, marks the end of the journal, which is why the
+// preallocated zero fill is written unencrypted. A frame that fails authentication is treated like a torn journal
+// record: it, and everything following it, is ignored.
+const (
+	journalFrameMaxSize    = 16 * 1024
+	journalFrameHeaderSize = uint32Size + 12
+)
+
+// journalFrame locates a frame of an encrypted journal.
+type journalFrame struct {
+	// off is the offset of the frame's contents in the decrypted journal
+	off int64
+	// pos is the offset of the frame in the journal file
+	pos int64
+	// size is the size of the frame's decrypted contents
+	size int64
+}
+
+func (fr journalFrame) sealedSize(aead cipher.AEAD) int64 {
+	return fr.size + int64(aead.Overhead())
+}
+
+// newJournalAEAD generates a new data key, wraps it with the current master key, and returns the AEAD that encrypts
+// journal frames with it along with the header that must be written at the start of the journal.
+func (e *storeEncryption) newJournalAEAD(ctx context.Context) (cipher.AEAD, []byte, error) {
+	_, dataKey, header, err := e.newDataKey(ctx, encryptionAlgAES256GCMFramed)
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := newJournalAEAD(dataKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return aead, header.Bytes(), nil
+}
+
+func newJournalAEAD(dataKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// openJournalAEAD reads the header of the journal file |r| and returns the AEAD that decrypts its frames along with
+// the size of the header, or a nil AEAD if the journal is not encrypted. |enc| may be nil for stores without
+// encryption at rest, in which case reading an encrypted journal returns ErrEncryptionNotConfigured.
+func openJournalAEAD(ctx context.Context, enc *storeEncryption, r io.ReaderAt) (cipher.AEAD, int64, error) {
+	hdr, err := readEncryptedFileHeader(r)
+	if err != nil || hdr == nil {
+		return nil, 0, err
+	}
+	if enc == nil {
+		return nil, 0, ErrEncryptionNotConfigured
+	}
+	if hdr.algorithm != encryptionAlgAES256GCMFramed {
+		return nil, 0, fmt.Errorf("unexpected encryption algorithm %d for a chunk journal", hdr.algorithm)
+	}
+	dataKey, err := enc.unwrapKey(ctx, hdr.keyID, hdr.wrapped)
+	if err != nil {
+		return nil, 0, err
+	}
+	aead, err := newJournalAEAD(dataKey)
+	if err != nil {
+		return nil, 0, err
+	}
+	return aead, hdr.length, nil
+}
+
+// openJournalFrameReader returns an io.ReaderAt over the decrypted contents of the encrypted journal file |r|, along
+// with their size.
+func openJournalFrameReader(ctx context.Context, enc *storeEncryption, r io.ReaderAt) (io.ReaderAt, int64, error) {
+	aead, headerLen, err := openJournalAEAD(ctx, enc, r)
+	if err != nil {
+		return nil, 0, err
+	} else if aead == nil {
+		return nil, 0, errors.New("chunk journal is not encrypted")
+	}
+	frames, _, _, err := readJournalFrames(r, aead, headerLen)
+	if err != nil {
+		return nil, 0, err
+	}
+	fr := journalFrameReader{r: r, aead: aead, frames: frames}
+	return fr, fr.size(), nil
+}
+
+// readJournalFrames reads the frames of the encrypted journal |r| starting at offset |pos| of the file, up to the
+// end of the journal. It returns the frames, the offset of the end of the last frame in the file, and whether the
+// end of the journal was marked by a frame that is torn or fails authentication rather than by a zero length or the
+// end of the file.
+func readJournalFrames(r io.ReaderAt, aead cipher.AEAD, pos int64) (frames []journalFrame, end int64, torn bool, err error) {
+	rdr := bufio.NewReaderSize(io.NewSectionReader(r, pos, 1<<62), journalWriterBuffSize)
+	var off int64
+	buf := make([]byte, journalFrameHeaderSize+journalFrameMaxSize+aead.Overhead())
+	for {
+		if _, err = io.ReadFull(rdr, buf[:uint32Size]); err != nil {
+			break
+		}
+		l := readUint32(buf)
+		if l == 0 {
+			break
+		} else if l <= uint32(aead.Overhead()) || l > uint32(journalFrameMaxSize+aead.Overhead()) {
+			torn = true
+			break
+		}
+		frame := buf[:journalFrameHeaderSize+int(l)]
+		if _, err = io.ReadFull(rdr, frame[uint32Size:]); err != nil {
+			torn = true
+			break
+		}
+		plain, oerr := openJournalFrame(aead, frame, off)
+		if oerr != nil {
+			torn = true
+			break
+		}
+		frames = append(frames, journalFrame{off: off, pos: pos, size: int64(len(plain))})
+		off += int64(len(plain))
+		pos += int64(len(frame))
+	}
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, 0, false, err
+	}
+	return frames, pos, torn, nil
+}
+
+// sealJournalFrame appends the frame encrypting |plain|, the contents at offset |off| of the decrypted journal, to
+// |dst|.
+func sealJournalFrame(dst []byte, aead cipher.AEAD, plain []byte, off int64) ([]byte, error) {
+	start := len(dst)
+	dst = binary.BigEndian.AppendUint32(dst, uint32(len(plain)+aead.Overhead()))
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	dst = append(dst, nonce...)
+	dst = aead.Seal(dst, nonce, plain, journalFrameAD(off))
+	if len(dst)-start != journalFrameHeaderSize+len(plain)+aead.Overhead() {
+		return nil, errors.New("unexpected journal frame size")
+	}
+	return dst, nil
+}
+
+// openJournalFrame decrypts and authenticates |frame|, the frame of the contents at offset |off| of the decrypted
+// journal. The decrypted contents are returned in place of the ciphertext in |frame|.
+func openJournalFrame(aead cipher.AEAD, frame []byte, off int64) ([]byte, error) {
+	nonce := frame[uint32Size:journalFrameHeaderSize]
+	sealed := frame[journalFrameHeaderSize:]
+	return aead.Open(sealed[:0], nonce, sealed, journalFrameAD(off))
+}
+
+func journalFrameAD(off int64) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(off))
+}
+
+// journalFrameReader reads the decrypted contents of an encrypted journal.
+type journalFrameReader struct {
+	r      io.ReaderAt
+	aead   cipher.AEAD
+	frames []journalFrame
+}
+
+func (fr journalFrameReader) size() int64 {
+	if len(fr.frames) == 0 {
+		return 0
+	}
+	last := fr.frames[len(fr.frames)-1]
+	return last.off + last.size
+}
+
+// frameAt returns the index of the frame containing offset |off| of the decrypted journal, or len(fr.frames) if |off|
+// is past the end of the journal.
+func (fr journalFrameReader) frameAt(off int64) int {
+	return sort.Search(len(fr.frames), func(i int) bool {
+		return fr.frames[i].off+fr.frames[i].size > off
+	})
+}
+
+// readFrame decrypts the |i|th frame of the journal.
+func (fr journalFrameReader) readFrame(i int) ([]byte, error) {
+	f := fr.frames[i]
+	buf := make([]byte, journalFrameHeaderSize+f.sealedSize(fr.aead))
+	if _, err := fr.r.ReadAt(buf, f.pos); err != nil {
+		return nil, err
+	}
+	plain, err := openJournalFrame(fr.aead, buf, f.off)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate chunk journal frame at %d: %w", f.pos, err)
+	}
+	return plain, nil
+}
+
+func (fr journalFrameReader) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	for i := fr.frameAt(off); n < len(p); i++ {
+		if i >= len(fr.frames) {
+			return n, io.EOF
+		}
+		plain, err := fr.readFrame(i)
+		if err != nil {
+			return n, err
+		}
+		n += copy(p[n:], plain[off+int64(n)-fr.frames[i].off:])
+	}
+	return n, nil
+}
+
+// encryptedJournal is a chunk journal file encrypted at rest. It is read and written at offsets of its decrypted
+// contents, and can only be written at the end of its contents: writing at an earlier offset truncates the contents
+// at that offset first.
+type encryptedJournal struct {
+	f    *os.File
+	aead cipher.AEAD
+
+	mu     sync.RWMutex
+	frames []journalFrame
+	// end is the offset of the end of the last frame in |f|
+	end int64
+	pos int64
+}
+
+// newEncryptedJournal returns the encryptedJournal for the journal file |f| encrypted with |aead|, whose frames
+// start after a header of |headerLen| bytes. A torn or unauthenticated frame at the end of the journal is truncated,
+// so that it can't be mistaken for part of the journal once frames are written after it.
+func newEncryptedJournal(f *os.File, aead cipher.AEAD, headerLen int64) (*encryptedJournal, error) {
+	frames, end, torn, err := readJournalFrames(f, aead, headerLen)
+	if err != nil {
+		return nil, err
+	}
+	if torn {
+		if err = f.Truncate(end); err != nil {
+			return nil, err
+		}
+	}
+	return &encryptedJournal{f: f, aead: aead, frames: frames, end: end}, nil
+}
+
+// reader returns a journalFrameReader over the current contents of the journal, which reads the journal file
+// through |r|.
+func (ej *encryptedJournal) reader(r io.ReaderAt) journalFrameReader {
+	ej.mu.RLock()
+	defer ej.mu.RUnlock()
+	return journalFrameReader{r: r, aead: ej.aead, frames: ej.frames[:len(ej.frames):len(ej.frames)]}
+}
+
+func (ej *encryptedJournal) ReadAt(p []byte, off int64) (int, error) {
+	return ej.reader(ej.f).ReadAt(p, off)
+}
+
+func (ej *encryptedJournal) WriteAt(p []byte, off int64) (int, error) {
+	ej.mu.Lock()
+	defer ej.mu.Unlock()
+
+	n := len(p)
+	rdr := journalFrameReader{r: ej.f, aead: ej.aead, frames: ej.frames}
+	if sz := rdr.size(); off > sz {
+		return 0, fmt.Errorf("cannot write chunk journal at %d past its end at %d", off, sz)
+	} else if off < sz {
+		// rewrite the frame containing |off| with the contents preceding |off|, and drop the frames following it
+		i := rdr.frameAt(off)
+		plain, err := rdr.readFrame(i)
+		if err != nil {
+			return 0, err
+		}
+		frame := ej.frames[i]
+		p = append(plain[:off-frame.off:off-frame.off], p...)
+		off = frame.off
+		if err = ej.f.Truncate(frame.pos); err != nil {
+			return 0, err
+		}
+		ej.frames = ej.frames[:i]
+		ej.end = frame.pos
+	}
+
+	buf := make([]byte, 0, len(p)+(len(p)/journalFrameMaxSize+1)*(journalFrameHeaderSize+ej.aead.Overhead()))
+	frames := ej.frames
+	pos := ej.end
+	for len(p) > 0 {
+		sz := min(len(p), journalFrameMaxSize)
+		start := len(buf)
+		var err error
+		if buf, err = sealJournalFrame(buf, ej.aead, p[:sz], off); err != nil {
+			return 0, err
+		}
+		frames = append(frames, journalFrame{off: off, pos: pos, size: int64(sz)})
+		pos += int64(len(buf) - start)
+		off += int64(sz)
+		p = p[sz:]
+	}
+	if _, err := ej.f.WriteAt(buf, ej.end); err != nil {
+		return 0, err
+	}
+	ej.frames = frames
+	ej.end = pos
+	return n, nil
+}
+
+func (ej *encryptedJournal) Read(p []byte) (int, error) {
+	n, err := ej.ReadAt(p, ej.pos)
+	ej.pos += int64(n)
+	if n > 0 && errors.Is(err, io.EOF) {
+		err = nil
+	}
+	return n, err
+}
+
+func (ej *encryptedJournal) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += ej.pos
+	case io.SeekEnd:
+		offset += ej.reader(ej.f).size()
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+	ej.pos = offset
+	return offset, nil
+}
+
+func (ej *encryptedJournal) Sync() error {
+	return ej.f.Sync()
+}
+
+func (ej *encryptedJournal) Close() error {
+	return ej.f.Close()
+}
@@ -17,6 +17,7 @@ package nbs
 import (
 	"bufio"
 	"context"
+	"crypto/cipher"
 	"errors"
 	"fmt"
 	"hash/crc32"
@@ -77,7 +78,7 @@ func fileExists(path string) (bool, error) {
 	return true, nil
 }
 
-func openJournalWriter(ctx context.Context, path string) (wr *journalWriter, exists bool, err error) {
+func openJournalWriter(ctx context.Context, path string, enc *storeEncryption) (wr *journalWriter, exists bool, err error) {
 	var f *os.File
 	if path, err = filepath.Abs(path); err != nil {
 		return nil, false, err
@@ -95,14 +96,32 @@ func openJournalWriter(ctx context.Context, path string) (wr *journalWriter, exi
 		return nil, true, err
 	}
 
+	// an existing journal stays unencrypted until it's dropped by garbage collection, even if encryption at rest
+	// has been enabled for the store since it was created
+	aead, headerLen, err := openJournalAEAD(ctx, enc, f)
+	if err != nil {
+		f.Close()
+		return nil, true, err
+	}
+	var journal journalFile = f
+	var encrypted *encryptedJournal
+	if aead != nil {
+		if encrypted, err = newEncryptedJournal(f, aead, headerLen); err != nil {
+			f.Close()
+			return nil, true, err
+		}
+		journal = encrypted
+	}
+
 	return &journalWriter{
-		buf:     make([]byte, 0, journalWriterBuffSize),
-		journal: f,
-		path:    path,
+		buf:       make([]byte, 0, journalWriterBuffSize),
+		journal:   journal,
+		encrypted: encrypted,
+		path:      path,
 	}, true, nil
 }
 
-func createJournalWriter(ctx context.Context, path string) (wr *journalWriter, err error) {
+func createJournalWriter(ctx context.Context, path string, enc *storeEncryption) (wr *journalWriter, err error) {
 	var f *os.File
 	if path, err = filepath.Abs(path); err != nil {
 		return nil, err
@@ -118,6 +137,17 @@ func createJournalWriter(ctx context.Context, path string) (wr *journalWriter, e
 	if f, err = os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0666); err != nil {
 		return nil, err
 	}
+	var header []byte
+	var aead cipher.AEAD
+	if enc != nil {
+		// the zero fill of an encrypted journal is not encrypted, it marks the end of the journal's frames
+		if aead, header, err = enc.newJournalAEAD(ctx); err != nil {
+			return nil, err
+		}
+		if _, err = f.Write(header); err != nil {
+			return nil, err
+		}
+	}
 	const batch = 1024 * 1024
 	b := make([]byte, batch)
 	for i := 0; i < chunkJournalFileSize; i += batch {
@@ -128,16 +158,23 @@ func createJournalWriter(ctx context.Context, path string) (wr *journalWriter, e
 	if err = f.Sync(); err != nil {
 		return nil, err
 	}
-	if o, err := f.Seek(0, io.SeekStart); err != nil {
+	var journal journalFile = f
+	var encrypted *encryptedJournal
+	if aead != nil {
+		encrypted = &encryptedJournal{f: f, aead: aead, end: int64(len(header))}
+		journal = encrypted
+	}
+	if o, err := journal.Seek(0, io.SeekStart); err != nil {
 		return nil, err
 	} else if o != 0 {
 		return nil, fmt.Errorf("expected file journalOffset 0, got %d", o)
 	}
 
 	return &journalWriter{
-		buf:     make([]byte, 0, journalWriterBuffSize),
-		journal: f,
-		path:    path,
+		buf:       make([]byte, 0, journalWriterBuffSize),
+		journal:   journal,
+		encrypted: encrypted,
+		path:      path,
 	}, nil
 }
 
@@ -149,10 +186,22 @@ func deleteJournalAndIndexFiles(ctx context.Context, path string) (err error) {
 	return os.Remove(idxPath)
 }
 
+// journalFile is the file a journalWriter reads and writes journal records in. It is either an *os.File, or an
+// *encryptedJournal for journals encrypted at rest.
+type journalFile interface {
+	io.ReadSeeker
+	io.ReaderAt
+	io.WriterAt
+	Sync() error
+	Close() error
+}
+
 type journalWriter struct {
 	buf []byte
 
-	journal *os.File
+	journal journalFile
+	// encrypted is |journal| if the journal is encrypted at rest, or nil if it is not
+	encrypted *encryptedJournal
 	// off indicates the last position that has been written to the journal buffer
 	off     int64
 	indexed int64
@@ -182,7 +231,7 @@ var _ io.Closer = &journalWriter{}
 // are added to the novel ranges map. If the number of novel lookups exceeds |wr.maxNovel|, we
 // extend the jounral index with one metadata flush before existing this function to save indexing
 // progress.
-func (wr *journalWriter) bootstrapJournal(ctx context.Context, reflogRingBuffer *reflogRingBuffer) (last hash.Hash, err error) {
+func (wr *journalWriter) bootstrapJournal(ctx context.Context, reflogRingBuffer reflogBuffer) (last hash.Hash, err error) {
 	wr.lock.Lock()
 	defer wr.lock.Unlock()
 
@@ -596,8 +645,12 @@ func (wr *journalWriter) snapshot(ctx context.Context) (io.ReadCloser, int64, er
 	if err != nil {
 		return nil, 0, err
 	}
+	var r io.Reader = f
+	if wr.encrypted != nil {
+		r = io.NewSectionReader(wr.encrypted.reader(f), 0, wr.off)
+	}
 	return journalWriterSnapshot{
-		io.LimitReader(f, wr.off),
+		io.LimitReader(r, wr.off),
 		func() error {
 			return f.Close()
 		},
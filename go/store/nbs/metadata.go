@@ -15,6 +15,7 @@
 package nbs
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 
@@ -111,6 +112,18 @@ func GetStorageMetadata(path string) (StorageMetadata, error) {
 		return StorageMetadata{}, err
 	}
 
+	// The table files and archives of an oldgen with tiered storage are not on the local filesystem.
+	if tiered, err := LoadTieredStorageConfig(oldgen); err != nil {
+		return StorageMetadata{}, err
+	} else if tiered != nil {
+		return StorageMetadata{path, nil}, nil
+	}
+
+	enc, err := loadStoreEncryption(oldgen)
+	if err != nil {
+		return StorageMetadata{}, err
+	}
+
 	var artifacts []StorageArtifact
 
 	// for each table in the manifest, get the table spec
@@ -134,7 +147,7 @@ func GetStorageMetadata(path string) (StorageMetadata, error) {
 			_, err := os.Stat(arcPath)
 			if err == nil {
 				// reader for the path. State. call
-				reader, fileSize, err := openReader(arcPath)
+				reader, fileSize, err := openReader(context.Background(), arcPath, enc)
 				if err != nil {
 					return StorageMetadata{}, err
 				}
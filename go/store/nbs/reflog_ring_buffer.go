@@ -33,6 +33,19 @@ type reflogRootHashEntry struct {
 	timestamp time.Time
 }
 
+// reflogBuffer holds the root hash updates recorded to the chunk journal that are exposed through the reflog.
+type reflogBuffer interface {
+	// Push records |newItem| as the most recent entry.
+	Push(newItem reflogRootHashEntry)
+	// Iterate invokes |f| on each entry, from the oldest entry to the most recent entry.
+	Iterate(f func(item reflogRootHashEntry) error) error
+	// Truncate removes every entry.
+	Truncate()
+}
+
+var _ reflogBuffer = (*reflogRingBuffer)(nil)
+var _ reflogBuffer = (*reflogUnboundedBuffer)(nil)
+
 // reflogRingBuffer is a fixed size circular buffer that allows the most recent N entries to be iterated over (where
 // N is equal to the size requested when this ring buffer is constructed. Its locking strategy assumes that
 // only new entries are written to the head (through Push) and that existing entries will never need to be
@@ -191,3 +204,67 @@ func (rb *reflogRingBuffer) insertionIndexIsInRange(rangeStart, rangeEnd int, st
 		return true
 	}
 }
+
+// reflogUnboundedBuffer is a reflogBuffer that never evicts entries on its own, so that every root recorded in the
+// chunk journal stays available to the reflog until it is archived by the persistent reflog and dropped with
+// TruncateThrough. Entries are only ever appended or dropped from the front, so iteration works on a snapshot of
+// the entries taken when iteration starts.
+type reflogUnboundedBuffer struct {
+	items []reflogRootHashEntry
+	mu    *sync.Mutex
+}
+
+// newReflogUnboundedBuffer creates a new, empty reflogUnboundedBuffer.
+func newReflogUnboundedBuffer() *reflogUnboundedBuffer {
+	return &reflogUnboundedBuffer{mu: &sync.Mutex{}}
+}
+
+// Push appends |newItem| to this buffer.
+func (ub *reflogUnboundedBuffer) Push(newItem reflogRootHashEntry) {
+	ub.mu.Lock()
+	defer ub.mu.Unlock()
+	ub.items = append(ub.items, newItem)
+}
+
+// Iterate traverses every entry in this buffer, from the oldest to the most recent entry, and invokes the specified
+// callback function, |f|, on each entry. Entries pushed after iteration starts are not visited.
+func (ub *reflogUnboundedBuffer) Iterate(f func(item reflogRootHashEntry) error) error {
+	ub.mu.Lock()
+	items := ub.items[:len(ub.items):len(ub.items)]
+	ub.mu.Unlock()
+
+	for _, item := range items {
+		if err := f(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Len returns the number of entries in this buffer.
+func (ub *reflogUnboundedBuffer) Len() int {
+	ub.mu.Lock()
+	defer ub.mu.Unlock()
+	return len(ub.items)
+}
+
+// Truncate resets this buffer so that it is empty.
+func (ub *reflogUnboundedBuffer) Truncate() {
+	ub.mu.Lock()
+	defer ub.mu.Unlock()
+	ub.items = nil
+}
+
+// TruncateThrough drops the entries up to and including the most recent entry for |root|. Nothing is dropped if no
+// entry is for |root|.
+func (ub *reflogUnboundedBuffer) TruncateThrough(root string) {
+	ub.mu.Lock()
+	defer ub.mu.Unlock()
+	for i := len(ub.items) - 1; i >= 0; i-- {
+		if ub.items[i].root == root {
+			// copy the remaining entries, so that the dropped ones can be freed
+			ub.items = append([]reflogRootHashEntry(nil), ub.items[i+1:]...)
+			return
+		}
+	}
+}
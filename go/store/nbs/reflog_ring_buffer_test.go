@@ -138,14 +138,81 @@ func TestIterationConflict(t *testing.T) {
 	require.True(t, iterationCount < 5)
 }
 
-func insertTestRecord(buffer *reflogRingBuffer, root string) {
+// TestUnboundedBuffer asserts that an unbounded reflog buffer never evicts entries, that entries pushed during
+// iteration are not visited, and that Truncate empties the buffer.
+func TestUnboundedBuffer(t *testing.T) {
+	buffer := newReflogUnboundedBuffer()
+	assertExpectedIterationOrder(t, buffer, []string{})
+
+	expected := make([]string, 0, 20_000)
+	for i := 0; i < 20_000; i++ {
+		root := fmt.Sprintf("r-%d", i)
+		insertTestRecord(buffer, root)
+		expected = append(expected, root)
+	}
+	assertExpectedIterationOrder(t, buffer, expected)
+
+	iterationCount := 0
+	err := buffer.Iterate(func(item reflogRootHashEntry) error {
+		insertTestRecord(buffer, fmt.Sprintf("i-%d", iterationCount))
+		iterationCount++
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 20_000, iterationCount)
+
+	buffer.Truncate()
+	assertExpectedIterationOrder(t, buffer, []string{})
+	insertTestRecord(buffer, "aaaa")
+	assertExpectedIterationOrder(t, buffer, []string{"aaaa"})
+}
+
+// TestUnboundedBufferTruncateThrough asserts that TruncateThrough drops the entries up to the most recent entry for a
+// root, and nothing if no entry is for the root.
+func TestUnboundedBufferTruncateThrough(t *testing.T) {
+	buffer := newReflogUnboundedBuffer()
+	for _, root := range []string{"aaaa", "bbbb", "aaaa", "cccc", "dddd"} {
+		insertTestRecord(buffer, root)
+	}
+
+	buffer.TruncateThrough("eeee")
+	assertExpectedIterationOrder(t, buffer, []string{"aaaa", "bbbb", "aaaa", "cccc", "dddd"})
+	buffer.TruncateThrough("aaaa")
+	assertExpectedIterationOrder(t, buffer, []string{"cccc", "dddd"})
+	buffer.TruncateThrough("dddd")
+	assertExpectedIterationOrder(t, buffer, []string{})
+}
+
+// TestEnablingPersistentReflog asserts that enabling the persistent reflog makes a journal's reflog stop dropping
+// entries, while keeping the entries its ring buffer already holds.
+func TestEnablingPersistentReflog(t *testing.T) {
+	j := &ChunkJournal{reflogRingBuffer: newReflogRingBuffer(2)}
+	for _, root := range []string{"aaaa", "bbbb", "cccc"} {
+		j.pushReflog(reflogRootHashEntry{root: root, timestamp: time.Now()})
+	}
+	assertExpectedIterationOrder(t, j.reflog(), []string{"bbbb", "cccc"})
+
+	j.SetPersistentReflog(true)
+	for _, root := range []string{"dddd", "eeee", "ffff"} {
+		j.pushReflog(reflogRootHashEntry{root: root, timestamp: time.Now()})
+	}
+	assertExpectedIterationOrder(t, j.reflog(), []string{"bbbb", "cccc", "dddd", "eeee", "ffff"})
+	assert.Equal(t, 5, j.ReflogLen())
+
+	// disabling it again keeps only the most recent roots
+	j.SetPersistentReflog(false)
+	assert.Equal(t, 0, j.ReflogLen())
+	assertExpectedIterationOrder(t, j.reflog(), []string{"bbbb", "cccc", "dddd", "eeee", "ffff"})
+}
+
+func insertTestRecord(buffer reflogBuffer, root string) {
 	buffer.Push(reflogRootHashEntry{
 		root:      root,
 		timestamp: time.Now(),
 	})
 }
 
-func assertExpectedIterationOrder(t *testing.T, buffer *reflogRingBuffer, expectedRoots []string) {
+func assertExpectedIterationOrder(t *testing.T, buffer reflogBuffer, expectedRoots []string) {
 	i := 0
 	err := buffer.Iterate(func(item reflogRootHashEntry) error {
 		assert.Equal(t, expectedRoots[i], item.root)
@@ -572,11 +572,32 @@ func NewNoConjoinBSStore(ctx context.Context, nbfVerStr string, bs blobstore.Blo
 	return newNomsBlockStore(ctx, nbfVerStr, mm, p, q, noopConjoiner{}, memTableSize)
 }
 
+// NewLocalStore returns a store persisted in |dir|, which is encrypted at rest with the encryption config in |dir|, if
+// it has one. The directory must be trusted to name the key provider of the store, see NewLocalStoreWithEncryption.
 func NewLocalStore(ctx context.Context, nbfVerStr string, dir string, memTableSize uint64, q MemoryQuotaProvider) (*NomsBlockStore, error) {
 	return newLocalStore(ctx, nbfVerStr, dir, memTableSize, defaultMaxTables, q)
 }
 
+// NewLocalStoreWithEncryption returns a store persisted in |dir| like NewLocalStore, but which is encrypted at rest
+// with |enc| instead of the encryption config in |dir|, and is not encrypted if |enc| is nil. It's used to open stores
+// whose directory isn't trusted, like file remotes and backups, whose encryption config could name a command to run.
+func NewLocalStoreWithEncryption(ctx context.Context, nbfVerStr string, dir string, memTableSize uint64, q MemoryQuotaProvider, enc *EncryptionConfig) (*NomsBlockStore, error) {
+	se, err := newStoreEncryption(enc)
+	if err != nil {
+		return nil, err
+	}
+	return newLocalStoreWithEncryption(ctx, nbfVerStr, dir, memTableSize, defaultMaxTables, q, se)
+}
+
 func newLocalStore(ctx context.Context, nbfVerStr string, dir string, memTableSize uint64, maxTables int, q MemoryQuotaProvider) (*NomsBlockStore, error) {
+	enc, err := loadStoreEncryption(dir)
+	if err != nil {
+		return nil, err
+	}
+	return newLocalStoreWithEncryption(ctx, nbfVerStr, dir, memTableSize, maxTables, q, enc)
+}
+
+func newLocalStoreWithEncryption(ctx context.Context, nbfVerStr string, dir string, memTableSize uint64, maxTables int, q MemoryQuotaProvider, enc *storeEncryption) (*NomsBlockStore, error) {
 	cacheOnce.Do(makeGlobalCaches)
 	if err := checkDir(dir); err != nil {
 		return nil, err
@@ -592,13 +613,34 @@ func newLocalStore(ctx context.Context, nbfVerStr string, dir string, memTableSi
 	if err != nil {
 		return nil, err
 	}
-	p := newFSTablePersister(dir, q)
+	p := newFSTablePersister(dir, q, enc)
 	c := conjoinStrategy(inlineConjoiner{maxTables})
 
 	return newNomsBlockStore(ctx, nbfVerStr, makeManifestManager(m), p, q, c, memTableSize)
 }
 
+// NewLocalJournalingStore returns a store persisted in |dir| with a chunk journal, which is encrypted at rest with the
+// encryption config in |dir|, if it has one. The directory must be trusted to name the key provider of the store, see
+// NewLocalJournalingStoreWithEncryption.
 func NewLocalJournalingStore(ctx context.Context, nbfVers, dir string, q MemoryQuotaProvider) (*NomsBlockStore, error) {
+	enc, err := loadStoreEncryption(dir)
+	if err != nil {
+		return nil, err
+	}
+	return newLocalJournalingStore(ctx, nbfVers, dir, q, enc)
+}
+
+// NewLocalJournalingStoreWithEncryption returns a store like NewLocalJournalingStore, but which is encrypted at rest
+// with |enc| instead of the encryption config in |dir|, and is not encrypted if |enc| is nil.
+func NewLocalJournalingStoreWithEncryption(ctx context.Context, nbfVers, dir string, q MemoryQuotaProvider, enc *EncryptionConfig) (*NomsBlockStore, error) {
+	se, err := newStoreEncryption(enc)
+	if err != nil {
+		return nil, err
+	}
+	return newLocalJournalingStore(ctx, nbfVers, dir, q, se)
+}
+
+func newLocalJournalingStore(ctx context.Context, nbfVers, dir string, q MemoryQuotaProvider, enc *storeEncryption) (*NomsBlockStore, error) {
 	cacheOnce.Do(makeGlobalCaches)
 	if err := checkDir(dir); err != nil {
 		return nil, err
@@ -608,7 +650,7 @@ func NewLocalJournalingStore(ctx context.Context, nbfVers, dir string, q MemoryQ
 	if err != nil {
 		return nil, err
 	}
-	p := newFSTablePersister(dir, q)
+	p := newFSTablePersister(dir, q, enc)
 
 	journal, err := newChunkJournal(ctx, nbfVers, dir, m, p.(*fsTablePersister))
 	if err != nil {
@@ -1690,7 +1732,7 @@ func copyMarkedChunks(ctx context.Context, keepChunks <-chan []hash.Hash, src NB
 		return nil, fmt.Errorf("NBS does not support copying garbage collection")
 	}
 
-	gcc, err := newGarbageCollectionCopier()
+	gcc, err := newGarbageCollectionCopier(encryptionOf(dest.p) != nil)
 	if err != nil {
 		return nil, err
 	}
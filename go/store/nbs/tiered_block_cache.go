@@ -0,0 +1,262 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// tieredBlockSize is the size of the blocks of remote table files and archives that are cached on local disk
+	// by a tiered store. The last block of a file may be smaller.
+	tieredBlockSize = 1 << 20
+
+	blockCacheTempPrefix = "tmp-"
+)
+
+// blockCache is a bounded, on-disk cache of the blocks of remote storage files. Blocks are stored as individual
+// files named <dir>/<storage file>/<block index>, and the least recently used blocks are evicted once the total
+// size of the cached blocks exceeds |maxSize|. Cached blocks survive restarts; their order of use is recovered from
+// their modification times.
+type blockCache struct {
+	dir     string
+	maxSize uint64
+
+	mu   sync.Mutex
+	size uint64
+	// lru holds *cachedBlock, with the most recently used block at the front.
+	lru    *list.List
+	blocks map[cachedBlockKey]*list.Element
+}
+
+type cachedBlockKey struct {
+	file string
+	idx  int64
+}
+
+type cachedBlock struct {
+	key  cachedBlockKey
+	size uint64
+}
+
+func newBlockCache(dir string, maxSize uint64) (*blockCache, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	c := &blockCache{
+		dir:     dir,
+		maxSize: maxSize,
+		lru:     list.New(),
+		blocks:  make(map[cachedBlockKey]*list.Element),
+	}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// load populates the cache with the blocks already on disk, removing any partially written or oversized blocks.
+func (c *blockCache) load() error {
+	type onDisk struct {
+		blk   *cachedBlock
+		mtime time.Time
+	}
+	var found []onDisk
+
+	files, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if !f.IsDir() {
+			continue
+		}
+		entries, err := os.ReadDir(filepath.Join(c.dir, f.Name()))
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			path := filepath.Join(c.dir, f.Name(), e.Name())
+			if strings.HasPrefix(e.Name(), blockCacheTempPrefix) {
+				if err = os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+					return err
+				}
+				continue
+			}
+			idx, err := strconv.ParseInt(e.Name(), 10, 64)
+			if err != nil || !e.Type().IsRegular() {
+				continue
+			}
+			info, err := e.Info()
+			if err != nil {
+				return err
+			}
+			if info.Size() > tieredBlockSize {
+				if err = os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+					return err
+				}
+				continue
+			}
+			found = append(found, onDisk{
+				blk:   &cachedBlock{key: cachedBlockKey{f.Name(), idx}, size: uint64(info.Size())},
+				mtime: info.ModTime(),
+			})
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].mtime.Before(found[j].mtime)
+	})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, d := range found {
+		c.blocks[d.blk.key] = c.lru.PushFront(d.blk)
+		c.size += d.blk.size
+	}
+	return c.evict()
+}
+
+func (c *blockCache) path(key cachedBlockKey) string {
+	return filepath.Join(c.dir, key.file, strconv.FormatInt(key.idx, 10))
+}
+
+// get returns the block identified by |key|, which is |size| bytes long, calling |fetch| to read it from remote
+// storage if it is not cached. Cached blocks of the wrong size are discarded and fetched again.
+func (c *blockCache) get(key cachedBlockKey, size int, fetch func() ([]byte, error)) ([]byte, error) {
+	c.mu.Lock()
+	_, ok := c.blocks[key]
+	if ok {
+		c.lru.MoveToFront(c.blocks[key])
+	}
+	c.mu.Unlock()
+
+	if ok {
+		data, err := os.ReadFile(c.path(key))
+		if err == nil && len(data) == size {
+			return data, nil
+		} else if err == nil {
+			if err = c.remove(key); err != nil {
+				return nil, err
+			}
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		// the block was evicted after we found it, or was corrupt, fetch it again.
+	}
+
+	data, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	if err = c.put(key, size, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// put adds |data| to the cache as the block identified by |key|, which must be |size| bytes long. The block is
+// synced to disk before it is added, so that a crash never leaves a partially written block in the cache.
+func (c *blockCache) put(key cachedBlockKey, size int, data []byte) error {
+	if len(data) != size || size > tieredBlockSize {
+		return fmt.Errorf("invalid block %d of %s: expected %d bytes, got %d", key.idx, key.file, size, len(data))
+	}
+
+	dir := filepath.Join(c.dir, key.file)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, blockCacheTempPrefix)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), c.path(key))
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.blocks[key]; ok {
+		// another reader cached this block concurrently
+		c.lru.MoveToFront(e)
+		return nil
+	}
+	c.blocks[key] = c.lru.PushFront(&cachedBlock{key: key, size: uint64(len(data))})
+	c.size += uint64(len(data))
+	return c.evict()
+}
+
+// remove removes the block identified by |key| from the cache.
+func (c *blockCache) remove(key cachedBlockKey) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.blocks[key]; ok {
+		blk := c.lru.Remove(e).(*cachedBlock)
+		delete(c.blocks, key)
+		c.size -= blk.size
+	}
+	if err := os.Remove(c.path(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// removeFile removes all cached blocks of the storage file |file|, which has been deleted from remote storage.
+func (c *blockCache) removeFile(file string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, e := range c.blocks {
+		if key.file != file {
+			continue
+		}
+		blk := c.lru.Remove(e).(*cachedBlock)
+		delete(c.blocks, key)
+		c.size -= blk.size
+	}
+	return os.RemoveAll(filepath.Join(c.dir, file))
+}
+
+// evict removes the least recently used blocks until the cache is within its size limit. Callers must hold |c.mu|.
+func (c *blockCache) evict() error {
+	for c.size > c.maxSize && c.lru.Len() > 0 {
+		blk := c.lru.Remove(c.lru.Back()).(*cachedBlock)
+		delete(c.blocks, blk.key)
+		c.size -= blk.size
+		if err := os.Remove(c.path(blk.key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,477 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/utils/file"
+	"github.com/dolthub/dolt/go/store/blobstore"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/util/tempfiles"
+)
+
+// tieredTablePersister persists the table files of a store to a Blobstore, and reads table files and archives from
+// the Blobstore through a local blockCache. It is used for the old generation of a store with tiered storage, whose
+// manifest is kept on local disk in |dir|.
+type tieredTablePersister struct {
+	dir   string
+	bs    blobstore.Blobstore
+	cache *blockCache
+	q     MemoryQuotaProvider
+
+	mu sync.Mutex
+	// files holds the sizes of the table files and archives this persister has opened or written, keyed by their
+	// names in the Blobstore.
+	files map[string]uint64
+}
+
+var _ tablePersister = &tieredTablePersister{}
+var _ tableFilePersister = &tieredTablePersister{}
+
+func newTieredTablePersister(dir string, bs blobstore.Blobstore, cache *blockCache, q MemoryQuotaProvider) *tieredTablePersister {
+	return &tieredTablePersister{dir: dir, bs: bs, cache: cache, q: q, files: make(map[string]uint64)}
+}
+
+// tieredPersisters holds the persisters of the open tiered stores, keyed by the directory of their manifest, so that
+// OpenStorageFile can read files which have been offloaded from those directories.
+var tieredPersisters = struct {
+	sync.Mutex
+	m map[string]*tieredTablePersister
+}{m: make(map[string]*tieredTablePersister)}
+
+// tieredPersisterKey returns the key of the old generation directory |dir| in the registry of tiered persisters.
+func tieredPersisterKey(dir string) string {
+	if abs, err := filepath.Abs(dir); err == nil {
+		return abs
+	}
+	return filepath.Clean(dir)
+}
+
+func registerTieredPersister(tp *tieredTablePersister) {
+	tieredPersisters.Lock()
+	defer tieredPersisters.Unlock()
+	tieredPersisters.m[tieredPersisterKey(tp.dir)] = tp
+}
+
+func unregisterTieredPersister(tp *tieredTablePersister) {
+	tieredPersisters.Lock()
+	defer tieredPersisters.Unlock()
+	if tieredPersisters.m[tieredPersisterKey(tp.dir)] == tp {
+		delete(tieredPersisters.m, tieredPersisterKey(tp.dir))
+	}
+}
+
+// openTieredStorageFile opens the storage file at |path| from the Blobstore of the open tiered store whose manifest
+// is in the directory of |path|. It returns os.ErrNotExist if there is no such store, or if the store has not opened
+// or written the file.
+func openTieredStorageFile(path string) (*tieredReaderAt, int64, error) {
+	tieredPersisters.Lock()
+	tp := tieredPersisters.m[tieredPersisterKey(filepath.Dir(path))]
+	tieredPersisters.Unlock()
+	if tp == nil {
+		return nil, 0, &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+	}
+
+	key := filepath.Base(path)
+	sz, ok := tp.fileSize(key)
+	if !ok {
+		return nil, 0, &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+	}
+	return tp.newReaderAt(key, sz), int64(sz), nil
+}
+
+func (tp *tieredTablePersister) addFile(key string, size uint64) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	tp.files[key] = size
+}
+
+func (tp *tieredTablePersister) fileSize(key string) (uint64, bool) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	sz, ok := tp.files[key]
+	return sz, ok
+}
+
+// isArchive returns true if the storage file |name| is an archive.
+func (tp *tieredTablePersister) isArchive(name hash.Hash) bool {
+	_, ok := tp.fileSize(name.String() + archiveFileSuffix)
+	return ok
+}
+
+// Persist makes the contents of mt durable. Chunks already present in
+// |haver| may be dropped in the process.
+func (tp *tieredTablePersister) Persist(ctx context.Context, mt *memTable, haver chunkReader, stats *Stats) (chunkSource, error) {
+	address, data, chunkCount, err := mt.write(haver, stats)
+	if err != nil {
+		return emptyChunkSource{}, err
+	} else if chunkCount == 0 {
+		return emptyChunkSource{}, nil
+	}
+	name := address.String()
+
+	if _, err = tp.bs.Put(ctx, name, int64(len(data)), bytes.NewReader(data)); err != nil {
+		return emptyChunkSource{}, err
+	}
+	tp.addFile(name, uint64(len(data)))
+
+	rdr := tp.newReaderAt(name, uint64(len(data)))
+	return newReaderFromIndexData(ctx, tp.q, data, address, rdr, s3BlockSize)
+}
+
+// ConjoinAll implements tablePersister. The conjoined table file is written to a local temporary file, since not all
+// Blobstores can concatenate blobs, and then uploaded to the Blobstore.
+func (tp *tieredTablePersister) ConjoinAll(ctx context.Context, sources chunkSources, stats *Stats) (chunkSource, cleanupFunc, error) {
+	plan, err := planRangeCopyConjoin(sources, stats)
+	if err != nil {
+		return emptyChunkSource{}, nil, err
+	}
+
+	if plan.chunkCount == 0 {
+		return emptyChunkSource{}, func() {}, nil
+	}
+	name := nameFromSuffixes(plan.suffixes())
+
+	temp, err := tempfiles.MovableTempFileProvider.NewFile("", tempTablePrefix)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		_ = temp.Close()
+		_ = file.Remove(temp.Name())
+	}()
+
+	for _, sws := range plan.sources.sws {
+		r, _, err := sws.source.reader(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		n, err := io.CopyN(temp, r, int64(sws.dataLen))
+		if cerr := r.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return nil, nil, err
+		} else if uint64(n) != sws.dataLen {
+			return nil, nil, errors.New("failed to copy all data")
+		}
+	}
+	if _, err = temp.Write(plan.mergedIndex); err != nil {
+		return nil, nil, err
+	}
+	if _, err = temp.Seek(0, io.SeekStart); err != nil {
+		return nil, nil, err
+	}
+
+	size := plan.totalCompressedData + uint64(len(plan.mergedIndex))
+	if _, err = tp.bs.Put(ctx, name.String(), int64(size), temp); err != nil {
+		return nil, nil, err
+	}
+
+	cs, err := tp.Open(ctx, name, plan.chunkCount, stats)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cs, func() {
+		for _, s := range sources {
+			_ = tp.removeStorageFile(context.Background(), s.hash().String())
+		}
+	}, nil
+}
+
+// removeStorageFile deletes the storage file |key| from the Blobstore, and its blocks from the local block cache.
+func (tp *tieredTablePersister) removeStorageFile(ctx context.Context, key string) error {
+	tp.mu.Lock()
+	delete(tp.files, key)
+	tp.mu.Unlock()
+
+	if err := tp.bs.Delete(ctx, key); err != nil {
+		return err
+	}
+	return tp.cache.removeFile(key)
+}
+
+// Open a table named |name|, containing |chunkCount| chunks.
+func (tp *tieredTablePersister) Open(ctx context.Context, name hash.Hash, chunkCount uint32, stats *Stats) (chunkSource, error) {
+	ok, err := tp.bs.Exists(ctx, name.String())
+	if err != nil {
+		return nil, err
+	} else if ok {
+		return tp.openTableFile(ctx, name, chunkCount, stats)
+	}
+
+	ok, err = tp.bs.Exists(ctx, name.String()+archiveFileSuffix)
+	if err != nil {
+		return nil, err
+	} else if ok {
+		return tp.openArchive(ctx, name)
+	}
+	return nil, fmt.Errorf("table file %s not found in %s", name.String(), tp.bs.Path())
+}
+
+func (tp *tieredTablePersister) openTableFile(ctx context.Context, name hash.Hash, chunkCount uint32, stats *Stats) (chunkSource, error) {
+	// the index is held in memory once loaded, so it is read directly rather than through the block cache
+	index, err := loadTableIndex(ctx, stats, chunkCount, tp.q, func(p []byte) error {
+		rc, _, err := tp.bs.Get(ctx, name.String(), blobstore.NewBlobRange(-int64(len(p)), 0))
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+
+		_, err = io.ReadFull(rc, p)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if chunkCount != index.chunkCount() {
+		_ = index.Close()
+		return nil, errors.New("unexpected chunk count")
+	}
+
+	tr, err := newTableReader(index, tp.newReaderAt(name.String(), index.tableFileSize()), s3BlockSize)
+	if err != nil {
+		_ = index.Close()
+		return nil, err
+	}
+	tp.addFile(name.String(), index.tableFileSize())
+	return &chunkSourceAdapter{tr, name}, nil
+}
+
+func (tp *tieredTablePersister) openArchive(ctx context.Context, name hash.Hash) (chunkSource, error) {
+	key := name.String() + archiveFileSuffix
+	sz, err := tp.archiveSize(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	aRdr, err := newArchiveReader(tp.newReaderAt(key, sz), sz)
+	if err != nil {
+		return nil, err
+	}
+	tp.addFile(key, sz)
+	return archiveChunkSource{key, aRdr, nil}, nil
+}
+
+// archiveSize returns the size of the archive |key|. Blobstores don't expose the size of a blob, and archive footers
+// don't record the size of the archive, so it is computed from the size of the index and metadata in the footer and
+// the end offset of the last byte span in the index.
+func (tp *tieredTablePersister) archiveSize(ctx context.Context, key string) (uint64, error) {
+	buf, _, err := blobstore.GetBytes(ctx, tp.bs, key, blobstore.NewBlobRange(-int64(archiveFooterSize), 0))
+	if err != nil {
+		return 0, err
+	} else if uint64(len(buf)) != archiveFooterSize {
+		return 0, fmt.Errorf("invalid archive %s: unexpected footer size %d", key, len(buf))
+	}
+	ftr, err := loadFooter(bytes.NewReader(buf), archiveFooterSize)
+	if err != nil {
+		return 0, err
+	}
+
+	tailSz := uint64(ftr.indexSize) + uint64(ftr.metadataSize) + archiveFooterSize
+	if ftr.byteSpanCount == 0 {
+		return tailSz, nil
+	}
+
+	// the byte span offsets are the first section of the index
+	lastSpanEnd := tailSz - uint64(ftr.byteSpanCount-1)*uint64Size
+	buf, _, err = blobstore.GetBytes(ctx, tp.bs, key, blobstore.NewBlobRange(-int64(lastSpanEnd), uint64Size))
+	if err != nil {
+		return 0, err
+	} else if len(buf) < uint64Size {
+		return 0, fmt.Errorf("invalid archive %s: truncated index", key)
+	}
+	return binary.BigEndian.Uint64(buf[:uint64Size]) + tailSz, nil
+}
+
+func (tp *tieredTablePersister) newReaderAt(key string, size uint64) *tieredReaderAt {
+	return &tieredReaderAt{key: key, size: size, bs: tp.bs, cache: tp.cache}
+}
+
+func (tp *tieredTablePersister) Exists(ctx context.Context, name hash.Hash, chunkCount uint32, stats *Stats) (bool, error) {
+	ok, err := tp.bs.Exists(ctx, name.String())
+	if ok || err != nil {
+		return ok, err
+	}
+	return tp.bs.Exists(ctx, name.String()+archiveFileSuffix)
+}
+
+// PruneTableFiles deletes the table files and archives in the Blobstore which are not in |keeper| and were last
+// modified before |mtime|, along with their blocks in the local block cache. The Blobstore must only hold the files
+// of this store.
+func (tp *tieredTablePersister) PruneTableFiles(ctx context.Context, keeper func() []hash.Hash, mtime time.Time) error {
+	blobs, err := tp.bs.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	toKeep := make(map[hash.Hash]struct{})
+	for _, k := range keeper() {
+		toKeep[k] = struct{}{}
+	}
+
+	ea := make(gcErrAccum)
+	for _, blob := range blobs {
+		h, ok := hash.MaybeParse(strings.TrimSuffix(blob.Key, archiveFileSuffix))
+		if !ok {
+			continue // not a table file or archive
+		}
+		if _, ok = toKeep[h]; ok {
+			continue
+		}
+		if blob.Modified.After(mtime) {
+			continue // file has been updated more recently than our cutoff time
+		}
+		if err = tp.removeStorageFile(ctx, blob.Key); err != nil {
+			ea.add(blob.Key, err)
+		}
+	}
+
+	if !ea.isEmpty() {
+		return ea
+	}
+	return nil
+}
+
+func (tp *tieredTablePersister) Close() error {
+	unregisterTieredPersister(tp)
+	return nil
+}
+
+func (tp *tieredTablePersister) AccessMode() chunks.ExclusiveAccessMode {
+	return chunks.ExclusiveAccessMode_Shared
+}
+
+func (tp *tieredTablePersister) Path() string {
+	return tp.bs.Path()
+}
+
+func (tp *tieredTablePersister) CopyTableFile(ctx context.Context, r io.Reader, name string, fileSz uint64, chunkCount uint32) error {
+	// sanity check file size
+	if fileSz < indexSize(chunkCount)+footerSize {
+		return fmt.Errorf("table file size %d too small for chunk count %d", fileSz, chunkCount)
+	}
+
+	if _, err := tp.bs.Put(ctx, name, int64(fileSz), r); err != nil {
+		return err
+	}
+	tp.addFile(name, fileSz)
+	return nil
+}
+
+// uploadArchive uploads the archive at |path| to the Blobstore as the archive |name|.
+func (tp *tieredTablePersister) uploadArchive(ctx context.Context, name hash.Hash, path string) error {
+	key := name.String() + archiveFileSuffix
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if _, err = tp.bs.Put(ctx, key, info.Size(), f); err != nil {
+		return err
+	}
+	tp.addFile(key, uint64(info.Size()))
+	return nil
+}
+
+// tieredReaderAt reads a table file or archive of |size| bytes stored in a Blobstore, in blocks of
+// tieredBlockSize that are cached on local disk.
+type tieredReaderAt struct {
+	key   string
+	size  uint64
+	bs    blobstore.Blobstore
+	cache *blockCache
+}
+
+var _ tableReaderAt = &tieredReaderAt{}
+var _ io.ReaderAt = &tieredReaderAt{}
+
+func (tr *tieredReaderAt) ReadAtWithStats(ctx context.Context, p []byte, off int64, stats *Stats) (int, error) {
+	t1 := time.Now()
+	defer stats.FileReadLatency.SampleTimeSince(t1)
+	return tr.readAt(ctx, p, off)
+}
+
+// ReadAt implements io.ReaderAt, for archive readers.
+func (tr *tieredReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	return tr.readAt(context.Background(), p, off)
+}
+
+func (tr *tieredReaderAt) readAt(ctx context.Context, p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, fmt.Errorf("invalid offset %d reading %s", off, tr.key)
+	}
+	for n < len(p) {
+		pos := off + int64(n)
+		if uint64(pos) >= tr.size {
+			return n, io.EOF
+		}
+		idx := pos / tieredBlockSize
+		blk, err := tr.block(ctx, idx)
+		if err != nil {
+			return n, err
+		}
+		n += copy(p[n:], blk[pos-idx*tieredBlockSize:])
+	}
+	return n, nil
+}
+
+func (tr *tieredReaderAt) block(ctx context.Context, idx int64) ([]byte, error) {
+	off := idx * tieredBlockSize
+	l := int64(tr.size) - off
+	if l > tieredBlockSize {
+		l = tieredBlockSize
+	}
+	return tr.cache.get(cachedBlockKey{tr.key, idx}, int(l), func() ([]byte, error) {
+		data, _, err := blobstore.GetBytes(ctx, tr.bs, tr.key, blobstore.NewBlobRange(off, l))
+		if err != nil {
+			return nil, err
+		} else if int64(len(data)) != l {
+			return nil, fmt.Errorf("short read of %s: expected %d bytes at offset %d, got %d", tr.key, l, off, len(data))
+		}
+		return data, nil
+	})
+}
+
+func (tr *tieredReaderAt) Reader(ctx context.Context) (io.ReadCloser, error) {
+	rc, _, err := tr.bs.Get(ctx, tr.key, blobstore.AllRange)
+	return rc, err
+}
+
+func (tr *tieredReaderAt) Close() error {
+	return nil
+}
+
+func (tr *tieredReaderAt) clone() (tableReaderAt, error) {
+	return tr, nil
+}
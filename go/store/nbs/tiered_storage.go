@@ -0,0 +1,356 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/dolthub/fslock"
+
+	"github.com/dolthub/dolt/go/libraries/utils/file"
+	"github.com/dolthub/dolt/go/store/blobstore"
+	"github.com/dolthub/dolt/go/store/util/tempfiles"
+)
+
+const (
+	// TieredStorageConfigFileName is the name of the file in a store's directory that configures tiered storage for
+	// the store.
+	TieredStorageConfigFileName = "tiered_storage.json"
+
+	// DefaultTieredCacheSize is the size of the local block cache of a tiered store if none is configured.
+	DefaultTieredCacheSize = 1 << 30
+
+	defaultTieredCacheDir = "tiered_cache"
+)
+
+// TieredStorageConfig is the configuration for tiered storage of a store, persisted as JSON in the
+// TieredStorageConfigFileName file in the store's directory. A store with tiered storage keeps its manifest on local
+// disk, and its table files and archives in a Blobstore, reading them through a bounded local block cache.
+type TieredStorageConfig struct {
+	// BlobstoreURL is the URL of the Blobstore holding the store's table files, e.g. gs://bucket/path.
+	BlobstoreURL string `json:"blobstore_url"`
+	// CacheDir is the directory of the local block cache. Relative paths are relative to the store's directory.
+	CacheDir string `json:"cache_dir,omitempty"`
+	// CacheSize is the maximum size of the local block cache, in bytes.
+	CacheSize uint64 `json:"cache_size,omitempty"`
+	// Params are the parameters used to connect to the Blobstore, such as credentials files or cloud regions.
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// CacheDirFor returns the directory of the local block cache for the store in |dir|.
+func (cfg TieredStorageConfig) CacheDirFor(dir string) string {
+	if cfg.CacheDir == "" {
+		return filepath.Join(dir, defaultTieredCacheDir)
+	} else if !filepath.IsAbs(cfg.CacheDir) {
+		return filepath.Join(dir, cfg.CacheDir)
+	}
+	return cfg.CacheDir
+}
+
+// MaxCacheSize returns the maximum size of the local block cache, in bytes.
+func (cfg TieredStorageConfig) MaxCacheSize() uint64 {
+	if cfg.CacheSize == 0 {
+		return DefaultTieredCacheSize
+	}
+	return cfg.CacheSize
+}
+
+// LoadTieredStorageConfig loads the tiered storage config of the store in |dir|. If tiered storage is not configured
+// for the store, nil is returned.
+func LoadTieredStorageConfig(dir string) (*TieredStorageConfig, error) {
+	b, err := os.ReadFile(filepath.Join(dir, TieredStorageConfigFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var cfg TieredStorageConfig
+	if err = json.Unmarshal(b, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid tiered storage config %s: %w", filepath.Join(dir, TieredStorageConfigFileName), err)
+	}
+	if cfg.BlobstoreURL == "" {
+		return nil, fmt.Errorf("invalid tiered storage config %s: missing blobstore_url", filepath.Join(dir, TieredStorageConfigFileName))
+	}
+	return &cfg, nil
+}
+
+// ErrDatabaseLocked is returned when the files of a store are offloaded or recalled while its database is open.
+var ErrDatabaseLocked = errors.New("the database is locked by another dolt process")
+
+// NewTieredStore returns a store in |dir| whose manifest is kept on local disk, and whose table files and archives
+// are stored in |bs| and read through a local block cache in |cacheDir| holding at most |cacheSize| bytes. Tiered
+// stores are intended for the old generation of a GenerationalNBS, which is rarely written.
+func NewTieredStore(ctx context.Context, nbfVerStr string, dir string, bs blobstore.Blobstore, cacheDir string, cacheSize uint64, memTableSize uint64, q MemoryQuotaProvider) (*NomsBlockStore, error) {
+	cacheOnce.Do(makeGlobalCaches)
+	if err := checkDir(dir); err != nil {
+		return nil, err
+	}
+	if cfg, err := LoadEncryptionConfig(dir); err != nil {
+		return nil, err
+	} else if cfg != nil {
+		return nil, fmt.Errorf("tiered storage is not supported for stores with encryption at rest: %s", dir)
+	}
+
+	m, err := getFileManifest(ctx, dir, asyncFlush)
+	if err != nil {
+		return nil, err
+	}
+	cache, err := newBlockCache(cacheDir, cacheSize)
+	if err != nil {
+		return nil, err
+	}
+	p := newTieredTablePersister(dir, bs, cache, q)
+	c := tieredConjoiner{child: inlineConjoiner{defaultMaxTables}, p: p}
+
+	st, err := newNomsBlockStore(ctx, nbfVerStr, makeManifestManager(m), p, q, c, memTableSize)
+	if err != nil {
+		return nil, err
+	}
+	registerTieredPersister(p)
+	return st, nil
+}
+
+// tieredConjoiner conjoins the table files of a tiered store. Archives can't be conjoined, so they are never chosen
+// as conjoinees, and don't count towards the number of tables which requires a conjoin.
+type tieredConjoiner struct {
+	child conjoinStrategy
+	p     *tieredTablePersister
+}
+
+func (c tieredConjoiner) conjoinRequired(ts tableSet) bool {
+	tables := make(chunkSourceSet, len(ts.upstream))
+	for h, cs := range ts.upstream {
+		if _, ok := cs.(archiveChunkSource); !ok {
+			tables[h] = cs
+		}
+	}
+	ts.upstream = tables
+	return c.child.conjoinRequired(ts)
+}
+
+func (c tieredConjoiner) chooseConjoinees(upstream []tableSpec) (conjoinees, keepers []tableSpec, err error) {
+	var archives []tableSpec
+	tables := make([]tableSpec, 0, len(upstream))
+	for _, spec := range upstream {
+		if c.p.isArchive(spec.name) {
+			archives = append(archives, spec)
+		} else {
+			tables = append(tables, spec)
+		}
+	}
+	if len(tables) < 2 {
+		return nil, upstream, nil
+	}
+	conjoinees, keepers, err = c.child.chooseConjoinees(tables)
+	if err != nil {
+		return nil, nil, err
+	}
+	return conjoinees, append(keepers, archives...), nil
+}
+
+// lockDatabase takes the lock of the database whose old generation is in |dir|, and the lock of the old generation's
+// manifest, returning ErrDatabaseLocked if either is held by an open store. The database's lock is in the parent
+// directory of its old generation.
+func lockDatabase(dir string) (unlock func(), err error) {
+	var locks []*fslock.Lock
+	unlock = func() {
+		for i := len(locks) - 1; i >= 0; i-- {
+			_ = locks[i].Unlock()
+		}
+	}
+	for _, d := range []string{filepath.Dir(dir), dir} {
+		lck := fslock.New(filepath.Join(d, lockFileName))
+		if err = lck.TryLock(); errors.Is(err, fslock.ErrLocked) {
+			unlock()
+			return nil, ErrDatabaseLocked
+		} else if err != nil {
+			unlock()
+			return nil, err
+		}
+		locks = append(locks, lck)
+	}
+	return unlock, nil
+}
+
+// OffloadTableFiles uploads the table files and archives referenced by the manifest of the local store in |dir|,
+// which is the old generation of a database, to |bs|, writes |cfg| as the tiered storage config of the store, and
+// then removes the local copies. Files which already exist in |bs| are not uploaded again. The database's lock is held
+// while its files are offloaded, and ErrDatabaseLocked is returned if the database is open.
+func OffloadTableFiles(ctx context.Context, dir string, bs blobstore.Blobstore, cfg TieredStorageConfig) error {
+	unlock, err := lockDatabase(dir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if enc, err := LoadEncryptionConfig(dir); err != nil {
+		return err
+	} else if enc != nil {
+		return fmt.Errorf("tiered storage is not supported for stores with encryption at rest: %s", dir)
+	}
+
+	files, err := manifestStorageFiles(ctx, dir)
+	if err != nil {
+		return err
+	}
+
+	var local []string
+	for _, name := range files {
+		path := filepath.Join(dir, name)
+		if _, err = os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			// already offloaded
+			continue
+		} else if err != nil {
+			return err
+		}
+		if err = uploadFile(ctx, bs, name, path); err != nil {
+			return err
+		}
+		local = append(local, path)
+	}
+
+	b, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(filepath.Join(dir, TieredStorageConfigFileName), b, 0600); err != nil {
+		return err
+	}
+
+	for _, path := range local {
+		if err = file.Remove(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecallTableFiles downloads the table files and archives referenced by the manifest of the tiered store in |dir|
+// from |bs|, and then removes its tiered storage config and local block cache, so that the store is stored entirely
+// on local disk again. The database's lock is held while its files are recalled, and ErrDatabaseLocked is returned if
+// the database is open.
+func RecallTableFiles(ctx context.Context, dir string, bs blobstore.Blobstore) error {
+	unlock, err := lockDatabase(dir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	cfg, err := LoadTieredStorageConfig(dir)
+	if err != nil {
+		return err
+	} else if cfg == nil {
+		return fmt.Errorf("tiered storage is not configured for %s", dir)
+	}
+
+	files, err := manifestStorageFiles(ctx, dir)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range files {
+		path := filepath.Join(dir, name)
+		if _, err = os.Stat(path); err == nil {
+			continue
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if err = downloadFile(ctx, bs, name, path); err != nil {
+			return err
+		}
+	}
+
+	if err = file.Remove(filepath.Join(dir, TieredStorageConfigFileName)); err != nil {
+		return err
+	}
+	return file.RemoveAll(cfg.CacheDirFor(dir))
+}
+
+// manifestStorageFiles returns the names of the table files and archives referenced by the manifest in |dir|. Files
+// which are not on local disk are named as table files, even if they are archives.
+func manifestStorageFiles(ctx context.Context, dir string) ([]string, error) {
+	fm := fileManifest{dir: dir, mode: asyncFlush, lock: fslock.New(filepath.Join(dir, lockFileName))}
+	ok, contents, err := fm.ParseIfExists(ctx, &Stats{}, nil)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(contents.specs))
+	for _, spec := range contents.specs {
+		name := spec.name.String()
+		if ok, err := archiveFileExists(ctx, dir, spec.name); err != nil {
+			return nil, err
+		} else if ok {
+			name += archiveFileSuffix
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func uploadFile(ctx context.Context, bs blobstore.Blobstore, key, path string) error {
+	if ok, err := bs.Exists(ctx, key); err != nil || ok {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	_, err = bs.Put(ctx, key, info.Size(), f)
+	return err
+}
+
+func downloadFile(ctx context.Context, bs blobstore.Blobstore, key, path string) error {
+	rc, _, err := bs.Get(ctx, key, blobstore.AllRange)
+	if blobstore.IsNotFoundError(err) {
+		rc, _, err = bs.Get(ctx, key+archiveFileSuffix, blobstore.AllRange)
+		path += archiveFileSuffix
+	}
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	tmp, err := func() (string, error) {
+		f, err := tempfiles.MovableTempFileProvider.NewFile("", filepath.Base(path))
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+
+		if _, err = io.Copy(f, rc); err != nil {
+			return "", err
+		}
+		return f.Name(), f.Sync()
+	}()
+	if err != nil {
+		return err
+	}
+	return file.Rename(tmp, path)
+}
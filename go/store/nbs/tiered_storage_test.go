@@ -0,0 +1,316 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dolthub/fslock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/dolthub/dolt/go/store/blobstore"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/constants"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestTieredStoreSuite(t *testing.T) {
+	fn := func(ctx context.Context, dir string) (*NomsBlockStore, error) {
+		bsDir := filepath.Join(dir, "blobs")
+		if err := os.MkdirAll(bsDir, os.ModePerm); err != nil {
+			return nil, err
+		}
+		nbf := constants.FormatDefaultString
+		qp := NewUnlimitedMemQuotaProvider()
+		return NewTieredStore(ctx, nbf, dir, blobstore.NewLocalBlobstore(bsDir), filepath.Join(dir, "cache"), 4*tieredBlockSize, testMemTableSize, qp)
+	}
+	suite.Run(t, &BlockStoreSuite{factory: fn})
+}
+
+func TestBlockCache(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := newBlockCache(dir, 25)
+	require.NoError(t, err)
+
+	fetches := 0
+	fetch := func(b byte) func() ([]byte, error) {
+		return func() ([]byte, error) {
+			fetches++
+			return bytes.Repeat([]byte{b}, 10), nil
+		}
+	}
+
+	for i := int64(0); i < 3; i++ {
+		data, err := cache.get(cachedBlockKey{"file", i}, 10, fetch(byte(i)))
+		require.NoError(t, err)
+		assert.Equal(t, bytes.Repeat([]byte{byte(i)}, 10), data)
+	}
+	assert.Equal(t, 3, fetches)
+	assert.Equal(t, uint64(20), cache.size)
+
+	// block 0 was evicted
+	_, err = os.Stat(cache.path(cachedBlockKey{"file", 0}))
+	assert.True(t, os.IsNotExist(err))
+
+	// blocks 1 and 2 are cached
+	for i := int64(1); i < 3; i++ {
+		data, err := cache.get(cachedBlockKey{"file", i}, 10, fetch(0xff))
+		require.NoError(t, err)
+		assert.Equal(t, bytes.Repeat([]byte{byte(i)}, 10), data)
+	}
+	assert.Equal(t, 3, fetches)
+
+	// cached blocks are loaded on startup, and partially written blocks are removed
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file", blockCacheTempPrefix+"123"), []byte("partial"), 0644))
+	cache, err = newBlockCache(dir, 25)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(20), cache.size)
+	assert.Len(t, cache.blocks, 2)
+	_, err = os.Stat(filepath.Join(dir, "file", blockCacheTempPrefix+"123"))
+	assert.True(t, os.IsNotExist(err))
+
+	// a smaller cache evicts blocks on startup
+	cache, err = newBlockCache(dir, 15)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), cache.size)
+	assert.Len(t, cache.blocks, 1)
+
+	// a cached block of the wrong size is fetched again
+	require.NoError(t, os.WriteFile(cache.path(cachedBlockKey{"file", 2}), []byte("short"), 0644))
+	data, err := cache.get(cachedBlockKey{"file", 2}, 10, fetch(2))
+	require.NoError(t, err)
+	assert.Equal(t, bytes.Repeat([]byte{2}, 10), data)
+	assert.Equal(t, 4, fetches)
+	assert.Equal(t, uint64(10), cache.size)
+
+	// blocks of the wrong size are not cached
+	_, err = cache.get(cachedBlockKey{"file", 3}, 11, fetch(3))
+	assert.Error(t, err)
+
+	require.NoError(t, cache.removeFile("file"))
+	assert.Equal(t, uint64(0), cache.size)
+	assert.Empty(t, cache.blocks)
+	_, err = os.Stat(filepath.Join(dir, "file"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestTieredReaderAt(t *testing.T) {
+	ctx := context.Background()
+	bs := blobstore.NewInMemoryBlobstore("")
+	data := generateRandomBytes(42, 3*tieredBlockSize+100)
+	_, err := blobstore.PutBytes(ctx, bs, "file", data)
+	require.NoError(t, err)
+
+	cache, err := newBlockCache(t.TempDir(), 2*tieredBlockSize)
+	require.NoError(t, err)
+	tp := newTieredTablePersister("", bs, cache, NewUnlimitedMemQuotaProvider())
+	rdr := tp.newReaderAt("file", uint64(len(data)))
+
+	// reads spanning blocks
+	buf := make([]byte, tieredBlockSize+200)
+	n, err := rdr.ReadAt(buf, tieredBlockSize-100)
+	require.NoError(t, err)
+	assert.Equal(t, len(buf), n)
+	assert.Equal(t, data[tieredBlockSize-100:2*tieredBlockSize+100], buf)
+
+	// reads past the end of the file
+	n, err = rdr.ReadAt(buf, int64(len(data))-10)
+	assert.Error(t, err)
+	assert.Equal(t, 10, n)
+	assert.Equal(t, data[len(data)-10:], buf[:n])
+
+	assert.LessOrEqual(t, cache.size, uint64(2*tieredBlockSize))
+}
+
+func TestTieredArchive(t *testing.T) {
+	ctx := context.Background()
+	writer := NewFixedBufferByteSink(make([]byte, 4096))
+	aw := newArchiveWriterWithSink(writer)
+	var hashes []hash.Hash
+	for i := 0; i < 3; i++ {
+		id, err := aw.writeByteSpan(generateRandomBytes(int64(i), 100*(i+1)))
+		require.NoError(t, err)
+		h := hashWithPrefix(t, uint64(i+1))
+		require.NoError(t, aw.stageChunk(h, 0, id))
+		hashes = append(hashes, h)
+	}
+	require.NoError(t, aw.finalizeByteSpans())
+	require.NoError(t, aw.writeIndex())
+	require.NoError(t, aw.writeMetadata([]byte("{}")))
+	require.NoError(t, aw.writeFooter())
+	data := writer.buff[:writer.pos]
+
+	bs := blobstore.NewInMemoryBlobstore("")
+	name := hash.Of(data)
+	_, err := blobstore.PutBytes(ctx, bs, name.String()+archiveFileSuffix, data)
+	require.NoError(t, err)
+
+	cache, err := newBlockCache(t.TempDir(), tieredBlockSize)
+	require.NoError(t, err)
+	tp := newTieredTablePersister("", bs, cache, NewUnlimitedMemQuotaProvider())
+
+	sz, err := tp.archiveSize(ctx, name.String()+archiveFileSuffix)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(len(data)), sz)
+
+	ok, err := tp.Exists(ctx, name, 3, nil)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	cs, err := tp.Open(ctx, name, 3, &Stats{})
+	require.NoError(t, err)
+	defer cs.close()
+	cnt, err := cs.count()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(3), cnt)
+	assert.Equal(t, uint64(len(data)), cs.currentSize())
+	for _, h := range hashes {
+		ok, err := cs.has(h)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	}
+
+	cloned, err := cs.clone()
+	require.NoError(t, err)
+	assert.Equal(t, cs.hash(), cloned.hash())
+}
+
+func TestOffloadAndRecallTableFiles(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "oldgen")
+	require.NoError(t, os.Mkdir(dir, os.ModePerm))
+	bsDir := t.TempDir()
+	nbf := constants.FormatDefaultString
+	q := NewUnlimitedMemQuotaProvider()
+
+	st, err := NewLocalStore(ctx, nbf, dir, testMemTableSize, q)
+	require.NoError(t, err)
+	c := chunks.NewChunk([]byte("history"))
+	require.NoError(t, st.Put(ctx, c, noopGetAddrs))
+	root, err := st.Root(ctx)
+	require.NoError(t, err)
+	ok, err := st.Commit(ctx, c.Hash(), root)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.NoError(t, st.Close())
+
+	files, err := manifestStorageFiles(ctx, dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	bs := blobstore.NewLocalBlobstore(bsDir)
+	cfg := TieredStorageConfig{BlobstoreURL: "localbs://" + bsDir}
+
+	// the database's lock is held by an open store
+	lck := fslock.New(filepath.Join(filepath.Dir(dir), lockFileName))
+	require.NoError(t, lck.TryLock())
+	assert.ErrorIs(t, OffloadTableFiles(ctx, dir, bs, cfg), ErrDatabaseLocked)
+	require.NoError(t, lck.Unlock())
+	_, err = os.Stat(filepath.Join(dir, files[0]))
+	require.NoError(t, err)
+
+	require.NoError(t, OffloadTableFiles(ctx, dir, bs, cfg))
+
+	_, err = os.Stat(filepath.Join(dir, files[0]))
+	assert.True(t, os.IsNotExist(err))
+	ok, err = bs.Exists(ctx, files[0])
+	require.NoError(t, err)
+	assert.True(t, ok)
+	loaded, err := LoadTieredStorageConfig(dir)
+	require.NoError(t, err)
+	assert.Equal(t, &cfg, loaded)
+
+	st, err = NewTieredStore(ctx, nbf, dir, bs, loaded.CacheDirFor(dir), loaded.MaxCacheSize(), testMemTableSize, q)
+	require.NoError(t, err)
+	got, err := st.Get(ctx, c.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, c.Data(), got.Data())
+	require.NoError(t, st.Close())
+
+	require.NoError(t, RecallTableFiles(ctx, dir, bs))
+	_, err = os.Stat(filepath.Join(dir, files[0]))
+	assert.NoError(t, err)
+	loaded, err = LoadTieredStorageConfig(dir)
+	require.NoError(t, err)
+	assert.Nil(t, loaded)
+	_, err = os.Stat(cfg.CacheDirFor(dir))
+	assert.True(t, os.IsNotExist(err))
+
+	st, err = NewLocalStore(ctx, nbf, dir, testMemTableSize, q)
+	require.NoError(t, err)
+	got, err = st.Get(ctx, c.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, c.Data(), got.Data())
+	require.NoError(t, st.Close())
+}
+
+func TestTieredStoreRejectsEncryption(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	require.NoError(t, EnableEncryption(dir, makeTestEncryptionConfig(t)))
+	_, err := NewTieredStore(ctx, constants.FormatDefaultString, filepath.Join(dir, "oldgen"), blobstore.NewLocalBlobstore(t.TempDir()), t.TempDir(), DefaultTieredCacheSize, testMemTableSize, NewUnlimitedMemQuotaProvider())
+	assert.Error(t, err)
+}
+
+func TestTieredConjoinAndPrune(t *testing.T) {
+	ctx := context.Background()
+	bs := blobstore.NewInMemoryBlobstore("")
+	cache, err := newBlockCache(t.TempDir(), 4*tieredBlockSize)
+	require.NoError(t, err)
+	tp := newTieredTablePersister("", bs, cache, NewUnlimitedMemQuotaProvider())
+
+	first, err := persistTableData(tp, []byte("one"), []byte("two"))
+	require.NoError(t, err)
+	second, err := persistTableData(tp, []byte("three"))
+	require.NoError(t, err)
+
+	conjoined, cleanup, err := tp.ConjoinAll(ctx, chunkSources{first, second}, &Stats{})
+	require.NoError(t, err)
+	defer conjoined.close()
+	cnt, err := conjoined.count()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(3), cnt)
+	for _, c := range [][]byte{[]byte("one"), []byte("two"), []byte("three")} {
+		data, err := conjoined.get(ctx, computeAddr(c), &Stats{})
+		require.NoError(t, err)
+		assert.Equal(t, c, data)
+	}
+
+	cleanup()
+	for _, cs := range []chunkSource{first, second} {
+		ok, err := bs.Exists(ctx, cs.hash().String())
+		require.NoError(t, err)
+		assert.False(t, ok)
+	}
+
+	orphan, err := persistTableData(tp, []byte("orphan"))
+	require.NoError(t, err)
+	keeper := func() []hash.Hash { return []hash.Hash{conjoined.hash()} }
+	require.NoError(t, tp.PruneTableFiles(ctx, keeper, time.Now().Add(time.Minute)))
+
+	ok, err := bs.Exists(ctx, orphan.hash().String())
+	require.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = bs.Exists(ctx, conjoined.hash().String())
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
@@ -0,0 +1,124 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql <<SQL
+CREATE TABLE test (
+  pk BIGINT NOT NULL,
+  name VARCHAR(20) NOT NULL,
+  amount DECIMAL(10,2),
+  d DATE,
+  ts DATETIME(6),
+  t TIME,
+  b VARBINARY(10),
+  PRIMARY KEY (pk)
+);
+INSERT INTO test VALUES
+  (1, 'one', 1.5, '2020-01-02', '2020-01-02 03:04:05.123456', '-01:02:03', 0x0102),
+  (2, 'two', NULL, NULL, NULL, NULL, NULL);
+SQL
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "avro-arrow: export and import a table" {
+    for format in avro arrow; do
+        run dolt table export test "test.$format"
+        [ "$status" -eq 0 ]
+        [[ "$output" =~ "Successfully exported data." ]] || false
+
+        # the schema is read from the file, no schema file is needed
+        run dolt table import -c --pk pk "imported_$format" "test.$format"
+        [ "$status" -eq 0 ]
+        [[ "$output" =~ "Import completed successfully." ]] || false
+
+        run dolt schema show "imported_$format"
+        [ "$status" -eq 0 ]
+        [[ "$output" =~ '`pk` bigint NOT NULL' ]] || false
+        [[ "$output" =~ '`name` varchar(1023) NOT NULL' ]] || false
+        [[ "$output" =~ '`amount` decimal(10,2)' ]] || false
+        [[ "$output" =~ '`d` date' ]] || false
+        [[ "$output" =~ '`ts` datetime(6)' ]] || false
+
+        run dolt sql -r csv -q "select pk, name, amount, d, ts, t, hex(b) from imported_$format order by pk"
+        [ "$status" -eq 0 ]
+        [[ "$output" =~ "1,one,1.50,2020-01-02,2020-01-02 03:04:05.123456,-01:02:03,0102" ]] || false
+        [[ "$output" =~ "2,two,,,,," ]] || false
+    done
+}
+
+@test "avro-arrow: update a table with a file" {
+    for format in avro arrow; do
+        dolt table export -f test "test.$format"
+        dolt sql -q "create table updated_$format like test"
+
+        run dolt table import -u "updated_$format" "test.$format"
+        [ "$status" -eq 0 ]
+        [[ "$output" =~ "Rows Processed: 2, Additions: 2" ]] || false
+    done
+}
+
+@test "avro-arrow: file type flag" {
+    dolt table export --file-type avro test test.data
+    run dolt table import -c --pk pk --file-type avro imported test.data
+    [ "$status" -eq 0 ]
+
+    run dolt sql -r csv -q "select count(*) from imported"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "2" ]] || false
+}
+
+@test "avro-arrow: sql result format" {
+    for format in avro arrow; do
+        dolt sql -r "$format" -q "select pk, name, amount * 2 as doubled from test order by pk" > "out.$format"
+
+        run dolt table import -c --pk pk "out_$format" "out.$format"
+        [ "$status" -eq 0 ]
+
+        run dolt sql -r csv -q "select * from out_$format order by pk"
+        [ "$status" -eq 0 ]
+        [[ "$output" =~ "pk,name,doubled" ]] || false
+        [[ "$output" =~ "1,one,3.00" ]] || false
+        [[ "$output" =~ "2,two," ]] || false
+    done
+}
+
+@test "avro-arrow: diff result format" {
+    dolt add .
+    dolt commit -m "added test"
+    dolt sql -q "update test set name = 'uno' where pk = 1; delete from test where pk = 2; insert into test (pk, name) values (3, 'three')"
+
+    for format in avro arrow; do
+        dolt diff -r "$format" test > "diff.$format"
+
+        run dolt table import -c "diff_$format" "diff.$format"
+        [ "$status" -eq 0 ]
+
+        run dolt sql -r csv -q "select pk, name, diff_type from diff_$format order by pk, diff_type"
+        [ "$status" -eq 0 ]
+        [[ "$output" =~ "1,one,modified_old" ]] || false
+        [[ "$output" =~ "1,uno,modified_new" ]] || false
+        [[ "$output" =~ "2,two,removed" ]] || false
+        [[ "$output" =~ "3,three,added" ]] || false
+        [ "${#lines[@]}" -eq 5 ]
+    done
+}
+
+@test "avro-arrow: diff of several tables is an error" {
+    dolt add .
+    dolt commit -m "added test"
+    dolt sql -q "insert into test (pk, name) values (3, 'three'); create table other (pk int primary key)"
+
+    run dolt diff -r avro
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "avro diff output supports a single table" ]] || false
+
+    run dolt diff -r arrow --stat test
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "not supported for arrow output" ]] || false
+}
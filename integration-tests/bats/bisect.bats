@@ -0,0 +1,103 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql -q "create table t (pk int primary key);"
+    dolt commit -Am "create t"
+    for i in 1 2 3 4 5 6 7 8; do
+        dolt sql -q "insert into t values ($i);"
+        if [ "$i" -eq 6 ]; then
+            dolt sql -q "delete from t where pk = 2;"
+        fi
+        dolt commit -am "commit $i"
+    done
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "bisect: run finds the first bad commit" {
+    run dolt bisect start HEAD HEAD~8
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Bisecting:" ]] || false
+
+    run dolt bisect run "select count(*) from t where pk = 2"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "is the first bad commit" ]] || false
+    [[ "$output" =~ "commit 6" ]] || false
+
+    dolt bisect reset
+}
+
+@test "bisect: good and bad mark the commit being tested" {
+    dolt bisect start
+    run dolt bisect bad
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "waiting for good commit(s), bad commit known" ]] || false
+
+    run dolt bisect good HEAD~8
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Bisecting:" ]] || false
+
+    for i in 1 2 3 4; do
+        [[ "$output" =~ \[([0-9a-v]{32})\] ]] || false
+        run dolt sql -q "select count(*) from t as of '${BASH_REMATCH[1]}' where pk = 2" -r csv
+        if [ "${lines[1]}" -eq 1 ]; then
+            run dolt bisect good
+        else
+            run dolt bisect bad
+        fi
+        [ "$status" -eq 0 ]
+        if [[ "$output" =~ "is the first bad commit" ]]; then
+            break
+        fi
+    done
+    [[ "$output" =~ "is the first bad commit" ]] || false
+    [[ "$output" =~ "commit 6" ]] || false
+}
+
+@test "bisect: skip reports the commits left to test" {
+    dolt bisect start HEAD HEAD~8
+    run dolt bisect skip HEAD~1 HEAD~2 HEAD~3 HEAD~4 HEAD~5 HEAD~6 HEAD~7
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "only 'skip'ped commits left to test" ]] || false
+}
+
+@test "bisect: errors" {
+    run dolt bisect good
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "no bisect in progress" ]] || false
+
+    dolt bisect start
+    run dolt bisect start
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "already in progress" ]] || false
+
+    dolt bisect bad HEAD
+    dolt bisect good HEAD~8
+    run dolt bisect run "delete from t"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "must be a SELECT statement" ]] || false
+
+    run dolt sql -q "select * from t" -r csv
+    [ "${#lines[@]}" -eq 8 ]
+
+    run dolt bisect reset
+    [ "$status" -eq 0 ]
+    run dolt bisect good
+    [ "$status" -ne 0 ]
+}
+
+@test "bisect: dolt_bisect procedure" {
+    run dolt sql -q "call dolt_bisect('start', 'HEAD', 'HEAD~8'); call dolt_bisect('run', 'select count(*) from t where pk = 2');"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "is the first bad commit" ]] || false
+
+    run dolt sql -q "call dolt_bisect('reset');"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Bisect reset." ]] || false
+}
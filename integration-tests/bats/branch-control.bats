@@ -255,6 +255,98 @@ branch', 'test', '%', 'admin')"
   [[ ! $output =~ "does not have the correct permissions" ]] || false
 }
 
+@test "branch-control: deny hides branches from reads" {
+  dolt sql -q "create table t (pk int primary key)"
+  dolt commit -Am "create table t"
+  dolt checkout -b customer-a
+  dolt sql -q "insert into t values (1)"
+  dolt commit -am "customer a data"
+  dolt checkout main
+
+  setup_test_user
+  dolt sql -q "create user reader identified by ''"
+  dolt sql -q "grant all on *.* to reader"
+  dolt sql -q "insert into dolt_branch_control values ('%', '%', 'test', '%', 'write')"
+  dolt sql -q "insert into dolt_branch_control values ('%', '%', 'reader', '%', 'write')"
+  dolt sql -q "insert into dolt_branch_control values ('dolt-repo-$$', 'customer-a', '%', '%', 'deny')"
+  dolt sql -q "insert into dolt_branch_control values ('dolt-repo-$$', 'customer-a', 'reader', '%', 'read')"
+
+  start_sql_server
+
+  run dolt -u test -p '' sql -r csv -q "select name from dolt_branches"
+  [ $status -eq 0 ]
+  [[ ! $output =~ "customer-a" ]] || false
+
+  run dolt -u test -p '' sql -q "select * from \`dolt-repo-$$/customer-a\`.t"
+  [ $status -ne 0 ]
+  [[ $output =~ "database not found" ]] || false
+
+  run dolt -u test -p '' sql -q "call dolt_checkout('customer-a')"
+  [ $status -ne 0 ]
+
+  run dolt -u test -p '' sql -q "select * from t as of 'customer-a'"
+  [ $status -ne 0 ]
+  [[ $output =~ "branch not found" ]] || false
+
+  run dolt -u test -p '' sql -q "select * from dolt_diff('main', 'customer-a', 't')"
+  [ $status -ne 0 ]
+  [[ $output =~ "branch not found" ]] || false
+
+  run dolt -u test -p '' sql -r csv -q "select count(*) from dolt_commits where message = 'customer a data'"
+  [ $status -eq 0 ]
+  [ "${lines[1]}" = "0" ]
+
+  # A longer match that grants read access takes precedence over the deny entry
+  run dolt -u reader -p '' sql -r csv -q "select * from t as of 'customer-a'"
+  [ $status -eq 0 ]
+  [ "${lines[1]}" = "1" ]
+
+  run dolt -u reader -p '' sql -q "call dolt_checkout('customer-a'); insert into t values (2)"
+  [ $status -ne 0 ]
+  [[ $output =~ "does not have the correct permissions" ]] || false
+}
+
+@test "branch-control: protected branches require approved merges" {
+  dolt sql -q "create table t (pk int primary key)"
+  dolt commit -Am "create table t"
+  dolt checkout -b feature
+  dolt sql -q "insert into t values (1)"
+  dolt commit -am "feature data"
+  dolt checkout main
+
+  dolt sql -q "insert into dolt_branch_protection values ('%', 'main', 1, null)"
+
+  run dolt sql -q "insert into t values (2)"
+  [ $status -ne 0 ]
+  [[ $output =~ "is protected" ]] || false
+
+  run dolt commit --allow-empty -m "direct commit"
+  [ $status -ne 0 ]
+  [[ $output =~ "is protected" ]] || false
+
+  run dolt merge feature
+  [ $status -ne 0 ]
+  [[ $output =~ "has 0 of the 1 required approvals" ]] || false
+
+  setup_test_user
+  dolt sql -q "insert into dolt_branch_control values ('%', '%', 'test', '%', 'write')"
+
+  start_sql_server
+
+  dolt -u test -p '' sql -q "insert into dolt_approvals select 'dolt-repo-$$', 'feature', 'test', hashof('feature')"
+
+  run dolt -u test -p '' sql -r csv -q "select approver from dolt_approvals"
+  [ $status -eq 0 ]
+  [ "${lines[1]}" = "test" ]
+
+  run dolt sql -q "call dolt_merge('feature')"
+  [ $status -eq 0 ]
+
+  run dolt sql -r csv -q "select * from t"
+  [ $status -eq 0 ]
+  [ "${lines[1]}" = "1" ]
+}
+
 @test "branch-control: repeat deletion does not cause a nil panic" {
   dolt sql <<SQL
 DELETE FROM dolt_branch_control;
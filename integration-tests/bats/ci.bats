@@ -373,3 +373,109 @@ EOF
     [ "$status" -eq 0 ]
     [[ "$output" =~ "workflow_2" ]] || false
 }
+
+@test "ci: run passes when saved query results match expectations" {
+    skip_remote_engine
+    dolt sql -q "create table t1 (pk int primary key, c1 int);"
+    dolt sql -q "insert into t1 values (1, 1), (2, 2);"
+    dolt sql -q "select * from t1;" -s "select t1"
+    dolt add -A
+    dolt commit -m "add t1 and saved query"
+    cat > workflow.yaml <<EOF
+name: my_workflow
+on:
+  push:
+    branches:
+      - master
+jobs:
+  - name: validate t1
+    steps:
+      - name: assert t1 rows
+        saved_query_name: select t1
+        expected_rows: "== 2"
+        expected_columns: "== 2"
+EOF
+    dolt ci init
+    dolt ci import ./workflow.yaml
+    run dolt ci run "my_workflow"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "PASS" ]] || false
+    [[ "$output" =~ "assert t1 rows" ]] || false
+}
+
+@test "ci: run fails when saved query results do not match expectations" {
+    skip_remote_engine
+    dolt sql -q "create table t1 (pk int primary key, c1 int);"
+    dolt sql -q "insert into t1 values (1, 1), (2, 2);"
+    dolt sql -q "select * from t1;" -s "select t1"
+    dolt add -A
+    dolt commit -m "add t1 and saved query"
+    cat > workflow.yaml <<EOF
+name: my_workflow
+on:
+  push:
+    branches:
+      - master
+jobs:
+  - name: validate t1
+    steps:
+      - name: assert t1 rows
+        saved_query_name: select t1
+        expected_rows: "> 5"
+      - name: assert missing query
+        saved_query_name: does not exist
+EOF
+    dolt ci init
+    dolt ci import ./workflow.yaml
+    run dolt ci run "my_workflow"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "FAIL" ]] || false
+    [[ "$output" =~ "expected row count > 5, got 2" ]] || false
+    [[ "$output" =~ "saved query not found: does not exist" ]] || false
+
+    run dolt sql -r csv -q "call dolt_ci_run('my_workflow');"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ 'validate t1,assert t1 rows,select t1,failed,"expected row count > 5, got 2"' ]] || false
+}
+
+@test "ci: run evaluates saved queries against the given revision" {
+    skip_remote_engine
+    dolt sql -q "create table t1 (pk int primary key, c1 int);"
+    dolt sql -q "insert into t1 values (1, 1);"
+    dolt sql -q "select * from t1;" -s "select t1"
+    dolt add -A
+    dolt commit -m "add t1 and saved query"
+    dolt branch other
+    dolt sql -q "insert into t1 values (2, 2);"
+    dolt commit -am "add a row"
+    cat > workflow.yaml <<EOF
+name: my_workflow
+on:
+  push:
+    branches:
+      - master
+jobs:
+  - name: validate t1
+    steps:
+      - name: assert t1 rows
+        saved_query_name: select t1
+        expected_rows: "== 2"
+EOF
+    dolt ci init
+    dolt ci import ./workflow.yaml
+    run dolt ci run "my_workflow"
+    [ "$status" -eq 0 ]
+    run dolt ci run "my_workflow" other
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "expected row count == 2, got 1" ]] || false
+    run dolt ci run "my_workflow" does_not_exist
+    [ "$status" -eq 1 ]
+}
+
+@test "ci: run errors on unknown workflow" {
+    skip_remote_engine
+    dolt ci init
+    run dolt ci run "does not exist"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "workflow not found" ]] || false
+}
@@ -0,0 +1,146 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql <<SQL
+CREATE TABLE test (
+    pk int primary key
+);
+INSERT INTO test VALUES (0),(1),(2);
+SQL
+    dolt add .
+    dolt commit -m "created table test"
+    dolt sql -q "INSERT INTO test VALUES (3);"
+    dolt commit -am "made changes"
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "commit_notes: add and show a note" {
+    head=$(dolt sql -q "select hashof('HEAD')" -r csv | tail -n 1)
+
+    run dolt notes add -m "ticket 123"
+    [ $status -eq 0 ]
+    run dolt notes show
+    [ $status -eq 0 ]
+    [ "$output" = "ticket 123" ]
+    run dolt notes show HEAD~1
+    [ $status -ne 0 ]
+    [[ "$output" =~ "no note found for commit HEAD~1" ]] || false
+
+    # adding a note does not change the commit
+    run dolt sql -q "select hashof('HEAD')" -r csv
+    [[ "$output" =~ "$head" ]] || false
+}
+
+@test "commit_notes: replace a note" {
+    dolt notes add -m "ticket 123"
+    run dolt notes add -m "ticket 456"
+    [ $status -ne 0 ]
+    [[ "$output" =~ "the commit already has a note" ]] || false
+
+    run dolt notes add -f -m "ticket 456"
+    [ $status -eq 0 ]
+    run dolt notes show
+    [ "$output" = "ticket 456" ]
+}
+
+@test "commit_notes: list and remove notes" {
+    dolt notes add -m "ticket 123"
+    dolt notes add -m "signed off" --author "John Doe <john@doe.com>" HEAD~1
+
+    run dolt notes list
+    [ $status -eq 0 ]
+    [[ "$output" =~ "ticket 123" ]] || false
+    [[ "$output" =~ "Author: John Doe <john@doe.com>" ]] || false
+    [[ "$output" =~ "signed off" ]] || false
+
+    run dolt notes remove HEAD~1
+    [ $status -eq 0 ]
+    run dolt notes
+    [ $status -eq 0 ]
+    [[ "$output" =~ "ticket 123" ]] || false
+    [[ ! "$output" =~ "signed off" ]] || false
+
+    run dolt notes remove HEAD~1
+    [ $status -ne 0 ]
+    [[ "$output" =~ "note not found" ]] || false
+}
+
+@test "commit_notes: log shows notes" {
+    dolt notes add -m "ticket 123" HEAD~1
+
+    run dolt log
+    [ $status -eq 0 ]
+    [[ ! "$output" =~ "ticket 123" ]] || false
+
+    run dolt log --notes
+    [ $status -eq 0 ]
+    [[ "$output" =~ "Notes:" ]] || false
+    [[ "$output" =~ "ticket 123" ]] || false
+
+    run dolt sql -q "select message, notes from dolt_log('--notes') where notes != ''" -r csv
+    [ $status -eq 0 ]
+    [[ "$output" =~ "created table test,ticket 123" ]] || false
+}
+
+@test "commit_notes: push and fetch notes" {
+    mkdir remote
+    dolt remote add origin file://./remote
+    dolt push origin main
+    dolt notes add -m "ticket 123"
+
+
+    run dolt push origin 'refs/notes/*'
+    [ $status -eq 0 ]
+    [[ "$output" =~ "[notes]" ]] || false
+    [[ "$output" =~ "refs/notes/commits" ]] || false
+
+    dolt clone file://./remote repo_clone
+    cd repo_clone
+    run dolt notes
+    [ $status -eq 0 ]
+    [ "$output" = "" ]
+
+    run dolt fetch origin 'refs/notes/*'
+    [ $status -eq 0 ]
+    run dolt notes show
+    [ $status -eq 0 ]
+    [ "$output" = "ticket 123" ]
+
+    # fetched notes replace local notes of the same commit
+    dolt notes add -f -m "local note"
+    cd ..
+    dolt notes add -f -m "ticket 456"
+    dolt push origin refs/notes/commits
+    cd repo_clone
+    dolt fetch origin refs/notes/commits
+    run dolt notes show
+    [ "$output" = "ticket 456" ]
+}
+
+@test "commit_notes: fetched notes are merged with local notes" {
+    mkdir remote
+    dolt remote add origin file://./remote
+    dolt commit --allow-empty -m "second commit"
+    dolt push origin main
+    dolt notes add -m "remote note" HEAD~1
+    dolt push origin 'refs/notes/*'
+
+    dolt clone file://./remote repo_clone
+    cd repo_clone
+    dolt notes add -m "local note"
+    dolt fetch origin 'refs/notes/*'
+
+    run dolt notes show HEAD~1
+    [ $status -eq 0 ]
+    [ "$output" = "remote note" ]
+    run dolt notes show
+    [ $status -eq 0 ]
+    [ "$output" = "local note" ]
+}
@@ -0,0 +1,167 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+    dolt sql -q "create table t (pk int primary key, v varchar(100))"
+    dolt sql -q "insert into t values (1, 'ENCRYPTION_BATS_SECRET')"
+    dolt commit -Am "create t"
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "encryption: status of a database without encryption" {
+    run dolt encryption status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Encryption at rest is not enabled" ]] || false
+    [[ "$output" =~ "plaintext" ]] || false
+}
+
+@test "encryption: enable and gc encrypts existing storage files" {
+    run dolt encryption enable --key-file "$PWD/keys.json"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Created key file" ]] || false
+
+    dolt gc --full
+
+    run dolt encryption status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "enabled with the 'file' key provider" ]] || false
+    [[ ! "$output" =~ "plaintext" ]] || false
+
+    run grep -rl "ENCRYPTION_BATS_SECRET" .dolt/noms
+    [ "$status" -ne 0 ]
+
+    dolt sql -q "insert into t values (2, 'ENCRYPTION_BATS_SECRET_2')"
+    dolt commit -am "insert"
+    run grep -rl "ENCRYPTION_BATS_SECRET" .dolt/noms
+    [ "$status" -ne 0 ]
+
+    run dolt sql -q "select v from t order by pk" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "ENCRYPTION_BATS_SECRET_2" ]] || false
+
+}
+
+@test "encryption: new-key rotates the master key" {
+    dolt encryption enable --key-file "$PWD/keys.json"
+    dolt gc --full
+    old_key=$(dolt encryption status | tail -n 1 | awk '{print $2}')
+
+    run dolt encryption new-key
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Added key" ]] || false
+    [[ ! "$output" =~ "$old_key" ]] || false
+
+    dolt gc --full
+    run dolt encryption status
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "$old_key" ]] || false
+
+    run dolt sql -q "select v from t" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "ENCRYPTION_BATS_SECRET" ]] || false
+
+}
+
+@test "encryption: enable requires a key provider" {
+    run dolt encryption enable
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "one of --key-file or --key-command is required" ]] || false
+
+    run dolt encryption new-key
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "encryption at rest is not enabled" ]] || false
+}
+
+@test "encryption: backup to an encrypted file backup" {
+    dolt backup add --encryption-key-file keys.json bac1 file://./bac1
+    run dolt backup sync bac1
+    [ "$status" -eq 0 ]
+
+    [ -f keys.json ]
+    [ -f bac1/encryption.json ]
+    [ -f bac1/oldgen/encryption.json ]
+    run grep -rl "ENCRYPTION_BATS_SECRET" bac1
+    [ "$status" -ne 0 ]
+
+    mkdir restore && cd restore
+    run dolt backup restore file://../bac1 restored
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "its key is not configured locally" ]] || false
+    [ ! -d restored ]
+
+    dolt backup restore --encryption-key-file ../keys.json file://../bac1 restored
+    [ -f restored/.dolt/noms/encryption.json ]
+    run grep -rl "ENCRYPTION_BATS_SECRET" restored/.dolt/noms
+    [ "$status" -ne 0 ]
+    cd restored
+    run dolt sql -q "select v from t" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "ENCRYPTION_BATS_SECRET" ]] || false
+}
+
+@test "encryption: clone of an encrypted file remote is encrypted" {
+    dolt remote add --encryption-key-file keys.json origin file://./rem1
+    dolt push origin main
+
+    mkdir clones && cd clones
+    run dolt clone file://../rem1 cloned
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "its key is not configured locally" ]] || false
+
+    dolt clone --encryption-key-file ../keys.json file://../rem1 cloned
+    [ -f cloned/.dolt/noms/encryption.json ]
+    run grep -rl "ENCRYPTION_BATS_SECRET" cloned/.dolt/noms
+    [ "$status" -ne 0 ]
+    cd cloned
+    run dolt sql -q "select v from t" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "ENCRYPTION_BATS_SECRET" ]] || false
+}
+
+@test "encryption: the encryption config of a file remote is not trusted" {
+    dolt remote add origin file://./rem1
+    dolt push origin main
+    cat > rem1/encryption.json <<EOF
+{"key_provider": "exec", "command": ["touch", "$BATS_TMPDIR/encryption-exec-marker"]}
+EOF
+    rm -f "$BATS_TMPDIR/encryption-exec-marker"
+
+    run dolt fetch origin
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "its key is not configured locally" ]] || false
+
+    mkdir clones && cd clones
+    run dolt clone file://../rem1 cloned
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "its key is not configured locally" ]] || false
+    [ ! -f "$BATS_TMPDIR/encryption-exec-marker" ]
+}
+
+@test "encryption: encrypted databases are not copied to unencrypted destinations" {
+    dolt encryption enable --key-file "$PWD/keys.json"
+
+    dolt backup add bac1 file://./bac1
+    run dolt backup sync bac1
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "not encrypted at rest" ]] || false
+
+    dolt remote add origin file://./rem1
+    run dolt push origin main
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "not encrypted at rest" ]] || false
+
+    dolt backup add --encryption-key-file keys.json bac2 file://./bac2
+    run dolt backup sync bac2
+    [ "$status" -eq 0 ]
+}
+
+@test "encryption: encryption key file is only valid for file backups" {
+    run dolt backup add --encryption-key-file keys.json bac1 http://localhost:50051/test-org/test-repo
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "only valid for file remotes and backups" ]] || false
+}
@@ -0,0 +1,162 @@
+#!/usr/bin/env bats
+#
+# Tests for the dolt_merge_policies table, which declares how merge resolves
+# cells that were changed differently on both sides of a merge.
+
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+    dolt sql -q "create table t (pk int primary key, c int, s varchar(20), j json, n int, ts datetime, e int);"
+    dolt sql -q "insert into t values (1, 10, 'a', '[1, 2]', 5, '2024-01-01', 1), (2, 0, 'x', '[]', 0, '2024-01-01', 0);"
+    dolt commit -Am "ancestor"
+    dolt branch right
+}
+
+teardown() {
+    teardown_common
+}
+
+make_divergent_changes() {
+    dolt sql -q "update t set c = 20, s = 'b', j = '[1, 3]', n = 7, ts = '2024-01-02', e = 2 where pk = 1;"
+    dolt commit -am "left"
+
+    dolt checkout right
+    dolt sql -q "update t set c = 15, s = 'c', j = '[2, 4]', n = 4, ts = '2024-01-03', e = 3 where pk = 1;"
+    dolt commit -am "right"
+    dolt checkout main
+}
+
+@test "merge-policies: policies resolve conflicting cells and record the resolutions" {
+    dolt sql <<SQL
+insert into dolt_merge_policies values
+    ('t', 'c', 'max', null),
+    ('t', 's', 'theirs', null),
+    ('t', 'j', 'json_union', null),
+    ('t', 'n', 'sum', null),
+    ('t', 'e', 'expression', 'ours + theirs * 100'),
+    ('t', '*', 'last_writer_wins', 'ts');
+SQL
+    dolt commit -Am "add merge policies"
+    make_divergent_changes
+
+    run dolt merge right -m "merge right"
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "CONFLICT" ]] || false
+
+    run dolt sql -q "select c, s, j, n, ts, e from t where pk = 1" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ '20,c,"[1,3,2,4]",6,2024-01-03 00:00:00,302' ]] || false
+
+    run dolt sql -q "select table_name, row_key, column_name, strategy, our_value, their_value, merged_value from dolt_merge_resolutions order by column_name" -r csv
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 7 ]
+    [ "${lines[1]}" = "t,1,c,max,20,15,20" ]
+    [ "${lines[2]}" = "t,1,e,expression,2,3,302" ]
+    [ "${lines[4]}" = "t,1,n,sum,7,4,6" ]
+    [ "${lines[5]}" = "t,1,s,theirs,b,c,c" ]
+    [[ "${lines[6]}" =~ "t,1,ts,last_writer_wins" ]] || false
+
+    # the resolutions are committed with the merge
+    run dolt status
+    [[ "$output" =~ "nothing to commit" ]] || false
+    run dolt sql -q "select count(*) from dolt_merge_resolutions as of 'HEAD'" -r csv
+    [[ "$output" =~ "6" ]] || false
+}
+
+@test "merge-policies: a table-wide policy applies to every column without its own policy" {
+    dolt sql -q "insert into dolt_merge_policies values ('t', '*', 'ours', null), ('t', 's', 'theirs', null);"
+    dolt commit -Am "add merge policies"
+    make_divergent_changes
+
+    dolt merge right -m "merge right"
+    run dolt sql -q "select c, s, n from t where pk = 1" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "20,c,7" ]] || false
+}
+
+@test "merge-policies: last_writer_wins takes every cell from the newer row" {
+    dolt sql -q "insert into dolt_merge_policies values ('t', '*', 'last_writer_wins', 'ts');"
+    dolt commit -Am "add merge policies"
+    make_divergent_changes
+
+    dolt merge right -m "merge right"
+    run dolt sql -q "select c, s, n, ts from t where pk = 1" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "15,c,4,2024-01-03 00:00:00" ]] || false
+}
+
+@test "merge-policies: cells without a policy still conflict" {
+    dolt sql -q "insert into dolt_merge_policies values ('t', 'c', 'max', null);"
+    dolt commit -Am "add merge policies"
+    make_divergent_changes
+
+    run dolt merge right -m "merge right"
+    [[ "$output" =~ "CONFLICT" ]] || false
+
+    run dolt sql -q "select count(*) from dolt_conflicts_t" -r csv
+    [[ "$output" =~ "1" ]] || false
+    run dolt sql -q "select count(*) from dolt_merge_resolutions" -r csv
+    [[ "$output" =~ "0" ]] || false
+}
+
+@test "merge-policies: merges without policies don't record resolutions" {
+    make_divergent_changes
+
+    run dolt merge right -m "merge right"
+    [[ "$output" =~ "CONFLICT" ]] || false
+
+    run dolt sql -q "select count(*) from dolt_merge_policies" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "0" ]] || false
+    run dolt sql -q "select count(*) from dolt_merge_resolutions" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "0" ]] || false
+    run dolt ls --all
+    [[ ! "$output" =~ "dolt_merge_resolutions" ]] || false
+}
+
+@test "merge-policies: invalid policies are rejected" {
+    run dolt sql -q "insert into dolt_merge_policies values ('t', 'c', 'biggest', null);"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "unknown strategy 'biggest'" ]] || false
+
+    run dolt sql -q "insert into dolt_merge_policies values ('t', '*', 'last_writer_wins', null);"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "requires the name of a timestamp column" ]] || false
+
+    run dolt sql -q "insert into dolt_merge_policies values ('t', 'c', 'expression', '');"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "requires a SQL expression" ]] || false
+}
+
+@test "merge-policies: sum applies the changes from both sides" {
+    dolt sql -q "insert into dolt_merge_policies values ('t', 'n', 'sum', null);"
+    dolt commit -Am "add merge policies"
+
+    dolt sql -q "update t set n = n + 3 where pk = 2;"
+    dolt commit -am "left"
+    dolt checkout right
+    dolt sql -q "update t set n = n + 10 where pk = 2;"
+    dolt commit -am "right"
+    dolt checkout main
+
+    dolt merge right -m "merge right"
+    run dolt sql -q "select n from t where pk = 2" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "13" ]] || false
+}
+
+@test "merge-policies: policies are versioned with the branch being merged into" {
+    dolt checkout right
+    dolt sql -q "insert into dolt_merge_policies values ('t', 'c', 'max', null);"
+    dolt sql -q "update t set c = 15 where pk = 1;"
+    dolt commit -Am "right"
+    dolt checkout main
+    dolt sql -q "update t set c = 20 where pk = 1;"
+    dolt commit -am "left"
+
+    # the policy added on the right side doesn't apply to this merge
+    run dolt merge right -m "merge right"
+    [[ "$output" =~ "CONFLICT" ]] || false
+}
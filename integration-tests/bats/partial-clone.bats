@@ -0,0 +1,199 @@
+#!/usr/bin/env bats
+#
+# Tests for partial clones, which only fetch the data of the tables
+# selected with --tables or --exclude-tables.
+
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_no_dolt_init
+    REMOTE_URL="file://$BATS_TMPDIR/partial-clone-remote-$$"
+
+    mkdir repo
+    cd repo
+    dolt init
+    dolt sql -q "create table small (pk int primary key, c int);"
+    dolt sql -q "create table big (pk int primary key, c varchar(64));"
+    dolt sql -q "insert into small values (1, 1), (2, 2);"
+    dolt sql -q "insert into big values (1, 'one'), (2, 'two');"
+    dolt commit -Am "create tables"
+    dolt sql -q "insert into big values (3, 'three');"
+    dolt commit -am "insert into big"
+    dolt tag v1
+    dolt remote add origin "$REMOTE_URL"
+    dolt push origin main
+    dolt push origin v1
+    cd ..
+}
+
+teardown() {
+    teardown_common
+    rm -rf "$BATS_TMPDIR/partial-clone-remote-$$"
+}
+
+@test "partial-clone: clone with --exclude-tables marks excluded tables as not fetched" {
+    dolt clone --exclude-tables big "$REMOTE_URL" partial
+    cd partial
+
+    run dolt sql -q "select sum(c) from small" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "3" ]] || false
+
+    run dolt sql -q "select * from big"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "table 'big' has not been fetched from the remote" ]] || false
+
+    run dolt sql -q "select * from big as of 'HEAD~1'"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "has not been fetched" ]] || false
+
+    run dolt sql -q "show tables" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "small" ]] || false
+    [[ ! "$output" =~ "big" ]] || false
+
+    run dolt sql -q "select * from dolt_unfetched_tables" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "big" ]
+
+    run dolt ls
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "big (not fetched)" ]] || false
+    [[ "$output" =~ "small" ]] || false
+
+    run dolt log --oneline
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "insert into big" ]] || false
+
+    run dolt tag
+    [[ "$output" =~ "v1" ]] || false
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "nothing to commit" ]] || false
+
+    dolt sql -q "insert into small values (3, 3);"
+    dolt commit -am "insert into small"
+    dolt gc
+
+    run dolt sql -q "select count(*) from small" -r csv
+    [[ "$output" =~ "3" ]] || false
+}
+
+@test "partial-clone: clone with --tables only fetches the given tables" {
+    dolt clone --tables small "$REMOTE_URL" partial
+    cd partial
+
+    run dolt sql -q "select count(*) from small" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "2" ]] || false
+
+    run dolt sql -q "select * from dolt_unfetched_tables" -r csv
+    [ "${lines[1]}" = "big" ]
+
+    run dolt remote -v
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "origin" ]] || false
+}
+
+@test "partial-clone: --tables and --exclude-tables can't be combined" {
+    run dolt clone --tables small --exclude-tables big "$REMOTE_URL" partial
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "can't be combined" ]] || false
+    [ ! -d partial ]
+}
+
+@test "partial-clone: fetch and pull keep the table filter of the remote" {
+    dolt clone --exclude-tables big "$REMOTE_URL" partial
+
+    cd repo
+    dolt sql -q "insert into small values (10, 10);"
+    dolt sql -q "insert into big values (10, 'ten');"
+    dolt commit -am "insert into both"
+    dolt push origin main
+
+    cd ../partial
+    dolt pull
+    run dolt sql -q "select count(*) from small" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "3" ]] || false
+    run dolt sql -q "select * from big"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "has not been fetched" ]] || false
+
+    cd ../repo
+    dolt sql -q "insert into big values (11, 'eleven');"
+    dolt commit -am "insert into big again"
+    dolt push origin main
+
+    cd ../partial
+    dolt fetch
+    run dolt sql -q "select count(*) from big as of 'origin/main'"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "has not been fetched" ]] || false
+}
+
+@test "partial-clone: fetch --tables fetches excluded tables on demand" {
+    dolt clone --exclude-tables big "$REMOTE_URL" partial
+    cd partial
+
+    dolt fetch origin --tables big
+
+    run dolt sql -q "select count(*) from big" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "3" ]] || false
+    run dolt sql -q "select count(*) from big as of 'HEAD~1'" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "2" ]] || false
+
+    run dolt sql -q "select count(*) from dolt_unfetched_tables" -r csv
+    [[ "$output" =~ "0" ]] || false
+    run dolt ls
+    [[ ! "$output" =~ "not fetched" ]] || false
+
+    # later fetches keep fetching the table
+    cd ../repo
+    dolt sql -q "insert into big values (4, 'four');"
+    dolt commit -am "insert four"
+    dolt push origin main
+
+    cd ../partial
+    dolt pull
+    run dolt sql -q "select count(*) from big" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "4" ]] || false
+}
+
+@test "partial-clone: dolt_fetch --exclude-tables stops fetching a table" {
+    dolt clone "$REMOTE_URL" partial
+
+    cd repo
+    dolt sql -q "insert into big values (4, 'four');"
+    dolt commit -am "insert four"
+    dolt push origin main
+
+    cd ../partial
+    dolt sql -q "call dolt_fetch('origin', '--exclude-tables', 'big')"
+
+    run dolt sql -q "select count(*) from big as of 'origin/main'"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "has not been fetched" ]] || false
+
+    # data fetched before is still there
+    run dolt sql -q "select count(*) from big" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "3" ]] || false
+}
+
+@test "partial-clone: dolt_clone procedure supports --exclude-tables" {
+    mkdir dbs
+    cd dbs
+    dolt sql -q "call dolt_clone('--exclude-tables', 'big', '$REMOTE_URL', 'partial')"
+
+    cd partial
+    run dolt sql -q "select count(*) from small" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "2" ]] || false
+    run dolt sql -q "select * from dolt_unfetched_tables" -r csv
+    [ "${lines[1]}" = "big" ]
+}
@@ -311,6 +311,95 @@ setupCustomEditorScript() {
     [[ "$output" =~ "main commit 2" ]] || false
 }
 
+@test "rebase: edit, exec and break steps stop the rebase" {
+    setupCustomEditorScript "editPlan.txt"
+
+    dolt checkout b1
+    run dolt show head
+    [ "$status" -eq 0 ]
+    COMMIT1=${lines[0]:12:32}
+
+    dolt sql -q "insert into t2 values (1);"
+    dolt commit -am "b1 commit 2"
+    run dolt show head
+    [ "$status" -eq 0 ]
+    COMMIT2=${lines[0]:12:32}
+
+    touch editPlan.txt
+    echo "edit $COMMIT1 b1 commit 1" >> editPlan.txt
+    echo "exec insert into t2 values (100); call dolt_commit('-am', 'added by exec')" >> editPlan.txt
+    echo "break" >> editPlan.txt
+    echo "pick $COMMIT2 b1 commit 2" >> editPlan.txt
+
+    run dolt rebase -i main
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Stopped rebasing at commit $COMMIT1 (b1 commit 1)" ]] || false
+
+    # The rebase working branch is checked out while the rebase is stopped
+    run dolt sql -q "select active_branch();"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ " dolt_rebase_b1 " ]] || false
+
+    # Staged changes are amended into the commit being edited
+    dolt sql -q "insert into t2 values (50);"
+    dolt add t2
+    run dolt rebase --continue
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Stopped rebasing at break" ]] || false
+
+    run dolt sql -q "select message from dolt_log limit 2" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "added by exec" ]
+    [ "${lines[2]}" = "b1 commit 1" ]
+
+    run dolt rebase --continue
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Successfully rebased and updated refs/heads/b1" ]] || false
+
+    run dolt branch
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "* b1" ]] || false
+    ! [[ "$output" =~ "dolt_rebase_b1" ]] || false
+
+    run dolt sql -q "select pk from t2 as of 'HEAD~2' order by pk" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "50" ]
+    [ "${#lines[@]}" -eq 2 ]
+
+    run dolt sql -q "select message from dolt_log limit 4" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "b1 commit 2" ]
+    [ "${lines[2]}" = "added by exec" ]
+    [ "${lines[3]}" = "b1 commit 1" ]
+    [ "${lines[4]}" = "main commit 2" ]
+}
+
+@test "rebase: failed exec step stops the rebase without aborting it" {
+    setupCustomEditorScript "execPlan.txt"
+
+    dolt checkout b1
+    run dolt show head
+    [ "$status" -eq 0 ]
+    COMMIT1=${lines[0]:12:32}
+
+    touch execPlan.txt
+    echo "pick $COMMIT1 b1 commit 1" >> execPlan.txt
+    echo "exec select * from doesnotexist" >> execPlan.txt
+
+    run dolt rebase -i main
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "exec failed while rebasing: select * from doesnotexist" ]] || false
+    [[ "$output" =~ "table not found: doesnotexist" ]] || false
+
+    run dolt sql -q "select active_branch();"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ " dolt_rebase_b1 " ]] || false
+
+    run dolt rebase --continue
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Successfully rebased and updated refs/heads/b1" ]] || false
+}
+
 @test "rebase: rebase skips merge commits" {
     setupCustomEditorScript
 
@@ -336,6 +425,66 @@ setupCustomEditorScript() {
     ! [[ "$output" =~ "b1 merge commit" ]] || false
 }
 
+@test "rebase: --rebase-merges keeps merge commits" {
+    setupCustomEditorScript
+
+    dolt checkout -b feature main
+    dolt sql -q "insert into t1 values (10, 10);"
+    dolt commit -am "feature commit 1"
+
+    dolt checkout b1
+    dolt merge --no-ff -m "merge feature into b1" feature
+    dolt sql -q "insert into t2 values (1);"
+    dolt commit -am "b1 commit 2"
+
+    dolt checkout main
+    dolt sql -q "insert into t1 values (2, 2);"
+    dolt commit -am "main commit 3"
+    dolt checkout b1
+
+    run dolt rebase -i --rebase-merges main
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Successfully rebased and updated refs/heads/b1" ]] || false
+
+    run dolt log --oneline
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "merge feature into b1" ]] || false
+    [[ "$output" =~ "main commit 3" ]] || false
+
+    # The replayed merge commit still has the feature branch as its second parent
+    run dolt sql -q "select count(*) from dolt_commit_ancestors where commit_hash = hashof('HEAD~1') and parent_hash = hashof('feature')" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+
+    run dolt sql -q "select pk from t1 order by pk" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+    [ "${lines[2]}" = "2" ]
+    [ "${lines[3]}" = "10" ]
+}
+
+@test "rebase: --onto transplants commits onto a different base" {
+    setupCustomEditorScript
+
+    dolt checkout -b topic b1
+    dolt sql -q "insert into t1 values (5, 5);"
+    dolt commit -am "topic commit 1"
+
+    run dolt rebase -i --onto main b1
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Successfully rebased and updated refs/heads/topic" ]] || false
+
+    run dolt log --oneline
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "topic commit 1" ]] || false
+    [[ "$output" =~ "main commit 2" ]] || false
+    [[ ! "$output" =~ "b1 commit 1" ]] || false
+
+    run dolt sql -q "show tables" -r csv
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "t2" ]] || false
+}
+
 @test "rebase: rebase with data conflict" {
     setupCustomEditorScript
 
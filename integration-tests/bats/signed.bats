@@ -1,8 +1,15 @@
 #!/usr/bin/env bats
 load $BATS_TEST_DIRNAME/helper/common.bash
 
+KEY_ID="D734F7535E0B95F2A990F665E7940B58CC9ED591"
+
 setup() {
     setup_common
+
+    dolt sql -q "CREATE TABLE t (pk INT primary key);"
+    dolt commit -Am "initial commit"
+    dolt sql -q "INSERT INTO t VALUES (1);"
+    dolt add .
 }
 
 teardown() {
@@ -10,135 +17,212 @@ teardown() {
     teardown_common
 }
 
-# init_gpg() {
-  # # check for existence of public.gpg and private.gpg
-  # run gpg --list-keys
-  # echo gpg --list-keys: \"$output\"
-  # if [[ "$output" =~ "573DA8C6366D04E35CDB1A44E09A0B208F666373" ]]; then
-    # echo "key exists"
-  # else
-    # echo "importing $BATS_TEST_DIRNAME/private.pgp"
-    # run gpg --import "$BATS_TEST_DIRNAME/private.pgp"
-    # echo gpg --import $BATS_TEST_DIRNAME/private.pgp: \"$output\"
-    # [ "$status" -eq 0 ]
-  # fi
-# }
-
-# @test "signed: dolt commit with key specified on command line" {
-  # init_gpg
-  # run dolt sql -q "CREATE TABLE t (pk INT primary key);"
-  # [ "$status" -eq 0 ]
-#
-  # run dolt add .
-  # [ "$status" -eq 0 ]
-#
-  # run dolt commit -m "initial commit"
-  # [ "$status" -eq 0 ]
-#
-  # run dolt sql -q "INSERT INTO t VALUES (1);"
-  # [ "$status" -eq 0 ]
-#
-  # run dolt add .
-  # [ "$status" -eq 0 ]
-#
-  # run dolt commit -S "573DA8C6366D04E35CDB1A44E09A0B208F666373" -m "signed commit"
-  # echo $output
-  # [ "$status" -eq 0 ]
-#
-  # run dolt log --show-signature
-  # echo $output
-  # [ "$status" -eq 0 ]
-  # [[ "$output" =~ 'gpg: Good signature from "Test User <test@dolthub.com>"' ]] || false
-# }
-#
-# @test "signed: dolt commit with key specified in config" {
-  # init_gpg
-  # run dolt config --global --add sqlserver.global.signingkey "573DA8C6366D04E35CDB1A44E09A0B208F666373"
-  # [ "$status" -eq 0 ]
-#
-  # run dolt sql -q "CREATE TABLE t (pk INT primary key);"
-  # [ "$status" -eq 0 ]
-#
-  # run dolt add .
-  # [ "$status" -eq 0 ]
-#
-  # run dolt commit -m "initial commit"
-  # [ "$status" -eq 0 ]
-#
-  # run dolt sql -q "INSERT INTO t VALUES (1);"
-  # [ "$status" -eq 0 ]
-#
-  # run dolt add .
-  # [ "$status" -eq 0 ]
-#
-  # run dolt commit -S -m "signed commit"
-  # [ "$status" -eq 0 ]
-#
-  # run dolt log --show-signature
-  # [ "$status" -eq 0 ]
-  # [[ "$output" =~ 'gpg: Good signature from "Test User <test@dolthub.com>"' ]] || false
-# }
-
-# @test "signed: signing by default using the config" {
-  # init_gpg
-  # run dolt config --global --add sqlserver.global.signingkey "573DA8C6366D04E35CDB1A44E09A0B208F666373"
-  # [ "$status" -eq 0 ]
-#
-  # run dolt config --global --add sqlserver.global.gpgsign true
-  # [ "$status" -eq 0 ]
-#
-  # run dolt sql -q "CREATE TABLE t (pk INT primary key);"
-  # [ "$status" -eq 0 ]
-#
-  # run dolt add .
-  # [ "$status" -eq 0 ]
-#
-  # run dolt commit -m "initial commit"
-  # echo $output
-  # [ "$status" -eq 0 ]
-#
-  # run dolt sql -q "INSERT INTO t VALUES (1);"
-  # [ "$status" -eq 0 ]
-#
-  # run dolt add .
-  # [ "$status" -eq 0 ]
-#
-  # run dolt commit -m "signed commit without being specified on the command line"
-  # [ "$status" -eq 0 ]
-#
-  # run dolt log --show-signature
-  # [ "$status" -eq 0 ]
-  # [[ "$output" =~ 'gpg: Good signature from "Test User <test@dolthub.com>"' ]] || false
-# }
-#
-# @test "signed: using stored procedure" {
-  # init_gpg
-  # run dolt config --global --add sqlserver.global.signingkey "573DA8C6366D04E35CDB1A44E09A0B208F666373"
-  # [ "$status" -eq 0 ]
-#
-  # run dolt config --global --add sqlserver.global.gpgsign true
-  # [ "$status" -eq 0 ]
-#
-  # run dolt sql -q "CREATE TABLE t (pk INT primary key);"
-  # [ "$status" -eq 0 ]
-#
-  # run dolt add .
-  # [ "$status" -eq 0 ]
-#
-  # run dolt commit -m "initial commit"
-  # echo $output
-  # [ "$status" -eq 0 ]
-#
-  # run dolt sql -q "INSERT INTO t VALUES (1);"
-  # [ "$status" -eq 0 ]
-#
-  # run dolt add .
-  # [ "$status" -eq 0 ]
-#
-  # run dolt sql -q "CALL dolt_commit('-m', 'signed commit');"
-  # [ "$status" -eq 0 ]
-#
-  # run dolt log --show-signature
-  # [ "$status" -eq 0 ]
-  # [[ "$output" =~ 'gpg: Good signature from "Test User <test@dolthub.com>"' ]] || false
-# }
\ No newline at end of file
+init_gpg() {
+    if ! command -v gpg > /dev/null; then
+        skip "gpg not installed"
+    fi
+    run gpg --list-keys
+    if [[ ! "$output" =~ "$KEY_ID" ]]; then
+        run gpg --import "$BATS_TEST_DIRNAME/private.pgp"
+        [ "$status" -eq 0 ]
+    fi
+}
+
+# init_ssh_key creates an ed25519 key at $BATS_TMPDIR/id_ed25519 and an allowed signers file trusting it for
+# test@dolthub.com at $BATS_TMPDIR/ssh_allowed_signers. The key's fingerprint is stored in $SSH_KEY_ID.
+init_ssh_key() {
+    if ! command -v ssh-keygen > /dev/null; then
+        skip "ssh-keygen not installed"
+    fi
+    rm -f "$BATS_TMPDIR/id_ed25519" "$BATS_TMPDIR/id_ed25519.pub"
+    ssh-keygen -q -t ed25519 -N "" -C "test@dolthub.com" -f "$BATS_TMPDIR/id_ed25519"
+    echo "test@dolthub.com $(cat "$BATS_TMPDIR/id_ed25519.pub")" > "$BATS_TMPDIR/ssh_allowed_signers"
+    SSH_KEY_ID=$(ssh-keygen -l -f "$BATS_TMPDIR/id_ed25519.pub" | awk '{print $2}')
+}
+
+@test "signed: dolt commit with key specified on command line" {
+    init_gpg
+    run dolt commit -S "$KEY_ID" --author "Test User <test@dolthub.com>" -m "signed commit"
+    [ "$status" -eq 0 ]
+
+    run dolt log --show-signature -n 1
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ 'Good signature from "Test User <test@dolthub.com>"' ]] || false
+
+    # keys in the gpg keyring aren't trusted by verify-commit unless they are allowed signers
+    run dolt verify-commit HEAD
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "Can't check signature: no public key for RSA key E7940B58CC9ED591" ]] || false
+
+    dolt config --global --add sqlserver.global.allowedsigners "$BATS_TEST_DIRNAME/pgp_allowed_signers"
+
+    run dolt verify-commit HEAD
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Good signature from \"test@dolthub.com\" with RSA key $KEY_ID" ]] || false
+}
+
+@test "signed: dolt commit with key specified in config" {
+    init_gpg
+    dolt config --global --add sqlserver.global.signingkey "$KEY_ID"
+
+    run dolt commit -S --author "Test User <test@dolthub.com>" -m "signed commit"
+    [ "$status" -eq 0 ]
+
+    run dolt log --show-signature -n 1
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ 'Good signature from "Test User <test@dolthub.com>"' ]] || false
+}
+
+@test "signed: dolt commit with user.signingkey" {
+    init_gpg
+    dolt config --global --add user.signingkey "E7940B58CC9ED591"
+
+    run dolt commit -S --author "Test User <test@dolthub.com>" -m "signed commit"
+    [ "$status" -eq 0 ]
+
+    run dolt log --show-signature -n 1
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ 'Good signature from "Test User <test@dolthub.com>"' ]] || false
+}
+
+@test "signed: signing by default using the config" {
+    init_gpg
+    dolt config --global --add sqlserver.global.signingkey "$KEY_ID"
+    dolt config --global --add sqlserver.global.gpgsign true
+    dolt config --global --add sqlserver.global.allowedsigners "$BATS_TEST_DIRNAME/pgp_allowed_signers"
+
+    run dolt commit --author "Test User <test@dolthub.com>" -m "signed commit without being specified on the command line"
+    [ "$status" -eq 0 ]
+
+    run dolt verify-commit HEAD
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ 'Good signature from "test@dolthub.com"' ]] || false
+
+    run dolt commit --amend --author "Test User <test@dolthub.com>" -m "amended signed commit"
+    [ "$status" -eq 0 ]
+
+    run dolt verify-commit HEAD
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ 'Good signature from "test@dolthub.com"' ]] || false
+}
+
+@test "signed: using stored procedure" {
+    init_gpg
+    dolt config --global --add sqlserver.global.signingkey "$KEY_ID"
+    dolt config --global --add sqlserver.global.gpgsign true
+    dolt config --global --add sqlserver.global.allowedsigners "$BATS_TEST_DIRNAME/pgp_allowed_signers"
+
+    run dolt sql -q "CALL dolt_commit('-m', 'signed commit', '--author', 'Test User <test@dolthub.com>');"
+    [ "$status" -eq 0 ]
+
+    run dolt sql -r csv -q "CALL dolt_verify_commit('HEAD', 'HEAD~1');"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ ",good," ]] || false
+    [[ "$output" =~ ",unsigned," ]] || false
+
+    run dolt sql -r csv -q "SELECT message, signature_status FROM dolt_log;"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "signed commit,good" ]] || false
+    [[ "$output" =~ "initial commit,unsigned" ]] || false
+
+    run dolt sql -r csv -q "SELECT message, signature_status FROM dolt_commits WHERE message = 'signed commit';"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "signed commit,good" ]] || false
+}
+
+@test "signed: server signing keys" {
+    init_ssh_key
+    dolt config --global --add sqlserver.global.signingkeys "$BATS_TMPDIR/id_ed25519,$BATS_TEST_DIRNAME/private.pgp"
+
+    run dolt commit -S "$SSH_KEY_ID" --author "Test User <test@dolthub.com>" -m "ssh signed commit"
+    [ "$status" -eq 0 ]
+
+    run dolt verify-commit HEAD
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "Good signature with ED25519 key $SSH_KEY_ID" ]] || false
+    [[ "$output" =~ "WARNING: This key is not trusted by the allowed signers." ]] || false
+
+    cat "$BATS_TMPDIR/ssh_allowed_signers" "$BATS_TEST_DIRNAME/pgp_allowed_signers" > "$BATS_TMPDIR/allowed_signers"
+    dolt config --global --add sqlserver.global.allowedsigners "$BATS_TMPDIR/allowed_signers"
+
+    run dolt verify-commit HEAD
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Good signature from \"test@dolthub.com\" with ED25519 key $SSH_KEY_ID" ]] || false
+
+    # OpenPGP keys are selected by fingerprint, key id or email, without gpg
+    dolt sql -q "INSERT INTO t VALUES (2);"
+    run dolt commit -a -S "test@dolthub.com" --author "Test User <test@dolthub.com>" -m "pgp signed commit"
+    [ "$status" -eq 0 ]
+
+    run dolt verify-commit HEAD
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Good signature from \"test@dolthub.com\" with RSA key $KEY_ID" ]] || false
+
+    # the key is only trusted for the email it is listed with
+    dolt sql -q "INSERT INTO t VALUES (3);"
+    dolt commit -a -S "$SSH_KEY_ID" --author "Other User <other@dolthub.com>" -m "signed by someone else"
+    run dolt verify-commit HEAD
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "WARNING: This key is not trusted by the allowed signers." ]] || false
+
+    # only the server's keys can be used
+    dolt sql -q "INSERT INTO t VALUES (4);"
+    run dolt commit -a -S "0123456789ABCDEF" -m "signed with an unknown key"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "is not one of the server's signing keys" ]] || false
+}
+
+@test "signed: key files can't be configured from SQL" {
+    run dolt sql -q "SET @@PERSIST.signingkeys = '$BATS_TEST_DIRNAME/private.pgp';"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "read only" ]] || false
+
+    run dolt sql -q "SET @@PERSIST.allowedsigners = '$BATS_TEST_DIRNAME/pgp_allowed_signers';"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "read only" ]] || false
+}
+
+@test "signed: commit with missing or invalid key fails" {
+    run dolt commit -S "0123456789ABCDEF" -m "signed commit"
+    [ "$status" -ne 0 ]
+
+    # -S takes a key id, not the path of a key file
+    run dolt commit -S "$BATS_TEST_DIRNAME/private.pgp" -m "signed commit"
+    [ "$status" -ne 0 ]
+
+    run dolt log -n 1
+    [[ "$output" =~ "initial commit" ]] || false
+}
+
+@test "signed: signed tags" {
+    init_ssh_key
+    dolt config --global --add sqlserver.global.signingkeys "$BATS_TMPDIR/id_ed25519,$BATS_TEST_DIRNAME/private.pgp"
+    dolt commit -m "second commit" --author "Test User <test@dolthub.com>"
+
+    dolt tag -u "$SSH_KEY_ID" -m "ssh signed" v1 --author "Test User <test@dolthub.com>"
+    dolt tag -u "$KEY_ID" v2 --author "Test User <test@dolthub.com>"
+    dolt tag v3
+
+    run dolt verify-tag v1 v2 v3
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "WARNING: This key is not trusted by the allowed signers." ]] || false
+    [[ "$output" =~ "Can't check signature: no public key for RSA key E7940B58CC9ED591" ]] || false
+    [[ "$output" =~ "No signature" ]] || false
+
+    cat "$BATS_TMPDIR/ssh_allowed_signers" "$BATS_TEST_DIRNAME/pgp_allowed_signers" > "$BATS_TMPDIR/allowed_signers"
+    dolt config --global --add sqlserver.global.allowedsigners "$BATS_TMPDIR/allowed_signers"
+
+    run dolt verify-tag v1 v2
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ 'Good signature from "test@dolthub.com" with ED25519 key' ]] || false
+    [[ "$output" =~ 'Good signature from "test@dolthub.com" with RSA key' ]] || false
+
+    run dolt sql -r csv -q "CALL dolt_verify_tag('v1', 'v3');"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "v1,good," ]] || false
+    [[ "$output" =~ "v3,unsigned," ]] || false
+
+    run dolt verify-tag v3
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "No signature" ]] || false
+}
@@ -57,3 +57,87 @@ teardown() {
     [[ ! "$output" =~ "initial commit" ]] || false
     [[ ! "$output" =~ "Initialize data repository" ]] || false
 }
+
+# Asserts that when DOLT_PERSISTENT_REFLOG has been set, the reflog keeps every entry
+# recorded in the chunk journal, regardless of DOLT_REFLOG_RECORD_LIMIT.
+@test "sql-reflog: set DOLT_PERSISTENT_REFLOG" {
+    export DOLT_PERSISTENT_REFLOG=true
+    export DOLT_REFLOG_RECORD_LIMIT=2
+    setup_common    # need to set env vars before setup_common for remote tests
+
+    dolt sql -q "create table t (i int primary key, j int);"
+    dolt sql -q "insert into t values (1, 1), (2, 2), (3, 3)";
+    dolt commit -Am "initial commit"
+    dolt commit --allow-empty -m "test commit 1"
+    dolt commit --allow-empty -m "test commit 2"
+
+    run dolt sql -q "select * from dolt_reflog();"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "test commit 1" ]] || false
+    [[ "$output" =~ "test commit 2" ]] || false
+    [[ "$output" =~ "initial commit" ]] || false
+    [[ "$output" =~ "Initialize data repository" ]] || false
+}
+
+# Asserts that when DOLT_PERSISTENT_REFLOG has been set, the reflog survives garbage
+# collection and is included in backups, including entries for deleted branches.
+@test "sql-reflog: persistent reflog survives gc and is included in backups" {
+    export DOLT_PERSISTENT_REFLOG=true
+    setup_common
+
+    dolt sql -q "create table t (i int primary key, j int);"
+    dolt commit -Am "initial commit"
+    dolt branch feature
+    dolt branch -D feature
+    dolt gc
+
+    run dolt sql -q "select ref, commit_message from dolt_reflog();"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "refs/heads/feature" ]] || false
+    [[ "$output" =~ "initial commit" ]] || false
+    [[ "$output" =~ "Initialize data repository" ]] || false
+
+    dolt backup add bac1 file://./bac1
+    dolt backup sync bac1
+    dolt backup restore file://./bac1 restored
+    cd restored
+
+    run dolt sql -q "select ref, commit_message from dolt_reflog();"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "refs/heads/feature" ]] || false
+    [[ "$output" =~ "initial commit" ]] || false
+}
+
+# Asserts that dolt_at_time() resolves a branch to where it pointed at a point in
+# time, even after the branch has been reset.
+@test "sql-reflog: dolt_at_time resolves a branch after a reset" {
+    setup_common
+
+    dolt sql -q "create table t (i int primary key, j int);"
+    dolt commit -Am "initial commit"
+    dolt sql -q "insert into t values (1, 1), (2, 2), (3, 3)";
+    dolt commit -Am "insert rows"
+    head=$(get_head_commit)
+
+    # reflog timestamps are persisted with second precision
+    sleep 1
+    ts=$(date -u "+%Y-%m-%d %H:%M:%S")
+    sleep 1
+    dolt reset --hard HEAD~1
+
+    run dolt sql -r csv -q "select count(*) from t;"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "0" ]] || false
+
+    run dolt sql -r csv -q "select dolt_at_time('main', '$ts');"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "$head" ]] || false
+
+    run dolt sql -r csv -q "select count(*) from t as of dolt_at_time('main', '$ts');"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "3" ]] || false
+
+    run dolt sql -q "select dolt_at_time('main', '2000-01-01');"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "no reflog entry found at or before 2000-01-01 00:00:00" ]] || false
+}
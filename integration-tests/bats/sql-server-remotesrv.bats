@@ -684,3 +684,130 @@ GRANT CLONE_ADMIN ON *.* TO clone_admin_user@'localhost';
     [[ "$output" =~ "main" ]] || false
 }
 
+
+@test "sql-server-remotesrv: push to branch with failing required workflow is rejected" {
+    mkdir remote
+    cd remote
+    dolt init
+    dolt sql -q 'create table names (name varchar(10) primary key);'
+    dolt sql -q 'insert into names (name) values ("abe"), ("betsy");'
+    dolt sql -q 'select * from names;' -s 'select names'
+    dolt add -A
+    dolt commit -m 'initial names.'
+    cat > workflow.yaml <<EOF
+name: names_workflow
+on:
+  push:
+    branches:
+      - main
+jobs:
+  - name: validate names
+    steps:
+      - name: assert names rows
+        saved_query_name: select names
+        expected_rows: "< 3"
+EOF
+    dolt ci init
+    dolt ci import ./workflow.yaml
+
+    APIPORT=$( definePORT )
+    cat > ci.yaml <<EOF
+remotesapi:
+  port: $APIPORT
+ci:
+  enabled: true
+  required_workflows:
+  - branch: main
+    workflows:
+    - names_workflow
+EOF
+    start_sql_server_with_config "" ci.yaml
+
+    cd ../
+    export DOLT_REMOTE_PASSWORD=""
+    dolt clone http://localhost:$APIPORT/remote cloned_db -u dolt
+    cd cloned_db
+    dolt sql -q 'insert into names (name) values ("calvin");'
+    dolt commit -am 'add calvin'
+
+    run dolt push origin --user dolt main:main
+    [[ "$status" -ne 0 ]] || false
+    [[ "$output" =~ "push to protected branch main rejected" ]] || false
+    [[ "$output" =~ "names_workflow" ]] || false
+
+    # other branches are not protected
+    run dolt push origin --user dolt main:other
+    [[ "$status" -eq 0 ]] || false
+
+    dolt sql -q 'delete from names where name = "abe";'
+    dolt commit -am 'remove abe'
+    run dolt push origin --user dolt main:main
+    [[ "$status" -eq 0 ]] || false
+
+    cd ../remote
+    run dolt sql -q "select workflow_name, event, branch, status from dolt_ci_runs order by started_at" -r csv
+    [[ "$output" =~ "names_workflow,push,main,failed" ]] || false
+    [[ "$output" =~ "names_workflow,push,main,passed" ]] || false
+}
+
+@test "sql-server-remotesrv: commits and pushes to protected branches must be signed by a trusted key" {
+    if ! command -v ssh-keygen > /dev/null; then
+        skip "ssh-keygen not installed"
+    fi
+    rm -f "$BATS_TMPDIR/signing_key" "$BATS_TMPDIR/signing_key.pub"
+    ssh-keygen -q -t ed25519 -N "" -f "$BATS_TMPDIR/signing_key"
+    echo "test@dolthub.com $(cat "$BATS_TMPDIR/signing_key.pub")" > "$BATS_TMPDIR/allowed_signers"
+
+    mkdir remote
+    cd remote
+    dolt init
+    dolt sql -q 'create table names (name varchar(10) primary key);'
+    dolt commit -Am 'create names, before signing was required'
+
+    APIPORT=$( definePORT )
+    cat > signed.yaml <<EOF
+remotesapi:
+  port: $APIPORT
+signed_commits:
+  branches:
+  - main
+  - release/%
+  allowed_signers: $BATS_TMPDIR/allowed_signers
+EOF
+    start_sql_server_with_config "" signed.yaml
+
+    run dolt sql -q "call dolt_commit('--allow-empty', '-m', 'unsigned')"
+    [[ "$status" -ne 0 ]] || false
+    [[ "$output" =~ "branch main requires commits signed by a trusted key: No signature" ]] || false
+
+    run dolt sql -q "call dolt_commit('--allow-empty', '-m', 'untrusted', '-S', '$BATS_TMPDIR/signing_key', '--author', 'Other <other@dolthub.com>')"
+    [[ "$status" -ne 0 ]] || false
+    [[ "$output" =~ "This key is not trusted by the allowed signers" ]] || false
+
+    run dolt sql -q "call dolt_commit('--allow-empty', '-m', 'signed', '-S', '$BATS_TMPDIR/signing_key', '--author', 'Test User <test@dolthub.com>')"
+    [[ "$status" -eq 0 ]] || false
+
+    cd ../
+    export DOLT_REMOTE_PASSWORD=""
+    dolt clone http://localhost:$APIPORT/remote cloned_db -u dolt
+    cd cloned_db
+    dolt sql -q 'insert into names (name) values ("abe");'
+    dolt commit -am 'add abe' --author 'Test User <test@dolthub.com>'
+
+    run dolt push origin --user dolt main:main
+    [[ "$status" -ne 0 ]] || false
+    [[ "$output" =~ "branch main requires commits signed by a trusted key" ]] || false
+    [[ "$output" =~ "No signature" ]] || false
+
+    run dolt push origin --user dolt main:release/1.0
+    [[ "$status" -ne 0 ]] || false
+    [[ "$output" =~ "branch release/1.0 requires commits signed by a trusted key" ]] || false
+
+    # other branches are not protected
+    run dolt push origin --user dolt main:other
+    [[ "$status" -eq 0 ]] || false
+
+    dolt commit --amend -S "$BATS_TMPDIR/signing_key" -m 'add abe' --author 'Test User <test@dolthub.com>'
+    run dolt push origin --user dolt main:main
+    [[ "$status" -eq 0 ]] || false
+}
@@ -1968,3 +1968,153 @@ behavior:
     [[ "$output" =~ "Detected that a Dolt sql-server is running from this directory." ]] || false
     [[ "$output" =~ "Stop the sql-server before initializing this directory as a Dolt database." ]] || false
 }
+
+@test "sql-server: webhooks are notified of branch updates" {
+    cd repo1
+    dolt sql -q "create table t (pk int primary key, c int)"
+    dolt commit -Am "create t"
+
+    HOOKPORT=$( definePORT )
+    python3 -c '
+import http.server, sys
+class Handler(http.server.BaseHTTPRequestHandler):
+    def do_POST(self):
+        body = self.rfile.read(int(self.headers["Content-Length"]))
+        with open(sys.argv[2], "a") as f:
+            f.write(self.headers.get("X-Dolt-Signature-256", "") + " " + body.decode() + "\n")
+        self.send_response(200)
+        self.end_headers()
+    def log_message(self, *args):
+        pass
+http.server.HTTPServer(("127.0.0.1", int(sys.argv[1])), Handler).serve_forever()
+' $HOOKPORT "$BATS_TMPDIR/webhook-$$.log" &
+    HOOK_PID=$!
+
+    cat > server.yaml <<EOF
+webhooks:
+- url: http://127.0.0.1:$HOOKPORT/hook
+  secret: shh
+  branches:
+  - main
+  - feature%
+EOF
+    start_sql_server_with_config repo1 server.yaml
+
+    dolt sql -q "insert into t values (1, 1), (2, 2); call dolt_commit('-am', 'add rows')"
+    dolt sql -q "call dolt_branch('feature1')"
+    dolt sql -q "call dolt_branch('other')"
+    dolt sql -q "call dolt_branch('-d', 'feature1')"
+    sleep 2
+    kill $HOOK_PID
+
+    run cat "$BATS_TMPDIR/webhook-$$.log"
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 3 ]
+    [[ "${lines[0]}" =~ "sha256=" ]] || false
+    [[ "${lines[0]}" =~ '"database":"repo1","branch":"main"' ]] || false
+    [[ "${lines[0]}" =~ '"message":"add rows"' ]] || false
+    [[ "${lines[0]}" =~ '"tables":[{"table":"t","rows_added":2,"rows_deleted":0,"rows_modified":0}]' ]] || false
+    [[ "${lines[1]}" =~ '"branch":"feature1","old_commit":""' ]] || false
+    [[ "${lines[2]}" =~ '"branch":"feature1"' ]] || false
+    [[ "${lines[2]}" =~ '"new_commit":""' ]] || false
+    [[ ! "$output" =~ '"branch":"other"' ]] || false
+
+    # the signature is an HMAC of the payload with the secret
+    run python3 -c '
+import hashlib, hmac, sys
+sig, body = open(sys.argv[1]).readline().rstrip("\n").split(" ", 1)
+print(sig == "sha256=" + hmac.new(b"shh", body.encode(), hashlib.sha256).hexdigest())
+' "$BATS_TMPDIR/webhook-$$.log"
+    [ "$status" -eq 0 ]
+    [ "$output" = "True" ]
+}
+
+@test "sql-server: change data capture stream of a branch" {
+    cd repo1
+    dolt sql -q "create table t (pk int primary key, c varchar(10))"
+    dolt commit -Am "create t"
+    FROM=$(dolt sql -r csv -q "select dolt_hashof('HEAD')" | tail -n 1)
+
+    CDCPORT=$( definePORT )
+    cat > server.yaml <<EOF
+cdc:
+  host: 127.0.0.1
+  port: $CDCPORT
+  poll_interval_millis: 100
+EOF
+    start_sql_server_with_config repo1 server.yaml
+
+    dolt sql -q "insert into t values (1, 'one'), (2, 'two'); call dolt_commit('-am', 'add rows')"
+    dolt sql -q "update t set c = 'uno' where pk = 1; delete from t where pk = 2; call dolt_commit('-am', 'change rows')"
+
+    cat > "$BATS_TMPDIR/cdc-$$.py" <<'EOF'
+import base64, json, sys, urllib.error, urllib.request
+url = "http://127.0.0.1:%s/changes?%s" % (sys.argv[1], sys.argv[2])
+req = urllib.request.Request(url)
+if len(sys.argv) > 3:
+    req.add_header("Authorization", "Basic " + base64.b64encode(sys.argv[3].encode()).decode())
+try:
+    with urllib.request.urlopen(req) as resp:
+        for line in resp:
+            ev = json.loads(line)
+            print(ev["event_cursor"], ev["message"], ev["table_name"], ev["change_type"], json.dumps(ev["from_row"], sort_keys=True), json.dumps(ev["to_row"], sort_keys=True))
+except urllib.error.HTTPError as e:
+    print(e.code, e.read().decode().strip())
+EOF
+
+    run python3 "$BATS_TMPDIR/cdc-$$.py" $CDCPORT "database=repo1&branch=main&from=$FROM&follow=false"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "401 authentication required" ]] || false
+
+    run python3 "$BATS_TMPDIR/cdc-$$.py" $CDCPORT "database=repo1&branch=main&from=$FROM&follow=false" "dolt:"
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 4 ]
+    [[ "${lines[0]}" =~ ':0 add rows t insert null {"c": "one", "pk": 1}' ]] || false
+    [[ "${lines[1]}" =~ ':1 add rows t insert null {"c": "two", "pk": 2}' ]] || false
+    [[ "${lines[2]}" =~ ':0 change rows t update {"c": "one", "pk": 1} {"c": "uno", "pk": 1}' ]] || false
+    [[ "${lines[3]}" =~ ':1 change rows t delete {"c": "two", "pk": 2} null' ]] || false
+
+    # resume after the first change
+    CURSOR=$(echo "${lines[0]}" | cut -d' ' -f1)
+    run python3 "$BATS_TMPDIR/cdc-$$.py" $CDCPORT "database=repo1&branch=main&from=$CURSOR&follow=false" "dolt:"
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 3 ]
+    [[ "${lines[0]}" =~ ':1 add rows t insert null {"c": "two", "pk": 2}' ]] || false
+
+    run python3 "$BATS_TMPDIR/cdc-$$.py" $CDCPORT "database=repo1&branch=main&from=abc:1&follow=false" "dolt:"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "500 error reading changes" ]] || false
+}
+
+@test "sql-server: change data capture stream is served with the tls config of the listener" {
+    cd repo1
+    dolt sql -q "create table t (pk int primary key); insert into t values (1)"
+    dolt commit -Am "create t"
+    cp "$BATS_TEST_DIRNAME/../../go/libraries/doltcore/servercfg/testdata/chain_cert.pem" "$BATS_TEST_DIRNAME/../../go/libraries/doltcore/servercfg/testdata/chain_key.pem" .
+
+    PORT=$( definePORT )
+    CDCPORT=$( definePORT )
+    cat > server.yaml <<EOF
+user:
+  name: dolt
+listener:
+  host: 127.0.0.1
+  port: $PORT
+  tls_cert: chain_cert.pem
+  tls_key: chain_key.pem
+cdc:
+  host: 127.0.0.1
+  port: $CDCPORT
+  poll_interval_millis: 100
+EOF
+    dolt sql-server --config server.yaml --socket "dolt.$PORT.sock" &
+    SERVER_PID=$!
+    wait_for_connection $PORT 8500
+
+    run curl -sk -u dolt: "https://127.0.0.1:$CDCPORT/changes?database=repo1&branch=main&follow=false"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ '"change_type":"insert"' ]] || false
+
+    run curl -s -u dolt: "http://127.0.0.1:$CDCPORT/changes?database=repo1&branch=main&follow=false"
+    [[ ! "$output" =~ '"change_type"' ]] || false
+}
@@ -0,0 +1,156 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+load $BATS_TEST_DIRNAME/helper/query-server-common.bash
+
+setup() {
+    setup_common
+    BLOBS="$BATS_TMPDIR/tier-blobs-$$"
+    mkdir -p "$BLOBS"
+    dolt sql -q "create table t (pk int primary key, v varchar(100))"
+    dolt sql -q "insert into t values (1, 'one'), (2, 'two')"
+    dolt commit -Am "create t"
+    dolt sql -q "update t set v = 'changed' where pk = 1"
+    dolt commit -am "update t"
+    dolt gc
+}
+
+teardown() {
+    assert_feature_version
+    stop_sql_server
+    teardown_common
+    rm -rf "$BLOBS"
+}
+
+@test "tiered-storage: status of a database without tiered storage" {
+    run dolt tier status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Tiered storage is not enabled" ]] || false
+}
+
+@test "tiered-storage: enable offloads oldgen and history stays queryable" {
+    run dolt tier enable "localbs://$BLOBS" --cache-size 10MB
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Tiered storage enabled" ]] || false
+
+    [ -f .dolt/noms/oldgen/tiered_storage.json ]
+    run ls "$BLOBS"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ ".bs" ]] || false
+
+    run dolt sql -q "select v from t as of 'HEAD~1' where pk = 1" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "one" ]] || false
+
+    run dolt tier status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "enabled with blobstore localbs://$BLOBS" ]] || false
+    [[ "$output" =~ "of 9.5 MiB" ]] || false
+
+    dolt sql -q "insert into t values (3, 'three')"
+    dolt commit -am "insert"
+    dolt gc
+    run dolt sql -q "select count(*) from t" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "3" ]] || false
+
+    run dolt fsck
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "No problems found" ]] || false
+}
+
+@test "tiered-storage: disable restores oldgen to local disk" {
+    dolt tier enable "localbs://$BLOBS"
+    run dolt tier disable
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Tiered storage disabled" ]] || false
+
+    [ ! -f .dolt/noms/oldgen/tiered_storage.json ]
+    run dolt tier status
+    [[ "$output" =~ "Tiered storage is not enabled" ]] || false
+
+    run dolt sql -q "select v from t as of 'HEAD~1' where pk = 1" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "one" ]] || false
+}
+
+@test "tiered-storage: enable errors" {
+    run dolt tier enable
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "a blobstore url is required" ]] || false
+
+    run dolt tier enable "http://example.com/blobs"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "unsupported blobstore scheme" ]] || false
+
+    run dolt tier enable "localbs://$BLOBS" --aws-region us-west-2
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "only valid for s3 blobstores" ]] || false
+
+    dolt tier enable "localbs://$BLOBS"
+    run dolt tier enable "localbs://$BLOBS"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "already enabled" ]] || false
+}
+
+@test "tiered-storage: enable and disable refuse a database in use" {
+    start_sql_server
+    run dolt tier enable "localbs://$BLOBS"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "locked by another dolt process" ]] || false
+    [ ! -f .dolt/noms/oldgen/tiered_storage.json ]
+    stop_sql_server 1
+
+    dolt tier enable "localbs://$BLOBS"
+    start_sql_server
+    run dolt tier disable
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "locked by another dolt process" ]] || false
+    [ -f .dolt/noms/oldgen/tiered_storage.json ]
+}
+
+@test "tiered-storage: gc deletes unreferenced blobs" {
+    dolt tier enable "localbs://$BLOBS"
+    for i in 1 2 3; do
+        dolt sql -q "insert into t values (1$i, 'v$i')"
+        dolt commit -am "insert $i"
+        dolt gc --full
+    done
+    run dolt sql -q "select count(*) from t" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "5" ]] || false
+
+    # a full gc rewrites the old generation into a single table file
+    run ls "$BLOBS"
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 1 ]
+}
+
+@test "tiered-storage: archive and unarchive" {
+    # archives need enough chunks to build a dictionary
+    dolt sql -q "create table tbl (i int auto_increment primary key, guid char(36))"
+    for ((j=1; j<=30; j++)); do
+        dolt sql -q "insert into tbl (guid) values (uuid()), (uuid()), (uuid()), (uuid()), (uuid()); update tbl set guid = uuid() where i = $j"
+        dolt commit -Am "commit $j"
+    done
+    dolt gc
+
+    dolt tier enable "localbs://$BLOBS"
+    run dolt archive
+    [ "$status" -eq 0 ]
+    run ls "$BLOBS"
+    [[ "$output" =~ ".darc" ]] || false
+    [ -z "$(find .dolt/noms/oldgen -name '*.darc')" ]
+
+    run dolt sql -q "select count(*) from tbl as of 'HEAD~10'" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "100" ]] || false
+
+    run dolt archive --revert
+    [ "$status" -eq 0 ]
+    run dolt sql -q "select count(*) from tbl as of 'HEAD~10'" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "100" ]] || false
+
+    run dolt fsck
+    [ "$status" -eq 0 ]
+}
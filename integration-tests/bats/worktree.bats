@@ -0,0 +1,186 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    WORKTREES="$BATS_TMPDIR/worktrees-$$"
+    mkdir -p "$WORKTREES"
+
+    dolt sql -q "create table t (pk int primary key);"
+    dolt sql -q "insert into t values (1);"
+    dolt commit -Am "create t"
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+    rm -rf "$WORKTREES"
+}
+
+@test "worktree: add checks out a branch sharing the database of the repository" {
+    dolt branch feature
+
+    run dolt worktree add "$WORKTREES/wt" feature
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "with branch 'feature' checked out" ]] || false
+    [ ! -d "$WORKTREES/wt/.dolt/noms" ]
+
+    cd "$WORKTREES/wt"
+    run dolt branch --show-current
+    [ "$status" -eq 0 ]
+    [ "$output" = "feature" ]
+
+    dolt sql -q "insert into t values (2);"
+    dolt commit -am "insert 2"
+
+    cd "$BATS_TMPDIR/dolt-repo-$$"
+    run dolt branch --show-current
+    [ "$output" = "main" ]
+    run dolt sql -q "select count(*) from t as of 'feature'" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "2" ]] || false
+    run dolt log -n 1 feature
+    [[ "$output" =~ "insert 2" ]] || false
+}
+
+@test "worktree: working sets of worktrees are independent" {
+    dolt worktree add "$WORKTREES/feature"
+
+    dolt sql -q "insert into t values (10);"
+
+    cd "$WORKTREES/feature"
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "On branch feature" ]] || false
+    [[ "$output" =~ "nothing to commit" ]] || false
+
+    dolt sql -q "delete from t;"
+
+    cd "$BATS_TMPDIR/dolt-repo-$$"
+    run dolt sql -q "select pk from t order by pk" -r csv
+    [ "${lines[1]}" = "1" ]
+    [ "${lines[2]}" = "10" ]
+}
+
+@test "worktree: a branch can't be checked out in two worktrees" {
+    run dolt worktree add "$WORKTREES/wt" main
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "branch 'main' is already checked out at" ]] || false
+    [ ! -d "$WORKTREES/wt" ]
+
+    dolt worktree add -b feature "$WORKTREES/wt"
+
+    run dolt checkout feature
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "branch 'feature' is already checked out at '$WORKTREES/wt'" ]] || false
+
+    run dolt branch -D feature
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "branch 'feature' is already checked out at '$WORKTREES/wt'" ]] || false
+
+    cd "$WORKTREES/wt"
+    run dolt checkout main
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "branch 'main' is already checked out at '$BATS_TMPDIR/dolt-repo-$$'" ]] || false
+
+    dolt branch other
+    dolt checkout other
+    cd "$BATS_TMPDIR/dolt-repo-$$"
+    run dolt branch -m other renamed
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "branch 'other' is already checked out at '$WORKTREES/wt'" ]] || false
+    dolt checkout feature
+    run dolt branch --show-current
+    [ "$output" = "feature" ]
+}
+
+@test "worktree: dolt_checkout and dolt_branch respect worktrees" {
+    dolt worktree add -b feature "$WORKTREES/wt"
+
+    run dolt sql -q "call dolt_checkout('feature')"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "branch 'feature' is already checked out at '$WORKTREES/wt'" ]] || false
+
+    run dolt sql -q "call dolt_checkout('-B', 'feature')"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "branch 'feature' is already checked out at '$WORKTREES/wt'" ]] || false
+
+    run dolt sql -q "call dolt_branch('-D', 'feature')"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "branch 'feature' is already checked out at '$WORKTREES/wt'" ]] || false
+
+    run dolt sql -q "call dolt_branch('-m', 'feature', 'renamed')"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "branch 'feature' is already checked out at '$WORKTREES/wt'" ]] || false
+
+    run dolt branch
+    [[ "$output" =~ "feature" ]] || false
+    [[ ! "$output" =~ "renamed" ]] || false
+}
+
+@test "worktree: remotes and config are shared with the repository" {
+    dolt worktree add "$WORKTREES/wt"
+    dolt remote add origin file://"$BATS_TMPDIR/remote-$$"
+    dolt config --local --add user.name "worktree user"
+
+    cd "$WORKTREES/wt"
+    run dolt remote -v
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "origin" ]] || false
+    run dolt config --get user.name
+    [ "$output" = "worktree user" ]
+
+    dolt remote remove origin
+    cd "$BATS_TMPDIR/dolt-repo-$$"
+    run dolt remote -v
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "origin" ]] || false
+}
+
+@test "worktree: list and remove" {
+    dolt worktree add -b b1 "$WORKTREES/one"
+    dolt worktree add "$WORKTREES/two"
+
+    run dolt worktree list
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 3 ]
+    [[ "${lines[0]}" =~ "dolt-repo-$$" ]] || false
+    [[ "${lines[0]}" =~ "[main]" ]] || false
+    [[ "${lines[1]}" =~ "$WORKTREES/one" ]] || false
+    [[ "${lines[1]}" =~ "[b1]" ]] || false
+    [[ "${lines[2]}" =~ "$WORKTREES/two" ]] || false
+    [[ "${lines[2]}" =~ "[two]" ]] || false
+
+    cd "$WORKTREES/one"
+    dolt sql -q "insert into t values (2);"
+    run dolt worktree remove .
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "is the current worktree" ]] || false
+
+    cd "$WORKTREES/two"
+    run dolt worktree remove "$WORKTREES/one"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "has uncommitted changes" ]] || false
+    [ -d "$WORKTREES/one" ]
+
+    dolt worktree remove --force "$WORKTREES/one"
+    [ ! -d "$WORKTREES/one" ]
+
+    cd "$BATS_TMPDIR/dolt-repo-$$"
+    run dolt worktree list
+    [ "${#lines[@]}" -eq 2 ]
+
+    # uncommitted changes of a removed worktree stay in the working set of its branch
+    dolt checkout b1
+    run dolt status
+    [[ "$output" =~ "modified:" ]] || false
+
+    run dolt worktree remove "$WORKTREES/one"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "is not a worktree of this repository" ]] || false
+
+    run dolt worktree remove .
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "is the main worktree of the repository" ]] || false
+}